@@ -32,6 +32,11 @@ func main() {
 	log := logger.New("api-gateway", cfg.Server.Environment)
 	log.Info().Msg("starting API Gateway")
 
+	// Fail fast if a locale file is missing keys (see pkg/i18n.Validate)
+	if err := i18n.Validate(); err != nil {
+		log.Fatal().Err(err).Msg("invalid message catalog")
+	}
+
 	// Create router
 	r := chi.NewRouter()
 
@@ -40,7 +45,7 @@ func main() {
 	r.Use(httputil.RequestID)
 	r.Use(httputil.Logger(log))
 	r.Use(httputil.Recoverer(log))
-	r.Use(middleware.Timeout(60 * time.Second))
+	r.Use(gateway.Timeout(60 * time.Second)) // event streams are exempt
 
 	// CORS - supports subdomain-based multi-tenancy
 	corsOrigins := os.Getenv("MEDFLOW_CORS_ORIGINS") // comma-separated extra origins
@@ -97,6 +102,9 @@ func main() {
 		})
 	})
 
+	// Signed document downloads (public; the URL signature is the authorization)
+	r.Get("/files/*", proxy.ForwardToFiles)
+
 	// API routes
 	r.Route("/api/v1", func(r chi.Router) {
 		// Auth routes (public)
@@ -109,13 +117,24 @@ func main() {
 				r.Use(proxy.AuthMiddleware)
 				r.Post("/logout", proxy.ForwardToAuth)
 				r.Get("/me", proxy.ForwardToAuth)
+
+				// Background job administration
+				r.Get("/admin/jobs", proxy.ForwardToAuth)
+				r.Get("/admin/jobs/{name}/runs", proxy.ForwardToAuth)
+				r.Post("/admin/jobs/{name}/trigger", proxy.ForwardToAuth)
 			})
 		})
 
+		// Temperature sensor readings (public; signed with the sensor's secret)
+		r.Post("/inventory/sensors/readings", proxy.ForwardSensorReadings)
+
 		// Protected routes
 		r.Group(func(r chi.Router) {
 			r.Use(proxy.AuthMiddleware)
 
+			// Search across services (items, hygiene plans, employees)
+			r.Get("/search", proxy.Search)
+
 			// User routes
 			r.Route("/users", func(r chi.Router) {
 				r.Get("/", proxy.ForwardToUsers)
@@ -140,6 +159,38 @@ func main() {
 			// Audit routes
 			r.Get("/audit", proxy.ForwardToUsers)
 
+			// Alert notification routes
+			r.Route("/notifications", func(r chi.Router) {
+				r.Get("/", proxy.ForwardToUsers)
+				r.Get("/inbox", proxy.ForwardToUsers)
+				r.Get("/inbox/unread-count", proxy.ForwardToUsers)
+				r.Post("/inbox/read-all", proxy.ForwardToUsers)
+				r.Post("/inbox/{id}/read", proxy.ForwardToUsers)
+				r.Post("/inbox/{id}/unread", proxy.ForwardToUsers)
+				r.Post("/inbox/{id}/archive", proxy.ForwardToUsers)
+				r.Get("/stream", proxy.StreamToUsers)
+				r.Get("/rules", proxy.ForwardToUsers)
+				r.Post("/rules", proxy.ForwardToUsers)
+				r.Get("/rules/{id}", proxy.ForwardToUsers)
+				r.Put("/rules/{id}", proxy.ForwardToUsers)
+				r.Delete("/rules/{id}", proxy.ForwardToUsers)
+				r.Get("/settings", proxy.ForwardToUsers)
+				r.Put("/settings", proxy.ForwardToUsers)
+				r.Get("/webhooks", proxy.ForwardToUsers)
+				r.Post("/webhooks", proxy.ForwardToUsers)
+				r.Get("/webhooks/{id}", proxy.ForwardToUsers)
+				r.Put("/webhooks/{id}", proxy.ForwardToUsers)
+				r.Delete("/webhooks/{id}", proxy.ForwardToUsers)
+				r.Get("/{id}", proxy.ForwardToUsers)
+				r.Post("/{id}/acknowledge", proxy.ForwardToUsers)
+				r.Get("/{id}/deliveries", proxy.ForwardToUsers)
+
+				// Background job administration
+				r.Get("/admin/jobs", proxy.ForwardToUsers)
+				r.Get("/admin/jobs/{name}/runs", proxy.ForwardToUsers)
+				r.Post("/admin/jobs/{name}/trigger", proxy.ForwardToUsers)
+			})
+
 			// Staff routes
 			r.Route("/staff", func(r chi.Router) {
 				// Employee routes
@@ -157,6 +208,8 @@ func main() {
 					r.Get("/{id}/files", proxy.ForwardToStaff)
 					r.Post("/{id}/files", proxy.ForwardToStaff)
 					r.Delete("/{id}/files/{fileId}", proxy.ForwardToStaff)
+					r.Get("/{id}/files/{fileId}/download", proxy.ForwardToStaff)
+					r.Get("/{id}/files/{fileId}/url", proxy.ForwardToStaff)
 				})
 
 				// Shift Template routes
@@ -210,6 +263,16 @@ func main() {
 					r.Post("/extract", proxy.ForwardToStaff)
 					r.Get("/extract/{jobId}", proxy.ForwardToStaff)
 				})
+
+				// Audit trail (working time, absences, personnel data)
+				r.Get("/audit", proxy.ForwardToStaff)
+				r.Get("/audit/export", proxy.ForwardToStaff)
+				r.Get("/audit/verify", proxy.ForwardToStaff)
+
+				// Background job administration
+				r.Get("/admin/jobs", proxy.ForwardToStaff)
+				r.Get("/admin/jobs/{name}/runs", proxy.ForwardToStaff)
+				r.Post("/admin/jobs/{name}/trigger", proxy.ForwardToStaff)
 			})
 
 			// Time Tracking routes
@@ -261,6 +324,11 @@ func main() {
 						// Temperature monitoring
 						r.Post("/{id}/temperature", proxy.ForwardToInventory)
 						r.Get("/{id}/temperature", proxy.ForwardToInventory)
+						r.Get("/{id}/temperature/daily", proxy.ForwardToInventory)
+						r.Get("/{id}/temperature/report", proxy.ForwardToInventory)
+						r.Post("/{id}/temperature/report/verify", proxy.ForwardToInventory)
+						r.Get("/{id}/sensors", proxy.ForwardToInventory)
+						r.Post("/{id}/sensors", proxy.ForwardToInventory)
 					})
 					r.Route("/shelves", func(r chi.Router) {
 						r.Get("/", proxy.ForwardToInventory)
@@ -274,6 +342,18 @@ func main() {
 				// Temperature webhook
 				r.Post("/temperature/webhook", proxy.ForwardToInventory)
 
+				// Fridge logs of all monitored cabinets
+				r.Get("/temperature/report", proxy.ForwardToInventory)
+
+				// Temperature excursions and their release decisions
+				r.Get("/temperature/excursions", proxy.ForwardToInventory)
+				r.Get("/temperature/excursions/{id}", proxy.ForwardToInventory)
+				r.Post("/temperature/excursions/{id}/decision", proxy.ForwardToInventory)
+
+				// Sensor credentials
+				r.Post("/sensors/{id}/rotate", proxy.ForwardToInventory)
+				r.Delete("/sensors/{id}", proxy.ForwardToInventory)
+
 				// Item routes
 				r.Route("/items", func(r chi.Router) {
 					r.Get("/", proxy.ForwardToInventory)
@@ -283,6 +363,9 @@ func main() {
 					r.Delete("/{id}", proxy.ForwardToInventory)
 					r.Get("/{id}/batches", proxy.ForwardToInventory)
 					r.Post("/{id}/batches", proxy.ForwardToInventory)
+					r.Get("/{id}/pick-list", proxy.ForwardToInventory)
+					r.Get("/{id}/consumption", proxy.ForwardToInventory)
+					r.Get("/{id}/forecast", proxy.ForwardToInventory)
 					// Compliance: hazardous details
 					r.Get("/{id}/hazardous", proxy.ForwardToInventory)
 					r.Put("/{id}/hazardous", proxy.ForwardToInventory)
@@ -314,10 +397,12 @@ func main() {
 					r.Delete("/{id}", proxy.ForwardToInventory)
 					r.Post("/{id}/adjust", proxy.ForwardToInventory)
 					r.Post("/{id}/open", proxy.ForwardToInventory)
+					r.Post("/{id}/transfer", proxy.ForwardToInventory)
 				})
 
 				// Scan/lookup routes
 				r.Route("/scan", func(r chi.Router) {
+					r.Post("/", proxy.ForwardToInventory)
 					r.Get("/barcode/{barcode}", proxy.ForwardToInventory)
 					r.Get("/batch", proxy.ForwardToInventory)
 				})
@@ -326,6 +411,7 @@ func main() {
 				r.Route("/documents", func(r chi.Router) {
 					r.Delete("/{id}", proxy.ForwardToInventory)
 					r.Get("/{id}/download", proxy.ForwardToInventory)
+					r.Get("/{id}/url", proxy.ForwardToInventory)
 				})
 
 				// PDF exports
@@ -333,12 +419,117 @@ func main() {
 				r.Get("/export/gefahrstoffverzeichnis", proxy.ForwardToInventory)
 				r.Get("/export/bestandsverzeichnis", proxy.ForwardToInventory)
 
+				// GDPdU data exports for tax audits
+				r.Route("/export/gdpdu", func(r chi.Router) {
+					r.Get("/", proxy.ForwardToInventory)
+					r.Post("/", proxy.ForwardToInventory)
+					r.Get("/{id}", proxy.ForwardToInventory)
+					r.Get("/{id}/download", proxy.ForwardToInventory)
+				})
+
+				// Suppliers
+				r.Route("/suppliers", func(r chi.Router) {
+					r.Get("/", proxy.ForwardToInventory)
+					r.Post("/", proxy.ForwardToInventory)
+					r.Get("/{id}", proxy.ForwardToInventory)
+					r.Put("/{id}", proxy.ForwardToInventory)
+					r.Delete("/{id}", proxy.ForwardToInventory)
+					r.Get("/{id}/items", proxy.ForwardToInventory)
+					r.Put("/{id}/items/{itemId}", proxy.ForwardToInventory)
+					r.Delete("/{id}/items/{itemId}", proxy.ForwardToInventory)
+				})
+
+				// Purchase orders and goods receipts
+				r.Route("/purchase-orders", func(r chi.Router) {
+					r.Get("/", proxy.ForwardToInventory)
+					r.Post("/", proxy.ForwardToInventory)
+					r.Post("/suggestions", proxy.ForwardToInventory)
+					r.Get("/{id}", proxy.ForwardToInventory)
+					r.Put("/{id}", proxy.ForwardToInventory)
+					r.Delete("/{id}", proxy.ForwardToInventory)
+					r.Post("/{id}/send", proxy.ForwardToInventory)
+					r.Post("/{id}/cancel", proxy.ForwardToInventory)
+					r.Get("/{id}/receipts", proxy.ForwardToInventory)
+					r.Post("/{id}/receipts", proxy.ForwardToInventory)
+					r.Post("/{id}/receipts/{receiptId}/labels", proxy.ForwardToInventory)
+				})
+
+				// Stock reservations
+				r.Route("/reservations", func(r chi.Router) {
+					r.Get("/", proxy.ForwardToInventory)
+					r.Post("/", proxy.ForwardToInventory)
+					r.Get("/{id}", proxy.ForwardToInventory)
+					r.Post("/{id}/release", proxy.ForwardToInventory)
+					r.Post("/{id}/consume", proxy.ForwardToInventory)
+				})
+
+				// Procedure kits
+				r.Route("/kits", func(r chi.Router) {
+					r.Get("/", proxy.ForwardToInventory)
+					r.Post("/", proxy.ForwardToInventory)
+					r.Get("/{id}", proxy.ForwardToInventory)
+					r.Put("/{id}", proxy.ForwardToInventory)
+					r.Delete("/{id}", proxy.ForwardToInventory)
+					r.Get("/{id}/pick-list", proxy.ForwardToInventory)
+					r.Post("/{id}/consume", proxy.ForwardToInventory)
+				})
+				r.Get("/kit-consumptions", proxy.ForwardToInventory)
+				r.Get("/kit-consumptions/{id}", proxy.ForwardToInventory)
+
+				// Label templates and label printing
+				r.Route("/label-templates", func(r chi.Router) {
+					r.Get("/", proxy.ForwardToInventory)
+					r.Post("/", proxy.ForwardToInventory)
+					r.Get("/{id}", proxy.ForwardToInventory)
+					r.Put("/{id}", proxy.ForwardToInventory)
+					r.Delete("/{id}", proxy.ForwardToInventory)
+				})
+				r.Post("/labels", proxy.ForwardToInventory)
+
+				// Consumption forecasts
+				r.Route("/forecasts", func(r chi.Router) {
+					r.Get("/", proxy.ForwardToInventory)
+					r.Post("/recalculate", proxy.ForwardToInventory)
+					r.Post("/accept", proxy.ForwardToInventory)
+				})
+
+				// Stocktakes (Inventur)
+				r.Route("/stocktakes", func(r chi.Router) {
+					r.Get("/", proxy.ForwardToInventory)
+					r.Post("/", proxy.ForwardToInventory)
+					r.Get("/{id}", proxy.ForwardToInventory)
+					r.Get("/{id}/count-sheet", proxy.ForwardToInventory)
+					r.Get("/{id}/counts", proxy.ForwardToInventory)
+					r.Post("/{id}/counts", proxy.ForwardToInventory)
+					r.Post("/{id}/finish", proxy.ForwardToInventory)
+					r.Post("/{id}/reopen", proxy.ForwardToInventory)
+					r.Post("/{id}/cancel", proxy.ForwardToInventory)
+					r.Get("/{id}/variance", proxy.ForwardToInventory)
+					r.Post("/{id}/approve", proxy.ForwardToInventory)
+					r.Get("/{id}/protocol", proxy.ForwardToInventory)
+					r.Post("/{id}/protocol/verify", proxy.ForwardToInventory)
+				})
+
+				// Retention: legal holds and the purge's deletion protocol
+				r.Route("/legal-holds", func(r chi.Router) {
+					r.Get("/", proxy.ForwardToInventory)
+					r.Post("/", proxy.ForwardToInventory)
+					r.Get("/{id}", proxy.ForwardToInventory)
+					r.Post("/{id}/release", proxy.ForwardToInventory)
+				})
+				r.Get("/deletion-protocols", proxy.ForwardToInventory)
+
 				// Alerts
 				r.Get("/alerts", proxy.ForwardToInventory)
 				r.Put("/alerts/{id}/acknowledge", proxy.ForwardToInventory)
 
 				// Dashboard
 				r.Get("/dashboard/stats", proxy.ForwardToInventory)
+
+				// Background job administration
+				r.Get("/admin/jobs", proxy.ForwardToInventory)
+				r.Get("/admin/jobs/{name}/runs", proxy.ForwardToInventory)
+				r.Post("/admin/jobs/{name}/trigger", proxy.ForwardToInventory)
 			})
 		})
 	})
@@ -0,0 +1,145 @@
+// Command audit-verify re-walks the GoBD audit trail hash chains of all
+// tenants (or of one tenant) and reports the first changed, deleted
+// or reordered entry of each chain. It exits with status 1 if a chain is
+// broken and 2 if the check could not run.
+//
+// Usage:
+//
+//	audit-verify [-tenant <id or slug>] [-json]
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	inventoryrepo "github.com/medflow/medflow-backend/internal/inventory/repository"
+	staffrepo "github.com/medflow/medflow-backend/internal/staff/repository"
+	"github.com/medflow/medflow-backend/pkg/auditchain"
+	"github.com/medflow/medflow-backend/pkg/config"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/medflow/medflow-backend/pkg/tenant"
+)
+
+type tenantRow struct {
+	ID   string `db:"id"`
+	Slug string `db:"slug"`
+}
+
+// verifier verifies one audit trail chain of the tenant in the context
+type verifier func(ctx context.Context) (*auditchain.Result, error)
+
+type tenantResult struct {
+	TenantID   string             `json:"tenant_id"`
+	TenantSlug string             `json:"tenant_slug"`
+	Result     *auditchain.Result `json:"result"`
+}
+
+func main() {
+	tenantFlag := flag.String("tenant", "", "verify only this tenant (id or slug)")
+	jsonOutput := flag.Bool("json", false, "print the results as JSON lines")
+	flag.Parse()
+
+	cfg, err := config.LoadWithValidation("inventory-service")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "configuration error: %v\n", err)
+		os.Exit(2)
+	}
+
+	log := logger.New("audit-verify", cfg.Server.Environment)
+
+	db, err := database.NewWithSearchPath(&cfg.Database, cfg.Database.SearchPath, log)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to connect to database")
+	}
+	defer db.Close()
+
+	// The staff audit trail lives in the staff schema
+	staffDB, err := database.NewWithSearchPath(&cfg.Database, "staff, public", log)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to connect to database")
+	}
+	defer staffDB.Close()
+
+	ctx := context.Background()
+	tenants, err := listTenants(ctx, db, *tenantFlag)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to list tenants")
+	}
+	if len(tenants) == 0 {
+		fmt.Fprintf(os.Stderr, "no tenant matches %q\n", *tenantFlag)
+		os.Exit(2)
+	}
+
+	verifiers := []verifier{
+		inventoryrepo.NewAuditTrailRepository(db).Verify,
+		staffrepo.NewAuditTrailRepository(staffDB).Verify,
+	}
+	results := make([]tenantResult, 0, len(tenants)*len(verifiers))
+	failed := false
+	for _, t := range tenants {
+		for _, verify := range verifiers {
+			result, err := verify(tenant.WithTenantContext(ctx, t.ID, t.Slug))
+			if err != nil {
+				log.Error().Err(err).Str("tenant_id", t.ID).Msg("failed to verify audit trail")
+				os.Exit(2)
+			}
+			failed = failed || !result.Valid
+			results = append(results, tenantResult{TenantID: t.ID, TenantSlug: t.Slug, Result: result})
+		}
+	}
+
+	if *jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		for _, r := range results {
+			if err := enc.Encode(r); err != nil {
+				log.Fatal().Err(err).Msg("failed to encode result")
+			}
+		}
+	} else {
+		printResults(results)
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// listTenants returns the tenants that are not deleted, or the one with the
+// given id or slug. Suspended tenants are verified too: their audit trail
+// must stay intact.
+func listTenants(ctx context.Context, db *database.DB, filter string) ([]tenantRow, error) {
+	var tenants []tenantRow
+	query := `SELECT id, slug FROM public.tenants WHERE deleted_at IS NULL`
+	args := []interface{}{}
+	if filter != "" {
+		query += ` AND (id::text = $1 OR slug = $1)`
+		args = append(args, filter)
+	}
+	query += ` ORDER BY slug`
+
+	err := db.SelectContext(ctx, &tenants, query, args...)
+	return tenants, err
+}
+
+func printResults(results []tenantResult) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TENANT\tCHAIN\tENTRIES\tHEAD\tANCHOR\tSTATUS")
+	for _, r := range results {
+		anchor := "-"
+		if r.Result.Anchor != nil {
+			anchor = fmt.Sprintf("%d", r.Result.Anchor.Sequence)
+		}
+		status := "ok"
+		if b := r.Result.Break; b != nil {
+			status = fmt.Sprintf("BROKEN at %d: %s (expected %s, got %s)", b.Sequence, b.Reason, b.Expected, b.Actual)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\t%s\n",
+			r.TenantSlug, r.Result.Chain, r.Result.Entries, r.Result.Head.Sequence, anchor, status)
+	}
+	w.Flush()
+}
@@ -19,6 +19,8 @@ import (
 	"github.com/medflow/medflow-backend/pkg/config"
 	"github.com/medflow/medflow-backend/pkg/database"
 	"github.com/medflow/medflow-backend/pkg/httputil"
+	"github.com/medflow/medflow-backend/pkg/i18n"
+	"github.com/medflow/medflow-backend/pkg/jobs"
 	"github.com/medflow/medflow-backend/pkg/logger"
 	"github.com/medflow/medflow-backend/pkg/messaging"
 )
@@ -35,6 +37,11 @@ func main() {
 	log := logger.New("auth-service", cfg.Server.Environment)
 	log.Info().Msg("starting Auth Service")
 
+	// Fail fast if a locale file is missing keys (see pkg/i18n.Validate)
+	if err := i18n.Validate(); err != nil {
+		log.Fatal().Err(err).Msg("invalid message catalog")
+	}
+
 	// Connect to database (single Supabase DB, search_path = public)
 	db, err := database.NewWithSearchPath(&cfg.Database, cfg.Database.SearchPath, log)
 	if err != nil {
@@ -78,6 +85,20 @@ func main() {
 	}
 	defer consumerCancel()
 
+	// Background jobs (distributed lock, run history)
+	jobsCtx, jobsCancel := context.WithCancel(context.Background())
+	defer jobsCancel()
+
+	scheduler := jobs.NewScheduler(db, &cfg.Jobs, log)
+	scheduler.MustRegister(&jobs.Job{
+		Name:        "auth.session_cleanup",
+		Description: "Delete expired and revoked sessions",
+		Schedule:    "@hourly",
+		Run:         sessionRepo.CleanExpired,
+	})
+	jobHandler := jobs.NewHandler(scheduler, log)
+	scheduler.Start(jobsCtx)
+
 	// Create router
 	r := chi.NewRouter()
 
@@ -86,6 +107,7 @@ func main() {
 	r.Use(httputil.RequestID)
 	r.Use(httputil.Logger(log))
 	r.Use(httputil.Recoverer(log))
+	r.Use(i18n.Middleware) // Locale from Accept-Language (forwarded by the gateway)
 
 	// Health check
 	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -108,6 +130,9 @@ func main() {
 		r.Post("/logout", authHandler.Logout)
 		r.Post("/refresh", authHandler.Refresh)
 		r.Get("/me", authHandler.Me)
+
+		// Background job administration (tenant context required for run history)
+		r.With(httputil.TenantMiddleware).Mount("/admin/jobs", jobHandler.Routes())
 	})
 
 	// Create server
@@ -134,6 +159,10 @@ func main() {
 
 	log.Info().Msg("shutting down server")
 
+	// Stop the job scheduler (waits for in-flight runs)
+	jobsCancel()
+	scheduler.Stop()
+
 	// Graceful shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -16,11 +16,17 @@ import (
 	"github.com/medflow/medflow-backend/internal/inventory/handler"
 	"github.com/medflow/medflow-backend/internal/inventory/repository"
 	"github.com/medflow/medflow-backend/internal/inventory/service"
+	"github.com/medflow/medflow-backend/pkg/auditchain"
 	"github.com/medflow/medflow-backend/pkg/config"
 	"github.com/medflow/medflow-backend/pkg/database"
 	"github.com/medflow/medflow-backend/pkg/httputil"
+	"github.com/medflow/medflow-backend/pkg/i18n"
+	"github.com/medflow/medflow-backend/pkg/jobs"
 	"github.com/medflow/medflow-backend/pkg/logger"
 	"github.com/medflow/medflow-backend/pkg/messaging"
+	"github.com/medflow/medflow-backend/pkg/retention"
+	"github.com/medflow/medflow-backend/pkg/scan"
+	"github.com/medflow/medflow-backend/pkg/storage"
 )
 
 func main() {
@@ -35,6 +41,11 @@ func main() {
 	log := logger.New("inventory-service", cfg.Server.Environment)
 	log.Info().Msg("starting Inventory Service")
 
+	// Fail fast if a locale file is missing keys (see pkg/i18n.Validate)
+	if err := i18n.Validate(); err != nil {
+		log.Fatal().Err(err).Msg("invalid message catalog")
+	}
+
 	// Connect to database (single Supabase DB, search_path = inventory, public)
 	db, err := database.NewWithSearchPath(&cfg.Database, cfg.Database.SearchPath, log)
 	if err != nil {
@@ -42,6 +53,21 @@ func main() {
 	}
 	defer db.Close()
 
+	// Malware scanner for uploads (clamd in production)
+	scanner, err := scan.New(&cfg.Scan)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to initialize upload scanner")
+	}
+	if cfg.Scan.Backend == "none" {
+		log.Warn().Msg("upload malware scanning disabled (MEDFLOW_SCAN_BACKEND=none)")
+	}
+
+	// Object storage for uploaded documents (local directory or S3-compatible bucket)
+	documents, err := storage.NewDocuments(&cfg.Storage, scanner)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to initialize document storage")
+	}
+
 	// Connect to RabbitMQ (optional — nil if unavailable)
 	rmq := messaging.NewOptional(&cfg.RabbitMQ, log)
 	if rmq != nil {
@@ -81,34 +107,69 @@ func main() {
 	recallRepo := repository.NewRecallRepository(db)
 	biosafetyRepo := repository.NewBioSafetyRepository(db)
 	retentionRepo := repository.NewRetentionRepository(db)
+	legalHoldRepo := repository.NewLegalHoldRepository(db)
 	reprocessingRepo := repository.NewReprocessingRepository(db)
 	hygieneRepo := repository.NewHygieneRepository(db)
 	radiationRepo := repository.NewRadiationRepository(db)
+	supplierRepo := repository.NewSupplierRepository(db)
+	purchaseOrderRepo := repository.NewPurchaseOrderRepository(db)
+	stocktakeRepo := repository.NewStocktakeRepository(db)
+	reservationRepo := repository.NewReservationRepository(db)
+	kitRepo := repository.NewKitRepository(db)
+	forecastRepo := repository.NewForecastRepository(db)
+	labelTemplateRepo := repository.NewLabelTemplateRepository(db)
+	gdpduExportRepo := repository.NewGDPdUExportRepository(db)
+	sensorRepo := repository.NewSensorRepository(db)
+	excursionRepo := repository.NewExcursionRepository(db)
 
 	// Initialize service
 	inventoryService := service.NewInventoryService(locationRepo, itemRepo, batchRepo, alertRepo, hazardousRepo, documentRepo, temperatureRepo, inspectionRepo, trainingRepo, incidentRepo, publisher, log)
+	retentionGuard := retention.NewGuard(db, log)
+	inventoryService.SetRetentionGuard(retentionGuard)
 
 	// Regulatory compliance services
 	auditService := service.NewAuditService(auditRepo, log)
 	btmService := service.NewBtmService(btmRepo, btmAuthRepo, itemRepo, auditService, log)
 	recallService := service.NewRecallService(recallRepo, safetyOfficerRepo, itemRepo, batchRepo, alertRepo, auditService, log)
-	biosafetyService := service.NewBioSafetyService(biosafetyRepo, auditService, log)
-	retentionService := service.NewRetentionService(retentionRepo, auditService, log)
-	reprocessingService := service.NewReprocessingService(reprocessingRepo, auditService, log)
-	hygieneService := service.NewHygieneService(hygieneRepo, auditService, log)
-	radiationService := service.NewRadiationService(radiationRepo, auditService, log)
+	biosafetyService := service.NewBioSafetyService(biosafetyRepo, retentionGuard, auditService, log)
+	retentionService := service.NewRetentionService(retentionRepo, legalHoldRepo, auditService, log)
+	reprocessingService := service.NewReprocessingService(reprocessingRepo, retentionGuard, auditService, log)
+	hygieneService := service.NewHygieneService(hygieneRepo, retentionGuard, auditService, log)
+	radiationService := service.NewRadiationService(radiationRepo, retentionGuard, auditService, log)
+	searchService := service.NewSearchService(itemRepo, hygieneRepo, log)
+	transferService := service.NewTransferService(batchRepo, itemRepo, locationRepo, auditService, publisher, log)
+	purchaseOrderService := service.NewPurchaseOrderService(supplierRepo, purchaseOrderRepo, itemRepo, batchRepo, alertRepo, auditService, publisher, log)
+	stocktakeService := service.NewStocktakeService(stocktakeRepo, auditService, publisher, cfg.Seal.Key, log)
+	reservationService := service.NewReservationService(reservationRepo, itemRepo, locationRepo, auditService, publisher, log)
+	kitService := service.NewKitService(kitRepo, reservationService, auditService, publisher, log)
+	forecastService := service.NewForecastService(forecastRepo, itemRepo, batchRepo, auditService, log)
+	inventoryService.SetForecastRepository(forecastRepo)
+	labelService := service.NewLabelService(labelTemplateRepo, itemRepo, batchRepo, locationRepo, purchaseOrderRepo, auditService, log)
+	gdpduExportService := service.NewGDPdUExportService(gdpduExportRepo, documents, auditService, log)
+	coldChainService := service.NewColdChainService(sensorRepo, excursionRepo, temperatureRepo, locationRepo, alertRepo, auditService, publisher, cfg.Seal.Key, log)
+	inventoryService.SetColdChain(coldChainService)
 
 	// Initialize handlers
 	locationHandler := handler.NewLocationHandler(locationRepo, log)
 	itemHandler := handler.NewItemHandler(inventoryService, log)
 	batchHandler := handler.NewBatchHandler(inventoryService, log)
-	alertHandler := handler.NewAlertHandler(alertRepo, log)
+	alertHandler := handler.NewAlertHandler(alertRepo, publisher, log)
 	dashboardHandler := handler.NewDashboardHandler(inventoryService, log)
-	complianceHandler := handler.NewComplianceHandler(inventoryService, log)
+	complianceHandler := handler.NewComplianceHandler(inventoryService, documents, log)
 	exportHandler := handler.NewExportHandler(inventoryService, log)
 	temperatureHandler := handler.NewTemperatureHandler(inventoryService, log)
+	coldChainHandler := handler.NewColdChainHandler(coldChainService, log)
 	deviceBookHandler := handler.NewDeviceBookHandler(inventoryService, log)
 	scanHandler := handler.NewScanHandler(inventoryService, log)
+	searchHandler := handler.NewSearchHandler(searchService, log)
+	transferHandler := handler.NewTransferHandler(transferService, log)
+	purchaseOrderHandler := handler.NewPurchaseOrderHandler(purchaseOrderService, log)
+	stocktakeHandler := handler.NewStocktakeHandler(stocktakeService, log)
+	reservationHandler := handler.NewReservationHandler(reservationService, log)
+	kitHandler := handler.NewKitHandler(kitService, log)
+	forecastHandler := handler.NewForecastHandler(forecastService, log)
+	labelHandler := handler.NewLabelHandler(labelService, log)
+	gdpduExportHandler := handler.NewGDPdUExportHandler(gdpduExportService, log)
 
 	// Regulatory compliance handlers
 	auditHandler := handler.NewAuditHandler(auditService, log)
@@ -121,9 +182,80 @@ func main() {
 	radiationHandler := handler.NewRadiationHandler(radiationService, log)
 	dataPortabilityHandler := handler.NewDataPortabilityHandler(inventoryService, biosafetyService, retentionService, reprocessingService, hygieneService, radiationService, log)
 
-	// Initialize alert scanner and scheduler
+	// Initialize alert scanner
 	alertScanner := service.NewAlertScanner(itemRepo, batchRepo, alertRepo, temperatureRepo, locationRepo, log)
-	alertScheduler := service.NewAlertScheduler(alertScanner, db, 15*time.Minute, log)
+	alertScanner.SetPublisher(publisher)
+
+	// Background jobs (tenant fan-out, distributed lock, run history)
+	scheduler := jobs.NewScheduler(db, &cfg.Jobs, log)
+	scheduler.MustRegister(&jobs.Job{
+		Name:        "inventory.alert_scan",
+		Description: "Scan all tenants for expiry, low stock and temperature alerts",
+		Schedule:    "*/15 * * * *",
+		PerTenant:   true,
+		RunOnStart:  true,
+		Run:         alertScanner.ScanAll,
+	})
+	scheduler.MustRegister(&jobs.Job{
+		Name:        "inventory.confirm_overdue_excursions",
+		Description: "Confirm pending temperature excursions whose grace period passed without a reading back in range",
+		Schedule:    "*/5 * * * *",
+		PerTenant:   true,
+		Run:         coldChainService.ConfirmOverdueExcursionsJob,
+	})
+	scheduler.MustRegister(&jobs.Job{
+		Name:        "inventory.document_rescan",
+		Description: "Rescan item documents stored while the malware scanner was unavailable",
+		Schedule:    "*/10 * * * *",
+		PerTenant:   true,
+		Run: func(ctx context.Context) error {
+			return documents.RescanPending(ctx, documentRepo)
+		},
+	})
+	scheduler.MustRegister(&jobs.Job{
+		Name:        "inventory.purchase_order_suggestions",
+		Description: "Create draft purchase orders for items below their reorder point",
+		Schedule:    "0 6 * * *",
+		PerTenant:   true,
+		Run:         purchaseOrderService.SuggestOrdersJob,
+	})
+	scheduler.MustRegister(&jobs.Job{
+		Name:        "inventory.release_overdue_reservations",
+		Description: "Release stock reservations not used within a day after they were needed",
+		Schedule:    "30 * * * *",
+		PerTenant:   true,
+		Run:         reservationService.ReleaseOverdueJob,
+	})
+	scheduler.MustRegister(&jobs.Job{
+		Name:        "inventory.consumption_forecasts",
+		Description: "Forecast item demand and project stock-out dates from consumption history",
+		Schedule:    "15 3 * * *",
+		PerTenant:   true,
+		Run:         forecastService.RecalculateJob,
+	})
+	scheduler.MustRegister(&jobs.Job{
+		Name:        "inventory.retention_purge",
+		Description: "Delete or anonymize records whose retention period has ended, as the retention policies say",
+		Schedule:    "30 2 * * *",
+		PerTenant:   true,
+		Run:         retention.NewPurger(db, log, service.RetentionPurgeRecords...).Run,
+	})
+	scheduler.MustRegister(&jobs.Job{
+		Name:        "inventory.audit_anchor",
+		Description: "Anchor the head of the audit trail hash chain in the tenant audit log",
+		Schedule:    "5 * * * *",
+		PerTenant:   true,
+		Run:         auditchain.New(db, repository.AuditChain, log).Anchor,
+	})
+	scheduler.MustRegister(&jobs.Job{
+		Name:        "inventory.gdpdu_exports",
+		Description: "Build requested GDPdU data exports for tax audits",
+		Schedule:    "*/5 * * * *",
+		PerTenant:   true,
+		Timeout:     time.Hour,
+		Run:         gdpduExportService.ProcessPendingJob,
+	})
+	jobHandler := jobs.NewHandler(scheduler, log)
 
 	// Start user event consumer (if RabbitMQ is available)
 	ctx, cancel := context.WithCancel(context.Background())
@@ -141,8 +273,20 @@ func main() {
 		log.Warn().Msg("user event consumer disabled (no RabbitMQ)")
 	}
 
-	// Start alert scheduler (runs periodic alert scans across all tenants)
-	alertScheduler.Start(ctx)
+	// Start MQTT subscriber for fridge data loggers (if configured)
+	var sensorConsumer *consumers.SensorMQTTConsumer
+	if cfg.MQTT.Enabled {
+		sensorConsumer, err = consumers.NewSensorMQTTConsumer(cfg.MQTT, inventoryService, log)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to create MQTT sensor consumer")
+		}
+		if err := sensorConsumer.Start(ctx); err != nil {
+			log.Fatal().Err(err).Msg("failed to start MQTT sensor consumer")
+		}
+	}
+
+	// Start background job scheduler
+	scheduler.Start(ctx)
 
 	// Create router
 	r := chi.NewRouter()
@@ -152,7 +296,10 @@ func main() {
 	r.Use(httputil.RequestID)
 	r.Use(httputil.Logger(log))
 	r.Use(httputil.Recoverer(log))
-	r.Use(httputil.TenantMiddleware) // Extract tenant context from headers
+	r.Use(i18n.Middleware)           // Locale from Accept-Language (forwarded by the gateway)
+	// Extract tenant context from headers; signed sensor readings resolve the
+	// tenant from the sensor key instead
+	r.Use(httputil.TenantMiddlewareWithBypass("/api/v1/inventory/sensors/readings"))
 
 	// Health check
 	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -169,6 +316,9 @@ func main() {
 		httputil.JSON(w, http.StatusOK, health)
 	})
 
+	// Signed document downloads (local storage backend; the signature is the authorization)
+	r.Handle(storage.PublicPathPrefix+"*", storage.ServeSigned(documents.Storage))
+
 	// API routes
 	r.Route("/api/v1/inventory", func(r chi.Router) {
 		// Location routes
@@ -190,6 +340,11 @@ func main() {
 				// Temperature monitoring
 				r.Post("/{id}/temperature", temperatureHandler.RecordTemperature)
 				r.Get("/{id}/temperature", temperatureHandler.ListReadings)
+				r.Get("/{id}/temperature/daily", coldChainHandler.DailySummaries)
+				r.Get("/{id}/temperature/report", coldChainHandler.TemperatureReport)
+				r.Post("/{id}/temperature/report/verify", coldChainHandler.VerifyTemperatureReport)
+				r.Get("/{id}/sensors", coldChainHandler.ListSensors)
+				r.Post("/{id}/sensors", coldChainHandler.CreateSensor)
 			})
 			r.Route("/shelves", func(r chi.Router) {
 				r.Get("/", locationHandler.ListShelves)
@@ -203,6 +358,22 @@ func main() {
 		// Temperature webhook
 		r.Post("/temperature/webhook", temperatureHandler.Webhook)
 
+		// Fridge logs of all monitored cabinets
+		r.Get("/temperature/report", coldChainHandler.TemperatureReports)
+
+		// Temperature excursions and their release decisions
+		r.Get("/temperature/excursions", coldChainHandler.ListExcursions)
+		r.Get("/temperature/excursions/{id}", coldChainHandler.GetExcursion)
+		r.Post("/temperature/excursions/{id}/decision", coldChainHandler.DecideExcursion)
+
+		// Sensor credentials; readings are signed by the sensor (no tenant headers)
+		r.Post("/sensors/readings", coldChainHandler.IngestReadings)
+		r.Post("/sensors/{id}/rotate", coldChainHandler.RotateSensorSecret)
+		r.Delete("/sensors/{id}", coldChainHandler.RevokeSensor)
+
+		// Search (items, hygiene plans)
+		r.Get("/search", searchHandler.Search)
+
 		// Item routes
 		r.Route("/items", func(r chi.Router) {
 			r.Get("/", itemHandler.List)
@@ -212,6 +383,9 @@ func main() {
 			r.Delete("/{id}", itemHandler.Delete)
 			r.Get("/{id}/batches", batchHandler.ListByItem)
 			r.Post("/{id}/batches", batchHandler.Create)
+			r.Get("/{id}/pick-list", reservationHandler.PickList)
+			r.Get("/{id}/consumption", forecastHandler.Consumption)
+			r.Get("/{id}/forecast", forecastHandler.Forecast)
 			// Compliance: hazardous substance details
 			r.Get("/{id}/hazardous", complianceHandler.GetHazardousDetails)
 			r.Put("/{id}/hazardous", complianceHandler.UpsertHazardousDetails)
@@ -243,10 +417,12 @@ func main() {
 			r.Delete("/{id}", batchHandler.Delete)
 			r.Post("/{id}/adjust", batchHandler.AdjustStock)
 			r.Post("/{id}/open", batchHandler.OpenBatch)
+			r.Post("/{id}/transfer", transferHandler.Transfer)
 		})
 
 		// Scan/lookup routes
 		r.Route("/scan", func(r chi.Router) {
+			r.Post("/", scanHandler.ScanCode)
 			r.Get("/barcode/{barcode}", scanHandler.LookupByBarcode)
 			r.Get("/batch", scanHandler.LookupByBatchNumber)
 		})
@@ -255,6 +431,7 @@ func main() {
 		r.Route("/documents", func(r chi.Router) {
 			r.Delete("/{id}", complianceHandler.DeleteDocument)
 			r.Get("/{id}/download", complianceHandler.DownloadDocument)
+			r.Get("/{id}/url", complianceHandler.GetDocumentURL)
 		})
 
 		// Export routes
@@ -262,6 +439,89 @@ func main() {
 		r.Get("/export/gefahrstoffverzeichnis", exportHandler.ExportGefahrstoffverzeichnis)
 		r.Get("/export/bestandsverzeichnis", exportHandler.ExportBestandsverzeichnis)
 
+		// Supplier routes
+		r.Route("/suppliers", func(r chi.Router) {
+			r.Get("/", purchaseOrderHandler.ListSuppliers)
+			r.Post("/", purchaseOrderHandler.CreateSupplier)
+			r.Get("/{id}", purchaseOrderHandler.GetSupplier)
+			r.Put("/{id}", purchaseOrderHandler.UpdateSupplier)
+			r.Delete("/{id}", purchaseOrderHandler.DeleteSupplier)
+			r.Get("/{id}/items", purchaseOrderHandler.ListSupplierItems)
+			r.Put("/{id}/items/{itemId}", purchaseOrderHandler.SetSupplierItem)
+			r.Delete("/{id}/items/{itemId}", purchaseOrderHandler.RemoveSupplierItem)
+		})
+
+		// Purchase order and goods receipt routes
+		r.Route("/purchase-orders", func(r chi.Router) {
+			r.Get("/", purchaseOrderHandler.ListOrders)
+			r.Post("/", purchaseOrderHandler.CreateOrder)
+			r.Post("/suggestions", purchaseOrderHandler.SuggestOrders)
+			r.Get("/{id}", purchaseOrderHandler.GetOrder)
+			r.Put("/{id}", purchaseOrderHandler.UpdateOrder)
+			r.Delete("/{id}", purchaseOrderHandler.DeleteOrder)
+			r.Post("/{id}/send", purchaseOrderHandler.SendOrder)
+			r.Post("/{id}/cancel", purchaseOrderHandler.CancelOrder)
+			r.Get("/{id}/receipts", purchaseOrderHandler.ListReceipts)
+			r.Post("/{id}/receipts", purchaseOrderHandler.ReceiveGoods)
+			r.Post("/{id}/receipts/{receiptId}/labels", labelHandler.PrintReceipt)
+		})
+
+		// Stock reservation routes
+		r.Route("/reservations", func(r chi.Router) {
+			r.Get("/", reservationHandler.List)
+			r.Post("/", reservationHandler.Reserve)
+			r.Get("/{id}", reservationHandler.Get)
+			r.Post("/{id}/release", reservationHandler.Release)
+			r.Post("/{id}/consume", reservationHandler.Consume)
+		})
+
+		// Procedure kit (Behandlungsset) routes
+		r.Route("/kits", func(r chi.Router) {
+			r.Get("/", kitHandler.List)
+			r.Post("/", kitHandler.Create)
+			r.Get("/{id}", kitHandler.Get)
+			r.Put("/{id}", kitHandler.Update)
+			r.Delete("/{id}", kitHandler.Delete)
+			r.Get("/{id}/pick-list", kitHandler.PickList)
+			r.Post("/{id}/consume", kitHandler.Consume)
+		})
+		r.Get("/kit-consumptions", kitHandler.ListConsumptions)
+		r.Get("/kit-consumptions/{id}", kitHandler.GetConsumption)
+
+		// Label template and label printing routes
+		r.Route("/label-templates", func(r chi.Router) {
+			r.Get("/", labelHandler.ListTemplates)
+			r.Post("/", labelHandler.CreateTemplate)
+			r.Get("/{id}", labelHandler.GetTemplate)
+			r.Put("/{id}", labelHandler.UpdateTemplate)
+			r.Delete("/{id}", labelHandler.DeleteTemplate)
+		})
+		r.Post("/labels", labelHandler.Print)
+
+		// Consumption forecast routes
+		r.Route("/forecasts", func(r chi.Router) {
+			r.Get("/", forecastHandler.List)
+			r.Post("/recalculate", forecastHandler.Recalculate)
+			r.Post("/accept", forecastHandler.Accept)
+		})
+
+		// Stocktake (Inventur) routes
+		r.Route("/stocktakes", func(r chi.Router) {
+			r.Get("/", stocktakeHandler.List)
+			r.Post("/", stocktakeHandler.Start)
+			r.Get("/{id}", stocktakeHandler.Get)
+			r.Get("/{id}/count-sheet", stocktakeHandler.CountSheet)
+			r.Get("/{id}/counts", stocktakeHandler.ListCounts)
+			r.Post("/{id}/counts", stocktakeHandler.Count)
+			r.Post("/{id}/finish", stocktakeHandler.Finish)
+			r.Post("/{id}/reopen", stocktakeHandler.Reopen)
+			r.Post("/{id}/cancel", stocktakeHandler.Cancel)
+			r.Get("/{id}/variance", stocktakeHandler.Variance)
+			r.Post("/{id}/approve", stocktakeHandler.Approve)
+			r.Get("/{id}/protocol", stocktakeHandler.Protocol)
+			r.Post("/{id}/protocol/verify", stocktakeHandler.VerifyProtocol)
+		})
+
 		// Alert routes
 		r.Get("/alerts", alertHandler.List)
 		r.Put("/alerts/{id}/acknowledge", alertHandler.Acknowledge)
@@ -274,6 +534,7 @@ func main() {
 		// Audit trail routes (GoBD compliance)
 		r.Get("/items/{id}/audit", auditHandler.GetItemAudit)
 		r.Get("/audit", auditHandler.ListAudit)
+		r.Get("/audit/verify", auditHandler.Verify)
 
 		// BtM (controlled substance) routes
 		r.Route("/btm/{itemId}", func(r chi.Router) {
@@ -284,7 +545,12 @@ func main() {
 			r.Post("/disposal", btmHandler.DisposeSubstance)
 			r.Post("/correction", btmHandler.CorrectEntry)
 			r.Post("/check", btmHandler.InventoryCheck)
+			r.Get("/karteikarte", btmHandler.Karteikarte)
 		})
+		r.Get("/btm/closings", btmHandler.ListClosings)
+		r.Post("/btm/closings", btmHandler.ClosePeriod)
+		r.Get("/btm/closings/preview", btmHandler.PreviewClosing)
+		r.Get("/btm/closings/{id}", btmHandler.GetClosing)
 		r.Get("/btm/authorized-personnel", btmHandler.ListAuthorizedPersonnel)
 		r.Post("/btm/authorized-personnel", btmHandler.CreateAuthorizedPerson)
 		r.Put("/btm/authorized-personnel/{id}/revoke", btmHandler.RevokeAuthorization)
@@ -322,6 +588,13 @@ func main() {
 		r.Put("/retention-policies/{id}", retentionHandler.Update)
 		r.Delete("/retention-policies/{id}", retentionHandler.Delete)
 
+		// Legal hold and deletion protocol routes
+		r.Get("/legal-holds", retentionHandler.ListHolds)
+		r.Post("/legal-holds", retentionHandler.PlaceHold)
+		r.Get("/legal-holds/{id}", retentionHandler.GetHold)
+		r.Post("/legal-holds/{id}/release", retentionHandler.ReleaseHold)
+		r.Get("/deletion-protocols", retentionHandler.ListProtocols)
+
 		// Reprocessing / Sterilization routes (KRINKO compliance)
 		r.Route("/sterilization/batches", func(r chi.Router) {
 			r.Get("/", reprocessingHandler.ListBatches)
@@ -379,7 +652,16 @@ func main() {
 
 		// Compliance export routes
 		r.Get("/export/gobd", auditHandler.ExportGoBD)
+		r.Route("/export/gdpdu", func(r chi.Router) {
+			r.Get("/", gdpduExportHandler.List)
+			r.Post("/", gdpduExportHandler.Request)
+			r.Get("/{id}", gdpduExportHandler.Get)
+			r.Get("/{id}/download", gdpduExportHandler.Download)
+		})
 		r.Get("/export/data-portability", dataPortabilityHandler.ExportDataPortability)
+
+		// Background job administration
+		r.Mount("/admin/jobs", jobHandler.Routes())
 	})
 
 	// Create server
@@ -409,8 +691,12 @@ func main() {
 	// Cancel context to stop consumers and scheduler
 	cancel()
 
-	// Stop the alert scheduler
-	alertScheduler.Stop()
+	// Stop the job scheduler (waits for in-flight runs)
+	scheduler.Stop()
+
+	if sensorConsumer != nil {
+		<-sensorConsumer.Stopped()
+	}
 
 	// Graceful shutdown
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -22,11 +22,17 @@ import (
 	"github.com/medflow/medflow-backend/internal/staff/repository"
 	"github.com/medflow/medflow-backend/internal/staff/service"
 	"github.com/medflow/medflow-backend/internal/staff/validation"
+	"github.com/medflow/medflow-backend/pkg/auditchain"
 	"github.com/medflow/medflow-backend/pkg/config"
 	"github.com/medflow/medflow-backend/pkg/database"
 	"github.com/medflow/medflow-backend/pkg/httputil"
+	"github.com/medflow/medflow-backend/pkg/i18n"
+	"github.com/medflow/medflow-backend/pkg/jobs"
 	"github.com/medflow/medflow-backend/pkg/logger"
 	"github.com/medflow/medflow-backend/pkg/messaging"
+	"github.com/medflow/medflow-backend/pkg/retention"
+	"github.com/medflow/medflow-backend/pkg/scan"
+	objectstorage "github.com/medflow/medflow-backend/pkg/storage"
 )
 
 func main() {
@@ -41,6 +47,11 @@ func main() {
 	log := logger.New("staff-service", cfg.Server.Environment)
 	log.Info().Msg("starting Staff Service")
 
+	// Fail fast if a locale file is missing keys (see pkg/i18n.Validate)
+	if err := i18n.Validate(); err != nil {
+		log.Fatal().Err(err).Msg("invalid message catalog")
+	}
+
 	// Connect to database (single Supabase DB, search_path = staff, public)
 	db, err := database.NewWithSearchPath(&cfg.Database, cfg.Database.SearchPath, log)
 	if err != nil {
@@ -48,6 +59,21 @@ func main() {
 	}
 	defer db.Close()
 
+	// Malware scanner for uploads (clamd in production)
+	scanner, err := scan.New(&cfg.Scan)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to initialize upload scanner")
+	}
+	if cfg.Scan.Backend == "none" {
+		log.Warn().Msg("upload malware scanning disabled (MEDFLOW_SCAN_BACKEND=none)")
+	}
+
+	// Object storage for uploaded documents (local directory or S3-compatible bucket)
+	documents, err := objectstorage.NewDocuments(&cfg.Storage, scanner)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to initialize document storage")
+	}
+
 	// Connect to RabbitMQ (optional — nil if unavailable)
 	rmq := messaging.NewOptional(&cfg.RabbitMQ, log)
 	if rmq != nil {
@@ -73,16 +99,20 @@ func main() {
 	absenceRepo := repository.NewAbsenceRepository(db)
 	timeTrackingRepo := repository.NewTimeTrackingRepository(db)
 	complianceRepo := repository.NewComplianceRepository(db)
+	auditRepo := repository.NewAuditTrailRepository(db)
 
 	// Initialize validators
 	germanValidator := validation.NewGermanValidator()
 
 	// Initialize services
-	staffService := service.NewStaffService(employeeRepo, publisher, germanValidator, log)
-	shiftService := service.NewShiftService(shiftRepo, publisher, log)
-	absenceService := service.NewAbsenceService(absenceRepo, publisher, log)
-	complianceService := service.NewComplianceService(complianceRepo, timeTrackingRepo, shiftRepo, log)
-	timeTrackingService := service.NewTimeTrackingService(timeTrackingRepo, complianceService, publisher, log)
+	retentionGuard := retention.NewGuard(db, log)
+	auditService := service.NewAuditService(auditRepo, log)
+	staffService := service.NewStaffService(employeeRepo, retentionGuard, auditService, publisher, germanValidator, log)
+	shiftService := service.NewShiftService(shiftRepo, retentionGuard, publisher, log)
+	absenceService := service.NewAbsenceService(absenceRepo, retentionGuard, auditService, publisher, log)
+	complianceService := service.NewComplianceService(complianceRepo, timeTrackingRepo, shiftRepo, auditService, log)
+	complianceService.SetPublisher(publisher)
+	timeTrackingService := service.NewTimeTrackingService(timeTrackingRepo, complianceService, retentionGuard, auditService, publisher, log)
 
 	// Initialize user service client for creating user accounts
 	userServiceURL := os.Getenv("USER_SERVICE_URL")
@@ -95,12 +125,13 @@ func main() {
 	staffService.SetUserClient(userClient)
 
 	// Initialize handlers
-	employeeHandler := handler.NewEmployeeHandler(staffService, userClient, log)
+	employeeHandler := handler.NewEmployeeHandler(staffService, userClient, documents, log)
 	validationHandler := handler.NewValidationHandler(germanValidator, log)
 	shiftHandler := handler.NewShiftHandler(shiftService, log)
 	absenceHandler := handler.NewAbsenceHandler(absenceService, log)
 	timeTrackingHandler := handler.NewTimeTrackingHandler(timeTrackingService, staffService, log)
 	complianceHandler := handler.NewComplianceHandler(complianceService, staffService, log)
+	auditHandler := handler.NewAuditHandler(auditService, log)
 
 	// Initialize document processing
 	visionServiceURL := os.Getenv("VISION_SERVICE_URL")
@@ -133,21 +164,40 @@ func main() {
 		log.Warn().Msg("user event consumer disabled (no RabbitMQ)")
 	}
 
-	// Start periodic compliance checker (ArbZG monitoring)
-	go func() {
-		ticker := time.NewTicker(15 * time.Minute)
-		defer ticker.Stop()
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case <-ticker.C:
-				if err := complianceService.CheckAllActiveEmployees(ctx); err != nil {
-					log.Error().Err(err).Msg("periodic compliance check failed")
-				}
-			}
-		}
-	}()
+	// Background jobs (tenant fan-out, distributed lock, run history)
+	scheduler := jobs.NewScheduler(db, &cfg.Jobs, log)
+	scheduler.MustRegister(&jobs.Job{
+		Name:        "staff.compliance_check",
+		Description: "Check clocked-in employees for ArbZG violations",
+		Schedule:    "*/15 * * * *",
+		PerTenant:   true,
+		Run:         complianceService.CheckAllActiveEmployees,
+	})
+	scheduler.MustRegister(&jobs.Job{
+		Name:        "staff.file_rescan",
+		Description: "Rescan employee files stored while the malware scanner was unavailable",
+		Schedule:    "*/10 * * * *",
+		PerTenant:   true,
+		Run: func(ctx context.Context) error {
+			return documents.RescanPending(ctx, employeeRepo)
+		},
+	})
+	scheduler.MustRegister(&jobs.Job{
+		Name:        "staff.retention_purge",
+		Description: "Delete working time records and anonymize former employees once their retention period has ended",
+		Schedule:    "45 2 * * *",
+		PerTenant:   true,
+		Run:         retention.NewPurger(db, log, service.RetentionPurgeRecords...).Run,
+	})
+	scheduler.MustRegister(&jobs.Job{
+		Name:        "staff.audit_anchor",
+		Description: "Anchor the head of the staff audit trail hash chain in the tenant audit log",
+		Schedule:    "10 * * * *",
+		PerTenant:   true,
+		Run:         auditchain.New(db, repository.AuditChain, log).Anchor,
+	})
+	jobHandler := jobs.NewHandler(scheduler, log)
+	scheduler.Start(ctx)
 
 	// Create router
 	r := chi.NewRouter()
@@ -157,7 +207,10 @@ func main() {
 	r.Use(httputil.RequestID)
 	r.Use(httputil.Logger(log))
 	r.Use(httputil.Recoverer(log))
+	r.Use(i18n.Middleware)           // Locale from Accept-Language (forwarded by the gateway)
 	r.Use(httputil.TenantMiddleware) // Tenant middleware with /health exception
+	// Acting user and client IP for the audit trail
+	r.Use(httputil.UserContext)
 
 	// Health check (no tenant required - handled by middleware)
 	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -174,8 +227,14 @@ func main() {
 		httputil.JSON(w, http.StatusOK, health)
 	})
 
+	// Signed document downloads (local storage backend; the signature is the authorization)
+	r.Handle(objectstorage.PublicPathPrefix+"*", objectstorage.ServeSigned(documents.Storage))
+
 	// API routes (tenant required)
 	r.Route("/api/v1/staff", func(r chi.Router) {
+		// Search (employees)
+		r.Get("/search", employeeHandler.Search)
+
 		// Employee routes
 		r.Route("/employees", func(r chi.Router) {
 			r.Get("/", employeeHandler.List)
@@ -191,6 +250,8 @@ func main() {
 			r.Get("/{id}/files", employeeHandler.ListFiles)
 			r.Post("/{id}/files", employeeHandler.UploadFile)
 			r.Delete("/{id}/files/{fileId}", employeeHandler.DeleteFile)
+			r.Get("/{id}/files/{fileId}/download", employeeHandler.DownloadFile)
+			r.Get("/{id}/files/{fileId}/url", employeeHandler.GetFileURL)
 
 			// Credential management endpoints
 			r.Route("/{id}/credentials", func(r chi.Router) {
@@ -251,6 +312,16 @@ func main() {
 			r.Post("/extract", docProcessingHandler.Extract)
 			r.Get("/extract/{jobId}", docProcessingHandler.GetResult)
 		})
+
+		// Audit trail (working time, absences, personnel data)
+		r.Route("/audit", func(r chi.Router) {
+			r.Get("/", auditHandler.List)
+			r.Get("/export", auditHandler.Export)
+			r.Get("/verify", auditHandler.Verify)
+		})
+
+		// Background job administration
+		r.Mount("/admin/jobs", jobHandler.Routes())
 	})
 
 	// Time Tracking routes (under /api/v1/time-tracking)
@@ -341,9 +412,12 @@ func main() {
 
 	log.Info().Msg("shutting down server")
 
-	// Cancel context to stop consumers
+	// Cancel context to stop consumers and scheduler
 	cancel()
 
+	// Stop the job scheduler (waits for in-flight runs)
+	scheduler.Stop()
+
 	// Graceful shutdown
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer shutdownCancel()
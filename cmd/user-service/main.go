@@ -11,6 +11,10 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	notificationconsumers "github.com/medflow/medflow-backend/internal/notification/consumers"
+	notificationhandler "github.com/medflow/medflow-backend/internal/notification/handler"
+	notificationrepo "github.com/medflow/medflow-backend/internal/notification/repository"
+	notificationservice "github.com/medflow/medflow-backend/internal/notification/service"
 	"github.com/medflow/medflow-backend/internal/user/events"
 	"github.com/medflow/medflow-backend/internal/user/handler"
 	"github.com/medflow/medflow-backend/internal/user/repository"
@@ -18,7 +22,10 @@ import (
 	"github.com/medflow/medflow-backend/pkg/config"
 	"github.com/medflow/medflow-backend/pkg/database"
 	"github.com/medflow/medflow-backend/pkg/httputil"
+	"github.com/medflow/medflow-backend/pkg/i18n"
+	"github.com/medflow/medflow-backend/pkg/jobs"
 	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/medflow/medflow-backend/pkg/mail"
 	"github.com/medflow/medflow-backend/pkg/messaging"
 )
 
@@ -34,6 +41,11 @@ func main() {
 	log := logger.New("user-service", cfg.Server.Environment)
 	log.Info().Msg("starting User Service")
 
+	// Fail fast if a locale file is missing keys (see pkg/i18n.Validate)
+	if err := i18n.Validate(); err != nil {
+		log.Fatal().Err(err).Msg("invalid message catalog")
+	}
+
 	// Connect to database (single Supabase DB, search_path = users, public)
 	db, err := database.NewWithSearchPath(&cfg.Database, cfg.Database.SearchPath, log)
 	if err != nil {
@@ -64,13 +76,76 @@ func main() {
 	roleRepo := repository.NewRoleRepository(db)
 	auditRepo := repository.NewAuditRepository(db)
 
+	// Mail transport for alert notifications (SMTP, or the log in development)
+	mailTransport, err := mail.New(&cfg.Mail, log)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to create mail transport")
+	}
+
 	// Initialize services
 	userService := service.NewUserService(userRepo, roleRepo, auditRepo, publisher, log)
+	notificationService := notificationservice.NewNotificationService(
+		notificationrepo.NewRuleRepository(db),
+		notificationrepo.NewSettingsRepository(db),
+		notificationrepo.NewWebhookRepository(db),
+		notificationrepo.NewNotificationRepository(db),
+		notificationrepo.NewInboxRepository(db),
+		mailTransport,
+		&cfg.Mail,
+		log,
+	)
 
 	// Initialize handlers
 	userHandler := handler.NewUserHandler(userService, log)
 	roleHandler := handler.NewRoleHandler(roleRepo, log)
 	auditHandler := handler.NewAuditHandler(auditRepo, log)
+	notificationHandler := notificationhandler.NewNotificationHandler(notificationService, log)
+	inboxHub := notificationservice.NewHub(log)
+	streamHandler := notificationhandler.NewStreamHandler(notificationService, inboxHub, log)
+
+	// Start alert event consumer (if RabbitMQ is available)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if rmq != nil {
+		alertConsumer, err := notificationconsumers.NewAlertEventConsumer(rmq, notificationService, log)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to create alert event consumer")
+		}
+		if err := alertConsumer.Start(ctx); err != nil {
+			log.Fatal().Err(err).Msg("failed to start alert event consumer")
+		}
+
+		inboxConsumer, err := notificationconsumers.NewInboxEventConsumer(rmq, notificationService, log)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to create inbox event consumer")
+		}
+		if err := inboxConsumer.Start(ctx); err != nil {
+			log.Fatal().Err(err).Msg("failed to start inbox event consumer")
+		}
+	} else {
+		log.Warn().Msg("alert event consumer disabled (no RabbitMQ)")
+	}
+
+	// Wake notification streams when items are added to an inbox
+	go func() {
+		if err := inboxHub.Listen(ctx, cfg.Database.DSN()); err != nil {
+			log.Error().Err(err).Msg("notification inbox listener stopped; streams fall back to polling")
+		}
+	}()
+
+	// Background jobs (tenant fan-out, distributed lock, run history)
+	scheduler := jobs.NewScheduler(db, &cfg.Jobs, log)
+	scheduler.MustRegister(&jobs.Job{
+		Name:        "users.notification_dispatch",
+		Description: "Escalate unacknowledged alert notifications and send due emails and webhooks",
+		Schedule:    "* * * * *",
+		PerTenant:   true,
+		Timeout:     time.Minute,
+		Run:         notificationService.Dispatch,
+	})
+	jobHandler := jobs.NewHandler(scheduler, log)
+	scheduler.Start(ctx)
 
 	// Create router
 	r := chi.NewRouter()
@@ -80,6 +155,7 @@ func main() {
 	r.Use(httputil.RequestID)
 	r.Use(httputil.Logger(log))
 	r.Use(httputil.Recoverer(log))
+	r.Use(i18n.Middleware) // Locale from Accept-Language (forwarded by the gateway)
 
 	// Health check (no tenant required)
 	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -129,6 +205,42 @@ func main() {
 
 		// Audit logs
 		r.Get("/audit", auditHandler.List)
+
+		// Alert notifications
+		r.Route("/notifications", func(r chi.Router) {
+			r.Get("/", notificationHandler.List)
+			r.Route("/inbox", func(r chi.Router) {
+				r.Get("/", notificationHandler.ListInbox)
+				r.Get("/unread-count", notificationHandler.UnreadCount)
+				r.Post("/read-all", notificationHandler.MarkAllRead)
+				r.Post("/{id}/read", notificationHandler.MarkRead)
+				r.Post("/{id}/unread", notificationHandler.MarkUnread)
+				r.Post("/{id}/archive", notificationHandler.Archive)
+			})
+			r.Get("/stream", streamHandler.Stream)
+			r.Route("/rules", func(r chi.Router) {
+				r.Get("/", notificationHandler.ListRules)
+				r.Post("/", notificationHandler.CreateRule)
+				r.Get("/{id}", notificationHandler.GetRule)
+				r.Put("/{id}", notificationHandler.UpdateRule)
+				r.Delete("/{id}", notificationHandler.DeleteRule)
+			})
+			r.Get("/settings", notificationHandler.GetSettings)
+			r.Put("/settings", notificationHandler.SaveSettings)
+			r.Route("/webhooks", func(r chi.Router) {
+				r.Get("/", notificationHandler.ListWebhooks)
+				r.Post("/", notificationHandler.CreateWebhook)
+				r.Get("/{id}", notificationHandler.GetWebhook)
+				r.Put("/{id}", notificationHandler.UpdateWebhook)
+				r.Delete("/{id}", notificationHandler.DeleteWebhook)
+			})
+			r.Get("/{id}", notificationHandler.Get)
+			r.Post("/{id}/acknowledge", notificationHandler.Acknowledge)
+			r.Get("/{id}/deliveries", notificationHandler.ListDeliveries)
+
+			// Background job administration
+			r.Mount("/admin/jobs", jobHandler.Routes())
+		})
 	})
 
 	// Create server
@@ -139,6 +251,7 @@ func main() {
 		ReadTimeout:  cfg.Server.ReadTimeout,
 		WriteTimeout: cfg.Server.WriteTimeout,
 	}
+	srv.RegisterOnShutdown(inboxHub.Close)
 
 	// Start server
 	go func() {
@@ -155,11 +268,17 @@ func main() {
 
 	log.Info().Msg("shutting down server")
 
+	// Cancel context to stop the consumer and scheduler
+	cancel()
+
+	// Stop the job scheduler (waits for in-flight runs)
+	scheduler.Stop()
+
 	// Graceful shutdown
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer shutdownCancel()
 
-	if err := srv.Shutdown(ctx); err != nil {
+	if err := srv.Shutdown(shutdownCtx); err != nil {
 		log.Error().Err(err).Msg("server forced to shutdown")
 	}
 
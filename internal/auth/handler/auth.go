@@ -28,12 +28,12 @@ func NewAuthHandler(svc *service.AuthService, log *logger.Logger) *AuthHandler {
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	var req service.LoginRequest
 	if err := httputil.DecodeJSON(r, &req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	if err := httputil.Validate(&req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -42,7 +42,7 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 
 	response, err := h.service.Login(r.Context(), &req, userAgent, ipAddress)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -80,18 +80,18 @@ func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := httputil.DecodeJSON(r, &req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	if err := httputil.Validate(&req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	tokens, err := h.service.Refresh(r.Context(), req.RefreshToken)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -102,7 +102,7 @@ func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
 func (h *AuthHandler) Me(w http.ResponseWriter, r *http.Request) {
 	userID := r.Header.Get("X-User-ID")
 	if userID == "" {
-		httputil.Error(w, errors.Unauthorized("not authenticated"))
+		httputil.ErrorLocalized(w, r, errors.Unauthorized("errors.auth.not_authenticated"))
 		return
 	}
 
@@ -112,7 +112,7 @@ func (h *AuthHandler) Me(w http.ResponseWriter, r *http.Request) {
 
 	user, err := h.service.GetCurrentUser(r.Context(), userID, tenantID, tenantSlug)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -116,14 +116,14 @@ func (s *AuthService) Login(ctx context.Context, req *LoginRequest, userAgent, i
 	// Generate tokens with the session ID
 	tokens, err := s.jwtManager.GenerateTokenPair(tokenInfo, sessionID)
 	if err != nil {
-		return nil, errors.Internal("failed to generate tokens")
+		return nil, errors.Internal("errors.auth.token_generation_failed")
 	}
 
 	// Create session with the actual refresh token
 	_, err = s.repo.CreateWithID(ctx, sessionID, user.ID, tokens.RefreshToken, expiresAt, userAgent, ipAddress)
 	if err != nil {
 		s.logger.Error().Err(err).Msg("failed to create session")
-		return nil, errors.Internal("failed to create session")
+		return nil, errors.Internal("errors.auth.session_create_failed")
 	}
 
 	return &LoginResponse{
@@ -154,12 +154,12 @@ func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*jwt.To
 	// Get session
 	session, err := s.repo.GetByRefreshToken(ctx, refreshToken)
 	if err != nil {
-		return nil, errors.Unauthorized("invalid session")
+		return nil, errors.Unauthorized("errors.auth.invalid_session")
 	}
 
 	// Check session is not revoked
 	if session.RevokedAt != nil {
-		return nil, errors.Unauthorized("session revoked")
+		return nil, errors.Unauthorized("errors.auth.session_revoked")
 	}
 
 	// Get user info from user service (pass tenant context from refresh token claims)
@@ -185,13 +185,13 @@ func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*jwt.To
 
 	tokens, err := s.jwtManager.GenerateTokenPair(tokenInfo, session.ID)
 	if err != nil {
-		return nil, errors.Internal("failed to generate tokens")
+		return nil, errors.Internal("errors.auth.token_generation_failed")
 	}
 
 	// CRITICAL: Update session with new refresh token hash for token rotation
 	if err := s.repo.UpdateRefreshTokenHash(ctx, session.ID, tokens.RefreshToken); err != nil {
 		s.logger.Error().Err(err).Msg("failed to update refresh token hash")
-		return nil, errors.Internal("failed to update session")
+		return nil, errors.Internal("errors.auth.session_update_failed")
 	}
 
 	return tokens, nil
@@ -230,7 +230,7 @@ func (s *AuthService) validateCredentials(ctx context.Context, identifier, passw
 				Str("expected_tenant", *tenantSlug).
 				Str("actual_tenant", lookup.TenantSlug).
 				Msg("tenant mismatch: email belongs to different tenant")
-			return nil, errors.BadRequest("tenant_mismatch")
+			return nil, errors.NewWithKey("TENANT_MISMATCH", "errors.auth.tenant_mismatch", http.StatusBadRequest)
 		}
 
 		s.logger.Debug().
@@ -245,7 +245,7 @@ func (s *AuthService) validateCredentials(ctx context.Context, identifier, passw
 			s.logger.Debug().
 				Str("username", identifier).
 				Msg("username login attempted without tenant_slug (subdomain required)")
-			return nil, errors.BadRequest("username_requires_subdomain")
+			return nil, errors.NewWithKey("USERNAME_REQUIRES_SUBDOMAIN", "errors.auth.username_requires_subdomain", http.StatusBadRequest)
 		}
 
 		// Lookup by username AND tenant slug
@@ -275,12 +275,12 @@ func (s *AuthService) validateCredentials(ctx context.Context, identifier, passw
 
 	bodyBytes, err := json.Marshal(requestBody)
 	if err != nil {
-		return nil, errors.Internal("failed to encode request")
+		return nil, errors.Internal("errors.upstream.request_failed")
 	}
 
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(bodyBytes))
 	if err != nil {
-		return nil, errors.Internal("failed to create request")
+		return nil, errors.Internal("errors.upstream.request_failed")
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -292,7 +292,7 @@ func (s *AuthService) validateCredentials(ctx context.Context, identifier, passw
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		s.logger.Error().Err(err).Msg("failed to call user service")
-		return nil, errors.Internal("authentication service unavailable")
+		return nil, errors.Internal("errors.auth.service_unavailable")
 	}
 	defer resp.Body.Close()
 
@@ -301,7 +301,7 @@ func (s *AuthService) validateCredentials(ctx context.Context, identifier, passw
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, errors.Internal("failed to validate credentials")
+		return nil, errors.Internal("errors.auth.credentials_check_failed")
 	}
 
 	var result struct {
@@ -310,7 +310,7 @@ func (s *AuthService) validateCredentials(ctx context.Context, identifier, passw
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, errors.Internal("failed to parse response")
+		return nil, errors.Internal("errors.upstream.invalid_response")
 	}
 
 	// Ensure tenant context is populated in response
@@ -328,7 +328,7 @@ func (s *AuthService) getUserInfo(ctx context.Context, userID, tenantID, tenantS
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return nil, errors.Internal("failed to create request")
+		return nil, errors.Internal("errors.upstream.request_failed")
 	}
 
 	// Forward tenant headers to user service (required for RLS-based tenant isolation)
@@ -342,7 +342,7 @@ func (s *AuthService) getUserInfo(ctx context.Context, userID, tenantID, tenantS
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		s.logger.Error().Err(err).Msg("failed to call user service")
-		return nil, errors.Internal("user service unavailable")
+		return nil, errors.Internal("errors.upstream.user_service_unavailable")
 	}
 	defer resp.Body.Close()
 
@@ -352,7 +352,7 @@ func (s *AuthService) getUserInfo(ctx context.Context, userID, tenantID, tenantS
 
 	if resp.StatusCode != http.StatusOK {
 		s.logger.Error().Int("status", resp.StatusCode).Str("user_id", userID).Msg("user service returned error")
-		return nil, errors.Internal("failed to get user info")
+		return nil, errors.Internal("errors.auth.user_info_failed")
 	}
 
 	var result struct {
@@ -361,7 +361,7 @@ func (s *AuthService) getUserInfo(ctx context.Context, userID, tenantID, tenantS
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, errors.Internal("failed to parse response")
+		return nil, errors.Internal("errors.upstream.invalid_response")
 	}
 
 	return result.Data, nil
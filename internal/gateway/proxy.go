@@ -24,13 +24,17 @@ type Proxy struct {
 	userProxy *httputil.ReverseProxy
 	staffProxy *httputil.ReverseProxy
 	inventoryProxy *httputil.ReverseProxy
+
+	// client calls services directly for fan-out endpoints (see search.go)
+	client *http.Client
 }
 
 // NewProxy creates a new proxy instance
 func NewProxy(cfg *config.Config, log *logger.Logger) *Proxy {
 	p := &Proxy{
-		cfg: cfg,
-		log: log,
+		cfg:    cfg,
+		log:    log,
+		client: &http.Client{Timeout: searchTimeout},
 	}
 
 	p.authProxy = p.createProxy(cfg.Services.AuthServiceURL)
@@ -54,7 +58,7 @@ func (p *Proxy) createProxy(targetURL string) *httputil.ReverseProxy {
 
 	proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
 		p.log.Error().Err(err).Str("path", r.URL.Path).Msg("proxy error")
-		pkghttp.Error(w, errors.Internal("service unavailable"))
+		pkghttp.ErrorLocalized(w, r, errors.Internal("errors.service_unavailable"))
 	}
 
 	return proxy
@@ -86,18 +90,56 @@ func (p *Proxy) ForwardToInventory(w http.ResponseWriter, r *http.Request) {
 	p.inventoryProxy.ServeHTTP(w, r)
 }
 
+// ForwardSensorReadings forwards readings signed by a temperature sensor to
+// the inventory service. No token is required: the service verifies the
+// signature and resolves the tenant from the sensor key.
+func (p *Proxy) ForwardSensorReadings(w http.ResponseWriter, r *http.Request) {
+	stripIdentityHeaders(r)
+	p.inventoryProxy.ServeHTTP(w, r)
+}
+
+// stripIdentityHeaders removes client-supplied identity headers from a
+// request forwarded without a token; only AuthMiddleware may set them
+func stripIdentityHeaders(r *http.Request) {
+	for _, h := range []string{"X-Tenant-ID", "X-Tenant-Slug", "X-User-ID", "X-User-Email", "X-User-Role", "X-User-Permissions"} {
+		r.Header.Del(h)
+	}
+}
+
+// ForwardToFiles forwards a signed download URL (/files/tenants/<tenant>/<service>/...)
+// to the service that stored the object. No token is required: the service
+// verifies the URL signature.
+func (p *Proxy) ForwardToFiles(w http.ResponseWriter, r *http.Request) {
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/files/"), "/", 4)
+	if len(parts) < 4 || parts[0] != "tenants" {
+		pkghttp.ErrorLocalized(w, r, errors.NotFound("file"))
+		return
+	}
+
+	stripIdentityHeaders(r)
+
+	switch parts[2] {
+	case "inventory":
+		p.inventoryProxy.ServeHTTP(w, r)
+	case "staff":
+		p.staffProxy.ServeHTTP(w, r)
+	default:
+		pkghttp.ErrorLocalized(w, r, errors.NotFound("file"))
+	}
+}
+
 // AuthMiddleware validates JWT tokens and adds user context
 func (p *Proxy) AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
-			pkghttp.Error(w, errors.Unauthorized("missing authorization header"))
+			pkghttp.ErrorLocalized(w, r, errors.Unauthorized("errors.auth.missing_authorization"))
 			return
 		}
 
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			pkghttp.Error(w, errors.Unauthorized("invalid authorization header format"))
+			pkghttp.ErrorLocalized(w, r, errors.Unauthorized("errors.auth.invalid_authorization_format"))
 			return
 		}
 
@@ -114,16 +156,16 @@ func (p *Proxy) AuthMiddleware(next http.Handler) http.Handler {
 		if err != nil {
 			p.log.Debug().Err(err).Msg("token validation failed")
 			if strings.Contains(err.Error(), "expired") {
-				pkghttp.Error(w, errors.TokenExpired())
+				pkghttp.ErrorLocalized(w, r, errors.TokenExpired())
 			} else {
-				pkghttp.Error(w, errors.TokenInvalid())
+				pkghttp.ErrorLocalized(w, r, errors.TokenInvalid())
 			}
 			return
 		}
 
 		claims, ok := token.Claims.(jwt.MapClaims)
 		if !ok || !token.Valid {
-			pkghttp.Error(w, errors.TokenInvalid())
+			pkghttp.ErrorLocalized(w, r, errors.TokenInvalid())
 			return
 		}
 
@@ -0,0 +1,143 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	pkghttp "github.com/medflow/medflow-backend/pkg/httputil"
+	"github.com/medflow/medflow-backend/pkg/search"
+)
+
+// searchTimeout bounds the whole fan-out; slow services are reported as unavailable
+const searchTimeout = 3 * time.Second
+
+// forwardedSearchHeaders are copied from the gateway request to each service
+var forwardedSearchHeaders = []string{
+	"Authorization",
+	"X-User-ID",
+	"X-User-Email",
+	"X-User-Role",
+	"X-User-Permissions",
+	"X-Tenant-ID",
+	"X-Tenant-Slug",
+	"X-Request-ID",
+	"Accept-Language",
+}
+
+// searchBackend is a service queried by the gateway search
+type searchBackend struct {
+	url   string
+	types []string
+}
+
+// Search fans the query out to the inventory and staff search endpoints
+// concurrently and merges the ranked results by type.
+// GET /api/v1/search?q=&types=item,employee,hygiene_plan&limit=
+func (p *Proxy) Search(w http.ResponseWriter, r *http.Request) {
+	req, err := search.ParseRequest(r)
+	if err != nil {
+		pkghttp.ErrorLocalized(w, r, err)
+		return
+	}
+
+	backends := []searchBackend{
+		{url: p.cfg.Services.InventoryServiceURL + "/api/v1/inventory/search", types: []string{search.TypeItem, search.TypeHygienePlan}},
+		{url: p.cfg.Services.StaffServiceURL + "/api/v1/staff/search", types: []string{search.TypeEmployee}},
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), searchTimeout)
+	defer cancel()
+
+	var (
+		wg          sync.WaitGroup
+		mu          sync.Mutex
+		sets        [][]*search.Result
+		unavailable []string
+	)
+	for _, b := range backends {
+		if !wantsAny(req, b.types) {
+			continue
+		}
+		wg.Add(1)
+		go func(b searchBackend) {
+			defer wg.Done()
+			results, err := p.searchBackend(ctx, r, b.url)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				p.log.Warn().Err(err).Str("backend", b.url).Msg("search backend unavailable")
+				for _, t := range b.types {
+					if req.Wants(t) {
+						unavailable = append(unavailable, t)
+					}
+				}
+				return
+			}
+			sets = append(sets, results)
+		}(b)
+	}
+	wg.Wait()
+
+	pkghttp.JSON(w, http.StatusOK, &search.Response{
+		Query:       req.Query,
+		Groups:      search.Merge(req.Limit, sets...),
+		Unavailable: unavailable,
+	})
+}
+
+// searchBackend queries one service search endpoint with the caller's identity
+func (p *Proxy) searchBackend(ctx context.Context, r *http.Request, url string) ([]*search.Result, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url+"?"+r.URL.RawQuery, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range forwardedSearchHeaders {
+		if v := r.Header.Get(h); v != "" {
+			req.Header.Set(h, v)
+		}
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("search returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Data *search.Response `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode search response: %w", err)
+	}
+	if body.Data == nil {
+		return nil, nil
+	}
+
+	var results []*search.Result
+	for _, g := range body.Data.Groups {
+		for _, res := range g.Results {
+			res.Type = g.Type
+			results = append(results, res)
+		}
+	}
+	return results, nil
+}
+
+// wantsAny reports whether any of types was requested
+func wantsAny(req *search.Request, types []string) bool {
+	for _, t := range types {
+		if req.Wants(t) {
+			return true
+		}
+	}
+	return false
+}
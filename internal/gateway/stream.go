@@ -0,0 +1,40 @@
+package gateway
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// isEventStream reports whether a request opens a Server-Sent Events stream
+func isEventStream(r *http.Request) bool {
+	return strings.HasSuffix(r.URL.Path, "/stream") &&
+		strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+// Timeout cancels requests after d, except event streams, which stay open
+// until the client disconnects
+func Timeout(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		limited := middleware.Timeout(d)(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isEventStream(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			limited.ServeHTTP(w, r)
+		})
+	}
+}
+
+// StreamToUsers forwards an event stream to the user service. The reverse
+// proxy flushes text/event-stream responses as they arrive; the server's
+// write timeout is lifted for the stream's lifetime.
+func (p *Proxy) StreamToUsers(w http.ResponseWriter, r *http.Request) {
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		p.log.Debug().Err(err).Msg("cannot clear write deadline of event stream")
+	}
+	p.userProxy.ServeHTTP(w, r)
+}
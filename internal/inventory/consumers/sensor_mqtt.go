@@ -0,0 +1,231 @@
+package consumers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/pkg/config"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/medflow/medflow-backend/pkg/tenant"
+)
+
+// sensorQoS is the MQTT quality of service of sensor subscriptions: the
+// broker redelivers a reading until the subscriber acknowledged it
+const sensorQoS = 1
+
+// sensorQueueSize bounds readings received but not yet recorded. The broker
+// limits unacknowledged QoS 1 messages per client to far fewer.
+const sensorQueueSize = 256
+
+// TemperatureRecorder records a cabinet temperature reading
+// (service.InventoryService)
+type TemperatureRecorder interface {
+	RecordTemperature(ctx context.Context, cabinetID string, tempCelsius float64, recordedAt time.Time, source string, recordedBy *string, notes *string) (*repository.TemperatureReading, error)
+}
+
+// SensorMQTTConsumer subscribes to the topics fridge data loggers publish
+// their readings to and records them for the mapped cabinets. It keeps a
+// persistent session and acknowledges a reading only once it is recorded,
+// so readings published while the service is down or the database is
+// unavailable are delivered later.
+type SensorMQTTConsumer struct {
+	client   paho.Client
+	topics   map[string]config.MQTTTopic
+	recorder TemperatureRecorder
+	retryMax time.Duration
+	timeout  time.Duration
+	queue    chan paho.Message
+	closing  chan struct{}
+	stopped  chan struct{}
+	logger   *logger.Logger
+}
+
+// NewSensorMQTTConsumer creates a new MQTT sensor consumer
+func NewSensorMQTTConsumer(cfg config.MQTTConfig, recorder TemperatureRecorder, log *logger.Logger) (*SensorMQTTConsumer, error) {
+	mappings, err := cfg.TopicMappings()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &SensorMQTTConsumer{
+		topics:   make(map[string]config.MQTTTopic, len(mappings)),
+		recorder: recorder,
+		retryMax: cfg.MaxReconnectInterval,
+		timeout:  cfg.ConnectTimeout,
+		queue:    make(chan paho.Message, sensorQueueSize),
+		closing:  make(chan struct{}),
+		stopped:  make(chan struct{}),
+		logger:   log,
+	}
+	for _, m := range mappings {
+		c.topics[m.Topic] = m
+	}
+	if c.retryMax <= 0 {
+		c.retryMax = time.Minute
+	}
+
+	opts := paho.NewClientOptions().
+		AddBroker(cfg.BrokerURL).
+		SetClientID(cfg.ClientID).
+		SetUsername(cfg.Username).
+		SetPassword(cfg.Password).
+		SetCleanSession(false).
+		SetAutoAckDisabled(true).
+		SetOrderMatters(true).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetConnectTimeout(cfg.ConnectTimeout).
+		SetMaxReconnectInterval(c.retryMax).
+		// Readings queued in the session may arrive before the subscriptions
+		// of a new connection are registered
+		SetDefaultPublishHandler(c.receive).
+		SetOnConnectHandler(c.subscribe).
+		SetConnectionLostHandler(func(_ paho.Client, err error) {
+			log.Warn().Err(err).Msg("MQTT connection lost, reconnecting")
+		})
+	c.client = paho.NewClient(opts)
+
+	return c, nil
+}
+
+// Start connects to the broker and records readings until ctx is cancelled.
+// An unreachable broker is retried in the background.
+func (c *SensorMQTTConsumer) Start(ctx context.Context) error {
+	token := c.client.Connect()
+	if token.WaitTimeout(c.timeout) && token.Error() != nil {
+		return fmt.Errorf("failed to connect to MQTT broker: %w", token.Error())
+	}
+	if !c.client.IsConnected() {
+		c.logger.Warn().Msg("MQTT broker not reachable yet, retrying in the background")
+	}
+
+	go c.run(ctx)
+	return nil
+}
+
+// Stopped is closed once the consumer disconnected after ctx was cancelled
+func (c *SensorMQTTConsumer) Stopped() <-chan struct{} {
+	return c.stopped
+}
+
+// subscribe (re)subscribes to the mapped topics on every connect
+func (c *SensorMQTTConsumer) subscribe(client paho.Client) {
+	filters := make(map[string]byte, len(c.topics))
+	for topic := range c.topics {
+		filters[topic] = sensorQoS
+	}
+
+	token := client.SubscribeMultiple(filters, c.receive)
+	go func() {
+		if token.Wait() && token.Error() != nil {
+			c.logger.Error().Err(token.Error()).Msg("failed to subscribe to MQTT sensor topics")
+			return
+		}
+		c.logger.Info().Int("topics", len(filters)).Msg("subscribed to MQTT sensor topics")
+	}()
+}
+
+// receive hands a message to the recording goroutine in arrival order
+func (c *SensorMQTTConsumer) receive(_ paho.Client, msg paho.Message) {
+	select {
+	case c.queue <- msg:
+	case <-c.closing:
+	}
+}
+
+func (c *SensorMQTTConsumer) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			// Unacknowledged readings stay in the session
+			close(c.closing)
+			c.client.Disconnect(250)
+			close(c.stopped)
+			return
+		case msg := <-c.queue:
+			if c.handle(ctx, msg) {
+				msg.Ack()
+			}
+		}
+	}
+}
+
+// handle records a message and reports whether it is done with: recorded,
+// already recorded, or rejected for good. A failure that may pass (e.g. the
+// database is unreachable) is retried with backoff until ctx is cancelled.
+func (c *SensorMQTTConsumer) handle(ctx context.Context, msg paho.Message) bool {
+	mapping, ok := c.topics[msg.Topic()]
+	if !ok {
+		c.logger.Warn().Str("topic", msg.Topic()).Msg("ignoring MQTT message on unmapped topic")
+		return true
+	}
+
+	celsius, recordedAt, err := parseSensorPayload(msg.Payload(), time.Now())
+	if err != nil {
+		c.logger.Warn().Err(err).Str("topic", msg.Topic()).Msg("ignoring malformed MQTT sensor reading")
+		return true
+	}
+
+	tenantCtx := tenant.WithTenantContext(ctx, mapping.TenantID, "")
+	for delay := time.Second; ; delay = min(2*delay, c.retryMax) {
+		_, err := c.recorder.RecordTemperature(tenantCtx, mapping.CabinetID, celsius, recordedAt, "sensor", nil, nil)
+		if err == nil {
+			return true
+		}
+
+		var appErr *errors.AppError
+		if stderrors.As(err, &appErr) && appErr.StatusCode < http.StatusInternalServerError {
+			if appErr.StatusCode != http.StatusConflict {
+				c.logger.Warn().Err(err).Str("topic", msg.Topic()).Str("cabinet_id", mapping.CabinetID).
+					Msg("MQTT sensor reading rejected")
+			}
+			return true
+		}
+
+		c.logger.Error().Err(err).Str("topic", msg.Topic()).Dur("retry_in", delay).
+			Msg("failed to record MQTT sensor reading")
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(delay):
+		}
+	}
+}
+
+// sensorPayload is a reading published as JSON. Loggers should send
+// recorded_at: it deduplicates redelivered readings and keeps the time of
+// readings buffered while offline.
+type sensorPayload struct {
+	TemperatureCelsius *float64   `json:"temperature_celsius"`
+	RecordedAt         *time.Time `json:"recorded_at"`
+}
+
+// parseSensorPayload reads a JSON reading or a bare number in °C, which is
+// taken as measured on receipt
+func parseSensorPayload(payload []byte, received time.Time) (float64, time.Time, error) {
+	payload = bytes.TrimSpace(payload)
+	if celsius, err := strconv.ParseFloat(string(payload), 64); err == nil {
+		return celsius, received, nil
+	}
+
+	var p sensorPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return 0, time.Time{}, fmt.Errorf("payload is neither a number nor a JSON reading: %w", err)
+	}
+	if p.TemperatureCelsius == nil {
+		return 0, time.Time{}, stderrors.New("payload has no temperature_celsius")
+	}
+	if p.RecordedAt == nil {
+		return *p.TemperatureCelsius, received, nil
+	}
+	return *p.TemperatureCelsius, *p.RecordedAt, nil
+}
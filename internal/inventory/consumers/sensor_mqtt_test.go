@@ -0,0 +1,227 @@
+package consumers_test
+
+import (
+	"context"
+	stderrors "errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/google/uuid"
+	"github.com/medflow/medflow-backend/internal/inventory/consumers"
+	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/pkg/config"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/medflow/medflow-backend/pkg/tenant"
+	"github.com/medflow/medflow-backend/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var broker *testutil.MosquittoContainer
+
+func TestMain(m *testing.M) {
+	ctx := context.Background()
+	var err error
+	broker, err = testutil.NewMosquittoContainer(ctx)
+	if err != nil {
+		panic(err)
+	}
+	code := m.Run()
+	broker.Terminate(ctx)
+	os.Exit(code)
+}
+
+// recordedReading is a RecordTemperature call seen by fakeRecorder
+type recordedReading struct {
+	TenantID   string
+	CabinetID  string
+	Celsius    float64
+	RecordedAt time.Time
+	Source     string
+}
+
+// fakeRecorder reports calls on a channel and fails them with the queued errors
+type fakeRecorder struct {
+	mu       sync.Mutex
+	errs     []error
+	readings chan recordedReading
+}
+
+func newFakeRecorder(errs ...error) *fakeRecorder {
+	return &fakeRecorder{errs: errs, readings: make(chan recordedReading, 16)}
+}
+
+func (f *fakeRecorder) RecordTemperature(ctx context.Context, cabinetID string, tempCelsius float64, recordedAt time.Time, source string, _ *string, _ *string) (*repository.TemperatureReading, error) {
+	tenantID, _ := tenant.TenantID(ctx)
+	f.readings <- recordedReading{TenantID: tenantID, CabinetID: cabinetID, Celsius: tempCelsius, RecordedAt: recordedAt, Source: source}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.errs) > 0 {
+		err := f.errs[0]
+		f.errs = f.errs[1:]
+		return nil, err
+	}
+	return &repository.TemperatureReading{CabinetID: cabinetID, TemperatureCelsius: tempCelsius, RecordedAt: recordedAt}, nil
+}
+
+func (f *fakeRecorder) next(t *testing.T) recordedReading {
+	t.Helper()
+	select {
+	case r := <-f.readings:
+		return r
+	case <-time.After(10 * time.Second):
+		t.Fatal("no reading recorded")
+		return recordedReading{}
+	}
+}
+
+func (f *fakeRecorder) none(t *testing.T, wait time.Duration) {
+	t.Helper()
+	select {
+	case r := <-f.readings:
+		t.Fatalf("unexpected reading recorded: %+v", r)
+	case <-time.After(wait):
+	}
+}
+
+func mqttConfig(clientID string, topics ...string) config.MQTTConfig {
+	return config.MQTTConfig{
+		Enabled:              true,
+		BrokerURL:            broker.BrokerURL,
+		ClientID:             clientID,
+		ConnectTimeout:       10 * time.Second,
+		MaxReconnectInterval: 2 * time.Second,
+		Topics:               topics,
+	}
+}
+
+// startConsumer starts a consumer and returns a func stopping it
+func startConsumer(t *testing.T, cfg config.MQTTConfig, recorder consumers.TemperatureRecorder) func() {
+	t.Helper()
+	c, err := consumers.NewSensorMQTTConsumer(cfg, recorder, logger.New("test", "test"))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, c.Start(ctx))
+
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			cancel()
+			<-c.Stopped()
+		})
+	}
+	t.Cleanup(stop)
+	return stop
+}
+
+func publish(t *testing.T, topic, payload string) {
+	t.Helper()
+	client := paho.NewClient(paho.NewClientOptions().AddBroker(broker.BrokerURL).SetClientID("publisher-" + uuid.NewString()))
+	token := client.Connect()
+	require.True(t, token.WaitTimeout(10*time.Second))
+	require.NoError(t, token.Error())
+	defer client.Disconnect(250)
+
+	token = client.Publish(topic, 1, false, payload)
+	require.True(t, token.WaitTimeout(10*time.Second))
+	require.NoError(t, token.Error())
+}
+
+// waitSubscribed publishes probes until the consumer receives one, since the
+// subscription completes asynchronously after Start
+func waitSubscribed(t *testing.T, recorder *fakeRecorder, topic string) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		publish(t, topic, `{"temperature_celsius": 5, "recorded_at": "2026-01-01T00:00:00Z"}`)
+		select {
+		case <-recorder.readings:
+			return
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+	t.Fatal("consumer did not subscribe")
+}
+
+func TestSensorMQTTConsumer_RecordsReadings(t *testing.T) {
+	tenantID, cabinetID := uuid.NewString(), uuid.NewString()
+	topic := "praxis/" + uuid.NewString() + "/fridge"
+	recorder := newFakeRecorder()
+	startConsumer(t, mqttConfig("test-"+uuid.NewString(), topic+"="+tenantID+":"+cabinetID), recorder)
+	waitSubscribed(t, recorder, topic)
+
+	publish(t, topic, `{"temperature_celsius": 4.5, "recorded_at": "2026-03-01T10:15:00Z"}`)
+	r := recorder.next(t)
+	assert.Equal(t, tenantID, r.TenantID)
+	assert.Equal(t, cabinetID, r.CabinetID)
+	assert.Equal(t, 4.5, r.Celsius)
+	assert.True(t, r.RecordedAt.Equal(time.Date(2026, 3, 1, 10, 15, 0, 0, time.UTC)))
+	assert.Equal(t, "sensor", r.Source)
+
+	// A bare number is taken as measured on receipt
+	before := time.Now()
+	publish(t, topic, " 6.25\n")
+	r = recorder.next(t)
+	assert.Equal(t, 6.25, r.Celsius)
+	assert.WithinDuration(t, before, r.RecordedAt, 10*time.Second)
+
+	// Malformed payloads are dropped without stalling the topic
+	publish(t, topic, `{"humidity": 40}`)
+	publish(t, topic, "warm")
+	publish(t, topic, "7")
+	assert.Equal(t, 7.0, recorder.next(t).Celsius)
+}
+
+func TestSensorMQTTConsumer_RetriesTransientErrors(t *testing.T) {
+	tenantID, cabinetID := uuid.NewString(), uuid.NewString()
+	topic := "praxis/" + uuid.NewString() + "/fridge"
+	recorder := newFakeRecorder()
+	startConsumer(t, mqttConfig("test-"+uuid.NewString(), topic+"="+tenantID+":"+cabinetID), recorder)
+	waitSubscribed(t, recorder, topic)
+
+	// A database outage is retried, a duplicate is acknowledged, and
+	// readings keep their order
+	recorder.mu.Lock()
+	recorder.errs = []error{stderrors.New("connection refused"), errors.Conflict("errors.temperature.duplicate_reading")}
+	recorder.mu.Unlock()
+
+	publish(t, topic, "3")
+	publish(t, topic, "4")
+	assert.Equal(t, 3.0, recorder.next(t).Celsius)
+	assert.Equal(t, 3.0, recorder.next(t).Celsius)
+	assert.Equal(t, 4.0, recorder.next(t).Celsius)
+	recorder.none(t, 2*time.Second)
+}
+
+func TestSensorMQTTConsumer_DeliversReadingsPublishedWhileStopped(t *testing.T) {
+	tenantID, cabinetID := uuid.NewString(), uuid.NewString()
+	topic := "praxis/" + uuid.NewString() + "/fridge"
+	cfg := mqttConfig("test-"+uuid.NewString(), topic+"="+tenantID+":"+cabinetID)
+
+	recorder := newFakeRecorder()
+	stop := startConsumer(t, cfg, recorder)
+	waitSubscribed(t, recorder, topic)
+	stop()
+
+	// The persistent session queues QoS 1 readings until the service is back
+	publish(t, topic, `{"temperature_celsius": 9.5, "recorded_at": "2026-03-01T03:00:00Z"}`)
+
+	recorder = newFakeRecorder()
+	startConsumer(t, cfg, recorder)
+	r := recorder.next(t)
+	assert.Equal(t, 9.5, r.Celsius)
+	assert.Equal(t, cabinetID, r.CabinetID)
+	assert.True(t, r.RecordedAt.Equal(time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC)))
+}
+
+func TestNewSensorMQTTConsumer_InvalidTopics(t *testing.T) {
+	_, err := consumers.NewSensorMQTTConsumer(mqttConfig("test", "praxis/+/fridge="+uuid.NewString()+":"+uuid.NewString()),
+		newFakeRecorder(), logger.New("test", "test"))
+	assert.Error(t, err)
+}
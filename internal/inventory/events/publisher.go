@@ -54,6 +54,31 @@ func (p *InventoryEventPublisher) PublishStockAdjusted(ctx context.Context, adj
 	}
 }
 
+// PublishStockTransferred publishes a stock transferred event
+func (p *InventoryEventPublisher) PublishStockTransferred(ctx context.Context, t *repository.StockTransfer) {
+	if p == nil { return }
+	fromLocationID := ""
+	if t.FromLocationID != nil {
+		fromLocationID = *t.FromLocationID
+	}
+
+	data := messaging.StockTransferredEvent{
+		TransferID:     t.ID,
+		ItemID:         t.ItemID,
+		FromBatchID:    t.BatchID,
+		ToBatchID:      t.TargetBatchID,
+		FromLocationID: fromLocationID,
+		ToLocationID:   t.ToLocationID,
+		Quantity:       t.Quantity,
+		Split:          t.Split,
+		PerformedBy:    t.PerformedBy,
+	}
+
+	if err := p.publisher.Publish(ctx, messaging.EventStockTransferred, data); err != nil {
+		p.logger.Error().Err(err).Str("transfer_id", t.ID).Msg("failed to publish stock transferred event")
+	}
+}
+
 // PublishAlertGenerated publishes an alert generated event
 func (p *InventoryEventPublisher) PublishAlertGenerated(ctx context.Context, alert *repository.InventoryAlert) {
 	if p == nil { return }
@@ -75,3 +100,18 @@ func (p *InventoryEventPublisher) PublishAlertGenerated(ctx context.Context, ale
 		p.logger.Error().Err(err).Str("alert_id", alert.ID).Msg("failed to publish alert generated event")
 	}
 }
+
+// PublishAlertAcknowledged publishes an alert acknowledged event
+func (p *InventoryEventPublisher) PublishAlertAcknowledged(ctx context.Context, alertID, userID string) {
+	if p == nil {
+		return
+	}
+	data := messaging.AlertAcknowledgedEvent{
+		AlertID:        alertID,
+		AcknowledgedBy: userID,
+	}
+
+	if err := p.publisher.Publish(ctx, messaging.EventAlertAcknowledged, data); err != nil {
+		p.logger.Error().Err(err).Str("alert_id", alertID).Msg("failed to publish alert acknowledged event")
+	}
+}
@@ -5,22 +5,26 @@ import (
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/medflow/medflow-backend/internal/inventory/events"
 	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/pkg/database"
 	"github.com/medflow/medflow-backend/pkg/httputil"
 	"github.com/medflow/medflow-backend/pkg/logger"
 )
 
 // AlertHandler handles alert endpoints
 type AlertHandler struct {
-	repo   *repository.AlertRepository
-	logger *logger.Logger
+	repo      *repository.AlertRepository
+	publisher *events.InventoryEventPublisher
+	logger    *logger.Logger
 }
 
 // NewAlertHandler creates a new alert handler
-func NewAlertHandler(repo *repository.AlertRepository, log *logger.Logger) *AlertHandler {
+func NewAlertHandler(repo *repository.AlertRepository, publisher *events.InventoryEventPublisher, log *logger.Logger) *AlertHandler {
 	return &AlertHandler{
-		repo:   repo,
-		logger: log,
+		repo:      repo,
+		publisher: publisher,
+		logger:    log,
 	}
 }
 
@@ -44,9 +48,9 @@ func (h *AlertHandler) List(w http.ResponseWriter, r *http.Request) {
 
 	alertType := r.URL.Query().Get("type")
 
-	alerts, total, err := h.repo.List(r.Context(), acknowledged, alertType, page, perPage)
+	alerts, total, err := h.repo.List(database.ReadOnly(r.Context()), acknowledged, alertType, page, perPage)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -69,9 +73,11 @@ func (h *AlertHandler) Acknowledge(w http.ResponseWriter, r *http.Request) {
 	userID := r.Header.Get("X-User-ID")
 
 	if err := h.repo.Acknowledge(r.Context(), id, userID); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
+	// Stops the escalation of the alert's notifications
+	h.publisher.PublishAlertAcknowledged(r.Context(), id, userID)
 
 	httputil.NoContent(w)
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/medflow/medflow-backend/internal/inventory/service"
+	"github.com/medflow/medflow-backend/pkg/database"
 	"github.com/medflow/medflow-backend/pkg/httputil"
 	"github.com/medflow/medflow-backend/pkg/logger"
 )
@@ -42,10 +43,10 @@ func (h *AuditHandler) GetItemAudit(w http.ResponseWriter, r *http.Request) {
 		perPage = 50
 	}
 
-	entries, total, err := h.auditService.ListByEntity(r.Context(), "item", itemID, page, perPage)
+	entries, total, err := h.auditService.ListByEntity(database.ReadOnly(r.Context()), "item", itemID, page, perPage)
 	if err != nil {
 		h.logger.Error().Err(err).Str("item_id", itemID).Msg("failed to list item audit entries")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -91,10 +92,10 @@ func (h *AuditHandler) ListAudit(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	entries, total, err := h.auditService.ListByTenant(r.Context(), entityType, from, to, page, perPage)
+	entries, total, err := h.auditService.ListByTenant(database.ReadOnly(r.Context()), entityType, from, to, page, perPage)
 	if err != nil {
 		h.logger.Error().Err(err).Msg("failed to list audit entries")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -128,10 +129,10 @@ func (h *AuditHandler) ExportGoBD(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	entries, err := h.auditService.ExportGoBD(r.Context(), from, to)
+	entries, err := h.auditService.ExportGoBD(exportContext(r), from, to)
 	if err != nil {
 		h.logger.Error().Err(err).Msg("failed to export GoBD audit trail")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -148,3 +149,17 @@ func (h *AuditHandler) ExportGoBD(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 }
+
+// Verify re-walks the tenant's audit trail hash chain and reports the first
+// changed, deleted or reordered entry
+// GET /audit/verify
+func (h *AuditHandler) Verify(w http.ResponseWriter, r *http.Request) {
+	result, err := h.auditService.Verify(exportContext(r))
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to verify audit trail")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, result)
+}
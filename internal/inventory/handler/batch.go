@@ -30,7 +30,7 @@ func (h *BatchHandler) ListByItem(w http.ResponseWriter, r *http.Request) {
 
 	batches, err := h.service.ListBatchesByItem(r.Context(), itemID)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -43,7 +43,7 @@ func (h *BatchHandler) Get(w http.ResponseWriter, r *http.Request) {
 
 	batch, err := h.service.GetBatch(r.Context(), id)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -56,7 +56,7 @@ func (h *BatchHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	var batch repository.InventoryBatch
 	if err := httputil.DecodeJSON(r, &batch); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -69,7 +69,7 @@ func (h *BatchHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 	if err := h.service.CreateBatch(r.Context(), &batch); err != nil {
 		h.logger.Error().Err(err).Str("item_id", itemID).Msg("failed to create batch")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -82,13 +82,13 @@ func (h *BatchHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 	var batch repository.InventoryBatch
 	if err := httputil.DecodeJSON(r, &batch); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	batch.ID = id
 	if err := h.service.UpdateBatch(r.Context(), &batch); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -100,7 +100,7 @@ func (h *BatchHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	if err := h.service.DeleteBatch(r.Context(), id); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -114,7 +114,7 @@ func (h *BatchHandler) OpenBatch(w http.ResponseWriter, r *http.Request) {
 	batch, err := h.service.OpenBatch(r.Context(), batchID)
 	if err != nil {
 		h.logger.Error().Err(err).Str("batch_id", batchID).Msg("failed to open batch")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -131,12 +131,12 @@ func (h *BatchHandler) AdjustStock(w http.ResponseWriter, r *http.Request) {
 		Reason   string `json:"reason"`
 	}
 	if err := httputil.DecodeJSON(r, &req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	if err := httputil.Validate(&req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -145,7 +145,7 @@ func (h *BatchHandler) AdjustStock(w http.ResponseWriter, r *http.Request) {
 
 	adj, err := h.service.AdjustStock(r.Context(), batchID, req.Quantity, req.Type, req.Reason, userID, userName)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -6,6 +6,7 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/medflow/medflow-backend/internal/inventory/repository"
 	"github.com/medflow/medflow-backend/internal/inventory/service"
+	"github.com/medflow/medflow-backend/pkg/database"
 	"github.com/medflow/medflow-backend/pkg/httputil"
 	"github.com/medflow/medflow-backend/pkg/logger"
 )
@@ -31,13 +32,13 @@ func NewBioSafetyHandler(svc *service.BioSafetyService, log *logger.Logger) *Bio
 func (h *BioSafetyHandler) CreateAssessment(w http.ResponseWriter, r *http.Request) {
 	var assessment repository.BioRiskAssessment
 	if err := httputil.DecodeJSON(r, &assessment); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	if err := h.service.CreateAssessment(r.Context(), &assessment); err != nil {
 		h.logger.Error().Err(err).Str("item_id", assessment.ItemID).Msg("failed to create bio risk assessment")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -49,14 +50,20 @@ func (h *BioSafetyHandler) CreateAssessment(w http.ResponseWriter, r *http.Reque
 func (h *BioSafetyHandler) ListAssessmentsByItem(w http.ResponseWriter, r *http.Request) {
 	itemID := chi.URLParam(r, "itemId")
 
-	assessments, err := h.service.ListAssessmentsByItem(r.Context(), itemID)
+	q, err := httputil.ParseListQuery(r)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	page, err := h.service.ListAssessmentsByItem(database.ReadOnly(r.Context()), itemID, q)
 	if err != nil {
 		h.logger.Error().Err(err).Str("item_id", itemID).Msg("failed to list bio risk assessments")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
-	httputil.JSON(w, http.StatusOK, assessments)
+	httputil.JSONWithMeta(w, http.StatusOK, page.Items, httputil.PageMeta(q, page))
 }
 
 // UpdateAssessment updates a bio risk assessment
@@ -66,14 +73,14 @@ func (h *BioSafetyHandler) UpdateAssessment(w http.ResponseWriter, r *http.Reque
 
 	var assessment repository.BioRiskAssessment
 	if err := httputil.DecodeJSON(r, &assessment); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	assessment.ID = id
 	if err := h.service.UpdateAssessment(r.Context(), &assessment); err != nil {
 		h.logger.Error().Err(err).Str("id", id).Msg("failed to update bio risk assessment")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -87,7 +94,7 @@ func (h *BioSafetyHandler) DeleteAssessment(w http.ResponseWriter, r *http.Reque
 
 	if err := h.service.DeleteAssessment(r.Context(), id); err != nil {
 		h.logger.Error().Err(err).Str("id", id).Msg("failed to delete bio risk assessment")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -101,13 +108,13 @@ func (h *BioSafetyHandler) DeleteAssessment(w http.ResponseWriter, r *http.Reque
 func (h *BioSafetyHandler) CreateTraining(w http.ResponseWriter, r *http.Request) {
 	var training repository.BioTraining
 	if err := httputil.DecodeJSON(r, &training); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	if err := h.service.CreateTraining(r.Context(), &training); err != nil {
 		h.logger.Error().Err(err).Msg("failed to create bio training")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -120,7 +127,7 @@ func (h *BioSafetyHandler) ListTrainings(w http.ResponseWriter, r *http.Request)
 	trainings, err := h.service.ListTrainings(r.Context())
 	if err != nil {
 		h.logger.Error().Err(err).Msg("failed to list bio trainings")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -134,14 +141,14 @@ func (h *BioSafetyHandler) UpdateTraining(w http.ResponseWriter, r *http.Request
 
 	var training repository.BioTraining
 	if err := httputil.DecodeJSON(r, &training); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	training.ID = id
 	if err := h.service.UpdateTraining(r.Context(), &training); err != nil {
 		h.logger.Error().Err(err).Str("id", id).Msg("failed to update bio training")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -155,7 +162,7 @@ func (h *BioSafetyHandler) DeleteTraining(w http.ResponseWriter, r *http.Request
 
 	if err := h.service.DeleteTraining(r.Context(), id); err != nil {
 		h.logger.Error().Err(err).Str("id", id).Msg("failed to delete bio training")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
@@ -8,6 +9,7 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/medflow/medflow-backend/internal/inventory/repository"
 	"github.com/medflow/medflow-backend/internal/inventory/service"
+	"github.com/medflow/medflow-backend/pkg/errors"
 	"github.com/medflow/medflow-backend/pkg/httputil"
 	"github.com/medflow/medflow-backend/pkg/logger"
 )
@@ -32,7 +34,7 @@ func (h *BtmHandler) ReceiveSubstance(w http.ResponseWriter, r *http.Request) {
 
 	var req service.BtmReceiveRequest
 	if err := httputil.DecodeJSON(r, &req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -41,7 +43,7 @@ func (h *BtmHandler) ReceiveSubstance(w http.ResponseWriter, r *http.Request) {
 	entry, err := h.btmService.ReceiveSubstance(r.Context(), &req)
 	if err != nil {
 		h.logger.Error().Err(err).Str("item_id", itemID).Msg("failed to receive BtM substance")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -54,7 +56,7 @@ func (h *BtmHandler) DispenseSubstance(w http.ResponseWriter, r *http.Request) {
 
 	var req service.BtmDispenseRequest
 	if err := httputil.DecodeJSON(r, &req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -63,7 +65,7 @@ func (h *BtmHandler) DispenseSubstance(w http.ResponseWriter, r *http.Request) {
 	entry, err := h.btmService.DispenseSubstance(r.Context(), &req)
 	if err != nil {
 		h.logger.Error().Err(err).Str("item_id", itemID).Msg("failed to dispense BtM substance")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -76,7 +78,7 @@ func (h *BtmHandler) DisposeSubstance(w http.ResponseWriter, r *http.Request) {
 
 	var req service.BtmDisposeRequest
 	if err := httputil.DecodeJSON(r, &req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -85,7 +87,7 @@ func (h *BtmHandler) DisposeSubstance(w http.ResponseWriter, r *http.Request) {
 	entry, err := h.btmService.DisposeSubstance(r.Context(), &req)
 	if err != nil {
 		h.logger.Error().Err(err).Str("item_id", itemID).Msg("failed to dispose BtM substance")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -98,7 +100,7 @@ func (h *BtmHandler) CorrectEntry(w http.ResponseWriter, r *http.Request) {
 
 	var req service.BtmCorrectionRequest
 	if err := httputil.DecodeJSON(r, &req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -107,7 +109,7 @@ func (h *BtmHandler) CorrectEntry(w http.ResponseWriter, r *http.Request) {
 	entry, err := h.btmService.CorrectEntry(r.Context(), &req)
 	if err != nil {
 		h.logger.Error().Err(err).Str("item_id", itemID).Msg("failed to correct BtM entry")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -120,7 +122,7 @@ func (h *BtmHandler) InventoryCheck(w http.ResponseWriter, r *http.Request) {
 
 	var req service.BtmCheckRequest
 	if err := httputil.DecodeJSON(r, &req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -129,7 +131,7 @@ func (h *BtmHandler) InventoryCheck(w http.ResponseWriter, r *http.Request) {
 	entry, err := h.btmService.InventoryCheck(r.Context(), &req)
 	if err != nil {
 		h.logger.Error().Err(err).Str("item_id", itemID).Msg("failed to create BtM inventory check")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -153,7 +155,7 @@ func (h *BtmHandler) GetRegister(w http.ResponseWriter, r *http.Request) {
 	entries, total, err := h.btmService.GetRegister(r.Context(), itemID, page, perPage)
 	if err != nil {
 		h.logger.Error().Err(err).Str("item_id", itemID).Msg("failed to get BtM register")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -177,7 +179,7 @@ func (h *BtmHandler) GetBalance(w http.ResponseWriter, r *http.Request) {
 	balance, err := h.btmService.GetBalance(r.Context(), itemID)
 	if err != nil {
 		h.logger.Error().Err(err).Str("item_id", itemID).Msg("failed to get BtM balance")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -187,12 +189,91 @@ func (h *BtmHandler) GetBalance(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// Karteikarte handles GET /btm/{itemId}/karteikarte?period=2026-03 (or 2026)
+func (h *BtmHandler) Karteikarte(w http.ResponseWriter, r *http.Request) {
+	itemID := chi.URLParam(r, "itemId")
+	period := r.URL.Query().Get("period")
+
+	pdfBytes, err := h.btmService.Karteikarte(exportContext(r), itemID, period)
+	if err != nil {
+		var appErr *errors.AppError
+		if !errors.As(err, &appErr) {
+			h.logger.Error().Err(err).Str("item_id", itemID).Msg("failed to generate BtM Karteikarte")
+			err = errors.Internal("errors.export.pdf_failed")
+		}
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	filename := fmt.Sprintf("btm-karteikarte-%s-%s.pdf", itemID, period)
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(pdfBytes)))
+	w.Write(pdfBytes)
+}
+
+// PreviewClosing handles GET /btm/closings/preview?period=2026-03 (or 2026)
+func (h *BtmHandler) PreviewClosing(w http.ResponseWriter, r *http.Request) {
+	rec, err := h.btmService.ReconcilePeriod(r.Context(), r.URL.Query().Get("period"))
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to reconcile BtM register")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, rec)
+}
+
+// ClosePeriod handles POST /btm/closings
+func (h *BtmHandler) ClosePeriod(w http.ResponseWriter, r *http.Request) {
+	var req service.BtmCloseRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	closing, err := h.btmService.ClosePeriod(r.Context(), &req)
+	if err != nil {
+		h.logger.Error().Err(err).Str("period", req.Period).Msg("failed to close BtM period")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.Created(w, closing)
+}
+
+// ListClosings handles GET /btm/closings
+func (h *BtmHandler) ListClosings(w http.ResponseWriter, r *http.Request) {
+	closings, err := h.btmService.ListClosings(r.Context())
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to list BtM closings")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, closings)
+}
+
+// GetClosing handles GET /btm/closings/{id}
+func (h *BtmHandler) GetClosing(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	closing, err := h.btmService.GetClosing(r.Context(), id)
+	if err != nil {
+		h.logger.Error().Err(err).Str("id", id).Msg("failed to get BtM closing")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, closing)
+}
+
 // ListAuthorizedPersonnel handles GET /btm/authorized-personnel
 func (h *BtmHandler) ListAuthorizedPersonnel(w http.ResponseWriter, r *http.Request) {
 	persons, err := h.btmService.ListAuthorizedPersonnel(r.Context())
 	if err != nil {
 		h.logger.Error().Err(err).Msg("failed to list BtM authorized personnel")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -203,7 +284,7 @@ func (h *BtmHandler) ListAuthorizedPersonnel(w http.ResponseWriter, r *http.Requ
 func (h *BtmHandler) CreateAuthorizedPerson(w http.ResponseWriter, r *http.Request) {
 	var person repository.BtmAuthorizedPerson
 	if err := httputil.DecodeJSON(r, &person); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -216,7 +297,7 @@ func (h *BtmHandler) CreateAuthorizedPerson(w http.ResponseWriter, r *http.Reque
 
 	if err := h.btmService.CreateAuthorizedPerson(r.Context(), &person); err != nil {
 		h.logger.Error().Err(err).Msg("failed to create BtM authorized person")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -230,7 +311,7 @@ func (h *BtmHandler) RevokeAuthorization(w http.ResponseWriter, r *http.Request)
 
 	if err := h.btmService.RevokeAuthorization(r.Context(), id, userID, userID); err != nil {
 		h.logger.Error().Err(err).Str("id", id).Msg("failed to revoke BtM authorization")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -0,0 +1,340 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/medflow/medflow-backend/internal/inventory/service"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/httputil"
+	"github.com/medflow/medflow-backend/pkg/logger"
+)
+
+// Headers of signed sensor requests
+const (
+	SensorKeyHeader       = "X-MedFlow-Sensor"
+	SensorSignatureHeader = "X-MedFlow-Signature"
+)
+
+// maxSensorBody limits a sensor request (MaxReadingsPerRequest readings fit easily)
+const maxSensorBody = 1 << 20
+
+// ColdChainHandler handles sensor credentials, signed sensor readings and
+// temperature excursions
+type ColdChainHandler struct {
+	service *service.ColdChainService
+	logger  *logger.Logger
+}
+
+// NewColdChainHandler creates a new cold chain handler
+func NewColdChainHandler(svc *service.ColdChainService, log *logger.Logger) *ColdChainHandler {
+	return &ColdChainHandler{
+		service: svc,
+		logger:  log,
+	}
+}
+
+// CreateSensorRequest registers a sensor for a cabinet
+type CreateSensorRequest struct {
+	Name string `json:"name" validate:"required,max=255"`
+}
+
+// ListSensors lists the sensors of a cabinet
+// GET /locations/cabinets/{id}/sensors
+func (h *ColdChainHandler) ListSensors(w http.ResponseWriter, r *http.Request) {
+	sensors, err := h.service.ListSensors(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, sensors)
+}
+
+// CreateSensor registers a sensor; the response carries its secret once
+// POST /locations/cabinets/{id}/sensors
+func (h *ColdChainHandler) CreateSensor(w http.ResponseWriter, r *http.Request) {
+	var req CreateSensorRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+	if err := httputil.Validate(&req); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	sensor, err := h.service.CreateSensor(r.Context(), chi.URLParam(r, "id"), req.Name, r.Header.Get("X-User-ID"))
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to create temperature sensor")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.Created(w, sensor)
+}
+
+// RotateSensorSecret replaces a sensor's secret; the response carries the new one
+// POST /sensors/{id}/rotate
+func (h *ColdChainHandler) RotateSensorSecret(w http.ResponseWriter, r *http.Request) {
+	sensor, err := h.service.RotateSensorSecret(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, sensor)
+}
+
+// RevokeSensor revokes a sensor
+// DELETE /sensors/{id}
+func (h *ColdChainHandler) RevokeSensor(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.RevokeSensor(r.Context(), chi.URLParam(r, "id")); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.NoContent(w)
+}
+
+// IngestReadings accepts the buffered readings of a sensor. The request is
+// signed with the sensor's secret instead of a user token, and the readings
+// are recorded for the cabinet the sensor belongs to.
+// POST /sensors/readings
+func (h *ColdChainHandler) IngestReadings(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxSensorBody)
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, errors.BadRequest("errors.invalid_json"))
+		return
+	}
+
+	ctx, sensor, err := h.service.AuthenticateSensor(r.Context(), r.Header.Get(SensorKeyHeader), r.Header.Get(SensorSignatureHeader), body)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	var req struct {
+		Readings []service.ReadingInput `json:"readings"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		httputil.ErrorLocalized(w, r, errors.BadRequest("errors.invalid_json"))
+		return
+	}
+
+	result, err := h.service.Ingest(ctx, sensor.CabinetID, service.ReadingSource{
+		Source:   "sensor",
+		SensorID: &sensor.ID,
+	}, req.Readings)
+	if err != nil {
+		h.logger.Error().Err(err).Str("sensor_id", sensor.ID).Msg("failed to ingest sensor readings")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, result)
+}
+
+// DailySummaries returns per-day statistics and the mean kinetic temperature
+// GET /locations/cabinets/{id}/temperature/daily?from=2026-01-01&to=2026-01-31
+func (h *ColdChainHandler) DailySummaries(w http.ResponseWriter, r *http.Request) {
+	from, to, err := parsePeriod(r)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	summaries, err := h.service.DailySummaries(database.ReadOnly(r.Context()), chi.URLParam(r, "id"), from, to)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, summaries)
+}
+
+// parsePeriod reads the days from..to (inclusive) of at most a year from the
+// query, or the calendar month given as month=2026-01
+func parsePeriod(r *http.Request) (time.Time, time.Time, error) {
+	query := r.URL.Query()
+	if month := query.Get("month"); month != "" {
+		from, err := time.Parse("2006-01", month)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.BadRequest("errors.invalid_date", map[string]string{"field": "month"})
+		}
+		return from, from.AddDate(0, 1, -1), nil
+	}
+
+	from, err := time.Parse("2006-01-02", query.Get("from"))
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.BadRequest("errors.invalid_date", map[string]string{"field": "from"})
+	}
+	to, err := time.Parse("2006-01-02", query.Get("to"))
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.BadRequest("errors.invalid_date", map[string]string{"field": "to"})
+	}
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, errors.BadRequest("errors.end_before_start")
+	}
+	if to.Sub(from) > 366*24*time.Hour {
+		return time.Time{}, time.Time{}, errors.Validation(nil).WithDetail("to", "validation.range",
+			map[string]string{"field": "to", "min": from.Format("2006-01-02"), "max": from.AddDate(1, 0, 0).Format("2006-01-02")})
+	}
+	return from, to, nil
+}
+
+// TemperatureReport downloads the fridge log (Kühlschrankprotokoll) of a
+// cabinet: daily min/max, chart, excursions with their decisions and the
+// manual checks, with its SHA-256 digest and seal
+// GET /locations/cabinets/{id}/temperature/report?month=2026-01 (or from/to)
+func (h *ColdChainHandler) TemperatureReport(w http.ResponseWriter, r *http.Request) {
+	from, to, err := parsePeriod(r)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	report, pdfBytes, err := h.service.TemperatureReport(exportContext(r), id, from, to)
+	if err != nil {
+		var appErr *errors.AppError
+		if !errors.As(err, &appErr) {
+			h.logger.Error().Err(err).Str("cabinet_id", id).Msg("failed to generate temperature report")
+			err = errors.Internal("errors.export.pdf_failed")
+		}
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	filename := fmt.Sprintf("kuehlschrankprotokoll-%s-%s_%s.pdf", id, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(pdfBytes)))
+	w.Header().Set("X-Report-SHA256", report.SHA256)
+	w.Header().Set("X-Report-Seal", report.Seal)
+	w.Write(pdfBytes)
+}
+
+// VerifyTemperatureReport checks the digest and seal printed on the fridge
+// log of a cabinet
+// POST /locations/cabinets/{id}/temperature/report/verify?month=2026-01 (or from/to)
+func (h *ColdChainHandler) VerifyTemperatureReport(w http.ResponseWriter, r *http.Request) {
+	from, to, err := parsePeriod(r)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	var input service.VerifySealInput
+	if err := httputil.DecodeJSON(r, &input); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+	if err := httputil.Validate(&input); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	result, err := h.service.VerifyTemperatureReport(exportContext(r), id, from, to, &input)
+	if err != nil {
+		h.logger.Error().Err(err).Str("cabinet_id", id).Msg("failed to verify temperature report")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, result)
+}
+
+// TemperatureReports downloads the fridge logs of all monitored cabinets in one PDF
+// GET /temperature/report?month=2026-01 (or from/to)
+func (h *ColdChainHandler) TemperatureReports(w http.ResponseWriter, r *http.Request) {
+	from, to, err := parsePeriod(r)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	pdfBytes, err := h.service.TemperatureReports(exportContext(r), from, to)
+	if err != nil {
+		var appErr *errors.AppError
+		if !errors.As(err, &appErr) {
+			h.logger.Error().Err(err).Msg("failed to generate temperature reports")
+			err = errors.Internal("errors.export.pdf_failed")
+		}
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	filename := fmt.Sprintf("kuehlschrankprotokolle-%s_%s.pdf", from.Format("2006-01-02"), to.Format("2006-01-02"))
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(pdfBytes)))
+	w.Write(pdfBytes)
+}
+
+// ListExcursions lists excursions
+// GET /temperature/excursions?filter[status]=confirmed&sort=-started_at
+func (h *ColdChainHandler) ListExcursions(w http.ResponseWriter, r *http.Request) {
+	q, err := httputil.ParseListQuery(r)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	page, err := h.service.ListExcursions(database.ReadOnly(r.Context()), q)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to list temperature excursions")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSONWithMeta(w, http.StatusOK, page.Items, httputil.PageMeta(q, page))
+}
+
+// GetExcursion gets an excursion with its quarantined batches
+// GET /temperature/excursions/{id}
+func (h *ColdChainHandler) GetExcursion(w http.ResponseWriter, r *http.Request) {
+	e, err := h.service.GetExcursion(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, e)
+}
+
+// ExcursionDecisionRequest documents the assessment of a confirmed excursion
+type ExcursionDecisionRequest struct {
+	Decision string `json:"decision" validate:"required,oneof=released discarded"`
+	Reason   string `json:"reason" validate:"required,max=2000"`
+}
+
+// DecideExcursion releases or discards the stock quarantined by an excursion
+// POST /temperature/excursions/{id}/decision
+func (h *ColdChainHandler) DecideExcursion(w http.ResponseWriter, r *http.Request) {
+	var req ExcursionDecisionRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+	if err := httputil.Validate(&req); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	e, err := h.service.DecideExcursion(r.Context(), chi.URLParam(r, "id"), req.Decision, req.Reason, r.Header.Get("X-User-ID"))
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to decide temperature excursion")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, e)
+}
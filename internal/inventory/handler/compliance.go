@@ -1,46 +1,46 @@
 package handler
 
 import (
-	"fmt"
-	"io"
 	"net/http"
-	"os"
-	"path/filepath"
-	"strings"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/google/uuid"
 	"github.com/medflow/medflow-backend/internal/inventory/repository"
 	"github.com/medflow/medflow-backend/internal/inventory/service"
+	"github.com/medflow/medflow-backend/pkg/errors"
 	"github.com/medflow/medflow-backend/pkg/httputil"
 	"github.com/medflow/medflow-backend/pkg/logger"
-	"github.com/medflow/medflow-backend/pkg/tenant"
+	"github.com/medflow/medflow-backend/pkg/scan"
+	"github.com/medflow/medflow-backend/pkg/storage"
 )
 
 const (
-	maxUploadSize = 20 << 20 // 20MB
-	uploadBaseDir = "uploads/inventory"
+	// multipartMemory is how much of a multipart form is buffered in memory
+	// before parts spill to temporary files
+	multipartMemory = 8 << 20
+	// multipartOverhead allows for the multipart envelope and form fields
+	multipartOverhead = 1 << 20
 )
 
-var allowedMimeTypes = map[string]bool{
-	"application/pdf": true,
-	"image/jpeg":      true,
-	"image/png":       true,
-	"image/gif":       true,
-	"image/webp":      true,
+// documentFileTypes lists the file types each document type accepts
+var documentFileTypes = map[string][]string{
+	"sdb":         storage.PDFOnly,
+	"manual":      storage.PDFOnly,
+	"certificate": storage.ScannedDocument,
 }
 
 // ComplianceHandler handles compliance endpoints
 type ComplianceHandler struct {
-	service *service.InventoryService
-	logger  *logger.Logger
+	service   *service.InventoryService
+	documents *storage.Documents
+	logger    *logger.Logger
 }
 
 // NewComplianceHandler creates a new compliance handler
-func NewComplianceHandler(svc *service.InventoryService, log *logger.Logger) *ComplianceHandler {
+func NewComplianceHandler(svc *service.InventoryService, documents *storage.Documents, log *logger.Logger) *ComplianceHandler {
 	return &ComplianceHandler{
-		service: svc,
-		logger:  log,
+		service:   svc,
+		documents: documents,
+		logger:    log,
 	}
 }
 
@@ -50,7 +50,7 @@ func (h *ComplianceHandler) GetHazardousDetails(w http.ResponseWriter, r *http.R
 
 	details, err := h.service.GetHazardousDetails(r.Context(), itemID)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -63,13 +63,13 @@ func (h *ComplianceHandler) UpsertHazardousDetails(w http.ResponseWriter, r *htt
 
 	var detail repository.HazardousSubstanceDetail
 	if err := httputil.DecodeJSON(r, &detail); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	detail.ItemID = itemID
 	if err := h.service.UpsertHazardousDetails(r.Context(), &detail); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -81,7 +81,7 @@ func (h *ComplianceHandler) DeleteHazardousDetails(w http.ResponseWriter, r *htt
 	itemID := chi.URLParam(r, "id")
 
 	if err := h.service.DeleteHazardousDetails(r.Context(), itemID); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -94,7 +94,7 @@ func (h *ComplianceHandler) ListDocuments(w http.ResponseWriter, r *http.Request
 
 	docs, err := h.service.ListItemDocuments(r.Context(), itemID)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -105,69 +105,45 @@ func (h *ComplianceHandler) ListDocuments(w http.ResponseWriter, r *http.Request
 func (h *ComplianceHandler) UploadDocument(w http.ResponseWriter, r *http.Request) {
 	itemID := chi.URLParam(r, "id")
 
-	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
-	if err := r.ParseMultipartForm(maxUploadSize); err != nil {
-		http.Error(w, "File too large (max 20MB)", http.StatusBadRequest)
+	// Leave room for the multipart envelope; the file itself is limited by the upload policy
+	r.Body = http.MaxBytesReader(w, r.Body, h.documents.Policy.MaxBytes+multipartOverhead)
+	if err := r.ParseMultipartForm(multipartMemory); err != nil {
+		httputil.ErrorLocalized(w, r, errors.BadRequest("errors.file.too_large", map[string]string{"max": storage.FormatSize(h.documents.Policy.MaxBytes)}))
 		return
 	}
 
 	file, header, err := r.FormFile("file")
 	if err != nil {
-		http.Error(w, "Missing file", http.StatusBadRequest)
+		httputil.ErrorLocalized(w, r, errors.BadRequest("validation.required", map[string]string{"field": "file"}))
 		return
 	}
 	defer file.Close()
 
 	documentType := r.FormValue("document_type")
-	if documentType != "sdb" && documentType != "manual" && documentType != "certificate" {
-		http.Error(w, "Invalid document_type (must be sdb, manual, or certificate)", http.StatusBadRequest)
+	fileTypes, ok := documentFileTypes[documentType]
+	if !ok {
+		httputil.ErrorLocalized(w, r, errors.Validation(nil).WithDetail("document_type", "validation.one_of", map[string]string{
+			"field":  "document_type",
+			"values": "sdb, manual, certificate",
+		}))
 		return
 	}
 
-	// Validate MIME type
-	mimeType := header.Header.Get("Content-Type")
-	if !allowedMimeTypes[mimeType] {
-		http.Error(w, "Unsupported file type (PDF and images only)", http.StatusBadRequest)
-		return
-	}
-
-	// Build storage path
-	tenantID, err := tenant.TenantID(r.Context())
-	if err != nil {
-		httputil.Error(w, err)
-		return
-	}
-
-	fileUUID := uuid.New().String()
-	ext := filepath.Ext(header.Filename)
-	storedName := fileUUID + ext
-	relPath := filepath.Join(tenantID, itemID, storedName)
-	absPath := filepath.Join(uploadBaseDir, relPath)
-
-	// Ensure directory exists
-	if err := os.MkdirAll(filepath.Dir(absPath), 0750); err != nil {
-		h.logger.Error().Err(err).Msg("failed to create upload directory")
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
-	}
-
-	// Write file
-	dst, err := os.Create(absPath)
+	// Content type, size and checksum come from the content, not the client;
+	// the upload is type-checked and scanned before it is stored
+	obj, err := h.documents.Upload(r.Context(), "inventory/documents", itemID, file, fileTypes)
 	if err != nil {
-		h.logger.Error().Err(err).Msg("failed to create file")
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
-	}
-	defer dst.Close()
-
-	if _, err := io.Copy(dst, file); err != nil {
-		h.logger.Error().Err(err).Msg("failed to write file")
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		var appErr *errors.AppError
+		if !errors.As(err, &appErr) {
+			h.logger.Error().Err(err).Str("item_id", itemID).Msg("failed to store document")
+			err = errors.Internal("errors.file.store_failed")
+		}
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	// Save metadata
-	size := int(header.Size)
+	size := int(obj.Size)
 	userID := r.Header.Get("X-User-ID")
 	var uploadedBy *string
 	if userID != "" {
@@ -178,16 +154,18 @@ func (h *ComplianceHandler) UploadDocument(w http.ResponseWriter, r *http.Reques
 		ItemID:        itemID,
 		DocumentType:  documentType,
 		FileName:      header.Filename,
-		FilePath:      relPath,
+		FilePath:      obj.Key,
 		FileSizeBytes: &size,
-		MimeType:      &mimeType,
+		MimeType:      &obj.ContentType,
+		SHA256:        &obj.SHA256,
+		ScanStatus:    obj.ScanStatus,
 		UploadedBy:    uploadedBy,
 	}
 
 	if err := h.service.CreateItemDocument(r.Context(), doc); err != nil {
-		// Clean up file on DB error
-		os.Remove(absPath)
-		httputil.Error(w, err)
+		// Clean up the object on DB error
+		h.documents.Delete(r.Context(), obj.Key)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -198,57 +176,63 @@ func (h *ComplianceHandler) UploadDocument(w http.ResponseWriter, r *http.Reques
 func (h *ComplianceHandler) DeleteDocument(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	// Get doc to find file path
+	// Get doc to find the object key
 	doc, err := h.service.GetItemDocument(r.Context(), id)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	if err := h.service.DeleteItemDocument(r.Context(), id); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
-	// Remove file (best-effort)
-	absPath := filepath.Join(uploadBaseDir, doc.FilePath)
-	os.Remove(absPath)
+	// Remove object (best-effort)
+	if err := h.documents.Delete(r.Context(), doc.FilePath); err != nil {
+		h.logger.Warn().Err(err).Str("key", doc.FilePath).Msg("failed to delete stored document")
+	}
 
 	httputil.NoContent(w)
 }
 
-// DownloadDocument serves a document file
-func (h *ComplianceHandler) DownloadDocument(w http.ResponseWriter, r *http.Request) {
-	id := chi.URLParam(r, "id")
-
-	doc, err := h.service.GetItemDocument(r.Context(), id)
-	if err != nil {
-		httputil.Error(w, err)
+// GetDocumentURL returns a signed, short-lived download URL for a document
+func (h *ComplianceHandler) GetDocumentURL(w http.ResponseWriter, r *http.Request) {
+	link, ok := h.documentURL(w, r)
+	if !ok {
 		return
 	}
+	httputil.JSON(w, http.StatusOK, link)
+}
 
-	absPath := filepath.Join(uploadBaseDir, doc.FilePath)
-
-	// Prevent path traversal
-	absBase, _ := filepath.Abs(uploadBaseDir)
-	absPath, err = filepath.Abs(absPath)
-	if err != nil || !strings.HasPrefix(absPath, absBase) {
-		http.Error(w, "Invalid path", http.StatusBadRequest)
+// DownloadDocument redirects to a signed, short-lived download URL
+func (h *ComplianceHandler) DownloadDocument(w http.ResponseWriter, r *http.Request) {
+	link, ok := h.documentURL(w, r)
+	if !ok {
 		return
 	}
+	w.Header().Set("Cache-Control", "no-store")
+	http.Redirect(w, r, link.URL, http.StatusFound)
+}
 
-	f, err := os.Open(absPath)
+// documentURL signs a download URL for the document in the path.
+// Documents that are not scanned clean are never served.
+func (h *ComplianceHandler) documentURL(w http.ResponseWriter, r *http.Request) (*storage.DownloadURL, bool) {
+	doc, err := h.service.GetItemDocument(r.Context(), chi.URLParam(r, "id"))
 	if err != nil {
-		h.logger.Error().Err(err).Str("path", absPath).Msg("file not found on disk")
-		http.Error(w, "File not found", http.StatusNotFound)
-		return
+		httputil.ErrorLocalized(w, r, err)
+		return nil, false
 	}
-	defer f.Close()
-
-	if doc.MimeType != nil {
-		w.Header().Set("Content-Type", *doc.MimeType)
+	if err := scan.CheckDownload(doc.ScanStatus); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return nil, false
 	}
-	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", doc.FileName))
 
-	io.Copy(w, f)
+	link, err := h.documents.DownloadURL(r.Context(), doc.FilePath, doc.FileName)
+	if err != nil {
+		h.logger.Error().Err(err).Str("key", doc.FilePath).Msg("failed to sign document URL")
+		httputil.ErrorLocalized(w, r, errors.Internal("errors.file.read_failed"))
+		return nil, false
+	}
+	return link, true
 }
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/medflow/medflow-backend/internal/inventory/service"
+	"github.com/medflow/medflow-backend/pkg/database"
 	"github.com/medflow/medflow-backend/pkg/httputil"
 	"github.com/medflow/medflow-backend/pkg/logger"
 )
@@ -24,9 +25,9 @@ func NewDashboardHandler(svc *service.InventoryService, log *logger.Logger) *Das
 
 // GetStats returns dashboard statistics
 func (h *DashboardHandler) GetStats(w http.ResponseWriter, r *http.Request) {
-	stats, err := h.service.GetDashboardStats(r.Context())
+	stats, err := h.service.GetDashboardStats(database.ReadOnly(r.Context()))
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
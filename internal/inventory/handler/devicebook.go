@@ -32,7 +32,7 @@ func (h *DeviceBookHandler) ListInspections(w http.ResponseWriter, r *http.Reque
 
 	inspections, err := h.service.ListInspections(r.Context(), itemID)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -45,14 +45,14 @@ func (h *DeviceBookHandler) CreateInspection(w http.ResponseWriter, r *http.Requ
 
 	var insp repository.DeviceInspection
 	if err := httputil.DecodeJSON(r, &insp); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	insp.ItemID = itemID
 	if err := h.service.CreateInspection(r.Context(), &insp); err != nil {
 		h.logger.Error().Err(err).Str("item_id", itemID).Msg("failed to create inspection")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -65,13 +65,13 @@ func (h *DeviceBookHandler) UpdateInspection(w http.ResponseWriter, r *http.Requ
 
 	var insp repository.DeviceInspection
 	if err := httputil.DecodeJSON(r, &insp); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	insp.ID = inspID
 	if err := h.service.UpdateInspection(r.Context(), &insp); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -83,7 +83,7 @@ func (h *DeviceBookHandler) DeleteInspection(w http.ResponseWriter, r *http.Requ
 	inspID := chi.URLParam(r, "inspId")
 
 	if err := h.service.DeleteInspection(r.Context(), inspID); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -98,7 +98,7 @@ func (h *DeviceBookHandler) ListTrainings(w http.ResponseWriter, r *http.Request
 
 	trainings, err := h.service.ListTrainings(r.Context(), itemID)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -111,14 +111,14 @@ func (h *DeviceBookHandler) CreateTraining(w http.ResponseWriter, r *http.Reques
 
 	var tr repository.DeviceTraining
 	if err := httputil.DecodeJSON(r, &tr); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	tr.ItemID = itemID
 	if err := h.service.CreateTraining(r.Context(), &tr); err != nil {
 		h.logger.Error().Err(err).Str("item_id", itemID).Msg("failed to create training")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -131,13 +131,13 @@ func (h *DeviceBookHandler) UpdateTraining(w http.ResponseWriter, r *http.Reques
 
 	var tr repository.DeviceTraining
 	if err := httputil.DecodeJSON(r, &tr); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	tr.ID = trID
 	if err := h.service.UpdateTraining(r.Context(), &tr); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -149,7 +149,7 @@ func (h *DeviceBookHandler) DeleteTraining(w http.ResponseWriter, r *http.Reques
 	trID := chi.URLParam(r, "trId")
 
 	if err := h.service.DeleteTraining(r.Context(), trID); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -164,7 +164,7 @@ func (h *DeviceBookHandler) ListIncidents(w http.ResponseWriter, r *http.Request
 
 	incidents, err := h.service.ListIncidents(r.Context(), itemID)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -177,14 +177,14 @@ func (h *DeviceBookHandler) CreateIncident(w http.ResponseWriter, r *http.Reques
 
 	var inc repository.DeviceIncident
 	if err := httputil.DecodeJSON(r, &inc); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	inc.ItemID = itemID
 	if err := h.service.CreateIncident(r.Context(), &inc); err != nil {
 		h.logger.Error().Err(err).Str("item_id", itemID).Msg("failed to create incident")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -197,13 +197,13 @@ func (h *DeviceBookHandler) UpdateIncident(w http.ResponseWriter, r *http.Reques
 
 	var inc repository.DeviceIncident
 	if err := httputil.DecodeJSON(r, &inc); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	inc.ID = incID
 	if err := h.service.UpdateIncident(r.Context(), &inc); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -215,7 +215,7 @@ func (h *DeviceBookHandler) DeleteIncident(w http.ResponseWriter, r *http.Reques
 	incID := chi.URLParam(r, "incId")
 
 	if err := h.service.DeleteIncident(r.Context(), incID); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -1,14 +1,27 @@
 package handler
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/medflow/medflow-backend/internal/inventory/service"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/httputil"
 	"github.com/medflow/medflow-backend/pkg/logger"
 )
 
+// exportStatementTimeout bounds the queries of heavy export endpoints,
+// which run on a read replica when one is configured
+const exportStatementTimeout = 2 * time.Minute
+
+// exportContext marks an export request as read-only work with the export timeout
+func exportContext(r *http.Request) context.Context {
+	return database.WithStatementTimeout(database.ReadOnly(r.Context()), exportStatementTimeout)
+}
+
 // ExportHandler handles PDF export endpoints
 type ExportHandler struct {
 	service *service.InventoryService
@@ -25,10 +38,10 @@ func NewExportHandler(svc *service.InventoryService, log *logger.Logger) *Export
 
 // ExportInventoryRegister generates and serves the inventory register PDF
 func (h *ExportHandler) ExportInventoryRegister(w http.ResponseWriter, r *http.Request) {
-	pdfBytes, err := h.service.ExportInventoryRegister(r.Context())
+	pdfBytes, err := h.service.ExportInventoryRegister(exportContext(r))
 	if err != nil {
 		h.logger.Error().Err(err).Msg("failed to generate inventory register PDF")
-		http.Error(w, "Failed to generate PDF", http.StatusInternalServerError)
+		httputil.ErrorLocalized(w, r, errors.Internal("errors.export.pdf_failed"))
 		return
 	}
 
@@ -41,10 +54,10 @@ func (h *ExportHandler) ExportInventoryRegister(w http.ResponseWriter, r *http.R
 
 // ExportBestandsverzeichnis generates and serves the Bestandsverzeichnis PDF (MPBetreibV §14)
 func (h *ExportHandler) ExportBestandsverzeichnis(w http.ResponseWriter, r *http.Request) {
-	pdfBytes, err := h.service.ExportBestandsverzeichnis(r.Context())
+	pdfBytes, err := h.service.ExportBestandsverzeichnis(exportContext(r))
 	if err != nil {
 		h.logger.Error().Err(err).Msg("failed to generate Bestandsverzeichnis PDF")
-		http.Error(w, "Failed to generate PDF", http.StatusInternalServerError)
+		httputil.ErrorLocalized(w, r, errors.Internal("errors.export.pdf_failed"))
 		return
 	}
 
@@ -57,10 +70,10 @@ func (h *ExportHandler) ExportBestandsverzeichnis(w http.ResponseWriter, r *http
 
 // ExportGefahrstoffverzeichnis generates and serves the hazardous substance register PDF
 func (h *ExportHandler) ExportGefahrstoffverzeichnis(w http.ResponseWriter, r *http.Request) {
-	pdfBytes, err := h.service.ExportGefahrstoffverzeichnis(r.Context())
+	pdfBytes, err := h.service.ExportGefahrstoffverzeichnis(exportContext(r))
 	if err != nil {
 		h.logger.Error().Err(err).Msg("failed to generate Gefahrstoffverzeichnis PDF")
-		http.Error(w, "Failed to generate PDF", http.StatusInternalServerError)
+		httputil.ErrorLocalized(w, r, errors.Internal("errors.export.pdf_failed"))
 		return
 	}
 
@@ -4,7 +4,9 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/medflow/medflow-backend/internal/inventory/repository"
 	"github.com/medflow/medflow-backend/internal/inventory/service"
+	"github.com/medflow/medflow-backend/pkg/database"
 	"github.com/medflow/medflow-backend/pkg/httputil"
 	"github.com/medflow/medflow-backend/pkg/logger"
 )
@@ -61,7 +63,7 @@ func NewDataPortabilityHandler(
 // ExportDataPortability exports all inventory data for DSGVO data portability (Art. 20 DSGVO)
 // GET /export/data-portability
 func (h *DataPortabilityHandler) ExportDataPortability(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+	ctx := exportContext(r)
 
 	export := DataPortabilityExport{
 		ExportedAt: time.Now(),
@@ -147,11 +149,21 @@ func (h *DataPortabilityHandler) ExportDataPortability(w http.ResponseWriter, r
 			export.RadiationCertifications = certifications
 		}
 
-		dosimetry, _, err := h.radiationService.ListAllDosimetry(ctx, 1, 1000)
-		if err != nil {
-			h.logger.Error().Err(err).Msg("data portability export: failed to get dosimetry records")
-		} else {
-			export.DosimetryRecords = dosimetry
+		// Follow the cursor so the export is complete regardless of size
+		var dosimetry []*repository.DosimetryRecord
+		q := &database.ListQuery{Limit: database.MaxListLimit, SkipCount: true}
+		for {
+			page, err := h.radiationService.ListAllDosimetry(ctx, q)
+			if err != nil {
+				h.logger.Error().Err(err).Msg("data portability export: failed to get dosimetry records")
+				break
+			}
+			dosimetry = append(dosimetry, page.Items...)
+			if page.NextCursor == "" {
+				export.DosimetryRecords = dosimetry
+				break
+			}
+			q.Cursor = page.NextCursor
 		}
 	}
 
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/medflow/medflow-backend/internal/inventory/service"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/httputil"
+	"github.com/medflow/medflow-backend/pkg/logger"
+)
+
+// ForecastHandler handles consumption history and demand forecast endpoints
+type ForecastHandler struct {
+	service *service.ForecastService
+	logger  *logger.Logger
+}
+
+// NewForecastHandler creates a new forecast handler
+func NewForecastHandler(svc *service.ForecastService, log *logger.Logger) *ForecastHandler {
+	return &ForecastHandler{
+		service: svc,
+		logger:  log,
+	}
+}
+
+// Consumption returns the daily consumption of an item
+// GET /items/{id}/consumption?days=90
+func (h *ForecastHandler) Consumption(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	days := service.DefaultForecastHistoryDays
+	if v := r.URL.Query().Get("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			httputil.ErrorLocalized(w, r, errors.Validation(nil).WithDetail("days", "validation.integer", nil))
+			return
+		}
+		days = n
+	}
+
+	history, err := h.service.ConsumptionHistory(database.ReadOnly(r.Context()), id, days)
+	if err != nil {
+		h.logger.Error().Err(err).Str("item_id", id).Msg("failed to get consumption history")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, history)
+}
+
+// Forecast forecasts the demand of an item without storing it
+// GET /items/{id}/forecast?service_level=0.98&coverage_days=30&history_days=180
+func (h *ForecastHandler) Forecast(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	input, err := parseForecastQuery(r)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	f, err := h.service.Forecast(database.ReadOnly(r.Context()), id, input)
+	if err != nil {
+		h.logger.Error().Err(err).Str("item_id", id).Msg("failed to forecast item")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, f)
+}
+
+// parseForecastQuery reads forecast settings from query parameters
+func parseForecastQuery(r *http.Request) (*service.ForecastInput, error) {
+	var input service.ForecastInput
+	query := r.URL.Query()
+
+	if v := query.Get("service_level"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, errors.Validation(nil).WithDetail("service_level", "validation.number", nil)
+		}
+		input.ServiceLevel = &f
+	}
+	for field, dst := range map[string]**int{"coverage_days": &input.CoverageDays, "history_days": &input.HistoryDays} {
+		if v := query.Get(field); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, errors.Validation(nil).WithDetail(field, "validation.integer", nil)
+			}
+			*dst = &n
+		}
+	}
+
+	if err := httputil.Validate(&input); err != nil {
+		return nil, err
+	}
+	return &input, nil
+}
+
+// List lists the stored forecasts
+// GET /forecasts?filter[projected_stockout_date][lte]=2026-12-31&sort=projected_stockout_date
+func (h *ForecastHandler) List(w http.ResponseWriter, r *http.Request) {
+	q, err := httputil.ParseListQuery(r)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	page, err := h.service.List(database.ReadOnly(r.Context()), q)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to list forecasts")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSONWithMeta(w, http.StatusOK, page.Items, httputil.PageMeta(q, page))
+}
+
+// Recalculate forecasts and stores the demand of every active item
+// POST /forecasts/recalculate
+func (h *ForecastHandler) Recalculate(w http.ResponseWriter, r *http.Request) {
+	var input service.ForecastInput
+	if r.ContentLength != 0 {
+		if err := httputil.DecodeJSON(r, &input); err != nil {
+			httputil.ErrorLocalized(w, r, err)
+			return
+		}
+		if err := httputil.Validate(&input); err != nil {
+			httputil.ErrorLocalized(w, r, err)
+			return
+		}
+	}
+
+	result, err := h.service.Recalculate(r.Context(), &input)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to recalculate forecasts")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, result)
+}
+
+// Accept sets the reorder point and quantity of items to their suggested values
+// POST /forecasts/accept
+func (h *ForecastHandler) Accept(w http.ResponseWriter, r *http.Request) {
+	var input service.AcceptInput
+	if err := httputil.DecodeJSON(r, &input); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+	if err := httputil.Validate(&input); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	accepted, err := h.service.Accept(r.Context(), &input, r.Header.Get("X-User-ID"))
+	if err != nil {
+		h.logger.Error().Err(err).Int("items", len(input.ItemIDs)).Msg("failed to accept forecasts")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, accepted)
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/medflow/medflow-backend/internal/inventory/service"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/httputil"
+	"github.com/medflow/medflow-backend/pkg/logger"
+)
+
+// GDPdUExportHandler handles GDPdU data export endpoints
+type GDPdUExportHandler struct {
+	service *service.GDPdUExportService
+	logger  *logger.Logger
+}
+
+// NewGDPdUExportHandler creates a new GDPdU export handler
+func NewGDPdUExportHandler(svc *service.GDPdUExportService, log *logger.Logger) *GDPdUExportHandler {
+	return &GDPdUExportHandler{
+		service: svc,
+		logger:  log,
+	}
+}
+
+// Request queues an export of a period; poll the export until it completes
+// POST /export/gdpdu
+func (h *GDPdUExportHandler) Request(w http.ResponseWriter, r *http.Request) {
+	var input service.RequestGDPdUExportInput
+	if err := httputil.DecodeJSON(r, &input); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+	if err := httputil.Validate(&input); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	e, err := h.service.Request(r.Context(), &input, r.Header.Get("X-User-ID"), r.Header.Get("X-User-Email"))
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to request GDPdU export")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusAccepted, e)
+}
+
+// List lists exports
+// GET /export/gdpdu?filter[status]=completed&sort=-created_at
+func (h *GDPdUExportHandler) List(w http.ResponseWriter, r *http.Request) {
+	q, err := httputil.ParseListQuery(r)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	page, err := h.service.List(database.ReadOnly(r.Context()), q)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to list GDPdU exports")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSONWithMeta(w, http.StatusOK, page.Items, httputil.PageMeta(q, page))
+}
+
+// Get gets an export with its status and, once built, its manifest
+// GET /export/gdpdu/{id}
+func (h *GDPdUExportHandler) Get(w http.ResponseWriter, r *http.Request) {
+	e, err := h.service.Get(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, e)
+}
+
+// Download redirects to a signed, short-lived download URL of the ZIP
+// GET /export/gdpdu/{id}/download
+func (h *GDPdUExportHandler) Download(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	link, err := h.service.DownloadURL(r.Context(), id)
+	if err != nil {
+		h.logger.Error().Err(err).Str("export_id", id).Msg("failed to sign GDPdU export URL")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	http.Redirect(w, r, link.URL, http.StatusFound)
+}
@@ -32,13 +32,13 @@ func NewHygieneHandler(svc *service.HygieneService, log *logger.Logger) *Hygiene
 func (h *HygieneHandler) CreatePlan(w http.ResponseWriter, r *http.Request) {
 	var plan repository.HygienePlan
 	if err := httputil.DecodeJSON(r, &plan); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	if err := h.service.CreatePlan(r.Context(), &plan); err != nil {
 		h.logger.Error().Err(err).Str("title", plan.Title).Msg("failed to create hygiene plan")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -63,7 +63,7 @@ func (h *HygieneHandler) ListPlans(w http.ResponseWriter, r *http.Request) {
 	plans, total, err := h.service.ListPlans(r.Context(), status, category, page, perPage)
 	if err != nil {
 		h.logger.Error().Err(err).Msg("failed to list hygiene plans")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -87,7 +87,7 @@ func (h *HygieneHandler) GetPlan(w http.ResponseWriter, r *http.Request) {
 
 	plan, err := h.service.GetPlan(r.Context(), id)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -101,14 +101,14 @@ func (h *HygieneHandler) UpdatePlan(w http.ResponseWriter, r *http.Request) {
 
 	var plan repository.HygienePlan
 	if err := httputil.DecodeJSON(r, &plan); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	plan.ID = id
 	if err := h.service.UpdatePlan(r.Context(), &plan); err != nil {
 		h.logger.Error().Err(err).Str("id", id).Msg("failed to update hygiene plan")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -122,7 +122,7 @@ func (h *HygieneHandler) DeletePlan(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.service.DeletePlan(r.Context(), id); err != nil {
 		h.logger.Error().Err(err).Str("id", id).Msg("failed to delete hygiene plan")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -136,13 +136,13 @@ func (h *HygieneHandler) DeletePlan(w http.ResponseWriter, r *http.Request) {
 func (h *HygieneHandler) CreateInspection(w http.ResponseWriter, r *http.Request) {
 	var inspection repository.HygieneInspection
 	if err := httputil.DecodeJSON(r, &inspection); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	if err := h.service.CreateInspection(r.Context(), &inspection); err != nil {
 		h.logger.Error().Err(err).Msg("failed to create hygiene inspection")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -166,7 +166,7 @@ func (h *HygieneHandler) ListInspections(w http.ResponseWriter, r *http.Request)
 	inspections, total, err := h.service.ListInspections(r.Context(), planID, page, perPage)
 	if err != nil {
 		h.logger.Error().Err(err).Msg("failed to list hygiene inspections")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -190,7 +190,7 @@ func (h *HygieneHandler) GetInspection(w http.ResponseWriter, r *http.Request) {
 
 	inspection, err := h.service.GetInspection(r.Context(), id)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -204,14 +204,14 @@ func (h *HygieneHandler) UpdateInspection(w http.ResponseWriter, r *http.Request
 
 	var inspection repository.HygieneInspection
 	if err := httputil.DecodeJSON(r, &inspection); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	inspection.ID = id
 	if err := h.service.UpdateInspection(r.Context(), &inspection); err != nil {
 		h.logger.Error().Err(err).Str("id", id).Msg("failed to update hygiene inspection")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -225,7 +225,7 @@ func (h *HygieneHandler) DeleteInspection(w http.ResponseWriter, r *http.Request
 
 	if err := h.service.DeleteInspection(r.Context(), id); err != nil {
 		h.logger.Error().Err(err).Str("id", id).Msg("failed to delete hygiene inspection")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
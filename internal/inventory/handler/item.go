@@ -2,11 +2,11 @@ package handler
 
 import (
 	"net/http"
-	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/medflow/medflow-backend/internal/inventory/repository"
 	"github.com/medflow/medflow-backend/internal/inventory/service"
+	"github.com/medflow/medflow-backend/pkg/database"
 	"github.com/medflow/medflow-backend/pkg/httputil"
 	"github.com/medflow/medflow-backend/pkg/logger"
 )
@@ -26,36 +26,23 @@ func NewItemHandler(svc *service.InventoryService, log *logger.Logger) *ItemHand
 }
 
 // List lists inventory items
+// GET /items?filter[category]=Supplies&sort=-expiry_date&cursor=...
 func (h *ItemHandler) List(w http.ResponseWriter, r *http.Request) {
-	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
-	if page < 1 {
-		page = 1
-	}
-
-	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
-	if perPage < 1 || perPage > 100 {
-		perPage = 20
-	}
-
-	category := r.URL.Query().Get("category")
-
-	items, total, err := h.service.ListItems(r.Context(), page, perPage, category)
+	q, err := httputil.ParseListQuery(r)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
+	// Legacy ?category= parameter
+	q.Where("category", r.URL.Query().Get("category"))
 
-	totalPages := int(total) / perPage
-	if int(total)%perPage > 0 {
-		totalPages++
+	page, err := h.service.ListItems(database.ReadOnly(r.Context()), q)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
 	}
 
-	httputil.JSONWithMeta(w, http.StatusOK, items, &httputil.Meta{
-		Page:       page,
-		PerPage:    perPage,
-		Total:      total,
-		TotalPages: totalPages,
-	})
+	httputil.JSONWithMeta(w, http.StatusOK, page.Items, httputil.PageMeta(q, page))
 }
 
 // Get gets an item by ID
@@ -64,7 +51,7 @@ func (h *ItemHandler) Get(w http.ResponseWriter, r *http.Request) {
 
 	item, err := h.service.GetItem(r.Context(), id)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -75,13 +62,13 @@ func (h *ItemHandler) Get(w http.ResponseWriter, r *http.Request) {
 func (h *ItemHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var item repository.InventoryItem
 	if err := httputil.DecodeJSON(r, &item); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	item.IsActive = true
 	if err := h.service.CreateItem(r.Context(), &item); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -94,13 +81,13 @@ func (h *ItemHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 	var item repository.InventoryItem
 	if err := httputil.DecodeJSON(r, &item); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	item.ID = id
 	if err := h.service.UpdateItem(r.Context(), &item); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -112,7 +99,7 @@ func (h *ItemHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	if err := h.service.DeleteItem(r.Context(), id); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
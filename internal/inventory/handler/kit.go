@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/medflow/medflow-backend/internal/inventory/service"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/httputil"
+	"github.com/medflow/medflow-backend/pkg/logger"
+)
+
+// KitHandler handles procedure kit (Behandlungsset) endpoints
+type KitHandler struct {
+	service *service.KitService
+	logger  *logger.Logger
+}
+
+// NewKitHandler creates a new kit handler
+func NewKitHandler(svc *service.KitService, log *logger.Logger) *KitHandler {
+	return &KitHandler{
+		service: svc,
+		logger:  log,
+	}
+}
+
+// decodeKit decodes and validates a kit request body
+func decodeKit(r *http.Request) (*service.KitInput, error) {
+	var input service.KitInput
+	if err := httputil.DecodeJSON(r, &input); err != nil {
+		return nil, err
+	}
+	if err := httputil.Validate(&input); err != nil {
+		return nil, err
+	}
+	return &input, nil
+}
+
+// Create creates a kit
+// POST /kits
+func (h *KitHandler) Create(w http.ResponseWriter, r *http.Request) {
+	input, err := decodeKit(r)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	kit, err := h.service.Create(r.Context(), input, r.Header.Get("X-User-ID"))
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to create kit")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.Created(w, kit)
+}
+
+// List lists kits
+// GET /kits?filter[is_active]=true&sort=name
+func (h *KitHandler) List(w http.ResponseWriter, r *http.Request) {
+	q, err := httputil.ParseListQuery(r)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	page, err := h.service.List(database.ReadOnly(r.Context()), q)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to list kits")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSONWithMeta(w, http.StatusOK, page.Items, httputil.PageMeta(q, page))
+}
+
+// Get gets a kit with its items
+// GET /kits/{id}
+func (h *KitHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	kit, err := h.service.Get(r.Context(), id)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, kit)
+}
+
+// Update replaces a kit and its items
+// PUT /kits/{id}
+func (h *KitHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	input, err := decodeKit(r)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	kit, err := h.service.Update(r.Context(), id, input)
+	if err != nil {
+		h.logger.Error().Err(err).Str("id", id).Msg("failed to update kit")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, kit)
+}
+
+// Delete deletes a kit
+// DELETE /kits/{id}
+func (h *KitHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		h.logger.Error().Err(err).Str("id", id).Msg("failed to delete kit")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.NoContent(w)
+}
+
+// PickList suggests the batches to take a kit's items from (FEFO)
+// GET /kits/{id}/pick-list?count=2
+func (h *KitHandler) PickList(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	count := 1
+	if v := r.URL.Query().Get("count"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			httputil.ErrorLocalized(w, r, errors.Validation(nil).WithDetail("count", "validation.integer", nil))
+			return
+		}
+		count = n
+	}
+
+	list, err := h.service.PickList(database.ReadOnly(r.Context()), id, count)
+	if err != nil {
+		h.logger.Error().Err(err).Str("kit_id", id).Msg("failed to build kit pick list")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, list)
+}
+
+// Consume books out every item of a kit; nothing is booked if one is short
+// POST /kits/{id}/consume
+func (h *KitHandler) Consume(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var input service.ConsumeKitInput
+	if r.ContentLength != 0 {
+		if err := httputil.DecodeJSON(r, &input); err != nil {
+			httputil.ErrorLocalized(w, r, err)
+			return
+		}
+		if err := httputil.Validate(&input); err != nil {
+			httputil.ErrorLocalized(w, r, err)
+			return
+		}
+	}
+
+	consumption, err := h.service.Consume(r.Context(), id, &input, r.Header.Get("X-User-ID"), r.Header.Get("X-User-Email"))
+	if err != nil {
+		h.logger.Error().Err(err).Str("kit_id", id).Msg("failed to consume kit")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.Created(w, consumption)
+}
+
+// ListConsumptions lists kit consumptions
+// GET /kit-consumptions?filter[reference_id]=...
+func (h *KitHandler) ListConsumptions(w http.ResponseWriter, r *http.Request) {
+	q, err := httputil.ParseListQuery(r)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	page, err := h.service.ListConsumptions(database.ReadOnly(r.Context()), q)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to list kit consumptions")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSONWithMeta(w, http.StatusOK, page.Items, httputil.PageMeta(q, page))
+}
+
+// GetConsumption gets a kit consumption with the batches it booked out
+// GET /kit-consumptions/{id}
+func (h *KitHandler) GetConsumption(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	consumption, err := h.service.GetConsumption(r.Context(), id)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, consumption)
+}
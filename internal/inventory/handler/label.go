@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/medflow/medflow-backend/internal/inventory/service"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/httputil"
+	"github.com/medflow/medflow-backend/pkg/logger"
+)
+
+// LabelHandler handles label template and label printing endpoints
+type LabelHandler struct {
+	service *service.LabelService
+	logger  *logger.Logger
+}
+
+// NewLabelHandler creates a new label handler
+func NewLabelHandler(svc *service.LabelService, log *logger.Logger) *LabelHandler {
+	return &LabelHandler{
+		service: svc,
+		logger:  log,
+	}
+}
+
+// decodeLabelTemplate decodes and validates a label template request body
+func decodeLabelTemplate(r *http.Request) (*service.LabelTemplateInput, error) {
+	var input service.LabelTemplateInput
+	if err := httputil.DecodeJSON(r, &input); err != nil {
+		return nil, err
+	}
+	if err := httputil.Validate(&input); err != nil {
+		return nil, err
+	}
+	return &input, nil
+}
+
+// CreateTemplate creates a label template
+// POST /label-templates
+func (h *LabelHandler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	input, err := decodeLabelTemplate(r)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	t, err := h.service.CreateTemplate(r.Context(), input, r.Header.Get("X-User-ID"))
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to create label template")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.Created(w, t)
+}
+
+// ListTemplates lists label templates
+// GET /label-templates?filter[kind]=batch
+func (h *LabelHandler) ListTemplates(w http.ResponseWriter, r *http.Request) {
+	q, err := httputil.ParseListQuery(r)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	page, err := h.service.ListTemplates(database.ReadOnly(r.Context()), q)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to list label templates")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSONWithMeta(w, http.StatusOK, page.Items, httputil.PageMeta(q, page))
+}
+
+// GetTemplate gets a label template
+// GET /label-templates/{id}
+func (h *LabelHandler) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	t, err := h.service.GetTemplate(r.Context(), id)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, t)
+}
+
+// UpdateTemplate replaces a label template
+// PUT /label-templates/{id}
+func (h *LabelHandler) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	input, err := decodeLabelTemplate(r)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	t, err := h.service.UpdateTemplate(r.Context(), id, input)
+	if err != nil {
+		h.logger.Error().Err(err).Str("id", id).Msg("failed to update label template")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, t)
+}
+
+// DeleteTemplate deletes a label template
+// DELETE /label-templates/{id}
+func (h *LabelHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if err := h.service.DeleteTemplate(r.Context(), id); err != nil {
+		h.logger.Error().Err(err).Str("id", id).Msg("failed to delete label template")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.NoContent(w)
+}
+
+// Print renders labels for batches, shelves or devices as ZPL or PDF
+// POST /labels
+func (h *LabelHandler) Print(w http.ResponseWriter, r *http.Request) {
+	var input service.PrintLabelsInput
+	if err := httputil.DecodeJSON(r, &input); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+	if err := httputil.Validate(&input); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	doc, err := h.service.Print(r.Context(), &input)
+	if err != nil {
+		h.logger.Error().Err(err).Str("kind", input.Kind).Msg("failed to print labels")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	writeLabels(w, doc, fmt.Sprintf("etiketten-%s-%s", input.Kind, time.Now().Format("20060102-150405")))
+}
+
+// PrintReceipt renders the batch labels of a goods receipt
+// POST /purchase-orders/{id}/receipts/{receiptId}/labels
+func (h *LabelHandler) PrintReceipt(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	receiptID := chi.URLParam(r, "receiptId")
+
+	var input service.ReceiptLabelsInput
+	if err := httputil.DecodeJSON(r, &input); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+	if err := httputil.Validate(&input); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	doc, err := h.service.PrintReceipt(r.Context(), id, receiptID, &input)
+	if err != nil {
+		h.logger.Error().Err(err).Str("id", id).Str("receipt_id", receiptID).Msg("failed to print receipt labels")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	writeLabels(w, doc, "etiketten-wareneingang-"+receiptID)
+}
+
+// writeLabels sends rendered labels as a download
+func writeLabels(w http.ResponseWriter, doc *service.LabelDocument, name string) {
+	filename := fmt.Sprintf("%s.%s", name, doc.Format)
+	w.Header().Set("Content-Type", doc.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(doc.Data)))
+	w.Header().Set("X-Label-Count", fmt.Sprintf("%d", doc.Labels))
+	w.Write(doc.Data)
+}
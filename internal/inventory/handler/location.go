@@ -27,7 +27,7 @@ func NewLocationHandler(repo *repository.LocationRepository, log *logger.Logger)
 func (h *LocationHandler) GetTree(w http.ResponseWriter, r *http.Request) {
 	tree, err := h.repo.GetTree(r.Context())
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 	httputil.JSON(w, http.StatusOK, tree)
@@ -38,7 +38,7 @@ func (h *LocationHandler) GetTree(w http.ResponseWriter, r *http.Request) {
 func (h *LocationHandler) ListRooms(w http.ResponseWriter, r *http.Request) {
 	rooms, err := h.repo.ListRooms(r.Context())
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 	httputil.JSON(w, http.StatusOK, rooms)
@@ -48,7 +48,7 @@ func (h *LocationHandler) GetRoom(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	room, err := h.repo.GetRoom(r.Context(), id)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 	httputil.JSON(w, http.StatusOK, room)
@@ -57,12 +57,12 @@ func (h *LocationHandler) GetRoom(w http.ResponseWriter, r *http.Request) {
 func (h *LocationHandler) CreateRoom(w http.ResponseWriter, r *http.Request) {
 	var room repository.StorageRoom
 	if err := httputil.DecodeJSON(r, &room); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	if err := h.repo.CreateRoom(r.Context(), &room); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -74,13 +74,13 @@ func (h *LocationHandler) UpdateRoom(w http.ResponseWriter, r *http.Request) {
 
 	var room repository.StorageRoom
 	if err := httputil.DecodeJSON(r, &room); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	room.ID = id
 	if err := h.repo.UpdateRoom(r.Context(), &room); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -90,7 +90,7 @@ func (h *LocationHandler) UpdateRoom(w http.ResponseWriter, r *http.Request) {
 func (h *LocationHandler) DeleteRoom(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	if err := h.repo.DeleteRoom(r.Context(), id); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 	httputil.NoContent(w)
@@ -110,7 +110,7 @@ func (h *LocationHandler) ListCabinets(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 	httputil.JSON(w, http.StatusOK, cabinets)
@@ -120,7 +120,7 @@ func (h *LocationHandler) GetCabinet(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	cabinet, err := h.repo.GetCabinet(r.Context(), id)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 	httputil.JSON(w, http.StatusOK, cabinet)
@@ -129,12 +129,12 @@ func (h *LocationHandler) GetCabinet(w http.ResponseWriter, r *http.Request) {
 func (h *LocationHandler) CreateCabinet(w http.ResponseWriter, r *http.Request) {
 	var cabinet repository.StorageCabinet
 	if err := httputil.DecodeJSON(r, &cabinet); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	if err := h.repo.CreateCabinet(r.Context(), &cabinet); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -146,13 +146,13 @@ func (h *LocationHandler) UpdateCabinet(w http.ResponseWriter, r *http.Request)
 
 	var cabinet repository.StorageCabinet
 	if err := httputil.DecodeJSON(r, &cabinet); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	cabinet.ID = id
 	if err := h.repo.UpdateCabinet(r.Context(), &cabinet); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -162,7 +162,7 @@ func (h *LocationHandler) UpdateCabinet(w http.ResponseWriter, r *http.Request)
 func (h *LocationHandler) DeleteCabinet(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	if err := h.repo.DeleteCabinet(r.Context(), id); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 	httputil.NoContent(w)
@@ -182,7 +182,7 @@ func (h *LocationHandler) ListShelves(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 	httputil.JSON(w, http.StatusOK, shelves)
@@ -192,7 +192,7 @@ func (h *LocationHandler) GetShelf(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	shelf, err := h.repo.GetShelf(r.Context(), id)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 	httputil.JSON(w, http.StatusOK, shelf)
@@ -201,12 +201,12 @@ func (h *LocationHandler) GetShelf(w http.ResponseWriter, r *http.Request) {
 func (h *LocationHandler) CreateShelf(w http.ResponseWriter, r *http.Request) {
 	var shelf repository.StorageShelf
 	if err := httputil.DecodeJSON(r, &shelf); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	if err := h.repo.CreateShelf(r.Context(), &shelf); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -218,13 +218,13 @@ func (h *LocationHandler) UpdateShelf(w http.ResponseWriter, r *http.Request) {
 
 	var shelf repository.StorageShelf
 	if err := httputil.DecodeJSON(r, &shelf); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	shelf.ID = id
 	if err := h.repo.UpdateShelf(r.Context(), &shelf); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -234,7 +234,7 @@ func (h *LocationHandler) UpdateShelf(w http.ResponseWriter, r *http.Request) {
 func (h *LocationHandler) DeleteShelf(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	if err := h.repo.DeleteShelf(r.Context(), id); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 	httputil.NoContent(w)
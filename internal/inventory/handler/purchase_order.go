@@ -0,0 +1,465 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/internal/inventory/service"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/httputil"
+	"github.com/medflow/medflow-backend/pkg/logger"
+)
+
+// PurchaseOrderHandler handles supplier, purchase order and goods receipt endpoints
+type PurchaseOrderHandler struct {
+	service *service.PurchaseOrderService
+	logger  *logger.Logger
+}
+
+// NewPurchaseOrderHandler creates a new purchase order handler
+func NewPurchaseOrderHandler(svc *service.PurchaseOrderService, log *logger.Logger) *PurchaseOrderHandler {
+	return &PurchaseOrderHandler{
+		service: svc,
+		logger:  log,
+	}
+}
+
+// --- Supplier Handlers ---
+
+// supplierRequest is the body of POST and PUT /suppliers
+type supplierRequest struct {
+	Name               string  `json:"name" validate:"required,max=255"`
+	CustomerNumber     *string `json:"customer_number"`
+	ContactName        *string `json:"contact_name"`
+	Email              *string `json:"email" validate:"omitempty,email"`
+	Phone              *string `json:"phone"`
+	Fax                *string `json:"fax"`
+	Website            *string `json:"website"`
+	Address            *string `json:"address"`
+	LeadTimeDays       int     `json:"lead_time_days" validate:"gte=0"`
+	MinOrderValueCents *int    `json:"min_order_value_cents" validate:"omitempty,gte=0"`
+	Notes              *string `json:"notes"`
+	IsActive           *bool   `json:"is_active"`
+}
+
+func (req *supplierRequest) supplier(id string) *repository.Supplier {
+	supplier := &repository.Supplier{
+		ID:                 id,
+		Name:               req.Name,
+		CustomerNumber:     req.CustomerNumber,
+		ContactName:        req.ContactName,
+		Email:              req.Email,
+		Phone:              req.Phone,
+		Fax:                req.Fax,
+		Website:            req.Website,
+		Address:            req.Address,
+		LeadTimeDays:       req.LeadTimeDays,
+		MinOrderValueCents: req.MinOrderValueCents,
+		Notes:              req.Notes,
+		IsActive:           true,
+	}
+	if req.IsActive != nil {
+		supplier.IsActive = *req.IsActive
+	}
+	return supplier
+}
+
+// decodeSupplier decodes and validates a supplier request body
+func decodeSupplier(r *http.Request) (*supplierRequest, error) {
+	var req supplierRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		return nil, err
+	}
+	if err := httputil.Validate(&req); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// CreateSupplier creates a new supplier
+// POST /suppliers
+func (h *PurchaseOrderHandler) CreateSupplier(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeSupplier(r)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	supplier := req.supplier("")
+	if err := h.service.CreateSupplier(r.Context(), supplier); err != nil {
+		h.logger.Error().Err(err).Msg("failed to create supplier")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.Created(w, supplier)
+}
+
+// ListSuppliers lists suppliers
+// GET /suppliers?filter[is_active]=true&sort=name
+func (h *PurchaseOrderHandler) ListSuppliers(w http.ResponseWriter, r *http.Request) {
+	q, err := httputil.ParseListQuery(r)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	page, err := h.service.ListSuppliers(database.ReadOnly(r.Context()), q)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to list suppliers")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSONWithMeta(w, http.StatusOK, page.Items, httputil.PageMeta(q, page))
+}
+
+// GetSupplier gets a supplier by ID
+// GET /suppliers/{id}
+func (h *PurchaseOrderHandler) GetSupplier(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	supplier, err := h.service.GetSupplier(r.Context(), id)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, supplier)
+}
+
+// UpdateSupplier updates a supplier
+// PUT /suppliers/{id}
+func (h *PurchaseOrderHandler) UpdateSupplier(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	req, err := decodeSupplier(r)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	supplier := req.supplier(id)
+	if err := h.service.UpdateSupplier(r.Context(), supplier); err != nil {
+		h.logger.Error().Err(err).Str("id", id).Msg("failed to update supplier")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, supplier)
+}
+
+// DeleteSupplier deletes a supplier
+// DELETE /suppliers/{id}
+func (h *PurchaseOrderHandler) DeleteSupplier(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if err := h.service.DeleteSupplier(r.Context(), id); err != nil {
+		h.logger.Error().Err(err).Str("id", id).Msg("failed to delete supplier")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.NoContent(w)
+}
+
+// ListSupplierItems lists the items a supplier delivers
+// GET /suppliers/{id}/items
+func (h *PurchaseOrderHandler) ListSupplierItems(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	items, err := h.service.ListSupplierItems(r.Context(), id)
+	if err != nil {
+		h.logger.Error().Err(err).Str("supplier_id", id).Msg("failed to list supplier items")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, items)
+}
+
+// SetSupplierItem links an item to a supplier
+// PUT /suppliers/{id}/items/{itemId}
+func (h *PurchaseOrderHandler) SetSupplierItem(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		SupplierArticleNumber *string `json:"supplier_article_number"`
+		UnitPriceCents        *int    `json:"unit_price_cents" validate:"omitempty,gte=0"`
+		PackSize              int     `json:"pack_size" validate:"gte=0"`
+		IsPreferred           bool    `json:"is_preferred"`
+	}
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+	if err := httputil.Validate(&req); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	si := &repository.SupplierItem{
+		SupplierID:            chi.URLParam(r, "id"),
+		ItemID:                chi.URLParam(r, "itemId"),
+		SupplierArticleNumber: req.SupplierArticleNumber,
+		UnitPriceCents:        req.UnitPriceCents,
+		PackSize:              req.PackSize,
+		IsPreferred:           req.IsPreferred,
+	}
+	if err := h.service.SetSupplierItem(r.Context(), si); err != nil {
+		h.logger.Error().Err(err).Str("supplier_id", si.SupplierID).Str("item_id", si.ItemID).Msg("failed to set supplier item")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, si)
+}
+
+// RemoveSupplierItem removes an item from a supplier
+// DELETE /suppliers/{id}/items/{itemId}
+func (h *PurchaseOrderHandler) RemoveSupplierItem(w http.ResponseWriter, r *http.Request) {
+	supplierID := chi.URLParam(r, "id")
+	itemID := chi.URLParam(r, "itemId")
+
+	if err := h.service.RemoveSupplierItem(r.Context(), supplierID, itemID); err != nil {
+		h.logger.Error().Err(err).Str("supplier_id", supplierID).Str("item_id", itemID).Msg("failed to remove supplier item")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.NoContent(w)
+}
+
+// --- Purchase Order Handlers ---
+
+// orderRequest is the body of POST and PUT /purchase-orders
+type orderRequest struct {
+	SupplierID   string     `json:"supplier_id" validate:"required,uuid"`
+	ExpectedDate *time.Time `json:"expected_date"`
+	Notes        *string    `json:"notes"`
+	Lines        []struct {
+		ItemID                string  `json:"item_id" validate:"required,uuid"`
+		Quantity              int     `json:"quantity" validate:"required,gt=0"`
+		UnitPriceCents        *int    `json:"unit_price_cents" validate:"omitempty,gte=0"`
+		SupplierArticleNumber *string `json:"supplier_article_number"`
+		Notes                 *string `json:"notes"`
+	} `json:"lines" validate:"required,min=1,dive"`
+}
+
+// decodeOrder decodes and validates a purchase order request body
+func decodeOrder(r *http.Request, id string) (*repository.PurchaseOrder, error) {
+	var req orderRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		return nil, err
+	}
+	if err := httputil.Validate(&req); err != nil {
+		return nil, err
+	}
+
+	po := &repository.PurchaseOrder{
+		ID:           id,
+		SupplierID:   req.SupplierID,
+		ExpectedDate: req.ExpectedDate,
+		Notes:        req.Notes,
+	}
+	for _, l := range req.Lines {
+		po.Lines = append(po.Lines, &repository.PurchaseOrderLine{
+			ItemID:                l.ItemID,
+			QuantityOrdered:       l.Quantity,
+			UnitPriceCents:        l.UnitPriceCents,
+			SupplierArticleNumber: l.SupplierArticleNumber,
+			Notes:                 l.Notes,
+		})
+	}
+	return po, nil
+}
+
+// CreateOrder creates a draft purchase order
+// POST /purchase-orders
+func (h *PurchaseOrderHandler) CreateOrder(w http.ResponseWriter, r *http.Request) {
+	po, err := decodeOrder(r, "")
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	if userID := r.Header.Get("X-User-ID"); userID != "" {
+		po.CreatedBy = &userID
+	}
+
+	if err := h.service.CreateOrder(r.Context(), po); err != nil {
+		h.logger.Error().Err(err).Str("supplier_id", po.SupplierID).Msg("failed to create purchase order")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	h.respondOrder(w, r, po.ID, http.StatusCreated)
+}
+
+// ListOrders lists purchase orders
+// GET /purchase-orders?filter[status]=sent&sort=expected_date
+func (h *PurchaseOrderHandler) ListOrders(w http.ResponseWriter, r *http.Request) {
+	q, err := httputil.ParseListQuery(r)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	page, err := h.service.ListOrders(database.ReadOnly(r.Context()), q)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to list purchase orders")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSONWithMeta(w, http.StatusOK, page.Items, httputil.PageMeta(q, page))
+}
+
+// GetOrder gets a purchase order with its lines
+// GET /purchase-orders/{id}
+func (h *PurchaseOrderHandler) GetOrder(w http.ResponseWriter, r *http.Request) {
+	h.respondOrder(w, r, chi.URLParam(r, "id"), http.StatusOK)
+}
+
+// respondOrder writes the current state of an order
+func (h *PurchaseOrderHandler) respondOrder(w http.ResponseWriter, r *http.Request, id string, status int) {
+	po, err := h.service.GetOrder(r.Context(), id)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, status, po)
+}
+
+// UpdateOrder replaces a draft order
+// PUT /purchase-orders/{id}
+func (h *PurchaseOrderHandler) UpdateOrder(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	po, err := decodeOrder(r, id)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	if err := h.service.UpdateOrder(r.Context(), po); err != nil {
+		h.logger.Error().Err(err).Str("id", id).Msg("failed to update purchase order")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	h.respondOrder(w, r, id, http.StatusOK)
+}
+
+// DeleteOrder deletes a draft order
+// DELETE /purchase-orders/{id}
+func (h *PurchaseOrderHandler) DeleteOrder(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if err := h.service.DeleteOrder(r.Context(), id); err != nil {
+		h.logger.Error().Err(err).Str("id", id).Msg("failed to delete purchase order")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.NoContent(w)
+}
+
+// SendOrder marks a draft order as sent to the supplier
+// POST /purchase-orders/{id}/send
+func (h *PurchaseOrderHandler) SendOrder(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	po, err := h.service.SendOrder(r.Context(), id)
+	if err != nil {
+		h.logger.Error().Err(err).Str("id", id).Msg("failed to send purchase order")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, po)
+}
+
+// CancelOrder cancels an order
+// POST /purchase-orders/{id}/cancel
+func (h *PurchaseOrderHandler) CancelOrder(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req struct {
+		Reason *string `json:"reason"`
+	}
+	if r.ContentLength != 0 {
+		if err := httputil.DecodeJSON(r, &req); err != nil {
+			httputil.ErrorLocalized(w, r, err)
+			return
+		}
+	}
+
+	po, err := h.service.CancelOrder(r.Context(), id, req.Reason)
+	if err != nil {
+		h.logger.Error().Err(err).Str("id", id).Msg("failed to cancel purchase order")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, po)
+}
+
+// SuggestOrders creates draft orders for items with open stock alerts
+// POST /purchase-orders/suggestions
+func (h *PurchaseOrderHandler) SuggestOrders(w http.ResponseWriter, r *http.Request) {
+	result, err := h.service.SuggestOrders(r.Context())
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to suggest purchase orders")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, result)
+}
+
+// --- Goods Receipt Handlers ---
+
+// ReceiveGoods books a delivery against an order
+// POST /purchase-orders/{id}/receipts
+func (h *PurchaseOrderHandler) ReceiveGoods(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var input service.ReceiveGoodsInput
+	if err := httputil.DecodeJSON(r, &input); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+	if err := httputil.Validate(&input); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	userID := r.Header.Get("X-User-ID")
+	userName := r.Header.Get("X-User-Email")
+
+	receipt, err := h.service.ReceiveGoods(r.Context(), id, &input, userID, userName)
+	if err != nil {
+		h.logger.Error().Err(err).Str("id", id).Msg("failed to receive goods")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.Created(w, receipt)
+}
+
+// ListReceipts lists the goods receipts of an order
+// GET /purchase-orders/{id}/receipts
+func (h *PurchaseOrderHandler) ListReceipts(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	receipts, err := h.service.ListReceipts(r.Context(), id)
+	if err != nil {
+		h.logger.Error().Err(err).Str("id", id).Msg("failed to list goods receipts")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, receipts)
+}
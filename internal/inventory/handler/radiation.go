@@ -2,11 +2,11 @@ package handler
 
 import (
 	"net/http"
-	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/medflow/medflow-backend/internal/inventory/repository"
 	"github.com/medflow/medflow-backend/internal/inventory/service"
+	"github.com/medflow/medflow-backend/pkg/database"
 	"github.com/medflow/medflow-backend/pkg/httputil"
 	"github.com/medflow/medflow-backend/pkg/logger"
 )
@@ -32,13 +32,13 @@ func NewRadiationHandler(svc *service.RadiationService, log *logger.Logger) *Rad
 func (h *RadiationHandler) CreateDevice(w http.ResponseWriter, r *http.Request) {
 	var device repository.RadiationDevice
 	if err := httputil.DecodeJSON(r, &device); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	if err := h.service.CreateDevice(r.Context(), &device); err != nil {
 		h.logger.Error().Err(err).Str("item_id", device.ItemID).Msg("failed to create radiation device")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -51,7 +51,7 @@ func (h *RadiationHandler) ListDevices(w http.ResponseWriter, r *http.Request) {
 	devices, err := h.service.ListDevices(r.Context())
 	if err != nil {
 		h.logger.Error().Err(err).Msg("failed to list radiation devices")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -65,7 +65,7 @@ func (h *RadiationHandler) GetDevice(w http.ResponseWriter, r *http.Request) {
 
 	device, err := h.service.GetDevice(r.Context(), id)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -79,14 +79,14 @@ func (h *RadiationHandler) UpdateDevice(w http.ResponseWriter, r *http.Request)
 
 	var device repository.RadiationDevice
 	if err := httputil.DecodeJSON(r, &device); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	device.ID = id
 	if err := h.service.UpdateDevice(r.Context(), &device); err != nil {
 		h.logger.Error().Err(err).Str("id", id).Msg("failed to update radiation device")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -100,7 +100,7 @@ func (h *RadiationHandler) DeleteDevice(w http.ResponseWriter, r *http.Request)
 
 	if err := h.service.DeleteDevice(r.Context(), id); err != nil {
 		h.logger.Error().Err(err).Str("id", id).Msg("failed to delete radiation device")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -116,14 +116,14 @@ func (h *RadiationHandler) CreateTest(w http.ResponseWriter, r *http.Request) {
 
 	var test repository.ConstancyTest
 	if err := httputil.DecodeJSON(r, &test); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	test.DeviceID = deviceID
 	if err := h.service.CreateTest(r.Context(), &test); err != nil {
 		h.logger.Error().Err(err).Str("device_id", deviceID).Msg("failed to create constancy test")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -138,7 +138,7 @@ func (h *RadiationHandler) ListTests(w http.ResponseWriter, r *http.Request) {
 	tests, err := h.service.ListTestsByDevice(r.Context(), deviceID)
 	if err != nil {
 		h.logger.Error().Err(err).Str("device_id", deviceID).Msg("failed to list constancy tests")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -154,14 +154,14 @@ func (h *RadiationHandler) CreateExpertInspection(w http.ResponseWriter, r *http
 
 	var insp repository.ExpertInspection
 	if err := httputil.DecodeJSON(r, &insp); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	insp.DeviceID = deviceID
 	if err := h.service.CreateExpertInspection(r.Context(), &insp); err != nil {
 		h.logger.Error().Err(err).Str("device_id", deviceID).Msg("failed to create expert inspection")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -176,7 +176,7 @@ func (h *RadiationHandler) ListExpertInspections(w http.ResponseWriter, r *http.
 	inspections, err := h.service.ListExpertInspectionsByDevice(r.Context(), deviceID)
 	if err != nil {
 		h.logger.Error().Err(err).Str("device_id", deviceID).Msg("failed to list expert inspections")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -190,13 +190,13 @@ func (h *RadiationHandler) ListExpertInspections(w http.ResponseWriter, r *http.
 func (h *RadiationHandler) CreateCertification(w http.ResponseWriter, r *http.Request) {
 	var cert repository.StaffRadiationCertification
 	if err := httputil.DecodeJSON(r, &cert); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	if err := h.service.CreateCertification(r.Context(), &cert); err != nil {
 		h.logger.Error().Err(err).Str("employee_id", cert.EmployeeID).Msg("failed to create radiation certification")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -209,7 +209,7 @@ func (h *RadiationHandler) ListCertifications(w http.ResponseWriter, r *http.Req
 	certs, err := h.service.ListCertifications(r.Context())
 	if err != nil {
 		h.logger.Error().Err(err).Msg("failed to list radiation certifications")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -223,14 +223,14 @@ func (h *RadiationHandler) UpdateCertification(w http.ResponseWriter, r *http.Re
 
 	var cert repository.StaffRadiationCertification
 	if err := httputil.DecodeJSON(r, &cert); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	cert.ID = id
 	if err := h.service.UpdateCertification(r.Context(), &cert); err != nil {
 		h.logger.Error().Err(err).Str("id", id).Msg("failed to update radiation certification")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -244,7 +244,7 @@ func (h *RadiationHandler) DeleteCertification(w http.ResponseWriter, r *http.Re
 
 	if err := h.service.DeleteCertification(r.Context(), id); err != nil {
 		h.logger.Error().Err(err).Str("id", id).Msg("failed to delete radiation certification")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -258,13 +258,13 @@ func (h *RadiationHandler) DeleteCertification(w http.ResponseWriter, r *http.Re
 func (h *RadiationHandler) CreateDosimetryRecord(w http.ResponseWriter, r *http.Request) {
 	var record repository.DosimetryRecord
 	if err := httputil.DecodeJSON(r, &record); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	if err := h.service.CreateDosimetryRecord(r.Context(), &record); err != nil {
 		h.logger.Error().Err(err).Str("employee_id", record.EmployeeID).Msg("failed to create dosimetry record")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -272,35 +272,22 @@ func (h *RadiationHandler) CreateDosimetryRecord(w http.ResponseWriter, r *http.
 }
 
 // ListAllDosimetry lists all dosimetry records with pagination
-// GET /radiation/dosimetry
+// GET /radiation/dosimetry?filter[employee_id]=...&sort=-dose_msv
 func (h *RadiationHandler) ListAllDosimetry(w http.ResponseWriter, r *http.Request) {
-	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
-	if page < 1 {
-		page = 1
-	}
-	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
-	if perPage < 1 || perPage > 100 {
-		perPage = 50
+	q, err := httputil.ParseListQuery(r)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
 	}
 
-	records, total, err := h.service.ListAllDosimetry(r.Context(), page, perPage)
+	page, err := h.service.ListAllDosimetry(database.ReadOnly(r.Context()), q)
 	if err != nil {
 		h.logger.Error().Err(err).Msg("failed to list dosimetry records")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
-	totalPages := int(total) / perPage
-	if int(total)%perPage > 0 {
-		totalPages++
-	}
-
-	httputil.JSONWithMeta(w, http.StatusOK, records, &httputil.Meta{
-		Page:       page,
-		PerPage:    perPage,
-		Total:      total,
-		TotalPages: totalPages,
-	})
+	httputil.JSONWithMeta(w, http.StatusOK, page.Items, httputil.PageMeta(q, page))
 }
 
 // ListDosimetryByEmployee lists dosimetry records for a specific employee
@@ -311,7 +298,7 @@ func (h *RadiationHandler) ListDosimetryByEmployee(w http.ResponseWriter, r *htt
 	records, err := h.service.ListDosimetryByEmployee(r.Context(), employeeID)
 	if err != nil {
 		h.logger.Error().Err(err).Str("employee_id", employeeID).Msg("failed to list dosimetry records for employee")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -32,7 +32,7 @@ func NewRecallHandler(svc *service.RecallService, safetyRepo *repository.SafetyO
 func (h *RecallHandler) CreateNotice(w http.ResponseWriter, r *http.Request) {
 	var notice repository.FieldSafetyNotice
 	if err := httputil.DecodeJSON(r, &notice); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -42,7 +42,7 @@ func (h *RecallHandler) CreateNotice(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.recallService.CreateFieldSafetyNotice(r.Context(), &notice); err != nil {
 		h.logger.Error().Err(err).Msg("failed to create field safety notice")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -55,7 +55,7 @@ func (h *RecallHandler) GetNotice(w http.ResponseWriter, r *http.Request) {
 
 	notice, err := h.recallService.GetNotice(r.Context(), id)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -79,7 +79,7 @@ func (h *RecallHandler) ListNotices(w http.ResponseWriter, r *http.Request) {
 	notices, total, err := h.recallService.ListNotices(r.Context(), status, page, perPage)
 	if err != nil {
 		h.logger.Error().Err(err).Msg("failed to list field safety notices")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -104,13 +104,13 @@ func (h *RecallHandler) UpdateNoticeStatus(w http.ResponseWriter, r *http.Reques
 		Status string `json:"status"`
 	}
 	if err := httputil.DecodeJSON(r, &req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	if err := h.recallService.UpdateNoticeStatus(r.Context(), id, req.Status); err != nil {
 		h.logger.Error().Err(err).Str("id", id).Msg("failed to update notice status")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -124,7 +124,7 @@ func (h *RecallHandler) ListMatchesByNotice(w http.ResponseWriter, r *http.Reque
 	matches, err := h.recallService.ListMatchesByNotice(r.Context(), noticeID)
 	if err != nil {
 		h.logger.Error().Err(err).Str("notice_id", noticeID).Msg("failed to list recall matches")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -139,7 +139,7 @@ func (h *RecallHandler) ResolveMatch(w http.ResponseWriter, r *http.Request) {
 		ActionTaken string `json:"action_taken"`
 	}
 	if err := httputil.DecodeJSON(r, &req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -147,7 +147,7 @@ func (h *RecallHandler) ResolveMatch(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.recallService.ResolveMatch(r.Context(), matchID, req.ActionTaken, actionBy); err != nil {
 		h.logger.Error().Err(err).Str("match_id", matchID).Msg("failed to resolve recall match")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -159,7 +159,7 @@ func (h *RecallHandler) ListPendingMatches(w http.ResponseWriter, r *http.Reques
 	matches, total, err := h.recallService.ListPendingMatches(r.Context())
 	if err != nil {
 		h.logger.Error().Err(err).Msg("failed to list pending recall matches")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -175,7 +175,7 @@ func (h *RecallHandler) ListSafetyOfficers(w http.ResponseWriter, r *http.Reques
 	officers, err := h.safetyRepo.List(r.Context())
 	if err != nil {
 		h.logger.Error().Err(err).Msg("failed to list safety officers")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -186,7 +186,7 @@ func (h *RecallHandler) ListSafetyOfficers(w http.ResponseWriter, r *http.Reques
 func (h *RecallHandler) CreateSafetyOfficer(w http.ResponseWriter, r *http.Request) {
 	var officer repository.SafetyOfficer
 	if err := httputil.DecodeJSON(r, &officer); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -194,7 +194,7 @@ func (h *RecallHandler) CreateSafetyOfficer(w http.ResponseWriter, r *http.Reque
 
 	if err := h.safetyRepo.Create(r.Context(), &officer); err != nil {
 		h.logger.Error().Err(err).Msg("failed to create safety officer")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -207,7 +207,7 @@ func (h *RecallHandler) UpdateSafetyOfficer(w http.ResponseWriter, r *http.Reque
 
 	var officer repository.SafetyOfficer
 	if err := httputil.DecodeJSON(r, &officer); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -215,7 +215,7 @@ func (h *RecallHandler) UpdateSafetyOfficer(w http.ResponseWriter, r *http.Reque
 
 	if err := h.safetyRepo.Update(r.Context(), &officer); err != nil {
 		h.logger.Error().Err(err).Str("id", id).Msg("failed to update safety officer")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -228,7 +228,7 @@ func (h *RecallHandler) DeleteSafetyOfficer(w http.ResponseWriter, r *http.Reque
 
 	if err := h.safetyRepo.Delete(r.Context(), id); err != nil {
 		h.logger.Error().Err(err).Str("id", id).Msg("failed to delete safety officer")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
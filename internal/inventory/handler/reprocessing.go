@@ -32,13 +32,13 @@ func NewReprocessingHandler(svc *service.ReprocessingService, log *logger.Logger
 func (h *ReprocessingHandler) CreateBatch(w http.ResponseWriter, r *http.Request) {
 	var batch repository.SterilizationBatch
 	if err := httputil.DecodeJSON(r, &batch); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	if err := h.service.CreateBatch(r.Context(), &batch); err != nil {
 		h.logger.Error().Err(err).Str("batch_number", batch.BatchNumber).Msg("failed to create sterilization batch")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -60,7 +60,7 @@ func (h *ReprocessingHandler) ListBatches(w http.ResponseWriter, r *http.Request
 	batches, total, err := h.service.ListBatches(r.Context(), page, perPage)
 	if err != nil {
 		h.logger.Error().Err(err).Msg("failed to list sterilization batches")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -84,7 +84,7 @@ func (h *ReprocessingHandler) GetBatch(w http.ResponseWriter, r *http.Request) {
 
 	batch, err := h.service.GetBatch(r.Context(), id)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -98,14 +98,14 @@ func (h *ReprocessingHandler) UpdateBatch(w http.ResponseWriter, r *http.Request
 
 	var batch repository.SterilizationBatch
 	if err := httputil.DecodeJSON(r, &batch); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	batch.ID = id
 	if err := h.service.UpdateBatch(r.Context(), &batch); err != nil {
 		h.logger.Error().Err(err).Str("id", id).Msg("failed to update sterilization batch")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -119,7 +119,7 @@ func (h *ReprocessingHandler) DeleteBatch(w http.ResponseWriter, r *http.Request
 
 	if err := h.service.DeleteBatch(r.Context(), id); err != nil {
 		h.logger.Error().Err(err).Str("id", id).Msg("failed to delete sterilization batch")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -135,14 +135,14 @@ func (h *ReprocessingHandler) CreateCycle(w http.ResponseWriter, r *http.Request
 
 	var cycle repository.ReprocessingCycle
 	if err := httputil.DecodeJSON(r, &cycle); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	cycle.ItemID = itemID
 	if err := h.service.CreateCycle(r.Context(), &cycle); err != nil {
 		h.logger.Error().Err(err).Str("item_id", itemID).Msg("failed to create reprocessing cycle")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -157,7 +157,7 @@ func (h *ReprocessingHandler) ListCyclesByItem(w http.ResponseWriter, r *http.Re
 	cycles, err := h.service.ListCyclesByItem(r.Context(), itemID)
 	if err != nil {
 		h.logger.Error().Err(err).Str("item_id", itemID).Msg("failed to list reprocessing cycles")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -171,14 +171,14 @@ func (h *ReprocessingHandler) UpdateCycle(w http.ResponseWriter, r *http.Request
 
 	var cycle repository.ReprocessingCycle
 	if err := httputil.DecodeJSON(r, &cycle); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	cycle.ID = id
 	if err := h.service.UpdateCycle(r.Context(), &cycle); err != nil {
 		h.logger.Error().Err(err).Str("id", id).Msg("failed to update reprocessing cycle")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -192,7 +192,7 @@ func (h *ReprocessingHandler) DeleteCycle(w http.ResponseWriter, r *http.Request
 
 	if err := h.service.DeleteCycle(r.Context(), id); err != nil {
 		h.logger.Error().Err(err).Str("id", id).Msg("failed to delete reprocessing cycle")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/medflow/medflow-backend/internal/inventory/service"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/httputil"
+	"github.com/medflow/medflow-backend/pkg/logger"
+)
+
+// ReservationHandler handles stock reservation and pick list endpoints
+type ReservationHandler struct {
+	service *service.ReservationService
+	logger  *logger.Logger
+}
+
+// NewReservationHandler creates a new reservation handler
+func NewReservationHandler(svc *service.ReservationService, log *logger.Logger) *ReservationHandler {
+	return &ReservationHandler{
+		service: svc,
+		logger:  log,
+	}
+}
+
+// PickList suggests batches to take a quantity of an item from (FEFO)
+// GET /items/{id}/pick-list?quantity=10
+func (h *ReservationHandler) PickList(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	quantity := 0
+	if v := r.URL.Query().Get("quantity"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			httputil.ErrorLocalized(w, r, errors.Validation(nil).WithDetail("quantity", "validation.integer", nil))
+			return
+		}
+		quantity = n
+	}
+
+	list, err := h.service.PickList(database.ReadOnly(r.Context()), id, quantity)
+	if err != nil {
+		h.logger.Error().Err(err).Str("item_id", id).Msg("failed to build pick list")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, list)
+}
+
+// Reserve reserves stock of an item
+// POST /reservations
+func (h *ReservationHandler) Reserve(w http.ResponseWriter, r *http.Request) {
+	var input service.ReserveInput
+	if err := httputil.DecodeJSON(r, &input); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+	if err := httputil.Validate(&input); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	res, err := h.service.Reserve(r.Context(), &input, r.Header.Get("X-User-ID"), r.Header.Get("X-User-Email"))
+	if err != nil {
+		h.logger.Error().Err(err).Str("item_id", input.ItemID).Msg("failed to reserve stock")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.Created(w, res)
+}
+
+// List lists reservations
+// GET /reservations?filter[status]=active&sort=needed_at
+func (h *ReservationHandler) List(w http.ResponseWriter, r *http.Request) {
+	q, err := httputil.ParseListQuery(r)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	page, err := h.service.List(database.ReadOnly(r.Context()), q)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to list reservations")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSONWithMeta(w, http.StatusOK, page.Items, httputil.PageMeta(q, page))
+}
+
+// Get gets a reservation with its batches
+// GET /reservations/{id}
+func (h *ReservationHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	res, err := h.service.Get(r.Context(), id)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, res)
+}
+
+// Release gives the reserved stock back
+// POST /reservations/{id}/release
+func (h *ReservationHandler) Release(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	res, err := h.service.Release(r.Context(), id)
+	if err != nil {
+		h.logger.Error().Err(err).Str("id", id).Msg("failed to release reservation")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, res)
+}
+
+// Consume books out the reserved stock; a smaller quantity releases the rest
+// POST /reservations/{id}/consume
+func (h *ReservationHandler) Consume(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req struct {
+		Quantity *int `json:"quantity" validate:"omitempty,gte=0"`
+	}
+	if r.ContentLength != 0 {
+		if err := httputil.DecodeJSON(r, &req); err != nil {
+			httputil.ErrorLocalized(w, r, err)
+			return
+		}
+		if err := httputil.Validate(&req); err != nil {
+			httputil.ErrorLocalized(w, r, err)
+			return
+		}
+	}
+
+	res, err := h.service.Consume(r.Context(), id, req.Quantity, r.Header.Get("X-User-ID"), r.Header.Get("X-User-Email"))
+	if err != nil {
+		h.logger.Error().Err(err).Str("id", id).Msg("failed to consume reservation")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, res)
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/medflow/medflow-backend/internal/inventory/repository"
 	"github.com/medflow/medflow-backend/internal/inventory/service"
+	"github.com/medflow/medflow-backend/pkg/database"
 	"github.com/medflow/medflow-backend/pkg/httputil"
 	"github.com/medflow/medflow-backend/pkg/logger"
 )
@@ -30,7 +31,7 @@ func (h *RetentionHandler) List(w http.ResponseWriter, r *http.Request) {
 	policies, err := h.service.List(r.Context())
 	if err != nil {
 		h.logger.Error().Err(err).Msg("failed to list retention policies")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -42,13 +43,13 @@ func (h *RetentionHandler) List(w http.ResponseWriter, r *http.Request) {
 func (h *RetentionHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var policy repository.RetentionPolicy
 	if err := httputil.DecodeJSON(r, &policy); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	if err := h.service.Create(r.Context(), &policy); err != nil {
 		h.logger.Error().Err(err).Str("entity_type", policy.EntityType).Msg("failed to create retention policy")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -62,14 +63,14 @@ func (h *RetentionHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 	var policy repository.RetentionPolicy
 	if err := httputil.DecodeJSON(r, &policy); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	policy.ID = id
 	if err := h.service.Update(r.Context(), &policy); err != nil {
 		h.logger.Error().Err(err).Str("id", id).Msg("failed to update retention policy")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -83,9 +84,135 @@ func (h *RetentionHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.service.Delete(r.Context(), id); err != nil {
 		h.logger.Error().Err(err).Str("id", id).Msg("failed to delete retention policy")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	httputil.NoContent(w)
 }
+
+// --- Legal holds ---
+
+// legalHoldRequest is the body of POST /legal-holds
+type legalHoldRequest struct {
+	EntityType string  `json:"entity_type" validate:"required,max=50"`
+	EntityID   *string `json:"entity_id" validate:"omitempty,uuid"`
+	Reason     string  `json:"reason" validate:"required"`
+	Reference  *string `json:"reference" validate:"omitempty,max=255"`
+}
+
+// releaseLegalHoldRequest is the body of POST /legal-holds/{id}/release
+type releaseLegalHoldRequest struct {
+	Note *string `json:"note"`
+}
+
+// ListHolds lists legal holds
+// GET /legal-holds
+func (h *RetentionHandler) ListHolds(w http.ResponseWriter, r *http.Request) {
+	q, err := httputil.ParseListQuery(r)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	page, err := h.service.ListHolds(database.ReadOnly(r.Context()), q)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to list legal holds")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSONWithMeta(w, http.StatusOK, page.Items, httputil.PageMeta(q, page))
+}
+
+// PlaceHold places a legal hold on an entity type or one record
+// POST /legal-holds
+func (h *RetentionHandler) PlaceHold(w http.ResponseWriter, r *http.Request) {
+	var req legalHoldRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+	if err := httputil.Validate(&req); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	hold := &repository.LegalHold{
+		EntityType: req.EntityType,
+		EntityID:   req.EntityID,
+		Reason:     req.Reason,
+		Reference:  req.Reference,
+	}
+	if userID := r.Header.Get("X-User-ID"); userID != "" {
+		hold.CreatedBy = &userID
+	}
+
+	if err := h.service.PlaceHold(r.Context(), hold); err != nil {
+		h.logger.Error().Err(err).Str("entity_type", req.EntityType).Msg("failed to place legal hold")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.Created(w, hold)
+}
+
+// GetHold gets a legal hold
+// GET /legal-holds/{id}
+func (h *RetentionHandler) GetHold(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	hold, err := h.service.GetHold(r.Context(), id)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, hold)
+}
+
+// ReleaseHold lifts a legal hold
+// POST /legal-holds/{id}/release
+func (h *RetentionHandler) ReleaseHold(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req releaseLegalHoldRequest
+	if r.ContentLength != 0 {
+		if err := httputil.DecodeJSON(r, &req); err != nil {
+			httputil.ErrorLocalized(w, r, err)
+			return
+		}
+	}
+
+	hold := &repository.LegalHold{ID: id, ReleaseNote: req.Note}
+	if userID := r.Header.Get("X-User-ID"); userID != "" {
+		hold.ReleasedBy = &userID
+	}
+
+	if err := h.service.ReleaseHold(r.Context(), hold); err != nil {
+		h.logger.Error().Err(err).Str("id", id).Msg("failed to release legal hold")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, hold)
+}
+
+// ListProtocols lists the deletion protocol of the retention purge
+// GET /deletion-protocols
+func (h *RetentionHandler) ListProtocols(w http.ResponseWriter, r *http.Request) {
+	q, err := httputil.ParseListQuery(r)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	page, err := h.service.ListProtocols(database.ReadOnly(r.Context()), q)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to list deletion protocols")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSONWithMeta(w, http.StatusOK, page.Items, httputil.PageMeta(q, page))
+}
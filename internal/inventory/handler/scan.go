@@ -28,18 +28,18 @@ func NewScanHandler(svc *service.InventoryService, log *logger.Logger) *ScanHand
 func (h *ScanHandler) LookupByBarcode(w http.ResponseWriter, r *http.Request) {
 	barcode := chi.URLParam(r, "barcode")
 	if barcode == "" {
-		httputil.Error(w, errors.BadRequest("barcode is required"))
+		httputil.ErrorLocalized(w, r, errors.BadRequest("validation.required", map[string]string{"field": "barcode"}))
 		return
 	}
 	// Reject excessively long input to avoid unnecessary DB queries
 	if len(barcode) > 200 {
-		httputil.Error(w, errors.BadRequest("barcode too long"))
+		httputil.ErrorLocalized(w, r, errors.BadRequest("validation.max_length", map[string]string{"field": "barcode", "max": "200"}))
 		return
 	}
 
 	item, err := h.service.GetItemByBarcode(r.Context(), barcode)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -50,17 +50,45 @@ func (h *ScanHandler) LookupByBarcode(w http.ResponseWriter, r *http.Request) {
 func (h *ScanHandler) LookupByBatchNumber(w http.ResponseWriter, r *http.Request) {
 	batchNumber := r.URL.Query().Get("batchNumber")
 	if batchNumber == "" {
-		httputil.Error(w, errors.BadRequest("batchNumber query parameter is required"))
+		httputil.ErrorLocalized(w, r, errors.BadRequest("validation.required", map[string]string{"field": "batchNumber"}))
 		return
 	}
 	if len(batchNumber) > 200 {
-		httputil.Error(w, errors.BadRequest("batchNumber too long"))
+		httputil.ErrorLocalized(w, r, errors.BadRequest("validation.max_length", map[string]string{"field": "batchNumber", "max": "200"}))
 		return
 	}
 
 	result, err := h.service.GetBatchByBatchNumber(r.Context(), batchNumber)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, result)
+}
+
+// ScanCodeRequest is the body of a scan
+type ScanCodeRequest struct {
+	// Code as sent by the scanner, including GS/RS control characters
+	Code string `json:"code" validate:"required,max=512"`
+}
+
+// ScanCode decodes a GS1/UDI, securPharm, HIBC or plain code and returns the
+// matching item and batch with batch number and expiry to pre-fill
+func (h *ScanHandler) ScanCode(w http.ResponseWriter, r *http.Request) {
+	var req ScanCodeRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+	if err := httputil.Validate(&req); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	result, err := h.service.ScanCode(r.Context(), req.Code)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
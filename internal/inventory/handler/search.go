@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/medflow/medflow-backend/internal/inventory/service"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/httputil"
+	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/medflow/medflow-backend/pkg/search"
+)
+
+// SearchHandler handles the inventory search endpoint
+type SearchHandler struct {
+	service *service.SearchService
+	logger  *logger.Logger
+}
+
+// NewSearchHandler creates a new search handler
+func NewSearchHandler(svc *service.SearchService, log *logger.Logger) *SearchHandler {
+	return &SearchHandler{
+		service: svc,
+		logger:  log,
+	}
+}
+
+// Search searches items and hygiene plans
+// GET /search?q=&types=item,hygiene_plan&limit=
+func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	req, err := search.ParseRequest(r)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	resp, err := h.service.Search(database.ReadOnly(r.Context()), req)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("inventory search failed")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, resp)
+}
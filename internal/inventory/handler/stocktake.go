@@ -0,0 +1,256 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/internal/inventory/service"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/httputil"
+	"github.com/medflow/medflow-backend/pkg/logger"
+)
+
+// StocktakeHandler handles stocktake (Inventur) endpoints
+type StocktakeHandler struct {
+	service *service.StocktakeService
+	logger  *logger.Logger
+}
+
+// NewStocktakeHandler creates a new stocktake handler
+func NewStocktakeHandler(svc *service.StocktakeService, log *logger.Logger) *StocktakeHandler {
+	return &StocktakeHandler{
+		service: svc,
+		logger:  log,
+	}
+}
+
+// Start starts a stocktake
+// POST /stocktakes
+func (h *StocktakeHandler) Start(w http.ResponseWriter, r *http.Request) {
+	var input service.StartStocktakeInput
+	if err := httputil.DecodeJSON(r, &input); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+	if err := httputil.Validate(&input); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	st, err := h.service.Start(r.Context(), &input, r.Header.Get("X-User-ID"), r.Header.Get("X-User-Email"))
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to start stocktake")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.Created(w, st)
+}
+
+// List lists stocktakes
+// GET /stocktakes?filter[status]=approved&sort=-started_at
+func (h *StocktakeHandler) List(w http.ResponseWriter, r *http.Request) {
+	q, err := httputil.ParseListQuery(r)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	page, err := h.service.List(database.ReadOnly(r.Context()), q)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to list stocktakes")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSONWithMeta(w, http.StatusOK, page.Items, httputil.PageMeta(q, page))
+}
+
+// Get gets a stocktake by ID
+// GET /stocktakes/{id}
+func (h *StocktakeHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	st, err := h.service.Get(r.Context(), id)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, st)
+}
+
+// CountSheet gets the count sheet; expected quantities are hidden while a
+// blind stocktake is counting
+// GET /stocktakes/{id}/count-sheet
+func (h *StocktakeHandler) CountSheet(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	lines, err := h.service.CountSheet(database.ReadOnly(r.Context()), id)
+	if err != nil {
+		h.logger.Error().Err(err).Str("id", id).Msg("failed to get count sheet")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, lines)
+}
+
+// Count adds a count sheet entry, by line or by scanned code
+// POST /stocktakes/{id}/counts
+func (h *StocktakeHandler) Count(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var input service.CountInput
+	if err := httputil.DecodeJSON(r, &input); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+	if err := httputil.Validate(&input); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	line, err := h.service.Count(r.Context(), id, &input, r.Header.Get("X-User-ID"), r.Header.Get("X-User-Email"))
+	if err != nil {
+		h.logger.Error().Err(err).Str("id", id).Msg("failed to count stocktake line")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.Created(w, line)
+}
+
+// ListCounts lists all count sheet entries of a stocktake
+// GET /stocktakes/{id}/counts
+func (h *StocktakeHandler) ListCounts(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	counts, err := h.service.ListCounts(database.ReadOnly(r.Context()), id)
+	if err != nil {
+		h.logger.Error().Err(err).Str("id", id).Msg("failed to list stocktake counts")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, counts)
+}
+
+// Finish closes counting and moves the stocktake to review
+// POST /stocktakes/{id}/finish
+func (h *StocktakeHandler) Finish(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, "finish", h.service.FinishCounting)
+}
+
+// Reopen returns a stocktake in review to counting for recounts
+// POST /stocktakes/{id}/reopen
+func (h *StocktakeHandler) Reopen(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, "reopen", h.service.Reopen)
+}
+
+// Cancel cancels a stocktake without posting
+// POST /stocktakes/{id}/cancel
+func (h *StocktakeHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, "cancel", h.service.Cancel)
+}
+
+func (h *StocktakeHandler) transition(w http.ResponseWriter, r *http.Request, action string, fn func(context.Context, string) (*repository.Stocktake, error)) {
+	id := chi.URLParam(r, "id")
+
+	st, err := fn(r.Context(), id)
+	if err != nil {
+		h.logger.Error().Err(err).Str("id", id).Str("action", action).Msg("failed to change stocktake status")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, st)
+}
+
+// Variance gets the valued variance report
+// GET /stocktakes/{id}/variance
+func (h *StocktakeHandler) Variance(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	report, err := h.service.Variance(database.ReadOnly(r.Context()), id)
+	if err != nil {
+		h.logger.Error().Err(err).Str("id", id).Msg("failed to get stocktake variance")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, report)
+}
+
+// Approve posts the variances as stock adjustments and seals the stocktake
+// POST /stocktakes/{id}/approve
+func (h *StocktakeHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	st, err := h.service.Approve(r.Context(), id, r.Header.Get("X-User-ID"), r.Header.Get("X-User-Email"))
+	if err != nil {
+		h.logger.Error().Err(err).Str("id", id).Msg("failed to approve stocktake")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, st)
+}
+
+// Protocol downloads the stocktake protocol PDF of an approved stocktake
+// GET /stocktakes/{id}/protocol
+func (h *StocktakeHandler) Protocol(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	st, pdfBytes, err := h.service.Protocol(exportContext(r), id)
+	if err != nil {
+		var appErr *errors.AppError
+		if !errors.As(err, &appErr) {
+			h.logger.Error().Err(err).Str("id", id).Msg("failed to generate stocktake protocol")
+			err = errors.Internal("errors.export.pdf_failed")
+		}
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	filename := fmt.Sprintf("inventurprotokoll-%s.pdf", st.StocktakeNumber)
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(pdfBytes)))
+	if st.ProtocolSHA256 != nil {
+		w.Header().Set("X-Protocol-SHA256", *st.ProtocolSHA256)
+	}
+	if st.ProtocolSeal != nil {
+		w.Header().Set("X-Protocol-Seal", *st.ProtocolSeal)
+	}
+	w.Write(pdfBytes)
+}
+
+// VerifyProtocol checks the digest and seal printed on a stocktake protocol
+// POST /stocktakes/{id}/protocol/verify
+func (h *StocktakeHandler) VerifyProtocol(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var input service.VerifySealInput
+	if err := httputil.DecodeJSON(r, &input); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+	if err := httputil.Validate(&input); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	result, err := h.service.VerifyProtocol(database.ReadOnly(r.Context()), id, &input)
+	if err != nil {
+		h.logger.Error().Err(err).Str("id", id).Msg("failed to verify stocktake protocol")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, result)
+}
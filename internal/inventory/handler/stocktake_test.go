@@ -0,0 +1,89 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/medflow/medflow-backend/internal/inventory/handler"
+	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/internal/inventory/service"
+	"github.com/medflow/medflow-backend/pkg/httputil"
+	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Stocktake Count Tests ---
+
+func TestStocktakeCount_BlindHidesExpectedQuantity(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tenant := suite.SetupInventoryTenant(t, ctx, "handler-stocktake-blind")
+	tenantCtx := suite.TenantContext(tenant)
+
+	item := createTestItem(t, tenantCtx, "Blind Count Item")
+	batch := &repository.InventoryBatch{
+		ItemID: item.ID, BatchNumber: "BLIND-1", InitialQuantity: 12, CurrentQuantity: 12,
+		ReceivedDate: time.Now().UTC(), Status: "available",
+	}
+	require.NoError(t, repository.NewBatchRepository(suite.DB).Create(tenantCtx, batch))
+
+	log := logger.New("test", "test")
+	auditService := service.NewAuditService(repository.NewAuditTrailRepository(suite.DB), log)
+	svc := service.NewStocktakeService(repository.NewStocktakeRepository(suite.DB), auditService, nil, "test-seal-key", log)
+	h := handler.NewStocktakeHandler(svc, log)
+
+	r := chi.NewRouter()
+	r.Use(httputil.TenantMiddleware)
+	r.Post("/stocktakes/{id}/counts", h.Count)
+
+	count := func(st *repository.Stocktake, body string) map[string]interface{} {
+		t.Helper()
+		req := httptest.NewRequest("POST", "/stocktakes/"+st.ID+"/counts", bytes.NewBufferString(body))
+		req.Header.Set("X-Tenant-ID", tenant.ID)
+		req.Header.Set("X-Tenant-Slug", tenant.Slug)
+		req.Header.Set("Content-Type", "application/json")
+
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusCreated, rr.Code, "unexpected status code. Body: %s", rr.Body.String())
+
+		var resp struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		return resp.Data
+	}
+
+	// Blind: the count response must not reveal the book quantity
+	blind, err := svc.Start(tenantCtx, &service.StartStocktakeInput{Title: "Blindinventur"}, "", "")
+	require.NoError(t, err)
+	require.True(t, blind.Blind)
+	sheet, err := svc.CountSheet(tenantCtx, blind.ID)
+	require.NoError(t, err)
+	require.Len(t, sheet, 1)
+	line := count(blind, `{"line_id": "`+sheet[0].LineID+`", "quantity": 5}`)
+	assert.NotContains(t, line, "expected_quantity")
+	assert.Equal(t, float64(5), line["counted_quantity"])
+
+	_, err = svc.Cancel(tenantCtx, blind.ID)
+	require.NoError(t, err)
+
+	// Open: the expected quantity is shown as before
+	notBlind := false
+	open, err := svc.Start(tenantCtx, &service.StartStocktakeInput{Title: "Offene Inventur", Blind: &notBlind}, "", "")
+	require.NoError(t, err)
+	sheet, err = svc.CountSheet(tenantCtx, open.ID)
+	require.NoError(t, err)
+	require.Len(t, sheet, 1)
+	line = count(open, `{"line_id": "`+sheet[0].LineID+`", "quantity": 12}`)
+	assert.Equal(t, float64(12), line["expected_quantity"])
+}
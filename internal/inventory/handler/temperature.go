@@ -7,6 +7,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/medflow/medflow-backend/internal/inventory/service"
+	"github.com/medflow/medflow-backend/pkg/errors"
 	"github.com/medflow/medflow-backend/pkg/httputil"
 	"github.com/medflow/medflow-backend/pkg/logger"
 )
@@ -34,7 +35,7 @@ func (h *TemperatureHandler) RecordTemperature(w http.ResponseWriter, r *http.Re
 		Notes              *string `json:"notes,omitempty"`
 	}
 	if err := httputil.DecodeJSON(r, &req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -44,10 +45,10 @@ func (h *TemperatureHandler) RecordTemperature(w http.ResponseWriter, r *http.Re
 		recordedBy = &userID
 	}
 
-	reading, err := h.service.RecordTemperature(r.Context(), cabinetID, req.TemperatureCelsius, "manual", recordedBy, req.Notes)
+	reading, err := h.service.RecordTemperature(r.Context(), cabinetID, req.TemperatureCelsius, time.Time{}, "manual", recordedBy, req.Notes)
 	if err != nil {
 		h.logger.Error().Err(err).Str("cabinet_id", cabinetID).Msg("failed to record temperature")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -82,7 +83,7 @@ func (h *TemperatureHandler) ListReadings(w http.ResponseWriter, r *http.Request
 
 	readings, total, err := h.service.ListTemperatureReadings(r.Context(), cabinetID, from, to, page, perPage)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -94,27 +95,37 @@ func (h *TemperatureHandler) ListReadings(w http.ResponseWriter, r *http.Request
 	})
 }
 
-// Webhook handles incoming temperature readings from external sensors
+// Webhook handles a reading pushed by an integration signed in as a user.
+// Sensors with their own credentials use ColdChainHandler.IngestReadings.
 func (h *TemperatureHandler) Webhook(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		CabinetID          string    `json:"cabinet_id"`
-		TemperatureCelsius float64   `json:"temperature_celsius"`
+		CabinetID          string     `json:"cabinet_id"`
+		TemperatureCelsius float64    `json:"temperature_celsius"`
 		RecordedAt         *time.Time `json:"recorded_at,omitempty"`
 	}
 	if err := httputil.DecodeJSON(r, &req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	if req.CabinetID == "" {
-		http.Error(w, "cabinet_id required", http.StatusBadRequest)
+		httputil.ErrorLocalized(w, r, errors.BadRequest("validation.required", map[string]string{"field": "cabinet_id"}))
 		return
 	}
 
-	reading, err := h.service.RecordTemperature(r.Context(), req.CabinetID, req.TemperatureCelsius, "webhook", nil, nil)
+	var recordedAt time.Time
+	if req.RecordedAt != nil {
+		recordedAt = *req.RecordedAt
+	}
+	var recordedBy *string
+	if userID := r.Header.Get("X-User-ID"); userID != "" {
+		recordedBy = &userID
+	}
+
+	reading, err := h.service.RecordTemperature(r.Context(), req.CabinetID, req.TemperatureCelsius, recordedAt, "webhook", recordedBy, nil)
 	if err != nil {
 		h.logger.Error().Err(err).Str("cabinet_id", req.CabinetID).Msg("failed to record webhook temperature")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
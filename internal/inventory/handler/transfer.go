@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/internal/inventory/service"
+	"github.com/medflow/medflow-backend/pkg/httputil"
+	"github.com/medflow/medflow-backend/pkg/logger"
+)
+
+// TransferHandler handles stock transfer endpoints
+type TransferHandler struct {
+	service *service.TransferService
+	logger  *logger.Logger
+}
+
+// NewTransferHandler creates a new transfer handler
+func NewTransferHandler(svc *service.TransferService, log *logger.Logger) *TransferHandler {
+	return &TransferHandler{
+		service: svc,
+		logger:  log,
+	}
+}
+
+// Transfer moves stock of a batch to another room, cabinet or shelf
+// POST /batches/{id}/transfer
+func (h *TransferHandler) Transfer(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req struct {
+		ToLocationID string  `json:"to_location_id" validate:"required,uuid"`
+		Quantity     int     `json:"quantity" validate:"required,gt=0"`
+		Reason       *string `json:"reason"`
+	}
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+	if err := httputil.Validate(&req); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	userName := r.Header.Get("X-User-Email")
+	transfer := &repository.StockTransfer{
+		BatchID:         id,
+		ToLocationID:    req.ToLocationID,
+		Quantity:        req.Quantity,
+		Reason:          req.Reason,
+		PerformedBy:     r.Header.Get("X-User-ID"),
+		PerformedByName: &userName,
+	}
+	if err := h.service.Transfer(r.Context(), transfer); err != nil {
+		h.logger.Error().Err(err).Str("batch_id", id).Msg("failed to transfer stock")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.Created(w, transfer)
+}
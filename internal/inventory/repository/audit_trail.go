@@ -6,12 +6,18 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/medflow/medflow-backend/pkg/auditchain"
 	"github.com/medflow/medflow-backend/pkg/database"
 	"github.com/medflow/medflow-backend/pkg/tenant"
 )
 
+// AuditChain is the name of the hash chain of the inventory audit trail
+const AuditChain = "inventory.audit_trail"
+
 // AuditEntry represents a GoBD-compliant audit trail entry.
 // Audit entries are append-only — they are never updated or deleted.
+// Sequence, PrevHash and Hash chain the entries of a tenant (pkg/auditchain);
+// they are nil on entries written before the chain was introduced.
 type AuditEntry struct {
 	ID              string    `db:"id" json:"id"`
 	EntityType      string    `db:"entity_type" json:"entity_type"`
@@ -23,6 +29,30 @@ type AuditEntry struct {
 	PerformedByName *string   `db:"performed_by_name" json:"performed_by_name,omitempty"`
 	IPAddress       *string   `db:"ip_address" json:"ip_address,omitempty"`
 	CreatedAt       time.Time `db:"created_at" json:"created_at"`
+	Sequence        *int64    `db:"sequence" json:"sequence,omitempty"`
+	PrevHash        *string   `db:"prev_hash" json:"prev_hash,omitempty"`
+	Hash            *string   `db:"hash" json:"hash,omitempty"`
+}
+
+// chainEntry returns the entry as its hash chain sees it. Only chained
+// entries have a link.
+func (e *AuditEntry) chainEntry() *auditchain.Entry {
+	ce := &auditchain.Entry{
+		ID:              e.ID,
+		EntityType:      e.EntityType,
+		EntityID:        e.EntityID,
+		Action:          e.Action,
+		FieldChanges:    e.FieldChanges,
+		Metadata:        e.Metadata,
+		PerformedBy:     e.PerformedBy,
+		PerformedByName: e.PerformedByName,
+		IPAddress:       e.IPAddress,
+		CreatedAt:       e.CreatedAt,
+	}
+	if e.Sequence != nil && e.PrevHash != nil && e.Hash != nil {
+		ce.Sequence, ce.PrevHash, ce.Hash = *e.Sequence, *e.PrevHash, *e.Hash
+	}
+	return ce
 }
 
 // AuditTrailRepository handles GoBD-compliant audit trail persistence.
@@ -37,6 +67,8 @@ func NewAuditTrailRepository(db *database.DB) *AuditTrailRepository {
 }
 
 // Create creates a new audit trail entry (append-only, no update/delete)
+// and links it to the tenant's hash chain. The chain head stays locked until
+// the entry is committed, so concurrent entries get consecutive sequences.
 // TENANT-ISOLATED: Inserts with tenant_id for RLS
 func (r *AuditTrailRepository) Create(ctx context.Context, entry *AuditEntry) error {
 	tenantID, err := tenant.TenantID(ctx)
@@ -47,24 +79,95 @@ func (r *AuditTrailRepository) Create(ctx context.Context, entry *AuditEntry) er
 	if entry.ID == "" {
 		entry.ID = uuid.New().String()
 	}
+	entry.ID = auditchain.CanonicalUUID(entry.ID)
+	entry.EntityID = auditchain.CanonicalUUID(entry.EntityID)
+	entry.PerformedBy = auditchain.CanonicalUUIDPtr(entry.PerformedBy)
 
+	chain := auditchain.New(r.db, AuditChain, nil)
 	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		head, err := chain.Lock(ctx, tenantID)
+		if err != nil {
+			return err
+		}
+
+		entry.CreatedAt = auditchain.Now()
+		ce := entry.chainEntry()
+		ce.Sequence = head.Sequence + 1
+		content, err := ce.Content(tenantID)
+		if err != nil {
+			return err
+		}
+		next := head.Next(content)
+		entry.Sequence = &next.Sequence
+		entry.PrevHash = &head.Hash
+		entry.Hash = &next.Hash
+
 		query := `
 			INSERT INTO audit_trail (
 				id, tenant_id, entity_type, entity_id, action, field_changes,
-				metadata, performed_by, performed_by_name, ip_address
-			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-			RETURNING created_at
+				metadata, performed_by, performed_by_name, ip_address, created_at,
+				sequence, prev_hash, hash
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 		`
 
-		return r.db.QueryRowxContext(ctx, query,
+		_, err = r.db.ExecContext(ctx, query,
 			entry.ID, tenantID, entry.EntityType, entry.EntityID, entry.Action,
 			entry.FieldChanges, entry.Metadata, entry.PerformedBy,
-			entry.PerformedByName, entry.IPAddress,
-		).Scan(&entry.CreatedAt)
+			entry.PerformedByName, entry.IPAddress, entry.CreatedAt,
+			next.Sequence, head.Hash, next.Hash,
+		)
+		if err != nil {
+			return err
+		}
+
+		return chain.Advance(ctx, tenantID, next)
 	})
 }
 
+// Verify re-walks the tenant's hash chain and reports the first entry that
+// was changed, deleted or reordered, checking the chain head and its latest
+// anchor in the tenant audit log as well
+// TENANT-ISOLATED: Reads entries via RLS
+func (r *AuditTrailRepository) Verify(ctx context.Context) (*auditchain.Result, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *auditchain.Result
+	chain := auditchain.New(r.db, AuditChain, nil)
+	err = r.db.WithTenantRLS(database.ReadOnly(ctx), tenantID, func(ctx context.Context) error {
+		var err error
+		result, err = chain.Verify(ctx, tenantID, func(ctx context.Context, after, upto int64, limit int) ([]*auditchain.Entry, error) {
+			var entries []*AuditEntry
+			err := r.db.SelectContext(ctx, &entries, `
+				SELECT id, entity_type, entity_id, action, field_changes, metadata,
+				       performed_by, performed_by_name, ip_address, created_at,
+				       sequence, prev_hash, hash
+				FROM audit_trail
+				WHERE sequence > $1 AND sequence <= $2
+				ORDER BY sequence
+				LIMIT $3
+			`, after, upto, limit)
+			if err != nil {
+				return nil, err
+			}
+
+			chained := make([]*auditchain.Entry, len(entries))
+			for i, e := range entries {
+				chained[i] = e.chainEntry()
+			}
+			return chained, nil
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
 // ListByEntity lists audit entries for a specific entity with pagination
 // TENANT-ISOLATED: Returns only entries via RLS
 func (r *AuditTrailRepository) ListByEntity(ctx context.Context, entityType, entityID string, page, perPage int) ([]*AuditEntry, int64, error) {
@@ -85,7 +188,8 @@ func (r *AuditTrailRepository) ListByEntity(ctx context.Context, entityType, ent
 		offset := (page - 1) * perPage
 		query := `
 			SELECT id, entity_type, entity_id, action, field_changes, metadata,
-			       performed_by, performed_by_name, ip_address, created_at
+			       performed_by, performed_by_name, ip_address, created_at,
+			       sequence, prev_hash, hash
 			FROM audit_trail
 			WHERE entity_type = $1 AND entity_id = $2
 			ORDER BY created_at DESC
@@ -120,7 +224,8 @@ func (r *AuditTrailRepository) ListByTenant(ctx context.Context, entityType stri
 		countQuery := `SELECT COUNT(*) FROM audit_trail WHERE 1=1`
 		query := `
 			SELECT id, entity_type, entity_id, action, field_changes, metadata,
-			       performed_by, performed_by_name, ip_address, created_at
+			       performed_by, performed_by_name, ip_address, created_at,
+			       sequence, prev_hash, hash
 			FROM audit_trail WHERE 1=1
 		`
 
@@ -181,7 +286,8 @@ func (r *AuditTrailRepository) ExportGoBD(ctx context.Context, from, to *time.Ti
 
 		query := `
 			SELECT id, entity_type, entity_id, action, field_changes, metadata,
-			       performed_by, performed_by_name, ip_address, created_at
+			       performed_by, performed_by_name, ip_address, created_at,
+			       sequence, prev_hash, hash
 			FROM audit_trail WHERE 1=1
 		`
 
@@ -0,0 +1,174 @@
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/pkg/auditchain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createAuditEntries(t *testing.T, tenantCtx context.Context, repo *repository.AuditTrailRepository, n int) {
+	t.Helper()
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			changes := fmt.Sprintf(`{"quantity": {"old": %d, "new": %d}, "note": "Entnahme <OP 2>"}`, i+1, i)
+			errs <- repo.Create(tenantCtx, &repository.AuditEntry{
+				EntityType:   "item",
+				EntityID:     uuid.New().String(),
+				Action:       "update",
+				FieldChanges: &changes,
+			})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+}
+
+func TestAuditTrailRepository_ChainConcurrentWrites(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tenant := suite.SetupInventoryTenant(t, ctx, "audit-chain")
+	tenantCtx := suite.TenantContext(tenant)
+	repo := repository.NewAuditTrailRepository(suite.DB)
+
+	createAuditEntries(t, tenantCtx, repo, 20)
+
+	result, err := repo.Verify(tenantCtx)
+	require.NoError(t, err)
+	assert.True(t, result.Valid, "%+v", result.Break)
+	assert.Equal(t, int64(20), result.Entries)
+	assert.Equal(t, int64(20), result.Head.Sequence)
+
+	entries, total, err := repo.ListByTenant(tenantCtx, "item", nil, nil, 1, 100)
+	require.NoError(t, err)
+	assert.Equal(t, int64(20), total)
+	seen := map[int64]bool{}
+	for _, e := range entries {
+		require.NotNil(t, e.Sequence)
+		seen[*e.Sequence] = true
+	}
+	assert.Len(t, seen, 20)
+}
+
+func TestAuditTrailRepository_VerifyDetectsTampering(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tenant := suite.SetupInventoryTenant(t, ctx, "audit-tamper")
+	tenantCtx := suite.TenantContext(tenant)
+	repo := repository.NewAuditTrailRepository(suite.DB)
+
+	createAuditEntries(t, tenantCtx, repo, 5)
+
+	// The app role cannot change entries; only the database owner can
+	res, err := suite.DB.ExecContext(tenantCtx, `UPDATE inventory.audit_trail SET action = 'delete'`)
+	if err == nil {
+		affected, _ := res.RowsAffected()
+		assert.Zero(t, affected)
+	}
+
+	_, err = suite.RawDB.ExecContext(ctx, `
+		UPDATE inventory.audit_trail SET field_changes = '{"quantity": {"old": 99, "new": 0}}'
+		WHERE tenant_id = $1 AND sequence = 3
+	`, tenant.ID)
+	require.NoError(t, err)
+
+	result, err := repo.Verify(tenantCtx)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	require.NotNil(t, result.Break)
+	assert.Equal(t, int64(3), result.Break.Sequence)
+	assert.Equal(t, auditchain.BreakHash, result.Break.Reason)
+	assert.Equal(t, int64(2), result.Entries)
+
+	// Deleting the last entry is a truncation
+	other := suite.SetupInventoryTenant(t, ctx, "audit-truncate")
+	otherCtx := suite.TenantContext(other)
+	createAuditEntries(t, otherCtx, repo, 3)
+	_, err = suite.RawDB.ExecContext(ctx, `DELETE FROM inventory.audit_trail WHERE tenant_id = $1 AND sequence = 3`, other.ID)
+	require.NoError(t, err)
+
+	result, err = repo.Verify(otherCtx)
+	require.NoError(t, err)
+	require.NotNil(t, result.Break)
+	assert.Equal(t, auditchain.BreakHead, result.Break.Reason)
+}
+
+func TestAuditChain_Anchor(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tenant := suite.SetupInventoryTenant(t, ctx, "audit-anchor")
+	tenantCtx := suite.TenantContext(tenant)
+	repo := repository.NewAuditTrailRepository(suite.DB)
+	chain := auditchain.New(suite.DB, repository.AuditChain, suite.Logger)
+
+	// An empty chain is not anchored
+	require.NoError(t, chain.Anchor(tenantCtx))
+	var anchors int
+	require.NoError(t, suite.RawDB.GetContext(ctx, &anchors, `
+		SELECT COUNT(*) FROM public.tenant_audit_log WHERE tenant_id = $1 AND event_type = $2
+	`, tenant.ID, auditchain.AnchorEventType))
+	assert.Zero(t, anchors)
+
+	createAuditEntries(t, tenantCtx, repo, 4)
+	require.NoError(t, chain.Anchor(tenantCtx))
+	// An unchanged head is anchored once
+	require.NoError(t, chain.Anchor(tenantCtx))
+	require.NoError(t, suite.RawDB.GetContext(ctx, &anchors, `
+		SELECT COUNT(*) FROM public.tenant_audit_log WHERE tenant_id = $1 AND event_type = $2
+	`, tenant.ID, auditchain.AnchorEventType))
+	assert.Equal(t, 1, anchors)
+
+	result, err := repo.Verify(tenantCtx)
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	require.NotNil(t, result.Anchor)
+	assert.Equal(t, int64(4), result.Anchor.Sequence)
+
+	// Rewriting the chain from entry 2 on, head included, is caught by the
+	// anchor
+	var entries []struct {
+		ID       string `db:"id"`
+		Sequence int64  `db:"sequence"`
+	}
+	require.NoError(t, suite.RawDB.SelectContext(ctx, &entries, `
+		SELECT id, sequence FROM inventory.audit_trail WHERE tenant_id = $1 ORDER BY sequence
+	`, tenant.ID))
+	require.Len(t, entries, 4)
+	_, err = suite.RawDB.ExecContext(ctx, `DELETE FROM inventory.audit_trail WHERE tenant_id = $1 AND sequence >= 2`, tenant.ID)
+	require.NoError(t, err)
+	var head auditchain.Link
+	require.NoError(t, suite.RawDB.GetContext(ctx, &head, `
+		SELECT sequence, hash FROM inventory.audit_trail WHERE tenant_id = $1 AND sequence = 1
+	`, tenant.ID))
+	_, err = suite.RawDB.ExecContext(ctx, `
+		UPDATE public.audit_chain_heads SET sequence = $2, hash = $3 WHERE tenant_id = $1
+	`, tenant.ID, head.Sequence, head.Hash)
+	require.NoError(t, err)
+	createAuditEntries(t, tenantCtx, repo, 3)
+
+	result, err = repo.Verify(tenantCtx)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	require.NotNil(t, result.Break)
+	assert.Equal(t, auditchain.BreakAnchor, result.Break.Reason)
+	assert.Equal(t, int64(4), result.Break.Sequence)
+}
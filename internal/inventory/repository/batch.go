@@ -43,7 +43,11 @@ type StockAdjustment struct {
 	Quantity         int       `db:"quantity" json:"quantity"`
 	PreviousQuantity int       `db:"previous_quantity" json:"previous_quantity"`
 	NewQuantity      int       `db:"new_quantity" json:"new_quantity"`
+	FromLocationID   *string   `db:"from_location_id" json:"from_location_id,omitempty"`
+	ToLocationID     *string   `db:"to_location_id" json:"to_location_id,omitempty"`
 	Reason           *string   `db:"reason" json:"reason,omitempty"`
+	ReferenceType    *string   `db:"reference_type" json:"reference_type,omitempty"` // e.g. purchase_order
+	ReferenceID      *string   `db:"reference_id" json:"reference_id,omitempty"`
 	PerformedBy      string    `db:"performed_by" json:"performed_by"`
 	PerformedByName  *string   `db:"performed_by_name" json:"performed_by_name,omitempty"`
 	CreatedAt        time.Time `db:"created_at" json:"created_at"`
@@ -79,23 +83,36 @@ func (r *BatchRepository) Create(ctx context.Context, batch *InventoryBatch) err
 
 	// Execute query with tenant RLS
 	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
-		query := `
-			INSERT INTO inventory_batches (
-				id, tenant_id, item_id, location_id, batch_number, lot_number, initial_quantity,
-				current_quantity, reserved_quantity, manufactured_date, expiry_date,
-				received_date, opened_at, status
-			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
-			RETURNING created_at, updated_at
-		`
-
-		return r.db.QueryRowxContext(ctx, query,
-			batch.ID, tenantID, batch.ItemID, batch.LocationID, batch.BatchNumber, batch.LotNumber,
-			batch.InitialQuantity, batch.CurrentQuantity, batch.ReservedQuantity,
-			batch.ManufacturedDate, batch.ExpiryDate, batch.ReceivedDate, batch.OpenedAt, batch.Status,
-		).Scan(&batch.CreatedAt, &batch.UpdatedAt)
+		return insertBatch(ctx, r.db, tenantID, batch)
 	})
 }
 
+// insertBatch inserts a batch using the transaction in ctx, so that other
+// repositories can create batches as part of a larger booking
+func insertBatch(ctx context.Context, db *database.DB, tenantID string, batch *InventoryBatch) error {
+	if batch.ID == "" {
+		batch.ID = uuid.New().String()
+	}
+	if batch.Status == "" {
+		batch.Status = "available"
+	}
+
+	query := `
+		INSERT INTO inventory_batches (
+			id, tenant_id, item_id, location_id, batch_number, lot_number, initial_quantity,
+			current_quantity, reserved_quantity, manufactured_date, expiry_date,
+			received_date, opened_at, status
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING created_at, updated_at
+	`
+
+	return db.QueryRowxContext(ctx, query,
+		batch.ID, tenantID, batch.ItemID, batch.LocationID, batch.BatchNumber, batch.LotNumber,
+		batch.InitialQuantity, batch.CurrentQuantity, batch.ReservedQuantity,
+		batch.ManufacturedDate, batch.ExpiryDate, batch.ReceivedDate, batch.OpenedAt, batch.Status,
+	).Scan(&batch.CreatedAt, &batch.UpdatedAt)
+}
+
 // GetByID gets a batch by ID
 // TENANT-ISOLATED: Queries via RLS
 func (r *BatchRepository) GetByID(ctx context.Context, id string) (*InventoryBatch, error) {
@@ -166,6 +183,43 @@ func (r *BatchRepository) GetByBatchNumber(ctx context.Context, batchNumber stri
 	return &batch, nil
 }
 
+// GetByItemAndBatchNumber gets an item's batch by batch number. A batch
+// number received at several locations matches more than one row; the
+// available batch with the most stock is returned.
+// TENANT-ISOLATED: Returns only batches via RLS
+func (r *BatchRepository) GetByItemAndBatchNumber(ctx context.Context, itemID, batchNumber string) (*InventoryBatch, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var batch InventoryBatch
+
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			SELECT id, item_id, location_id, batch_number, lot_number, initial_quantity,
+			       current_quantity, reserved_quantity, manufactured_date, expiry_date,
+			       received_date, opened_at, status, created_at, updated_at
+			FROM inventory_batches
+			WHERE item_id = $1 AND batch_number = $2 AND deleted_at IS NULL
+			ORDER BY (status = 'available') DESC, current_quantity DESC, received_date
+			LIMIT 1
+		`
+		return r.db.GetContext(ctx, &batch, query, itemID, batchNumber)
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFound("batch")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	batch.Quantity = batch.CurrentQuantity
+
+	return &batch, nil
+}
+
 // ListByItem lists batches for an item
 // TENANT-ISOLATED: Returns only batches via RLS
 func (r *BatchRepository) ListByItem(ctx context.Context, itemID string) ([]*InventoryBatch, error) {
@@ -394,9 +448,9 @@ func (r *BatchRepository) AdjustStock(ctx context.Context, adj *StockAdjustment)
 		// If adjusting a specific batch, atomically lock + read + validate + update
 		if adj.BatchID != nil {
 			// Lock the row to prevent concurrent modifications (SELECT FOR UPDATE)
-			var currentQty int
-			lockQuery := `SELECT current_quantity FROM inventory_batches WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
-			if err := r.db.QueryRowxContext(ctx, lockQuery, *adj.BatchID).Scan(&currentQty); err != nil {
+			var currentQty, reservedQty int
+			lockQuery := `SELECT current_quantity, reserved_quantity FROM inventory_batches WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+			if err := r.db.QueryRowxContext(ctx, lockQuery, *adj.BatchID).Scan(&currentQty, &reservedQty); err != nil {
 				if err == sql.ErrNoRows {
 					return errors.NotFound("batch")
 				}
@@ -416,14 +470,23 @@ func (r *BatchRepository) AdjustStock(ctx context.Context, adj *StockAdjustment)
 			case "adjust":
 				newQty = adj.Quantity
 			default:
-				return errors.BadRequest("invalid adjustment type: " + adj.AdjustmentType)
+				return errors.BadRequest("errors.inventory.invalid_adjustment_type", map[string]string{"type": adj.AdjustmentType})
 			}
 
 			// Prevent negative stock
 			if newQty < 0 {
-				return errors.BadRequest("insufficient stock: cannot deduct " +
-					fmt.Sprintf("%d", adj.Quantity) + " from current stock of " +
-					fmt.Sprintf("%d", currentQty))
+				return errors.BadRequest("errors.inventory.insufficient_stock", map[string]string{
+					"quantity": fmt.Sprintf("%d", adj.Quantity),
+					"current":  fmt.Sprintf("%d", currentQty),
+				})
+			}
+
+			// Reserved stock is taken by consuming or releasing its reservation
+			if newQty < reservedQty {
+				return errors.Conflict("errors.inventory.reserved_stock", map[string]string{
+					"reserved":  fmt.Sprintf("%d", reservedQty),
+					"available": fmt.Sprintf("%d", currentQty-reservedQty),
+				})
 			}
 
 			adj.NewQuantity = newQty
@@ -442,26 +505,37 @@ func (r *BatchRepository) AdjustStock(ctx context.Context, adj *StockAdjustment)
 			case "adjust":
 				adj.NewQuantity = adj.Quantity
 			default:
-				return errors.BadRequest("invalid adjustment type: " + adj.AdjustmentType)
+				return errors.BadRequest("errors.inventory.invalid_adjustment_type", map[string]string{"type": adj.AdjustmentType})
 			}
 		}
 
 		// Create adjustment record
-		query := `
-			INSERT INTO stock_adjustments (
-				id, tenant_id, item_id, batch_id, adjustment_type, quantity, previous_quantity,
-				new_quantity, reason, performed_by, performed_by_name
-			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
-			RETURNING created_at
-		`
-
-		return r.db.QueryRowxContext(ctx, query,
-			adj.ID, tenantID, adj.ItemID, adj.BatchID, adj.AdjustmentType, adj.Quantity,
-			adj.PreviousQuantity, adj.NewQuantity, adj.Reason, adj.PerformedBy, adj.PerformedByName,
-		).Scan(&adj.CreatedAt)
+		return insertStockAdjustment(ctx, r.db, tenantID, adj)
 	})
 }
 
+// insertStockAdjustment records a stock booking using the transaction in ctx
+func insertStockAdjustment(ctx context.Context, db *database.DB, tenantID string, adj *StockAdjustment) error {
+	if adj.ID == "" {
+		adj.ID = uuid.New().String()
+	}
+
+	query := `
+		INSERT INTO stock_adjustments (
+			id, tenant_id, item_id, batch_id, adjustment_type, quantity, previous_quantity,
+			new_quantity, from_location_id, to_location_id, reason, reference_type, reference_id,
+			performed_by, performed_by_name
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		RETURNING created_at
+	`
+
+	return db.QueryRowxContext(ctx, query,
+		adj.ID, tenantID, adj.ItemID, adj.BatchID, adj.AdjustmentType, adj.Quantity,
+		adj.PreviousQuantity, adj.NewQuantity, adj.FromLocationID, adj.ToLocationID, adj.Reason,
+		adj.ReferenceType, adj.ReferenceID, adj.PerformedBy, adj.PerformedByName,
+	).Scan(&adj.CreatedAt)
+}
+
 // GetAllActiveBatches gets all active batches
 // TENANT-ISOLATED: Returns only active batches via RLS
 func (r *BatchRepository) GetAllActiveBatches(ctx context.Context) ([]*InventoryBatch, error) {
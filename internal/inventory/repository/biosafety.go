@@ -110,31 +110,44 @@ func (r *BioSafetyRepository) GetAssessment(ctx context.Context, id string) (*Bi
 	return &assessment, nil
 }
 
-// ListAssessmentsByItem lists risk assessments for an item
+// assessmentListSchema whitelists the filter and sort fields of the assessment list
+var assessmentListSchema = database.ListSchema{
+	Fields: map[string]database.Field{
+		"risk_group":      {Column: "risk_group", Type: database.FieldInt, Filter: true, Sort: true},
+		"assessment_date": {Column: "assessment_date", Type: database.FieldDate, Filter: true, Sort: true},
+		"assessor_name":   {Column: "assessor_name", Type: database.FieldText, Filter: true, Sort: true},
+		"valid_until":     {Column: "valid_until", Type: database.FieldDate, Filter: true, Sort: true, Nullable: true},
+		"created_at":      {Column: "created_at", Type: database.FieldTime, Filter: true, Sort: true},
+	},
+	DefaultSort: []string{"-assessment_date"},
+}
+
+// ListAssessmentsByItem lists risk assessments for an item filtered, sorted and paginated by q
 // TENANT-ISOLATED: Returns only assessments via RLS
-func (r *BioSafetyRepository) ListAssessmentsByItem(ctx context.Context, itemID string) ([]*BioRiskAssessment, error) {
+func (r *BioSafetyRepository) ListAssessmentsByItem(ctx context.Context, itemID string, q *database.ListQuery) (*database.Page[*BioRiskAssessment], error) {
 	tenantID, err := tenant.TenantID(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	var assessments []*BioRiskAssessment
+	var page *database.Page[*BioRiskAssessment]
 	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
 		query := `
 			SELECT id, item_id, risk_group, assessment_date, assessor_name,
 			       exposure_routes, protective_measures, operating_instructions_ref,
 			       valid_until, created_at, updated_at
 			FROM bio_risk_assessments WHERE item_id = $1 AND deleted_at IS NULL
-			ORDER BY assessment_date DESC
 		`
-		return r.db.SelectContext(ctx, &assessments, query, itemID)
+		var err error
+		page, err = database.SelectPage[*BioRiskAssessment](ctx, r.db, &assessmentListSchema, q, query, itemID)
+		return err
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	return assessments, nil
+	return page, nil
 }
 
 // UpdateAssessment updates a risk assessment
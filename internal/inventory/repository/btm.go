@@ -19,6 +19,7 @@ type BtmEntry struct {
 	ItemID             string    `db:"item_id" json:"item_id"`
 	EntryNumber        int       `db:"entry_number" json:"entry_number"`
 	EntryType          string    `db:"entry_type" json:"entry_type"` // receipt, dispense, disposal, correction, inventory_check
+	EntryDate          time.Time `db:"entry_date" json:"entry_date"` // day of the movement, may precede created_at
 	Quantity           float64   `db:"quantity" json:"quantity"`
 	RunningBalance     float64   `db:"running_balance" json:"running_balance"`
 	Unit               string    `db:"unit" json:"unit"`
@@ -49,8 +50,10 @@ func NewBtmRepository(db *database.DB) *BtmRepository {
 }
 
 // CreateEntry creates a new BtM register entry with auto-calculated entry number and running balance.
+// Entries dated into a closed period or before the latest inventory check of
+// the item are rejected, so the running balance follows the order of dates.
 // TENANT-ISOLATED: Inserts with tenant_id for RLS.
-// Uses FOR UPDATE locks to ensure sequential consistency.
+// Uses advisory locks to ensure sequential consistency.
 func (r *BtmRepository) CreateEntry(ctx context.Context, entry *BtmEntry) error {
 	tenantID, err := tenant.TenantID(ctx)
 	if err != nil {
@@ -62,18 +65,50 @@ func (r *BtmRepository) CreateEntry(ctx context.Context, entry *BtmEntry) error
 	}
 
 	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
-		// Get next entry number (locked for sequential consistency)
+		// Entries of different items run concurrently; a closing waits for them
+		if _, err := r.db.ExecContext(ctx, `SELECT pg_advisory_xact_lock_shared(hashtext('btm_closing:' || $1))`, tenantID); err != nil {
+			return err
+		}
+		closedThrough, err := r.ClosedThrough(database.InTx(ctx))
+		if err != nil {
+			return err
+		}
+		if closedThrough != nil && !entry.EntryDate.After(*closedThrough) {
+			return errors.Conflict("errors.btm.period_closed", map[string]string{
+				"closed_through": closedThrough.Format("2006-01-02"),
+			})
+		}
+
+		// Serialize entries of the item for gap-free numbering
+		if _, err := r.db.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('btm_register:' || $1))`, entry.ItemID); err != nil {
+			return err
+		}
+
+		// A check confirmed the balance on its date; an earlier entry would
+		// change it after the fact
+		var checkedOn sql.NullTime
+		checkQuery := `SELECT MAX(entry_date) FROM btm_register WHERE item_id = $1 AND entry_type = 'inventory_check'`
+		if err := r.db.GetContext(ctx, &checkedOn, checkQuery, entry.ItemID); err != nil {
+			return fmt.Errorf("failed to get last inventory check: %w", err)
+		}
+		if checkedOn.Valid && entry.EntryDate.Format("2006-01-02") < checkedOn.Time.Format("2006-01-02") {
+			return errors.Conflict("errors.btm.before_inventory_check", map[string]string{
+				"checked_on": checkedOn.Time.Format("2006-01-02"),
+			})
+		}
+
+		// Get next entry number
 		var entryNumber int
-		entryNumQuery := `SELECT COALESCE(MAX(entry_number), 0) + 1 FROM btm_register WHERE item_id = $1 FOR UPDATE`
+		entryNumQuery := `SELECT COALESCE(MAX(entry_number), 0) + 1 FROM btm_register WHERE item_id = $1`
 		if err := r.db.GetContext(ctx, &entryNumber, entryNumQuery, entry.ItemID); err != nil {
 			return fmt.Errorf("failed to get next entry number: %w", err)
 		}
 		entry.EntryNumber = entryNumber
 
-		// Get current running balance (locked for consistency)
+		// Get current running balance
 		var currentBalance sql.NullFloat64
-		balanceQuery := `SELECT running_balance FROM btm_register WHERE item_id = $1 ORDER BY entry_number DESC LIMIT 1 FOR UPDATE`
-		err := r.db.GetContext(ctx, &currentBalance, balanceQuery, entry.ItemID)
+		balanceQuery := `SELECT running_balance FROM btm_register WHERE item_id = $1 ORDER BY entry_number DESC LIMIT 1`
+		err = r.db.GetContext(ctx, &currentBalance, balanceQuery, entry.ItemID)
 		if err != nil && err != sql.ErrNoRows {
 			return fmt.Errorf("failed to get current balance: %w", err)
 		}
@@ -105,9 +140,9 @@ func (r *BtmRepository) CreateEntry(ctx context.Context, entry *BtmEntry) error
 				id, tenant_id, item_id, entry_number, entry_type, quantity, running_balance, unit,
 				supplier_name, delivery_note_number, patient_identifier, prescribing_doctor,
 				purpose, disposal_method, disposal_witness, correction_reason, corrects_entry_id,
-				performed_by, performed_by_name, notes
-			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
-			RETURNING created_at
+				performed_by, performed_by_name, notes, entry_date
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
+			RETURNING entry_date, created_at
 		`
 
 		return r.db.QueryRowxContext(ctx, query,
@@ -116,8 +151,8 @@ func (r *BtmRepository) CreateEntry(ctx context.Context, entry *BtmEntry) error
 			entry.SupplierName, entry.DeliveryNoteNumber, entry.PatientIdentifier,
 			entry.PrescribingDoctor, entry.Purpose, entry.DisposalMethod,
 			entry.DisposalWitness, entry.CorrectionReason, entry.CorrectsEntryID,
-			entry.PerformedBy, entry.PerformedByName, entry.Notes,
-		).Scan(&entry.CreatedAt)
+			entry.PerformedBy, entry.PerformedByName, entry.Notes, entry.EntryDate.Format("2006-01-02"),
+		).Scan(&entry.EntryDate, &entry.CreatedAt)
 	})
 }
 
@@ -140,7 +175,7 @@ func (r *BtmRepository) ListByItem(ctx context.Context, itemID string, page, per
 
 		offset := (page - 1) * perPage
 		query := `
-			SELECT id, item_id, entry_number, entry_type, quantity, running_balance, unit,
+			SELECT id, item_id, entry_number, entry_type, entry_date, quantity, running_balance, unit,
 			       supplier_name, delivery_note_number, patient_identifier, prescribing_doctor,
 			       purpose, disposal_method, disposal_witness, correction_reason, corrects_entry_id,
 			       performed_by, performed_by_name, notes, created_at
@@ -201,7 +236,7 @@ func (r *BtmRepository) GetLastEntry(ctx context.Context, itemID string) (*BtmEn
 
 	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
 		query := `
-			SELECT id, item_id, entry_number, entry_type, quantity, running_balance, unit,
+			SELECT id, item_id, entry_number, entry_type, entry_date, quantity, running_balance, unit,
 			       supplier_name, delivery_note_number, patient_identifier, prescribing_doctor,
 			       purpose, disposal_method, disposal_witness, correction_reason, corrects_entry_id,
 			       performed_by, performed_by_name, notes, created_at
@@ -95,6 +95,38 @@ func (r *BtmAuthRepository) List(ctx context.Context) ([]*BtmAuthorizedPerson, e
 	return persons, nil
 }
 
+// GetActiveByUserID gets the active (non-revoked) authorization of a user
+// TENANT-ISOLATED: Queries via RLS
+func (r *BtmAuthRepository) GetActiveByUserID(ctx context.Context, userID string) (*BtmAuthorizedPerson, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var person BtmAuthorizedPerson
+
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			SELECT id, user_id, user_name, authorization_type,
+			       authorized_by, authorized_by_name, authorized_at,
+			       revoked_at, revoked_by, revoked_by_name,
+			       created_at, updated_at
+			FROM btm_authorized_personnel
+			WHERE user_id = $1 AND revoked_at IS NULL
+		`
+		return r.db.GetContext(ctx, &person, query, userID)
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFound("btm_authorized_person")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &person, nil
+}
+
 // IsAuthorized checks if a user has sufficient authorization for BtM operations.
 // Authorization hierarchy: full > dispense_only > view_only
 // TENANT-ISOLATED: Queries via RLS
@@ -113,7 +145,7 @@ func (r *BtmAuthRepository) IsAuthorized(ctx context.Context, userID string, req
 
 	requiredLevel, ok := authLevel[requiredType]
 	if !ok {
-		return false, errors.BadRequest("invalid authorization type: " + requiredType)
+		return false, errors.BadRequest("errors.btm.invalid_authorization_type", map[string]string{"type": requiredType})
 	}
 
 	var authType sql.NullString
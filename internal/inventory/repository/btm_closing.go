@@ -0,0 +1,268 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/tenant"
+)
+
+// BtmPeriodClosing is the signed end-of-period verification of the BtM
+// register (BtMVV §13). It locks every entry dated on or before PeriodEnd.
+type BtmPeriodClosing struct {
+	ID              string              `db:"id" json:"id"`
+	PeriodStart     time.Time           `db:"period_start" json:"period_start"`
+	PeriodEnd       time.Time           `db:"period_end" json:"period_end"`
+	SubstanceCount  int                 `db:"substance_count" json:"substance_count"`
+	DifferenceCount int                 `db:"difference_count" json:"difference_count"`
+	Notes           *string             `db:"notes" json:"notes,omitempty"`
+	ClosedBy        string              `db:"closed_by" json:"closed_by"`
+	ClosedByName    string              `db:"closed_by_name" json:"closed_by_name"`
+	ClosedAt        time.Time           `db:"closed_at" json:"closed_at"`
+	ProtocolSHA256  string              `db:"protocol_sha256" json:"protocol_sha256"`
+	Balances        []*BtmPeriodBalance `db:"-" json:"balances,omitempty"`
+}
+
+// BtmPeriodBalance is the reconciliation of one substance over a period
+type BtmPeriodBalance struct {
+	ItemID          string  `db:"item_id" json:"item_id"`
+	ItemName        string  `db:"item_name" json:"item_name"`
+	Unit            string  `db:"unit" json:"unit"`
+	OpeningBalance  float64 `db:"opening_balance" json:"opening_balance"`
+	Receipts        float64 `db:"receipts" json:"receipts"`
+	Dispensed       float64 `db:"dispensed" json:"dispensed"`
+	Disposed        float64 `db:"disposed" json:"disposed"`
+	Corrections     float64 `db:"corrections" json:"corrections"`
+	CheckDifference float64 `db:"check_difference" json:"check_difference"` // counted minus book balance
+	ClosingBalance  float64 `db:"closing_balance" json:"closing_balance"`
+	EntryCount      int     `db:"entry_count" json:"entry_count"`
+	CheckCount      int     `db:"check_count" json:"check_count"`
+	DifferenceCount int     `db:"difference_count" json:"difference_count"`
+}
+
+// BtmSubstance is an item kept in the BtM register
+type BtmSubstance struct {
+	ID   string `db:"id" json:"id"`
+	Name string `db:"name" json:"name"`
+	Unit string `db:"unit" json:"unit"`
+}
+
+// Transaction runs fn in one tenant transaction; repository calls made with
+// the context fn receives join it
+// TENANT-ISOLATED: Runs via RLS
+func (r *BtmRepository) Transaction(ctx context.Context, fn func(context.Context) error) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		return fn(database.InTx(ctx))
+	})
+}
+
+// LockClosings waits for running register entries and blocks new ones for
+// the rest of the transaction
+// TENANT-ISOLATED: Runs via RLS
+func (r *BtmRepository) LockClosings(ctx context.Context) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		_, err := r.db.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('btm_closing:' || $1))`, tenantID)
+		return err
+	})
+}
+
+// ClosedThrough returns the last day of the latest closed period, or nil
+// when no period has been closed
+// TENANT-ISOLATED: Queries via RLS
+func (r *BtmRepository) ClosedThrough(ctx context.Context) (*time.Time, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var closedThrough sql.NullTime
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		return r.db.GetContext(ctx, &closedThrough, `SELECT MAX(period_end) FROM btm_period_closings`)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !closedThrough.Valid {
+		return nil, nil
+	}
+	return &closedThrough.Time, nil
+}
+
+// ListSubstances lists the controlled substances and every other item with
+// register entries dated on or before through, ordered by name
+// TENANT-ISOLATED: Queries via RLS
+func (r *BtmRepository) ListSubstances(ctx context.Context, through time.Time) ([]*BtmSubstance, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var substances []*BtmSubstance
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			SELECT i.id, i.name, i.unit
+			FROM inventory_items i
+			WHERE (i.is_controlled_substance AND i.deleted_at IS NULL)
+			   OR EXISTS (SELECT 1 FROM btm_register b WHERE b.item_id = i.id AND b.entry_date <= $1)
+			ORDER BY i.name, i.id
+		`
+		return r.db.SelectContext(ctx, &substances, query, through.Format("2006-01-02"))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return substances, nil
+}
+
+// ListEntriesThrough lists the register entries of an item dated on or
+// before through, in order of their date and entry number
+// TENANT-ISOLATED: Queries via RLS
+func (r *BtmRepository) ListEntriesThrough(ctx context.Context, itemID string, through time.Time) ([]*BtmEntry, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*BtmEntry
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			SELECT id, item_id, entry_number, entry_type, entry_date, quantity, running_balance, unit,
+			       supplier_name, delivery_note_number, patient_identifier, prescribing_doctor,
+			       purpose, disposal_method, disposal_witness, correction_reason, corrects_entry_id,
+			       performed_by, performed_by_name, notes, created_at
+			FROM btm_register
+			WHERE item_id = $1 AND entry_date <= $2
+			ORDER BY entry_date ASC, entry_number ASC
+		`
+		return r.db.SelectContext(ctx, &entries, query, itemID, through.Format("2006-01-02"))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// CreateClosing inserts a closing with its balances
+// TENANT-ISOLATED: Inserts with tenant_id for RLS
+func (r *BtmRepository) CreateClosing(ctx context.Context, c *BtmPeriodClosing) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			INSERT INTO btm_period_closings (
+				id, tenant_id, period_start, period_end, substance_count, difference_count, notes,
+				closed_by, closed_by_name, closed_at, protocol_sha256
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		`
+		if _, err := r.db.ExecContext(ctx, query,
+			c.ID, tenantID, c.PeriodStart.Format("2006-01-02"), c.PeriodEnd.Format("2006-01-02"),
+			c.SubstanceCount, c.DifferenceCount, c.Notes, c.ClosedBy, c.ClosedByName, c.ClosedAt, c.ProtocolSHA256,
+		); err != nil {
+			if appErr := database.MapPQError(err); appErr != nil {
+				return appErr
+			}
+			return err
+		}
+
+		balanceQuery := `
+			INSERT INTO btm_period_balances (
+				closing_id, tenant_id, item_id, item_name, unit, opening_balance, receipts, dispensed,
+				disposed, corrections, check_difference, closing_balance, entry_count, check_count,
+				difference_count
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		`
+		for _, b := range c.Balances {
+			if _, err := r.db.ExecContext(ctx, balanceQuery,
+				c.ID, tenantID, b.ItemID, b.ItemName, b.Unit, b.OpeningBalance, b.Receipts, b.Dispensed,
+				b.Disposed, b.Corrections, b.CheckDifference, b.ClosingBalance, b.EntryCount, b.CheckCount,
+				b.DifferenceCount,
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListClosings lists the closings, latest period first
+// TENANT-ISOLATED: Queries via RLS
+func (r *BtmRepository) ListClosings(ctx context.Context) ([]*BtmPeriodClosing, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	closings := []*BtmPeriodClosing{}
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			SELECT id, period_start, period_end, substance_count, difference_count, notes,
+			       closed_by, closed_by_name, closed_at, protocol_sha256
+			FROM btm_period_closings
+			ORDER BY period_start DESC
+		`
+		return r.db.SelectContext(ctx, &closings, query)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return closings, nil
+}
+
+// GetClosing gets a closing with its balances
+// TENANT-ISOLATED: Queries via RLS
+func (r *BtmRepository) GetClosing(ctx context.Context, id string) (*BtmPeriodClosing, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var c BtmPeriodClosing
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			SELECT id, period_start, period_end, substance_count, difference_count, notes,
+			       closed_by, closed_by_name, closed_at, protocol_sha256
+			FROM btm_period_closings
+			WHERE id = $1
+		`
+		if err := r.db.GetContext(ctx, &c, query, id); err != nil {
+			return err
+		}
+
+		balanceQuery := `
+			SELECT item_id, item_name, unit, opening_balance, receipts, dispensed, disposed,
+			       corrections, check_difference, closing_balance, entry_count, check_count,
+			       difference_count
+			FROM btm_period_balances
+			WHERE closing_id = $1
+			ORDER BY item_name, item_id
+		`
+		return r.db.SelectContext(ctx, &c.Balances, balanceQuery, id)
+	})
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFound("btm_period_closing")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
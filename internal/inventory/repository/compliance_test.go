@@ -45,6 +45,39 @@ func createTestItem(t *testing.T, tenantCtx context.Context, repo *repository.It
 	return item
 }
 
+// createTestLocations creates a room with an ambient and a cooled cabinet
+// for tests that store batches
+func createTestLocations(t *testing.T, tenantCtx context.Context) (room *repository.StorageRoom, ambient, fridge *repository.StorageCabinet) {
+	t.Helper()
+	locRepo := repository.NewLocationRepository(suite.DB)
+
+	room = &repository.StorageRoom{Name: "Lager", IsActive: true}
+	require.NoError(t, locRepo.CreateRoom(tenantCtx, room))
+	ambient = &repository.StorageCabinet{RoomID: room.ID, Name: "Regalschrank", IsActive: true}
+	require.NoError(t, locRepo.CreateCabinet(tenantCtx, ambient))
+	fridge = &repository.StorageCabinet{RoomID: room.ID, Name: "Medikamentenkühlschrank", TemperatureControlled: true, IsActive: true}
+	require.NoError(t, locRepo.CreateCabinet(tenantCtx, fridge))
+	return room, ambient, fridge
+}
+
+// createTestBatch creates an available batch of an item at a location that
+// expires in a year
+func createTestBatch(t *testing.T, tenantCtx context.Context, itemID, locationID string, quantity int) *repository.InventoryBatch {
+	t.Helper()
+	expiry := time.Now().AddDate(1, 0, 0).UTC().Truncate(24 * time.Hour)
+	batch := &repository.InventoryBatch{
+		ItemID:          itemID,
+		LocationID:      &locationID,
+		BatchNumber:     "CH-2025-01",
+		InitialQuantity: quantity,
+		CurrentQuantity: quantity,
+		ExpiryDate:      &expiry,
+		ReceivedDate:    time.Now().UTC(),
+	}
+	require.NoError(t, repository.NewBatchRepository(suite.DB).Create(tenantCtx, batch))
+	return batch
+}
+
 func strPtr(s string) *string {
 	return &s
 }
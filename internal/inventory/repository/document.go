@@ -8,6 +8,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/medflow/medflow-backend/pkg/database"
 	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/scan"
+	"github.com/medflow/medflow-backend/pkg/storage"
 	"github.com/medflow/medflow-backend/pkg/tenant"
 )
 
@@ -20,6 +22,8 @@ type ItemDocument struct {
 	FilePath      string    `db:"file_path" json:"-"`
 	FileSizeBytes *int      `db:"file_size_bytes" json:"file_size_bytes,omitempty"`
 	MimeType      *string   `db:"mime_type" json:"mime_type,omitempty"`
+	SHA256        *string   `db:"sha256" json:"sha256,omitempty"`
+	ScanStatus    string    `db:"scan_status" json:"scan_status"`
 	UploadedAt    time.Time `db:"uploaded_at" json:"uploaded_at"`
 	UploadedBy    *string   `db:"uploaded_by" json:"uploaded_by,omitempty"`
 	CreatedAt     time.Time `db:"created_at" json:"created_at"`
@@ -52,14 +56,14 @@ func (r *DocumentRepository) Create(ctx context.Context, doc *ItemDocument) erro
 		query := `
 			INSERT INTO item_documents (
 				id, tenant_id, item_id, document_type, file_name, file_path,
-				file_size_bytes, mime_type, uploaded_by
-			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+				file_size_bytes, mime_type, sha256, scan_status, uploaded_by
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 			RETURNING uploaded_at, created_at, updated_at
 		`
 
 		return r.db.QueryRowxContext(ctx, query,
 			doc.ID, tenantID, doc.ItemID, doc.DocumentType, doc.FileName,
-			doc.FilePath, doc.FileSizeBytes, doc.MimeType, doc.UploadedBy,
+			doc.FilePath, doc.FileSizeBytes, doc.MimeType, doc.SHA256, doc.ScanStatus, doc.UploadedBy,
 		).Scan(&doc.UploadedAt, &doc.CreatedAt, &doc.UpdatedAt)
 	})
 }
@@ -76,7 +80,7 @@ func (r *DocumentRepository) GetByID(ctx context.Context, id string) (*ItemDocum
 	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
 		query := `
 			SELECT id, item_id, document_type, file_name, file_path, file_size_bytes,
-			       mime_type, uploaded_at, uploaded_by, created_at, updated_at
+			       mime_type, sha256, scan_status, uploaded_at, uploaded_by, created_at, updated_at
 			FROM item_documents
 			WHERE id = $1 AND deleted_at IS NULL
 		`
@@ -105,7 +109,7 @@ func (r *DocumentRepository) ListByItem(ctx context.Context, itemID string) ([]*
 	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
 		query := `
 			SELECT id, item_id, document_type, file_name, file_path, file_size_bytes,
-			       mime_type, uploaded_at, uploaded_by, created_at, updated_at
+			       mime_type, sha256, scan_status, uploaded_at, uploaded_by, created_at, updated_at
 			FROM item_documents
 			WHERE item_id = $1 AND deleted_at IS NULL
 			ORDER BY uploaded_at DESC
@@ -142,3 +146,44 @@ func (r *DocumentRepository) Delete(ctx context.Context, id string) error {
 		return nil
 	})
 }
+
+// ListPendingScans lists documents still awaiting a malware scan, oldest first
+func (r *DocumentRepository) ListPendingScans(ctx context.Context, limit int) ([]storage.PendingScan, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []storage.PendingScan
+
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			SELECT id, file_path
+			FROM item_documents
+			WHERE scan_status = $1 AND deleted_at IS NULL
+			ORDER BY uploaded_at
+			LIMIT $2
+		`
+		return r.db.SelectContext(ctx, &docs, query, scan.StatusPending, limit)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return docs, nil
+}
+
+// SetScanStatus records the malware scan outcome of a document
+func (r *DocumentRepository) SetScanStatus(ctx context.Context, id, status string) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `UPDATE item_documents SET scan_status = $2, scanned_at = NOW(), updated_at = NOW() WHERE id = $1`
+		_, err := r.db.ExecContext(ctx, query, id, status)
+		return err
+	})
+}
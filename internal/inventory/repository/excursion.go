@@ -0,0 +1,416 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/tenant"
+)
+
+// Excursion statuses
+const (
+	ExcursionPending   = "pending"   // out of range, within the grace period
+	ExcursionTransient = "transient" // back in range within the grace period
+	ExcursionConfirmed = "confirmed" // out of range longer than the grace period
+	ExcursionReleased  = "released"  // quarantined stock released after assessment
+	ExcursionDiscarded = "discarded" // quarantined stock to be disposed of
+)
+
+// TemperatureExcursion is an episode of consecutive readings of a cabinet
+// outside its temperature range
+type TemperatureExcursion struct {
+	ID              string     `db:"id" json:"id"`
+	CabinetID       string     `db:"cabinet_id" json:"cabinet_id"`
+	Status          string     `db:"status" json:"status"`
+	StartedAt       time.Time  `db:"started_at" json:"started_at"`
+	EndedAt         *time.Time `db:"ended_at" json:"ended_at,omitempty"` // nil while ongoing
+	LastReadingAt   time.Time  `db:"last_reading_at" json:"last_reading_at"`
+	DurationSeconds int        `db:"duration_seconds" json:"duration_seconds"`
+	ReadingCount    int        `db:"reading_count" json:"reading_count"`
+	MinTemperature  float64    `db:"min_temperature_celsius" json:"min_temperature_celsius"`
+	MaxTemperature  float64    `db:"max_temperature_celsius" json:"max_temperature_celsius"`
+	AllowedMin      *float64   `db:"allowed_min_celsius" json:"allowed_min_celsius,omitempty"`
+	AllowedMax      *float64   `db:"allowed_max_celsius" json:"allowed_max_celsius,omitempty"`
+	GraceMinutes    int        `db:"grace_minutes" json:"grace_minutes"`
+	ConfirmedAt     *time.Time `db:"confirmed_at" json:"confirmed_at,omitempty"`
+	AlertID         *string    `db:"alert_id" json:"alert_id,omitempty"`
+	DecisionReason  *string    `db:"decision_reason" json:"decision_reason,omitempty"`
+	DecidedBy       *string    `db:"decided_by" json:"decided_by,omitempty"`
+	DecidedAt       *time.Time `db:"decided_at" json:"decided_at,omitempty"`
+	CreatedAt       time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time  `db:"updated_at" json:"updated_at"`
+
+	// Joined
+	CabinetName   string  `db:"cabinet_name" json:"cabinet_name"`
+	DecidedByName *string `db:"decided_by_name" json:"decided_by_name,omitempty"` // ListForPeriod only
+
+	Batches []*ExcursionBatch `db:"-" json:"batches,omitempty"`
+}
+
+// ExcursionBatch is a batch quarantined by a confirmed excursion
+type ExcursionBatch struct {
+	BatchID        string    `db:"batch_id" json:"batch_id"`
+	PreviousStatus string    `db:"previous_status" json:"previous_status"`
+	QuarantinedAt  time.Time `db:"quarantined_at" json:"quarantined_at"`
+
+	// Joined from the batch
+	ItemID          string  `db:"item_id" json:"item_id"`
+	ItemName        string  `db:"item_name" json:"item_name"`
+	BatchNumber     string  `db:"batch_number" json:"batch_number"`
+	LocationID      *string `db:"location_id" json:"location_id,omitempty"`
+	CurrentQuantity int     `db:"current_quantity" json:"current_quantity"`
+	Status          string  `db:"status" json:"status"`
+}
+
+// ExcursionRepository handles temperature excursion persistence
+type ExcursionRepository struct {
+	db *database.DB
+}
+
+// NewExcursionRepository creates a new excursion repository
+func NewExcursionRepository(db *database.DB) *ExcursionRepository {
+	return &ExcursionRepository{db: db}
+}
+
+const excursionSelect = `
+	SELECT e.id, e.cabinet_id, e.status, e.started_at, e.ended_at, e.last_reading_at,
+	       e.duration_seconds, e.reading_count, e.min_temperature_celsius, e.max_temperature_celsius,
+	       e.allowed_min_celsius, e.allowed_max_celsius, e.grace_minutes, e.confirmed_at, e.alert_id,
+	       e.decision_reason, e.decided_by, e.decided_at, e.created_at, e.updated_at,
+	       c.name AS cabinet_name
+	FROM temperature_excursions e
+	JOIN storage_cabinets c ON c.id = e.cabinet_id
+`
+
+// Transaction runs fn in one tenant transaction; repository calls made with
+// the context fn receives join it
+// TENANT-ISOLATED: Runs via RLS
+func (r *ExcursionRepository) Transaction(ctx context.Context, fn func(context.Context) error) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		return fn(database.InTx(ctx))
+	})
+}
+
+// LockCabinet locks a cabinet for the rest of the transaction, so readings
+// of one cabinet are tracked one request at a time
+// TENANT-ISOLATED: Queries via RLS
+func (r *ExcursionRepository) LockCabinet(ctx context.Context, cabinetID string) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		var id string
+		err := r.db.GetContext(ctx, &id, `SELECT id FROM storage_cabinets WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, cabinetID)
+		if err == sql.ErrNoRows {
+			return errors.NotFound("cabinet")
+		}
+		return err
+	})
+}
+
+// GetOngoing gets the excursion of a cabinet that has not ended, or nil
+// TENANT-ISOLATED: Queries via RLS
+func (r *ExcursionRepository) GetOngoing(ctx context.Context, cabinetID string) (*TemperatureExcursion, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var e TemperatureExcursion
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		return r.db.GetContext(ctx, &e, excursionSelect+` WHERE e.cabinet_id = $1 AND e.ended_at IS NULL`, cabinetID)
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// Save inserts a new excursion (empty ID) or updates the tracked fields of
+// an existing one
+// TENANT-ISOLATED: Upserts with tenant_id for RLS
+func (r *ExcursionRepository) Save(ctx context.Context, e *TemperatureExcursion) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		if e.ID == "" {
+			e.ID = uuid.New().String()
+			query := `
+				INSERT INTO temperature_excursions (
+					id, tenant_id, cabinet_id, status, started_at, ended_at, last_reading_at,
+					duration_seconds, reading_count, min_temperature_celsius, max_temperature_celsius,
+					allowed_min_celsius, allowed_max_celsius, grace_minutes, confirmed_at, alert_id
+				) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+				RETURNING created_at, updated_at
+			`
+			return r.db.QueryRowxContext(ctx, query,
+				e.ID, tenantID, e.CabinetID, e.Status, e.StartedAt, e.EndedAt, e.LastReadingAt,
+				e.DurationSeconds, e.ReadingCount, e.MinTemperature, e.MaxTemperature,
+				e.AllowedMin, e.AllowedMax, e.GraceMinutes, e.ConfirmedAt, e.AlertID,
+			).Scan(&e.CreatedAt, &e.UpdatedAt)
+		}
+
+		query := `
+			UPDATE temperature_excursions SET
+				status = $2, started_at = $3, ended_at = $4, last_reading_at = $5, duration_seconds = $6,
+				reading_count = $7, min_temperature_celsius = $8, max_temperature_celsius = $9,
+				confirmed_at = $10, alert_id = $11
+			WHERE id = $1
+			RETURNING updated_at
+		`
+		err := r.db.QueryRowxContext(ctx, query,
+			e.ID, e.Status, e.StartedAt, e.EndedAt, e.LastReadingAt, e.DurationSeconds,
+			e.ReadingCount, e.MinTemperature, e.MaxTemperature, e.ConfirmedAt, e.AlertID,
+		).Scan(&e.UpdatedAt)
+		if err == sql.ErrNoRows {
+			return errors.NotFound("temperature_excursion")
+		}
+		return err
+	})
+}
+
+// GetByID gets an excursion with its quarantined batches
+// TENANT-ISOLATED: Queries via RLS
+func (r *ExcursionRepository) GetByID(ctx context.Context, id string) (*TemperatureExcursion, error) {
+	return r.get(ctx, id, "")
+}
+
+// Lock locks an excursion for the rest of the transaction and loads its
+// quarantined batches
+// TENANT-ISOLATED: Queries via RLS
+func (r *ExcursionRepository) Lock(ctx context.Context, id string) (*TemperatureExcursion, error) {
+	return r.get(ctx, id, ` FOR UPDATE OF e`)
+}
+
+func (r *ExcursionRepository) get(ctx context.Context, id, lock string) (*TemperatureExcursion, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var e TemperatureExcursion
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		if err := r.db.GetContext(ctx, &e, excursionSelect+` WHERE e.id = $1`+lock, id); err != nil {
+			return err
+		}
+		return r.db.SelectContext(ctx, &e.Batches, `
+			SELECT eb.batch_id, eb.previous_status, eb.quarantined_at,
+			       b.item_id, i.name AS item_name, b.batch_number, b.location_id, b.current_quantity, b.status
+			FROM temperature_excursion_batches eb
+			JOIN inventory_batches b ON b.id = eb.batch_id
+			JOIN inventory_items i ON i.id = b.item_id
+			WHERE eb.excursion_id = $1
+			ORDER BY i.name, b.batch_number
+		`, id)
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFound("temperature_excursion")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// excursionListSchema whitelists the filter and sort fields of GET /temperature/excursions
+var excursionListSchema = database.ListSchema{
+	Fields: map[string]database.Field{
+		"cabinet_id":       {Column: "e.cabinet_id", Type: database.FieldUUID, Filter: true},
+		"cabinet_name":     {Column: "c.name", Type: database.FieldText, Filter: true, Sort: true},
+		"status":           {Column: "e.status", Type: database.FieldText, Filter: true, Sort: true},
+		"started_at":       {Column: "e.started_at", Type: database.FieldTime, Filter: true, Sort: true},
+		"ended_at":         {Column: "e.ended_at", Type: database.FieldTime, Filter: true, Sort: true, Nullable: true},
+		"duration_seconds": {Column: "e.duration_seconds", Type: database.FieldInt, Filter: true, Sort: true},
+	},
+	DefaultSort: []string{"-started_at"},
+	IDColumn:    "e.id",
+}
+
+// List lists excursions (without batches) filtered, sorted and paginated by q
+// TENANT-ISOLATED: Returns only excursions via RLS
+func (r *ExcursionRepository) List(ctx context.Context, q *database.ListQuery) (*database.Page[*TemperatureExcursion], error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var page *database.Page[*TemperatureExcursion]
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		var err error
+		page, err = database.SelectPage[*TemperatureExcursion](ctx, r.db, &excursionListSchema, q, excursionSelect+` WHERE 1=1`)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// ListForPeriod lists the excursions of a cabinet that overlap [from, to),
+// oldest first, with the name of who decided them
+// TENANT-ISOLATED: Returns only excursions via RLS
+func (r *ExcursionRepository) ListForPeriod(ctx context.Context, cabinetID string, from, to time.Time) ([]*TemperatureExcursion, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var excursions []*TemperatureExcursion
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			SELECT e.id, e.cabinet_id, e.status, e.started_at, e.ended_at, e.last_reading_at,
+			       e.duration_seconds, e.reading_count, e.min_temperature_celsius, e.max_temperature_celsius,
+			       e.allowed_min_celsius, e.allowed_max_celsius, e.grace_minutes, e.confirmed_at, e.alert_id,
+			       e.decision_reason, e.decided_by, e.decided_at, e.created_at, e.updated_at,
+			       c.name AS cabinet_name,
+			       NULLIF(TRIM(CONCAT(u.first_name, ' ', u.last_name)), '') AS decided_by_name
+			FROM temperature_excursions e
+			JOIN storage_cabinets c ON c.id = e.cabinet_id
+			LEFT JOIN user_cache u ON u.user_id = e.decided_by
+			WHERE e.cabinet_id = $1 AND e.started_at < $3 AND (e.ended_at IS NULL OR e.ended_at >= $2)
+			ORDER BY e.started_at
+		`
+		return r.db.SelectContext(ctx, &excursions, query, cabinetID, from, to)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return excursions, nil
+}
+
+// ListOverdue lists the ongoing pending excursions whose grace period had
+// passed at now, oldest first
+// TENANT-ISOLATED: Returns only excursions via RLS
+func (r *ExcursionRepository) ListOverdue(ctx context.Context, now time.Time) ([]*TemperatureExcursion, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var excursions []*TemperatureExcursion
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		return r.db.SelectContext(ctx, &excursions, excursionSelect+`
+			WHERE e.status = 'pending' AND e.ended_at IS NULL
+			  AND e.started_at + make_interval(mins => e.grace_minutes) <= $1
+			ORDER BY e.started_at
+		`, now)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return excursions, nil
+}
+
+// QuarantineCabinetBatches puts the usable batches of items that require
+// cooling stored in a cabinet (or on its shelves) into quarantine and links
+// them to the excursion. Batches already quarantined by another excursion
+// awaiting a decision are linked too, with the status they had before it.
+// TENANT-ISOLATED: Updates and inserts via RLS
+func (r *ExcursionRepository) QuarantineCabinetBatches(ctx context.Context, excursionID, cabinetID string) ([]string, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var batchIDs []string
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			WITH pending_decision AS (
+				SELECT DISTINCT ON (eb.batch_id) eb.batch_id, eb.previous_status
+				FROM temperature_excursion_batches eb
+				JOIN temperature_excursions e ON e.id = eb.excursion_id
+				WHERE e.status = 'confirmed'
+				ORDER BY eb.batch_id, eb.quarantined_at
+			), targets AS (
+				SELECT b.id, COALESCE(p.previous_status, b.status) AS previous_status
+				FROM inventory_batches b
+				JOIN inventory_items i ON i.id = b.item_id
+				LEFT JOIN pending_decision p ON p.batch_id = b.id
+				WHERE i.requires_cooling AND b.deleted_at IS NULL AND b.current_quantity > 0
+				  AND (b.location_id = $2 OR b.location_id IN (
+				      SELECT id FROM storage_shelves WHERE cabinet_id = $2 AND deleted_at IS NULL))
+				  AND (b.status IN ('available', 'reserved') OR (b.status = 'quarantine' AND p.batch_id IS NOT NULL))
+			), linked AS (
+				INSERT INTO temperature_excursion_batches (excursion_id, batch_id, tenant_id, previous_status)
+				SELECT $1, id, $3, previous_status FROM targets
+				ON CONFLICT DO NOTHING
+				RETURNING batch_id
+			)
+			UPDATE inventory_batches SET status = 'quarantine', updated_at = NOW()
+			WHERE id IN (SELECT batch_id FROM linked)
+			RETURNING id
+		`
+		return r.db.SelectContext(ctx, &batchIDs, query, excursionID, cabinetID, tenantID)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return batchIDs, nil
+}
+
+// Decide records the release decision of a confirmed excursion. Releasing
+// returns its batches to the status they had before, unless another
+// excursion still holds them; discarded batches stay in quarantine until
+// they are booked out.
+// TENANT-ISOLATED: Updates via RLS
+func (r *ExcursionRepository) Decide(ctx context.Context, e *TemperatureExcursion) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		err := r.db.QueryRowxContext(ctx, `
+			UPDATE temperature_excursions
+			SET status = $2, decision_reason = $3, decided_by = $4, decided_at = NOW()
+			WHERE id = $1 AND status = 'confirmed'
+			RETURNING decided_at, updated_at
+		`, e.ID, e.Status, e.DecisionReason, e.DecidedBy).Scan(&e.DecidedAt, &e.UpdatedAt)
+		if err == sql.ErrNoRows {
+			return errors.NotFound("temperature_excursion")
+		}
+		if err != nil {
+			if appErr := database.MapPQError(err); appErr != nil {
+				return appErr
+			}
+			return err
+		}
+
+		if e.Status != ExcursionReleased {
+			return nil
+		}
+		_, err = r.db.ExecContext(ctx, `
+			UPDATE inventory_batches b SET status = eb.previous_status, updated_at = NOW()
+			FROM temperature_excursion_batches eb
+			WHERE eb.excursion_id = $1 AND b.id = eb.batch_id AND b.status = 'quarantine'
+			  AND NOT EXISTS (
+			      SELECT 1 FROM temperature_excursion_batches other
+			      JOIN temperature_excursions oe ON oe.id = other.excursion_id
+			      WHERE other.batch_id = b.id AND other.excursion_id <> $1
+			        AND oe.status IN ('confirmed', 'discarded'))
+		`, e.ID)
+		return err
+	})
+}
@@ -0,0 +1,316 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/tenant"
+)
+
+// consumptionAdjustmentTypes are the adjustment types that count as
+// consumption; disposals, transfers and count corrections do not
+const consumptionAdjustmentTypes = `('deduct', 'issue')`
+
+// DailyConsumption is the quantity of an item consumed on one day (UTC)
+type DailyConsumption struct {
+	Day      time.Time `db:"day" json:"day"`
+	Quantity int       `db:"quantity" json:"quantity"`
+}
+
+// ConsumptionForecast is the latest demand forecast of an item and the
+// reorder values it suggests
+type ConsumptionForecast struct {
+	ID                       string          `db:"id" json:"id"`
+	ItemID                   string          `db:"item_id" json:"item_id"`
+	HistoryDays              int             `db:"history_days" json:"history_days"`
+	DailyDemand              float64         `db:"daily_demand" json:"daily_demand"`
+	WeekdayFactors           pq.Float64Array `db:"weekday_factors" json:"weekday_factors"` // Sunday first
+	StdDev                   float64         `db:"std_dev" json:"std_dev"`
+	LeadTimeDays             int             `db:"lead_time_days" json:"lead_time_days"`
+	ServiceLevel             float64         `db:"service_level" json:"service_level"`
+	CoverageDays             int             `db:"coverage_days" json:"coverage_days"`
+	LeadTimeDemand           float64         `db:"lead_time_demand" json:"lead_time_demand"`
+	SafetyStock              float64         `db:"safety_stock" json:"safety_stock"`
+	SuggestedReorderPoint    int             `db:"suggested_reorder_point" json:"suggested_reorder_point"`
+	SuggestedReorderQuantity int             `db:"suggested_reorder_quantity" json:"suggested_reorder_quantity"`
+	CurrentStock             int             `db:"current_stock" json:"current_stock"`
+	ProjectedStockoutDate    *time.Time      `db:"projected_stockout_date" json:"projected_stockout_date,omitempty"`
+	ComputedAt               time.Time       `db:"computed_at" json:"computed_at"`
+	AcceptedAt               *time.Time      `db:"accepted_at" json:"accepted_at,omitempty"`
+	AcceptedBy               *string         `db:"accepted_by" json:"accepted_by,omitempty"`
+
+	// Joined from the item
+	ItemName        string `db:"item_name" json:"item_name"`
+	Unit            string `db:"unit" json:"unit"`
+	ReorderPoint    *int   `db:"reorder_point" json:"reorder_point,omitempty"`
+	ReorderQuantity *int   `db:"reorder_quantity" json:"reorder_quantity,omitempty"`
+	MinStock        int    `db:"min_stock" json:"min_stock"`
+}
+
+// ForecastRepository handles consumption history and forecasts
+type ForecastRepository struct {
+	db *database.DB
+}
+
+// NewForecastRepository creates a new forecast repository
+func NewForecastRepository(db *database.DB) *ForecastRepository {
+	return &ForecastRepository{db: db}
+}
+
+// DailyConsumption aggregates the consumption of an item per practice day
+// (Europe/Berlin) from the day of since; days without consumption are left
+// out
+// TENANT-ISOLATED: Queries via RLS
+func (r *ForecastRepository) DailyConsumption(ctx context.Context, itemID string, since time.Time) ([]*DailyConsumption, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var days []*DailyConsumption
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			SELECT (created_at AT TIME ZONE 'Europe/Berlin')::date AS day, SUM(quantity) AS quantity
+			FROM stock_adjustments
+			WHERE item_id = $1 AND adjustment_type IN ` + consumptionAdjustmentTypes + `
+			  AND created_at >= date_trunc('day', $2::timestamptz AT TIME ZONE 'Europe/Berlin') AT TIME ZONE 'Europe/Berlin'
+			GROUP BY day
+			ORDER BY day
+		`
+		return r.db.SelectContext(ctx, &days, query, itemID, since)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return days, nil
+}
+
+// LeadTimeDays returns the lead time of the supplier an item is ordered
+// from, or nil if no active supplier is linked
+// TENANT-ISOLATED: Queries via RLS
+func (r *ForecastRepository) LeadTimeDays(ctx context.Context, itemID string) (*int, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var days int
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		// Same supplier choice as SupplierRepository.GetSourcing
+		query := `
+			SELECT s.lead_time_days
+			FROM supplier_items si
+			JOIN suppliers s ON s.id = si.supplier_id
+			WHERE si.item_id = $1 AND s.is_active = TRUE AND s.deleted_at IS NULL
+			ORDER BY si.is_preferred DESC, si.unit_price_cents ASC NULLS LAST
+			LIMIT 1
+		`
+		return r.db.GetContext(ctx, &days, query, itemID)
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &days, nil
+}
+
+// Upsert stores the forecast of an item, replacing the previous one. A
+// changed suggestion clears the acceptance.
+// TENANT-ISOLATED: Inserts with tenant_id for RLS
+func (r *ForecastRepository) Upsert(ctx context.Context, f *ConsumptionForecast) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			INSERT INTO consumption_forecasts (
+				id, tenant_id, item_id, history_days, daily_demand, weekday_factors, std_dev,
+				lead_time_days, service_level, coverage_days, lead_time_demand, safety_stock,
+				suggested_reorder_point, suggested_reorder_quantity, current_stock, projected_stockout_date,
+				computed_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, NOW())
+			ON CONFLICT (tenant_id, item_id) DO UPDATE SET
+				history_days = EXCLUDED.history_days,
+				daily_demand = EXCLUDED.daily_demand,
+				weekday_factors = EXCLUDED.weekday_factors,
+				std_dev = EXCLUDED.std_dev,
+				lead_time_days = EXCLUDED.lead_time_days,
+				service_level = EXCLUDED.service_level,
+				coverage_days = EXCLUDED.coverage_days,
+				lead_time_demand = EXCLUDED.lead_time_demand,
+				safety_stock = EXCLUDED.safety_stock,
+				suggested_reorder_point = EXCLUDED.suggested_reorder_point,
+				suggested_reorder_quantity = EXCLUDED.suggested_reorder_quantity,
+				current_stock = EXCLUDED.current_stock,
+				projected_stockout_date = EXCLUDED.projected_stockout_date,
+				computed_at = EXCLUDED.computed_at,
+				accepted_at = CASE
+					WHEN consumption_forecasts.suggested_reorder_point = EXCLUDED.suggested_reorder_point
+					 AND consumption_forecasts.suggested_reorder_quantity = EXCLUDED.suggested_reorder_quantity
+					THEN consumption_forecasts.accepted_at END,
+				accepted_by = CASE
+					WHEN consumption_forecasts.suggested_reorder_point = EXCLUDED.suggested_reorder_point
+					 AND consumption_forecasts.suggested_reorder_quantity = EXCLUDED.suggested_reorder_quantity
+					THEN consumption_forecasts.accepted_by END
+			RETURNING id, computed_at, accepted_at, accepted_by
+		`
+		return r.db.QueryRowxContext(ctx, query,
+			uuid.New().String(), tenantID, f.ItemID, f.HistoryDays, f.DailyDemand, f.WeekdayFactors, f.StdDev,
+			f.LeadTimeDays, f.ServiceLevel, f.CoverageDays, f.LeadTimeDemand, f.SafetyStock,
+			f.SuggestedReorderPoint, f.SuggestedReorderQuantity, f.CurrentStock, f.ProjectedStockoutDate,
+		).Scan(&f.ID, &f.ComputedAt, &f.AcceptedAt, &f.AcceptedBy)
+	})
+}
+
+const forecastSelect = `
+	SELECT f.id, f.item_id, f.history_days, f.daily_demand, f.weekday_factors, f.std_dev,
+	       f.lead_time_days, f.service_level, f.coverage_days, f.lead_time_demand, f.safety_stock,
+	       f.suggested_reorder_point, f.suggested_reorder_quantity, f.current_stock, f.projected_stockout_date,
+	       f.computed_at, f.accepted_at, f.accepted_by,
+	       i.name AS item_name, i.unit, i.reorder_point, i.reorder_quantity, i.min_stock
+	FROM consumption_forecasts f
+	JOIN inventory_items i ON i.id = f.item_id AND i.deleted_at IS NULL
+`
+
+// GetByItem gets the stored forecast of an item
+// TENANT-ISOLATED: Queries via RLS
+func (r *ForecastRepository) GetByItem(ctx context.Context, itemID string) (*ConsumptionForecast, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var f ConsumptionForecast
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		return r.db.GetContext(ctx, &f, forecastSelect+` WHERE f.item_id = $1`, itemID)
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFound("consumption_forecast")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &f, nil
+}
+
+// forecastListSchema whitelists the filter and sort fields of GET /forecasts
+var forecastListSchema = database.ListSchema{
+	Fields: map[string]database.Field{
+		"item_id":                    {Column: "item_id", Type: database.FieldUUID, Filter: true},
+		"item_name":                  {Column: "item_name", Type: database.FieldText, Filter: true, Sort: true},
+		"daily_demand":               {Column: "daily_demand", Type: database.FieldNumeric, Filter: true, Sort: true},
+		"suggested_reorder_point":    {Column: "suggested_reorder_point", Type: database.FieldInt, Filter: true, Sort: true},
+		"suggested_reorder_quantity": {Column: "suggested_reorder_quantity", Type: database.FieldInt, Filter: true, Sort: true},
+		"projected_stockout_date":    {Column: "projected_stockout_date", Type: database.FieldDate, Filter: true, Sort: true, Nullable: true},
+		"accepted_at":                {Column: "accepted_at", Type: database.FieldTime, Filter: true, Sort: true, Nullable: true},
+		"computed_at":                {Column: "computed_at", Type: database.FieldTime, Filter: true, Sort: true},
+	},
+	DefaultSort: []string{"projected_stockout_date", "item_name"},
+}
+
+// List lists stored forecasts filtered, sorted and paginated by q
+// TENANT-ISOLATED: Returns only forecasts via RLS
+func (r *ForecastRepository) List(ctx context.Context, q *database.ListQuery) (*database.Page[*ConsumptionForecast], error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var page *database.Page[*ConsumptionForecast]
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		// Wrapped in a subquery so item columns can be filtered and sorted on
+		query := `SELECT * FROM (` + forecastSelect + `) AS forecasts WHERE 1=1`
+		var err error
+		page, err = database.SelectPage[*ConsumptionForecast](ctx, r.db, &forecastListSchema, q, query)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// ListStockOuts lists forecasts projecting a stock-out before a date,
+// soonest first
+// TENANT-ISOLATED: Queries via RLS
+func (r *ForecastRepository) ListStockOuts(ctx context.Context, before time.Time, limit int) ([]*ConsumptionForecast, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var forecasts []*ConsumptionForecast
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := forecastSelect + `
+			WHERE f.projected_stockout_date IS NOT NULL AND f.projected_stockout_date < $1 AND i.is_active = TRUE
+			ORDER BY f.projected_stockout_date, i.name
+			LIMIT $2
+		`
+		return r.db.SelectContext(ctx, &forecasts, query, before, limit)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return forecasts, nil
+}
+
+// Accept copies the suggested reorder point and quantity of the stored
+// forecasts of itemIDs to the items in one transaction. Items without a
+// forecast are skipped; the accepted forecasts are returned.
+// TENANT-ISOLATED: Updates via RLS
+func (r *ForecastRepository) Accept(ctx context.Context, itemIDs []string, acceptedBy *string) ([]*ConsumptionForecast, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var accepted []*ConsumptionForecast
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			UPDATE inventory_items i
+			SET reorder_point = f.suggested_reorder_point,
+			    reorder_quantity = f.suggested_reorder_quantity
+			FROM consumption_forecasts f
+			WHERE f.item_id = i.id AND i.id = ANY($1) AND i.deleted_at IS NULL
+		`
+		if _, err := r.db.ExecContext(ctx, query, pq.Array(itemIDs)); err != nil {
+			return err
+		}
+
+		query = `
+			UPDATE consumption_forecasts
+			SET accepted_at = NOW(), accepted_by = $2
+			WHERE item_id = ANY($1)
+		`
+		if _, err := r.db.ExecContext(ctx, query, pq.Array(itemIDs), acceptedBy); err != nil {
+			return err
+		}
+
+		return r.db.SelectContext(ctx, &accepted, forecastSelect+` WHERE f.item_id = ANY($1) ORDER BY i.name`, pq.Array(itemIDs))
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return accepted, nil
+}
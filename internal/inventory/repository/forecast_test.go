@@ -0,0 +1,92 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/pkg/forecast"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Consumption Forecast Tests ---
+
+func TestForecastRepository_ConsumptionAndAccept(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tenant := suite.SetupInventoryTenant(t, ctx, "forecast-accept")
+	tenantCtx := suite.TenantContext(tenant)
+
+	itemRepo := repository.NewItemRepository(suite.DB)
+	batchRepo := repository.NewBatchRepository(suite.DB)
+	forecastRepo := repository.NewForecastRepository(suite.DB)
+
+	item := createTestItem(t, tenantCtx, itemRepo, "Einmalspritze 5 ml")
+	_, ambient, _ := createTestLocations(t, tenantCtx)
+	batch := createTestBatch(t, tenantCtx, item.ID, ambient.ID, 50)
+
+	// Deductions count as consumption, count corrections do not
+	for _, adj := range []*repository.StockAdjustment{
+		{AdjustmentType: "deduct", Quantity: 3},
+		{AdjustmentType: "deduct", Quantity: 2},
+		{AdjustmentType: "count", Quantity: 4},
+	} {
+		adj.ItemID = item.ID
+		adj.BatchID = &batch.ID
+		adj.PerformedBy = "user-1"
+		require.NoError(t, batchRepo.AdjustStock(tenantCtx, adj))
+	}
+
+	days, err := forecastRepo.DailyConsumption(tenantCtx, item.ID, time.Now().AddDate(0, 0, -7))
+	require.NoError(t, err)
+	require.Len(t, days, 1)
+	assert.Equal(t, 5, days[0].Quantity)
+	// Bucketed by practice day, as forecast.Day counts them
+	assert.Equal(t, forecast.Day(time.Now()), days[0].Day.UTC())
+
+	// Stored forecasts show up as stock-outs and can be accepted
+	stockout := time.Now().AddDate(0, 0, 10).UTC().Truncate(24 * time.Hour)
+	f := &repository.ConsumptionForecast{
+		ItemID:                   item.ID,
+		HistoryDays:              90,
+		DailyDemand:              4.5,
+		WeekdayFactors:           []float64{0, 1.4, 1.4, 1.4, 1.4, 1.4, 0},
+		LeadTimeDays:             7,
+		ServiceLevel:             0.95,
+		CoverageDays:             30,
+		SuggestedReorderPoint:    40,
+		SuggestedReorderQuantity: 135,
+		CurrentStock:             45,
+		ProjectedStockoutDate:    &stockout,
+	}
+	require.NoError(t, forecastRepo.Upsert(tenantCtx, f))
+
+	stockOuts, err := forecastRepo.ListStockOuts(tenantCtx, time.Now().AddDate(0, 0, 30), 10)
+	require.NoError(t, err)
+	require.Len(t, stockOuts, 1)
+	assert.Equal(t, item.Name, stockOuts[0].ItemName)
+	assert.Len(t, stockOuts[0].WeekdayFactors, 7)
+
+	userID := "00000000-0000-0000-0000-000000000001"
+	accepted, err := forecastRepo.Accept(tenantCtx, []string{item.ID}, &userID)
+	require.NoError(t, err)
+	require.Len(t, accepted, 1)
+	assert.NotNil(t, accepted[0].AcceptedAt)
+
+	updated, err := itemRepo.GetByID(tenantCtx, item.ID)
+	require.NoError(t, err)
+	require.NotNil(t, updated.ReorderPoint)
+	assert.Equal(t, 40, *updated.ReorderPoint)
+	assert.Equal(t, 135, *updated.ReorderQuantity)
+
+	// An unchanged suggestion keeps the acceptance, a changed one clears it
+	require.NoError(t, forecastRepo.Upsert(tenantCtx, f))
+	assert.NotNil(t, f.AcceptedAt)
+	f.SuggestedReorderPoint = 42
+	require.NoError(t, forecastRepo.Upsert(tenantCtx, f))
+	assert.Nil(t, f.AcceptedAt)
+}
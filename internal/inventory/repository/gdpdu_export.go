@@ -0,0 +1,288 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/gdpdu"
+	"github.com/medflow/medflow-backend/pkg/tenant"
+)
+
+// GDPdU export statuses
+const (
+	GDPdUExportPending   = "pending"
+	GDPdUExportRunning   = "running"
+	GDPdUExportCompleted = "completed"
+	GDPdUExportFailed    = "failed"
+)
+
+// gdpduStaleAfter is how long a running export may take before the job
+// assumes its instance died and builds it again
+const gdpduStaleAfter = 2 * time.Hour
+
+// GDPdUExport is a requested data export for a tax audit
+type GDPdUExport struct {
+	ID              string     `db:"id" json:"id"`
+	PeriodFrom      time.Time  `db:"period_from" json:"period_from"`
+	PeriodTo        time.Time  `db:"period_to" json:"period_to"`
+	Status          string     `db:"status" json:"status"`
+	FileKey         *string    `db:"file_key" json:"-"`
+	FileSize        *int64     `db:"file_size" json:"file_size,omitempty"`
+	SHA256          *string    `db:"sha256" json:"sha256,omitempty"`
+	RecordCount     *int64     `db:"record_count" json:"record_count,omitempty"`
+	Manifest        *string    `db:"manifest" json:"manifest,omitempty"`
+	Error           *string    `db:"error" json:"error,omitempty"`
+	RequestedBy     *string    `db:"requested_by" json:"requested_by,omitempty"`
+	RequestedByName *string    `db:"requested_by_name" json:"requested_by_name,omitempty"`
+	CreatedAt       time.Time  `db:"created_at" json:"created_at"`
+	StartedAt       *time.Time `db:"started_at" json:"started_at,omitempty"`
+	CompletedAt     *time.Time `db:"completed_at" json:"completed_at,omitempty"`
+}
+
+// GDPdUExportRepository handles GDPdU export persistence and reads the
+// exported data
+type GDPdUExportRepository struct {
+	db *database.DB
+}
+
+// NewGDPdUExportRepository creates a new GDPdU export repository
+func NewGDPdUExportRepository(db *database.DB) *GDPdUExportRepository {
+	return &GDPdUExportRepository{db: db}
+}
+
+const gdpduExportColumns = `
+	id, period_from, period_to, status, file_key, file_size, sha256, record_count,
+	manifest, error, requested_by, requested_by_name, created_at, started_at, completed_at
+`
+
+const gdpduExportSelect = `SELECT` + gdpduExportColumns + `FROM gdpdu_exports`
+
+// Create creates a pending export
+// TENANT-ISOLATED: Inserts with tenant_id for RLS
+func (r *GDPdUExportRepository) Create(ctx context.Context, e *GDPdUExport) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	if e.ID == "" {
+		e.ID = uuid.New().String()
+	}
+	e.Status = GDPdUExportPending
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			INSERT INTO gdpdu_exports (
+				id, tenant_id, period_from, period_to, status, requested_by, requested_by_name
+			) VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING created_at
+		`
+		err := r.db.QueryRowxContext(ctx, query,
+			e.ID, tenantID, e.PeriodFrom, e.PeriodTo, e.Status, e.RequestedBy, e.RequestedByName,
+		).Scan(&e.CreatedAt)
+		if appErr := database.MapPQError(err); appErr != nil {
+			return appErr
+		}
+		return err
+	})
+}
+
+// GetByID gets an export by ID
+// TENANT-ISOLATED: Queries via RLS
+func (r *GDPdUExportRepository) GetByID(ctx context.Context, id string) (*GDPdUExport, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var e GDPdUExport
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		return r.db.GetContext(ctx, &e, gdpduExportSelect+` WHERE id = $1`, id)
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFound("gdpdu_export")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &e, nil
+}
+
+var gdpduExportListSchema = database.ListSchema{
+	Fields: map[string]database.Field{
+		"status":      {Column: "status", Type: database.FieldText, Filter: true, Sort: true},
+		"period_from": {Column: "period_from", Type: database.FieldDate, Filter: true, Sort: true},
+		"period_to":   {Column: "period_to", Type: database.FieldDate, Filter: true, Sort: true},
+		"created_at":  {Column: "created_at", Type: database.FieldTime, Filter: true, Sort: true},
+	},
+	DefaultSort: []string{"-created_at"},
+}
+
+// List lists exports filtered, sorted and paginated by q
+// TENANT-ISOLATED: Returns only exports via RLS
+func (r *GDPdUExportRepository) List(ctx context.Context, q *database.ListQuery) (*database.Page[*GDPdUExport], error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var page *database.Page[*GDPdUExport]
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		var err error
+		page, err = database.SelectPage[*GDPdUExport](ctx, r.db, &gdpduExportListSchema, q,
+			gdpduExportSelect+` WHERE 1=1`)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// ClaimNext marks the oldest pending export (or a running one whose build
+// was abandoned) as running and returns it, or nil if there is none
+// TENANT-ISOLATED: Updates via RLS
+func (r *GDPdUExportRepository) ClaimNext(ctx context.Context) (*GDPdUExport, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var e GDPdUExport
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		return r.db.GetContext(ctx, &e, `
+			UPDATE gdpdu_exports SET status = 'running', started_at = NOW(), error = NULL
+			WHERE id = (
+				SELECT id FROM gdpdu_exports
+				WHERE status = 'pending' OR (status = 'running' AND started_at < $1)
+				ORDER BY created_at
+				LIMIT 1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING`+gdpduExportColumns, time.Now().Add(-gdpduStaleAfter))
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &e, nil
+}
+
+// Complete records the built ZIP of an export
+// TENANT-ISOLATED: Updates via RLS
+func (r *GDPdUExportRepository) Complete(ctx context.Context, e *GDPdUExport) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		return r.db.GetContext(ctx, e, `
+			UPDATE gdpdu_exports
+			SET status = 'completed', file_key = $2, file_size = $3, sha256 = $4,
+			    record_count = $5, manifest = $6, completed_at = NOW()
+			WHERE id = $1
+			RETURNING`+gdpduExportColumns,
+			e.ID, e.FileKey, e.FileSize, e.SHA256, e.RecordCount, e.Manifest)
+	})
+}
+
+// Fail records why an export could not be built
+// TENANT-ISOLATED: Updates via RLS
+func (r *GDPdUExportRepository) Fail(ctx context.Context, id, reason string) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		_, err := r.db.ExecContext(ctx, `
+			UPDATE gdpdu_exports SET status = 'failed', error = $2, completed_at = NOW()
+			WHERE id = $1
+		`, id, reason)
+		return err
+	})
+}
+
+// DataSupplier returns the tenant as the supplier of the exported data
+// TENANT-ISOLATED: Reads the caller's tenant record only
+func (r *GDPdUExportRepository) DataSupplier(ctx context.Context) (gdpdu.DataSupplier, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return gdpdu.DataSupplier{}, err
+	}
+
+	var supplier gdpdu.DataSupplier
+	err = r.db.WithTenantRLS(database.ReadOnly(ctx), tenantID, func(ctx context.Context) error {
+		return r.db.QueryRowxContext(ctx, `
+			SELECT name,
+			       CONCAT_WS(', ', NULLIF(street, ''), NULLIF(CONCAT_WS(' ', postal_code, city), ''))
+			FROM public.tenants WHERE id = $1
+		`, tenantID).Scan(&supplier.Name, &supplier.Location)
+	})
+	return supplier, err
+}
+
+// WriteTables writes the tax-relevant inventory data of the export's period
+// to w, from one snapshot of the database
+// TENANT-ISOLATED: Reads via RLS
+func (r *GDPdUExportRepository) WriteTables(ctx context.Context, e *GDPdUExport, w *gdpdu.Writer) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithTenantRLS(database.ReadOnly(ctx), tenantID, func(ctx context.Context) error {
+		if _, err := r.db.ExecContext(ctx, `SET TRANSACTION ISOLATION LEVEL REPEATABLE READ`); err != nil {
+			return err
+		}
+
+		for _, t := range gdpduTables {
+			table := t.table
+			var args []interface{}
+			if t.period {
+				table.From, table.To = &e.PeriodFrom, &e.PeriodTo
+				args = []interface{}{e.PeriodFrom.Format("2006-01-02"), e.PeriodTo.Format("2006-01-02")}
+			}
+			if err := r.writeTable(ctx, w, &table, t.query, args...); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// writeTable streams the rows of query into the table's CSV file
+func (r *GDPdUExportRepository) writeTable(ctx context.Context, w *gdpdu.Writer, t *gdpdu.Table, query string, args ...interface{}) error {
+	rows, err := r.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	err = w.WriteTable(t, func(write gdpdu.WriteFunc) error {
+		for rows.Next() {
+			values, err := rows.SliceScan()
+			if err != nil {
+				return err
+			}
+			if err := write(values...); err != nil {
+				return err
+			}
+		}
+		return rows.Err()
+	})
+	return err
+}
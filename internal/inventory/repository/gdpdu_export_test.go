@@ -0,0 +1,98 @@
+package repository_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/pkg/gdpdu"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- GDPdU Export Tests ---
+
+func TestGDPdUExportRepository_ClaimAndWrite(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tenant := suite.SetupInventoryTenant(t, ctx, "gdpdu-export")
+	tenantCtx := suite.TenantContext(tenant)
+
+	itemRepo := repository.NewItemRepository(suite.DB)
+	batchRepo := repository.NewBatchRepository(suite.DB)
+	exportRepo := repository.NewGDPdUExportRepository(suite.DB)
+
+	item := createTestItem(t, tenantCtx, itemRepo, "Handschuhe; Nitril")
+	_, ambient, _ := createTestLocations(t, tenantCtx)
+	batch := createTestBatch(t, tenantCtx, item.ID, ambient.ID, 20)
+	require.NoError(t, batchRepo.AdjustStock(tenantCtx, &repository.StockAdjustment{
+		ItemID: item.ID, BatchID: &batch.ID, AdjustmentType: "deduct", Quantity: 3, PerformedBy: "user-1",
+	}))
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	e := &repository.GDPdUExport{PeriodFrom: today.AddDate(0, 0, -1), PeriodTo: today.AddDate(0, 0, 1)}
+	require.NoError(t, exportRepo.Create(tenantCtx, e))
+	assert.Equal(t, repository.GDPdUExportPending, e.Status)
+
+	// The pending export is claimed once
+	claimed, err := exportRepo.ClaimNext(tenantCtx)
+	require.NoError(t, err)
+	require.NotNil(t, claimed)
+	assert.Equal(t, e.ID, claimed.ID)
+	assert.Equal(t, repository.GDPdUExportRunning, claimed.Status)
+
+	none, err := exportRepo.ClaimNext(tenantCtx)
+	require.NoError(t, err)
+	assert.Nil(t, none)
+
+	supplier, err := exportRepo.DataSupplier(tenantCtx)
+	require.NoError(t, err)
+	assert.NotEmpty(t, supplier.Name)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w := gdpdu.NewWriter(zw, supplier)
+	require.NoError(t, exportRepo.WriteTables(tenantCtx, claimed, w))
+	manifest, err := w.Close()
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	files := map[string]string{}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+		files[f.Name] = string(content)
+	}
+
+	assert.Contains(t, files["artikel.csv"], item.ID+`;`)
+	assert.Contains(t, files["artikel.csv"], `"Handschuhe; Nitril"`)
+	assert.Contains(t, files["chargen.csv"], batch.ID+`;`+item.ID)
+	assert.Contains(t, files["lagerbewegungen.csv"], `;deduct;3;`)
+	assert.Contains(t, files, gdpdu.IndexFile)
+	assert.Contains(t, files, gdpdu.ChecksumsFile)
+	assert.Positive(t, manifest.Records())
+
+	// Completing records the stored ZIP
+	key, size, sum, records, m := "tenants/x/export.zip", int64(buf.Len()), strings.Repeat("a", 64), manifest.Records(), `{"files": []}`
+	claimed.FileKey, claimed.FileSize, claimed.SHA256, claimed.RecordCount, claimed.Manifest = &key, &size, &sum, &records, &m
+	require.NoError(t, exportRepo.Complete(tenantCtx, claimed))
+	assert.Equal(t, repository.GDPdUExportCompleted, claimed.Status)
+	assert.NotNil(t, claimed.CompletedAt)
+
+	got, err := exportRepo.GetByID(tenantCtx, e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, repository.GDPdUExportCompleted, got.Status)
+	assert.Equal(t, records, *got.RecordCount)
+}
@@ -0,0 +1,292 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/medflow/medflow-backend/pkg/gdpdu"
+)
+
+// Tables of a GDPdU export. Master data (items, batches, suppliers) is
+// exported in full, so every foreign key resolves; movements and the audit
+// trail are limited to the period ($1, $2 as DATE, in German local time).
+// Column order in the queries must match the table definitions.
+
+// berlinDate and berlinTime split a timestamp into a date and a time of day
+// in German local time: GDPdU dates carry no time
+const (
+	berlinDate = `(%[1]s AT TIME ZONE 'Europe/Berlin')::date`
+	berlinTime = `to_char(%[1]s AT TIME ZONE 'Europe/Berlin', 'HH24:MI:SS')`
+)
+
+func gdpduKey(name, description string) gdpdu.Column {
+	return gdpdu.Column{Name: name, Description: description, Type: gdpdu.AlphaNumeric, PrimaryKey: true}
+}
+
+func gdpduText(name, description string) gdpdu.Column {
+	return gdpdu.Column{Name: name, Description: description, Type: gdpdu.AlphaNumeric}
+}
+
+func gdpduNumber(name, description string) gdpdu.Column {
+	return gdpdu.Column{Name: name, Description: description, Type: gdpdu.Numeric}
+}
+
+func gdpduAmount(name, description string) gdpdu.Column {
+	return gdpdu.Column{Name: name, Description: description, Type: gdpdu.Numeric, Accuracy: 2}
+}
+
+func gdpduDate(name, description string) gdpdu.Column {
+	return gdpdu.Column{Name: name, Description: description, Type: gdpdu.Date}
+}
+
+type gdpduTable struct {
+	table  gdpdu.Table
+	query  string
+	period bool
+}
+
+var gdpduTables = []gdpduTable{
+	{
+		table: gdpdu.Table{
+			Name: "Artikel", File: "artikel.csv", Description: "Artikelstamm",
+			Columns: []gdpdu.Column{
+				gdpduKey("artikel_id", "Artikel-ID"),
+				gdpduText("artikelnummer", "Artikelnummer"),
+				gdpduText("bezeichnung", "Bezeichnung"),
+				gdpduText("kategorie", "Kategorie"),
+				gdpduText("hersteller", "Hersteller"),
+				gdpduText("lieferant", "Lieferant"),
+				gdpduText("barcode", "Barcode"),
+				gdpduText("einheit", "Mengeneinheit"),
+				gdpduAmount("einzelpreis", "Einzelpreis"),
+				gdpduText("waehrung", "Währung"),
+				gdpduNumber("chargenpflichtig", "Chargenpflichtig (1 = ja)"),
+				gdpduNumber("btm", "Betäubungsmittel (1 = ja)"),
+				gdpduNumber("aktiv", "Aktiv (1 = ja)"),
+				gdpduDate("angelegt_am", "Angelegt am"),
+				gdpduDate("geloescht_am", "Gelöscht am"),
+			},
+		},
+		query: `
+			SELECT id, article_number, name, category, manufacturer, supplier, barcode, unit,
+			       ROUND(unit_price_cents / 100.0, 2), currency,
+			       use_batch_tracking::int, is_controlled_substance::int, is_active::int,
+			       ` + fmt.Sprintf(berlinDate, "created_at") + `, ` + fmt.Sprintf(berlinDate, "deleted_at") + `
+			FROM inventory_items
+			ORDER BY article_number NULLS LAST, name, id
+		`,
+	},
+	{
+		table: gdpdu.Table{
+			Name: "Chargen", File: "chargen.csv", Description: "Chargen und Bestände",
+			Columns: []gdpdu.Column{
+				gdpduKey("charge_id", "Chargen-ID"),
+				gdpduText("artikel_id", "Artikel-ID"),
+				gdpduText("chargennummer", "Chargennummer"),
+				gdpduText("losnummer", "Losnummer"),
+				gdpduNumber("anfangsmenge", "Eingangsmenge"),
+				gdpduNumber("bestand", "Aktueller Bestand"),
+				gdpduDate("verfallsdatum", "Verfallsdatum"),
+				gdpduDate("eingangsdatum", "Eingangsdatum"),
+				gdpduText("status", "Status"),
+				gdpduDate("geloescht_am", "Gelöscht am"),
+			},
+			ForeignKeys: []gdpdu.ForeignKey{{Columns: []string{"artikel_id"}, References: "Artikel"}},
+		},
+		query: `
+			SELECT id, item_id, batch_number, lot_number, initial_quantity, current_quantity,
+			       expiry_date, received_date, status, ` + fmt.Sprintf(berlinDate, "deleted_at") + `
+			FROM inventory_batches
+			ORDER BY received_date, id
+		`,
+	},
+	{
+		table: gdpdu.Table{
+			Name: "Lieferanten", File: "lieferanten.csv", Description: "Lieferantenstamm",
+			Columns: []gdpdu.Column{
+				gdpduKey("lieferant_id", "Lieferanten-ID"),
+				gdpduText("name", "Name"),
+				gdpduText("kundennummer", "Eigene Kundennummer beim Lieferanten"),
+				gdpduText("anschrift", "Anschrift"),
+				gdpduNumber("aktiv", "Aktiv (1 = ja)"),
+			},
+		},
+		query: `
+			SELECT id, name, customer_number, address, is_active::int
+			FROM suppliers
+			ORDER BY name, id
+		`,
+	},
+	{
+		table: gdpdu.Table{
+			Name: "Bestellungen", File: "bestellungen.csv", Description: "Bestellungen",
+			Columns: []gdpdu.Column{
+				gdpduKey("bestellung_id", "Bestellungs-ID"),
+				gdpduText("bestellnummer", "Bestellnummer"),
+				gdpduText("lieferant_id", "Lieferanten-ID"),
+				gdpduText("status", "Status"),
+				gdpduDate("bestelldatum", "Angelegt am"),
+				gdpduDate("versanddatum", "Versendet am"),
+				gdpduDate("eingangsdatum", "Vollständig geliefert am"),
+				gdpduDate("stornodatum", "Storniert am"),
+				gdpduText("stornogrund", "Stornogrund"),
+			},
+			ForeignKeys: []gdpdu.ForeignKey{{Columns: []string{"lieferant_id"}, References: "Lieferanten"}},
+		},
+		query: `
+			SELECT id, order_number, supplier_id, status,
+			       ` + fmt.Sprintf(berlinDate, "created_at") + `, ` + fmt.Sprintf(berlinDate, "sent_at") + `,
+			       ` + fmt.Sprintf(berlinDate, "received_at") + `, ` + fmt.Sprintf(berlinDate, "cancelled_at") + `,
+			       cancel_reason
+			FROM purchase_orders
+			WHERE ` + gdpduOrderInPeriod + `
+			ORDER BY created_at, id
+		`,
+		period: true,
+	},
+	{
+		table: gdpdu.Table{
+			Name: "Bestellpositionen", File: "bestellpositionen.csv", Description: "Positionen der Bestellungen",
+			Columns: []gdpdu.Column{
+				gdpduKey("position_id", "Positions-ID"),
+				gdpduText("bestellung_id", "Bestellungs-ID"),
+				gdpduText("artikel_id", "Artikel-ID"),
+				gdpduNumber("menge_bestellt", "Bestellte Menge"),
+				gdpduNumber("menge_geliefert", "Gelieferte Menge"),
+				gdpduAmount("einzelpreis", "Einzelpreis"),
+				gdpduText("lieferanten_artikelnummer", "Artikelnummer des Lieferanten"),
+			},
+			ForeignKeys: []gdpdu.ForeignKey{
+				{Columns: []string{"bestellung_id"}, References: "Bestellungen"},
+				{Columns: []string{"artikel_id"}, References: "Artikel"},
+			},
+		},
+		query: `
+			SELECT l.id, l.purchase_order_id, l.item_id, l.quantity_ordered, l.quantity_received,
+			       ROUND(l.unit_price_cents / 100.0, 2), l.supplier_article_number
+			FROM purchase_order_lines l
+			JOIN purchase_orders ON purchase_orders.id = l.purchase_order_id
+			WHERE ` + gdpduOrderInPeriod + `
+			ORDER BY purchase_orders.created_at, l.purchase_order_id, l.created_at, l.id
+		`,
+		period: true,
+	},
+	{
+		table: gdpdu.Table{
+			Name: "Wareneingaenge", File: "wareneingaenge.csv", Description: "Wareneingänge",
+			Columns: []gdpdu.Column{
+				gdpduKey("wareneingang_id", "Wareneingangs-ID"),
+				gdpduText("bestellung_id", "Bestellungs-ID"),
+				gdpduText("lieferscheinnummer", "Lieferscheinnummer"),
+				gdpduDate("eingangsdatum", "Eingangsdatum"),
+				gdpduText("eingangszeit", "Eingangszeit"),
+				gdpduText("erfasst_von", "Erfasst von"),
+			},
+			ForeignKeys: []gdpdu.ForeignKey{{Columns: []string{"bestellung_id"}, References: "Bestellungen"}},
+		},
+		query: `
+			SELECT id, purchase_order_id, delivery_note_number,
+			       ` + fmt.Sprintf(berlinDate, "received_at") + `, ` + fmt.Sprintf(berlinTime, "received_at") + `,
+			       received_by_name
+			FROM goods_receipts
+			WHERE ` + fmt.Sprintf(berlinDate, "received_at") + ` BETWEEN $1 AND $2
+			ORDER BY received_at, id
+		`,
+		period: true,
+	},
+	{
+		table: gdpdu.Table{
+			Name: "Wareneingangspositionen", File: "wareneingangspositionen.csv", Description: "Positionen der Wareneingänge",
+			Columns: []gdpdu.Column{
+				gdpduKey("position_id", "Positions-ID"),
+				gdpduText("wareneingang_id", "Wareneingangs-ID"),
+				gdpduText("bestellposition_id", "Bestellpositions-ID"),
+				gdpduText("artikel_id", "Artikel-ID"),
+				gdpduText("charge_id", "Chargen-ID"),
+				gdpduNumber("menge", "Menge"),
+			},
+			ForeignKeys: []gdpdu.ForeignKey{
+				{Columns: []string{"wareneingang_id"}, References: "Wareneingaenge"},
+				{Columns: []string{"bestellposition_id"}, References: "Bestellpositionen"},
+				{Columns: []string{"artikel_id"}, References: "Artikel"},
+				{Columns: []string{"charge_id"}, References: "Chargen"},
+			},
+		},
+		query: `
+			SELECT l.id, l.goods_receipt_id, l.purchase_order_line_id, l.item_id, l.batch_id, l.quantity
+			FROM goods_receipt_lines l
+			JOIN goods_receipts g ON g.id = l.goods_receipt_id
+			WHERE ` + fmt.Sprintf(berlinDate, "g.received_at") + ` BETWEEN $1 AND $2
+			ORDER BY g.received_at, l.goods_receipt_id, l.id
+		`,
+		period: true,
+	},
+	{
+		table: gdpdu.Table{
+			Name: "Lagerbewegungen", File: "lagerbewegungen.csv", Description: "Bestandsveränderungen",
+			Columns: []gdpdu.Column{
+				gdpduKey("bewegung_id", "Bewegungs-ID"),
+				gdpduText("artikel_id", "Artikel-ID"),
+				gdpduText("charge_id", "Chargen-ID"),
+				gdpduText("art", "Art der Bewegung"),
+				gdpduNumber("menge", "Menge"),
+				gdpduText("grund", "Grund"),
+				gdpduText("beleg_typ", "Belegart"),
+				gdpduText("beleg_id", "Beleg-ID"),
+				gdpduDate("datum", "Datum"),
+				gdpduText("uhrzeit", "Uhrzeit"),
+				gdpduText("erfasst_von", "Benutzer-ID"),
+			},
+			ForeignKeys: []gdpdu.ForeignKey{
+				{Columns: []string{"artikel_id"}, References: "Artikel"},
+				{Columns: []string{"charge_id"}, References: "Chargen"},
+			},
+		},
+		query: `
+			SELECT id, item_id, batch_id, adjustment_type, quantity, reason, reference_type, reference_id,
+			       ` + fmt.Sprintf(berlinDate, "created_at") + `, ` + fmt.Sprintf(berlinTime, "created_at") + `,
+			       created_by
+			FROM stock_adjustments
+			WHERE ` + fmt.Sprintf(berlinDate, "created_at") + ` BETWEEN $1 AND $2
+			ORDER BY created_at, id
+		`,
+		period: true,
+	},
+	{
+		table: gdpdu.Table{
+			Name: "Protokoll", File: "protokoll.csv", Description: "Änderungsprotokoll (GoBD), hashverkettet",
+			Columns: []gdpdu.Column{
+				gdpduKey("eintrag_id", "Eintrags-ID"),
+				gdpduNumber("sequenz", "Laufende Nummer der Hash-Kette"),
+				gdpduDate("datum", "Datum"),
+				gdpduText("uhrzeit", "Uhrzeit"),
+				gdpduText("objekttyp", "Art des geänderten Datensatzes"),
+				gdpduText("objekt_id", "ID des geänderten Datensatzes"),
+				gdpduText("aktion", "Aktion"),
+				gdpduText("aenderungen", "Geänderte Felder (JSON)"),
+				gdpduText("metadaten", "Weitere Angaben (JSON)"),
+				gdpduText("benutzer_id", "Benutzer-ID"),
+				gdpduText("benutzer_name", "Benutzer"),
+				gdpduText("hash", "SHA-256 des Eintrags"),
+				gdpduText("vorgaenger_hash", "SHA-256 des vorherigen Eintrags"),
+			},
+		},
+		query: `
+			SELECT id, sequence,
+			       ` + fmt.Sprintf(berlinDate, "created_at") + `, ` + fmt.Sprintf(berlinTime, "created_at") + `,
+			       entity_type, entity_id, action, field_changes::text, metadata::text,
+			       performed_by, performed_by_name, hash, prev_hash
+			FROM audit_trail
+			WHERE ` + fmt.Sprintf(berlinDate, "created_at") + ` BETWEEN $1 AND $2
+			ORDER BY sequence NULLS FIRST, created_at, id
+		`,
+		period: true,
+	},
+}
+
+// gdpduOrderInPeriod selects orders placed in the period and orders
+// received in it, whose lines the period's goods receipts reference
+var gdpduOrderInPeriod = `(` + fmt.Sprintf(berlinDate, "purchase_orders.created_at") + ` BETWEEN $1 AND $2
+	OR purchase_orders.id IN (
+		SELECT purchase_order_id FROM goods_receipts
+		WHERE ` + fmt.Sprintf(berlinDate, "received_at") + ` BETWEEN $1 AND $2
+	))`
@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/medflow/medflow-backend/pkg/database"
 	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/search"
 	"github.com/medflow/medflow-backend/pkg/tenant"
 )
 
@@ -392,3 +393,45 @@ func (r *HygieneRepository) DeleteInspection(ctx context.Context, id string) err
 func itoa(i int) string {
 	return fmt.Sprintf("%d", i)
 }
+
+// hygienePlanTitle and hygienePlanDocument must match the
+// idx_hygiene_plans_title_trgm and idx_hygiene_plans_content_fts index expressions
+const (
+	hygienePlanTitle    = `public.search_normalize(title)`
+	hygienePlanDocument = `to_tsvector('german', public.search_normalize(title || ' ' || COALESCE(content, '')))`
+)
+
+// SearchPlans finds hygiene plans by title (typo tolerant) or content (full-text)
+// TENANT-ISOLATED: Searches only the tenant's plans via RLS
+func (r *HygieneRepository) SearchPlans(ctx context.Context, query string, limit int) ([]*search.Result, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*search.Result
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		if err := search.PrepareTx(ctx, r.db); err != nil {
+			return err
+		}
+
+		// ts_rank normalization 32 scales the rank into [0, 1)
+		q := `
+			SELECT id, title, category AS subtitle,
+			       GREATEST(word_similarity($1, ` + hygienePlanTitle + `),
+			                ts_rank(` + hygienePlanDocument + `, plainto_tsquery('german', $1), 32)) AS score
+			FROM hygiene_plans
+			WHERE deleted_at IS NULL
+			  AND ($1 <% ` + hygienePlanTitle + ` OR ` + hygienePlanDocument + ` @@ plainto_tsquery('german', $1))
+			ORDER BY score DESC, title
+			LIMIT $2
+		`
+		return r.db.SelectContext(ctx, &results, q, search.Normalize(query), limit)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return search.Tag(results, search.TypeHygienePlan), nil
+}
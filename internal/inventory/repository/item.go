@@ -6,8 +6,10 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/medflow/medflow-backend/pkg/database"
 	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/search"
 	"github.com/medflow/medflow-backend/pkg/tenant"
 )
 
@@ -63,6 +65,8 @@ type InventoryItem struct {
 	CreatedAt time.Time  `db:"created_at" json:"created_at"`
 	UpdatedAt time.Time  `db:"updated_at" json:"updated_at"`
 	DeletedAt *time.Time `db:"deleted_at" json:"-"`
+	// Earliest expiry of available batches; only populated by List (sort/cursor key)
+	ExpiryDate *time.Time `db:"expiry_date" json:"-"`
 	// Computed field for API compatibility
 	PricePerUnit float64 `db:"-" json:"price_per_unit"`
 }
@@ -261,70 +265,51 @@ func (r *ItemRepository) GetByArticleNumber(ctx context.Context, articleNumber s
 	return &item, nil
 }
 
-// List lists inventory items with pagination
+// itemListSchema whitelists the filter and sort fields of GET /items.
+// expiry_date is the earliest expiry of the item's available batches.
+// Every sort field except expiry_date has a (tenant_id, field, id) keyset
+// index (migration 000048); add one when adding a sort field.
+var itemListSchema = database.ListSchema{
+	Fields: map[string]database.Field{
+		"name":              {Column: "name", Type: database.FieldText, Filter: true, Sort: true},
+		"category":          {Column: "category", Type: database.FieldText, Filter: true, Sort: true},
+		"manufacturer":      {Column: "manufacturer", Type: database.FieldText, Filter: true, Sort: true, Nullable: true},
+		"supplier":          {Column: "supplier", Type: database.FieldText, Filter: true, Sort: true, Nullable: true},
+		"barcode":           {Column: "barcode", Type: database.FieldText, Filter: true},
+		"pzn":               {Column: "pzn", Type: database.FieldText, Filter: true},
+		"article_number":    {Column: "article_number", Type: database.FieldText, Filter: true},
+		"is_active":         {Column: "is_active", Type: database.FieldBool, Filter: true},
+		"is_hazardous":      {Column: "is_hazardous", Type: database.FieldBool, Filter: true},
+		"requires_cooling":  {Column: "requires_cooling", Type: database.FieldBool, Filter: true},
+		"is_medical_device": {Column: "is_medical_device", Type: database.FieldBool, Filter: true},
+		"expiry_date":       {Column: "expiry_date", Type: database.FieldDate, Filter: true, Sort: true, Nullable: true},
+		"created_at":        {Column: "created_at", Type: database.FieldTime, Filter: true, Sort: true},
+		"updated_at":        {Column: "updated_at", Type: database.FieldTime, Filter: true, Sort: true},
+	},
+	DefaultSort: []string{"name"},
+}
+
+// List lists inventory items filtered, sorted and paginated by q
 // TENANT-ISOLATED: Returns only items from the tenant's schema
-func (r *ItemRepository) List(ctx context.Context, page, perPage int, category string) ([]*InventoryItem, int64, error) {
+func (r *ItemRepository) List(ctx context.Context, q *database.ListQuery) (*database.Page[*InventoryItem], error) {
 	// Extract tenant ID from context
 	tenantID, err := tenant.TenantID(ctx)
 	if err != nil {
-		return nil, 0, err // Fail-fast if tenant context missing
+		return nil, err // Fail-fast if tenant context missing
 	}
 
-	var total int64
-	var items []*InventoryItem
+	var page *database.Page[*InventoryItem]
 
 	// Execute queries with tenant RLS
 	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
-		// Count total items
-		countQuery := `SELECT COUNT(*) FROM inventory_items WHERE deleted_at IS NULL`
-		args := []interface{}{}
-
-		if category != "" {
-			countQuery += ` AND category = $1`
-			args = append(args, category)
-		}
-
-		if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
-			return err
-		}
-
-		// Get paginated items
-		offset := (page - 1) * perPage
-		query := `
-			SELECT id, name, description, category, barcode, article_number, pzn, manufacturer, supplier,
-			       unit, min_stock, max_stock, reorder_point, reorder_quantity, use_batch_tracking,
-			       requires_cooling, is_hazardous, shelf_life_days, default_location_id,
-			       unit_price_cents, currency, is_active,
-			       manufacturer_address, ce_marking_number, notified_body_id, acquisition_date,
-			       serial_number, udi_di, udi_pi,
-			       is_medical_device, device_type, device_model, authorized_representative, importer,
-			       operational_id_number, location_assignment, risk_class,
-			       stk_interval_months, mtk_interval_months, last_stk_date, next_stk_due,
-			       last_mtk_date, next_mtk_due, shelf_life_after_opening_days,
-			       created_at, updated_at
-			FROM inventory_items WHERE deleted_at IS NULL
-		`
-
-		if category != "" {
-			query += ` AND category = $1`
-		}
-
-		query += ` ORDER BY name`
-
-		if category != "" {
-			query += ` LIMIT $2 OFFSET $3`
-			args = append(args, perPage, offset)
-		} else {
-			query += ` LIMIT $1 OFFSET $2`
-			args = append(args, perPage, offset)
-		}
-
-		if err := r.db.SelectContext(ctx, &items, query, args...); err != nil {
+		var err error
+		page, err = database.SelectPage[*InventoryItem](ctx, r.db, &itemListSchema, q, itemListQuery)
+		if err != nil {
 			return err
 		}
 
 		// Compute price_per_unit from cents for each item
-		for _, item := range items {
+		for _, item := range page.Items {
 			item.PricePerUnit = float64(item.UnitPriceCents) / 100.0
 		}
 
@@ -332,12 +317,42 @@ func (r *ItemRepository) List(ctx context.Context, page, perPage int, category s
 	})
 
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	return items, total, nil
+	return page, nil
 }
 
+// itemListQuery is wrapped in a subquery so the computed expiry_date can be
+// filtered and sorted on. The earliest expiry is the first entry of the
+// idx_inventory_batches_item_expiry index of the item, so the LATERAL lookup
+// costs one index probe per listed item.
+const itemListQuery = `
+	SELECT * FROM (
+		SELECT i.id, i.name, i.description, i.category, i.barcode, i.article_number, i.pzn, i.manufacturer, i.supplier,
+		       i.unit, i.min_stock, i.max_stock, i.reorder_point, i.reorder_quantity, i.use_batch_tracking,
+		       i.requires_cooling, i.is_hazardous, i.shelf_life_days, i.default_location_id,
+		       i.unit_price_cents, i.currency, i.is_active,
+		       i.manufacturer_address, i.ce_marking_number, i.notified_body_id, i.acquisition_date,
+		       i.serial_number, i.udi_di, i.udi_pi,
+		       i.is_medical_device, i.device_type, i.device_model, i.authorized_representative, i.importer,
+		       i.operational_id_number, i.location_assignment, i.risk_class,
+		       i.stk_interval_months, i.mtk_interval_months, i.last_stk_date, i.next_stk_due,
+		       i.last_mtk_date, i.next_mtk_due, i.shelf_life_after_opening_days,
+		       i.created_at, i.updated_at, i.deleted_at,
+		       e.expiry_date
+		FROM inventory_items i
+		LEFT JOIN LATERAL (
+		    SELECT b.expiry_date FROM inventory_batches b
+		    WHERE b.item_id = i.id AND b.status = 'available' AND b.deleted_at IS NULL
+		      AND b.expiry_date IS NOT NULL
+		    ORDER BY b.expiry_date
+		    LIMIT 1
+		) e ON TRUE
+	) AS items
+	WHERE deleted_at IS NULL
+`
+
 // Update updates an inventory item
 // TENANT-ISOLATED: Updates only in the tenant's schema
 func (r *ItemRepository) Update(ctx context.Context, item *InventoryItem) error {
@@ -508,6 +523,54 @@ func (r *ItemRepository) GetByPZN(ctx context.Context, pzn string) (*InventoryIt
 	return &item, nil
 }
 
+// GetByProductCode gets an item whose barcode or UDI-DI is one of codes, the
+// identifiers decoded from a GS1 or HIBC code
+// TENANT-ISOLATED: Returns only items from the tenant's schema
+func (r *ItemRepository) GetByProductCode(ctx context.Context, codes []string) (*InventoryItem, error) {
+	if len(codes) == 0 {
+		return nil, errors.NotFound("item")
+	}
+
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var item InventoryItem
+
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			SELECT id, name, description, category, barcode, article_number, pzn, manufacturer, supplier,
+			       unit, min_stock, max_stock, reorder_point, reorder_quantity, use_batch_tracking,
+			       requires_cooling, is_hazardous, shelf_life_days, default_location_id,
+			       unit_price_cents, currency, is_active,
+			       manufacturer_address, ce_marking_number, notified_body_id, acquisition_date,
+			       serial_number, udi_di, udi_pi,
+			       is_medical_device, device_type, device_model, authorized_representative, importer,
+			       operational_id_number, location_assignment, risk_class,
+			       stk_interval_months, mtk_interval_months, last_stk_date, next_stk_due,
+			       last_mtk_date, next_mtk_due, shelf_life_after_opening_days,
+			       created_at, updated_at
+			FROM inventory_items
+			WHERE (barcode = ANY($1) OR udi_di = ANY($1)) AND deleted_at IS NULL
+			ORDER BY is_active DESC, created_at
+			LIMIT 1
+		`
+		return r.db.GetContext(ctx, &item, query, pq.Array(codes))
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFound("item")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	item.PricePerUnit = float64(item.UnitPriceCents) / 100.0
+
+	return &item, nil
+}
+
 // ListMedicalDevices gets all active medical devices (for Bestandsverzeichnis MPBetreibV §14)
 // TENANT-ISOLATED: Returns only medical devices from the tenant's schema
 func (r *ItemRepository) ListMedicalDevices(ctx context.Context) ([]*InventoryItem, error) {
@@ -551,3 +614,43 @@ func (r *ItemRepository) ListMedicalDevices(ctx context.Context) ([]*InventoryIt
 
 	return items, nil
 }
+
+// itemSearchDocument is the normalized text items are fuzzy-matched against.
+// Must match the idx_inventory_items_search_trgm index expression.
+const itemSearchDocument = `public.search_normalize(name || ' ' || COALESCE(manufacturer, '') || ' ' || COALESCE(pzn, '') || ' ' || COALESCE(article_number, ''))`
+
+// Search finds items by name, manufacturer, PZN or article number with typo tolerance.
+// Exact PZN, article number or barcode matches rank first.
+// TENANT-ISOLATED: Searches only the tenant's items via RLS
+func (r *ItemRepository) Search(ctx context.Context, query string, limit int) ([]*search.Result, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*search.Result
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		if err := search.PrepareTx(ctx, r.db); err != nil {
+			return err
+		}
+
+		q := `
+			SELECT id, name AS title,
+			       NULLIF(concat_ws(' · ', manufacturer, pzn), '') AS subtitle,
+			       CASE WHEN pzn = $2 OR article_number = $2 OR barcode = $2 THEN 1.0
+			            ELSE word_similarity($1, ` + itemSearchDocument + `) END AS score
+			FROM inventory_items
+			WHERE deleted_at IS NULL
+			  AND (pzn = $2 OR article_number = $2 OR barcode = $2 OR $1 <% ` + itemSearchDocument + `)
+			ORDER BY score DESC, name
+			LIMIT $3
+		`
+		return r.db.SelectContext(ctx, &results, q, search.Normalize(query), query, limit)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return search.Tag(results, search.TypeItem), nil
+}
@@ -0,0 +1,77 @@
+package repository_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestItemRepository_ListUsesKeysetIndexes explains the first page of GET /items
+// as SelectPage renders it for ItemRepository.List: the page is read in sort
+// order from the (tenant_id, field, id) index and the earliest expiry is one
+// probe of idx_inventory_batches_item_expiry per item, without a sort step.
+func TestItemRepository_ListUsesKeysetIndexes(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tenant := suite.SetupInventoryTenant(t, ctx, "item-list-plan")
+	tenantCtx := suite.TenantContext(tenant)
+
+	_, err := suite.RawDB.ExecContext(ctx, `
+		INSERT INTO inventory.inventory_items (tenant_id, name, manufacturer, unit)
+		SELECT $1, 'Artikel ' || n, CASE WHEN n % 3 = 0 THEN NULL ELSE 'Hersteller ' || n % 40 END, 'Stk'
+		FROM generate_series(1, 2000) AS n
+	`, tenant.ID)
+	require.NoError(t, err)
+	_, err = suite.RawDB.ExecContext(ctx, `
+		INSERT INTO inventory.inventory_batches (tenant_id, item_id, batch_number, initial_quantity, current_quantity, expiry_date)
+		SELECT i.tenant_id, i.id, 'CH-' || n, 10, 10, CURRENT_DATE + n * 30
+		FROM inventory.inventory_items i, generate_series(1, 3) AS n
+		WHERE i.tenant_id = $1
+	`, tenant.ID)
+	require.NoError(t, err)
+	_, err = suite.RawDB.ExecContext(ctx, `ANALYZE inventory.inventory_items, inventory.inventory_batches`)
+	require.NoError(t, err)
+
+	// Mirrors itemListQuery; the projection does not change the plan
+	const list = `
+		SELECT * FROM (
+			SELECT i.id, i.name, i.manufacturer, i.created_at, i.deleted_at, e.expiry_date
+			FROM inventory_items i
+			LEFT JOIN LATERAL (
+			    SELECT b.expiry_date FROM inventory_batches b
+			    WHERE b.item_id = i.id AND b.status = 'available' AND b.deleted_at IS NULL
+			      AND b.expiry_date IS NOT NULL
+			    ORDER BY b.expiry_date
+			    LIMIT 1
+			) e ON TRUE
+		) AS items
+		WHERE deleted_at IS NULL
+	`
+	tests := []struct {
+		order string
+		index string
+	}{
+		{"name, id", "idx_inventory_items_keyset_name"},
+		{"COALESCE(manufacturer, '') DESC, id DESC", "idx_inventory_items_keyset_manufacturer"},
+		{"created_at DESC, id DESC", "idx_inventory_items_keyset_created"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.index, func(t *testing.T) {
+			var plan []string
+			err := suite.DB.WithTenantRLS(tenantCtx, tenant.ID, func(ctx context.Context) error {
+				return suite.DB.SelectContext(ctx, &plan, "EXPLAIN "+list+" ORDER BY "+tt.order+" LIMIT 21")
+			})
+			require.NoError(t, err)
+
+			text := strings.Join(plan, "\n")
+			assert.Contains(t, text, tt.index, text)
+			assert.Contains(t, text, "idx_inventory_batches_item_expiry", text)
+			assert.NotContains(t, text, "Sort", text)
+		})
+	}
+}
@@ -0,0 +1,504 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/tenant"
+)
+
+// kitConsumptionReference is the reference_type of bookings that consume a kit
+const kitConsumptionReference = "kit_consumption"
+
+// ProcedureKit is the bill of materials of a treatment (Behandlungsset)
+type ProcedureKit struct {
+	ID          string     `db:"id" json:"id"`
+	Name        string     `db:"name" json:"name"`
+	Description *string    `db:"description" json:"description,omitempty"`
+	IsActive    bool       `db:"is_active" json:"is_active"`
+	CreatedBy   *string    `db:"created_by" json:"created_by,omitempty"`
+	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at" json:"updated_at"`
+	DeletedAt   *time.Time `db:"deleted_at" json:"-"`
+
+	// Computed
+	ItemCount int `db:"item_count" json:"item_count"`
+
+	Items []*ProcedureKitItem `db:"-" json:"items,omitempty"`
+}
+
+// ProcedureKitItem is an item and the quantity one kit uses of it
+type ProcedureKitItem struct {
+	ID        string    `db:"id" json:"id"`
+	KitID     string    `db:"kit_id" json:"kit_id"`
+	ItemID    string    `db:"item_id" json:"item_id"`
+	Quantity  int       `db:"quantity" json:"quantity"`
+	Position  int       `db:"position" json:"position"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+
+	// Joined from the item
+	ItemName string `db:"item_name" json:"item_name"`
+	Unit     string `db:"unit" json:"unit"`
+}
+
+// KitConsumption records one consumption of a kit. Its ID is the
+// correlation ID referenced by every stock adjustment of the booking.
+type KitConsumption struct {
+	ID              string    `db:"id" json:"id"`
+	KitID           string    `db:"kit_id" json:"kit_id"`
+	KitName         string    `db:"kit_name" json:"kit_name"`
+	KitCount        int       `db:"kit_count" json:"kit_count"`
+	ReferenceType   *string   `db:"reference_type" json:"reference_type,omitempty"`
+	ReferenceID     *string   `db:"reference_id" json:"reference_id,omitempty"`
+	Notes           *string   `db:"notes" json:"notes,omitempty"`
+	PerformedBy     string    `db:"performed_by" json:"performed_by"`
+	PerformedByName *string   `db:"performed_by_name" json:"performed_by_name,omitempty"`
+	CreatedAt       time.Time `db:"created_at" json:"created_at"`
+
+	Lines []*KitConsumptionLine `db:"-" json:"lines,omitempty"`
+}
+
+// KitConsumptionLine is the quantity a consumption booked out of one batch
+type KitConsumptionLine struct {
+	ID            string `db:"id" json:"id"`
+	ConsumptionID string `db:"consumption_id" json:"consumption_id"`
+	ItemID        string `db:"item_id" json:"item_id"`
+	BatchID       string `db:"batch_id" json:"batch_id"`
+	Quantity      int    `db:"quantity" json:"quantity"`
+	AdjustmentID  string `db:"adjustment_id" json:"adjustment_id"`
+
+	// Joined
+	ItemName    string `db:"item_name" json:"item_name"`
+	BatchNumber string `db:"batch_number" json:"batch_number"`
+}
+
+// KitPick is the quantity of an item a consumption needs
+type KitPick struct {
+	ItemID   string
+	ItemName string
+	Quantity int
+}
+
+// PickOrder returns the IDs of the candidates of an item that may be picked,
+// in the order to take stock from them
+type PickOrder func(itemID string, candidates []*PickCandidate) []string
+
+// KitRepository handles procedure kit persistence
+type KitRepository struct {
+	db *database.DB
+}
+
+// NewKitRepository creates a new kit repository
+func NewKitRepository(db *database.DB) *KitRepository {
+	return &KitRepository{db: db}
+}
+
+const kitSelect = `
+	SELECT k.id, k.name, k.description, k.is_active, k.created_by, k.created_at, k.updated_at, k.deleted_at,
+	       (SELECT COUNT(*) FROM procedure_kit_items ki WHERE ki.kit_id = k.id) AS item_count
+	FROM procedure_kits k
+`
+
+const kitItemSelect = `
+	SELECT ki.id, ki.kit_id, ki.item_id, ki.quantity, ki.position, ki.created_at,
+	       i.name AS item_name, i.unit
+	FROM procedure_kit_items ki
+	JOIN inventory_items i ON i.id = ki.item_id
+`
+
+// Create creates a kit with its items
+// TENANT-ISOLATED: Inserts with tenant_id for RLS
+func (r *KitRepository) Create(ctx context.Context, kit *ProcedureKit) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	if kit.ID == "" {
+		kit.ID = uuid.New().String()
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			INSERT INTO procedure_kits (id, tenant_id, name, description, is_active, created_by)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING created_at, updated_at
+		`
+		if err := r.db.QueryRowxContext(ctx, query,
+			kit.ID, tenantID, kit.Name, kit.Description, kit.IsActive, kit.CreatedBy,
+		).Scan(&kit.CreatedAt, &kit.UpdatedAt); err != nil {
+			if appErr := database.MapPQError(err); appErr != nil {
+				return appErr
+			}
+			return err
+		}
+
+		return r.insertItems(ctx, tenantID, kit)
+	})
+}
+
+// insertItems inserts the kit's items using the transaction in ctx
+func (r *KitRepository) insertItems(ctx context.Context, tenantID string, kit *ProcedureKit) error {
+	query := `
+		INSERT INTO procedure_kit_items (id, tenant_id, kit_id, item_id, quantity, position)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at
+	`
+
+	for i, item := range kit.Items {
+		if item.ID == "" {
+			item.ID = uuid.New().String()
+		}
+		item.KitID = kit.ID
+		item.Position = i + 1
+
+		err := r.db.QueryRowxContext(ctx, query,
+			item.ID, tenantID, kit.ID, item.ItemID, item.Quantity, item.Position,
+		).Scan(&item.CreatedAt)
+		if err != nil {
+			if appErr := database.MapPQError(err); appErr != nil {
+				return appErr
+			}
+			return err
+		}
+	}
+	kit.ItemCount = len(kit.Items)
+
+	return nil
+}
+
+// GetByID gets a kit with its items
+// TENANT-ISOLATED: Queries via RLS
+func (r *KitRepository) GetByID(ctx context.Context, id string) (*ProcedureKit, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var kit ProcedureKit
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		if err := r.db.GetContext(ctx, &kit, kitSelect+` WHERE k.id = $1 AND k.deleted_at IS NULL`, id); err != nil {
+			return err
+		}
+
+		return r.db.SelectContext(ctx, &kit.Items, kitItemSelect+` WHERE ki.kit_id = $1 ORDER BY ki.position`, id)
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFound("procedure_kit")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &kit, nil
+}
+
+// kitListSchema whitelists the filter and sort fields of GET /kits
+var kitListSchema = database.ListSchema{
+	Fields: map[string]database.Field{
+		"name":       {Column: "name", Type: database.FieldText, Filter: true, Sort: true},
+		"is_active":  {Column: "is_active", Type: database.FieldBool, Filter: true},
+		"item_count": {Column: "item_count", Type: database.FieldInt, Filter: true, Sort: true},
+		"created_at": {Column: "created_at", Type: database.FieldTime, Filter: true, Sort: true},
+	},
+	DefaultSort: []string{"name"},
+}
+
+// List lists kits (without items) filtered, sorted and paginated by q
+// TENANT-ISOLATED: Returns only kits via RLS
+func (r *KitRepository) List(ctx context.Context, q *database.ListQuery) (*database.Page[*ProcedureKit], error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var page *database.Page[*ProcedureKit]
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		// Wrapped in a subquery so item_count can be filtered and sorted on
+		query := `SELECT * FROM (` + kitSelect + `) AS kits WHERE deleted_at IS NULL`
+		var err error
+		page, err = database.SelectPage[*ProcedureKit](ctx, r.db, &kitListSchema, q, query)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// Update replaces the name, description, status and items of a kit
+// TENANT-ISOLATED: Updates via RLS
+func (r *KitRepository) Update(ctx context.Context, kit *ProcedureKit) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			UPDATE procedure_kits SET name = $2, description = $3, is_active = $4
+			WHERE id = $1 AND deleted_at IS NULL
+			RETURNING created_by, created_at, updated_at
+		`
+		if err := r.db.QueryRowxContext(ctx, query, kit.ID, kit.Name, kit.Description, kit.IsActive).
+			Scan(&kit.CreatedBy, &kit.CreatedAt, &kit.UpdatedAt); err != nil {
+			if err == sql.ErrNoRows {
+				return errors.NotFound("procedure_kit")
+			}
+			if appErr := database.MapPQError(err); appErr != nil {
+				return appErr
+			}
+			return err
+		}
+
+		if _, err := r.db.ExecContext(ctx, `DELETE FROM procedure_kit_items WHERE kit_id = $1`, kit.ID); err != nil {
+			return err
+		}
+		return r.insertItems(ctx, tenantID, kit)
+	})
+}
+
+// Delete soft-deletes a kit; its consumptions are kept
+// TENANT-ISOLATED: Updates via RLS
+func (r *KitRepository) Delete(ctx context.Context, id string) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		result, err := r.db.ExecContext(ctx, `UPDATE procedure_kits SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`, id)
+		if err != nil {
+			return err
+		}
+		rows, _ := result.RowsAffected()
+		if rows == 0 {
+			return errors.NotFound("procedure_kit")
+		}
+		return nil
+	})
+}
+
+// Consume books out the picks of a kit consumption atomically. The batches
+// of each item are locked and read in the transaction, and order chooses
+// which of them to take the item from; only their unreserved stock is used.
+// If any item is short, nothing is booked and the error lists every shortage.
+// TENANT-ISOLATED: Updates and inserts via RLS
+func (r *KitRepository) Consume(ctx context.Context, c *KitConsumption, picks []*KitPick, order PickOrder) ([]*StockAdjustment, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+
+	// Lock batches in item order so concurrent consumptions cannot deadlock
+	ordered := make([]*KitPick, len(picks))
+	copy(ordered, picks)
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].ItemID < ordered[j].ItemID })
+
+	var adjustments []*StockAdjustment
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			INSERT INTO kit_consumptions (
+				id, tenant_id, kit_id, kit_name, kit_count, reference_type, reference_id, notes,
+				performed_by, performed_by_name
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			RETURNING created_at
+		`
+		if err := r.db.QueryRowxContext(ctx, query,
+			c.ID, tenantID, c.KitID, c.KitName, c.KitCount, c.ReferenceType, c.ReferenceID, c.Notes,
+			c.PerformedBy, c.PerformedByName,
+		).Scan(&c.CreatedAt); err != nil {
+			return err
+		}
+
+		reason := fmt.Sprintf("Set: %s", c.KitName)
+		referenceType := kitConsumptionReference
+
+		var shortage *errors.AppError
+		c.Lines = nil
+		for _, pick := range ordered {
+			candidates, err := selectPickCandidates(ctx, r.db, pick.ItemID, " FOR UPDATE OF b")
+			if err != nil {
+				return err
+			}
+			byID := make(map[string]*PickCandidate, len(candidates))
+			for _, cand := range candidates {
+				byID[cand.ID] = cand
+			}
+
+			remaining := pick.Quantity
+			for _, batchID := range order(pick.ItemID, candidates) {
+				if remaining == 0 {
+					break
+				}
+
+				cand, ok := byID[batchID]
+				if !ok {
+					continue
+				}
+				current := cand.CurrentQuantity
+				take := min(cand.Available(), remaining)
+				if take <= 0 {
+					continue
+				}
+				remaining -= take
+
+				// Bookings of a short consumption are rolled back with the error
+				if _, err := r.db.ExecContext(ctx, `UPDATE inventory_batches SET current_quantity = $2 WHERE id = $1`, batchID, current-take); err != nil {
+					return err
+				}
+
+				id := batchID
+				adj := &StockAdjustment{
+					ItemID:           pick.ItemID,
+					BatchID:          &id,
+					AdjustmentType:   "deduct",
+					Quantity:         take,
+					PreviousQuantity: current,
+					NewQuantity:      current - take,
+					Reason:           &reason,
+					ReferenceType:    &referenceType,
+					ReferenceID:      &c.ID,
+					PerformedBy:      c.PerformedBy,
+					PerformedByName:  c.PerformedByName,
+				}
+				if err := insertStockAdjustment(ctx, r.db, tenantID, adj); err != nil {
+					return err
+				}
+				adjustments = append(adjustments, adj)
+
+				line := &KitConsumptionLine{
+					ID:            uuid.New().String(),
+					ConsumptionID: c.ID,
+					ItemID:        pick.ItemID,
+					BatchID:       batchID,
+					Quantity:      take,
+					AdjustmentID:  adj.ID,
+					ItemName:      pick.ItemName,
+				}
+				lineQuery := `
+					INSERT INTO kit_consumption_lines (id, tenant_id, consumption_id, item_id, batch_id, quantity, adjustment_id)
+					VALUES ($1, $2, $3, $4, $5, $6, $7)
+				`
+				if _, err := r.db.ExecContext(ctx, lineQuery,
+					line.ID, tenantID, line.ConsumptionID, line.ItemID, line.BatchID, line.Quantity, line.AdjustmentID,
+				); err != nil {
+					return err
+				}
+				c.Lines = append(c.Lines, line)
+			}
+
+			if remaining > 0 {
+				if shortage == nil {
+					shortage = errors.Conflict("errors.kit.insufficient_stock", map[string]string{"kit": c.KitName})
+				}
+				shortage.WithDetail(pick.ItemID, "errors.kit.item_shortage", map[string]string{
+					"item":      pick.ItemName,
+					"required":  strconv.Itoa(pick.Quantity),
+					"available": strconv.Itoa(pick.Quantity - remaining),
+				})
+			}
+		}
+
+		if shortage != nil {
+			return shortage
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return adjustments, nil
+}
+
+const kitConsumptionSelect = `
+	SELECT id, kit_id, kit_name, kit_count, reference_type, reference_id, notes,
+	       performed_by, performed_by_name, created_at
+	FROM kit_consumptions
+`
+
+// GetConsumption gets a kit consumption with the batches it booked out
+// TENANT-ISOLATED: Queries via RLS
+func (r *KitRepository) GetConsumption(ctx context.Context, id string) (*KitConsumption, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var c KitConsumption
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		if err := r.db.GetContext(ctx, &c, kitConsumptionSelect+` WHERE id = $1`, id); err != nil {
+			return err
+		}
+
+		linesQuery := `
+			SELECT l.id, l.consumption_id, l.item_id, l.batch_id, l.quantity, l.adjustment_id,
+			       i.name AS item_name, b.batch_number
+			FROM kit_consumption_lines l
+			JOIN inventory_items i ON i.id = l.item_id
+			JOIN inventory_batches b ON b.id = l.batch_id
+			WHERE l.consumption_id = $1
+			ORDER BY i.name, b.batch_number
+		`
+		return r.db.SelectContext(ctx, &c.Lines, linesQuery, id)
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFound("kit_consumption")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// kitConsumptionListSchema whitelists the filter and sort fields of GET /kit-consumptions
+var kitConsumptionListSchema = database.ListSchema{
+	Fields: map[string]database.Field{
+		"kit_id":       {Column: "kit_id", Type: database.FieldUUID, Filter: true},
+		"kit_name":     {Column: "kit_name", Type: database.FieldText, Filter: true, Sort: true},
+		"reference_id": {Column: "reference_id", Type: database.FieldUUID, Filter: true, Nullable: true},
+		"performed_by": {Column: "performed_by", Type: database.FieldText, Filter: true},
+		"created_at":   {Column: "created_at", Type: database.FieldTime, Filter: true, Sort: true},
+	},
+	DefaultSort: []string{"-created_at"},
+}
+
+// ListConsumptions lists kit consumptions (without lines) filtered, sorted and paginated by q
+// TENANT-ISOLATED: Returns only consumptions via RLS
+func (r *KitRepository) ListConsumptions(ctx context.Context, q *database.ListQuery) (*database.Page[*KitConsumption], error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var page *database.Page[*KitConsumption]
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		var err error
+		page, err = database.SelectPage[*KitConsumption](ctx, r.db, &kitConsumptionListSchema, q, kitConsumptionSelect+` WHERE 1=1`)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
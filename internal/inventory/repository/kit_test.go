@@ -0,0 +1,101 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Procedure Kit Tests ---
+
+func TestKitRepository_Consume(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tenant := suite.SetupInventoryTenant(t, ctx, "kit-consume")
+	tenantCtx := suite.TenantContext(tenant)
+
+	itemRepo := repository.NewItemRepository(suite.DB)
+	batchRepo := repository.NewBatchRepository(suite.DB)
+	kitRepo := repository.NewKitRepository(suite.DB)
+
+	gloves := createTestItem(t, tenantCtx, itemRepo, "Handschuhe steril")
+	swabs := createTestItem(t, tenantCtx, itemRepo, "Tupfer")
+	_, ambient, _ := createTestLocations(t, tenantCtx)
+	glovesFirst := createTestBatch(t, tenantCtx, gloves.ID, ambient.ID, 1)
+	glovesSecond := createTestBatch(t, tenantCtx, gloves.ID, ambient.ID, 5)
+	swabBatch := createTestBatch(t, tenantCtx, swabs.ID, ambient.ID, 4)
+
+	kit := &repository.ProcedureKit{
+		Name:     "Wundversorgung",
+		IsActive: true,
+		Items: []*repository.ProcedureKitItem{
+			{ItemID: gloves.ID, Quantity: 2},
+			{ItemID: swabs.ID, Quantity: 3},
+		},
+	}
+	require.NoError(t, kitRepo.Create(tenantCtx, kit))
+
+	got, err := kitRepo.GetByID(tenantCtx, kit.ID)
+	require.NoError(t, err)
+	require.Len(t, got.Items, 2)
+	assert.Equal(t, gloves.ID, got.Items[0].ItemID)
+
+	// The order callback chooses among the locked candidates of each item
+	pickOrders := map[string][]string{
+		gloves.ID: {glovesFirst.ID, glovesSecond.ID},
+		swabs.ID:  {swabBatch.ID},
+	}
+	offered := map[string]int{}
+	order := func(itemID string, candidates []*repository.PickCandidate) []string {
+		offered[itemID] = len(candidates)
+		return pickOrders[itemID]
+	}
+
+	// One consumption books out every item and references one correlation ID
+	consumption := &repository.KitConsumption{KitID: kit.ID, KitName: kit.Name, KitCount: 1, PerformedBy: "user-1"}
+	adjustments, err := kitRepo.Consume(tenantCtx, consumption, []*repository.KitPick{
+		{ItemID: gloves.ID, ItemName: gloves.Name, Quantity: 2},
+		{ItemID: swabs.ID, ItemName: swabs.Name, Quantity: 3},
+	}, order)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{gloves.ID: 2, swabs.ID: 1}, offered)
+	require.Len(t, adjustments, 3)
+	for _, adj := range adjustments {
+		assert.Equal(t, consumption.ID, *adj.ReferenceID)
+		assert.Equal(t, "kit_consumption", *adj.ReferenceType)
+	}
+
+	batch, err := batchRepo.GetByID(tenantCtx, glovesSecond.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 4, batch.CurrentQuantity)
+
+	recorded, err := kitRepo.GetConsumption(tenantCtx, consumption.ID)
+	require.NoError(t, err)
+	assert.Len(t, recorded.Lines, 3)
+
+	// A shortage lists the short item and books nothing
+	shortCount := &repository.KitConsumption{KitID: kit.ID, KitName: kit.Name, KitCount: 2, PerformedBy: "user-1"}
+	_, err = kitRepo.Consume(tenantCtx, shortCount, []*repository.KitPick{
+		{ItemID: gloves.ID, ItemName: gloves.Name, Quantity: 4},
+		{ItemID: swabs.ID, ItemName: swabs.Name, Quantity: 6},
+	}, order)
+	var appErr *errors.AppError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, "errors.kit.insufficient_stock", appErr.MessageKey)
+	assert.Equal(t, "errors.kit.item_shortage", appErr.Details[swabs.ID])
+	assert.NotContains(t, appErr.Details, gloves.ID)
+
+	batch, err = batchRepo.GetByID(tenantCtx, glovesSecond.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 4, batch.CurrentQuantity)
+
+	_, err = kitRepo.GetConsumption(tenantCtx, shortCount.ID)
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, "errors.not_found", appErr.MessageKey)
+}
@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/tenant"
+)
+
+// Label kinds
+const (
+	LabelKindBatch  = "batch"  // batch or opened multi-dose container with its use-by date
+	LabelKindShelf  = "shelf"  // item at its storage location, with reorder point
+	LabelKindDevice = "device" // medical device with serial number and next inspections
+)
+
+// LabelTemplate is the label stock and symbology for one kind of label
+type LabelTemplate struct {
+	ID           string     `db:"id" json:"id"`
+	Name         string     `db:"name" json:"name"`
+	Kind         string     `db:"kind" json:"kind"`
+	WidthMM      float64    `db:"width_mm" json:"width_mm"`
+	HeightMM     float64    `db:"height_mm" json:"height_mm"`
+	DPI          int        `db:"dpi" json:"dpi"`
+	Symbology    string     `db:"symbology" json:"symbology"`
+	SheetColumns int        `db:"sheet_columns" json:"sheet_columns"`
+	SheetRows    int        `db:"sheet_rows" json:"sheet_rows"`
+	IsDefault    bool       `db:"is_default" json:"is_default"`
+	CreatedBy    *string    `db:"created_by" json:"created_by,omitempty"`
+	CreatedAt    time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt    time.Time  `db:"updated_at" json:"updated_at"`
+	DeletedAt    *time.Time `db:"deleted_at" json:"-"`
+}
+
+// LabelTemplateRepository handles label template persistence
+type LabelTemplateRepository struct {
+	db *database.DB
+}
+
+// NewLabelTemplateRepository creates a new label template repository
+func NewLabelTemplateRepository(db *database.DB) *LabelTemplateRepository {
+	return &LabelTemplateRepository{db: db}
+}
+
+const labelTemplateSelect = `
+	SELECT id, name, kind, width_mm, height_mm, dpi, symbology, sheet_columns, sheet_rows,
+	       is_default, created_by, created_at, updated_at, deleted_at
+	FROM label_templates
+`
+
+// clearDefault unsets the default of a kind, except for template id, using
+// the transaction in ctx
+func (r *LabelTemplateRepository) clearDefault(ctx context.Context, kind, id string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE label_templates SET is_default = FALSE
+		WHERE kind = $1 AND id <> $2 AND is_default AND deleted_at IS NULL
+	`, kind, id)
+	return err
+}
+
+// Create creates a template; a new default replaces the kind's previous one
+// TENANT-ISOLATED: Inserts with tenant_id for RLS
+func (r *LabelTemplateRepository) Create(ctx context.Context, t *LabelTemplate) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		if t.IsDefault {
+			if err := r.clearDefault(ctx, t.Kind, t.ID); err != nil {
+				return err
+			}
+		}
+
+		query := `
+			INSERT INTO label_templates (
+				id, tenant_id, name, kind, width_mm, height_mm, dpi, symbology,
+				sheet_columns, sheet_rows, is_default, created_by
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+			RETURNING created_at, updated_at
+		`
+		err := r.db.QueryRowxContext(ctx, query,
+			t.ID, tenantID, t.Name, t.Kind, t.WidthMM, t.HeightMM, t.DPI, t.Symbology,
+			t.SheetColumns, t.SheetRows, t.IsDefault, t.CreatedBy,
+		).Scan(&t.CreatedAt, &t.UpdatedAt)
+		if err != nil {
+			if appErr := database.MapPQError(err); appErr != nil {
+				return appErr
+			}
+			return err
+		}
+		return nil
+	})
+}
+
+// GetByID gets a template
+// TENANT-ISOLATED: Queries via RLS
+func (r *LabelTemplateRepository) GetByID(ctx context.Context, id string) (*LabelTemplate, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var t LabelTemplate
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		return r.db.GetContext(ctx, &t, labelTemplateSelect+` WHERE id = $1 AND deleted_at IS NULL`, id)
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFound("label_template")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+// GetDefault gets the default template of a kind
+// TENANT-ISOLATED: Queries via RLS
+func (r *LabelTemplateRepository) GetDefault(ctx context.Context, kind string) (*LabelTemplate, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var t LabelTemplate
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		return r.db.GetContext(ctx, &t, labelTemplateSelect+` WHERE kind = $1 AND is_default AND deleted_at IS NULL`, kind)
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFound("label_template")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+// labelTemplateListSchema whitelists the filter and sort fields of GET /label-templates
+var labelTemplateListSchema = database.ListSchema{
+	Fields: map[string]database.Field{
+		"name":       {Column: "name", Type: database.FieldText, Filter: true, Sort: true},
+		"kind":       {Column: "kind", Type: database.FieldText, Filter: true, Sort: true},
+		"symbology":  {Column: "symbology", Type: database.FieldText, Filter: true},
+		"is_default": {Column: "is_default", Type: database.FieldBool, Filter: true},
+		"created_at": {Column: "created_at", Type: database.FieldTime, Filter: true, Sort: true},
+	},
+	DefaultSort: []string{"kind", "name"},
+}
+
+// List lists templates filtered, sorted and paginated by q
+// TENANT-ISOLATED: Returns only templates via RLS
+func (r *LabelTemplateRepository) List(ctx context.Context, q *database.ListQuery) (*database.Page[*LabelTemplate], error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var page *database.Page[*LabelTemplate]
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		var err error
+		page, err = database.SelectPage[*LabelTemplate](ctx, r.db, &labelTemplateListSchema, q,
+			labelTemplateSelect+` WHERE deleted_at IS NULL`)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// Update replaces a template; a new default replaces the kind's previous one
+// TENANT-ISOLATED: Updates via RLS
+func (r *LabelTemplateRepository) Update(ctx context.Context, t *LabelTemplate) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		if t.IsDefault {
+			if err := r.clearDefault(ctx, t.Kind, t.ID); err != nil {
+				return err
+			}
+		}
+
+		query := `
+			UPDATE label_templates SET
+				name = $2, kind = $3, width_mm = $4, height_mm = $5, dpi = $6, symbology = $7,
+				sheet_columns = $8, sheet_rows = $9, is_default = $10
+			WHERE id = $1 AND deleted_at IS NULL
+			RETURNING created_by, created_at, updated_at
+		`
+		err := r.db.QueryRowxContext(ctx, query,
+			t.ID, t.Name, t.Kind, t.WidthMM, t.HeightMM, t.DPI, t.Symbology,
+			t.SheetColumns, t.SheetRows, t.IsDefault,
+		).Scan(&t.CreatedBy, &t.CreatedAt, &t.UpdatedAt)
+		if err == sql.ErrNoRows {
+			return errors.NotFound("label_template")
+		}
+		if err != nil {
+			if appErr := database.MapPQError(err); appErr != nil {
+				return appErr
+			}
+			return err
+		}
+		return nil
+	})
+}
+
+// Delete soft-deletes a template
+// TENANT-ISOLATED: Updates via RLS
+func (r *LabelTemplateRepository) Delete(ctx context.Context, id string) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		result, err := r.db.ExecContext(ctx, `
+			UPDATE label_templates SET deleted_at = NOW(), is_default = FALSE
+			WHERE id = $1 AND deleted_at IS NULL
+		`, id)
+		if err != nil {
+			return err
+		}
+		rows, _ := result.RowsAffected()
+		if rows == 0 {
+			return errors.NotFound("label_template")
+		}
+		return nil
+	})
+}
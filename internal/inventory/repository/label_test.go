@@ -0,0 +1,89 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newLabelTemplate(name, kind string, isDefault bool) *repository.LabelTemplate {
+	return &repository.LabelTemplate{
+		Name:      name,
+		Kind:      kind,
+		WidthMM:   57,
+		HeightMM:  32,
+		DPI:       203,
+		Symbology: "datamatrix",
+		IsDefault: isDefault,
+	}
+}
+
+func TestLabelTemplateRepository_DefaultPerKind(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tenant := suite.SetupInventoryTenant(t, ctx, "label-default")
+	tenantCtx := suite.TenantContext(tenant)
+
+	repo := repository.NewLabelTemplateRepository(suite.DB)
+
+	_, err := repo.GetDefault(tenantCtx, repository.LabelKindBatch)
+	assert.True(t, errors.Is(err, errors.ErrNotFound))
+
+	first := newLabelTemplate("Charge klein", repository.LabelKindBatch, true)
+	require.NoError(t, repo.Create(tenantCtx, first))
+	shelf := newLabelTemplate("Regal", repository.LabelKindShelf, true)
+	require.NoError(t, repo.Create(tenantCtx, shelf))
+
+	// A new default replaces the previous one of the same kind only
+	second := newLabelTemplate("Charge groß", repository.LabelKindBatch, true)
+	require.NoError(t, repo.Create(tenantCtx, second))
+
+	def, err := repo.GetDefault(tenantCtx, repository.LabelKindBatch)
+	require.NoError(t, err)
+	assert.Equal(t, second.ID, def.ID)
+
+	got, err := repo.GetByID(tenantCtx, first.ID)
+	require.NoError(t, err)
+	assert.False(t, got.IsDefault)
+
+	def, err = repo.GetDefault(tenantCtx, repository.LabelKindShelf)
+	require.NoError(t, err)
+	assert.Equal(t, shelf.ID, def.ID)
+
+	// Making the first one default again through Update
+	first.IsDefault = true
+	first.WidthMM = 62
+	require.NoError(t, repo.Update(tenantCtx, first))
+	def, err = repo.GetDefault(tenantCtx, repository.LabelKindBatch)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, def.ID)
+	assert.Equal(t, 62.0, def.WidthMM)
+
+	// Deleting the default leaves the kind without one
+	require.NoError(t, repo.Delete(tenantCtx, first.ID))
+	_, err = repo.GetDefault(tenantCtx, repository.LabelKindBatch)
+	assert.True(t, errors.Is(err, errors.ErrNotFound))
+	_, err = repo.GetByID(tenantCtx, first.ID)
+	assert.True(t, errors.Is(err, errors.ErrNotFound))
+}
+
+func TestLabelTemplateRepository_DuplicateName(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tenant := suite.SetupInventoryTenant(t, ctx, "label-duplicate")
+	tenantCtx := suite.TenantContext(tenant)
+
+	repo := repository.NewLabelTemplateRepository(suite.DB)
+
+	require.NoError(t, repo.Create(tenantCtx, newLabelTemplate("Charge", repository.LabelKindBatch, false)))
+	err := repo.Create(tenantCtx, newLabelTemplate("Charge", repository.LabelKindDevice, false))
+	assert.True(t, errors.Is(err, errors.ErrConflict))
+}
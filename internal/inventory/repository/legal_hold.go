@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/retention"
+	"github.com/medflow/medflow-backend/pkg/tenant"
+)
+
+// LegalHold suspends deleting and purging the records of an entity type, or
+// of one record, e.g. during litigation or a tax audit
+type LegalHold struct {
+	ID          string     `db:"id" json:"id"`
+	EntityType  string     `db:"entity_type" json:"entity_type"`
+	EntityID    *string    `db:"entity_id" json:"entity_id,omitempty"`
+	Reason      string     `db:"reason" json:"reason"`
+	Reference   *string    `db:"reference" json:"reference,omitempty"`
+	CreatedBy   *string    `db:"created_by" json:"created_by,omitempty"`
+	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
+	ReleasedAt  *time.Time `db:"released_at" json:"released_at,omitempty"`
+	ReleasedBy  *string    `db:"released_by" json:"released_by,omitempty"`
+	ReleaseNote *string    `db:"release_note" json:"release_note,omitempty"`
+}
+
+// LegalHoldRepository handles legal hold and deletion protocol persistence
+type LegalHoldRepository struct {
+	db *database.DB
+}
+
+// NewLegalHoldRepository creates a new legal hold repository
+func NewLegalHoldRepository(db *database.DB) *LegalHoldRepository {
+	return &LegalHoldRepository{db: db}
+}
+
+const legalHoldSelect = `
+	SELECT id, entity_type, entity_id, reason, reference, created_by, created_at,
+	       released_at, released_by, release_note
+	FROM legal_holds
+`
+
+// Create places a legal hold
+// TENANT-ISOLATED: Inserts with tenant_id for RLS
+func (r *LegalHoldRepository) Create(ctx context.Context, hold *LegalHold) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	if hold.ID == "" {
+		hold.ID = uuid.New().String()
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			INSERT INTO legal_holds (
+				id, tenant_id, entity_type, entity_id, reason, reference, created_by
+			) VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING created_at
+		`
+		err := r.db.QueryRowxContext(ctx, query,
+			hold.ID, tenantID, hold.EntityType, hold.EntityID, hold.Reason, hold.Reference, hold.CreatedBy,
+		).Scan(&hold.CreatedAt)
+		if appErr := database.MapPQError(err); appErr != nil {
+			return appErr
+		}
+		return err
+	})
+}
+
+// GetByID gets a legal hold by ID
+// TENANT-ISOLATED: Queries via RLS
+func (r *LegalHoldRepository) GetByID(ctx context.Context, id string) (*LegalHold, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var hold LegalHold
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		return r.db.GetContext(ctx, &hold, legalHoldSelect+` WHERE id = $1`, id)
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFound("legal_hold")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &hold, nil
+}
+
+var legalHoldListSchema = database.ListSchema{
+	Fields: map[string]database.Field{
+		"entity_type": {Column: "entity_type", Type: database.FieldText, Filter: true, Sort: true},
+		"entity_id":   {Column: "entity_id", Type: database.FieldUUID, Filter: true, Nullable: true},
+		"created_at":  {Column: "created_at", Type: database.FieldTime, Filter: true, Sort: true},
+		"released_at": {Column: "released_at", Type: database.FieldTime, Filter: true, Sort: true, Nullable: true},
+	},
+	DefaultSort: []string{"-created_at"},
+}
+
+// List lists legal holds filtered, sorted and paginated by q; released
+// holds are included unless filtered out with filter[released_at][null]=true
+// TENANT-ISOLATED: Returns only holds via RLS
+func (r *LegalHoldRepository) List(ctx context.Context, q *database.ListQuery) (*database.Page[*LegalHold], error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var page *database.Page[*LegalHold]
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		var err error
+		page, err = database.SelectPage[*LegalHold](ctx, r.db, &legalHoldListSchema, q,
+			legalHoldSelect+` WHERE 1=1`)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// Release lifts a legal hold
+// TENANT-ISOLATED: Updates via RLS
+func (r *LegalHoldRepository) Release(ctx context.Context, hold *LegalHold) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		var releasedAt *time.Time
+		err := r.db.GetContext(ctx, &releasedAt, `SELECT released_at FROM legal_holds WHERE id = $1 FOR UPDATE`, hold.ID)
+		if err == sql.ErrNoRows {
+			return errors.NotFound("legal_hold")
+		}
+		if err != nil {
+			return err
+		}
+		if releasedAt != nil {
+			return errors.Conflict("errors.legal_hold.already_released")
+		}
+
+		return r.db.GetContext(ctx, hold, `
+			UPDATE legal_holds SET released_at = NOW(), released_by = $2, release_note = $3
+			WHERE id = $1
+			RETURNING id, entity_type, entity_id, reason, reference, created_by, created_at,
+			          released_at, released_by, release_note
+		`, hold.ID, hold.ReleasedBy, hold.ReleaseNote)
+	})
+}
+
+var deletionProtocolListSchema = database.ListSchema{
+	Fields: map[string]database.Field{
+		"entity_type": {Column: "entity_type", Type: database.FieldText, Filter: true, Sort: true},
+		"table_name":  {Column: "table_name", Type: database.FieldText, Filter: true},
+		"action":      {Column: "action", Type: database.FieldText, Filter: true},
+		"executed_at": {Column: "executed_at", Type: database.FieldTime, Filter: true, Sort: true},
+	},
+	DefaultSort: []string{"-executed_at"},
+}
+
+// ListProtocols lists the deletion protocol filtered, sorted and paginated by q
+// TENANT-ISOLATED: Returns only protocol entries via RLS
+func (r *LegalHoldRepository) ListProtocols(ctx context.Context, q *database.ListQuery) (*database.Page[*retention.Protocol], error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var page *database.Page[*retention.Protocol]
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		var err error
+		page, err = database.SelectPage[*retention.Protocol](ctx, r.db, &deletionProtocolListSchema, q, `
+			SELECT id, entity_type, table_name, action, legal_basis, retention_years, cutoff_date,
+			       record_count, record_ids, executed_by, executed_by_name, executed_at
+			FROM deletion_protocols WHERE 1=1
+		`)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
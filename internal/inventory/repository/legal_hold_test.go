@@ -0,0 +1,207 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/retention"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testInspectionRecord = retention.Record{
+		EntityType: "device_book", Table: "inventory.device_inspections", DateColumn: "t.inspection_date",
+	}
+	testTrainingRecord = retention.Record{
+		EntityType: "device_book", Table: "inventory.device_trainings", DateColumn: "t.training_date",
+		Anonymize: "trainer_name = 'Anonymisiert', attendee_names = 'Anonymisiert'",
+	}
+)
+
+func createDeviceBookPolicy(t *testing.T, tenantCtx context.Context, purgeAction string) {
+	t.Helper()
+	legalBasis := "MPBetreibV §12"
+	require.NoError(t, repository.NewRetentionRepository(suite.DB).Create(tenantCtx, &repository.RetentionPolicy{
+		EntityType:     "device_book",
+		RetentionYears: 5,
+		LegalBasis:     &legalBasis,
+		PurgeAction:    purgeAction,
+	}))
+}
+
+func createInspectionOn(t *testing.T, tenantCtx context.Context, itemID string, date time.Time) *repository.DeviceInspection {
+	t.Helper()
+	insp := &repository.DeviceInspection{
+		ItemID:         itemID,
+		InspectionType: "STK",
+		InspectionDate: date,
+		Result:         "passed",
+		PerformedBy:    "TUeV Sued",
+	}
+	require.NoError(t, repository.NewInspectionRepository(suite.DB).Create(tenantCtx, insp))
+	return insp
+}
+
+func TestRetentionGuard_Check(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tenant := suite.SetupInventoryTenant(t, ctx, "retention-guard")
+	tenantCtx := suite.TenantContext(tenant)
+
+	item := createTestItem(t, tenantCtx, repository.NewItemRepository(suite.DB), "Retention Device")
+	recent := createInspectionOn(t, tenantCtx, item.ID, time.Now().AddDate(-1, 0, 0))
+	old := createInspectionOn(t, tenantCtx, item.ID, time.Now().AddDate(-6, 0, 0))
+
+	guard := retention.NewGuard(suite.DB, suite.Logger)
+
+	// Without a policy every delete is allowed
+	assert.NoError(t, guard.Check(tenantCtx, recent.ID, testInspectionRecord))
+
+	createDeviceBookPolicy(t, tenantCtx, retention.ActionNone)
+
+	err := guard.Check(tenantCtx, recent.ID, testInspectionRecord)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, errors.ErrConflict))
+	var appErr *errors.AppError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, "errors.retention.period_active", appErr.MessageKey)
+	assert.Equal(t, "MPBetreibV §12", appErr.Params["legal_basis"])
+
+	assert.NoError(t, guard.Check(tenantCtx, old.ID, testInspectionRecord))
+
+	// A hold on the record blocks the delete even after the period
+	holdRepo := repository.NewLegalHoldRepository(suite.DB)
+	reference := "Az. 4 O 123/26"
+	hold := &repository.LegalHold{EntityType: "device_book", EntityID: &old.ID, Reason: "Rechtsstreit", Reference: &reference}
+	require.NoError(t, holdRepo.Create(tenantCtx, hold))
+
+	err = guard.Check(tenantCtx, old.ID, testInspectionRecord)
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, "errors.retention.legal_hold", appErr.MessageKey)
+	assert.Equal(t, reference, appErr.Params["reference"])
+
+	require.NoError(t, holdRepo.Release(tenantCtx, hold))
+	require.NotNil(t, hold.ReleasedAt)
+	assert.NoError(t, guard.Check(tenantCtx, old.ID, testInspectionRecord))
+
+	err = holdRepo.Release(tenantCtx, hold)
+	assert.True(t, errors.Is(err, errors.ErrConflict))
+
+	// Unknown records are left to the delete
+	assert.NoError(t, guard.Check(tenantCtx, "00000000-0000-0000-0000-000000000001", testInspectionRecord))
+}
+
+func TestRetentionPurger_Run(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tenant := suite.SetupInventoryTenant(t, ctx, "retention-purge")
+	tenantCtx := suite.TenantContext(tenant)
+
+	item := createTestItem(t, tenantCtx, repository.NewItemRepository(suite.DB), "Purge Device")
+	recent := createInspectionOn(t, tenantCtx, item.ID, time.Now().AddDate(-1, 0, 0))
+	old := createInspectionOn(t, tenantCtx, item.ID, time.Now().AddDate(-6, 0, 0))
+	held := createInspectionOn(t, tenantCtx, item.ID, time.Now().AddDate(-7, 0, 0))
+
+	trainingRepo := repository.NewTrainingRepository(suite.DB)
+	training := &repository.DeviceTraining{
+		ItemID:        item.ID,
+		TrainingDate:  time.Now().AddDate(-6, 0, 0),
+		TrainerName:   "Dr. Schmidt",
+		AttendeeNames: "Mueller, Weber",
+	}
+	require.NoError(t, trainingRepo.Create(tenantCtx, training))
+
+	holdRepo := repository.NewLegalHoldRepository(suite.DB)
+	require.NoError(t, holdRepo.Create(tenantCtx, &repository.LegalHold{EntityType: "device_book", EntityID: &held.ID, Reason: "Betriebsprüfung"}))
+
+	purger := retention.NewPurger(suite.DB, suite.Logger, testInspectionRecord, testTrainingRecord)
+
+	// Purge action none keeps everything
+	createDeviceBookPolicy(t, tenantCtx, retention.ActionNone)
+	require.NoError(t, purger.Run(tenantCtx))
+	protocols, err := holdRepo.ListProtocols(tenantCtx, &database.ListQuery{})
+	require.NoError(t, err)
+	assert.Empty(t, protocols.Items)
+
+	// Delete removes the expired records that are not on hold
+	policy, err := repository.NewRetentionRepository(suite.DB).GetByEntityType(tenantCtx, "device_book")
+	require.NoError(t, err)
+	policy.PurgeAction = retention.ActionDelete
+	require.NoError(t, repository.NewRetentionRepository(suite.DB).Update(tenantCtx, policy))
+	require.NoError(t, purger.Run(tenantCtx))
+
+	inspectionRepo := repository.NewInspectionRepository(suite.DB)
+	_, err = inspectionRepo.GetByID(tenantCtx, old.ID)
+	assert.True(t, errors.Is(err, errors.ErrNotFound))
+	_, err = inspectionRepo.GetByID(tenantCtx, recent.ID)
+	assert.NoError(t, err)
+	_, err = inspectionRepo.GetByID(tenantCtx, held.ID)
+	assert.NoError(t, err)
+
+	protocols, err = holdRepo.ListProtocols(tenantCtx, &database.ListQuery{})
+	require.NoError(t, err)
+	require.Len(t, protocols.Items, 2)
+	byTable := map[string]*retention.Protocol{}
+	for _, p := range protocols.Items {
+		byTable[p.TableName] = p
+	}
+	require.Contains(t, byTable, "inventory.device_inspections")
+	assert.Equal(t, retention.ActionDelete, byTable["inventory.device_inspections"].Action)
+	assert.Equal(t, []string{old.ID}, []string(byTable["inventory.device_inspections"].RecordIDs))
+	require.Contains(t, byTable, "inventory.device_trainings")
+	assert.Equal(t, []string{training.ID}, []string(byTable["inventory.device_trainings"].RecordIDs))
+
+	// A second run finds nothing new
+	require.NoError(t, purger.Run(tenantCtx))
+	protocols, err = holdRepo.ListProtocols(tenantCtx, &database.ListQuery{})
+	require.NoError(t, err)
+	assert.Len(t, protocols.Items, 2)
+}
+
+func TestRetentionPurger_Anonymize(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tenant := suite.SetupInventoryTenant(t, ctx, "retention-anon")
+	tenantCtx := suite.TenantContext(tenant)
+
+	item := createTestItem(t, tenantCtx, repository.NewItemRepository(suite.DB), "Anonymize Device")
+	trainingRepo := repository.NewTrainingRepository(suite.DB)
+	training := &repository.DeviceTraining{
+		ItemID:        item.ID,
+		TrainingDate:  time.Now().AddDate(-6, 0, 0),
+		TrainerName:   "Dr. Schmidt",
+		AttendeeNames: "Mueller, Weber",
+	}
+	require.NoError(t, trainingRepo.Create(tenantCtx, training))
+	old := createInspectionOn(t, tenantCtx, item.ID, time.Now().AddDate(-6, 0, 0))
+
+	createDeviceBookPolicy(t, tenantCtx, retention.ActionAnonymize)
+	purger := retention.NewPurger(suite.DB, suite.Logger, testInspectionRecord, testTrainingRecord)
+	require.NoError(t, purger.Run(tenantCtx))
+
+	got, err := trainingRepo.GetByID(tenantCtx, training.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Anonymisiert", got.TrainerName)
+	assert.Equal(t, "Anonymisiert", got.AttendeeNames)
+
+	// Inspections hold no personal data and are kept
+	_, err = repository.NewInspectionRepository(suite.DB).GetByID(tenantCtx, old.ID)
+	assert.NoError(t, err)
+
+	protocols, err := repository.NewLegalHoldRepository(suite.DB).ListProtocols(tenantCtx, &database.ListQuery{})
+	require.NoError(t, err)
+	require.Len(t, protocols.Items, 1)
+	assert.Equal(t, retention.ActionAnonymize, protocols.Items[0].Action)
+	assert.Equal(t, "inventory.device_trainings", protocols.Items[0].TableName)
+}
@@ -38,6 +38,7 @@ type StorageCabinet struct {
 	MinTemperature               *float64  `db:"min_temperature_celsius" json:"min_temperature_celsius,omitempty"`
 	MaxTemperature               *float64  `db:"max_temperature_celsius" json:"max_temperature_celsius,omitempty"`
 	TemperatureMonitoringEnabled bool      `db:"temperature_monitoring_enabled" json:"temperature_monitoring_enabled"`
+	ExcursionGraceMinutes        *int      `db:"excursion_grace_minutes" json:"excursion_grace_minutes,omitempty"` // nil = 15 minutes
 	CreatedAt                    time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt                    time.Time `db:"updated_at" json:"updated_at"`
 }
@@ -216,14 +217,16 @@ func (r *LocationRepository) CreateCabinet(ctx context.Context, cabinet *Storage
 		query := `
 			INSERT INTO storage_cabinets (id, tenant_id, room_id, name, description, temperature_controlled,
 			       target_temperature_celsius, requires_key, is_active,
-			       min_temperature_celsius, max_temperature_celsius, temperature_monitoring_enabled)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+			       min_temperature_celsius, max_temperature_celsius, temperature_monitoring_enabled,
+			       excursion_grace_minutes)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 			RETURNING created_at, updated_at
 		`
 		return r.db.QueryRowxContext(ctx, query,
 			cabinet.ID, tenantID, cabinet.RoomID, cabinet.Name, cabinet.Description,
 			cabinet.TemperatureControlled, cabinet.TargetTemperature, cabinet.RequiresKey, cabinet.IsActive,
 			cabinet.MinTemperature, cabinet.MaxTemperature, cabinet.TemperatureMonitoringEnabled,
+			cabinet.ExcursionGraceMinutes,
 		).Scan(&cabinet.CreatedAt, &cabinet.UpdatedAt)
 	})
 }
@@ -241,7 +244,7 @@ func (r *LocationRepository) GetCabinet(ctx context.Context, id string) (*Storag
 			SELECT id, room_id, name, description, temperature_controlled,
 			       target_temperature_celsius, requires_key, is_active,
 			       min_temperature_celsius, max_temperature_celsius, temperature_monitoring_enabled,
-			       created_at, updated_at
+			       excursion_grace_minutes, created_at, updated_at
 			FROM storage_cabinets WHERE id = $1 AND deleted_at IS NULL
 		`
 		return r.db.GetContext(ctx, &cabinet, query, id)
@@ -269,7 +272,7 @@ func (r *LocationRepository) ListCabinets(ctx context.Context, roomID string) ([
 			SELECT id, room_id, name, description, temperature_controlled,
 			       target_temperature_celsius, requires_key, is_active,
 			       min_temperature_celsius, max_temperature_celsius, temperature_monitoring_enabled,
-			       created_at, updated_at
+			       excursion_grace_minutes, created_at, updated_at
 			FROM storage_cabinets WHERE room_id = $1 AND is_active = true AND deleted_at IS NULL ORDER BY name
 		`
 		return r.db.SelectContext(ctx, &cabinets, query, roomID)
@@ -294,7 +297,7 @@ func (r *LocationRepository) ListAllCabinets(ctx context.Context) ([]*StorageCab
 			SELECT id, room_id, name, description, temperature_controlled,
 			       target_temperature_celsius, requires_key, is_active,
 			       min_temperature_celsius, max_temperature_celsius, temperature_monitoring_enabled,
-			       created_at, updated_at
+			       excursion_grace_minutes, created_at, updated_at
 			FROM storage_cabinets WHERE is_active = true AND deleted_at IS NULL ORDER BY name
 		`
 		return r.db.SelectContext(ctx, &cabinets, query)
@@ -318,13 +321,14 @@ func (r *LocationRepository) UpdateCabinet(ctx context.Context, cabinet *Storage
 			UPDATE storage_cabinets SET room_id = $2, name = $3, description = $4, temperature_controlled = $5,
 			target_temperature_celsius = $6, requires_key = $7, is_active = $8,
 			min_temperature_celsius = $9, max_temperature_celsius = $10, temperature_monitoring_enabled = $11,
-			updated_at = NOW()
+			excursion_grace_minutes = $12, updated_at = NOW()
 			WHERE id = $1 AND deleted_at IS NULL
 		`
 		result, err := r.db.ExecContext(ctx, query,
 			cabinet.ID, cabinet.RoomID, cabinet.Name, cabinet.Description,
 			cabinet.TemperatureControlled, cabinet.TargetTemperature, cabinet.RequiresKey, cabinet.IsActive,
 			cabinet.MinTemperature, cabinet.MaxTemperature, cabinet.TemperatureMonitoringEnabled,
+			cabinet.ExcursionGraceMinutes,
 		)
 		if err != nil {
 			return err
@@ -550,3 +554,48 @@ func (r *LocationRepository) GetTree(ctx context.Context) (*LocationTree, error)
 
 	return &LocationTree{Rooms: roomsWithCabinets}, nil
 }
+
+// ResolvedLocation describes where a location ID points in the hierarchy.
+// Batch and item locations may reference a room, a cabinet or a shelf.
+type ResolvedLocation struct {
+	ID                    string  `db:"id" json:"id"`
+	Kind                  string  `db:"kind" json:"kind"` // room, cabinet, shelf
+	Name                  string  `db:"name" json:"name"`
+	RoomID                string  `db:"room_id" json:"room_id"`
+	CabinetID             *string `db:"cabinet_id" json:"cabinet_id,omitempty"`
+	TemperatureControlled bool    `db:"temperature_controlled" json:"temperature_controlled"`
+}
+
+// ResolveLocation looks up a room, cabinet or shelf by ID
+// TENANT-ISOLATED: Queries via RLS
+func (r *LocationRepository) ResolveLocation(ctx context.Context, id string) (*ResolvedLocation, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var loc ResolvedLocation
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			SELECT s.id, 'shelf' AS kind, s.name, c.room_id, c.id AS cabinet_id, c.temperature_controlled
+			FROM storage_shelves s JOIN storage_cabinets c ON c.id = s.cabinet_id
+			WHERE s.id = $1 AND s.deleted_at IS NULL AND c.deleted_at IS NULL
+			UNION ALL
+			SELECT id, 'cabinet', name, room_id, id, temperature_controlled
+			FROM storage_cabinets WHERE id = $1 AND deleted_at IS NULL
+			UNION ALL
+			SELECT id, 'room', name, id, NULL, FALSE
+			FROM storage_rooms WHERE id = $1 AND deleted_at IS NULL
+			LIMIT 1
+		`
+		return r.db.GetContext(ctx, &loc, query, id)
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFound("location")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &loc, nil
+}
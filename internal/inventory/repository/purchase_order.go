@@ -0,0 +1,679 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/tenant"
+)
+
+// Purchase order statuses
+const (
+	PurchaseOrderDraft             = "draft"
+	PurchaseOrderSent              = "sent"
+	PurchaseOrderPartiallyReceived = "partially_received"
+	PurchaseOrderReceived          = "received"
+	PurchaseOrderCancelled         = "cancelled"
+)
+
+// openPurchaseOrderStatuses are the statuses of orders that may still deliver
+var openPurchaseOrderStatuses = []string{PurchaseOrderDraft, PurchaseOrderSent, PurchaseOrderPartiallyReceived}
+
+// PurchaseOrder represents an order (Bestellung) to a supplier
+type PurchaseOrder struct {
+	ID           string     `db:"id" json:"id"`
+	OrderNumber  string     `db:"order_number" json:"order_number"`
+	SupplierID   string     `db:"supplier_id" json:"supplier_id"`
+	Status       string     `db:"status" json:"status"` // draft, sent, partially_received, received, cancelled
+	Source       string     `db:"source" json:"source"` // manual, suggestion
+	ExpectedDate *time.Time `db:"expected_date" json:"expected_date,omitempty"`
+	SentAt       *time.Time `db:"sent_at" json:"sent_at,omitempty"`
+	ReceivedAt   *time.Time `db:"received_at" json:"received_at,omitempty"`
+	CancelledAt  *time.Time `db:"cancelled_at" json:"cancelled_at,omitempty"`
+	CancelReason *string    `db:"cancel_reason" json:"cancel_reason,omitempty"`
+	Notes        *string    `db:"notes" json:"notes,omitempty"`
+	CreatedBy    *string    `db:"created_by" json:"created_by,omitempty"`
+	CreatedAt    time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt    time.Time  `db:"updated_at" json:"updated_at"`
+	DeletedAt    *time.Time `db:"deleted_at" json:"-"`
+	// Computed fields for API (not in purchase_orders)
+	SupplierName string               `db:"supplier_name" json:"supplier_name"`
+	TotalCents   int64                `db:"total_cents" json:"total_cents"`
+	Lines        []*PurchaseOrderLine `db:"-" json:"lines,omitempty"`
+}
+
+// PurchaseOrderLine is an ordered item
+type PurchaseOrderLine struct {
+	ID                    string    `db:"id" json:"id"`
+	PurchaseOrderID       string    `db:"purchase_order_id" json:"purchase_order_id"`
+	ItemID                string    `db:"item_id" json:"item_id"`
+	QuantityOrdered       int       `db:"quantity_ordered" json:"quantity_ordered"`
+	QuantityReceived      int       `db:"quantity_received" json:"quantity_received"`
+	UnitPriceCents        *int      `db:"unit_price_cents" json:"unit_price_cents,omitempty"`
+	SupplierArticleNumber *string   `db:"supplier_article_number" json:"supplier_article_number,omitempty"`
+	Notes                 *string   `db:"notes" json:"notes,omitempty"`
+	CreatedAt             time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt             time.Time `db:"updated_at" json:"updated_at"`
+	// Joined fields for API (not in purchase_order_lines)
+	ItemName string `db:"item_name" json:"item_name"`
+	Unit     string `db:"unit" json:"unit"`
+}
+
+// QuantityOpen returns the quantity still to be delivered
+func (l *PurchaseOrderLine) QuantityOpen() int {
+	return l.QuantityOrdered - l.QuantityReceived
+}
+
+// GoodsReceipt is a delivery (Wareneingang) booked against a purchase order
+type GoodsReceipt struct {
+	ID                 string              `db:"id" json:"id"`
+	PurchaseOrderID    string              `db:"purchase_order_id" json:"purchase_order_id"`
+	DeliveryNoteNumber *string             `db:"delivery_note_number" json:"delivery_note_number,omitempty"`
+	ReceivedAt         time.Time           `db:"received_at" json:"received_at"`
+	ReceivedBy         *string             `db:"received_by" json:"received_by,omitempty"`
+	ReceivedByName     *string             `db:"received_by_name" json:"received_by_name,omitempty"`
+	Notes              *string             `db:"notes" json:"notes,omitempty"`
+	CreatedAt          time.Time           `db:"created_at" json:"created_at"`
+	Lines              []*GoodsReceiptLine `db:"-" json:"lines,omitempty"`
+}
+
+// GoodsReceiptLine is a received batch of an ordered item
+type GoodsReceiptLine struct {
+	ID                  string    `db:"id" json:"id"`
+	GoodsReceiptID      string    `db:"goods_receipt_id" json:"goods_receipt_id"`
+	PurchaseOrderLineID string    `db:"purchase_order_line_id" json:"purchase_order_line_id"`
+	ItemID              string    `db:"item_id" json:"item_id"`
+	BatchID             string    `db:"batch_id" json:"batch_id"`
+	Quantity            int       `db:"quantity" json:"quantity"`
+	CreatedAt           time.Time `db:"created_at" json:"created_at"`
+	// Batch is the batch created by the receipt; Receive takes the batch
+	// number, expiry and location from it
+	Batch *InventoryBatch `db:"-" json:"batch,omitempty"`
+	// Adjustment is the receipt booking created for the batch
+	Adjustment *StockAdjustment `db:"-" json:"-"`
+}
+
+// PurchaseOrderRepository handles purchase order and goods receipt persistence
+type PurchaseOrderRepository struct {
+	db *database.DB
+}
+
+// NewPurchaseOrderRepository creates a new purchase order repository
+func NewPurchaseOrderRepository(db *database.DB) *PurchaseOrderRepository {
+	return &PurchaseOrderRepository{db: db}
+}
+
+// purchaseOrderSelect selects orders with the supplier name and order total
+const purchaseOrderSelect = `
+	SELECT po.id, po.order_number, po.supplier_id, po.status, po.source, po.expected_date,
+	       po.sent_at, po.received_at, po.cancelled_at, po.cancel_reason, po.notes,
+	       po.created_by, po.created_at, po.updated_at, po.deleted_at,
+	       s.name AS supplier_name,
+	       COALESCE((SELECT SUM(l.quantity_ordered * COALESCE(l.unit_price_cents, 0))
+	                 FROM purchase_order_lines l WHERE l.purchase_order_id = po.id), 0) AS total_cents
+	FROM purchase_orders po
+	JOIN suppliers s ON s.id = po.supplier_id
+`
+
+const purchaseOrderLineSelect = `
+	SELECT l.id, l.purchase_order_id, l.item_id, l.quantity_ordered, l.quantity_received,
+	       l.unit_price_cents, l.supplier_article_number, l.notes, l.created_at, l.updated_at,
+	       i.name AS item_name, i.unit
+	FROM purchase_order_lines l
+	JOIN inventory_items i ON i.id = l.item_id
+`
+
+// Create creates a purchase order with its lines and assigns the next order
+// number of the year (PO-2025-0001)
+// TENANT-ISOLATED: Inserts with tenant_id for RLS
+func (r *PurchaseOrderRepository) Create(ctx context.Context, po *PurchaseOrder) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	if po.ID == "" {
+		po.ID = uuid.New().String()
+	}
+	if po.Status == "" {
+		po.Status = PurchaseOrderDraft
+	}
+	if po.Source == "" {
+		po.Source = "manual"
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		// Serialize numbering per tenant for the rest of the transaction
+		if _, err := r.db.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('purchase_orders:' || $1))`, tenantID); err != nil {
+			return err
+		}
+
+		prefix := fmt.Sprintf("PO-%d-", time.Now().Year())
+		var count int
+		if err := r.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM purchase_orders WHERE order_number LIKE $1`, prefix+"%"); err != nil {
+			return err
+		}
+		po.OrderNumber = fmt.Sprintf("%s%04d", prefix, count+1)
+
+		query := `
+			INSERT INTO purchase_orders (
+				id, tenant_id, order_number, supplier_id, status, source, expected_date, notes, created_by
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			RETURNING created_at, updated_at
+		`
+		if err := r.db.QueryRowxContext(ctx, query,
+			po.ID, tenantID, po.OrderNumber, po.SupplierID, po.Status, po.Source,
+			po.ExpectedDate, po.Notes, po.CreatedBy,
+		).Scan(&po.CreatedAt, &po.UpdatedAt); err != nil {
+			if appErr := database.MapPQError(err); appErr != nil {
+				return appErr
+			}
+			return err
+		}
+
+		return r.insertLines(ctx, tenantID, po)
+	})
+}
+
+// insertLines inserts the order's lines using the transaction in ctx
+func (r *PurchaseOrderRepository) insertLines(ctx context.Context, tenantID string, po *PurchaseOrder) error {
+	query := `
+		INSERT INTO purchase_order_lines (
+			id, tenant_id, purchase_order_id, item_id, quantity_ordered,
+			unit_price_cents, supplier_article_number, notes
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING created_at, updated_at
+	`
+
+	for _, line := range po.Lines {
+		if line.ID == "" {
+			line.ID = uuid.New().String()
+		}
+		line.PurchaseOrderID = po.ID
+
+		err := r.db.QueryRowxContext(ctx, query,
+			line.ID, tenantID, po.ID, line.ItemID, line.QuantityOrdered,
+			line.UnitPriceCents, line.SupplierArticleNumber, line.Notes,
+		).Scan(&line.CreatedAt, &line.UpdatedAt)
+		if err != nil {
+			if appErr := database.MapPQError(err); appErr != nil {
+				return appErr
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetByID gets a purchase order with its lines
+// TENANT-ISOLATED: Queries via RLS
+func (r *PurchaseOrderRepository) GetByID(ctx context.Context, id string) (*PurchaseOrder, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var po PurchaseOrder
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := purchaseOrderSelect + ` WHERE po.id = $1 AND po.deleted_at IS NULL`
+		if err := r.db.GetContext(ctx, &po, query, id); err != nil {
+			return err
+		}
+
+		linesQuery := purchaseOrderLineSelect + ` WHERE l.purchase_order_id = $1 ORDER BY i.name`
+		return r.db.SelectContext(ctx, &po.Lines, linesQuery, id)
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFound("purchase_order")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &po, nil
+}
+
+// purchaseOrderListSchema whitelists the filter and sort fields of GET /purchase-orders
+var purchaseOrderListSchema = database.ListSchema{
+	Fields: map[string]database.Field{
+		"order_number":  {Column: "order_number", Type: database.FieldText, Filter: true, Sort: true},
+		"supplier_id":   {Column: "supplier_id", Type: database.FieldUUID, Filter: true},
+		"supplier_name": {Column: "supplier_name", Type: database.FieldText, Filter: true, Sort: true},
+		"status":        {Column: "status", Type: database.FieldText, Filter: true, Sort: true},
+		"source":        {Column: "source", Type: database.FieldText, Filter: true},
+		"expected_date": {Column: "expected_date", Type: database.FieldDate, Filter: true, Sort: true, Nullable: true},
+		"sent_at":       {Column: "sent_at", Type: database.FieldTime, Filter: true, Sort: true, Nullable: true},
+		"total_cents":   {Column: "total_cents", Type: database.FieldInt, Filter: true, Sort: true},
+		"created_at":    {Column: "created_at", Type: database.FieldTime, Filter: true, Sort: true},
+	},
+	DefaultSort: []string{"-created_at"},
+}
+
+// List lists purchase orders (without lines) filtered, sorted and paginated by q
+// TENANT-ISOLATED: Returns only orders via RLS
+func (r *PurchaseOrderRepository) List(ctx context.Context, q *database.ListQuery) (*database.Page[*PurchaseOrder], error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var page *database.Page[*PurchaseOrder]
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		// Wrapped in a subquery so supplier_name and total_cents can be filtered and sorted on
+		query := `SELECT * FROM (` + purchaseOrderSelect + `) AS orders WHERE deleted_at IS NULL`
+		var err error
+		page, err = database.SelectPage[*PurchaseOrder](ctx, r.db, &purchaseOrderListSchema, q, query)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// lockOrder locks an order for the rest of the transaction and returns its status
+func (r *PurchaseOrderRepository) lockOrder(ctx context.Context, id string) (string, error) {
+	var status string
+	query := `SELECT status FROM purchase_orders WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+	if err := r.db.QueryRowxContext(ctx, query, id).Scan(&status); err != nil {
+		if err == sql.ErrNoRows {
+			return "", errors.NotFound("purchase_order")
+		}
+		return "", err
+	}
+	return status, nil
+}
+
+// invalidTransition is returned when an order's status does not allow an action
+func invalidTransition(status, action string) error {
+	return errors.Conflict("errors.purchase_order.invalid_status", map[string]string{"status": status, "action": action})
+}
+
+// Update replaces the supplier, expected date, notes and lines of a draft order
+// TENANT-ISOLATED: Updates via RLS
+func (r *PurchaseOrderRepository) Update(ctx context.Context, po *PurchaseOrder) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		status, err := r.lockOrder(ctx, po.ID)
+		if err != nil {
+			return err
+		}
+		if status != PurchaseOrderDraft {
+			return invalidTransition(status, "update")
+		}
+
+		query := `
+			UPDATE purchase_orders SET supplier_id = $2, expected_date = $3, notes = $4
+			WHERE id = $1
+			RETURNING order_number, status, source, created_at, updated_at
+		`
+		if err := r.db.QueryRowxContext(ctx, query, po.ID, po.SupplierID, po.ExpectedDate, po.Notes).
+			Scan(&po.OrderNumber, &po.Status, &po.Source, &po.CreatedAt, &po.UpdatedAt); err != nil {
+			if appErr := database.MapPQError(err); appErr != nil {
+				return appErr
+			}
+			return err
+		}
+
+		if _, err := r.db.ExecContext(ctx, `DELETE FROM purchase_order_lines WHERE purchase_order_id = $1`, po.ID); err != nil {
+			return err
+		}
+		return r.insertLines(ctx, tenantID, po)
+	})
+}
+
+// MarkSent marks a draft order as sent to the supplier
+// TENANT-ISOLATED: Updates via RLS
+func (r *PurchaseOrderRepository) MarkSent(ctx context.Context, id string, expectedDate *time.Time) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		status, err := r.lockOrder(ctx, id)
+		if err != nil {
+			return err
+		}
+		if status != PurchaseOrderDraft {
+			return invalidTransition(status, "send")
+		}
+
+		query := `
+			UPDATE purchase_orders SET status = $2, sent_at = NOW(), expected_date = COALESCE(expected_date, $3)
+			WHERE id = $1
+		`
+		_, err = r.db.ExecContext(ctx, query, id, PurchaseOrderSent, expectedDate)
+		return err
+	})
+}
+
+// Cancel cancels an order that has not been fully received. Quantities
+// already received stay booked.
+// TENANT-ISOLATED: Updates via RLS
+func (r *PurchaseOrderRepository) Cancel(ctx context.Context, id string, reason *string) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		status, err := r.lockOrder(ctx, id)
+		if err != nil {
+			return err
+		}
+		if status == PurchaseOrderReceived || status == PurchaseOrderCancelled {
+			return invalidTransition(status, "cancel")
+		}
+
+		query := `UPDATE purchase_orders SET status = $2, cancelled_at = NOW(), cancel_reason = $3 WHERE id = $1`
+		_, err = r.db.ExecContext(ctx, query, id, PurchaseOrderCancelled, reason)
+		return err
+	})
+}
+
+// Delete soft-deletes a draft order
+// TENANT-ISOLATED: Updates via RLS
+func (r *PurchaseOrderRepository) Delete(ctx context.Context, id string) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		status, err := r.lockOrder(ctx, id)
+		if err != nil {
+			return err
+		}
+		if status != PurchaseOrderDraft {
+			return invalidTransition(status, "delete")
+		}
+
+		_, err = r.db.ExecContext(ctx, `UPDATE purchase_orders SET deleted_at = NOW() WHERE id = $1`, id)
+		return err
+	})
+}
+
+// OpenQuantities returns, per item, the quantity on open orders (draft, sent
+// or partially received) that has not been delivered yet
+// TENANT-ISOLATED: Queries via RLS
+func (r *PurchaseOrderRepository) OpenQuantities(ctx context.Context) (map[string]int, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		ItemID   string `db:"item_id"`
+		Quantity int    `db:"quantity"`
+	}
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			SELECT l.item_id, SUM(l.quantity_ordered - l.quantity_received) AS quantity
+			FROM purchase_order_lines l
+			JOIN purchase_orders po ON po.id = l.purchase_order_id
+			WHERE po.deleted_at IS NULL AND po.status = ANY($1)
+			GROUP BY l.item_id
+		`
+		return r.db.SelectContext(ctx, &rows, query, pq.Array(openPurchaseOrderStatuses))
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	open := make(map[string]int, len(rows))
+	for _, row := range rows {
+		open[row.ItemID] = row.Quantity
+	}
+	return open, nil
+}
+
+// Receive books a goods receipt against an order in one transaction: for
+// every receipt line it creates the batch, books a 'receipt' stock adjustment
+// and raises the line's received quantity. The order becomes
+// partially_received, or received once every line is complete.
+// Over-deliveries are rejected.
+// TENANT-ISOLATED: Inserts with tenant_id for RLS
+func (r *PurchaseOrderRepository) Receive(ctx context.Context, receipt *GoodsReceipt) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	if receipt.ID == "" {
+		receipt.ID = uuid.New().String()
+	}
+	if receipt.ReceivedAt.IsZero() {
+		receipt.ReceivedAt = time.Now()
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		status, err := r.lockOrder(ctx, receipt.PurchaseOrderID)
+		if err != nil {
+			return err
+		}
+		if status != PurchaseOrderSent && status != PurchaseOrderPartiallyReceived {
+			return invalidTransition(status, "receive")
+		}
+
+		receiptQuery := `
+			INSERT INTO goods_receipts (
+				id, tenant_id, purchase_order_id, delivery_note_number, received_at,
+				received_by, received_by_name, notes
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING created_at
+		`
+		if err := r.db.QueryRowxContext(ctx, receiptQuery,
+			receipt.ID, tenantID, receipt.PurchaseOrderID, receipt.DeliveryNoteNumber, receipt.ReceivedAt,
+			receipt.ReceivedBy, receipt.ReceivedByName, receipt.Notes,
+		).Scan(&receipt.CreatedAt); err != nil {
+			return err
+		}
+
+		for _, line := range receipt.Lines {
+			if err := r.receiveLine(ctx, tenantID, receipt, line); err != nil {
+				return err
+			}
+		}
+
+		var complete bool
+		completeQuery := `SELECT COALESCE(bool_and(quantity_received >= quantity_ordered), FALSE) FROM purchase_order_lines WHERE purchase_order_id = $1`
+		if err := r.db.GetContext(ctx, &complete, completeQuery, receipt.PurchaseOrderID); err != nil {
+			return err
+		}
+
+		if complete {
+			_, err = r.db.ExecContext(ctx, `UPDATE purchase_orders SET status = $2, received_at = $3 WHERE id = $1`,
+				receipt.PurchaseOrderID, PurchaseOrderReceived, receipt.ReceivedAt)
+		} else {
+			_, err = r.db.ExecContext(ctx, `UPDATE purchase_orders SET status = $2 WHERE id = $1`,
+				receipt.PurchaseOrderID, PurchaseOrderPartiallyReceived)
+		}
+		return err
+	})
+}
+
+// receiveLine books one receipt line using the transaction in ctx
+func (r *PurchaseOrderRepository) receiveLine(ctx context.Context, tenantID string, receipt *GoodsReceipt, line *GoodsReceiptLine) error {
+	var ordered struct {
+		ItemID           string `db:"item_id"`
+		QuantityOrdered  int    `db:"quantity_ordered"`
+		QuantityReceived int    `db:"quantity_received"`
+	}
+	lockQuery := `
+		SELECT item_id, quantity_ordered, quantity_received FROM purchase_order_lines
+		WHERE id = $1 AND purchase_order_id = $2 FOR UPDATE
+	`
+	if err := r.db.GetContext(ctx, &ordered, lockQuery, line.PurchaseOrderLineID, receipt.PurchaseOrderID); err != nil {
+		if err == sql.ErrNoRows {
+			return errors.NotFound("purchase_order_line")
+		}
+		return err
+	}
+
+	if open := ordered.QuantityOrdered - ordered.QuantityReceived; line.Quantity > open {
+		return errors.BadRequest("errors.purchase_order.over_delivery", map[string]string{
+			"quantity": strconv.Itoa(line.Quantity),
+			"open":     strconv.Itoa(open),
+		})
+	}
+
+	batch := line.Batch
+	if batch == nil {
+		batch = &InventoryBatch{}
+		line.Batch = batch
+	}
+	batch.ItemID = ordered.ItemID
+	batch.InitialQuantity = line.Quantity
+	batch.CurrentQuantity = line.Quantity
+	batch.ReceivedDate = receipt.ReceivedAt
+	if err := insertBatch(ctx, r.db, tenantID, batch); err != nil {
+		return err
+	}
+
+	referenceType := "goods_receipt"
+	adj := &StockAdjustment{
+		ItemID:          ordered.ItemID,
+		BatchID:         &batch.ID,
+		AdjustmentType:  "receipt",
+		Quantity:        line.Quantity,
+		NewQuantity:     line.Quantity,
+		Reason:          receipt.DeliveryNoteNumber,
+		ReferenceType:   &referenceType,
+		ReferenceID:     &receipt.ID,
+		PerformedByName: receipt.ReceivedByName,
+	}
+	if receipt.ReceivedBy != nil {
+		adj.PerformedBy = *receipt.ReceivedBy
+	}
+	if err := insertStockAdjustment(ctx, r.db, tenantID, adj); err != nil {
+		return err
+	}
+	line.Adjustment = adj
+
+	updateQuery := `UPDATE purchase_order_lines SET quantity_received = quantity_received + $2 WHERE id = $1`
+	if _, err := r.db.ExecContext(ctx, updateQuery, line.PurchaseOrderLineID, line.Quantity); err != nil {
+		return err
+	}
+
+	if line.ID == "" {
+		line.ID = uuid.New().String()
+	}
+	line.GoodsReceiptID = receipt.ID
+	line.ItemID = ordered.ItemID
+	line.BatchID = batch.ID
+
+	lineQuery := `
+		INSERT INTO goods_receipt_lines (
+			id, tenant_id, goods_receipt_id, purchase_order_line_id, item_id, batch_id, quantity
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at
+	`
+	return r.db.QueryRowxContext(ctx, lineQuery,
+		line.ID, tenantID, receipt.ID, line.PurchaseOrderLineID, line.ItemID, line.BatchID, line.Quantity,
+	).Scan(&line.CreatedAt)
+}
+
+// ListReceipts lists the goods receipts of an order with their lines
+// TENANT-ISOLATED: Returns only receipts via RLS
+func (r *PurchaseOrderRepository) ListReceipts(ctx context.Context, purchaseOrderID string) ([]*GoodsReceipt, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var receipts []*GoodsReceipt
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			SELECT id, purchase_order_id, delivery_note_number, received_at, received_by,
+			       received_by_name, notes, created_at
+			FROM goods_receipts WHERE purchase_order_id = $1
+			ORDER BY received_at
+		`
+		if err := r.db.SelectContext(ctx, &receipts, query, purchaseOrderID); err != nil {
+			return err
+		}
+
+		var lines []*GoodsReceiptLine
+		linesQuery := `
+			SELECT l.id, l.goods_receipt_id, l.purchase_order_line_id, l.item_id, l.batch_id, l.quantity, l.created_at
+			FROM goods_receipt_lines l
+			JOIN goods_receipts g ON g.id = l.goods_receipt_id
+			WHERE g.purchase_order_id = $1
+			ORDER BY l.created_at
+		`
+		if err := r.db.SelectContext(ctx, &lines, linesQuery, purchaseOrderID); err != nil {
+			return err
+		}
+
+		byID := make(map[string]*GoodsReceipt, len(receipts))
+		for _, receipt := range receipts {
+			byID[receipt.ID] = receipt
+		}
+		for _, line := range lines {
+			if receipt := byID[line.GoodsReceiptID]; receipt != nil {
+				receipt.Lines = append(receipt.Lines, line)
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return receipts, nil
+}
+
+// GetReceipt gets a goods receipt of an order with its lines
+// TENANT-ISOLATED: Queries via RLS
+func (r *PurchaseOrderRepository) GetReceipt(ctx context.Context, purchaseOrderID, receiptID string) (*GoodsReceipt, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var receipt GoodsReceipt
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			SELECT id, purchase_order_id, delivery_note_number, received_at, received_by,
+			       received_by_name, notes, created_at
+			FROM goods_receipts WHERE id = $1 AND purchase_order_id = $2
+		`
+		if err := r.db.GetContext(ctx, &receipt, query, receiptID, purchaseOrderID); err != nil {
+			return err
+		}
+
+		linesQuery := `
+			SELECT id, goods_receipt_id, purchase_order_line_id, item_id, batch_id, quantity, created_at
+			FROM goods_receipt_lines WHERE goods_receipt_id = $1
+			ORDER BY created_at
+		`
+		return r.db.SelectContext(ctx, &receipt.Lines, linesQuery, receiptID)
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFound("goods_receipt")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &receipt, nil
+}
@@ -0,0 +1,177 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Purchase Order Repository Tests ---
+
+// createSentOrder creates a supplier and a sent order for one line of item
+func createSentOrder(t *testing.T, tenantCtx context.Context, itemID string, quantity int) *repository.PurchaseOrder {
+	t.Helper()
+	supplierRepo := repository.NewSupplierRepository(suite.DB)
+	orderRepo := repository.NewPurchaseOrderRepository(suite.DB)
+
+	supplier := &repository.Supplier{Name: "Praxisbedarf GmbH", LeadTimeDays: 3, IsActive: true}
+	require.NoError(t, supplierRepo.Create(tenantCtx, supplier))
+
+	po := &repository.PurchaseOrder{
+		SupplierID: supplier.ID,
+		Lines: []*repository.PurchaseOrderLine{
+			{ItemID: itemID, QuantityOrdered: quantity},
+		},
+	}
+	require.NoError(t, orderRepo.Create(tenantCtx, po))
+	require.NoError(t, orderRepo.MarkSent(tenantCtx, po.ID, nil))
+
+	po, err := orderRepo.GetByID(tenantCtx, po.ID)
+	require.NoError(t, err)
+	return po
+}
+
+func receiptFor(po *repository.PurchaseOrder, batchNumber string, quantity int) *repository.GoodsReceipt {
+	expiry := time.Now().AddDate(1, 0, 0).UTC().Truncate(24 * time.Hour)
+	return &repository.GoodsReceipt{
+		PurchaseOrderID: po.ID,
+		Lines: []*repository.GoodsReceiptLine{
+			{
+				PurchaseOrderLineID: po.Lines[0].ID,
+				Quantity:            quantity,
+				Batch:               &repository.InventoryBatch{BatchNumber: batchNumber, ExpiryDate: &expiry},
+			},
+		},
+	}
+}
+
+func TestPurchaseOrderRepository_CreateAndSend(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tenant := suite.SetupInventoryTenant(t, ctx, "po-create")
+	tenantCtx := suite.TenantContext(tenant)
+
+	itemRepo := repository.NewItemRepository(suite.DB)
+	item := createTestItem(t, tenantCtx, itemRepo, "Nitrile Gloves")
+
+	po := createSentOrder(t, tenantCtx, item.ID, 10)
+	assert.Regexp(t, `^PO-\d{4}-0001$`, po.OrderNumber)
+	assert.Equal(t, repository.PurchaseOrderSent, po.Status)
+	assert.NotNil(t, po.SentAt)
+	assert.Equal(t, "Praxisbedarf GmbH", po.SupplierName)
+	require.Len(t, po.Lines, 1)
+	assert.Equal(t, 10, po.Lines[0].QuantityOpen())
+
+	orderRepo := repository.NewPurchaseOrderRepository(suite.DB)
+	open, err := orderRepo.OpenQuantities(tenantCtx)
+	require.NoError(t, err)
+	assert.Equal(t, 10, open[item.ID])
+
+	// A sent order can no longer be edited
+	err = orderRepo.Update(tenantCtx, po)
+	assert.Error(t, err)
+}
+
+func TestPurchaseOrderRepository_Receive(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tenant := suite.SetupInventoryTenant(t, ctx, "po-receive")
+	tenantCtx := suite.TenantContext(tenant)
+
+	itemRepo := repository.NewItemRepository(suite.DB)
+	batchRepo := repository.NewBatchRepository(suite.DB)
+	orderRepo := repository.NewPurchaseOrderRepository(suite.DB)
+	item := createTestItem(t, tenantCtx, itemRepo, "Syringes 5ml")
+
+	po := createSentOrder(t, tenantCtx, item.ID, 10)
+
+	// Partial delivery
+	first := receiptFor(po, "LOT-A", 4)
+	require.NoError(t, orderRepo.Receive(tenantCtx, first))
+	assert.NotEmpty(t, first.Lines[0].BatchID)
+	require.NotNil(t, first.Lines[0].Adjustment)
+	assert.Equal(t, "receipt", first.Lines[0].Adjustment.AdjustmentType)
+
+	po, err := orderRepo.GetByID(tenantCtx, po.ID)
+	require.NoError(t, err)
+	assert.Equal(t, repository.PurchaseOrderPartiallyReceived, po.Status)
+	assert.Equal(t, 6, po.Lines[0].QuantityOpen())
+
+	// Over-delivery is rejected and books nothing
+	err = orderRepo.Receive(tenantCtx, receiptFor(po, "LOT-X", 7))
+	assert.Error(t, err)
+
+	// Remaining delivery completes the order
+	require.NoError(t, orderRepo.Receive(tenantCtx, receiptFor(po, "LOT-B", 6)))
+
+	po, err = orderRepo.GetByID(tenantCtx, po.ID)
+	require.NoError(t, err)
+	assert.Equal(t, repository.PurchaseOrderReceived, po.Status)
+	assert.NotNil(t, po.ReceivedAt)
+
+	stock, err := batchRepo.GetTotalStock(tenantCtx, item.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 10, stock)
+
+	batches, err := batchRepo.ListByItem(tenantCtx, item.ID)
+	require.NoError(t, err)
+	assert.Len(t, batches, 2)
+
+	receipts, err := orderRepo.ListReceipts(tenantCtx, po.ID)
+	require.NoError(t, err)
+	require.Len(t, receipts, 2)
+	assert.Len(t, receipts[0].Lines, 1)
+
+	// A received order cannot be cancelled
+	err = orderRepo.Cancel(tenantCtx, po.ID, nil)
+	assert.Error(t, err)
+}
+
+func TestSupplierRepository_GetSourcing(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tenant := suite.SetupInventoryTenant(t, ctx, "po-sourcing")
+	tenantCtx := suite.TenantContext(tenant)
+
+	itemRepo := repository.NewItemRepository(suite.DB)
+	supplierRepo := repository.NewSupplierRepository(suite.DB)
+	item := createTestItem(t, tenantCtx, itemRepo, "Gauze Swabs")
+
+	cheap := &repository.Supplier{Name: "Cheap Supplies", IsActive: true}
+	preferred := &repository.Supplier{Name: "Preferred Supplies", IsActive: true}
+	require.NoError(t, supplierRepo.Create(tenantCtx, cheap))
+	require.NoError(t, supplierRepo.Create(tenantCtx, preferred))
+
+	cheapPrice, preferredPrice := 100, 150
+	require.NoError(t, supplierRepo.UpsertItem(tenantCtx, &repository.SupplierItem{
+		SupplierID: cheap.ID, ItemID: item.ID, UnitPriceCents: &cheapPrice, PackSize: 10,
+	}))
+
+	sourcing, err := supplierRepo.GetSourcing(tenantCtx, item.ID)
+	require.NoError(t, err)
+	require.NotNil(t, sourcing)
+	assert.Equal(t, cheap.ID, sourcing.SupplierID)
+
+	require.NoError(t, supplierRepo.UpsertItem(tenantCtx, &repository.SupplierItem{
+		SupplierID: preferred.ID, ItemID: item.ID, UnitPriceCents: &preferredPrice, PackSize: 10, IsPreferred: true,
+	}))
+
+	sourcing, err = supplierRepo.GetSourcing(tenantCtx, item.ID)
+	require.NoError(t, err)
+	require.NotNil(t, sourcing)
+	assert.Equal(t, preferred.ID, sourcing.SupplierID)
+
+	// Supplier names are unique per tenant, case-insensitive
+	err = supplierRepo.Create(tenantCtx, &repository.Supplier{Name: "cheap supplies", IsActive: true})
+	assert.Error(t, err)
+}
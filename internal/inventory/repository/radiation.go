@@ -634,37 +634,43 @@ func (r *RadiationRepository) ListDosimetryByEmployee(ctx context.Context, emplo
 	return records, nil
 }
 
-// ListAllDosimetry lists all dosimetry records with pagination
+// dosimetryListSchema whitelists the filter and sort fields of GET /radiation/dosimetry
+var dosimetryListSchema = database.ListSchema{
+	Fields: map[string]database.Field{
+		"employee_id":              {Column: "employee_id", Type: database.FieldUUID, Filter: true},
+		"employee_name":            {Column: "employee_name", Type: database.FieldText, Filter: true, Sort: true},
+		"measurement_period_start": {Column: "measurement_period_start", Type: database.FieldDate, Filter: true, Sort: true},
+		"measurement_period_end":   {Column: "measurement_period_end", Type: database.FieldDate, Filter: true, Sort: true},
+		"dosimeter_type":           {Column: "dosimeter_type", Type: database.FieldText, Filter: true, Sort: true},
+		"dose_msv":                 {Column: "dose_msv", Type: database.FieldNumeric, Filter: true, Sort: true},
+		"body_region":              {Column: "body_region", Type: database.FieldText, Filter: true, Sort: true},
+	},
+	DefaultSort: []string{"-measurement_period_end"},
+}
+
+// ListAllDosimetry lists all dosimetry records filtered, sorted and paginated by q
 // TENANT-ISOLATED: Returns only records via RLS
-func (r *RadiationRepository) ListAllDosimetry(ctx context.Context, page, perPage int) ([]*DosimetryRecord, int64, error) {
+func (r *RadiationRepository) ListAllDosimetry(ctx context.Context, q *database.ListQuery) (*database.Page[*DosimetryRecord], error) {
 	tenantID, err := tenant.TenantID(ctx)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	var total int64
-	var records []*DosimetryRecord
-
+	var page *database.Page[*DosimetryRecord]
 	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
-		countQuery := `SELECT COUNT(*) FROM dosimetry_records WHERE deleted_at IS NULL`
-		if err := r.db.GetContext(ctx, &total, countQuery); err != nil {
-			return err
-		}
-
 		query := `
 			SELECT id, employee_id, employee_name, measurement_period_start, measurement_period_end,
 			       dosimeter_type, dose_msv, body_region, notes, created_at, updated_at
 			FROM dosimetry_records WHERE deleted_at IS NULL
-			ORDER BY measurement_period_end DESC
-			LIMIT $1 OFFSET $2
 		`
-		offset := (page - 1) * perPage
-		return r.db.SelectContext(ctx, &records, query, perPage, offset)
+		var err error
+		page, err = database.SelectPage[*DosimetryRecord](ctx, r.db, &dosimetryListSchema, q, query)
+		return err
 	})
 
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	return records, total, nil
+	return page, nil
 }
@@ -0,0 +1,471 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/tenant"
+)
+
+// Reservation statuses
+const (
+	ReservationActive   = "active"
+	ReservationConsumed = "consumed"
+	ReservationReleased = "released"
+)
+
+// stockReservationReference is the reference_type of bookings that consume a reservation
+const stockReservationReference = "stock_reservation"
+
+// StockReservation holds stock of an item for a procedure or date
+type StockReservation struct {
+	ID               string     `db:"id" json:"id"`
+	ItemID           string     `db:"item_id" json:"item_id"`
+	Quantity         int        `db:"quantity" json:"quantity"`
+	ConsumedQuantity int        `db:"consumed_quantity" json:"consumed_quantity"`
+	Status           string     `db:"status" json:"status"` // active, consumed, released
+	ProcedureName    *string    `db:"procedure_name" json:"procedure_name,omitempty"`
+	NeededAt         *time.Time `db:"needed_at" json:"needed_at,omitempty"`
+	ReferenceType    *string    `db:"reference_type" json:"reference_type,omitempty"` // e.g. appointment
+	ReferenceID      *string    `db:"reference_id" json:"reference_id,omitempty"`
+	Notes            *string    `db:"notes" json:"notes,omitempty"`
+	ReservedBy       *string    `db:"reserved_by" json:"reserved_by,omitempty"`
+	ReservedByName   *string    `db:"reserved_by_name" json:"reserved_by_name,omitempty"`
+	ConsumedAt       *time.Time `db:"consumed_at" json:"consumed_at,omitempty"`
+	ReleasedAt       *time.Time `db:"released_at" json:"released_at,omitempty"`
+	CreatedAt        time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time  `db:"updated_at" json:"updated_at"`
+
+	// Joined
+	ItemName string `db:"item_name" json:"item_name"`
+	Unit     string `db:"unit" json:"unit"`
+
+	Batches []*ReservationBatch `db:"-" json:"batches,omitempty"`
+}
+
+// ReservationBatch is the part of a reservation allocated to one batch
+type ReservationBatch struct {
+	ID            string    `db:"id" json:"id"`
+	ReservationID string    `db:"reservation_id" json:"reservation_id"`
+	BatchID       string    `db:"batch_id" json:"batch_id"`
+	Position      int       `db:"position" json:"position"`
+	Quantity      int       `db:"quantity" json:"quantity"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+
+	// Joined from the batch
+	BatchNumber string     `db:"batch_number" json:"batch_number"`
+	LocationID  *string    `db:"location_id" json:"location_id,omitempty"`
+	ExpiryDate  *time.Time `db:"expiry_date" json:"expiry_date,omitempty"`
+}
+
+// PickCandidate is a batch with stock that may be picked, with the facts
+// needed to skip it
+type PickCandidate struct {
+	InventoryBatch
+	Recalled bool `db:"recalled" json:"recalled"` // has an unresolved recall match
+}
+
+// Available is the stock of the batch that is not reserved
+func (c *PickCandidate) Available() int {
+	return c.CurrentQuantity - c.ReservedQuantity
+}
+
+// ReservationRepository handles stock reservation persistence
+type ReservationRepository struct {
+	db *database.DB
+}
+
+// NewReservationRepository creates a new reservation repository
+func NewReservationRepository(db *database.DB) *ReservationRepository {
+	return &ReservationRepository{db: db}
+}
+
+const reservationSelect = `
+	SELECT r.id, r.item_id, r.quantity, r.consumed_quantity, r.status, r.procedure_name,
+	       r.needed_at, r.reference_type, r.reference_id, r.notes, r.reserved_by,
+	       r.reserved_by_name, r.consumed_at, r.released_at, r.created_at, r.updated_at,
+	       i.name AS item_name, i.unit
+	FROM stock_reservations r
+	JOIN inventory_items i ON i.id = r.item_id
+`
+
+const reservationBatchSelect = `
+	SELECT rb.id, rb.reservation_id, rb.batch_id, rb.position, rb.quantity, rb.created_at,
+	       b.batch_number, b.location_id, b.expiry_date
+	FROM stock_reservation_batches rb
+	JOIN inventory_batches b ON b.id = rb.batch_id
+`
+
+const pickCandidateSelect = `
+	SELECT b.id, b.item_id, b.location_id, b.batch_number, b.lot_number, b.initial_quantity,
+	       b.current_quantity, b.reserved_quantity, b.manufactured_date, b.expiry_date,
+	       b.received_date, b.opened_at, b.status, b.created_at, b.updated_at,
+	       EXISTS (
+	           SELECT 1 FROM recall_matches m
+	           WHERE m.batch_id = b.id AND m.status <> 'resolved' AND m.deleted_at IS NULL
+	       ) AS recalled
+	FROM inventory_batches b
+	WHERE b.item_id = $1 AND b.current_quantity > 0 AND b.deleted_at IS NULL
+	ORDER BY b.expiry_date NULLS LAST, b.received_date, b.created_at
+`
+
+// selectPickCandidates reads the pick candidates of an item; lock is appended
+// to the query (e.g. " FOR UPDATE OF b"). Must be called inside WithTenantRLS.
+func selectPickCandidates(ctx context.Context, db *database.DB, itemID, lock string) ([]*PickCandidate, error) {
+	var candidates []*PickCandidate
+	if err := db.SelectContext(ctx, &candidates, pickCandidateSelect+lock, itemID); err != nil {
+		return nil, err
+	}
+	for _, c := range candidates {
+		c.Quantity = c.CurrentQuantity
+	}
+	return candidates, nil
+}
+
+// ListPickCandidates lists the batches of an item that have stock, whatever
+// their status, so a pick list can explain why a batch is skipped
+// TENANT-ISOLATED: Returns only batches via RLS
+func (r *ReservationRepository) ListPickCandidates(ctx context.Context, itemID string) ([]*PickCandidate, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []*PickCandidate
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		candidates, err = selectPickCandidates(ctx, r.db, itemID, "")
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return candidates, nil
+}
+
+// Create reserves stock by allocating it to the given batches in order.
+// The batches are locked and their availability is checked again, so a
+// reservation never takes stock that was reserved or booked out since the
+// pick list was built.
+// TENANT-ISOLATED: Inserts and updates via RLS
+func (r *ReservationRepository) Create(ctx context.Context, res *StockReservation, batchIDs []string) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	if res.ID == "" {
+		res.ID = uuid.New().String()
+	}
+	res.Status = ReservationActive
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			INSERT INTO stock_reservations (
+				id, tenant_id, item_id, quantity, status, procedure_name, needed_at,
+				reference_type, reference_id, notes, reserved_by, reserved_by_name
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+			RETURNING created_at, updated_at
+		`
+		if err := r.db.QueryRowxContext(ctx, query,
+			res.ID, tenantID, res.ItemID, res.Quantity, res.Status, res.ProcedureName, res.NeededAt,
+			res.ReferenceType, res.ReferenceID, res.Notes, res.ReservedBy, res.ReservedByName,
+		).Scan(&res.CreatedAt, &res.UpdatedAt); err != nil {
+			return err
+		}
+
+		res.Batches = nil
+		remaining := res.Quantity
+		for _, batchID := range batchIDs {
+			if remaining == 0 {
+				break
+			}
+
+			var available int
+			lockQuery := `
+				SELECT current_quantity - reserved_quantity FROM inventory_batches
+				WHERE id = $1 AND item_id = $2 AND status = 'available' AND deleted_at IS NULL
+				FOR UPDATE
+			`
+			if err := r.db.GetContext(ctx, &available, lockQuery, batchID, res.ItemID); err != nil {
+				if err == sql.ErrNoRows {
+					continue
+				}
+				return err
+			}
+			if available <= 0 {
+				continue
+			}
+
+			take := min(available, remaining)
+			if _, err := r.db.ExecContext(ctx, `UPDATE inventory_batches SET reserved_quantity = reserved_quantity + $2 WHERE id = $1`, batchID, take); err != nil {
+				return err
+			}
+
+			rb := &ReservationBatch{
+				ID:            uuid.New().String(),
+				ReservationID: res.ID,
+				BatchID:       batchID,
+				Position:      len(res.Batches) + 1,
+				Quantity:      take,
+			}
+			insertQuery := `
+				INSERT INTO stock_reservation_batches (id, tenant_id, reservation_id, batch_id, position, quantity)
+				VALUES ($1, $2, $3, $4, $5, $6)
+				RETURNING created_at
+			`
+			if err := r.db.QueryRowxContext(ctx, insertQuery,
+				rb.ID, tenantID, rb.ReservationID, rb.BatchID, rb.Position, rb.Quantity,
+			).Scan(&rb.CreatedAt); err != nil {
+				return err
+			}
+			res.Batches = append(res.Batches, rb)
+			remaining -= take
+		}
+
+		if remaining > 0 {
+			return errors.Conflict("errors.reservation.insufficient_stock", map[string]string{
+				"quantity":  strconv.Itoa(res.Quantity),
+				"available": strconv.Itoa(res.Quantity - remaining),
+			})
+		}
+		return nil
+	})
+}
+
+// GetByID gets a reservation with its batches
+// TENANT-ISOLATED: Queries via RLS
+func (r *ReservationRepository) GetByID(ctx context.Context, id string) (*StockReservation, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res StockReservation
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		if err := r.db.GetContext(ctx, &res, reservationSelect+` WHERE r.id = $1`, id); err != nil {
+			return err
+		}
+
+		return r.db.SelectContext(ctx, &res.Batches, reservationBatchSelect+` WHERE rb.reservation_id = $1 ORDER BY rb.position`, id)
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFound("reservation")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// reservationListSchema whitelists the filter and sort fields of GET /reservations
+var reservationListSchema = database.ListSchema{
+	Fields: map[string]database.Field{
+		"item_id":        {Column: "item_id", Type: database.FieldUUID, Filter: true},
+		"item_name":      {Column: "item_name", Type: database.FieldText, Filter: true, Sort: true},
+		"status":         {Column: "status", Type: database.FieldText, Filter: true, Sort: true},
+		"procedure_name": {Column: "procedure_name", Type: database.FieldText, Filter: true, Sort: true, Nullable: true},
+		"needed_at":      {Column: "needed_at", Type: database.FieldTime, Filter: true, Sort: true, Nullable: true},
+		"reference_id":   {Column: "reference_id", Type: database.FieldUUID, Filter: true, Nullable: true},
+		"created_at":     {Column: "created_at", Type: database.FieldTime, Filter: true, Sort: true},
+	},
+	DefaultSort: []string{"needed_at", "created_at"},
+}
+
+// List lists reservations (without batches) filtered, sorted and paginated by q
+// TENANT-ISOLATED: Returns only reservations via RLS
+func (r *ReservationRepository) List(ctx context.Context, q *database.ListQuery) (*database.Page[*StockReservation], error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var page *database.Page[*StockReservation]
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		// Wrapped in a subquery so item_name can be filtered and sorted on
+		query := `SELECT * FROM (` + reservationSelect + `) AS reservations WHERE 1=1`
+		var err error
+		page, err = database.SelectPage[*StockReservation](ctx, r.db, &reservationListSchema, q, query)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// lockReservation locks an active reservation and loads its batches in pick order
+func (r *ReservationRepository) lockReservation(ctx context.Context, id, action string) (*StockReservation, error) {
+	var res StockReservation
+	query := reservationSelect + ` WHERE r.id = $1 FOR UPDATE OF r`
+	if err := r.db.GetContext(ctx, &res, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NotFound("reservation")
+		}
+		return nil, err
+	}
+	if res.Status != ReservationActive {
+		return nil, errors.Conflict("errors.reservation.invalid_status", map[string]string{"status": res.Status, "action": action})
+	}
+
+	if err := r.db.SelectContext(ctx, &res.Batches, reservationBatchSelect+` WHERE rb.reservation_id = $1 ORDER BY rb.position`, id); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// unreserve gives the reserved stock of a batch back
+func (r *ReservationRepository) unreserve(ctx context.Context, batchID string, quantity int) error {
+	query := `UPDATE inventory_batches SET reserved_quantity = GREATEST(reserved_quantity - $2, 0) WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, batchID, quantity)
+	return err
+}
+
+// Release gives the stock of an active reservation back
+// TENANT-ISOLATED: Updates via RLS
+func (r *ReservationRepository) Release(ctx context.Context, id string) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		res, err := r.lockReservation(ctx, id, "release")
+		if err != nil {
+			return err
+		}
+
+		for _, rb := range res.Batches {
+			if err := r.unreserve(ctx, rb.BatchID, rb.Quantity); err != nil {
+				return err
+			}
+		}
+
+		query := `UPDATE stock_reservations SET status = $2, released_at = NOW() WHERE id = $1`
+		_, err = r.db.ExecContext(ctx, query, id, ReservationReleased)
+		return err
+	})
+}
+
+// Consume books out the reserved stock in pick order. A quantity below the
+// reserved quantity consumes that much; the rest is released.
+// TENANT-ISOLATED: Updates and inserts via RLS
+func (r *ReservationRepository) Consume(ctx context.Context, id string, quantity *int, performedBy string, performedByName *string) ([]*StockAdjustment, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var adjustments []*StockAdjustment
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		res, err := r.lockReservation(ctx, id, "consume")
+		if err != nil {
+			return err
+		}
+
+		consume := res.Quantity
+		if quantity != nil {
+			if *quantity > res.Quantity {
+				return errors.BadRequest("errors.reservation.over_consumption", map[string]string{
+					"quantity": strconv.Itoa(*quantity),
+					"reserved": strconv.Itoa(res.Quantity),
+				})
+			}
+			consume = *quantity
+		}
+
+		reason := "Reservierung"
+		if res.ProcedureName != nil {
+			reason = fmt.Sprintf("Reservierung: %s", *res.ProcedureName)
+		}
+		referenceType := stockReservationReference
+
+		remaining := consume
+		for _, rb := range res.Batches {
+			if err := r.unreserve(ctx, rb.BatchID, rb.Quantity); err != nil {
+				return err
+			}
+
+			take := min(rb.Quantity, remaining)
+			if take == 0 {
+				continue
+			}
+
+			var current int
+			lockQuery := `SELECT current_quantity FROM inventory_batches WHERE id = $1 FOR UPDATE`
+			if err := r.db.GetContext(ctx, &current, lockQuery, rb.BatchID); err != nil {
+				return err
+			}
+			if current < take {
+				return errors.BadRequest("errors.inventory.insufficient_stock", map[string]string{
+					"quantity": strconv.Itoa(take),
+					"current":  strconv.Itoa(current),
+				})
+			}
+			if _, err := r.db.ExecContext(ctx, `UPDATE inventory_batches SET current_quantity = $2 WHERE id = $1`, rb.BatchID, current-take); err != nil {
+				return err
+			}
+
+			batchID := rb.BatchID
+			adj := &StockAdjustment{
+				ItemID:           res.ItemID,
+				BatchID:          &batchID,
+				AdjustmentType:   "deduct",
+				Quantity:         take,
+				PreviousQuantity: current,
+				NewQuantity:      current - take,
+				Reason:           &reason,
+				ReferenceType:    &referenceType,
+				ReferenceID:      &res.ID,
+				PerformedBy:      performedBy,
+				PerformedByName:  performedByName,
+			}
+			if err := insertStockAdjustment(ctx, r.db, tenantID, adj); err != nil {
+				return err
+			}
+			adjustments = append(adjustments, adj)
+			remaining -= take
+		}
+
+		query := `UPDATE stock_reservations SET status = $2, consumed_quantity = $3, consumed_at = NOW() WHERE id = $1`
+		_, err = r.db.ExecContext(ctx, query, id, ReservationConsumed, consume)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return adjustments, nil
+}
+
+// ListOverdue lists the IDs of active reservations needed before the given time
+// TENANT-ISOLATED: Queries via RLS
+func (r *ReservationRepository) ListOverdue(ctx context.Context, before time.Time) ([]string, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `SELECT id FROM stock_reservations WHERE status = $1 AND needed_at < $2 ORDER BY needed_at`
+		return r.db.SelectContext(ctx, &ids, query, ReservationActive, before)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,86 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Stock Reservation Tests ---
+
+func TestReservationRepository_ReserveConsumeRelease(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tenant := suite.SetupInventoryTenant(t, ctx, "reservation-lifecycle")
+	tenantCtx := suite.TenantContext(tenant)
+
+	itemRepo := repository.NewItemRepository(suite.DB)
+	batchRepo := repository.NewBatchRepository(suite.DB)
+	reservationRepo := repository.NewReservationRepository(suite.DB)
+
+	item := createTestItem(t, tenantCtx, itemRepo, "Nahtmaterial 4-0")
+	_, ambient, fridge := createTestLocations(t, tenantCtx)
+	first := createTestBatch(t, tenantCtx, item.ID, ambient.ID, 3)
+	second := createTestBatch(t, tenantCtx, item.ID, fridge.ID, 10)
+
+	// Allocated across batches in the given order
+	procedure := "Exzision"
+	res := &repository.StockReservation{ItemID: item.ID, Quantity: 5, ProcedureName: &procedure}
+	require.NoError(t, reservationRepo.Create(tenantCtx, res, []string{first.ID, second.ID}))
+	require.Len(t, res.Batches, 2)
+	assert.Equal(t, 3, res.Batches[0].Quantity)
+	assert.Equal(t, 2, res.Batches[1].Quantity)
+
+	got, err := batchRepo.GetByID(tenantCtx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, got.ReservedQuantity)
+
+	// Reserved stock cannot be booked out or reserved again
+	err = batchRepo.AdjustStock(tenantCtx, &repository.StockAdjustment{
+		ItemID: item.ID, BatchID: &first.ID, AdjustmentType: "deduct", Quantity: 1, PerformedBy: "user-1",
+	})
+	var appErr *errors.AppError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, "errors.inventory.reserved_stock", appErr.MessageKey)
+
+	err = reservationRepo.Create(tenantCtx, &repository.StockReservation{ItemID: item.ID, Quantity: 9}, []string{first.ID, second.ID})
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, "errors.reservation.insufficient_stock", appErr.MessageKey)
+
+	// Consuming less than reserved books out in pick order and releases the rest
+	quantity := 4
+	adjustments, err := reservationRepo.Consume(tenantCtx, res.ID, &quantity, "user-1", nil)
+	require.NoError(t, err)
+	require.Len(t, adjustments, 2)
+	assert.Equal(t, 3, adjustments[0].Quantity)
+	assert.Equal(t, 1, adjustments[1].Quantity)
+	assert.Equal(t, res.ID, *adjustments[0].ReferenceID)
+
+	got, err = batchRepo.GetByID(tenantCtx, second.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 9, got.CurrentQuantity)
+	assert.Equal(t, 0, got.ReservedQuantity)
+
+	consumed, err := reservationRepo.GetByID(tenantCtx, res.ID)
+	require.NoError(t, err)
+	assert.Equal(t, repository.ReservationConsumed, consumed.Status)
+	assert.Equal(t, 4, consumed.ConsumedQuantity)
+
+	_, err = reservationRepo.Consume(tenantCtx, res.ID, nil, "user-1", nil)
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, "errors.reservation.invalid_status", appErr.MessageKey)
+
+	// Releasing gives the stock back
+	other := &repository.StockReservation{ItemID: item.ID, Quantity: 6}
+	require.NoError(t, reservationRepo.Create(tenantCtx, other, []string{second.ID}))
+	require.NoError(t, reservationRepo.Release(tenantCtx, other.ID))
+	got, err = batchRepo.GetByID(tenantCtx, second.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, got.ReservedQuantity)
+}
@@ -3,12 +3,12 @@ package repository
 import (
 	"context"
 	"database/sql"
-	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/medflow/medflow-backend/pkg/database"
 	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/retention"
 	"github.com/medflow/medflow-backend/pkg/tenant"
 )
 
@@ -19,6 +19,7 @@ type RetentionPolicy struct {
 	RetentionYears int        `db:"retention_years" json:"retention_years"`
 	LegalBasis     *string    `db:"legal_basis" json:"legal_basis,omitempty"`
 	Description    *string    `db:"description" json:"description,omitempty"`
+	PurgeAction    string     `db:"purge_action" json:"purge_action"`
 	CreatedAt      time.Time  `db:"created_at" json:"created_at"`
 	UpdatedAt      time.Time  `db:"updated_at" json:"updated_at"`
 	DeletedAt      *time.Time `db:"deleted_at" json:"-"`
@@ -49,13 +50,13 @@ func (r *RetentionRepository) Create(ctx context.Context, policy *RetentionPolic
 	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
 		query := `
 			INSERT INTO retention_policies (
-				id, tenant_id, entity_type, retention_years, legal_basis, description
-			) VALUES ($1, $2, $3, $4, $5, $6)
+				id, tenant_id, entity_type, retention_years, legal_basis, description, purge_action
+			) VALUES ($1, $2, $3, $4, $5, $6, $7)
 			RETURNING created_at, updated_at
 		`
 		return r.db.QueryRowxContext(ctx, query,
 			policy.ID, tenantID, policy.EntityType, policy.RetentionYears,
-			policy.LegalBasis, policy.Description,
+			policy.LegalBasis, policy.Description, policy.PurgeAction,
 		).Scan(&policy.CreatedAt, &policy.UpdatedAt)
 	})
 }
@@ -72,7 +73,7 @@ func (r *RetentionRepository) GetByEntityType(ctx context.Context, entityType st
 	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
 		query := `
 			SELECT id, entity_type, retention_years, legal_basis, description,
-			       created_at, updated_at
+			       purge_action, created_at, updated_at
 			FROM retention_policies WHERE entity_type = $1 AND deleted_at IS NULL
 		`
 		return r.db.GetContext(ctx, &policy, query, entityType)
@@ -100,7 +101,7 @@ func (r *RetentionRepository) List(ctx context.Context) ([]*RetentionPolicy, err
 	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
 		query := `
 			SELECT id, entity_type, retention_years, legal_basis, description,
-			       created_at, updated_at
+			       purge_action, created_at, updated_at
 			FROM retention_policies WHERE deleted_at IS NULL
 			ORDER BY entity_type ASC
 		`
@@ -126,12 +127,12 @@ func (r *RetentionRepository) Update(ctx context.Context, policy *RetentionPolic
 		query := `
 			UPDATE retention_policies SET
 				entity_type = $2, retention_years = $3, legal_basis = $4,
-				description = $5, updated_at = NOW()
+				description = $5, purge_action = $6, updated_at = NOW()
 			WHERE id = $1 AND deleted_at IS NULL
 		`
 		result, err := r.db.ExecContext(ctx, query,
 			policy.ID, policy.EntityType, policy.RetentionYears,
-			policy.LegalBasis, policy.Description,
+			policy.LegalBasis, policy.Description, policy.PurgeAction,
 		)
 		if err != nil {
 			return err
@@ -184,17 +185,9 @@ func (r *RetentionRepository) ValidateDeletion(ctx context.Context, entityType s
 		return err
 	}
 
-	retentionEnd := recordDate.AddDate(policy.RetentionYears, 0, 0)
-	if time.Now().Before(retentionEnd) {
-		legalBasis := ""
-		if policy.LegalBasis != nil {
-			legalBasis = *policy.LegalBasis
-		}
-		return fmt.Errorf(
-			"record cannot be deleted: retention period of %d years for %q has not expired (until %s, legal basis: %s)",
-			policy.RetentionYears, entityType, retentionEnd.Format("2006-01-02"), legalBasis,
-		)
+	period := retention.Period{EntityType: entityType, Years: policy.RetentionYears}
+	if policy.LegalBasis != nil {
+		period.LegalBasis = *policy.LegalBasis
 	}
-
-	return nil
+	return period.Check(recordDate, time.Now())
 }
@@ -351,3 +351,75 @@ func TestGetByBarcode_TenantIsolation(t *testing.T) {
 	assert.Nil(t, found2)
 	assert.Error(t, err, "tenant2 should NOT see tenant1's barcode item")
 }
+
+// --- GS1 / UDI lookup Tests ---
+
+func TestGetByProductCode_MatchesBarcodeOrUDIDI(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tenant := suite.SetupInventoryTenant(t, ctx, "scan-product-code")
+	tenantCtx := suite.TenantContext(tenant)
+
+	itemRepo := repository.NewItemRepository(suite.DB)
+
+	// EAN-13 in the barcode column, found by the GTIN-14 decoded from a DataMatrix
+	ean := "4006381333931"
+	drug := &repository.InventoryItem{
+		Name: "EAN Item", Category: "Supplies", Unit: "pieces", IsActive: true, Barcode: &ean,
+	}
+	require.NoError(t, itemRepo.Create(tenantCtx, drug))
+
+	udiDI := "09506000134352"
+	device := &repository.InventoryItem{
+		Name: "UDI Item", Category: "Devices", Unit: "pieces", IsActive: true, UdiDI: &udiDI,
+	}
+	require.NoError(t, itemRepo.Create(tenantCtx, device))
+
+	found, err := itemRepo.GetByProductCode(tenantCtx, []string{"04006381333931", "4006381333931"})
+	require.NoError(t, err)
+	assert.Equal(t, drug.ID, found.ID)
+
+	found, err = itemRepo.GetByProductCode(tenantCtx, []string{"09506000134352", "9506000134352"})
+	require.NoError(t, err)
+	assert.Equal(t, device.ID, found.ID)
+
+	_, err = itemRepo.GetByProductCode(tenantCtx, []string{"00000000000000"})
+	assert.True(t, errors.Is(err, errors.ErrNotFound))
+
+	_, err = itemRepo.GetByProductCode(tenantCtx, nil)
+	assert.True(t, errors.Is(err, errors.ErrNotFound))
+}
+
+func TestGetByItemAndBatchNumber_PrefersAvailableStock(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tenant := suite.SetupInventoryTenant(t, ctx, "scan-item-batch")
+	tenantCtx := suite.TenantContext(tenant)
+
+	itemRepo := repository.NewItemRepository(suite.DB)
+	batchRepo := repository.NewBatchRepository(suite.DB)
+
+	item := createTestItem(t, tenantCtx, itemRepo, "Scan Item Batch")
+	other := createTestItem(t, tenantCtx, itemRepo, "Scan Other Item")
+
+	now := time.Now().UTC().Truncate(time.Second)
+	for _, b := range []*repository.InventoryBatch{
+		{ItemID: item.ID, BatchNumber: "LOT-7", InitialQuantity: 5, CurrentQuantity: 5, ReceivedDate: now},
+		{ItemID: item.ID, BatchNumber: "LOT-7", InitialQuantity: 20, CurrentQuantity: 20, ReceivedDate: now},
+		{ItemID: other.ID, BatchNumber: "LOT-7", InitialQuantity: 50, CurrentQuantity: 50, ReceivedDate: now},
+	} {
+		require.NoError(t, batchRepo.Create(tenantCtx, b))
+	}
+
+	found, err := batchRepo.GetByItemAndBatchNumber(tenantCtx, item.ID, "LOT-7")
+	require.NoError(t, err)
+	assert.Equal(t, item.ID, found.ItemID)
+	assert.Equal(t, 20, found.CurrentQuantity)
+
+	_, err = batchRepo.GetByItemAndBatchNumber(tenantCtx, item.ID, "LOT-8")
+	assert.True(t, errors.Is(err, errors.ErrNotFound))
+}
@@ -0,0 +1,81 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/pkg/search"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemRepository_Search(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tenant := suite.SetupInventoryTenant(t, ctx, "search-items")
+	tenantCtx := suite.TenantContext(tenant)
+
+	itemRepo := repository.NewItemRepository(suite.DB)
+
+	for _, item := range []*repository.InventoryItem{
+		{Name: "Flächendesinfektion", Manufacturer: strPtr("Schülke"), Category: "Hygiene", Unit: "Liter", IsActive: true},
+		{Name: "Spritze 5ml", Manufacturer: strPtr("B. Braun"), PZN: strPtr("01234567"), Category: "Supplies", Unit: "pieces", IsActive: true},
+		{Name: "Verbandmull", Category: "Supplies", Unit: "pieces", IsActive: true},
+	} {
+		require.NoError(t, itemRepo.Create(tenantCtx, item))
+	}
+
+	titles := func(results []*search.Result) []string {
+		var out []string
+		for _, r := range results {
+			out = append(out, r.Title)
+		}
+		return out
+	}
+
+	// Umlaut folding: "ae" spelling and uppercase find "Flächendesinfektion"
+	results, err := itemRepo.Search(tenantCtx, "FLAECHENDESINFEKTION", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Flächendesinfektion"}, titles(results))
+	assert.Equal(t, search.TypeItem, results[0].Type)
+
+	// Manufacturer with ß/umlaut and a typo
+	results, err = itemRepo.Search(tenantCtx, "Schuelkke", 10)
+	require.NoError(t, err)
+	assert.Contains(t, titles(results), "Flächendesinfektion")
+
+	// Exact PZN ranks first with score 1
+	results, err = itemRepo.Search(tenantCtx, "01234567", 10)
+	require.NoError(t, err)
+	require.NotEmpty(t, results)
+	assert.Equal(t, "Spritze 5ml", results[0].Title)
+	assert.Equal(t, 1.0, results[0].Score)
+
+	// Typo in the name
+	results, err = itemRepo.Search(tenantCtx, "Verbandmul", 10)
+	require.NoError(t, err)
+	assert.Contains(t, titles(results), "Verbandmull")
+}
+
+func TestItemRepository_Search_TenantIsolation(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tenant1 := suite.SetupInventoryTenant(t, ctx, "search-iso-1")
+	tenant2 := suite.SetupInventoryTenant(t, ctx, "search-iso-2")
+
+	itemRepo := repository.NewItemRepository(suite.DB)
+	createTestItem(t, suite.TenantContext(tenant1), itemRepo, "Handschuhe Nitril")
+
+	results, err := itemRepo.Search(suite.TenantContext(tenant1), "handschuhe", 10)
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+
+	results, err = itemRepo.Search(suite.TenantContext(tenant2), "handschuhe", 10)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/tenant"
+)
+
+// TemperatureSensor is a data logger that sends the readings of a cabinet.
+// It signs its requests with Secret and names itself with KeyID.
+type TemperatureSensor struct {
+	ID         string     `db:"id" json:"id"`
+	CabinetID  string     `db:"cabinet_id" json:"cabinet_id"`
+	Name       string     `db:"name" json:"name"`
+	KeyID      string     `db:"key_id" json:"key_id"`
+	Secret     string     `db:"secret" json:"secret,omitempty"` // only returned when created or rotated
+	LastSeenAt *time.Time `db:"last_seen_at" json:"last_seen_at,omitempty"`
+	RevokedAt  *time.Time `db:"revoked_at" json:"revoked_at,omitempty"`
+	CreatedBy  *string    `db:"created_by" json:"created_by,omitempty"`
+	CreatedAt  time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+// SensorRepository handles temperature sensor persistence
+type SensorRepository struct {
+	db *database.DB
+}
+
+// NewSensorRepository creates a new sensor repository
+func NewSensorRepository(db *database.DB) *SensorRepository {
+	return &SensorRepository{db: db}
+}
+
+// sensorSelect selects sensors without their secret
+const sensorSelect = `
+	SELECT id, cabinet_id, name, key_id, '' AS secret, last_seen_at, revoked_at, created_by, created_at, updated_at
+	FROM temperature_sensors
+`
+
+// Create creates a sensor and registers its key ID for tenant resolution
+// TENANT-ISOLATED: Inserts with tenant_id for RLS
+func (r *SensorRepository) Create(ctx context.Context, sensor *TemperatureSensor) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	if sensor.ID == "" {
+		sensor.ID = uuid.New().String()
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			INSERT INTO temperature_sensors (id, tenant_id, cabinet_id, name, key_id, secret, created_by)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING created_at, updated_at
+		`
+		err := r.db.QueryRowxContext(ctx, query,
+			sensor.ID, tenantID, sensor.CabinetID, sensor.Name, sensor.KeyID, sensor.Secret, sensor.CreatedBy,
+		).Scan(&sensor.CreatedAt, &sensor.UpdatedAt)
+		if err != nil {
+			if appErr := database.MapPQError(err); appErr != nil {
+				return appErr
+			}
+			return err
+		}
+
+		_, err = r.db.ExecContext(ctx, `
+			INSERT INTO public.temperature_sensor_lookup (key_id, tenant_id) VALUES ($1, $2)
+		`, sensor.KeyID, tenantID)
+		return err
+	})
+}
+
+// Get gets a sensor without its secret
+// TENANT-ISOLATED: Queries via RLS
+func (r *SensorRepository) Get(ctx context.Context, id string) (*TemperatureSensor, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var sensor TemperatureSensor
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		return r.db.GetContext(ctx, &sensor, sensorSelect+` WHERE id = $1`, id)
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFound("temperature_sensor")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &sensor, nil
+}
+
+// ListByCabinet lists the sensors of a cabinet, revoked ones last
+// TENANT-ISOLATED: Returns only sensors via RLS
+func (r *SensorRepository) ListByCabinet(ctx context.Context, cabinetID string) ([]*TemperatureSensor, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sensors := []*TemperatureSensor{}
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		return r.db.SelectContext(ctx, &sensors,
+			sensorSelect+` WHERE cabinet_id = $1 ORDER BY revoked_at IS NOT NULL, name`, cabinetID)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return sensors, nil
+}
+
+// LookupTenant returns the tenant of a sensor key ID. It runs without a
+// tenant context, before the request is authenticated.
+func (r *SensorRepository) LookupTenant(ctx context.Context, keyID string) (string, error) {
+	var tenantID string
+	err := r.db.GetContext(ctx, &tenantID, `
+		SELECT tenant_id FROM public.temperature_sensor_lookup WHERE key_id = $1
+	`, keyID)
+
+	if err == sql.ErrNoRows {
+		return "", errors.NotFound("temperature_sensor")
+	}
+	if err != nil {
+		return "", err
+	}
+	return tenantID, nil
+}
+
+// GetActiveByKey gets a sensor that is not revoked, with its secret
+// TENANT-ISOLATED: Queries via RLS
+func (r *SensorRepository) GetActiveByKey(ctx context.Context, keyID string) (*TemperatureSensor, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var sensor TemperatureSensor
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			SELECT id, cabinet_id, name, key_id, secret, last_seen_at, revoked_at, created_by, created_at, updated_at
+			FROM temperature_sensors WHERE key_id = $1 AND revoked_at IS NULL
+		`
+		return r.db.GetContext(ctx, &sensor, query, keyID)
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFound("temperature_sensor")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &sensor, nil
+}
+
+// RotateSecret replaces the secret of a sensor that is not revoked
+// TENANT-ISOLATED: Updates via RLS
+func (r *SensorRepository) RotateSecret(ctx context.Context, id, secret string) error {
+	return r.update(ctx, id, `secret = $2`, secret)
+}
+
+// Revoke revokes a sensor and removes its key ID from the lookup, so its
+// requests are rejected
+// TENANT-ISOLATED: Updates via RLS
+func (r *SensorRepository) Revoke(ctx context.Context, id string) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		var keyID string
+		err := r.db.GetContext(ctx, &keyID, `
+			UPDATE temperature_sensors SET revoked_at = NOW()
+			WHERE id = $1 AND revoked_at IS NULL
+			RETURNING key_id
+		`, id)
+		if err == sql.ErrNoRows {
+			return errors.NotFound("temperature_sensor")
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = r.db.ExecContext(ctx, `DELETE FROM public.temperature_sensor_lookup WHERE key_id = $1`, keyID)
+		return err
+	})
+}
+
+// Touch records when a sensor last sent readings
+// TENANT-ISOLATED: Updates via RLS
+func (r *SensorRepository) Touch(ctx context.Context, id string, seenAt time.Time) error {
+	return r.update(ctx, id, `last_seen_at = GREATEST(last_seen_at, $2)`, seenAt)
+}
+
+// update sets the columns of a sensor that is not revoked; set's parameters
+// start at $2
+func (r *SensorRepository) update(ctx context.Context, id, set string, args ...interface{}) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		result, err := r.db.ExecContext(ctx, `UPDATE temperature_sensors SET `+set+` WHERE id = $1 AND revoked_at IS NULL`,
+			append([]interface{}{id}, args...)...)
+		if err != nil {
+			return err
+		}
+		affected, _ := result.RowsAffected()
+		if affected == 0 {
+			return errors.NotFound("temperature_sensor")
+		}
+		return nil
+	})
+}
@@ -0,0 +1,673 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/tenant"
+)
+
+// Stocktake statuses
+const (
+	StocktakeCounting  = "counting"
+	StocktakeReview    = "review"
+	StocktakeApproved  = "approved"
+	StocktakeCancelled = "cancelled"
+)
+
+// Stocktake is an Inventur session over all or some storage locations
+type Stocktake struct {
+	ID                 string         `db:"id" json:"id"`
+	StocktakeNumber    string         `db:"stocktake_number" json:"stocktake_number"`
+	Title              string         `db:"title" json:"title"`
+	Status             string         `db:"status" json:"status"` // counting, review, approved, cancelled
+	Blind              bool           `db:"blind" json:"blind"`   // hide expected quantities while counting
+	LocationIDs        pq.StringArray `db:"location_ids" json:"location_ids"`
+	Notes              *string        `db:"notes" json:"notes,omitempty"`
+	StartedAt          time.Time      `db:"started_at" json:"started_at"`
+	StartedBy          *string        `db:"started_by" json:"started_by,omitempty"`
+	StartedByName      *string        `db:"started_by_name" json:"started_by_name,omitempty"`
+	ReviewAt           *time.Time     `db:"review_at" json:"review_at,omitempty"`
+	ApprovedAt         *time.Time     `db:"approved_at" json:"approved_at,omitempty"`
+	ApprovedBy         *string        `db:"approved_by" json:"approved_by,omitempty"`
+	ApprovedByName     *string        `db:"approved_by_name" json:"approved_by_name,omitempty"`
+	CancelledAt        *time.Time     `db:"cancelled_at" json:"cancelled_at,omitempty"`
+	VarianceValueCents *int64         `db:"variance_value_cents" json:"variance_value_cents,omitempty"`
+	ProtocolSHA256     *string        `db:"protocol_sha256" json:"protocol_sha256,omitempty"`
+	ProtocolSeal       *string        `db:"protocol_seal" json:"protocol_seal,omitempty"` // keyed HMAC of the digest
+	CreatedAt          time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt          time.Time      `db:"updated_at" json:"updated_at"`
+
+	// Computed
+	LineCount    int `db:"line_count" json:"line_count"`
+	CountedLines int `db:"counted_lines" json:"counted_lines"`
+}
+
+// StocktakeLine is the frozen expectation and the count for one batch
+type StocktakeLine struct {
+	ID               string     `db:"id" json:"id"`
+	StocktakeID      string     `db:"stocktake_id" json:"stocktake_id"`
+	ItemID           string     `db:"item_id" json:"item_id"`
+	BatchID          string     `db:"batch_id" json:"batch_id"`
+	LocationID       *string    `db:"location_id" json:"location_id,omitempty"`
+	ExpectedQuantity int        `db:"expected_quantity" json:"expected_quantity"`
+	UnitPriceCents   int        `db:"unit_price_cents" json:"unit_price_cents"`
+	CountedQuantity  *int       `db:"counted_quantity" json:"counted_quantity,omitempty"`
+	LastCountedAt    *time.Time `db:"last_counted_at" json:"last_counted_at,omitempty"`
+	AdjustmentID     *string    `db:"adjustment_id" json:"adjustment_id,omitempty"`
+	CreatedAt        time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time  `db:"updated_at" json:"updated_at"`
+
+	// Joined from item and batch
+	ItemName    string     `db:"item_name" json:"item_name"`
+	Unit        string     `db:"unit" json:"unit"`
+	BatchNumber string     `db:"batch_number" json:"batch_number"`
+	ExpiryDate  *time.Time `db:"expiry_date" json:"expiry_date,omitempty"`
+}
+
+// Variance is the counted minus the expected quantity (uncounted counts as zero)
+func (l *StocktakeLine) Variance() int {
+	counted := 0
+	if l.CountedQuantity != nil {
+		counted = *l.CountedQuantity
+	}
+	return counted - l.ExpectedQuantity
+}
+
+// VarianceValueCents values the variance at the frozen unit price
+func (l *StocktakeLine) VarianceValueCents() int64 {
+	return int64(l.Variance()) * int64(l.UnitPriceCents)
+}
+
+// StocktakeCount is one entry on a count sheet
+type StocktakeCount struct {
+	ID            string    `db:"id" json:"id"`
+	StocktakeID   string    `db:"stocktake_id" json:"stocktake_id"`
+	LineID        string    `db:"line_id" json:"line_id"`
+	Quantity      int       `db:"quantity" json:"quantity"`
+	Method        string    `db:"method" json:"method"` // manual, scan
+	CountedBy     *string   `db:"counted_by" json:"counted_by,omitempty"`
+	CountedByName *string   `db:"counted_by_name" json:"counted_by_name,omitempty"`
+	CountedAt     time.Time `db:"counted_at" json:"counted_at"`
+}
+
+// StocktakeRepository handles stocktake persistence
+type StocktakeRepository struct {
+	db *database.DB
+}
+
+// NewStocktakeRepository creates a new stocktake repository
+func NewStocktakeRepository(db *database.DB) *StocktakeRepository {
+	return &StocktakeRepository{db: db}
+}
+
+const stocktakeSelect = `
+	SELECT s.id, s.stocktake_number, s.title, s.status, s.blind, s.location_ids, s.notes,
+	       s.started_at, s.started_by, s.started_by_name, s.review_at, s.approved_at,
+	       s.approved_by, s.approved_by_name, s.cancelled_at, s.variance_value_cents,
+	       s.protocol_sha256, s.protocol_seal, s.created_at, s.updated_at,
+	       (SELECT COUNT(*) FROM stocktake_lines l WHERE l.stocktake_id = s.id) AS line_count,
+	       (SELECT COUNT(*) FROM stocktake_lines l WHERE l.stocktake_id = s.id AND l.counted_quantity IS NOT NULL) AS counted_lines
+	FROM stocktakes s
+`
+
+const stocktakeLineSelect = `
+	SELECT l.id, l.stocktake_id, l.item_id, l.batch_id, l.location_id, l.expected_quantity,
+	       l.unit_price_cents, l.counted_quantity, l.last_counted_at, l.adjustment_id,
+	       l.created_at, l.updated_at,
+	       i.name AS item_name, i.unit, b.batch_number, b.expiry_date
+	FROM stocktake_lines l
+	JOIN inventory_items i ON i.id = l.item_id
+	JOIN inventory_batches b ON b.id = l.batch_id
+`
+
+// Create starts a stocktake and freezes the expected quantity and unit price
+// of every batch with stock in scope. Rooms and cabinets in LocationIDs
+// include their cabinets and shelves. Only one stocktake may be open at a time.
+// TENANT-ISOLATED: Inserts via RLS
+func (r *StocktakeRepository) Create(ctx context.Context, st *Stocktake) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	if st.ID == "" {
+		st.ID = uuid.New().String()
+	}
+	if st.LocationIDs == nil {
+		st.LocationIDs = pq.StringArray{}
+	}
+	st.Status = StocktakeCounting
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		// Serialize starts (numbering and the open-session check) per tenant
+		if _, err := r.db.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('stocktakes:' || $1))`, tenantID); err != nil {
+			return err
+		}
+
+		var open int
+		if err := r.db.GetContext(ctx, &open, `SELECT COUNT(*) FROM stocktakes WHERE status IN ('counting', 'review')`); err != nil {
+			return err
+		}
+		if open > 0 {
+			return errors.Conflict("errors.stocktake.already_open")
+		}
+
+		prefix := fmt.Sprintf("INV-%d-", time.Now().Year())
+		var count int
+		if err := r.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM stocktakes WHERE stocktake_number LIKE $1`, prefix+"%"); err != nil {
+			return err
+		}
+		st.StocktakeNumber = fmt.Sprintf("%s%03d", prefix, count+1)
+
+		query := `
+			INSERT INTO stocktakes (
+				id, tenant_id, stocktake_number, title, status, blind, location_ids, notes,
+				started_by, started_by_name
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			RETURNING started_at, created_at, updated_at
+		`
+		if err := r.db.QueryRowxContext(ctx, query,
+			st.ID, tenantID, st.StocktakeNumber, st.Title, st.Status, st.Blind, st.LocationIDs, st.Notes,
+			st.StartedBy, st.StartedByName,
+		).Scan(&st.StartedAt, &st.CreatedAt, &st.UpdatedAt); err != nil {
+			if appErr := database.MapPQError(err); appErr != nil {
+				return appErr
+			}
+			return err
+		}
+
+		freezeQuery := `
+			WITH scope AS (
+				SELECT unnest($3::uuid[]) AS id
+				UNION SELECT c.id FROM storage_cabinets c WHERE c.room_id = ANY($3::uuid[])
+				UNION SELECT s.id FROM storage_shelves s
+				      JOIN storage_cabinets c ON c.id = s.cabinet_id
+				      WHERE s.cabinet_id = ANY($3::uuid[]) OR c.room_id = ANY($3::uuid[])
+			)
+			INSERT INTO stocktake_lines (
+				tenant_id, stocktake_id, item_id, batch_id, location_id, expected_quantity, unit_price_cents
+			)
+			SELECT $1, $2, b.item_id, b.id, b.location_id, b.current_quantity, COALESCE(i.unit_price_cents, 0)
+			FROM inventory_batches b
+			JOIN inventory_items i ON i.id = b.item_id
+			WHERE b.deleted_at IS NULL AND i.deleted_at IS NULL AND b.current_quantity > 0
+			  AND (cardinality($3::uuid[]) = 0 OR b.location_id IN (SELECT id FROM scope))
+		`
+		res, err := r.db.ExecContext(ctx, freezeQuery, tenantID, st.ID, st.LocationIDs)
+		if err != nil {
+			return err
+		}
+		lines, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if lines == 0 {
+			return errors.BadRequest("errors.stocktake.empty_scope")
+		}
+		st.LineCount = int(lines)
+		return nil
+	})
+}
+
+// GetByID gets a stocktake by ID
+// TENANT-ISOLATED: Queries via RLS
+func (r *StocktakeRepository) GetByID(ctx context.Context, id string) (*Stocktake, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var st Stocktake
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		return r.db.GetContext(ctx, &st, stocktakeSelect+` WHERE s.id = $1`, id)
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFound("stocktake")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+var stocktakeListSchema = database.ListSchema{
+	Fields: map[string]database.Field{
+		"stocktake_number": {Column: "stocktake_number", Type: database.FieldText, Filter: true, Sort: true},
+		"title":            {Column: "title", Type: database.FieldText, Filter: true, Sort: true},
+		"status":           {Column: "status", Type: database.FieldText, Filter: true, Sort: true},
+		"started_at":       {Column: "started_at", Type: database.FieldTime, Filter: true, Sort: true},
+		"approved_at":      {Column: "approved_at", Type: database.FieldTime, Filter: true, Sort: true, Nullable: true},
+	},
+	DefaultSort: []string{"-started_at"},
+}
+
+// List lists stocktakes filtered, sorted and paginated by q
+// TENANT-ISOLATED: Returns only stocktakes via RLS
+func (r *StocktakeRepository) List(ctx context.Context, q *database.ListQuery) (*database.Page[*Stocktake], error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var page *database.Page[*Stocktake]
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `SELECT * FROM (` + stocktakeSelect + `) AS stocktakes WHERE 1=1`
+		var err error
+		page, err = database.SelectPage[*Stocktake](ctx, r.db, &stocktakeListSchema, q, query)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// ListLines lists the lines of a stocktake by location, item and batch
+// TENANT-ISOLATED: Returns only lines via RLS
+func (r *StocktakeRepository) ListLines(ctx context.Context, stocktakeID string) ([]*StocktakeLine, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []*StocktakeLine
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := stocktakeLineSelect + ` WHERE l.stocktake_id = $1 ORDER BY l.location_id NULLS LAST, i.name, b.batch_number`
+		return r.db.SelectContext(ctx, &lines, query, stocktakeID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// FindLines finds the lines a scan refers to. code matches the item's
+// barcode, article number or PZN; batchNumber narrows to one batch.
+// Empty arguments are ignored.
+// TENANT-ISOLATED: Returns only lines via RLS
+func (r *StocktakeRepository) FindLines(ctx context.Context, stocktakeID, code, batchNumber string) ([]*StocktakeLine, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []*StocktakeLine
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := stocktakeLineSelect + `
+			WHERE l.stocktake_id = $1
+			  AND ($2 = '' OR i.barcode = $2 OR i.article_number = $2 OR i.pzn = $2)
+			  AND ($3 = '' OR b.batch_number = $3)
+		`
+		return r.db.SelectContext(ctx, &lines, query, stocktakeID, code, batchNumber)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// ListCounts lists the count sheet entries of a stocktake in order
+// TENANT-ISOLATED: Returns only counts via RLS
+func (r *StocktakeRepository) ListCounts(ctx context.Context, stocktakeID string) ([]*StocktakeCount, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var counts []*StocktakeCount
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			SELECT id, stocktake_id, line_id, quantity, method, counted_by, counted_by_name, counted_at
+			FROM stocktake_counts WHERE stocktake_id = $1 ORDER BY counted_at, id
+		`
+		return r.db.SelectContext(ctx, &counts, query, stocktakeID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// lockStocktake locks a stocktake for the rest of the transaction and returns its status.
+// shared allows concurrent counters while blocking status changes.
+func (r *StocktakeRepository) lockStocktake(ctx context.Context, id string, shared bool) (string, error) {
+	lock := "FOR UPDATE"
+	if shared {
+		lock = "FOR SHARE"
+	}
+	var status string
+	if err := r.db.QueryRowxContext(ctx, `SELECT status FROM stocktakes WHERE id = $1 `+lock, id).Scan(&status); err != nil {
+		if err == sql.ErrNoRows {
+			return "", errors.NotFound("stocktake")
+		}
+		return "", err
+	}
+	return status, nil
+}
+
+// invalidStocktakeTransition is returned when a stocktake's status does not allow an action
+func invalidStocktakeTransition(status, action string) error {
+	return errors.Conflict("errors.stocktake.invalid_status", map[string]string{"status": status, "action": action})
+}
+
+// AddCount adds a count sheet entry to its line. Counting is only possible
+// while the stocktake is counting; the line total may not become negative.
+// TENANT-ISOLATED: Updates and inserts via RLS
+func (r *StocktakeRepository) AddCount(ctx context.Context, count *StocktakeCount) (*StocktakeLine, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if count.ID == "" {
+		count.ID = uuid.New().String()
+	}
+	if count.Method == "" {
+		count.Method = "manual"
+	}
+
+	var line StocktakeLine
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		status, err := r.lockStocktake(ctx, count.StocktakeID, true)
+		if err != nil {
+			return err
+		}
+		if status != StocktakeCounting {
+			return invalidStocktakeTransition(status, "count")
+		}
+
+		var current sql.NullInt64
+		lockQuery := `SELECT counted_quantity FROM stocktake_lines WHERE id = $1 AND stocktake_id = $2 FOR UPDATE`
+		if err := r.db.QueryRowxContext(ctx, lockQuery, count.LineID, count.StocktakeID).Scan(&current); err != nil {
+			if err == sql.ErrNoRows {
+				return errors.NotFound("stocktake_line")
+			}
+			return err
+		}
+		if total := int(current.Int64) + count.Quantity; total < 0 {
+			return errors.BadRequest("errors.stocktake.negative_count", map[string]string{
+				"quantity": strconv.Itoa(count.Quantity),
+				"counted":  strconv.FormatInt(current.Int64, 10),
+			})
+		}
+
+		countQuery := `
+			INSERT INTO stocktake_counts (
+				id, tenant_id, stocktake_id, line_id, quantity, method, counted_by, counted_by_name
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING counted_at
+		`
+		if err := r.db.QueryRowxContext(ctx, countQuery,
+			count.ID, tenantID, count.StocktakeID, count.LineID, count.Quantity, count.Method,
+			count.CountedBy, count.CountedByName,
+		).Scan(&count.CountedAt); err != nil {
+			return err
+		}
+
+		updateQuery := `
+			UPDATE stocktake_lines
+			SET counted_quantity = COALESCE(counted_quantity, 0) + $2, last_counted_at = $3
+			WHERE id = $1
+		`
+		if _, err := r.db.ExecContext(ctx, updateQuery, count.LineID, count.Quantity, count.CountedAt); err != nil {
+			return err
+		}
+
+		return r.db.GetContext(ctx, &line, stocktakeLineSelect+` WHERE l.id = $1`, count.LineID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &line, nil
+}
+
+// FinishCounting closes counting so variances can be reviewed
+// TENANT-ISOLATED: Updates via RLS
+func (r *StocktakeRepository) FinishCounting(ctx context.Context, id string) error {
+	return r.transition(ctx, id, "finish", []string{StocktakeCounting},
+		`UPDATE stocktakes SET status = 'review', review_at = NOW() WHERE id = $1`)
+}
+
+// Reopen returns a stocktake under review to counting for recounts
+// TENANT-ISOLATED: Updates via RLS
+func (r *StocktakeRepository) Reopen(ctx context.Context, id string) error {
+	return r.transition(ctx, id, "reopen", []string{StocktakeReview},
+		`UPDATE stocktakes SET status = 'counting', review_at = NULL WHERE id = $1`)
+}
+
+// Cancel cancels a stocktake that has not been approved; nothing is posted
+// TENANT-ISOLATED: Updates via RLS
+func (r *StocktakeRepository) Cancel(ctx context.Context, id string) error {
+	return r.transition(ctx, id, "cancel", []string{StocktakeCounting, StocktakeReview},
+		`UPDATE stocktakes SET status = 'cancelled', cancelled_at = NOW() WHERE id = $1`)
+}
+
+// transition runs a status update if the stocktake is in one of the allowed statuses
+func (r *StocktakeRepository) transition(ctx context.Context, id, action string, from []string, query string) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		status, err := r.lockStocktake(ctx, id, false)
+		if err != nil {
+			return err
+		}
+		allowed := false
+		for _, s := range from {
+			if status == s {
+				allowed = true
+			}
+		}
+		if !allowed {
+			return invalidStocktakeTransition(status, action)
+		}
+		_, err = r.db.ExecContext(ctx, query, id)
+		return err
+	})
+}
+
+// Approve posts every variance as a 'count' adjustment on its batch and seals
+// the stocktake, all in one transaction. Variances are applied as deltas so
+// movements booked since the start are kept. Every line must be counted, and
+// no batch may end up with less stock than is reserved on it: a 409 conflict
+// then names the reservations of each such batch and nothing is posted.
+// seal computes the keyed seal of the protocol digest.
+// Returns the posted adjustments.
+// TENANT-ISOLATED: Updates and inserts via RLS
+func (r *StocktakeRepository) Approve(ctx context.Context, id string, approvedBy, approvedByName *string, seal func(digest string) string) ([]*StockAdjustment, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var adjustments []*StockAdjustment
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		status, err := r.lockStocktake(ctx, id, false)
+		if err != nil {
+			return err
+		}
+		if status != StocktakeReview {
+			return invalidStocktakeTransition(status, "approve")
+		}
+
+		var st Stocktake
+		if err := r.db.GetContext(ctx, &st, stocktakeSelect+` WHERE s.id = $1`, id); err != nil {
+			return err
+		}
+		if uncounted := st.LineCount - st.CountedLines; uncounted > 0 {
+			return errors.BadRequest("errors.stocktake.uncounted_lines", map[string]string{"count": strconv.Itoa(uncounted)})
+		}
+
+		var lines []*StocktakeLine
+		if err := r.db.SelectContext(ctx, &lines, stocktakeLineSelect+` WHERE l.stocktake_id = $1`, id); err != nil {
+			return err
+		}
+
+		reason := "Inventur " + st.StocktakeNumber
+		referenceType := "stocktake"
+		performedBy := ""
+		if approvedBy != nil {
+			performedBy = *approvedBy
+		}
+
+		// Stock counted below its reservations is not posted; the conflict
+		// names every reservation to release or reduce first
+		var reservedConflict *errors.AppError
+		var varianceValue int64
+		for _, line := range lines {
+			variance := line.Variance()
+			if variance == 0 {
+				continue
+			}
+			varianceValue += line.VarianceValueCents()
+
+			var current, reserved int
+			lockQuery := `SELECT current_quantity, reserved_quantity FROM inventory_batches WHERE id = $1 FOR UPDATE`
+			if err := r.db.QueryRowxContext(ctx, lockQuery, line.BatchID).Scan(&current, &reserved); err != nil {
+				return err
+			}
+			newQty := current + variance
+			if newQty < 0 {
+				return errors.BadRequest("errors.stocktake.negative_stock", map[string]string{
+					"batch":    line.BatchNumber,
+					"current":  strconv.Itoa(current),
+					"variance": strconv.Itoa(variance),
+				})
+			}
+			if newQty < reserved {
+				if reservedConflict == nil {
+					reservedConflict = errors.Conflict("errors.stocktake.reserved_stock")
+				}
+				if err := r.addReservationDetails(ctx, reservedConflict, line, newQty, reserved); err != nil {
+					return err
+				}
+				continue
+			}
+			if _, err := r.db.ExecContext(ctx, `UPDATE inventory_batches SET current_quantity = $2 WHERE id = $1`, line.BatchID, newQty); err != nil {
+				return err
+			}
+
+			quantity := variance
+			if quantity < 0 {
+				quantity = -quantity
+			}
+			adj := &StockAdjustment{
+				ItemID:           line.ItemID,
+				BatchID:          &line.BatchID,
+				AdjustmentType:   "count",
+				Quantity:         quantity,
+				PreviousQuantity: current,
+				NewQuantity:      newQty,
+				Reason:           &reason,
+				ReferenceType:    &referenceType,
+				ReferenceID:      &st.ID,
+				PerformedBy:      performedBy,
+				PerformedByName:  approvedByName,
+			}
+			if err := insertStockAdjustment(ctx, r.db, tenantID, adj); err != nil {
+				return err
+			}
+			if _, err := r.db.ExecContext(ctx, `UPDATE stocktake_lines SET adjustment_id = $2 WHERE id = $1`, line.ID, adj.ID); err != nil {
+				return err
+			}
+			adjustments = append(adjustments, adj)
+		}
+		if reservedConflict != nil {
+			return reservedConflict
+		}
+
+		approvedAt := time.Now().UTC().Truncate(time.Second)
+		st.ApprovedAt = &approvedAt
+		st.ApprovedBy = approvedBy
+		st.ApprovedByName = approvedByName
+		digest := StocktakeDigest(&st, lines)
+
+		sealQuery := `
+			UPDATE stocktakes
+			SET status = 'approved', approved_at = $2, approved_by = $3, approved_by_name = $4,
+			    variance_value_cents = $5, protocol_sha256 = $6, protocol_seal = $7
+			WHERE id = $1
+		`
+		_, err = r.db.ExecContext(ctx, sealQuery, id, approvedAt, approvedBy, approvedByName, varianceValue, digest, seal(digest))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return adjustments, nil
+}
+
+// addReservationDetails adds the active reservations of a line's batch to
+// conflict, keyed by reservation ID
+func (r *StocktakeRepository) addReservationDetails(ctx context.Context, conflict *errors.AppError, line *StocktakeLine, stock, reserved int) error {
+	var holds []struct {
+		ID       string `db:"id"`
+		Name     string `db:"name"`
+		Quantity int    `db:"quantity"`
+	}
+	query := `
+		SELECT r.id, COALESCE(r.procedure_name, r.id::text) AS name, rb.quantity
+		FROM stock_reservation_batches rb
+		JOIN stock_reservations r ON r.id = rb.reservation_id
+		WHERE rb.batch_id = $1 AND r.status = 'active'
+		ORDER BY r.needed_at NULLS LAST, r.created_at
+	`
+	if err := r.db.SelectContext(ctx, &holds, query, line.BatchID); err != nil {
+		return err
+	}
+	for _, h := range holds {
+		conflict.WithDetail(h.ID, "errors.stocktake.reservation_on_batch", map[string]string{
+			"reservation": h.Name,
+			"quantity":    strconv.Itoa(h.Quantity),
+			"batch":       line.BatchNumber,
+			"stock":       strconv.Itoa(stock),
+			"reserved":    strconv.Itoa(reserved),
+		})
+	}
+	return nil
+}
+
+// StocktakeDigest is the SHA-256 over the approved result of a stocktake:
+// its number, approval time and approver, and every line's batch, frozen
+// expectation, count and unit price. It is stored on approval and printed on
+// the protocol so the document can be checked against the database.
+func StocktakeDigest(st *Stocktake, lines []*StocktakeLine) string {
+	sorted := make([]*StocktakeLine, len(lines))
+	copy(sorted, lines)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].BatchID < sorted[j].BatchID })
+
+	h := sha256.New()
+	approvedAt := ""
+	if st.ApprovedAt != nil {
+		approvedAt = st.ApprovedAt.UTC().Format(time.RFC3339)
+	}
+	approvedBy := ""
+	if st.ApprovedByName != nil {
+		approvedBy = *st.ApprovedByName
+	}
+	fmt.Fprintf(h, "%s|%s|%s|%s\n", st.ID, st.StocktakeNumber, approvedAt, approvedBy)
+	for _, l := range sorted {
+		counted := 0
+		if l.CountedQuantity != nil {
+			counted = *l.CountedQuantity
+		}
+		fmt.Fprintf(h, "%s|%s|%d|%d|%d\n", l.BatchID, l.ItemID, l.ExpectedQuantity, counted, l.UnitPriceCents)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
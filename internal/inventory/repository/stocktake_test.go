@@ -0,0 +1,163 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/hmacsig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Stocktake Tests ---
+
+func TestStocktakeRepository_CountAndApprove(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tenant := suite.SetupInventoryTenant(t, ctx, "stocktake-approve")
+	tenantCtx := suite.TenantContext(tenant)
+
+	itemRepo := repository.NewItemRepository(suite.DB)
+	batchRepo := repository.NewBatchRepository(suite.DB)
+	stocktakeRepo := repository.NewStocktakeRepository(suite.DB)
+
+	item := createTestItem(t, tenantCtx, itemRepo, "Spritzen 5ml")
+	room, ambient, fridge := createTestLocations(t, tenantCtx)
+	short := createTestBatch(t, tenantCtx, item.ID, ambient.ID, 10)
+	surplus := createTestBatch(t, tenantCtx, item.ID, fridge.ID, 5)
+
+	userName := "inventur@praxis.de"
+	st := &repository.Stocktake{Title: "Jahresinventur", Blind: true, LocationIDs: []string{room.ID}, StartedByName: &userName}
+	require.NoError(t, stocktakeRepo.Create(tenantCtx, st))
+	assert.Equal(t, repository.StocktakeCounting, st.Status)
+	assert.Contains(t, st.StocktakeNumber, "INV-")
+
+	// A second session cannot start while one is open
+	err := stocktakeRepo.Create(tenantCtx, &repository.Stocktake{Title: "Zweite"})
+	var appErr *errors.AppError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, "errors.stocktake.already_open", appErr.MessageKey)
+
+	// The room scope includes both cabinets
+	lines, err := stocktakeRepo.ListLines(tenantCtx, st.ID)
+	require.NoError(t, err)
+	require.Len(t, lines, 2)
+	lineByBatch := map[string]*repository.StocktakeLine{}
+	for _, l := range lines {
+		lineByBatch[l.BatchID] = l
+	}
+
+	// Two counters on the short batch, with a correction; one on the surplus batch
+	for _, q := range []int{5, 4, -1} {
+		_, err := stocktakeRepo.AddCount(tenantCtx, &repository.StocktakeCount{StocktakeID: st.ID, LineID: lineByBatch[short.ID].ID, Quantity: q, Method: "manual"})
+		require.NoError(t, err)
+	}
+	line, err := stocktakeRepo.AddCount(tenantCtx, &repository.StocktakeCount{StocktakeID: st.ID, LineID: lineByBatch[surplus.ID].ID, Quantity: 7, Method: "scan"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, line.Variance())
+
+	_, err = stocktakeRepo.AddCount(tenantCtx, &repository.StocktakeCount{StocktakeID: st.ID, LineID: line.ID, Quantity: -8, Method: "manual"})
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, "errors.stocktake.negative_count", appErr.MessageKey)
+
+	// Approval requires review
+	seal := func(digest string) string { return hmacsig.Seal("test-seal-key", digest) }
+	_, err = stocktakeRepo.Approve(tenantCtx, st.ID, nil, &userName, seal)
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, "errors.stocktake.invalid_status", appErr.MessageKey)
+
+	require.NoError(t, stocktakeRepo.FinishCounting(tenantCtx, st.ID))
+
+	// Stock used during the count is kept: the variance is posted as a delta
+	require.NoError(t, batchRepo.AdjustStock(tenantCtx, &repository.StockAdjustment{
+		ItemID: item.ID, BatchID: &short.ID, AdjustmentType: "deduct", Quantity: 1, PerformedBy: "user-1",
+	}))
+
+	adjustments, err := stocktakeRepo.Approve(tenantCtx, st.ID, nil, &userName, seal)
+	require.NoError(t, err)
+	require.Len(t, adjustments, 2)
+	for _, adj := range adjustments {
+		assert.Equal(t, "count", adj.AdjustmentType)
+		assert.Equal(t, st.ID, *adj.ReferenceID)
+	}
+
+	got, err := batchRepo.GetByID(tenantCtx, short.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 7, got.CurrentQuantity) // 9 - (10 - 8)
+	got, err = batchRepo.GetByID(tenantCtx, surplus.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 7, got.CurrentQuantity)
+
+	approved, err := stocktakeRepo.GetByID(tenantCtx, st.ID)
+	require.NoError(t, err)
+	assert.Equal(t, repository.StocktakeApproved, approved.Status)
+	require.NotNil(t, approved.ProtocolSHA256)
+	lines, err = stocktakeRepo.ListLines(tenantCtx, st.ID)
+	require.NoError(t, err)
+	assert.Equal(t, *approved.ProtocolSHA256, repository.StocktakeDigest(approved, lines))
+	require.NotNil(t, approved.ProtocolSeal)
+	assert.True(t, hmacsig.VerifySeal("test-seal-key", *approved.ProtocolSHA256, *approved.ProtocolSeal))
+	for _, l := range lines {
+		assert.NotNil(t, l.AdjustmentID)
+	}
+
+	// A new session can start once the previous one is approved
+	require.NoError(t, stocktakeRepo.Create(tenantCtx, &repository.Stocktake{Title: "Nachinventur"}))
+}
+
+func TestStocktakeRepository_ApproveBelowReservedStock(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tenant := suite.SetupInventoryTenant(t, ctx, "stocktake-reserved")
+	tenantCtx := suite.TenantContext(tenant)
+
+	itemRepo := repository.NewItemRepository(suite.DB)
+	batchRepo := repository.NewBatchRepository(suite.DB)
+	reservationRepo := repository.NewReservationRepository(suite.DB)
+	stocktakeRepo := repository.NewStocktakeRepository(suite.DB)
+
+	item := createTestItem(t, tenantCtx, itemRepo, "Kanülen")
+	room, ambient, _ := createTestLocations(t, tenantCtx)
+	batch := createTestBatch(t, tenantCtx, item.ID, ambient.ID, 10)
+
+	procedure := "Infusion Raum 2"
+	res := &repository.StockReservation{ItemID: item.ID, Quantity: 6, ProcedureName: &procedure}
+	require.NoError(t, reservationRepo.Create(tenantCtx, res, []string{batch.ID}))
+
+	st := &repository.Stocktake{Title: "Stichprobe", LocationIDs: []string{room.ID}}
+	require.NoError(t, stocktakeRepo.Create(tenantCtx, st))
+	lines, err := stocktakeRepo.ListLines(tenantCtx, st.ID)
+	require.NoError(t, err)
+	require.Len(t, lines, 1)
+	_, err = stocktakeRepo.AddCount(tenantCtx, &repository.StocktakeCount{StocktakeID: st.ID, LineID: lines[0].ID, Quantity: 4, Method: "manual"})
+	require.NoError(t, err)
+	require.NoError(t, stocktakeRepo.FinishCounting(tenantCtx, st.ID))
+
+	// Counted stock below the reservation is a conflict naming the reservation
+	seal := func(digest string) string { return hmacsig.Seal("test-seal-key", digest) }
+	_, err = stocktakeRepo.Approve(tenantCtx, st.ID, nil, nil, seal)
+	var appErr *errors.AppError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, "errors.stocktake.reserved_stock", appErr.MessageKey)
+	assert.Equal(t, "errors.stocktake.reservation_on_batch", appErr.Details[res.ID])
+
+	got, err := batchRepo.GetByID(tenantCtx, batch.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 10, got.CurrentQuantity)
+	assert.Equal(t, 6, got.ReservedQuantity)
+
+	// Once the reservation is released the count is posted
+	require.NoError(t, reservationRepo.Release(tenantCtx, res.ID))
+	_, err = stocktakeRepo.Approve(tenantCtx, st.ID, nil, nil, seal)
+	require.NoError(t, err)
+
+	got, err = batchRepo.GetByID(tenantCtx, batch.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 4, got.CurrentQuantity)
+}
@@ -0,0 +1,394 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/tenant"
+)
+
+// Supplier represents a supplier (Lieferant) the practice orders from
+type Supplier struct {
+	ID                 string     `db:"id" json:"id"`
+	Name               string     `db:"name" json:"name"`
+	CustomerNumber     *string    `db:"customer_number" json:"customer_number,omitempty"` // the practice's number at the supplier
+	ContactName        *string    `db:"contact_name" json:"contact_name,omitempty"`
+	Email              *string    `db:"email" json:"email,omitempty"`
+	Phone              *string    `db:"phone" json:"phone,omitempty"`
+	Fax                *string    `db:"fax" json:"fax,omitempty"`
+	Website            *string    `db:"website" json:"website,omitempty"`
+	Address            *string    `db:"address" json:"address,omitempty"`
+	LeadTimeDays       int        `db:"lead_time_days" json:"lead_time_days"`
+	MinOrderValueCents *int       `db:"min_order_value_cents" json:"min_order_value_cents,omitempty"`
+	Notes              *string    `db:"notes" json:"notes,omitempty"`
+	IsActive           bool       `db:"is_active" json:"is_active"`
+	CreatedAt          time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt          time.Time  `db:"updated_at" json:"updated_at"`
+	DeletedAt          *time.Time `db:"deleted_at" json:"-"`
+}
+
+// SupplierItem links an item to a supplier that delivers it
+type SupplierItem struct {
+	ID                    string    `db:"id" json:"id"`
+	SupplierID            string    `db:"supplier_id" json:"supplier_id"`
+	ItemID                string    `db:"item_id" json:"item_id"`
+	SupplierArticleNumber *string   `db:"supplier_article_number" json:"supplier_article_number,omitempty"`
+	UnitPriceCents        *int      `db:"unit_price_cents" json:"unit_price_cents,omitempty"`
+	PackSize              int       `db:"pack_size" json:"pack_size"` // orders are rounded up to whole packs
+	IsPreferred           bool      `db:"is_preferred" json:"is_preferred"`
+	CreatedAt             time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt             time.Time `db:"updated_at" json:"updated_at"`
+	// Joined fields for API (not in supplier_items)
+	ItemName     string `db:"item_name" json:"item_name,omitempty"`
+	SupplierName string `db:"supplier_name" json:"supplier_name,omitempty"`
+}
+
+// SupplierRepository handles supplier persistence
+type SupplierRepository struct {
+	db *database.DB
+}
+
+// NewSupplierRepository creates a new supplier repository
+func NewSupplierRepository(db *database.DB) *SupplierRepository {
+	return &SupplierRepository{db: db}
+}
+
+const supplierColumns = `
+	id, name, customer_number, contact_name, email, phone, fax, website, address,
+	lead_time_days, min_order_value_cents, notes, is_active, created_at, updated_at
+`
+
+// Create creates a new supplier
+// TENANT-ISOLATED: Inserts with tenant_id for RLS
+func (r *SupplierRepository) Create(ctx context.Context, supplier *Supplier) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	if supplier.ID == "" {
+		supplier.ID = uuid.New().String()
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			INSERT INTO suppliers (
+				id, tenant_id, name, customer_number, contact_name, email, phone, fax, website,
+				address, lead_time_days, min_order_value_cents, notes, is_active
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+			RETURNING created_at, updated_at
+		`
+
+		err := r.db.QueryRowxContext(ctx, query,
+			supplier.ID, tenantID, supplier.Name, supplier.CustomerNumber, supplier.ContactName,
+			supplier.Email, supplier.Phone, supplier.Fax, supplier.Website, supplier.Address,
+			supplier.LeadTimeDays, supplier.MinOrderValueCents, supplier.Notes, supplier.IsActive,
+		).Scan(&supplier.CreatedAt, &supplier.UpdatedAt)
+		if appErr := database.MapPQError(err); appErr != nil {
+			return appErr
+		}
+		return err
+	})
+}
+
+// GetByID gets a supplier by ID
+// TENANT-ISOLATED: Queries via RLS
+func (r *SupplierRepository) GetByID(ctx context.Context, id string) (*Supplier, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var supplier Supplier
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `SELECT ` + supplierColumns + ` FROM suppliers WHERE id = $1 AND deleted_at IS NULL`
+		return r.db.GetContext(ctx, &supplier, query, id)
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFound("supplier")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &supplier, nil
+}
+
+// GetByName finds an active supplier by name, ignoring case. Items still
+// carry the supplier as free text; this resolves it to a supplier record.
+// Returns nil if there is no such supplier.
+// TENANT-ISOLATED: Queries via RLS
+func (r *SupplierRepository) GetByName(ctx context.Context, name string) (*Supplier, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var supplier Supplier
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			SELECT ` + supplierColumns + ` FROM suppliers
+			WHERE lower(name) = lower($1) AND is_active = TRUE AND deleted_at IS NULL
+		`
+		return r.db.GetContext(ctx, &supplier, query, name)
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &supplier, nil
+}
+
+// supplierListSchema whitelists the filter and sort fields of GET /suppliers
+var supplierListSchema = database.ListSchema{
+	Fields: map[string]database.Field{
+		"name":            {Column: "name", Type: database.FieldText, Filter: true, Sort: true},
+		"customer_number": {Column: "customer_number", Type: database.FieldText, Filter: true, Nullable: true},
+		"lead_time_days":  {Column: "lead_time_days", Type: database.FieldInt, Filter: true, Sort: true},
+		"is_active":       {Column: "is_active", Type: database.FieldBool, Filter: true},
+		"created_at":      {Column: "created_at", Type: database.FieldTime, Filter: true, Sort: true},
+	},
+	DefaultSort: []string{"name"},
+}
+
+// List lists suppliers filtered, sorted and paginated by q
+// TENANT-ISOLATED: Returns only suppliers via RLS
+func (r *SupplierRepository) List(ctx context.Context, q *database.ListQuery) (*database.Page[*Supplier], error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var page *database.Page[*Supplier]
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `SELECT ` + supplierColumns + ` FROM suppliers WHERE deleted_at IS NULL`
+		var err error
+		page, err = database.SelectPage[*Supplier](ctx, r.db, &supplierListSchema, q, query)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// Update updates a supplier
+// TENANT-ISOLATED: Updates via RLS
+func (r *SupplierRepository) Update(ctx context.Context, supplier *Supplier) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			UPDATE suppliers SET
+				name = $2, customer_number = $3, contact_name = $4, email = $5, phone = $6,
+				fax = $7, website = $8, address = $9, lead_time_days = $10,
+				min_order_value_cents = $11, notes = $12, is_active = $13
+			WHERE id = $1 AND deleted_at IS NULL
+			RETURNING created_at, updated_at
+		`
+
+		err := r.db.QueryRowxContext(ctx, query,
+			supplier.ID, supplier.Name, supplier.CustomerNumber, supplier.ContactName,
+			supplier.Email, supplier.Phone, supplier.Fax, supplier.Website, supplier.Address,
+			supplier.LeadTimeDays, supplier.MinOrderValueCents, supplier.Notes, supplier.IsActive,
+		).Scan(&supplier.CreatedAt, &supplier.UpdatedAt)
+		if err == sql.ErrNoRows {
+			return errors.NotFound("supplier")
+		}
+		if appErr := database.MapPQError(err); appErr != nil {
+			return appErr
+		}
+		return err
+	})
+}
+
+// Delete soft-deletes a supplier. Suppliers with open purchase orders
+// cannot be deleted.
+// TENANT-ISOLATED: Updates via RLS
+func (r *SupplierRepository) Delete(ctx context.Context, id string) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		var open int
+		countQuery := `
+			SELECT COUNT(*) FROM purchase_orders
+			WHERE supplier_id = $1 AND deleted_at IS NULL
+			  AND status IN ('draft', 'sent', 'partially_received')
+		`
+		if err := r.db.GetContext(ctx, &open, countQuery, id); err != nil {
+			return err
+		}
+		if open > 0 {
+			return errors.Conflict("errors.supplier.has_open_orders")
+		}
+
+		query := `UPDATE suppliers SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
+		result, err := r.db.ExecContext(ctx, query, id)
+		if err != nil {
+			return err
+		}
+
+		affected, _ := result.RowsAffected()
+		if affected == 0 {
+			return errors.NotFound("supplier")
+		}
+
+		return nil
+	})
+}
+
+// --- Supplier Items ---
+
+// UpsertItem creates or updates the link between a supplier and an item.
+// Marking a link preferred clears the flag on the item's other suppliers.
+// TENANT-ISOLATED: Inserts with tenant_id for RLS
+func (r *SupplierRepository) UpsertItem(ctx context.Context, si *SupplierItem) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	if si.ID == "" {
+		si.ID = uuid.New().String()
+	}
+	if si.PackSize <= 0 {
+		si.PackSize = 1
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		if si.IsPreferred {
+			clearQuery := `UPDATE supplier_items SET is_preferred = FALSE WHERE item_id = $1 AND supplier_id <> $2 AND is_preferred`
+			if _, err := r.db.ExecContext(ctx, clearQuery, si.ItemID, si.SupplierID); err != nil {
+				return err
+			}
+		}
+
+		query := `
+			INSERT INTO supplier_items (
+				id, tenant_id, supplier_id, item_id, supplier_article_number,
+				unit_price_cents, pack_size, is_preferred
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			ON CONFLICT (supplier_id, item_id) DO UPDATE SET
+				supplier_article_number = EXCLUDED.supplier_article_number,
+				unit_price_cents = EXCLUDED.unit_price_cents,
+				pack_size = EXCLUDED.pack_size,
+				is_preferred = EXCLUDED.is_preferred
+			RETURNING id, created_at, updated_at
+		`
+
+		err := r.db.QueryRowxContext(ctx, query,
+			si.ID, tenantID, si.SupplierID, si.ItemID, si.SupplierArticleNumber,
+			si.UnitPriceCents, si.PackSize, si.IsPreferred,
+		).Scan(&si.ID, &si.CreatedAt, &si.UpdatedAt)
+		if appErr := database.MapPQError(err); appErr != nil {
+			return appErr
+		}
+		return err
+	})
+}
+
+// ListItems lists the items a supplier delivers
+// TENANT-ISOLATED: Returns only supplier items via RLS
+func (r *SupplierRepository) ListItems(ctx context.Context, supplierID string) ([]*SupplierItem, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []*SupplierItem
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			SELECT si.id, si.supplier_id, si.item_id, si.supplier_article_number, si.unit_price_cents,
+			       si.pack_size, si.is_preferred, si.created_at, si.updated_at,
+			       i.name AS item_name, s.name AS supplier_name
+			FROM supplier_items si
+			JOIN inventory_items i ON i.id = si.item_id
+			JOIN suppliers s ON s.id = si.supplier_id
+			WHERE si.supplier_id = $1 AND i.deleted_at IS NULL
+			ORDER BY i.name
+		`
+		return r.db.SelectContext(ctx, &items, query, supplierID)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// GetSourcing returns where an item is ordered from: the preferred supplier,
+// otherwise the cheapest active one. Returns nil if no supplier is linked.
+// TENANT-ISOLATED: Queries via RLS
+func (r *SupplierRepository) GetSourcing(ctx context.Context, itemID string) (*SupplierItem, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var si SupplierItem
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			SELECT si.id, si.supplier_id, si.item_id, si.supplier_article_number, si.unit_price_cents,
+			       si.pack_size, si.is_preferred, si.created_at, si.updated_at,
+			       i.name AS item_name, s.name AS supplier_name
+			FROM supplier_items si
+			JOIN inventory_items i ON i.id = si.item_id
+			JOIN suppliers s ON s.id = si.supplier_id
+			WHERE si.item_id = $1 AND s.is_active = TRUE AND s.deleted_at IS NULL
+			ORDER BY si.is_preferred DESC, si.unit_price_cents ASC NULLS LAST
+			LIMIT 1
+		`
+		return r.db.GetContext(ctx, &si, query, itemID)
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &si, nil
+}
+
+// DeleteItem removes the link between a supplier and an item
+// TENANT-ISOLATED: Deletes via RLS
+func (r *SupplierRepository) DeleteItem(ctx context.Context, supplierID, itemID string) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `DELETE FROM supplier_items WHERE supplier_id = $1 AND item_id = $2`
+		result, err := r.db.ExecContext(ctx, query, supplierID, itemID)
+		if err != nil {
+			return err
+		}
+
+		affected, _ := result.RowsAffected()
+		if affected == 0 {
+			return errors.NotFound("supplier_item")
+		}
+
+		return nil
+	})
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,10 +17,14 @@ type TemperatureReading struct {
 	TemperatureCelsius float64   `db:"temperature_celsius" json:"temperature_celsius"`
 	RecordedAt         time.Time `db:"recorded_at" json:"recorded_at"`
 	RecordedBy         *string   `db:"recorded_by" json:"recorded_by,omitempty"`
+	SensorID           *string   `db:"sensor_id" json:"sensor_id,omitempty"`
 	Source             string    `db:"source" json:"source"`
 	IsExcursion        bool      `db:"is_excursion" json:"is_excursion"`
 	Notes              *string   `db:"notes" json:"notes,omitempty"`
 	CreatedAt          time.Time `db:"created_at" json:"created_at"`
+
+	// Joined from the user cache (ListManualBetween)
+	RecordedByName *string `db:"recorded_by_name" json:"recorded_by_name,omitempty"`
 }
 
 // TemperatureRepository handles temperature reading persistence
@@ -63,6 +68,127 @@ func (r *TemperatureRepository) Create(ctx context.Context, reading *Temperature
 	})
 }
 
+// CreateBatch inserts readings and returns those that were new. A reading
+// a sensor (or a webhook or MQTT logger without registered sensor) already
+// sent with the same recorded_at is skipped, so loggers can safely re-send their buffer.
+// TENANT-ISOLATED: Inserts with tenant_id for RLS
+func (r *TemperatureRepository) CreateBatch(ctx context.Context, readings []*TemperatureReading) ([]*TemperatureReading, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var created []*TemperatureReading
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			INSERT INTO temperature_readings (
+				id, tenant_id, cabinet_id, temperature_celsius, recorded_at, recorded_by,
+				sensor_id, source, is_excursion, notes
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			ON CONFLICT DO NOTHING
+			RETURNING created_at
+		`
+		for _, reading := range readings {
+			if reading.ID == "" {
+				reading.ID = uuid.New().String()
+			}
+			err := r.db.QueryRowxContext(ctx, query,
+				reading.ID, tenantID, reading.CabinetID, reading.TemperatureCelsius,
+				reading.RecordedAt, reading.RecordedBy, reading.SensorID, reading.Source,
+				reading.IsExcursion, reading.Notes,
+			).Scan(&reading.CreatedAt)
+			if err == sql.ErrNoRows {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			created = append(created, reading)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// LatestRecordedAt returns when the newest reading of a cabinet was
+// recorded, or nil if it has none
+// TENANT-ISOLATED: Queries via RLS
+func (r *TemperatureRepository) LatestRecordedAt(ctx context.Context, cabinetID string) (*time.Time, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *time.Time
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		return r.db.GetContext(ctx, &latest, `SELECT MAX(recorded_at) FROM temperature_readings WHERE cabinet_id = $1`, cabinetID)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return latest, nil
+}
+
+// ListBetween lists the readings of a cabinet recorded in [from, to),
+// oldest first
+// TENANT-ISOLATED: Returns only readings via RLS
+func (r *TemperatureRepository) ListBetween(ctx context.Context, cabinetID string, from, to time.Time) ([]*TemperatureReading, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var readings []*TemperatureReading
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			SELECT id, cabinet_id, temperature_celsius, recorded_at, recorded_by, sensor_id, source, is_excursion, notes, created_at
+			FROM temperature_readings
+			WHERE cabinet_id = $1 AND recorded_at >= $2 AND recorded_at < $3
+			ORDER BY recorded_at
+		`
+		return r.db.SelectContext(ctx, &readings, query, cabinetID, from, to)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return readings, nil
+}
+
+// ListManualBetween lists the readings of a cabinet taken by staff (the
+// manual checks) in [from, to), oldest first, with the name of who took them
+// TENANT-ISOLATED: Returns only readings via RLS
+func (r *TemperatureRepository) ListManualBetween(ctx context.Context, cabinetID string, from, to time.Time) ([]*TemperatureReading, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var readings []*TemperatureReading
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			SELECT tr.id, tr.cabinet_id, tr.temperature_celsius, tr.recorded_at, tr.recorded_by, tr.sensor_id,
+			       tr.source, tr.is_excursion, tr.notes, tr.created_at,
+			       NULLIF(TRIM(CONCAT(u.first_name, ' ', u.last_name)), '') AS recorded_by_name
+			FROM temperature_readings tr
+			LEFT JOIN user_cache u ON u.user_id = tr.recorded_by
+			WHERE tr.cabinet_id = $1 AND tr.source = 'manual' AND tr.recorded_at >= $2 AND tr.recorded_at < $3
+			ORDER BY tr.recorded_at
+		`
+		return r.db.SelectContext(ctx, &readings, query, cabinetID, from, to)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return readings, nil
+}
+
 // ListByCabinet lists temperature readings for a cabinet with pagination
 // TENANT-ISOLATED: Returns only readings via RLS
 func (r *TemperatureRepository) ListByCabinet(ctx context.Context, cabinetID string, from, to *time.Time, page, perPage int) ([]*TemperatureReading, int64, error) {
@@ -79,7 +205,7 @@ func (r *TemperatureRepository) ListByCabinet(ctx context.Context, cabinetID str
 		argIdx := 2
 
 		countQuery := `SELECT COUNT(*) FROM temperature_readings WHERE cabinet_id = $1`
-		query := `SELECT id, cabinet_id, temperature_celsius, recorded_at, recorded_by, source, is_excursion, notes, created_at
+		query := `SELECT id, cabinet_id, temperature_celsius, recorded_at, recorded_by, sensor_id, source, is_excursion, notes, created_at
 			FROM temperature_readings WHERE cabinet_id = $1`
 
 		if from != nil {
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/tenant"
+)
+
+// StockTransfer moves a quantity of a batch to another room, cabinet or shelf.
+// Both bookings of a transfer carry its ID as reference_id.
+type StockTransfer struct {
+	ID              string  `json:"id"`
+	BatchID         string  `json:"batch_id"`
+	ToLocationID    string  `json:"to_location_id"`
+	Quantity        int     `json:"quantity"`
+	Reason          *string `json:"reason,omitempty"`
+	PerformedBy     string  `json:"performed_by"`
+	PerformedByName *string `json:"performed_by_name,omitempty"`
+
+	// Set by Transfer
+	ItemID         string           `json:"item_id"`
+	FromLocationID *string          `json:"from_location_id,omitempty"`
+	TargetBatchID  string           `json:"target_batch_id"`
+	Split          bool             `json:"split"` // a new batch was created at the destination
+	Out            *StockAdjustment `json:"out"`
+	In             *StockAdjustment `json:"in"`
+	CreatedAt      time.Time        `json:"created_at"`
+}
+
+// stockTransferReference is the reference_type of transfer bookings
+const stockTransferReference = "stock_transfer"
+
+// Transfer atomically moves stock between locations.
+// The whole batch is relocated when all of it moves; a partial move splits
+// off a new batch at the destination, or adds to a batch with the same batch
+// number and expiry that is already stored there.
+// TENANT-ISOLATED: Updates and inserts via RLS
+func (r *BatchRepository) Transfer(ctx context.Context, t *StockTransfer) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		var source InventoryBatch
+		lockQuery := `
+			SELECT id, item_id, location_id, batch_number, lot_number, initial_quantity,
+			       current_quantity, reserved_quantity, manufactured_date, expiry_date,
+			       received_date, opened_at, status, created_at, updated_at
+			FROM inventory_batches WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+		`
+		if err := r.db.GetContext(ctx, &source, lockQuery, t.BatchID); err != nil {
+			if err == sql.ErrNoRows {
+				return errors.NotFound("batch")
+			}
+			return err
+		}
+
+		if source.LocationID != nil && *source.LocationID == t.ToLocationID {
+			return errors.BadRequest("errors.transfer.same_location")
+		}
+		if available := source.CurrentQuantity - source.ReservedQuantity; t.Quantity > available {
+			return errors.BadRequest("errors.transfer.insufficient_quantity", map[string]string{
+				"quantity":  strconv.Itoa(t.Quantity),
+				"available": strconv.Itoa(available),
+			})
+		}
+
+		t.ItemID = source.ItemID
+		t.FromLocationID = source.LocationID
+
+		// Merge into a batch of the same lot that is already at the destination
+		var target InventoryBatch
+		targetQuery := `
+			SELECT id, current_quantity FROM inventory_batches
+			WHERE item_id = $1 AND batch_number = $2 AND location_id = $3 AND id <> $4
+			  AND expiry_date IS NOT DISTINCT FROM $5 AND status = $6 AND deleted_at IS NULL
+			ORDER BY created_at LIMIT 1 FOR UPDATE
+		`
+		err := r.db.GetContext(ctx, &target, targetQuery,
+			source.ItemID, source.BatchNumber, t.ToLocationID, source.ID, source.ExpiryDate, source.Status)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		merge := err == nil
+		relocate := !merge && t.Quantity == source.CurrentQuantity
+
+		outNew := source.CurrentQuantity - t.Quantity
+		inPrevious := 0
+		switch {
+		case relocate:
+			// The batch itself moves; the bookings record it leaving one
+			// location and arriving at the other
+			if _, err := r.db.ExecContext(ctx, `UPDATE inventory_batches SET location_id = $2 WHERE id = $1`, source.ID, t.ToLocationID); err != nil {
+				return err
+			}
+			t.TargetBatchID = source.ID
+		case merge:
+			if _, err := r.db.ExecContext(ctx, `UPDATE inventory_batches SET current_quantity = current_quantity + $2 WHERE id = $1`, target.ID, t.Quantity); err != nil {
+				return err
+			}
+			inPrevious = target.CurrentQuantity
+			t.TargetBatchID = target.ID
+		default:
+			split := &InventoryBatch{
+				ItemID:           source.ItemID,
+				LocationID:       &t.ToLocationID,
+				BatchNumber:      source.BatchNumber,
+				LotNumber:        source.LotNumber,
+				InitialQuantity:  t.Quantity,
+				CurrentQuantity:  t.Quantity,
+				ManufacturedDate: source.ManufacturedDate,
+				ExpiryDate:       source.ExpiryDate,
+				ReceivedDate:     source.ReceivedDate,
+				OpenedAt:         source.OpenedAt,
+				Status:           source.Status,
+			}
+			if err := insertBatch(ctx, r.db, tenantID, split); err != nil {
+				return err
+			}
+			t.TargetBatchID = split.ID
+			t.Split = true
+		}
+
+		if !relocate {
+			if _, err := r.db.ExecContext(ctx, `UPDATE inventory_batches SET current_quantity = $2 WHERE id = $1`, source.ID, outNew); err != nil {
+				return err
+			}
+		}
+
+		referenceType := stockTransferReference
+		t.Out = &StockAdjustment{
+			ItemID:           source.ItemID,
+			BatchID:          &source.ID,
+			AdjustmentType:   "transfer",
+			Quantity:         t.Quantity,
+			PreviousQuantity: source.CurrentQuantity,
+			NewQuantity:      outNew,
+			FromLocationID:   source.LocationID,
+			ToLocationID:     &t.ToLocationID,
+			Reason:           t.Reason,
+			ReferenceType:    &referenceType,
+			ReferenceID:      &t.ID,
+			PerformedBy:      t.PerformedBy,
+			PerformedByName:  t.PerformedByName,
+		}
+		t.In = &StockAdjustment{
+			ItemID:           source.ItemID,
+			BatchID:          &t.TargetBatchID,
+			AdjustmentType:   "transfer",
+			Quantity:         t.Quantity,
+			PreviousQuantity: inPrevious,
+			NewQuantity:      inPrevious + t.Quantity,
+			FromLocationID:   source.LocationID,
+			ToLocationID:     &t.ToLocationID,
+			Reason:           t.Reason,
+			ReferenceType:    &referenceType,
+			ReferenceID:      &t.ID,
+			PerformedBy:      t.PerformedBy,
+			PerformedByName:  t.PerformedByName,
+		}
+		if err := insertStockAdjustment(ctx, r.db, tenantID, t.Out); err != nil {
+			return err
+		}
+		if err := insertStockAdjustment(ctx, r.db, tenantID, t.In); err != nil {
+			return err
+		}
+		t.CreatedAt = t.In.CreatedAt
+		return nil
+	})
+}
@@ -0,0 +1,122 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Stock Transfer Tests ---
+
+func TestBatchRepository_Transfer_SplitAndMerge(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tenant := suite.SetupInventoryTenant(t, ctx, "transfer-split")
+	tenantCtx := suite.TenantContext(tenant)
+
+	itemRepo := repository.NewItemRepository(suite.DB)
+	batchRepo := repository.NewBatchRepository(suite.DB)
+	item := createTestItem(t, tenantCtx, itemRepo, "Kompressen")
+	_, ambient, fridge := createTestLocations(t, tenantCtx)
+	source := createTestBatch(t, tenantCtx, item.ID, ambient.ID, 10)
+
+	// Partial move splits off a new batch at the destination
+	first := &repository.StockTransfer{BatchID: source.ID, ToLocationID: fridge.ID, Quantity: 4, PerformedBy: "user-1"}
+	require.NoError(t, batchRepo.Transfer(tenantCtx, first))
+	assert.True(t, first.Split)
+	assert.NotEqual(t, source.ID, first.TargetBatchID)
+	require.NotNil(t, first.Out)
+	require.NotNil(t, first.In)
+	assert.Equal(t, first.ID, *first.Out.ReferenceID)
+	assert.Equal(t, first.ID, *first.In.ReferenceID)
+	assert.Equal(t, 10, first.Out.PreviousQuantity)
+	assert.Equal(t, 6, first.Out.NewQuantity)
+	assert.Equal(t, 4, first.In.NewQuantity)
+
+	split, err := batchRepo.GetByID(tenantCtx, first.TargetBatchID)
+	require.NoError(t, err)
+	assert.Equal(t, fridge.ID, *split.LocationID)
+	assert.Equal(t, source.BatchNumber, split.BatchNumber)
+	assert.Equal(t, 4, split.CurrentQuantity)
+
+	// A second partial move adds to the split batch instead of creating another
+	second := &repository.StockTransfer{BatchID: source.ID, ToLocationID: fridge.ID, Quantity: 2, PerformedBy: "user-1"}
+	require.NoError(t, batchRepo.Transfer(tenantCtx, second))
+	assert.False(t, second.Split)
+	assert.Equal(t, split.ID, second.TargetBatchID)
+	assert.Equal(t, 4, second.In.PreviousQuantity)
+	assert.Equal(t, 6, second.In.NewQuantity)
+
+	// Total stock is unchanged
+	stock, err := batchRepo.GetTotalStock(tenantCtx, item.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 10, stock)
+
+	// Moving more than is left is rejected
+	err = batchRepo.Transfer(tenantCtx, &repository.StockTransfer{BatchID: source.ID, ToLocationID: fridge.ID, Quantity: 5})
+	assert.Error(t, err)
+}
+
+func TestBatchRepository_Transfer_WholeBatch(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tenant := suite.SetupInventoryTenant(t, ctx, "transfer-whole")
+	tenantCtx := suite.TenantContext(tenant)
+
+	itemRepo := repository.NewItemRepository(suite.DB)
+	batchRepo := repository.NewBatchRepository(suite.DB)
+	item := createTestItem(t, tenantCtx, itemRepo, "Spritzen")
+	room, ambient, _ := createTestLocations(t, tenantCtx)
+	source := createTestBatch(t, tenantCtx, item.ID, ambient.ID, 8)
+
+	transfer := &repository.StockTransfer{BatchID: source.ID, ToLocationID: room.ID, Quantity: 8}
+	require.NoError(t, batchRepo.Transfer(tenantCtx, transfer))
+	assert.False(t, transfer.Split)
+	assert.Equal(t, source.ID, transfer.TargetBatchID)
+
+	moved, err := batchRepo.GetByID(tenantCtx, source.ID)
+	require.NoError(t, err)
+	assert.Equal(t, room.ID, *moved.LocationID)
+	assert.Equal(t, 8, moved.CurrentQuantity)
+
+	// Same location is rejected
+	err = batchRepo.Transfer(tenantCtx, &repository.StockTransfer{BatchID: source.ID, ToLocationID: room.ID, Quantity: 1})
+	assert.Error(t, err)
+}
+
+func TestLocationRepository_ResolveLocation(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tenant := suite.SetupInventoryTenant(t, ctx, "resolve-location")
+	tenantCtx := suite.TenantContext(tenant)
+
+	locRepo := repository.NewLocationRepository(suite.DB)
+	room, _, fridge := createTestLocations(t, tenantCtx)
+	shelf := &repository.StorageShelf{CabinetID: fridge.ID, Name: "Fach 1", Position: 1}
+	require.NoError(t, locRepo.CreateShelf(tenantCtx, shelf))
+
+	loc, err := locRepo.ResolveLocation(tenantCtx, shelf.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "shelf", loc.Kind)
+	require.NotNil(t, loc.CabinetID)
+	assert.Equal(t, fridge.ID, *loc.CabinetID)
+	assert.True(t, loc.TemperatureControlled)
+
+	loc, err = locRepo.ResolveLocation(tenantCtx, room.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "room", loc.Kind)
+	assert.Nil(t, loc.CabinetID)
+	assert.False(t, loc.TemperatureControlled)
+
+	_, err = locRepo.ResolveLocation(tenantCtx, "00000000-0000-0000-0000-000000000000")
+	assert.Error(t, err)
+}
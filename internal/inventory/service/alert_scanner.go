@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/medflow/medflow-backend/internal/inventory/events"
 	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/pkg/database"
 	"github.com/medflow/medflow-backend/pkg/logger"
 )
 
@@ -17,6 +19,7 @@ type AlertScanner struct {
 	alertRepo    *repository.AlertRepository
 	tempRepo     *repository.TemperatureRepository
 	locationRepo *repository.LocationRepository
+	publisher    *events.InventoryEventPublisher
 	logger       *logger.Logger
 }
 
@@ -39,7 +42,27 @@ func NewAlertScanner(
 	}
 }
 
+// SetPublisher sets the publisher that announces created alerts on the
+// inventory.events exchange, so they reach the notification routing of the
+// user service. Without one alerts only show on the dashboard.
+func (s *AlertScanner) SetPublisher(publisher *events.InventoryEventPublisher) {
+	s.publisher = publisher
+}
+
+// createAlert persists an alert and publishes it
+func (s *AlertScanner) createAlert(ctx context.Context, alert *repository.InventoryAlert) error {
+	if err := s.alertRepo.Create(ctx, alert); err != nil {
+		return err
+	}
+	s.publisher.PublishAlertGenerated(ctx, alert)
+	return nil
+}
+
 // ScanAll runs all alert scans. Logs errors but continues scanning.
+//
+// Detection queries are read-only work and run on a read replica when one is
+// configured. Dedup checks, alert creation and resolution stay on the primary
+// so replication lag cannot produce duplicate alerts.
 func (s *AlertScanner) ScanAll(ctx context.Context) error {
 	scanners := []struct {
 		name string
@@ -66,13 +89,15 @@ func (s *AlertScanner) ScanAll(ctx context.Context) error {
 
 // scanLowStock checks for low stock and out of stock conditions
 func (s *AlertScanner) scanLowStock(ctx context.Context) error {
-	items, err := s.itemRepo.GetAllActive(ctx)
+	readCtx := database.ReadOnly(ctx)
+
+	items, err := s.itemRepo.GetAllActive(readCtx)
 	if err != nil {
 		return fmt.Errorf("scanLowStock: get active items: %w", err)
 	}
 
 	for _, item := range items {
-		totalStock, err := s.batchRepo.GetTotalStock(ctx, item.ID)
+		totalStock, err := s.batchRepo.GetTotalStock(readCtx, item.ID)
 		if err != nil {
 			s.logger.Error().Err(err).Str("item_id", item.ID).Msg("scanLowStock: failed to get total stock")
 			continue
@@ -111,7 +136,7 @@ func (s *AlertScanner) scanLowStock(ctx context.Context) error {
 			MinStock:     &item.MinStock,
 		}
 
-		if err := s.alertRepo.Create(ctx, alert); err != nil {
+		if err := s.createAlert(ctx, alert); err != nil {
 			s.logger.Error().Err(err).Str("item_id", item.ID).Msg("scanLowStock: failed to create alert")
 		}
 	}
@@ -121,13 +146,15 @@ func (s *AlertScanner) scanLowStock(ctx context.Context) error {
 
 // scanExpiryAlerts checks for expiring and expired batches
 func (s *AlertScanner) scanExpiryAlerts(ctx context.Context) error {
-	batches, err := s.batchRepo.GetExpiringBatches(ctx, 90)
+	readCtx := database.ReadOnly(ctx)
+
+	batches, err := s.batchRepo.GetExpiringBatches(readCtx, 90)
 	if err != nil {
 		return fmt.Errorf("scanExpiryAlerts: get expiring batches: %w", err)
 	}
 
 	for _, batch := range batches {
-		item, err := s.itemRepo.GetByID(ctx, batch.ItemID)
+		item, err := s.itemRepo.GetByID(readCtx, batch.ItemID)
 		if err != nil {
 			continue
 		}
@@ -172,7 +199,7 @@ func (s *AlertScanner) scanExpiryAlerts(ctx context.Context) error {
 			DaysUntilExpiry: &daysUntil,
 		}
 
-		if err := s.alertRepo.Create(ctx, alert); err != nil {
+		if err := s.createAlert(ctx, alert); err != nil {
 			s.logger.Error().Err(err).Str("item_id", item.ID).Msg("scanExpiryAlerts: failed to create alert")
 		}
 	}
@@ -182,7 +209,7 @@ func (s *AlertScanner) scanExpiryAlerts(ctx context.Context) error {
 
 // scanMaintenanceAlerts checks for overdue/upcoming STK/MTK inspections
 func (s *AlertScanner) scanMaintenanceAlerts(ctx context.Context) error {
-	items, err := s.itemRepo.ListMedicalDevices(ctx)
+	items, err := s.itemRepo.ListMedicalDevices(database.ReadOnly(ctx))
 	if err != nil {
 		return fmt.Errorf("scanMaintenanceAlerts: list medical devices: %w", err)
 	}
@@ -236,7 +263,7 @@ func (s *AlertScanner) createMaintenanceAlert(ctx context.Context, item *reposit
 		Message:   message,
 	}
 
-	if err := s.alertRepo.Create(ctx, alert); err != nil {
+	if err := s.createAlert(ctx, alert); err != nil {
 		s.logger.Error().Err(err).Str("item_id", item.ID).Str("alert_type", alertType).Msg("failed to create maintenance alert")
 	}
 }
@@ -246,7 +273,7 @@ func (s *AlertScanner) scanTemperatureMissing(ctx context.Context) error {
 	now := time.Now()
 	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 
-	cabinetIDs, err := s.tempRepo.GetMonitoredCabinetsWithoutReading(ctx, startOfDay)
+	cabinetIDs, err := s.tempRepo.GetMonitoredCabinetsWithoutReading(database.ReadOnly(ctx), startOfDay)
 	if err != nil {
 		return fmt.Errorf("scanTemperatureMissing: get cabinets: %w", err)
 	}
@@ -275,7 +302,7 @@ func (s *AlertScanner) scanTemperatureMissing(ctx context.Context) error {
 			Message:   fmt.Sprintf("No temperature reading today for %s", cabinet.Name),
 		}
 
-		if err := s.alertRepo.Create(ctx, alert); err != nil {
+		if err := s.createAlert(ctx, alert); err != nil {
 			s.logger.Error().Err(err).Str("cabinet_id", cabID).Msg("scanTemperatureMissing: failed to create alert")
 		}
 	}
@@ -285,7 +312,9 @@ func (s *AlertScanner) scanTemperatureMissing(ctx context.Context) error {
 
 // scanOpeningExpired checks for opened batches past their post-opening shelf life
 func (s *AlertScanner) scanOpeningExpired(ctx context.Context) error {
-	items, err := s.itemRepo.GetAllActive(ctx)
+	readCtx := database.ReadOnly(ctx)
+
+	items, err := s.itemRepo.GetAllActive(readCtx)
 	if err != nil {
 		return fmt.Errorf("scanOpeningExpired: get active items: %w", err)
 	}
@@ -297,7 +326,7 @@ func (s *AlertScanner) scanOpeningExpired(ctx context.Context) error {
 			continue
 		}
 
-		batches, err := s.batchRepo.ListByItem(ctx, item.ID)
+		batches, err := s.batchRepo.ListByItem(readCtx, item.ID)
 		if err != nil {
 			continue
 		}
@@ -332,7 +361,7 @@ func (s *AlertScanner) scanOpeningExpired(ctx context.Context) error {
 				Message:     fmt.Sprintf("%s batch %s: post-opening shelf life expired", item.Name, batch.BatchNumber),
 			}
 
-			if err := s.alertRepo.Create(ctx, alert); err != nil {
+			if err := s.createAlert(ctx, alert); err != nil {
 				s.logger.Error().Err(err).Str("item_id", item.ID).Str("batch_id", batch.ID).Msg("scanOpeningExpired: failed to create alert")
 			}
 		}
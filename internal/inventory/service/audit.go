@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/pkg/auditchain"
 	"github.com/medflow/medflow-backend/pkg/httputil"
 	"github.com/medflow/medflow-backend/pkg/logger"
 )
@@ -60,6 +61,26 @@ func (s *AuditService) ExportGoBD(ctx context.Context, from, to *time.Time) ([]*
 	return s.repo.ExportGoBD(ctx, from, to)
 }
 
+// Verify checks the tenant's audit trail hash chain for changed, deleted or
+// reordered entries
+func (s *AuditService) Verify(ctx context.Context) (*auditchain.Result, error) {
+	result, err := s.repo.Verify(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !result.Valid {
+		s.logger.Warn().
+			Str("chain", result.Chain).
+			Int64("sequence", result.Break.Sequence).
+			Str("entry_id", result.Break.EntryID).
+			Str("reason", result.Break.Reason).
+			Msg("audit trail hash chain broken")
+	}
+
+	return result, nil
+}
+
 // record is the internal helper that constructs an AuditEntry and persists it
 func (s *AuditService) record(ctx context.Context, entityType, entityID, action string, fieldChanges, metadata map[string]interface{}) error {
 	entry := &repository.AuditEntry{
@@ -4,22 +4,26 @@ import (
 	"context"
 
 	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/pkg/database"
 	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/medflow/medflow-backend/pkg/retention"
 )
 
 // BioSafetyService handles biological safety business logic (BioStoffV compliance)
 type BioSafetyService struct {
-	bioRepo      *repository.BioSafetyRepository
-	auditService *AuditService
-	logger       *logger.Logger
+	bioRepo        *repository.BioSafetyRepository
+	retentionGuard *retention.Guard
+	auditService   *AuditService
+	logger         *logger.Logger
 }
 
 // NewBioSafetyService creates a new biosafety service
-func NewBioSafetyService(bioRepo *repository.BioSafetyRepository, auditService *AuditService, log *logger.Logger) *BioSafetyService {
+func NewBioSafetyService(bioRepo *repository.BioSafetyRepository, retentionGuard *retention.Guard, auditService *AuditService, log *logger.Logger) *BioSafetyService {
 	return &BioSafetyService{
-		bioRepo:      bioRepo,
-		auditService: auditService,
-		logger:       log,
+		bioRepo:        bioRepo,
+		retentionGuard: retentionGuard,
+		auditService:   auditService,
+		logger:         log,
 	}
 }
 
@@ -45,8 +49,8 @@ func (s *BioSafetyService) GetAssessment(ctx context.Context, id string) (*repos
 }
 
 // ListAssessmentsByItem lists risk assessments for an item
-func (s *BioSafetyService) ListAssessmentsByItem(ctx context.Context, itemID string) ([]*repository.BioRiskAssessment, error) {
-	return s.bioRepo.ListAssessmentsByItem(ctx, itemID)
+func (s *BioSafetyService) ListAssessmentsByItem(ctx context.Context, itemID string, q *database.ListQuery) (*database.Page[*repository.BioRiskAssessment], error) {
+	return s.bioRepo.ListAssessmentsByItem(ctx, itemID, q)
 }
 
 // UpdateAssessment updates a risk assessment
@@ -65,6 +69,9 @@ func (s *BioSafetyService) UpdateAssessment(ctx context.Context, assessment *rep
 
 // DeleteAssessment soft-deletes a risk assessment
 func (s *BioSafetyService) DeleteAssessment(ctx context.Context, id string) error {
+	if err := s.retentionGuard.Check(ctx, id, RetentionBioRiskAssessment); err != nil {
+		return err
+	}
 	if err := s.bioRepo.DeleteAssessment(ctx, id); err != nil {
 		return err
 	}
@@ -116,6 +123,9 @@ func (s *BioSafetyService) UpdateTraining(ctx context.Context, training *reposit
 
 // DeleteTraining soft-deletes a bio training record
 func (s *BioSafetyService) DeleteTraining(ctx context.Context, id string) error {
+	if err := s.retentionGuard.Check(ctx, id, RetentionBioTraining); err != nil {
+		return err
+	}
 	if err := s.bioRepo.DeleteTraining(ctx, id); err != nil {
 		return err
 	}
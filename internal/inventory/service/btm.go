@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/medflow/medflow-backend/internal/inventory/repository"
 	"github.com/medflow/medflow-backend/pkg/errors"
@@ -17,6 +18,7 @@ type BtmReceiveRequest struct {
 	Unit               string  `json:"unit"`
 	SupplierName       string  `json:"supplier_name"`
 	DeliveryNoteNumber string  `json:"delivery_note_number"`
+	EntryDate          string  `json:"entry_date,omitempty"` // YYYY-MM-DD, defaults to today
 	Notes              string  `json:"notes,omitempty"`
 }
 
@@ -28,6 +30,7 @@ type BtmDispenseRequest struct {
 	PatientIdentifier string  `json:"patient_identifier"`
 	PrescribingDoctor string  `json:"prescribing_doctor"`
 	Purpose           string  `json:"purpose,omitempty"`
+	EntryDate         string  `json:"entry_date,omitempty"`
 	Notes             string  `json:"notes,omitempty"`
 }
 
@@ -38,6 +41,7 @@ type BtmDisposeRequest struct {
 	Unit            string  `json:"unit"`
 	DisposalMethod  string  `json:"disposal_method"`
 	DisposalWitness string  `json:"disposal_witness"`
+	EntryDate       string  `json:"entry_date,omitempty"`
 	Notes           string  `json:"notes,omitempty"`
 }
 
@@ -48,15 +52,17 @@ type BtmCorrectionRequest struct {
 	Unit             string  `json:"unit"`
 	CorrectionReason string  `json:"correction_reason"`
 	CorrectsEntryID  string  `json:"corrects_entry_id"`
+	EntryDate        string  `json:"entry_date,omitempty"`
 	Notes            string  `json:"notes,omitempty"`
 }
 
 // BtmCheckRequest represents a request for an inventory check of a controlled substance
 type BtmCheckRequest struct {
-	ItemID   string  `json:"item_id"`
-	Quantity float64 `json:"quantity"`
-	Unit     string  `json:"unit"`
-	Notes    string  `json:"notes,omitempty"`
+	ItemID    string  `json:"item_id"`
+	Quantity  float64 `json:"quantity"`
+	Unit      string  `json:"unit"`
+	EntryDate string  `json:"entry_date,omitempty"`
+	Notes     string  `json:"notes,omitempty"`
 }
 
 // BtmService handles BtM (Betaeubungsmittel) controlled substance business logic.
@@ -95,6 +101,11 @@ func (s *BtmService) ReceiveSubstance(ctx context.Context, req *BtmReceiveReques
 		return nil, err
 	}
 
+	entryDate, err := parseEntryDate(req.EntryDate)
+	if err != nil {
+		return nil, err
+	}
+
 	// Validate item exists
 	if _, err := s.itemRepo.GetByID(ctx, req.ItemID); err != nil {
 		return nil, fmt.Errorf("item not found: %w", err)
@@ -105,6 +116,7 @@ func (s *BtmService) ReceiveSubstance(ctx context.Context, req *BtmReceiveReques
 		EntryType: "receipt",
 		Quantity:  req.Quantity,
 		Unit:      req.Unit,
+		EntryDate:       entryDate,
 		PerformedBy:     userID,
 		PerformedByName: userID, // In production, resolve from user service
 	}
@@ -145,6 +157,11 @@ func (s *BtmService) DispenseSubstance(ctx context.Context, req *BtmDispenseRequ
 		return nil, err
 	}
 
+	entryDate, err := parseEntryDate(req.EntryDate)
+	if err != nil {
+		return nil, err
+	}
+
 	// Validate item exists
 	if _, err := s.itemRepo.GetByID(ctx, req.ItemID); err != nil {
 		return nil, fmt.Errorf("item not found: %w", err)
@@ -156,7 +173,10 @@ func (s *BtmService) DispenseSubstance(ctx context.Context, req *BtmDispenseRequ
 		return nil, fmt.Errorf("failed to get running balance: %w", err)
 	}
 	if balance < req.Quantity {
-		return nil, errors.BadRequest(fmt.Sprintf("insufficient BtM balance: %.2f available, %.2f requested", balance, req.Quantity))
+		return nil, errors.BadRequest("errors.btm.insufficient_balance", map[string]string{
+			"available": fmt.Sprintf("%.2f", balance),
+			"requested": fmt.Sprintf("%.2f", req.Quantity),
+		})
 	}
 
 	entry := &repository.BtmEntry{
@@ -164,6 +184,7 @@ func (s *BtmService) DispenseSubstance(ctx context.Context, req *BtmDispenseRequ
 		EntryType:       "dispense",
 		Quantity:        req.Quantity,
 		Unit:            req.Unit,
+		EntryDate:       entryDate,
 		PerformedBy:     userID,
 		PerformedByName: userID,
 	}
@@ -207,6 +228,11 @@ func (s *BtmService) DisposeSubstance(ctx context.Context, req *BtmDisposeReques
 		return nil, err
 	}
 
+	entryDate, err := parseEntryDate(req.EntryDate)
+	if err != nil {
+		return nil, err
+	}
+
 	// Validate item exists
 	if _, err := s.itemRepo.GetByID(ctx, req.ItemID); err != nil {
 		return nil, fmt.Errorf("item not found: %w", err)
@@ -218,12 +244,15 @@ func (s *BtmService) DisposeSubstance(ctx context.Context, req *BtmDisposeReques
 		return nil, fmt.Errorf("failed to get running balance: %w", err)
 	}
 	if balance < req.Quantity {
-		return nil, errors.BadRequest(fmt.Sprintf("insufficient BtM balance: %.2f available, %.2f requested", balance, req.Quantity))
+		return nil, errors.BadRequest("errors.btm.insufficient_balance", map[string]string{
+			"available": fmt.Sprintf("%.2f", balance),
+			"requested": fmt.Sprintf("%.2f", req.Quantity),
+		})
 	}
 
 	// Disposal requires a witness
 	if req.DisposalWitness == "" {
-		return nil, errors.BadRequest("disposal witness is required for BtM disposal")
+		return nil, errors.BadRequest("errors.btm.witness_required")
 	}
 
 	entry := &repository.BtmEntry{
@@ -231,6 +260,7 @@ func (s *BtmService) DisposeSubstance(ctx context.Context, req *BtmDisposeReques
 		EntryType:       "disposal",
 		Quantity:        req.Quantity,
 		Unit:            req.Unit,
+		EntryDate:       entryDate,
 		PerformedBy:     userID,
 		PerformedByName: userID,
 	}
@@ -269,12 +299,17 @@ func (s *BtmService) CorrectEntry(ctx context.Context, req *BtmCorrectionRequest
 		return nil, err
 	}
 
+	entryDate, err := parseEntryDate(req.EntryDate)
+	if err != nil {
+		return nil, err
+	}
+
 	// Validate correction reason
 	if req.CorrectionReason == "" {
-		return nil, errors.BadRequest("correction reason is required")
+		return nil, errors.BadRequest("errors.correction_reason_required")
 	}
 	if req.CorrectsEntryID == "" {
-		return nil, errors.BadRequest("corrects_entry_id is required")
+		return nil, errors.BadRequest("validation.required", map[string]string{"field": "corrects_entry_id"})
 	}
 
 	entry := &repository.BtmEntry{
@@ -282,6 +317,7 @@ func (s *BtmService) CorrectEntry(ctx context.Context, req *BtmCorrectionRequest
 		EntryType:       "correction",
 		Quantity:        req.Quantity,
 		Unit:            req.Unit,
+		EntryDate:       entryDate,
 		PerformedBy:     userID,
 		PerformedByName: userID,
 	}
@@ -318,11 +354,17 @@ func (s *BtmService) InventoryCheck(ctx context.Context, req *BtmCheckRequest) (
 		return nil, err
 	}
 
+	entryDate, err := parseEntryDate(req.EntryDate)
+	if err != nil {
+		return nil, err
+	}
+
 	entry := &repository.BtmEntry{
 		ItemID:          req.ItemID,
 		EntryType:       "inventory_check",
 		Quantity:        req.Quantity,
 		Unit:            req.Unit,
+		EntryDate:       entryDate,
 		PerformedBy:     userID,
 		PerformedByName: userID,
 	}
@@ -378,10 +420,28 @@ func (s *BtmService) RevokeAuthorization(ctx context.Context, id, revokedBy, rev
 	return s.btmAuthRepo.Revoke(ctx, id, revokedBy, revokedByName)
 }
 
+// parseEntryDate parses the date of a register entry; an empty date is today.
+// Entries may be back-dated into an open period but not dated in the future.
+func parseEntryDate(value string) (time.Time, error) {
+	today := btmToday()
+	if value == "" {
+		return today, nil
+	}
+
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, errors.BadRequest("errors.invalid_date", map[string]string{"field": "entry_date"})
+	}
+	if date.After(today) {
+		return time.Time{}, errors.BadRequest("errors.btm.entry_date_in_future")
+	}
+	return date, nil
+}
+
 // checkAuthorization verifies the user has sufficient BtM authorization
 func (s *BtmService) checkAuthorization(ctx context.Context, userID, requiredType string) error {
 	if userID == "" {
-		return errors.Forbidden("user context required for BtM operations")
+		return errors.Forbidden("errors.btm.user_required")
 	}
 
 	authorized, err := s.btmAuthRepo.IsAuthorized(ctx, userID, requiredType)
@@ -390,7 +450,7 @@ func (s *BtmService) checkAuthorization(ctx context.Context, userID, requiredTyp
 	}
 
 	if !authorized {
-		return errors.Forbidden(fmt.Sprintf("user not authorized for BtM operation (requires %s)", requiredType))
+		return errors.Forbidden("errors.btm.not_authorized", map[string]string{"authorization": requiredType})
 	}
 
 	return nil
@@ -0,0 +1,637 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/httputil"
+)
+
+// btmDifferenceTolerance is below the precision of the register (4 decimals)
+const btmDifferenceTolerance = 0.00005
+
+// BtmCloseRequest represents the signed closing of a month
+type BtmCloseRequest struct {
+	Period string `json:"period"`          // YYYY-MM
+	Notes  string `json:"notes,omitempty"` // required when checks found differences
+}
+
+// BtmCheckDifference is an inventory check whose count differs from the book balance
+type BtmCheckDifference struct {
+	EntryID     string    `json:"entry_id"`
+	EntryNumber int       `json:"entry_number"`
+	EntryDate   time.Time `json:"entry_date"`
+	BookBalance float64   `json:"book_balance"`
+	Counted     float64   `json:"counted"`
+	Difference  float64   `json:"difference"`
+}
+
+// BtmSubstanceReconciliation reconciles the register of one substance over a period
+type BtmSubstanceReconciliation struct {
+	*repository.BtmPeriodBalance
+	Differences []*BtmCheckDifference `json:"differences"`
+
+	lines []*btmRegisterLine
+}
+
+// btmRegisterLine is an entry of the period with the balance after it, in
+// order of the entry dates
+type btmRegisterLine struct {
+	entry      *repository.BtmEntry
+	balance    float64
+	difference float64 // inventory checks: counted minus book balance
+}
+
+// BtmReconciliation is the reconciliation of every substance over a month or a year
+type BtmReconciliation struct {
+	Period          string                         `json:"period"`
+	PeriodStart     time.Time                      `json:"period_start"`
+	PeriodEnd       time.Time                      `json:"period_end"`
+	Substances      []*BtmSubstanceReconciliation  `json:"substances"`
+	DifferenceCount int                            `json:"difference_count"`
+	Closings        []*repository.BtmPeriodClosing `json:"closings"` // closed months of the period
+}
+
+// ReconcilePeriod reconciles the register of every substance over a month
+// (YYYY-MM) or a year (YYYY): opening balance, movements, differences found
+// by inventory checks and closing balance
+func (s *BtmService) ReconcilePeriod(ctx context.Context, period string) (*BtmReconciliation, error) {
+	if err := s.checkAuthorization(ctx, httputil.GetUserID(ctx), "view_only"); err != nil {
+		return nil, err
+	}
+
+	start, end, err := parseBtmPeriod(period)
+	if err != nil {
+		return nil, err
+	}
+	return s.reconcile(ctx, period, start, end)
+}
+
+// ClosePeriod signs the end-of-month verification of the register (BtMVV
+// §13 Abs. 2). Months are closed in order once they have ended; differences
+// found by inventory checks must be explained in the notes. The closing
+// stores the reconciled balances with a SHA-256 seal and locks the month
+// against back-dated entries.
+func (s *BtmService) ClosePeriod(ctx context.Context, req *BtmCloseRequest) (*repository.BtmPeriodClosing, error) {
+	userID := httputil.GetUserID(ctx)
+
+	// Signing requires full access
+	if err := s.checkAuthorization(ctx, userID, "full"); err != nil {
+		return nil, err
+	}
+	signer, err := s.btmAuthRepo.GetActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.Period) != len("2006-01") {
+		return nil, errors.BadRequest("errors.btm.invalid_period")
+	}
+	start, end, err := parseBtmPeriod(req.Period)
+	if err != nil {
+		return nil, err
+	}
+	if !end.Before(btmToday()) {
+		return nil, errors.BadRequest("errors.btm.period_not_ended", map[string]string{"period": req.Period})
+	}
+
+	closing := &repository.BtmPeriodClosing{
+		PeriodStart:  start,
+		PeriodEnd:    end,
+		ClosedBy:     userID,
+		ClosedByName: signer.UserName,
+		ClosedAt:     time.Now().UTC().Truncate(time.Microsecond),
+	}
+	if notes := strings.TrimSpace(req.Notes); notes != "" {
+		closing.Notes = &notes
+	}
+
+	err = s.btmRepo.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.btmRepo.LockClosings(ctx); err != nil {
+			return err
+		}
+
+		closedThrough, err := s.btmRepo.ClosedThrough(ctx)
+		if err != nil {
+			return err
+		}
+		if closedThrough != nil {
+			if !start.After(*closedThrough) {
+				return errors.Conflict("errors.btm.period_already_closed", map[string]string{"period": req.Period})
+			}
+			if next := closedThrough.AddDate(0, 0, 1); !start.Equal(next) {
+				return errors.Conflict("errors.btm.previous_period_open", map[string]string{"period": next.Format("2006-01")})
+			}
+		}
+
+		rec, err := s.reconcile(ctx, req.Period, start, end)
+		if err != nil {
+			return err
+		}
+		if rec.DifferenceCount > 0 && closing.Notes == nil {
+			return errors.Validation(nil).WithDetail("notes", "errors.btm.differences_unexplained",
+				map[string]string{"count": strconv.Itoa(rec.DifferenceCount)})
+		}
+
+		closing.SubstanceCount = len(rec.Substances)
+		closing.DifferenceCount = rec.DifferenceCount
+		closing.Balances = make([]*repository.BtmPeriodBalance, 0, len(rec.Substances))
+		for _, sub := range rec.Substances {
+			closing.Balances = append(closing.Balances, sub.BtmPeriodBalance)
+		}
+		closing.ProtocolSHA256 = btmClosingDigest(closing, rec.Substances)
+
+		if err := s.btmRepo.CreateClosing(ctx, closing); err != nil {
+			return err
+		}
+
+		return s.auditService.RecordAction(ctx, "btm_period_closing", closing.ID, "btm_period_closed", map[string]interface{}{
+			"period_start":     start.Format("2006-01-02"),
+			"period_end":       end.Format("2006-01-02"),
+			"substance_count":  closing.SubstanceCount,
+			"difference_count": closing.DifferenceCount,
+			"protocol_sha256":  closing.ProtocolSHA256,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return closing, nil
+}
+
+// ListClosings lists the signed closings, latest period first
+func (s *BtmService) ListClosings(ctx context.Context) ([]*repository.BtmPeriodClosing, error) {
+	if err := s.checkAuthorization(ctx, httputil.GetUserID(ctx), "view_only"); err != nil {
+		return nil, err
+	}
+	return s.btmRepo.ListClosings(ctx)
+}
+
+// GetClosing gets a signed closing with its balances
+func (s *BtmService) GetClosing(ctx context.Context, id string) (*repository.BtmPeriodClosing, error) {
+	if err := s.checkAuthorization(ctx, httputil.GetUserID(ctx), "view_only"); err != nil {
+		return nil, err
+	}
+	return s.btmRepo.GetClosing(ctx, id)
+}
+
+// Karteikarte renders the register of a substance over a month or a year in
+// the layout of the BtM-Karteikarte (BtMVV §13 Abs. 1), with the signed
+// monthly closings of the period
+func (s *BtmService) Karteikarte(ctx context.Context, itemID, period string) ([]byte, error) {
+	if err := s.checkAuthorization(ctx, httputil.GetUserID(ctx), "view_only"); err != nil {
+		return nil, err
+	}
+
+	start, end, err := parseBtmPeriod(period)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := s.itemRepo.GetByID(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.btmRepo.ListEntriesThrough(ctx, itemID, end)
+	if err != nil {
+		return nil, err
+	}
+	closings, err := s.btmRepo.ListClosings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sub := reconcileBtmSubstance(&repository.BtmSubstance{ID: item.ID, Name: item.Name, Unit: item.Unit}, entries, start)
+	return renderKarteikarte(item, sub, closingsWithin(closings, start, end), start, end)
+}
+
+// reconcile reconciles every substance; within a transaction it sees the
+// register as of the closing
+func (s *BtmService) reconcile(ctx context.Context, period string, start, end time.Time) (*BtmReconciliation, error) {
+	substances, err := s.btmRepo.ListSubstances(ctx, end)
+	if err != nil {
+		return nil, err
+	}
+	closings, err := s.btmRepo.ListClosings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rec := &BtmReconciliation{
+		Period:      period,
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Substances:  make([]*BtmSubstanceReconciliation, 0, len(substances)),
+		Closings:    closingsWithin(closings, start, end),
+	}
+	for _, substance := range substances {
+		entries, err := s.btmRepo.ListEntriesThrough(ctx, substance.ID, end)
+		if err != nil {
+			return nil, err
+		}
+		sub := reconcileBtmSubstance(substance, entries, start)
+		rec.Substances = append(rec.Substances, sub)
+		rec.DifferenceCount += len(sub.Differences)
+	}
+	return rec, nil
+}
+
+// reconcileBtmSubstance replays the entries up to the end of the period in
+// order of their dates. Entries before start make up the opening balance;
+// an inventory check sets the balance to the count and records the
+// difference to the book balance.
+func reconcileBtmSubstance(substance *repository.BtmSubstance, entries []*repository.BtmEntry, start time.Time) *BtmSubstanceReconciliation {
+	sub := &BtmSubstanceReconciliation{
+		BtmPeriodBalance: &repository.BtmPeriodBalance{
+			ItemID:   substance.ID,
+			ItemName: substance.Name,
+			Unit:     substance.Unit,
+		},
+		Differences: []*BtmCheckDifference{},
+	}
+	b := sub.BtmPeriodBalance
+
+	balance := 0.0
+	opened := false
+	for _, e := range entries {
+		inPeriod := !e.EntryDate.Before(start)
+		if inPeriod && !opened {
+			b.OpeningBalance = balance
+			opened = true
+		}
+
+		difference := 0.0
+		switch e.EntryType {
+		case "receipt":
+			balance += e.Quantity
+			if inPeriod {
+				b.Receipts += e.Quantity
+			}
+		case "dispense":
+			balance -= e.Quantity
+			if inPeriod {
+				b.Dispensed += e.Quantity
+			}
+		case "disposal":
+			balance -= e.Quantity
+			if inPeriod {
+				b.Disposed += e.Quantity
+			}
+		case "correction":
+			balance += e.Quantity
+			if inPeriod {
+				b.Corrections += e.Quantity
+			}
+		case "inventory_check":
+			difference = roundBtm(e.Quantity - balance)
+			if inPeriod {
+				b.CheckCount++
+				b.CheckDifference += difference
+				if math.Abs(difference) >= btmDifferenceTolerance {
+					sub.Differences = append(sub.Differences, &BtmCheckDifference{
+						EntryID:     e.ID,
+						EntryNumber: e.EntryNumber,
+						EntryDate:   e.EntryDate,
+						BookBalance: roundBtm(balance),
+						Counted:     e.Quantity,
+						Difference:  difference,
+					})
+				}
+			}
+			balance = e.Quantity
+		}
+		balance = roundBtm(balance)
+
+		if inPeriod {
+			b.EntryCount++
+			sub.lines = append(sub.lines, &btmRegisterLine{entry: e, balance: balance, difference: difference})
+		}
+	}
+	if !opened {
+		b.OpeningBalance = balance
+	}
+
+	b.OpeningBalance = roundBtm(b.OpeningBalance)
+	b.Receipts = roundBtm(b.Receipts)
+	b.Dispensed = roundBtm(b.Dispensed)
+	b.Disposed = roundBtm(b.Disposed)
+	b.Corrections = roundBtm(b.Corrections)
+	b.CheckDifference = roundBtm(b.CheckDifference)
+	b.ClosingBalance = balance
+	b.DifferenceCount = len(sub.Differences)
+	return sub
+}
+
+// btmClosingDigest seals the closing: the signer, the balances and every
+// entry of the period
+func btmClosingDigest(c *repository.BtmPeriodClosing, substances []*BtmSubstanceReconciliation) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s|%s|%s|%s|%s|%s\n", c.PeriodStart.Format("2006-01-02"), c.PeriodEnd.Format("2006-01-02"),
+		c.ClosedBy, c.ClosedByName, c.ClosedAt.UTC().Format(time.RFC3339Nano), deref(c.Notes))
+	for _, sub := range substances {
+		b := sub.BtmPeriodBalance
+		fmt.Fprintf(h, "%s|%s|%.4f|%.4f|%.4f|%.4f|%.4f|%.4f|%.4f|%d|%d\n", b.ItemID, b.Unit, b.OpeningBalance,
+			b.Receipts, b.Dispensed, b.Disposed, b.Corrections, b.CheckDifference, b.ClosingBalance,
+			b.EntryCount, b.CheckCount)
+		for _, l := range sub.lines {
+			fmt.Fprintf(h, "%s|%d|%s|%s|%.4f|%.4f\n", l.entry.ID, l.entry.EntryNumber,
+				l.entry.EntryDate.Format("2006-01-02"), l.entry.EntryType, l.entry.Quantity, l.balance)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// renderKarteikarte renders the register lines of one substance with the
+// carried-over balance, the monthly closings after the last entry of their
+// month, a summary and the signature line of the responsible physician
+func renderKarteikarte(item *repository.InventoryItem, sub *BtmSubstanceReconciliation, closings []*repository.BtmPeriodClosing, start, end time.Time) ([]byte, error) {
+	pdf := fpdf.New("L", "mm", "A4", "")
+	pdf.SetAutoPageBreak(true, 20)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	loc := berlin()
+	generated := time.Now().In(loc).Format("02.01.2006 15:04")
+
+	// Header
+	pdf.SetHeaderFunc(func() {
+		pdf.SetFont("Arial", "B", 14)
+		pdf.Cell(0, 10, tr("BtM-Karteikarte - Nachweis über Zugang und Abgang (§13 BtMVV)"))
+		pdf.Ln(6)
+		pdf.SetFont("Arial", "", 9)
+		line := "Bezeichnung: " + item.Name
+		if pzn := deref(item.PZN); pzn != "" {
+			line += " | PZN: " + pzn
+		}
+		if manufacturer := deref(item.Manufacturer); manufacturer != "" {
+			line += " | Hersteller: " + manufacturer
+		}
+		pdf.Cell(0, 6, tr(line+" | Einheit: "+item.Unit))
+		pdf.Ln(5)
+		pdf.Cell(0, 6, tr(fmt.Sprintf("Zeitraum: %s bis %s | Erstellt: %s",
+			start.Format("02.01.2006"), end.Format("02.01.2006"), generated)))
+		pdf.Ln(9)
+	})
+
+	// Footer
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-15)
+		pdf.SetFont("Arial", "I", 8)
+		pdf.CellFormat(0, 10, fmt.Sprintf("Seite %d/{nb} - Generated by MedFlow", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+	pdf.AliasNbPages("")
+	pdf.AddPage()
+
+	colWidths := []float64{14, 20, 20, 20, 22, 78, 30, 51, 22}
+	headers := []string{"Lfd. Nr.", "Datum", "Zugang", "Abgang", "Bestand", "Lieferant / Empfänger / Verbleib", "Beleg-Nr.", "Bemerkung", "Namenszeichen"}
+	tableWidth := 0.0
+	for _, w := range colWidths {
+		tableWidth += w
+	}
+
+	header := func() {
+		pdf.SetFont("Arial", "B", 8)
+		pdf.SetFillColor(220, 220, 220)
+		for i, h := range headers {
+			pdf.CellFormat(colWidths[i], 7, tr(h), "1", 0, "C", true, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetFont("Arial", "", 7)
+	}
+	row := func(values []string, fill bool) {
+		if pdf.GetY()+6 > 190 {
+			pdf.AddPage()
+			header()
+		}
+		for i, v := range values {
+			align := "L"
+			switch i {
+			case 0, 1:
+				align = "C"
+			case 2, 3, 4:
+				align = "R"
+			}
+			pdf.CellFormat(colWidths[i], 6, tr(v), "1", 0, align, fill, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+	closingRow := func(c *repository.BtmPeriodClosing, balance float64) {
+		if pdf.GetY()+6 > 190 {
+			pdf.AddPage()
+			header()
+		}
+		pdf.SetFillColor(230, 236, 245)
+		pdf.SetFont("Arial", "B", 7)
+		text := fmt.Sprintf("Monatsabschluss %s: Bestand %s %s geprüft, gezeichnet von %s am %s - SHA-256 %s",
+			c.PeriodStart.Format("01/2006"), formatBtmQuantity(balance), item.Unit, c.ClosedByName,
+			c.ClosedAt.In(loc).Format("02.01.2006 15:04"), c.ProtocolSHA256[:16])
+		pdf.CellFormat(tableWidth, 6, tr(truncate(text, 190)), "1", 1, "L", true, 0, "")
+		pdf.SetFont("Arial", "", 7)
+	}
+
+	header()
+	pdf.SetFillColor(245, 245, 245)
+	row([]string{"", start.Format("02.01.2006"), "", "", formatBtmQuantity(sub.OpeningBalance), "Übertrag", "", "", ""}, true)
+
+	numbers := make(map[string]int, len(sub.lines))
+	for _, l := range sub.lines {
+		numbers[l.entry.ID] = l.entry.EntryNumber
+	}
+
+	balance := sub.OpeningBalance
+	next := 0
+	fill := false
+	for _, l := range sub.lines {
+		for next < len(closings) && l.entry.EntryDate.After(closings[next].PeriodEnd) {
+			closingRow(closings[next], balance)
+			next++
+		}
+
+		if l.difference != 0 {
+			pdf.SetFillColor(253, 228, 222)
+		} else if fill {
+			pdf.SetFillColor(245, 245, 245)
+		} else {
+			pdf.SetFillColor(255, 255, 255)
+		}
+		in, out := karteikarteMovement(l)
+		row([]string{
+			strconv.Itoa(l.entry.EntryNumber),
+			l.entry.EntryDate.Format("02.01.2006"),
+			in,
+			out,
+			formatBtmQuantity(l.balance),
+			truncate(karteikarteCounterparty(l, numbers), 60),
+			truncate(deref(l.entry.DeliveryNoteNumber), 20),
+			truncate(deref(l.entry.Notes), 38),
+			truncate(l.entry.PerformedByName, 16),
+		}, true)
+		balance = l.balance
+		fill = !fill
+	}
+	for ; next < len(closings); next++ {
+		closingRow(closings[next], balance)
+	}
+	pdf.Ln(6)
+
+	renderKarteikarteSummary(pdf, tr, sub)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to generate PDF: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func renderKarteikarteSummary(pdf *fpdf.Fpdf, tr func(string) string, sub *BtmSubstanceReconciliation) {
+	if pdf.GetY()+70 > 190 {
+		pdf.AddPage()
+	}
+	unit := " " + sub.Unit
+	rows := [][2]string{
+		{"Anfangsbestand", formatBtmQuantity(sub.OpeningBalance) + unit},
+		{"Zugänge", formatBtmQuantity(sub.Receipts) + unit},
+		{"Abgänge", formatBtmQuantity(sub.Dispensed) + unit},
+		{"Vernichtungen", formatBtmQuantity(sub.Disposed) + unit},
+		{"Korrekturen", formatBtmSigned(sub.Corrections) + unit},
+		{"Prüfdifferenzen", fmt.Sprintf("%s%s (%d Bestandsprüfungen, %d mit Differenz)",
+			formatBtmSigned(sub.CheckDifference), unit, sub.CheckCount, len(sub.Differences))},
+		{"Endbestand", formatBtmQuantity(sub.ClosingBalance) + unit},
+	}
+	for _, row := range rows {
+		pdf.SetFont("Arial", "B", 9)
+		pdf.CellFormat(40, 5, tr(row[0]), "", 0, "L", false, 0, "")
+		pdf.SetFont("Arial", "", 9)
+		pdf.CellFormat(0, 5, tr(row[1]), "", 1, "L", false, 0, "")
+	}
+
+	pdf.Ln(16)
+	pdf.CellFormat(130, 6, "", "B", 1, "L", false, 0, "")
+	pdf.CellFormat(130, 6, tr("Ort, Datum, Unterschrift des verantwortlichen Arztes (§13 Abs. 2 BtMVV)"), "", 1, "L", false, 0, "")
+}
+
+// karteikarteMovement returns the Zugang and Abgang columns of a line
+func karteikarteMovement(l *btmRegisterLine) (string, string) {
+	q := l.entry.Quantity
+	switch l.entry.EntryType {
+	case "receipt":
+		return formatBtmQuantity(q), ""
+	case "dispense", "disposal":
+		return "", formatBtmQuantity(q)
+	case "inventory_check":
+		q = l.difference
+	}
+
+	// Corrections and check differences are signed
+	if q > 0 {
+		return formatBtmQuantity(q), ""
+	}
+	if q < 0 {
+		return "", formatBtmQuantity(-q)
+	}
+	return "", ""
+}
+
+// karteikarteCounterparty describes where a movement came from or went to
+func karteikarteCounterparty(l *btmRegisterLine, numbers map[string]int) string {
+	e := l.entry
+	var parts []string
+	switch e.EntryType {
+	case "receipt":
+		parts = append(parts, "Lieferant: "+deref(e.SupplierName))
+	case "dispense":
+		parts = append(parts, "Patient: "+deref(e.PatientIdentifier))
+		if doctor := deref(e.PrescribingDoctor); doctor != "" {
+			parts = append(parts, "Arzt: "+doctor)
+		}
+		if purpose := deref(e.Purpose); purpose != "" {
+			parts = append(parts, purpose)
+		}
+	case "disposal":
+		parts = append(parts, "Vernichtung")
+		if method := deref(e.DisposalMethod); method != "" {
+			parts[0] += " (" + method + ")"
+		}
+		parts = append(parts, "Zeuge: "+deref(e.DisposalWitness))
+	case "correction":
+		ref := "Korrektur"
+		if e.CorrectsEntryID != nil {
+			if n, ok := numbers[*e.CorrectsEntryID]; ok {
+				ref = fmt.Sprintf("Korrektur zu Nr. %d", n)
+			}
+		}
+		parts = append(parts, ref)
+		if reason := deref(e.CorrectionReason); reason != "" {
+			parts = append(parts, reason)
+		}
+	case "inventory_check":
+		parts = append(parts, "Bestandsprüfung: gezählt "+formatBtmQuantity(e.Quantity))
+		if l.difference != 0 {
+			parts = append(parts, "Differenz "+formatBtmSigned(l.difference))
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// closingsWithin returns the closings of months within start and end,
+// oldest first
+func closingsWithin(closings []*repository.BtmPeriodClosing, start, end time.Time) []*repository.BtmPeriodClosing {
+	within := []*repository.BtmPeriodClosing{}
+	for i := len(closings) - 1; i >= 0; i-- {
+		c := closings[i]
+		if !c.PeriodStart.Before(start) && !c.PeriodEnd.After(end) {
+			within = append(within, c)
+		}
+	}
+	return within
+}
+
+// parseBtmPeriod parses a month (YYYY-MM) or a year (YYYY) into its first
+// and last day
+func parseBtmPeriod(period string) (time.Time, time.Time, error) {
+	if month, err := time.Parse("2006-01", period); err == nil {
+		return month, month.AddDate(0, 1, -1), nil
+	}
+	if year, err := time.Parse("2006", period); err == nil {
+		return year, year.AddDate(1, 0, -1), nil
+	}
+	return time.Time{}, time.Time{}, errors.BadRequest("errors.btm.invalid_period")
+}
+
+// btmToday returns the current day in the practice time zone, as register
+// entry dates are stored
+func btmToday() time.Time {
+	now := time.Now().In(berlin())
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// roundBtm rounds to the precision of the register
+func roundBtm(v float64) float64 {
+	return math.Round(v*10000) / 10000
+}
+
+// formatBtmQuantity formats a quantity with up to four decimals and a decimal comma
+func formatBtmQuantity(v float64) string {
+	if roundBtm(v) == 0 {
+		return "0"
+	}
+	return strings.Replace(strconv.FormatFloat(roundBtm(v), 'f', -1, 64), ".", ",", 1)
+}
+
+// formatBtmSigned formats a quantity with its sign
+func formatBtmSigned(v float64) string {
+	if v > 0 {
+		return "+" + formatBtmQuantity(v)
+	}
+	return formatBtmQuantity(v)
+}
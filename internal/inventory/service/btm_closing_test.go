@@ -0,0 +1,154 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/internal/inventory/service"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/httputil"
+	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBtmService_ClosePeriod(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tenant := suite.SetupInventoryTenant(t, ctx, "btm-closing")
+	tenantCtx := suite.TenantContext(tenant)
+
+	log := logger.New("test", "test")
+	btmRepo := repository.NewBtmRepository(suite.DB)
+	btmAuthRepo := repository.NewBtmAuthRepository(suite.DB)
+	itemRepo := repository.NewItemRepository(suite.DB)
+	auditService := service.NewAuditService(repository.NewAuditTrailRepository(suite.DB), log)
+	svc := service.NewBtmService(btmRepo, btmAuthRepo, itemRepo, auditService, log)
+
+	physician := "00000000-0000-0000-0000-0000000000d1"
+	require.NoError(t, btmAuthRepo.Create(tenantCtx, &repository.BtmAuthorizedPerson{
+		UserID: physician, UserName: "Dr. Erika Muster", AuthorizationType: "full", AuthorizedAt: time.Now(),
+	}))
+	userCtx := httputil.WithUserContext(tenantCtx, physician, "erika@example.com", "admin")
+
+	morphine := &repository.InventoryItem{Name: "Morphin 10 mg/ml", Unit: "Ampulle", IsActive: true}
+	require.NoError(t, itemRepo.Create(tenantCtx, morphine))
+
+	// Three ended months: an empty one, one with a check difference, and the last one
+	now := time.Now()
+	month := func(offset int) time.Time {
+		return time.Date(now.Year(), now.Month()+time.Month(offset), 1, 0, 0, 0, 0, time.UTC)
+	}
+	empty, audited, last := month(-3), month(-2), month(-1)
+	day := func(m time.Time, d int) string { return m.AddDate(0, 0, d-1).Format("2006-01-02") }
+	errorKey := func(err error) string {
+		var appErr *errors.AppError
+		require.True(t, errors.As(err, &appErr), "expected an AppError, got %v", err)
+		return appErr.MessageKey
+	}
+
+	// Back-dated entries of open months
+	_, err := svc.ReceiveSubstance(userCtx, &service.BtmReceiveRequest{ItemID: morphine.ID, Quantity: 10, Unit: "Ampulle",
+		SupplierName: "Apotheke am Markt", DeliveryNoteNumber: "LS-4711", EntryDate: day(audited, 3)})
+	require.NoError(t, err)
+	_, err = svc.DispenseSubstance(userCtx, &service.BtmDispenseRequest{ItemID: morphine.ID, Quantity: 2, Unit: "Ampulle",
+		PatientIdentifier: "P-100", PrescribingDoctor: "Dr. Muster", EntryDate: day(audited, 10)})
+	require.NoError(t, err)
+	_, err = svc.InventoryCheck(userCtx, &service.BtmCheckRequest{ItemID: morphine.ID, Quantity: 7, Unit: "Ampulle",
+		EntryDate: day(audited, 20)})
+	require.NoError(t, err)
+	_, err = svc.ReceiveSubstance(userCtx, &service.BtmReceiveRequest{ItemID: morphine.ID, Quantity: 5, Unit: "Ampulle",
+		SupplierName: "Apotheke am Markt", EntryDate: day(last, 5)})
+	require.NoError(t, err)
+
+	_, err = svc.ReceiveSubstance(userCtx, &service.BtmReceiveRequest{ItemID: morphine.ID, Quantity: 1, Unit: "Ampulle",
+		EntryDate: now.AddDate(0, 0, 2).Format("2006-01-02")})
+	assert.Equal(t, "errors.btm.entry_date_in_future", errorKey(err))
+
+	// A dispense entered after the check but dated before it would change
+	// the balance the check confirmed
+	_, err = svc.DispenseSubstance(userCtx, &service.BtmDispenseRequest{ItemID: morphine.ID, Quantity: 1, Unit: "Ampulle",
+		PatientIdentifier: "P-101", PrescribingDoctor: "Dr. Muster", EntryDate: day(audited, 15)})
+	assert.Equal(t, "errors.btm.before_inventory_check", errorKey(err))
+
+	// The check found one ampoule less than the book balance
+	rec, err := svc.ReconcilePeriod(userCtx, audited.Format("2006-01"))
+	require.NoError(t, err)
+	require.Len(t, rec.Substances, 1)
+	sub := rec.Substances[0]
+	assert.Equal(t, 0.0, sub.OpeningBalance)
+	assert.Equal(t, 10.0, sub.Receipts)
+	assert.Equal(t, 2.0, sub.Dispensed)
+	assert.Equal(t, -1.0, sub.CheckDifference)
+	assert.Equal(t, 7.0, sub.ClosingBalance)
+	assert.Equal(t, 3, sub.EntryCount)
+	require.Len(t, sub.Differences, 1)
+	assert.Equal(t, 8.0, sub.Differences[0].BookBalance)
+	assert.Equal(t, 1, rec.DifferenceCount)
+
+	// The annual view carries the balance over from the months before
+	year, err := svc.ReconcilePeriod(userCtx, last.Format("2006"))
+	require.NoError(t, err)
+	require.Len(t, year.Substances, 1)
+	if audited.Year() == last.Year() {
+		assert.Equal(t, 0.0, year.Substances[0].OpeningBalance)
+	} else {
+		assert.Equal(t, 7.0, year.Substances[0].OpeningBalance)
+	}
+	assert.Equal(t, 12.0, year.Substances[0].ClosingBalance)
+
+	// Months are closed in order once they have ended
+	_, err = svc.ClosePeriod(userCtx, &service.BtmCloseRequest{Period: month(0).Format("2006-01")})
+	assert.Equal(t, "errors.btm.period_not_ended", errorKey(err))
+
+	first, err := svc.ClosePeriod(userCtx, &service.BtmCloseRequest{Period: empty.Format("2006-01")})
+	require.NoError(t, err)
+	assert.Equal(t, 0, first.SubstanceCount)
+
+	_, err = svc.ClosePeriod(userCtx, &service.BtmCloseRequest{Period: last.Format("2006-01")})
+	assert.Equal(t, "errors.btm.previous_period_open", errorKey(err))
+
+	// Differences must be explained before signing
+	_, err = svc.ClosePeriod(userCtx, &service.BtmCloseRequest{Period: audited.Format("2006-01")})
+	var appErr *errors.AppError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, "errors.btm.differences_unexplained", appErr.Details["notes"])
+
+	closing, err := svc.ClosePeriod(userCtx, &service.BtmCloseRequest{Period: audited.Format("2006-01"),
+		Notes: "Ampulle beim Aufziehen zerbrochen, Vernichtung nachgetragen"})
+	require.NoError(t, err)
+	assert.Equal(t, "Dr. Erika Muster", closing.ClosedByName)
+	assert.Equal(t, 1, closing.DifferenceCount)
+	assert.Len(t, closing.ProtocolSHA256, 64)
+
+	_, err = svc.ClosePeriod(userCtx, &service.BtmCloseRequest{Period: audited.Format("2006-01"), Notes: "erneut"})
+	assert.Equal(t, "errors.btm.period_already_closed", errorKey(err))
+
+	stored, err := svc.GetClosing(userCtx, closing.ID)
+	require.NoError(t, err)
+	require.Len(t, stored.Balances, 1)
+	assert.Equal(t, 7.0, stored.Balances[0].ClosingBalance)
+	assert.Equal(t, -1.0, stored.Balances[0].CheckDifference)
+
+	// The closed month is locked against back-dated entries
+	_, err = svc.DispenseSubstance(userCtx, &service.BtmDispenseRequest{ItemID: morphine.ID, Quantity: 1, Unit: "Ampulle",
+		PatientIdentifier: "P-101", EntryDate: day(audited, 28)})
+	assert.Equal(t, "errors.btm.period_closed", errorKey(err))
+	_, err = svc.DispenseSubstance(userCtx, &service.BtmDispenseRequest{ItemID: morphine.ID, Quantity: 1, Unit: "Ampulle",
+		PatientIdentifier: "P-101", EntryDate: day(last, 7)})
+	require.NoError(t, err)
+
+	rec, err = svc.ReconcilePeriod(userCtx, audited.Format("2006-01"))
+	require.NoError(t, err)
+	require.Len(t, rec.Closings, 1)
+	assert.Equal(t, closing.ID, rec.Closings[0].ID)
+
+	pdf, err := svc.Karteikarte(userCtx, morphine.ID, audited.Format("2006-01"))
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF")))
+}
@@ -0,0 +1,768 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/medflow/medflow-backend/internal/inventory/events"
+	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/hmacsig"
+	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/medflow/medflow-backend/pkg/tenant"
+)
+
+const (
+	// DefaultExcursionGrace is how long a cabinet may be out of range (a door
+	// left open while restocking) before the excursion is confirmed, unless
+	// the cabinet sets its own grace period
+	DefaultExcursionGrace = 15 * time.Minute
+
+	// MaxReadingsPerRequest limits the buffered readings a sensor sends at once
+	MaxReadingsPerRequest = 1000
+
+	// sensorSignatureTolerance is how old a sensor signature may be
+	sensorSignatureTolerance = 5 * time.Minute
+
+	// readingFutureSkew tolerates loggers whose clock runs slightly ahead
+	readingFutureSkew = 5 * time.Minute
+
+	// mktActivationEnergy is ΔH/R in kelvin for the mean kinetic temperature
+	// (ΔH = 83.144 kJ/mol, ICH Q1A)
+	mktActivationEnergy = 10000.0
+)
+
+// ColdChainService ingests cabinet temperature readings from sensors,
+// tracks excursions and quarantines the cooled stock they affect
+type ColdChainService struct {
+	sensorRepo      *repository.SensorRepository
+	excursionRepo   *repository.ExcursionRepository
+	temperatureRepo *repository.TemperatureRepository
+	locationRepo    *repository.LocationRepository
+	alertRepo       *repository.AlertRepository
+	auditService    *AuditService
+	publisher       *events.InventoryEventPublisher
+	sealKey         string // server key that seals temperature reports
+	logger          *logger.Logger
+}
+
+// NewColdChainService creates a new cold chain service
+func NewColdChainService(
+	sensorRepo *repository.SensorRepository,
+	excursionRepo *repository.ExcursionRepository,
+	temperatureRepo *repository.TemperatureRepository,
+	locationRepo *repository.LocationRepository,
+	alertRepo *repository.AlertRepository,
+	auditService *AuditService,
+	publisher *events.InventoryEventPublisher,
+	sealKey string,
+	log *logger.Logger,
+) *ColdChainService {
+	return &ColdChainService{
+		sensorRepo:      sensorRepo,
+		excursionRepo:   excursionRepo,
+		temperatureRepo: temperatureRepo,
+		locationRepo:    locationRepo,
+		alertRepo:       alertRepo,
+		auditService:    auditService,
+		publisher:       publisher,
+		sealKey:         sealKey,
+		logger:          log,
+	}
+}
+
+// Sensor credentials
+
+// CreateSensor registers a sensor for a cabinet. The returned sensor carries
+// its secret, which is not shown again.
+func (s *ColdChainService) CreateSensor(ctx context.Context, cabinetID, name, userID string) (*repository.TemperatureSensor, error) {
+	if _, err := s.locationRepo.GetCabinet(ctx, cabinetID); err != nil {
+		return nil, err
+	}
+
+	keyID, err := randomToken("sensor_", 8)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := randomToken("snsec_", 32)
+	if err != nil {
+		return nil, err
+	}
+
+	sensor := &repository.TemperatureSensor{
+		CabinetID: cabinetID,
+		Name:      name,
+		KeyID:     keyID,
+		Secret:    secret,
+	}
+	if userID != "" {
+		sensor.CreatedBy = &userID
+	}
+
+	if err := s.sensorRepo.Create(ctx, sensor); err != nil {
+		return nil, err
+	}
+	s.auditService.RecordCreate(ctx, "temperature_sensor", sensor.ID, map[string]interface{}{
+		"cabinet_id": cabinetID,
+		"key_id":     keyID,
+	})
+	return sensor, nil
+}
+
+// ListSensors lists the sensors of a cabinet
+func (s *ColdChainService) ListSensors(ctx context.Context, cabinetID string) ([]*repository.TemperatureSensor, error) {
+	return s.sensorRepo.ListByCabinet(ctx, cabinetID)
+}
+
+// RotateSensorSecret replaces the secret of a sensor and returns the sensor
+// with the new secret
+func (s *ColdChainService) RotateSensorSecret(ctx context.Context, id string) (*repository.TemperatureSensor, error) {
+	secret, err := randomToken("snsec_", 32)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.sensorRepo.RotateSecret(ctx, id, secret); err != nil {
+		return nil, err
+	}
+
+	sensor, err := s.sensorRepo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	sensor.Secret = secret
+	s.auditService.RecordAction(ctx, "temperature_sensor", id, "rotate_secret", nil)
+	return sensor, nil
+}
+
+// RevokeSensor revokes a sensor; its readings are rejected from then on
+func (s *ColdChainService) RevokeSensor(ctx context.Context, id string) error {
+	if err := s.sensorRepo.Revoke(ctx, id); err != nil {
+		return err
+	}
+	s.auditService.RecordAction(ctx, "temperature_sensor", id, "revoke", nil)
+	return nil
+}
+
+// AuthenticateSensor verifies the signature of a sensor request. It resolves
+// the tenant from the key ID and returns a context scoped to that tenant
+// together with the sensor.
+func (s *ColdChainService) AuthenticateSensor(ctx context.Context, keyID, signature string, body []byte) (context.Context, *repository.TemperatureSensor, error) {
+	unauthorized := errors.Unauthorized("errors.temperature.sensor_unauthorized")
+	if keyID == "" || signature == "" {
+		return nil, nil, unauthorized
+	}
+
+	tenantID, err := s.sensorRepo.LookupTenant(ctx, keyID)
+	if err != nil {
+		if errors.Is(err, errors.ErrNotFound) {
+			return nil, nil, unauthorized
+		}
+		return nil, nil, err
+	}
+
+	ctx = tenant.WithTenantContext(ctx, tenantID, "")
+	sensor, err := s.sensorRepo.GetActiveByKey(ctx, keyID)
+	if err != nil {
+		if errors.Is(err, errors.ErrNotFound) {
+			return nil, nil, unauthorized
+		}
+		return nil, nil, err
+	}
+
+	if !hmacsig.Verify(sensor.Secret, signature, body, sensorSignatureTolerance, time.Now()) {
+		return nil, nil, unauthorized
+	}
+	sensor.Secret = ""
+	return ctx, sensor, nil
+}
+
+// randomToken returns prefix followed by n random bytes in hex
+func randomToken(prefix string, n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return prefix + hex.EncodeToString(b), nil
+}
+
+// Ingestion
+
+// ReadingInput is one reading as measured by a sensor or a person
+type ReadingInput struct {
+	RecordedAt         time.Time `json:"recorded_at"`
+	TemperatureCelsius float64   `json:"temperature_celsius"`
+}
+
+// ReadingSource describes where a set of readings comes from
+type ReadingSource struct {
+	Source     string // manual, webhook, sensor
+	SensorID   *string
+	RecordedBy *string
+	Notes      *string
+}
+
+// IngestResult reports what a set of readings changed
+type IngestResult struct {
+	Accepted   int                                `json:"accepted"`
+	Duplicates int                                `json:"duplicates"`
+	Readings   []*repository.TemperatureReading   `json:"-"`
+	Excursions []*repository.TemperatureExcursion `json:"excursions"`
+}
+
+// Ingest stores the readings of a cabinet and updates its excursions.
+// Readings already stored for the same sensor and time are skipped, so
+// loggers can re-send their buffer after a connection loss. Excursions are
+// tracked in time order: readings older than the newest stored one re-track
+// the excursions from the first of them on.
+func (s *ColdChainService) Ingest(ctx context.Context, cabinetID string, src ReadingSource, inputs []ReadingInput) (*IngestResult, error) {
+	if err := validateReadings(inputs, time.Now()); err != nil {
+		return nil, err
+	}
+
+	cabinet, err := s.locationRepo.GetCabinet(ctx, cabinetID)
+	if err != nil {
+		return nil, err
+	}
+
+	readings := make([]*repository.TemperatureReading, 0, len(inputs))
+	for _, in := range inputs {
+		readings = append(readings, &repository.TemperatureReading{
+			CabinetID:          cabinetID,
+			TemperatureCelsius: in.TemperatureCelsius,
+			RecordedAt:         in.RecordedAt,
+			RecordedBy:         src.RecordedBy,
+			SensorID:           src.SensorID,
+			Source:             src.Source,
+			IsExcursion:        outOfRange(cabinet, in.TemperatureCelsius),
+			Notes:              src.Notes,
+		})
+	}
+	sort.SliceStable(readings, func(i, j int) bool { return readings[i].RecordedAt.Before(readings[j].RecordedAt) })
+
+	result := &IngestResult{Excursions: []*repository.TemperatureExcursion{}}
+	var alerts []*repository.InventoryAlert
+	err = s.excursionRepo.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.excursionRepo.LockCabinet(ctx, cabinetID); err != nil {
+			return err
+		}
+		latest, err := s.temperatureRepo.LatestRecordedAt(ctx, cabinetID)
+		if err != nil {
+			return err
+		}
+
+		created, err := s.temperatureRepo.CreateBatch(ctx, readings)
+		if err != nil {
+			return err
+		}
+		result.Readings = created
+		result.Accepted = len(created)
+		result.Duplicates = len(readings) - len(created)
+		if len(created) == 0 {
+			return nil
+		}
+
+		// created is in time order, so the first reading that is not newer
+		// than the stored ones is the earliest late one
+		var late *time.Time
+		for _, reading := range created {
+			if latest != nil && !reading.RecordedAt.After(*latest) {
+				late = &reading.RecordedAt
+				break
+			}
+		}
+
+		var changed, confirmed []*repository.TemperatureExcursion
+		if late != nil {
+			changed, confirmed, err = s.retrackExcursions(ctx, cabinet, *late)
+			if err != nil {
+				return err
+			}
+		} else {
+			ongoing, err := s.excursionRepo.GetOngoing(ctx, cabinetID)
+			if err != nil {
+				return err
+			}
+			changed, confirmed = trackExcursions(ongoing, cabinet, created)
+		}
+		for _, e := range changed {
+			if err := s.excursionRepo.Save(ctx, e); err != nil {
+				return err
+			}
+		}
+		for _, e := range confirmed {
+			alert, err := s.quarantine(ctx, cabinet, e)
+			if err != nil {
+				return err
+			}
+			alerts = append(alerts, alert)
+		}
+		result.Excursions = append(result.Excursions, changed...)
+
+		if src.SensorID != nil {
+			return s.sensorRepo.Touch(ctx, *src.SensorID, created[len(created)-1].RecordedAt)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, alert := range alerts {
+		s.publisher.PublishAlertGenerated(ctx, alert)
+	}
+	return result, nil
+}
+
+// retrackExcursions tracks the excursions of a cabinet again over all its
+// readings from the earliest late reading on, or from the start of the
+// excursion that was ongoing then, and merges the result into the stored
+// excursions
+func (s *ColdChainService) retrackExcursions(ctx context.Context, cabinet *repository.StorageCabinet, from time.Time) (changed, confirmed []*repository.TemperatureExcursion, err error) {
+	// No stored reading lies beyond the skew validateReadings allows
+	to := time.Now().Add(readingFutureSkew + time.Second)
+
+	stored, err := s.excursionRepo.ListForPeriod(ctx, cabinet.ID, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(stored) > 0 && stored[0].StartedAt.Before(from) {
+		from = stored[0].StartedAt
+	}
+
+	readings, err := s.temperatureRepo.ListBetween(ctx, cabinet.ID, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	tracked, _ := trackExcursions(nil, cabinet, readings)
+	changed, confirmed = mergeExcursions(stored, tracked)
+	return changed, confirmed, nil
+}
+
+// mergeExcursions merges excursions tracked again over a window into those
+// stored for it, both in time order. A tracked excursion updates the first
+// stored one it overlaps that no earlier tracked excursion updated, and is
+// new otherwise. Stored excursions keep their status once confirmed, since
+// their stock is already quarantined, and decided ones are left as they are.
+// It returns the excursions that changed and, among them, those confirmed
+// by the merge.
+func mergeExcursions(stored, tracked []*repository.TemperatureExcursion) (changed, confirmed []*repository.TemperatureExcursion) {
+	merged := make(map[*repository.TemperatureExcursion]bool, len(stored))
+	for _, t := range tracked {
+		var target *repository.TemperatureExcursion
+		for _, e := range stored {
+			if !merged[e] && excursionsOverlap(e, t) {
+				target = e
+				break
+			}
+		}
+
+		if target == nil {
+			changed = append(changed, t)
+			if t.Status == repository.ExcursionConfirmed {
+				confirmed = append(confirmed, t)
+			}
+			continue
+		}
+		merged[target] = true
+
+		switch target.Status {
+		case repository.ExcursionReleased, repository.ExcursionDiscarded:
+			continue
+		case repository.ExcursionPending, repository.ExcursionTransient:
+			target.Status = t.Status
+			if t.Status == repository.ExcursionConfirmed {
+				target.ConfirmedAt = t.ConfirmedAt
+				confirmed = append(confirmed, target)
+			}
+		}
+		target.StartedAt = t.StartedAt
+		target.EndedAt = t.EndedAt
+		target.LastReadingAt = t.LastReadingAt
+		target.DurationSeconds = t.DurationSeconds
+		target.ReadingCount = t.ReadingCount
+		target.MinTemperature = t.MinTemperature
+		target.MaxTemperature = t.MaxTemperature
+		changed = append(changed, target)
+	}
+	return changed, confirmed
+}
+
+// excursionsOverlap reports whether two excursions share a point in time
+func excursionsOverlap(a, b *repository.TemperatureExcursion) bool {
+	return (a.EndedAt == nil || !a.EndedAt.Before(b.StartedAt)) &&
+		(b.EndedAt == nil || !b.EndedAt.Before(a.StartedAt))
+}
+
+// ConfirmOverdueExcursionsJob confirms the pending excursions whose grace
+// period has passed without a reading back in range, e.g. because the
+// logger went offline in a warm cabinet, and quarantines their stock
+func (s *ColdChainService) ConfirmOverdueExcursionsJob(ctx context.Context) error {
+	now := time.Now()
+	overdue, err := s.excursionRepo.ListOverdue(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range overdue {
+		if err := s.confirmOverdue(ctx, e.CabinetID, now); err != nil {
+			s.logger.Error().Err(err).Str("excursion_id", e.ID).Msg("Failed to confirm overdue excursion")
+		}
+	}
+	return nil
+}
+
+// confirmOverdue confirms the ongoing excursion of a cabinet if it is still
+// pending after its grace period
+func (s *ColdChainService) confirmOverdue(ctx context.Context, cabinetID string, now time.Time) error {
+	cabinet, err := s.locationRepo.GetCabinet(ctx, cabinetID)
+	if err != nil {
+		return err
+	}
+
+	var alert *repository.InventoryAlert
+	err = s.excursionRepo.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.excursionRepo.LockCabinet(ctx, cabinetID); err != nil {
+			return err
+		}
+		// Readings may have ended it since it was listed
+		e, err := s.excursionRepo.GetOngoing(ctx, cabinetID)
+		if err != nil || e == nil || e.Status != repository.ExcursionPending {
+			return err
+		}
+		confirmedAt := e.StartedAt.Add(time.Duration(e.GraceMinutes) * time.Minute)
+		if confirmedAt.After(now) {
+			return nil
+		}
+
+		e.Status = repository.ExcursionConfirmed
+		e.ConfirmedAt = &confirmedAt
+		if err := s.excursionRepo.Save(ctx, e); err != nil {
+			return err
+		}
+		alert, err = s.quarantine(ctx, cabinet, e)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if alert != nil {
+		s.publisher.PublishAlertGenerated(ctx, alert)
+	}
+	return nil
+}
+
+// quarantine puts the cooled stock of a cabinet into quarantine for a newly
+// confirmed excursion and opens the alert that awaits the release decision
+func (s *ColdChainService) quarantine(ctx context.Context, cabinet *repository.StorageCabinet, e *repository.TemperatureExcursion) (*repository.InventoryAlert, error) {
+	batchIDs, err := s.excursionRepo.QuarantineCabinetBatches(ctx, e.ID, cabinet.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	alert := &repository.InventoryAlert{
+		AlertType: "temperature_excursion",
+		ItemID:    cabinet.ID, // Using cabinet ID as reference
+		ItemName:  cabinet.Name,
+		Severity:  "critical",
+		Message: fmt.Sprintf("Temperature excursion in %s since %s: %.1f-%.1f°C (range: %.1f-%.1f°C), %d batches quarantined pending release decision",
+			cabinet.Name, e.StartedAt.UTC().Format(time.RFC3339), e.MinTemperature, e.MaxTemperature,
+			derefFloat(cabinet.MinTemperature), derefFloat(cabinet.MaxTemperature), len(batchIDs)),
+	}
+	if err := s.alertRepo.Create(ctx, alert); err != nil {
+		return nil, err
+	}
+
+	e.AlertID = &alert.ID
+	if err := s.excursionRepo.Save(ctx, e); err != nil {
+		return nil, err
+	}
+
+	s.auditService.RecordAction(ctx, "temperature_excursion", e.ID, "quarantine", map[string]interface{}{
+		"cabinet_id": cabinet.ID,
+		"batch_ids":  batchIDs,
+		"alert_id":   alert.ID,
+	})
+	return alert, nil
+}
+
+// validateReadings checks the count, timestamps and plausibility of readings
+func validateReadings(inputs []ReadingInput, now time.Time) error {
+	if len(inputs) == 0 {
+		return errors.Validation(nil).WithDetail("readings", "validation.required", map[string]string{"field": "readings"})
+	}
+	if len(inputs) > MaxReadingsPerRequest {
+		return errors.BadRequest("errors.temperature.too_many_readings", map[string]string{"max": strconv.Itoa(MaxReadingsPerRequest)})
+	}
+
+	for i, in := range inputs {
+		prefix := fmt.Sprintf("readings[%d].", i)
+		if in.RecordedAt.IsZero() {
+			return errors.Validation(nil).WithDetail(prefix+"recorded_at", "validation.required", map[string]string{"field": "recorded_at"})
+		}
+		if in.RecordedAt.After(now.Add(readingFutureSkew)) {
+			return errors.Validation(nil).WithDetail(prefix+"recorded_at", "errors.temperature.reading_in_future")
+		}
+		if math.IsNaN(in.TemperatureCelsius) || in.TemperatureCelsius < -100 || in.TemperatureCelsius > 100 {
+			return errors.Validation(nil).WithDetail(prefix+"temperature_celsius", "validation.range",
+				map[string]string{"field": "temperature_celsius", "min": "-100", "max": "100"})
+		}
+	}
+	return nil
+}
+
+// outOfRange reports whether a temperature is outside the cabinet's range
+func outOfRange(cabinet *repository.StorageCabinet, celsius float64) bool {
+	return (cabinet.MinTemperature != nil && celsius < *cabinet.MinTemperature) ||
+		(cabinet.MaxTemperature != nil && celsius > *cabinet.MaxTemperature)
+}
+
+// excursionGrace returns the grace period of a cabinet
+func excursionGrace(cabinet *repository.StorageCabinet) time.Duration {
+	if cabinet.ExcursionGraceMinutes != nil {
+		return time.Duration(*cabinet.ExcursionGraceMinutes) * time.Minute
+	}
+	return DefaultExcursionGrace
+}
+
+// trackExcursions advances the excursion state of a cabinet over readings in
+// time order. An out-of-range reading opens an excursion or extends the
+// ongoing one; it is confirmed once it has lasted the grace period. The
+// next in-range reading ends it, and an excursion that ended within the
+// grace period was transient. It returns the excursions that changed and,
+// among them, those confirmed by these readings.
+func trackExcursions(ongoing *repository.TemperatureExcursion, cabinet *repository.StorageCabinet, readings []*repository.TemperatureReading) (changed, confirmed []*repository.TemperatureExcursion) {
+	grace := excursionGrace(cabinet)
+	current := ongoing
+	touched := func(e *repository.TemperatureExcursion) {
+		for _, c := range changed {
+			if c == e {
+				return
+			}
+		}
+		changed = append(changed, e)
+	}
+
+	for _, reading := range readings {
+		t := reading.TemperatureCelsius
+		if outOfRange(cabinet, t) {
+			if current == nil {
+				current = &repository.TemperatureExcursion{
+					CabinetID:      cabinet.ID,
+					Status:         repository.ExcursionPending,
+					StartedAt:      reading.RecordedAt,
+					MinTemperature: t,
+					MaxTemperature: t,
+					AllowedMin:     cabinet.MinTemperature,
+					AllowedMax:     cabinet.MaxTemperature,
+					GraceMinutes:   int(grace / time.Minute),
+				}
+			}
+			current.ReadingCount++
+			current.MinTemperature = math.Min(current.MinTemperature, t)
+			current.MaxTemperature = math.Max(current.MaxTemperature, t)
+			current.LastReadingAt = reading.RecordedAt
+			current.DurationSeconds = int(reading.RecordedAt.Sub(current.StartedAt) / time.Second)
+			touched(current)
+
+			if current.Status == repository.ExcursionPending && reading.RecordedAt.Sub(current.StartedAt) >= grace {
+				current.Status = repository.ExcursionConfirmed
+				confirmedAt := reading.RecordedAt
+				current.ConfirmedAt = &confirmedAt
+				confirmed = append(confirmed, current)
+			}
+			continue
+		}
+
+		if current == nil {
+			continue
+		}
+		// The cabinet was out of range until this reading showed otherwise
+		endedAt := reading.RecordedAt
+		current.EndedAt = &endedAt
+		current.DurationSeconds = int(endedAt.Sub(current.StartedAt) / time.Second)
+		if current.Status == repository.ExcursionPending {
+			if endedAt.Sub(current.StartedAt) >= grace {
+				current.Status = repository.ExcursionConfirmed
+				current.ConfirmedAt = &endedAt
+				confirmed = append(confirmed, current)
+			} else {
+				current.Status = repository.ExcursionTransient
+			}
+		}
+		touched(current)
+		current = nil
+	}
+	return changed, confirmed
+}
+
+// Excursion decisions
+
+// ListExcursions lists excursions filtered, sorted and paginated by q
+func (s *ColdChainService) ListExcursions(ctx context.Context, q *database.ListQuery) (*database.Page[*repository.TemperatureExcursion], error) {
+	return s.excursionRepo.List(ctx, q)
+}
+
+// GetExcursion gets an excursion with its quarantined batches
+func (s *ColdChainService) GetExcursion(ctx context.Context, id string) (*repository.TemperatureExcursion, error) {
+	return s.excursionRepo.GetByID(ctx, id)
+}
+
+// DecideExcursion documents the assessment of a confirmed, ended excursion.
+// Releasing returns its quarantined batches to stock; discarding keeps them
+// in quarantine until they are booked out. Either decision resolves the
+// excursion's alert.
+func (s *ColdChainService) DecideExcursion(ctx context.Context, id, decision, reason, userID string) (*repository.TemperatureExcursion, error) {
+	if userID == "" {
+		return nil, errors.Unauthorized("errors.auth.missing_authorization")
+	}
+
+	var alertID *string
+	err := s.excursionRepo.Transaction(ctx, func(ctx context.Context) error {
+		e, err := s.excursionRepo.Lock(ctx, id)
+		if err != nil {
+			return err
+		}
+		if e.EndedAt == nil {
+			return errors.Conflict("errors.temperature.excursion_ongoing")
+		}
+		if e.Status != repository.ExcursionConfirmed {
+			return errors.Conflict("errors.temperature.excursion_not_confirmed", map[string]string{"status": e.Status})
+		}
+
+		e.Status = decision
+		e.DecisionReason = &reason
+		e.DecidedBy = &userID
+		if err := s.excursionRepo.Decide(ctx, e); err != nil {
+			return err
+		}
+		alertID = e.AlertID
+
+		batchIDs := make([]string, 0, len(e.Batches))
+		for _, b := range e.Batches {
+			batchIDs = append(batchIDs, b.BatchID)
+		}
+		return s.auditService.RecordAction(ctx, "temperature_excursion", id, decision, map[string]interface{}{
+			"reason":    reason,
+			"batch_ids": batchIDs,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if alertID != nil {
+		if err := s.alertRepo.Resolve(ctx, *alertID, userID); err != nil && !errors.Is(err, errors.ErrNotFound) {
+			s.logger.Warn().Err(err).Str("excursion_id", id).Msg("failed to resolve excursion alert")
+		}
+	}
+	return s.excursionRepo.GetByID(ctx, id)
+}
+
+// Daily summaries
+
+// DailyTemperatureSummary aggregates the readings of a cabinet on one day
+// (Europe/Berlin)
+type DailyTemperatureSummary struct {
+	Date               string  `json:"date"`
+	ReadingCount       int     `json:"reading_count"`
+	MinCelsius         float64 `json:"min_celsius"`
+	MaxCelsius         float64 `json:"max_celsius"`
+	MeanCelsius        float64 `json:"mean_celsius"`
+	MeanKineticCelsius float64 `json:"mean_kinetic_celsius"`
+	ExcursionReadings  int     `json:"excursion_readings"`
+}
+
+// DailySummaries returns per-day statistics including the mean kinetic
+// temperature for the days from..to (inclusive) that have readings
+func (s *ColdChainService) DailySummaries(ctx context.Context, cabinetID string, from, to time.Time) ([]*DailyTemperatureSummary, error) {
+	if _, err := s.locationRepo.GetCabinet(ctx, cabinetID); err != nil {
+		return nil, err
+	}
+
+	loc := berlin()
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+	end := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, 1)
+
+	readings, err := s.temperatureRepo.ListBetween(ctx, cabinetID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return summarizeDays(readings, loc), nil
+}
+
+// summarizeDays groups readings (oldest first) by local day
+func summarizeDays(readings []*repository.TemperatureReading, loc *time.Location) []*DailyTemperatureSummary {
+	summaries := []*DailyTemperatureSummary{}
+	var day *DailyTemperatureSummary
+	var temps []float64
+	flush := func() {
+		if day == nil {
+			return
+		}
+		sum := 0.0
+		for _, t := range temps {
+			sum += t
+		}
+		day.MeanCelsius = round2(sum / float64(len(temps)))
+		day.MeanKineticCelsius = round2(MeanKineticTemperature(temps))
+		summaries = append(summaries, day)
+	}
+
+	for _, reading := range readings {
+		date := reading.RecordedAt.In(loc).Format("2006-01-02")
+		if day == nil || day.Date != date {
+			flush()
+			day = &DailyTemperatureSummary{
+				Date:       date,
+				MinCelsius: reading.TemperatureCelsius,
+				MaxCelsius: reading.TemperatureCelsius,
+			}
+			temps = temps[:0]
+		}
+		t := reading.TemperatureCelsius
+		temps = append(temps, t)
+		day.ReadingCount++
+		day.MinCelsius = math.Min(day.MinCelsius, t)
+		day.MaxCelsius = math.Max(day.MaxCelsius, t)
+		if reading.IsExcursion {
+			day.ExcursionReadings++
+		}
+	}
+	flush()
+	return summaries
+}
+
+// MeanKineticTemperature returns the mean kinetic temperature in °C of
+// equally spaced readings (Haynes formula, ΔH/R = 10000 K). It weighs
+// warm periods more than the arithmetic mean, as degradation does.
+func MeanKineticTemperature(celsius []float64) float64 {
+	if len(celsius) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, t := range celsius {
+		sum += math.Exp(-mktActivationEnergy / (t + 273.15))
+	}
+	return mktActivationEnergy/-math.Log(sum/float64(len(celsius))) - 273.15
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// berlin returns the practice time zone
+func berlin() *time.Location {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
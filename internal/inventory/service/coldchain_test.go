@@ -0,0 +1,221 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/internal/inventory/service"
+	"github.com/medflow/medflow-backend/pkg/hmacsig"
+	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeanKineticTemperature(t *testing.T) {
+	assert.InDelta(t, 5.0, service.MeanKineticTemperature([]float64{5, 5, 5}), 1e-9)
+
+	// Warm periods weigh more than in the arithmetic mean (5 °C)
+	mkt := service.MeanKineticTemperature([]float64{2, 2, 2, 14})
+	assert.Greater(t, mkt, 5.0)
+	assert.InDelta(t, 6.91, mkt, 0.01)
+}
+
+func TestColdChainService_ExcursionLifecycle(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tenant := suite.SetupInventoryTenant(t, ctx, "cold-chain-excursions")
+	tenantCtx := suite.TenantContext(tenant)
+
+	log := logger.New("test", "test")
+	locationRepo := repository.NewLocationRepository(suite.DB)
+	itemRepo := repository.NewItemRepository(suite.DB)
+	batchRepo := repository.NewBatchRepository(suite.DB)
+	auditService := service.NewAuditService(repository.NewAuditTrailRepository(suite.DB), log)
+	svc := service.NewColdChainService(repository.NewSensorRepository(suite.DB), repository.NewExcursionRepository(suite.DB),
+		repository.NewTemperatureRepository(suite.DB), locationRepo, repository.NewAlertRepository(suite.DB), auditService, nil, "test-seal-key", log)
+
+	room := &repository.StorageRoom{Name: "Labor", IsActive: true}
+	require.NoError(t, locationRepo.CreateRoom(tenantCtx, room))
+	minT, maxT := 2.0, 8.0
+	cabinet := &repository.StorageCabinet{
+		RoomID: room.ID, Name: "Impfstoffkühlschrank", IsActive: true, TemperatureControlled: true,
+		MinTemperature: &minT, MaxTemperature: &maxT, TemperatureMonitoringEnabled: true,
+	}
+	require.NoError(t, locationRepo.CreateCabinet(tenantCtx, cabinet))
+
+	cooled := &repository.InventoryItem{Name: "Influenza-Impfstoff", Unit: "Stück", RequiresCooling: true, IsActive: true}
+	require.NoError(t, itemRepo.Create(tenantCtx, cooled))
+	ambient := &repository.InventoryItem{Name: "Kanülen", Unit: "Stück", IsActive: true}
+	require.NoError(t, itemRepo.Create(tenantCtx, ambient))
+	newBatch := func(item *repository.InventoryItem, number string) *repository.InventoryBatch {
+		b := &repository.InventoryBatch{
+			ItemID: item.ID, BatchNumber: number, InitialQuantity: 10, CurrentQuantity: 10,
+			LocationID: &cabinet.ID, ReceivedDate: time.Now().UTC(), Status: "available",
+		}
+		require.NoError(t, batchRepo.Create(tenantCtx, b))
+		return b
+	}
+	vaccine := newBatch(cooled, "FLU-1")
+	needles := newBatch(ambient, "KAN-1")
+
+	sensor, err := svc.CreateSensor(tenantCtx, cabinet.ID, "Logger 1", "")
+	require.NoError(t, err)
+	require.NotEmpty(t, sensor.Secret)
+
+	// A signed request resolves the tenant from the sensor key
+	body := []byte(`{"readings":[]}`)
+	authCtx, authed, err := svc.AuthenticateSensor(ctx, sensor.KeyID, hmacsig.Sign(sensor.Secret, time.Now(), body), body)
+	require.NoError(t, err)
+	assert.Equal(t, cabinet.ID, authed.CabinetID)
+	_, _, err = svc.AuthenticateSensor(ctx, sensor.KeyID, hmacsig.Sign("wrong", time.Now(), body), body)
+	assert.Error(t, err)
+
+	src := service.ReadingSource{Source: "sensor", SensorID: &sensor.ID}
+	base := time.Now().UTC().Add(-3 * time.Hour).Truncate(time.Minute)
+	at := func(minutes int, celsius float64) service.ReadingInput {
+		return service.ReadingInput{RecordedAt: base.Add(time.Duration(minutes) * time.Minute), TemperatureCelsius: celsius}
+	}
+
+	// Door opened for five minutes: a transient excursion, stock untouched
+	result, err := svc.Ingest(authCtx, cabinet.ID, src, []service.ReadingInput{at(0, 5), at(5, 11), at(10, 6)})
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Accepted)
+	require.Len(t, result.Excursions, 1)
+	assert.Equal(t, repository.ExcursionTransient, result.Excursions[0].Status)
+	assert.Equal(t, 300, result.Excursions[0].DurationSeconds)
+
+	// Re-sent buffer is deduplicated
+	result, err = svc.Ingest(authCtx, cabinet.ID, src, []service.ReadingInput{at(5, 11), at(10, 6)})
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Accepted)
+	assert.Equal(t, 2, result.Duplicates)
+
+	// Compressor failure: out of range beyond the 15 minute grace period
+	result, err = svc.Ingest(authCtx, cabinet.ID, src, []service.ReadingInput{at(20, 9.5), at(30, 12), at(40, 13.5)})
+	require.NoError(t, err)
+	require.Len(t, result.Excursions, 1)
+	excursion := result.Excursions[0]
+	assert.Equal(t, repository.ExcursionConfirmed, excursion.Status)
+	assert.Nil(t, excursion.EndedAt)
+	require.NotNil(t, excursion.AlertID)
+
+	b, err := batchRepo.GetByID(tenantCtx, vaccine.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "quarantine", b.Status)
+	b, err = batchRepo.GetByID(tenantCtx, needles.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "available", b.Status)
+
+	// No decision while the cabinet is still out of range
+	_, err = svc.DecideExcursion(tenantCtx, excursion.ID, repository.ExcursionReleased, "Stabilitätsdaten", "00000000-0000-0000-0000-000000000001")
+	assert.Error(t, err)
+
+	_, err = svc.Ingest(authCtx, cabinet.ID, src, []service.ReadingInput{at(50, 7)})
+	require.NoError(t, err)
+
+	decided, err := svc.DecideExcursion(tenantCtx, excursion.ID, repository.ExcursionReleased,
+		"Herstellerauskunft: bis 25 °C für 24 h stabil", "00000000-0000-0000-0000-000000000001")
+	require.NoError(t, err)
+	assert.Equal(t, repository.ExcursionReleased, decided.Status)
+	assert.Equal(t, 1800, decided.DurationSeconds)
+	require.Len(t, decided.Batches, 1)
+	assert.Equal(t, "available", decided.Batches[0].Status)
+
+	days, err := svc.DailySummaries(tenantCtx, cabinet.ID, base.AddDate(0, 0, -1), base.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	count, excursions := 0, 0
+	for _, d := range days {
+		count += d.ReadingCount
+		excursions += d.ExcursionReadings
+	}
+	assert.Equal(t, 7, count)
+	assert.Equal(t, 4, excursions)
+}
+
+func TestColdChainService_LateAndOverdueExcursions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tenant := suite.SetupInventoryTenant(t, ctx, "cold-chain-late-readings")
+	tenantCtx := suite.TenantContext(tenant)
+
+	log := logger.New("test", "test")
+	locationRepo := repository.NewLocationRepository(suite.DB)
+	itemRepo := repository.NewItemRepository(suite.DB)
+	batchRepo := repository.NewBatchRepository(suite.DB)
+	auditService := service.NewAuditService(repository.NewAuditTrailRepository(suite.DB), log)
+	svc := service.NewColdChainService(repository.NewSensorRepository(suite.DB), repository.NewExcursionRepository(suite.DB),
+		repository.NewTemperatureRepository(suite.DB), locationRepo, repository.NewAlertRepository(suite.DB), auditService, nil, "test-seal-key", log)
+
+	room := &repository.StorageRoom{Name: "Labor", IsActive: true}
+	require.NoError(t, locationRepo.CreateRoom(tenantCtx, room))
+	minT, maxT := 2.0, 8.0
+	cabinet := &repository.StorageCabinet{
+		RoomID: room.ID, Name: "Medikamentenkühlschrank", IsActive: true, TemperatureControlled: true,
+		MinTemperature: &minT, MaxTemperature: &maxT, TemperatureMonitoringEnabled: true,
+	}
+	require.NoError(t, locationRepo.CreateCabinet(tenantCtx, cabinet))
+
+	cooled := &repository.InventoryItem{Name: "Insulin", Unit: "Stück", RequiresCooling: true, IsActive: true}
+	require.NoError(t, itemRepo.Create(tenantCtx, cooled))
+	insulin := &repository.InventoryBatch{
+		ItemID: cooled.ID, BatchNumber: "INS-1", InitialQuantity: 10, CurrentQuantity: 10,
+		LocationID: &cabinet.ID, ReceivedDate: time.Now().UTC(), Status: "available",
+	}
+	require.NoError(t, batchRepo.Create(tenantCtx, insulin))
+
+	src := service.ReadingSource{Source: "manual"}
+	base := time.Now().UTC().Add(-3 * time.Hour).Truncate(time.Minute)
+	at := func(minutes int, celsius float64) service.ReadingInput {
+		return service.ReadingInput{RecordedAt: base.Add(time.Duration(minutes) * time.Minute), TemperatureCelsius: celsius}
+	}
+
+	result, err := svc.Ingest(tenantCtx, cabinet.ID, src, []service.ReadingInput{at(0, 5), at(30, 6)})
+	require.NoError(t, err)
+	assert.Empty(t, result.Excursions)
+
+	// The logger's buffer arrives after the newer readings: the cabinet was
+	// out of range for 25 minutes in between
+	result, err = svc.Ingest(tenantCtx, cabinet.ID, src, []service.ReadingInput{at(5, 12), at(15, 12), at(25, 11)})
+	require.NoError(t, err)
+	require.Len(t, result.Excursions, 1)
+	late := result.Excursions[0]
+	assert.Equal(t, repository.ExcursionConfirmed, late.Status)
+	assert.True(t, base.Add(5*time.Minute).Equal(late.StartedAt))
+	require.NotNil(t, late.EndedAt)
+	assert.True(t, base.Add(30*time.Minute).Equal(*late.EndedAt))
+	assert.Equal(t, 3, late.ReadingCount)
+	require.NotNil(t, late.AlertID)
+
+	b, err := batchRepo.GetByID(tenantCtx, insulin.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "quarantine", b.Status)
+
+	// An earlier in-range reading does not undo the confirmation
+	_, err = svc.Ingest(tenantCtx, cabinet.ID, src, []service.ReadingInput{at(20, 7)})
+	require.NoError(t, err)
+	stored, err := svc.GetExcursion(tenantCtx, late.ID)
+	require.NoError(t, err)
+	assert.Equal(t, repository.ExcursionConfirmed, stored.Status)
+
+	// The logger goes silent in a warm cabinet
+	result, err = svc.Ingest(tenantCtx, cabinet.ID, src, []service.ReadingInput{at(40, 14)})
+	require.NoError(t, err)
+	require.Len(t, result.Excursions, 1)
+	silent := result.Excursions[0]
+	assert.Equal(t, repository.ExcursionPending, silent.Status)
+
+	require.NoError(t, svc.ConfirmOverdueExcursionsJob(tenantCtx))
+	stored, err = svc.GetExcursion(tenantCtx, silent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, repository.ExcursionConfirmed, stored.Status)
+	assert.Nil(t, stored.EndedAt)
+	require.NotNil(t, stored.ConfirmedAt)
+	assert.True(t, base.Add(55*time.Minute).Equal(*stored.ConfirmedAt))
+	assert.NotNil(t, stored.AlertID)
+}
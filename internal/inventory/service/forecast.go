@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/forecast"
+	"github.com/medflow/medflow-backend/pkg/logger"
+)
+
+// Consumption history used for forecasts
+const (
+	DefaultForecastHistoryDays = 90
+	MaxForecastHistoryDays     = 365
+)
+
+// ForecastService aggregates consumption and forecasts demand to suggest
+// reorder points and quantities
+type ForecastService struct {
+	forecastRepo *repository.ForecastRepository
+	itemRepo     *repository.ItemRepository
+	batchRepo    *repository.BatchRepository
+	auditService *AuditService
+	logger       *logger.Logger
+}
+
+// NewForecastService creates a new forecast service
+func NewForecastService(
+	forecastRepo *repository.ForecastRepository,
+	itemRepo *repository.ItemRepository,
+	batchRepo *repository.BatchRepository,
+	auditService *AuditService,
+	log *logger.Logger,
+) *ForecastService {
+	return &ForecastService{
+		forecastRepo: forecastRepo,
+		itemRepo:     itemRepo,
+		batchRepo:    batchRepo,
+		auditService: auditService,
+		logger:       log,
+	}
+}
+
+// ForecastInput overrides the forecast defaults
+type ForecastInput struct {
+	HistoryDays  *int     `json:"history_days" validate:"omitempty,gte=7,lte=365"`
+	ServiceLevel *float64 `json:"service_level" validate:"omitempty,gte=0.5,lt=1"`
+	CoverageDays *int     `json:"coverage_days" validate:"omitempty,gt=0,lte=365"`
+}
+
+// settings returns the forecast settings and history length of the input
+func (input *ForecastInput) settings() (forecast.Settings, int) {
+	settings := forecast.DefaultSettings()
+	historyDays := DefaultForecastHistoryDays
+	if input == nil {
+		return settings, historyDays
+	}
+	if input.HistoryDays != nil {
+		historyDays = *input.HistoryDays
+	}
+	if input.ServiceLevel != nil {
+		settings.ServiceLevel = *input.ServiceLevel
+	}
+	if input.CoverageDays != nil {
+		settings.CoverageDays = *input.CoverageDays
+	}
+	return settings, historyDays
+}
+
+// ConsumptionHistory returns the consumption of an item on each of the last
+// days, today included, with zero for days without consumption
+func (s *ForecastService) ConsumptionHistory(ctx context.Context, itemID string, days int) ([]*repository.DailyConsumption, error) {
+	if days <= 0 || days > MaxForecastHistoryDays {
+		return nil, errors.Validation(nil).WithDetail("days", "validation.range", map[string]string{
+			"field": "days", "min": "1", "max": "365",
+		})
+	}
+	if _, err := s.itemRepo.GetByID(ctx, itemID); err != nil {
+		return nil, err
+	}
+
+	start := forecast.Day(time.Now()).AddDate(0, 0, 1-days)
+	series, err := s.series(ctx, itemID, start, days)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]*repository.DailyConsumption, days)
+	for i, q := range series {
+		history[i] = &repository.DailyConsumption{Day: start.AddDate(0, 0, i), Quantity: int(q)}
+	}
+	return history, nil
+}
+
+// series returns the consumption on each of n days from start
+func (s *ForecastService) series(ctx context.Context, itemID string, start time.Time, n int) ([]float64, error) {
+	days, err := s.forecastRepo.DailyConsumption(ctx, itemID, start)
+	if err != nil {
+		return nil, err
+	}
+
+	series := make([]float64, n)
+	for _, d := range days {
+		if i := int(forecast.Day(d.Day).Sub(start).Hours() / 24); i >= 0 && i < n {
+			series[i] = float64(d.Quantity)
+		}
+	}
+	return series, nil
+}
+
+// compute forecasts the demand of an item from its consumption up to
+// yesterday. History before the item was created is left out so a new item
+// is not forecast from days it did not exist.
+func (s *ForecastService) compute(ctx context.Context, item *repository.InventoryItem, settings forecast.Settings, historyDays int) (*repository.ConsumptionForecast, error) {
+	today := forecast.Day(time.Now())
+	if age := int(today.Sub(forecast.Day(item.CreatedAt)).Hours() / 24); age < historyDays {
+		historyDays = max(age, 0)
+	}
+	start := today.AddDate(0, 0, -historyDays)
+
+	series, err := s.series(ctx, item.ID, start, historyDays)
+	if err != nil {
+		return nil, err
+	}
+
+	// The supplier's lead time, if one is linked and has it set
+	leadTime, err := s.forecastRepo.LeadTimeDays(ctx, item.ID)
+	if err != nil {
+		return nil, err
+	}
+	if leadTime != nil && *leadTime > 0 {
+		settings.LeadTimeDays = *leadTime
+	}
+
+	stock, err := s.batchRepo.GetTotalStock(ctx, item.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	f := forecast.Compute(series, start, stock, settings)
+	return &repository.ConsumptionForecast{
+		ItemID:                   item.ID,
+		HistoryDays:              f.HistoryDays,
+		DailyDemand:              f.Level,
+		WeekdayFactors:           f.WeekdayFactors[:],
+		StdDev:                   f.StdDev,
+		LeadTimeDays:             settings.LeadTimeDays,
+		ServiceLevel:             settings.ServiceLevel,
+		CoverageDays:             settings.CoverageDays,
+		LeadTimeDemand:           f.LeadTimeDemand,
+		SafetyStock:              f.SafetyStock,
+		SuggestedReorderPoint:    f.ReorderPoint,
+		SuggestedReorderQuantity: f.ReorderQuantity,
+		CurrentStock:             stock,
+		ProjectedStockoutDate:    f.StockOutDate,
+		ComputedAt:               time.Now(),
+		ItemName:                 item.Name,
+		Unit:                     item.Unit,
+		ReorderPoint:             item.ReorderPoint,
+		ReorderQuantity:          item.ReorderQuantity,
+		MinStock:                 item.MinStock,
+	}, nil
+}
+
+// Forecast forecasts the demand of an item without storing it, e.g. to try
+// another service level
+func (s *ForecastService) Forecast(ctx context.Context, itemID string, input *ForecastInput) (*repository.ConsumptionForecast, error) {
+	item, err := s.itemRepo.GetByID(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, historyDays := input.settings()
+	return s.compute(ctx, item, settings, historyDays)
+}
+
+// RecalculateResult summarizes a forecast run
+type RecalculateResult struct {
+	Items     int `json:"items"`
+	Failed    int `json:"failed"`
+	StockOuts int `json:"stock_outs"` // items projected to run out within the horizon
+}
+
+// Recalculate forecasts and stores the demand of every active item
+func (s *ForecastService) Recalculate(ctx context.Context, input *ForecastInput) (*RecalculateResult, error) {
+	items, err := s.itemRepo.GetAllActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, historyDays := input.settings()
+	result := &RecalculateResult{}
+	for _, item := range items {
+		f, err := s.compute(ctx, item, settings, historyDays)
+		if err == nil {
+			err = s.forecastRepo.Upsert(ctx, f)
+		}
+		if err != nil {
+			s.logger.Warn().Err(err).Str("item_id", item.ID).Msg("failed to forecast item consumption")
+			result.Failed++
+			continue
+		}
+		result.Items++
+		if f.ProjectedStockoutDate != nil {
+			result.StockOuts++
+		}
+	}
+
+	return result, nil
+}
+
+// RecalculateJob refreshes the stored forecasts with the default settings
+func (s *ForecastService) RecalculateJob(ctx context.Context) error {
+	result, err := s.Recalculate(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info().
+		Int("items", result.Items).
+		Int("failed", result.Failed).
+		Int("stock_outs", result.StockOuts).
+		Msg("recalculated consumption forecasts")
+	return nil
+}
+
+// List lists stored forecasts filtered, sorted and paginated by q
+func (s *ForecastService) List(ctx context.Context, q *database.ListQuery) (*database.Page[*repository.ConsumptionForecast], error) {
+	return s.forecastRepo.List(ctx, q)
+}
+
+// AcceptInput selects the items whose suggested reorder values to accept
+type AcceptInput struct {
+	ItemIDs []string `json:"item_ids" validate:"required,min=1,max=500,dive,uuid"`
+}
+
+// Accept sets the reorder point and quantity of the items to the values
+// their stored forecasts suggest
+func (s *ForecastService) Accept(ctx context.Context, input *AcceptInput, userID string) ([]*repository.ConsumptionForecast, error) {
+	var acceptedBy *string
+	if userID != "" {
+		acceptedBy = &userID
+	}
+
+	accepted, err := s.forecastRepo.Accept(ctx, input.ItemIDs, acceptedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range accepted {
+		s.auditService.RecordUpdate(ctx, "item", f.ItemID, map[string]interface{}{
+			"reorder_point":    f.SuggestedReorderPoint,
+			"reorder_quantity": f.SuggestedReorderQuantity,
+		}, map[string]interface{}{
+			"source":        "consumption_forecast",
+			"daily_demand":  f.DailyDemand,
+			"service_level": f.ServiceLevel,
+			"lead_time":     f.LeadTimeDays,
+		})
+	}
+
+	return accepted, nil
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/gdpdu"
+	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/medflow/medflow-backend/pkg/storage"
+	"github.com/medflow/medflow-backend/pkg/tenant"
+)
+
+// gdpduBuildTimeout bounds the statements that read the data of one export
+const gdpduBuildTimeout = 30 * time.Minute
+
+// GDPdUExportService builds the data exports (Datenüberlassung) requested
+// for tax audits in the GDPdU format: CSV tables described by index.xml,
+// packaged as a ZIP
+type GDPdUExportService struct {
+	repo         *repository.GDPdUExportRepository
+	documents    *storage.Documents
+	auditService *AuditService
+	logger       *logger.Logger
+}
+
+// NewGDPdUExportService creates a new GDPdU export service
+func NewGDPdUExportService(repo *repository.GDPdUExportRepository, documents *storage.Documents, auditService *AuditService, log *logger.Logger) *GDPdUExportService {
+	return &GDPdUExportService{
+		repo:         repo,
+		documents:    documents,
+		auditService: auditService,
+		logger:       log,
+	}
+}
+
+// RequestGDPdUExportInput is the input for requesting an export
+type RequestGDPdUExportInput struct {
+	From string `json:"from" validate:"required"` // YYYY-MM-DD
+	To   string `json:"to" validate:"required"`   // YYYY-MM-DD, inclusive
+}
+
+// Request queues an export of the period; the inventory.gdpdu_exports job
+// builds it
+func (s *GDPdUExportService) Request(ctx context.Context, input *RequestGDPdUExportInput, userID, userName string) (*repository.GDPdUExport, error) {
+	from, err := time.Parse("2006-01-02", input.From)
+	if err != nil {
+		return nil, errors.BadRequest("errors.invalid_date", map[string]string{"field": "from"})
+	}
+	to, err := time.Parse("2006-01-02", input.To)
+	if err != nil {
+		return nil, errors.BadRequest("errors.invalid_date", map[string]string{"field": "to"})
+	}
+	if to.Before(from) {
+		return nil, errors.BadRequest("errors.gdpdu_export.invalid_period")
+	}
+
+	e := &repository.GDPdUExport{PeriodFrom: from, PeriodTo: to}
+	if userID != "" {
+		e.RequestedBy = &userID
+	}
+	if userName != "" {
+		e.RequestedByName = &userName
+	}
+
+	if err := s.repo.Create(ctx, e); err != nil {
+		return nil, err
+	}
+
+	s.auditService.RecordAction(ctx, "gdpdu_export", e.ID, "request", map[string]interface{}{
+		"period_from": input.From,
+		"period_to":   input.To,
+	})
+
+	return e, nil
+}
+
+// Get gets an export by ID
+func (s *GDPdUExportService) Get(ctx context.Context, id string) (*repository.GDPdUExport, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// List lists exports filtered, sorted and paginated by q
+func (s *GDPdUExportService) List(ctx context.Context, q *database.ListQuery) (*database.Page[*repository.GDPdUExport], error) {
+	return s.repo.List(ctx, q)
+}
+
+// DownloadURL signs a download URL for a completed export. Downloads are
+// audited, as the export hands tax-relevant data to a third party.
+func (s *GDPdUExportService) DownloadURL(ctx context.Context, id string) (*storage.DownloadURL, error) {
+	e, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if e.Status != repository.GDPdUExportCompleted || e.FileKey == nil {
+		return nil, errors.Conflict("errors.gdpdu_export.not_ready", map[string]string{"status": e.Status})
+	}
+
+	filename := fmt.Sprintf("gdpdu_%s_%s.zip", e.PeriodFrom.Format("2006-01-02"), e.PeriodTo.Format("2006-01-02"))
+	link, err := s.documents.DownloadURL(ctx, *e.FileKey, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditService.RecordAction(ctx, "gdpdu_export", e.ID, "download", map[string]interface{}{
+		"sha256": e.SHA256,
+	})
+
+	return link, nil
+}
+
+// ProcessPendingJob builds the tenant's pending exports one at a time
+func (s *GDPdUExportService) ProcessPendingJob(ctx context.Context) error {
+	for {
+		e, err := s.repo.ClaimNext(ctx)
+		if err != nil {
+			return err
+		}
+		if e == nil {
+			return nil
+		}
+
+		if err := s.build(ctx, e); err != nil {
+			s.logger.Error().Err(err).Str("export_id", e.ID).Msg("failed to build GDPdU export")
+			if ferr := s.repo.Fail(ctx, e.ID, err.Error()); ferr != nil {
+				s.logger.Error().Err(ferr).Str("export_id", e.ID).Msg("failed to mark GDPdU export as failed")
+			}
+			return err
+		}
+
+		s.logger.Info().
+			Str("export_id", e.ID).
+			Int64("records", *e.RecordCount).
+			Int64("size", *e.FileSize).
+			Msg("built GDPdU export")
+	}
+}
+
+// build writes the export's ZIP to a temporary file, stores it and records
+// the result
+func (s *GDPdUExportService) build(ctx context.Context, e *repository.GDPdUExport) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp("", "gdpdu-*.zip")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	hash := sha256.New()
+	out := &countingWriter{w: io.MultiWriter(f, hash)}
+	zw := zip.NewWriter(out)
+
+	supplier, err := s.repo.DataSupplier(ctx)
+	if err != nil {
+		return err
+	}
+	supplier.Comment = fmt.Sprintf("MedFlow Inventar, Zeitraum %s – %s",
+		e.PeriodFrom.Format("02.01.2006"), e.PeriodTo.Format("02.01.2006"))
+
+	w := gdpdu.NewWriter(zw, supplier)
+	if err := s.repo.WriteTables(database.WithStatementTimeout(ctx, gdpduBuildTimeout), e, w); err != nil {
+		return err
+	}
+	manifest, err := w.Close()
+	if err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	obj := storage.Object{
+		Key:         storage.NewKey(tenantID, "inventory/exports", e.ID, ".zip"),
+		ContentType: "application/zip",
+		Size:        out.n,
+		SHA256:      hex.EncodeToString(hash.Sum(nil)),
+	}
+	if err := s.documents.Put(ctx, obj, f); err != nil {
+		return err
+	}
+
+	manifestJSON, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	records := manifest.Records()
+	manifestStr := string(manifestJSON)
+	e.FileKey = &obj.Key
+	e.FileSize = &obj.Size
+	e.SHA256 = &obj.SHA256
+	e.RecordCount = &records
+	e.Manifest = &manifestStr
+
+	if err := s.repo.Complete(ctx, e); err != nil {
+		s.documents.Delete(ctx, obj.Key)
+		return err
+	}
+	return nil
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
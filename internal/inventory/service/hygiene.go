@@ -5,21 +5,24 @@ import (
 
 	"github.com/medflow/medflow-backend/internal/inventory/repository"
 	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/medflow/medflow-backend/pkg/retention"
 )
 
 // HygieneService handles hygiene plan and inspection business logic (IfSG compliance)
 type HygieneService struct {
-	hygieneRepo  *repository.HygieneRepository
-	auditService *AuditService
-	logger       *logger.Logger
+	hygieneRepo    *repository.HygieneRepository
+	retentionGuard *retention.Guard
+	auditService   *AuditService
+	logger         *logger.Logger
 }
 
 // NewHygieneService creates a new hygiene service
-func NewHygieneService(hygieneRepo *repository.HygieneRepository, auditService *AuditService, log *logger.Logger) *HygieneService {
+func NewHygieneService(hygieneRepo *repository.HygieneRepository, retentionGuard *retention.Guard, auditService *AuditService, log *logger.Logger) *HygieneService {
 	return &HygieneService{
-		hygieneRepo:  hygieneRepo,
-		auditService: auditService,
-		logger:       log,
+		hygieneRepo:    hygieneRepo,
+		retentionGuard: retentionGuard,
+		auditService:   auditService,
+		logger:         log,
 	}
 }
 
@@ -69,6 +72,9 @@ func (s *HygieneService) UpdatePlan(ctx context.Context, plan *repository.Hygien
 
 // DeletePlan soft-deletes a hygiene plan
 func (s *HygieneService) DeletePlan(ctx context.Context, id string) error {
+	if err := s.retentionGuard.Check(ctx, id, RetentionHygienePlan); err != nil {
+		return err
+	}
 	if err := s.hygieneRepo.DeletePlan(ctx, id); err != nil {
 		return err
 	}
@@ -120,6 +126,9 @@ func (s *HygieneService) UpdateInspection(ctx context.Context, inspection *repos
 
 // DeleteInspection soft-deletes a hygiene inspection
 func (s *HygieneService) DeleteInspection(ctx context.Context, id string) error {
+	if err := s.retentionGuard.Check(ctx, id, RetentionHygieneInspection); err != nil {
+		return err
+	}
 	if err := s.hygieneRepo.DeleteInspection(ctx, id); err != nil {
 		return err
 	}
@@ -8,8 +8,10 @@ import (
 
 	"github.com/medflow/medflow-backend/internal/inventory/events"
 	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/pkg/database"
 	apperrors "github.com/medflow/medflow-backend/pkg/errors"
 	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/medflow/medflow-backend/pkg/retention"
 )
 
 // InventoryService handles inventory business logic
@@ -24,6 +26,9 @@ type InventoryService struct {
 	inspectionRepo  *repository.InspectionRepository
 	trainingRepo    *repository.TrainingRepository
 	incidentRepo    *repository.IncidentRepository
+	forecastRepo    *repository.ForecastRepository
+	retentionGuard  *retention.Guard
+	coldChain       *ColdChainService
 	publisher       *events.InventoryEventPublisher
 	logger          *logger.Logger
 }
@@ -59,14 +64,31 @@ func NewInventoryService(
 	}
 }
 
+// SetForecastRepository enables projected stock-outs on the dashboard
+func (s *InventoryService) SetForecastRepository(repo *repository.ForecastRepository) {
+	s.forecastRepo = repo
+}
+
+// SetRetentionGuard rejects deletes of records under retention or legal hold
+func (s *InventoryService) SetRetentionGuard(guard *retention.Guard) {
+	s.retentionGuard = guard
+}
+
+// SetColdChain routes temperature readings through excursion tracking
+func (s *InventoryService) SetColdChain(coldChain *ColdChainService) {
+	s.coldChain = coldChain
+}
+
 // ItemWithBatches represents an item with its batches
 type ItemWithBatches struct {
 	*repository.InventoryItem
-	Batches       []*repository.InventoryBatch `json:"batches"`
-	TotalStock    int                          `json:"total_stock"`
-	Status        string                       `json:"status"`
-	NearestExpiry *time.Time                   `json:"nearest_expiry,omitempty"`
-	ExpiryStatus  string                       `json:"expiry_status,omitempty"`
+	Batches        []*repository.InventoryBatch `json:"batches"`
+	TotalStock     int                          `json:"total_stock"`
+	ReservedStock  int                          `json:"reserved_stock"`
+	AvailableStock int                          `json:"available_stock"` // total minus reserved
+	Status         string                       `json:"status"`
+	NearestExpiry  *time.Time                   `json:"nearest_expiry,omitempty"`
+	ExpiryStatus   string                       `json:"expiry_status,omitempty"`
 }
 
 // DashboardStats represents dashboard statistics
@@ -78,8 +100,26 @@ type DashboardStats struct {
 	ExpiredCount      int64  `json:"expired_count"`
 	AlertCount        int64  `json:"alert_count"`
 	CategoryBreakdown map[string]int64 `json:"category_breakdown"`
+	// Items the consumption forecast expects to run out soon, soonest first
+	ProjectedStockOuts []*StockOutProjection `json:"projected_stockouts"`
+}
+
+// StockOutProjection is an item projected to run out of stock
+type StockOutProjection struct {
+	ItemID       string    `json:"item_id"`
+	ItemName     string    `json:"item_name"`
+	Unit         string    `json:"unit"`
+	CurrentStock int       `json:"current_stock"`
+	DailyDemand  float64   `json:"daily_demand"`
+	StockOutDate time.Time `json:"stockout_date"`
 }
 
+// Projected stock-outs shown on the dashboard
+const (
+	dashboardStockOutDays  = 30
+	dashboardStockOutLimit = 10
+)
+
 // Item operations
 
 // CreateItem creates a new inventory item
@@ -103,19 +143,24 @@ func (s *InventoryService) GetItem(ctx context.Context, id string) (*ItemWithBat
 }
 
 // ListItems lists items with batches
-func (s *InventoryService) ListItems(ctx context.Context, page, perPage int, category string) ([]*ItemWithBatches, int64, error) {
-	items, total, err := s.itemRepo.List(ctx, page, perPage, category)
+func (s *InventoryService) ListItems(ctx context.Context, q *database.ListQuery) (*database.Page[*ItemWithBatches], error) {
+	page, err := s.itemRepo.List(ctx, q)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	result := make([]*ItemWithBatches, len(items))
-	for i, item := range items {
+	result := make([]*ItemWithBatches, len(page.Items))
+	for i, item := range page.Items {
 		batches, _ := s.batchRepo.ListByItem(ctx, item.ID)
 		result[i] = s.enrichItem(item, batches)
 	}
 
-	return result, total, nil
+	return &database.Page[*ItemWithBatches]{
+		Items:      result,
+		Total:      page.Total,
+		Limit:      page.Limit,
+		NextCursor: page.NextCursor,
+	}, nil
 }
 
 // UpdateItem updates an inventory item
@@ -125,6 +170,9 @@ func (s *InventoryService) UpdateItem(ctx context.Context, item *repository.Inve
 
 // DeleteItem deletes an inventory item
 func (s *InventoryService) DeleteItem(ctx context.Context, id string) error {
+	if err := s.retentionGuard.Check(ctx, id, RetentionItem); err != nil {
+		return err
+	}
 	return s.itemRepo.SoftDelete(ctx, id)
 }
 
@@ -152,6 +200,9 @@ func (s *InventoryService) UpdateBatch(ctx context.Context, batch *repository.In
 
 // DeleteBatch deletes a batch
 func (s *InventoryService) DeleteBatch(ctx context.Context, id string) error {
+	if err := s.retentionGuard.Check(ctx, id, RetentionBtMBatch); err != nil {
+		return err
+	}
 	return s.batchRepo.Delete(ctx, id)
 }
 
@@ -226,10 +277,19 @@ func (s *InventoryService) checkAndCreateAlerts(ctx context.Context, itemID stri
 			MinStock:     &item.MinStock,
 		}
 
-		s.alertRepo.Create(ctx, alert)
+		s.createAlert(ctx, alert)
 	}
 }
 
+// createAlert persists an alert and publishes it for notification routing
+func (s *InventoryService) createAlert(ctx context.Context, alert *repository.InventoryAlert) error {
+	if err := s.alertRepo.Create(ctx, alert); err != nil {
+		return err
+	}
+	s.publisher.PublishAlertGenerated(ctx, alert)
+	return nil
+}
+
 // GenerateExpiryAlerts generates alerts for expiring batches
 func (s *InventoryService) GenerateExpiryAlerts(ctx context.Context) error {
 	// Check batches expiring within 90 days
@@ -274,7 +334,7 @@ func (s *InventoryService) GenerateExpiryAlerts(ctx context.Context) error {
 			DaysUntilExpiry: &daysUntil,
 		}
 
-		s.alertRepo.Create(ctx, alert)
+		s.createAlert(ctx, alert)
 	}
 
 	return nil
@@ -339,6 +399,24 @@ func (s *InventoryService) GetDashboardStats(ctx context.Context) (*DashboardSta
 		}
 	}
 
+	stats.ProjectedStockOuts = []*StockOutProjection{}
+	if s.forecastRepo != nil {
+		forecasts, err := s.forecastRepo.ListStockOuts(ctx, now.AddDate(0, 0, dashboardStockOutDays), dashboardStockOutLimit)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range forecasts {
+			stats.ProjectedStockOuts = append(stats.ProjectedStockOuts, &StockOutProjection{
+				ItemID:       f.ItemID,
+				ItemName:     f.ItemName,
+				Unit:         f.Unit,
+				CurrentStock: f.CurrentStock,
+				DailyDemand:  f.DailyDemand,
+				StockOutDate: *f.ProjectedStockoutDate,
+			})
+		}
+	}
+
 	return stats, nil
 }
 
@@ -356,6 +434,9 @@ func (s *InventoryService) UpsertHazardousDetails(ctx context.Context, detail *r
 
 // DeleteHazardousDetails deletes hazardous substance details for an item
 func (s *InventoryService) DeleteHazardousDetails(ctx context.Context, itemID string) error {
+	if err := s.retentionGuard.Check(ctx, itemID, RetentionHazardousDetails); err != nil {
+		return err
+	}
 	return s.hazardousRepo.Delete(ctx, itemID)
 }
 
@@ -378,6 +459,9 @@ func (s *InventoryService) CreateItemDocument(ctx context.Context, doc *reposito
 
 // DeleteItemDocument deletes an item document
 func (s *InventoryService) DeleteItemDocument(ctx context.Context, id string) error {
+	if err := s.retentionGuard.Check(ctx, id, RetentionItemDocument); err != nil {
+		return err
+	}
 	return s.documentRepo.Delete(ctx, id)
 }
 
@@ -473,7 +557,7 @@ func (s *InventoryService) OpenBatch(ctx context.Context, batchID string) (*repo
 					Severity:    severity,
 					Message:     fmt.Sprintf("%s batch %s: post-opening expiry in %d days", item.Name, batch.BatchNumber, daysUntil),
 				}
-				s.alertRepo.Create(ctx, alert)
+				s.createAlert(ctx, alert)
 			}
 		}
 	}
@@ -501,50 +585,26 @@ func GetEffectiveExpiry(batch *repository.InventoryBatch, item *repository.Inven
 
 // Temperature operations
 
-// RecordTemperature records a temperature reading for a cabinet
-func (s *InventoryService) RecordTemperature(ctx context.Context, cabinetID string, tempCelsius float64, source string, recordedBy *string, notes *string) (*repository.TemperatureReading, error) {
-	// Look up cabinet to check thresholds
-	cabinet, err := s.locationRepo.GetCabinet(ctx, cabinetID)
-	if err != nil {
-		return nil, fmt.Errorf("cabinet not found: %w", err)
-	}
-
-	// Determine if this is an excursion
-	isExcursion := false
-	if cabinet.MinTemperature != nil && tempCelsius < *cabinet.MinTemperature {
-		isExcursion = true
-	}
-	if cabinet.MaxTemperature != nil && tempCelsius > *cabinet.MaxTemperature {
-		isExcursion = true
-	}
-
-	reading := &repository.TemperatureReading{
-		CabinetID:          cabinetID,
-		TemperatureCelsius: tempCelsius,
-		RecordedAt:         time.Now(),
-		RecordedBy:         recordedBy,
-		Source:             source,
-		IsExcursion:        isExcursion,
-		Notes:              notes,
+// RecordTemperature records a temperature reading for a cabinet. A zero
+// recordedAt means now. A webhook or MQTT sensor reading already recorded
+// for the same time is rejected as a conflict.
+func (s *InventoryService) RecordTemperature(ctx context.Context, cabinetID string, tempCelsius float64, recordedAt time.Time, source string, recordedBy *string, notes *string) (*repository.TemperatureReading, error) {
+	if recordedAt.IsZero() {
+		recordedAt = time.Now()
 	}
 
-	if err := s.temperatureRepo.Create(ctx, reading); err != nil {
+	result, err := s.coldChain.Ingest(ctx, cabinetID, ReadingSource{
+		Source:     source,
+		RecordedBy: recordedBy,
+		Notes:      notes,
+	}, []ReadingInput{{RecordedAt: recordedAt, TemperatureCelsius: tempCelsius}})
+	if err != nil {
 		return nil, err
 	}
-
-	// Generate alert if excursion
-	if isExcursion {
-		alert := &repository.InventoryAlert{
-			AlertType: "temperature_excursion",
-			ItemID:    cabinetID, // Using cabinet ID as reference
-			ItemName:  cabinet.Name,
-			Severity:  "critical",
-			Message:   fmt.Sprintf("Temperature excursion in %s: %.1f°C (range: %.1f-%.1f°C)", cabinet.Name, tempCelsius, derefFloat(cabinet.MinTemperature), derefFloat(cabinet.MaxTemperature)),
-		}
-		s.alertRepo.Create(ctx, alert)
+	if len(result.Readings) == 0 {
+		return nil, apperrors.Conflict("errors.temperature.duplicate_reading")
 	}
-
-	return reading, nil
+	return result.Readings[0], nil
 }
 
 // ListTemperatureReadings lists temperature readings for a cabinet
@@ -575,7 +635,7 @@ func (s *InventoryService) CheckDailyTemperatureCompliance(ctx context.Context)
 			Severity:  "warning",
 			Message:   fmt.Sprintf("No temperature reading today for %s", cabinet.Name),
 		}
-		s.alertRepo.Create(ctx, alert)
+		s.createAlert(ctx, alert)
 	}
 
 	return nil
@@ -625,6 +685,9 @@ func (s *InventoryService) UpdateInspection(ctx context.Context, insp *repositor
 
 // DeleteInspection deletes an inspection
 func (s *InventoryService) DeleteInspection(ctx context.Context, id string) error {
+	if err := s.retentionGuard.Check(ctx, id, RetentionDeviceInspection); err != nil {
+		return err
+	}
 	return s.inspectionRepo.Delete(ctx, id)
 }
 
@@ -652,6 +715,9 @@ func (s *InventoryService) UpdateTraining(ctx context.Context, tr *repository.De
 
 // DeleteTraining deletes a training
 func (s *InventoryService) DeleteTraining(ctx context.Context, id string) error {
+	if err := s.retentionGuard.Check(ctx, id, RetentionDeviceTraining); err != nil {
+		return err
+	}
 	return s.trainingRepo.Delete(ctx, id)
 }
 
@@ -679,6 +745,9 @@ func (s *InventoryService) UpdateIncident(ctx context.Context, inc *repository.D
 
 // DeleteIncident deletes an incident
 func (s *InventoryService) DeleteIncident(ctx context.Context, id string) error {
+	if err := s.retentionGuard.Check(ctx, id, RetentionDeviceIncident); err != nil {
+		return err
+	}
 	return s.incidentRepo.Delete(ctx, id)
 }
 
@@ -703,7 +772,7 @@ func (s *InventoryService) GenerateMaintenanceAlerts(ctx context.Context) error
 					Severity:  "critical",
 					Message:   fmt.Sprintf("STK overdue for %s (due: %s) - Betriebsverbot!", item.Name, item.NextStkDue.Format("2006-01-02")),
 				}
-				s.alertRepo.Create(ctx, alert)
+				s.createAlert(ctx, alert)
 			} else if item.NextStkDue.Before(soonThreshold) {
 				alert := &repository.InventoryAlert{
 					AlertType: "stk_due_soon",
@@ -712,7 +781,7 @@ func (s *InventoryService) GenerateMaintenanceAlerts(ctx context.Context) error
 					Severity:  "warning",
 					Message:   fmt.Sprintf("STK due soon for %s (due: %s)", item.Name, item.NextStkDue.Format("2006-01-02")),
 				}
-				s.alertRepo.Create(ctx, alert)
+				s.createAlert(ctx, alert)
 			}
 		}
 
@@ -726,7 +795,7 @@ func (s *InventoryService) GenerateMaintenanceAlerts(ctx context.Context) error
 					Severity:  "critical",
 					Message:   fmt.Sprintf("MTK overdue for %s (due: %s)", item.Name, item.NextMtkDue.Format("2006-01-02")),
 				}
-				s.alertRepo.Create(ctx, alert)
+				s.createAlert(ctx, alert)
 			} else if item.NextMtkDue.Before(soonThreshold) {
 				alert := &repository.InventoryAlert{
 					AlertType: "mtk_due_soon",
@@ -735,7 +804,7 @@ func (s *InventoryService) GenerateMaintenanceAlerts(ctx context.Context) error
 					Severity:  "warning",
 					Message:   fmt.Sprintf("MTK due soon for %s (due: %s)", item.Name, item.NextMtkDue.Format("2006-01-02")),
 				}
-				s.alertRepo.Create(ctx, alert)
+				s.createAlert(ctx, alert)
 			}
 		}
 	}
@@ -749,7 +818,7 @@ func (s *InventoryService) GenerateMaintenanceAlerts(ctx context.Context) error
 // Only falls back on NotFound errors; real errors (DB connection, timeout) fail fast.
 func (s *InventoryService) GetItemByBarcode(ctx context.Context, barcode string) (*ItemWithBatches, error) {
 	if barcode == "" {
-		return nil, apperrors.BadRequest("barcode is required")
+		return nil, apperrors.BadRequest("validation.required", map[string]string{"field": "barcode"})
 	}
 
 	// 1. Try barcode column
@@ -784,6 +853,15 @@ func (s *InventoryService) GetItemByBarcode(ctx context.Context, barcode string)
 		}
 	}
 
+	// 4. Fallback: decode GS1/UDI, securPharm or HIBC and try GTIN, UDI-DI and PZN
+	item, err = s.lookupDecoded(ctx, barcode)
+	if err == nil {
+		return s.enrichItemWithBatches(ctx, item)
+	}
+	if !apperrors.Is(err, apperrors.ErrNotFound) {
+		return nil, err
+	}
+
 	return nil, apperrors.NotFound("item")
 }
 
@@ -838,7 +916,9 @@ func (s *InventoryService) enrichItem(item *repository.InventoryItem, batches []
 	// Calculate total stock
 	for _, b := range batches {
 		result.TotalStock += b.Quantity
+		result.ReservedStock += b.ReservedQuantity
 	}
+	result.AvailableStock = result.TotalStock - result.ReservedStock
 
 	// Find nearest expiry (considering effective expiry for opened batches)
 	var nearestExpiry *time.Time
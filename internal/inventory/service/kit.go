@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/medflow/medflow-backend/internal/inventory/events"
+	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/logger"
+)
+
+// KitService manages procedure kits and books out their items in one go
+type KitService struct {
+	kitRepo            *repository.KitRepository
+	reservationService *ReservationService
+	auditService       *AuditService
+	publisher          *events.InventoryEventPublisher
+	logger             *logger.Logger
+}
+
+// NewKitService creates a new kit service
+func NewKitService(
+	kitRepo *repository.KitRepository,
+	reservationService *ReservationService,
+	auditService *AuditService,
+	publisher *events.InventoryEventPublisher,
+	log *logger.Logger,
+) *KitService {
+	return &KitService{
+		kitRepo:            kitRepo,
+		reservationService: reservationService,
+		auditService:       auditService,
+		publisher:          publisher,
+		logger:             log,
+	}
+}
+
+// KitItemInput is an item of a kit and the quantity one kit uses
+type KitItemInput struct {
+	ItemID   string `json:"item_id" validate:"required,uuid"`
+	Quantity int    `json:"quantity" validate:"required,gt=0"`
+}
+
+// KitInput is the input for creating or updating a kit
+type KitInput struct {
+	Name        string         `json:"name" validate:"required,max=255"`
+	Description *string        `json:"description"`
+	IsActive    *bool          `json:"is_active"`
+	Items       []KitItemInput `json:"items" validate:"dive"`
+}
+
+// kit builds a kit from the input, rejecting empty kits and repeated items
+func (input *KitInput) kit(id string) (*repository.ProcedureKit, error) {
+	if len(input.Items) == 0 {
+		return nil, errors.BadRequest("errors.kit.no_items")
+	}
+
+	kit := &repository.ProcedureKit{
+		ID:          id,
+		Name:        input.Name,
+		Description: input.Description,
+		IsActive:    true,
+	}
+	if input.IsActive != nil {
+		kit.IsActive = *input.IsActive
+	}
+
+	seen := make(map[string]bool, len(input.Items))
+	for _, item := range input.Items {
+		if seen[item.ItemID] {
+			return nil, errors.BadRequest("errors.kit.duplicate_item")
+		}
+		seen[item.ItemID] = true
+		kit.Items = append(kit.Items, &repository.ProcedureKitItem{ItemID: item.ItemID, Quantity: item.Quantity})
+	}
+	return kit, nil
+}
+
+// Create creates a kit
+func (s *KitService) Create(ctx context.Context, input *KitInput, userID string) (*repository.ProcedureKit, error) {
+	kit, err := input.kit("")
+	if err != nil {
+		return nil, err
+	}
+	if userID != "" {
+		kit.CreatedBy = &userID
+	}
+
+	if err := s.kitRepo.Create(ctx, kit); err != nil {
+		return nil, err
+	}
+
+	s.auditService.RecordCreate(ctx, "procedure_kit", kit.ID, map[string]interface{}{
+		"name":  kit.Name,
+		"items": len(kit.Items),
+	})
+
+	return s.kitRepo.GetByID(ctx, kit.ID)
+}
+
+// Get gets a kit with its items
+func (s *KitService) Get(ctx context.Context, id string) (*repository.ProcedureKit, error) {
+	return s.kitRepo.GetByID(ctx, id)
+}
+
+// List lists kits filtered, sorted and paginated by q
+func (s *KitService) List(ctx context.Context, q *database.ListQuery) (*database.Page[*repository.ProcedureKit], error) {
+	return s.kitRepo.List(ctx, q)
+}
+
+// Update replaces a kit's name, description, status and items
+func (s *KitService) Update(ctx context.Context, id string, input *KitInput) (*repository.ProcedureKit, error) {
+	kit, err := input.kit(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.kitRepo.Update(ctx, kit); err != nil {
+		return nil, err
+	}
+
+	s.auditService.RecordUpdate(ctx, "procedure_kit", id, map[string]interface{}{
+		"name":      kit.Name,
+		"is_active": kit.IsActive,
+		"items":     len(kit.Items),
+	}, nil)
+
+	return s.kitRepo.GetByID(ctx, id)
+}
+
+// Delete deletes a kit; its consumptions are kept
+func (s *KitService) Delete(ctx context.Context, id string) error {
+	if err := s.kitRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.auditService.RecordDelete(ctx, "procedure_kit", id, nil)
+	return nil
+}
+
+// KitPickList is the pick list of every item of a kit
+type KitPickList struct {
+	KitID    string      `json:"kit_id"`
+	KitName  string      `json:"kit_name"`
+	Count    int         `json:"count"`
+	Complete bool        `json:"complete"` // every item is available
+	Items    []*PickList `json:"items"`
+}
+
+// PickList suggests the batches to take every item of count kits from
+func (s *KitService) PickList(ctx context.Context, id string, count int) (*KitPickList, error) {
+	if count <= 0 {
+		return nil, errors.Validation(nil).WithDetail("count", "validation.positive", map[string]string{"field": "count"})
+	}
+
+	kit, err := s.kitRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &KitPickList{
+		KitID:    kit.ID,
+		KitName:  kit.Name,
+		Count:    count,
+		Complete: true,
+		Items:    make([]*PickList, 0, len(kit.Items)),
+	}
+	for _, item := range kit.Items {
+		list, err := s.reservationService.PickList(ctx, item.ItemID, item.Quantity*count)
+		if err != nil {
+			return nil, err
+		}
+		if list.Shortfall > 0 {
+			result.Complete = false
+		}
+		result.Items = append(result.Items, list)
+	}
+
+	return result, nil
+}
+
+// ConsumeKitInput is the input for consuming a kit
+type ConsumeKitInput struct {
+	Count         int     `json:"count" validate:"omitempty,gt=0"` // default 1
+	ReferenceType *string `json:"reference_type" validate:"omitempty,max=50"`
+	ReferenceID   *string `json:"reference_id" validate:"omitempty,uuid"`
+	Notes         *string `json:"notes"`
+}
+
+// Consume books out every item of a kit first-expiry-first-out in one
+// transaction. The batches are chosen from the locked stock by the pick list
+// rules, so stock recalled, quarantined, expired or reserved since a pick list
+// was shown is not used. If any item is short nothing is booked and the error
+// lists each missing item.
+func (s *KitService) Consume(ctx context.Context, id string, input *ConsumeKitInput, userID, userName string) (*repository.KitConsumption, error) {
+	count := input.Count
+	if count == 0 {
+		count = 1
+	}
+	if count < 0 {
+		return nil, errors.Validation(nil).WithDetail("count", "validation.positive", map[string]string{"field": "count"})
+	}
+
+	kit, err := s.kitRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !kit.IsActive {
+		return nil, errors.BadRequest("errors.kit.inactive", map[string]string{"name": kit.Name})
+	}
+
+	picks := make([]*repository.KitPick, len(kit.Items))
+	itemIDs := make([]string, len(kit.Items))
+	for i, item := range kit.Items {
+		picks[i] = &repository.KitPick{ItemID: item.ItemID, ItemName: item.ItemName, Quantity: item.Quantity * count}
+		itemIDs[i] = item.ItemID
+	}
+	order, err := s.reservationService.pickOrder(ctx, itemIDs, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	consumption := &repository.KitConsumption{
+		KitID:         kit.ID,
+		KitName:       kit.Name,
+		KitCount:      count,
+		ReferenceType: input.ReferenceType,
+		ReferenceID:   input.ReferenceID,
+		Notes:         input.Notes,
+		PerformedBy:   userID,
+	}
+	if userName != "" {
+		consumption.PerformedByName = &userName
+	}
+
+	// The batches are chosen under lock; the pick list is only a preview
+	adjustments, err := s.kitRepo.Consume(ctx, consumption, picks, order)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditService.RecordAction(ctx, "procedure_kit", kit.ID, "consume", map[string]interface{}{
+		"consumption_id": consumption.ID,
+		"count":          count,
+		"adjustments":    len(adjustments),
+	})
+
+	for _, adj := range adjustments {
+		s.publisher.PublishStockAdjusted(ctx, adj)
+	}
+
+	return s.kitRepo.GetConsumption(ctx, consumption.ID)
+}
+
+// GetConsumption gets a kit consumption with the batches it booked out
+func (s *KitService) GetConsumption(ctx context.Context, id string) (*repository.KitConsumption, error) {
+	return s.kitRepo.GetConsumption(ctx, id)
+}
+
+// ListConsumptions lists kit consumptions filtered, sorted and paginated by q
+func (s *KitService) ListConsumptions(ctx context.Context, q *database.ListQuery) (*database.Page[*repository.KitConsumption], error) {
+	return s.kitRepo.ListConsumptions(ctx, q)
+}
@@ -0,0 +1,75 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/internal/inventory/service"
+	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKitService_ConsumeChoosesBatchesUnderLock(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tenant := suite.SetupInventoryTenant(t, ctx, "kit-consume-fefo")
+	tenantCtx := suite.TenantContext(tenant)
+
+	log := logger.New("test", "test")
+	itemRepo := repository.NewItemRepository(suite.DB)
+	batchRepo := repository.NewBatchRepository(suite.DB)
+	kitRepo := repository.NewKitRepository(suite.DB)
+	auditService := service.NewAuditService(repository.NewAuditTrailRepository(suite.DB), log)
+	reservationService := service.NewReservationService(repository.NewReservationRepository(suite.DB), itemRepo,
+		repository.NewLocationRepository(suite.DB), auditService, nil, log)
+	svc := service.NewKitService(kitRepo, reservationService, auditService, nil, log)
+
+	item := &repository.InventoryItem{Name: "Kompressen steril", Unit: "Stk", IsActive: true}
+	require.NoError(t, itemRepo.Create(tenantCtx, item))
+
+	newBatch := func(number string, expiresIn int) *repository.InventoryBatch {
+		expiry := time.Now().AddDate(0, 0, expiresIn).UTC().Truncate(24 * time.Hour)
+		b := &repository.InventoryBatch{
+			ItemID: item.ID, BatchNumber: number, InitialQuantity: 5, CurrentQuantity: 5,
+			ExpiryDate: &expiry, ReceivedDate: time.Now().UTC(), Status: "available",
+		}
+		require.NoError(t, batchRepo.Create(tenantCtx, b))
+		return b
+	}
+	first := newBatch("FIRST-30", 30)
+	second := newBatch("SECOND-60", 60)
+	third := newBatch("THIRD-90", 90)
+
+	kit := &repository.ProcedureKit{
+		Name:     "Verbandwechsel",
+		IsActive: true,
+		Items:    []*repository.ProcedureKitItem{{ItemID: item.ID, Quantity: 4}},
+	}
+	require.NoError(t, kitRepo.Create(tenantCtx, kit))
+
+	list, err := svc.PickList(tenantCtx, kit.ID, 1)
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, first.ID, list.Items[0].Lines[0].BatchID)
+
+	// The first batch is quarantined after the pick list was shown
+	first.Status = "quarantine"
+	require.NoError(t, batchRepo.Update(tenantCtx, first))
+
+	consumption, err := svc.Consume(tenantCtx, kit.ID, &service.ConsumeKitInput{}, "", "")
+	require.NoError(t, err)
+	require.Len(t, consumption.Lines, 1)
+	assert.Equal(t, second.ID, consumption.Lines[0].BatchID)
+	assert.Equal(t, 4, consumption.Lines[0].Quantity)
+
+	for id, want := range map[string]int{first.ID: 5, second.ID: 1, third.ID: 5} {
+		b, err := batchRepo.GetByID(tenantCtx, id)
+		require.NoError(t, err)
+		assert.Equal(t, want, b.CurrentQuantity, b.BatchNumber)
+	}
+}
@@ -0,0 +1,528 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/pkg/barcode"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/label"
+	"github.com/medflow/medflow-backend/pkg/logger"
+)
+
+// MaxLabelsPerPrint limits the labels, copies included, of one print request
+const MaxLabelsPerPrint = 500
+
+// builtinLabelTemplates are used for a kind the tenant has no default
+// template for: common thermal label stock at 203 dpi
+var builtinLabelTemplates = map[string]repository.LabelTemplate{
+	repository.LabelKindBatch:  {Name: "Charge 57x32", Kind: repository.LabelKindBatch, WidthMM: 57, HeightMM: 32, DPI: 203, Symbology: barcode.SymbologyDataMatrix},
+	repository.LabelKindShelf:  {Name: "Regal 70x35", Kind: repository.LabelKindShelf, WidthMM: 70, HeightMM: 35, DPI: 203, Symbology: barcode.SymbologyCode128},
+	repository.LabelKindDevice: {Name: "Gerät 50x25", Kind: repository.LabelKindDevice, WidthMM: 50, HeightMM: 25, DPI: 203, Symbology: barcode.SymbologyDataMatrix},
+}
+
+// LabelService manages label templates and renders labels for batches,
+// shelves and medical devices
+type LabelService struct {
+	templateRepo      *repository.LabelTemplateRepository
+	itemRepo          *repository.ItemRepository
+	batchRepo         *repository.BatchRepository
+	locationRepo      *repository.LocationRepository
+	purchaseOrderRepo *repository.PurchaseOrderRepository
+	auditService      *AuditService
+	logger            *logger.Logger
+}
+
+// NewLabelService creates a new label service
+func NewLabelService(
+	templateRepo *repository.LabelTemplateRepository,
+	itemRepo *repository.ItemRepository,
+	batchRepo *repository.BatchRepository,
+	locationRepo *repository.LocationRepository,
+	purchaseOrderRepo *repository.PurchaseOrderRepository,
+	auditService *AuditService,
+	log *logger.Logger,
+) *LabelService {
+	return &LabelService{
+		templateRepo:      templateRepo,
+		itemRepo:          itemRepo,
+		batchRepo:         batchRepo,
+		locationRepo:      locationRepo,
+		purchaseOrderRepo: purchaseOrderRepo,
+		auditService:      auditService,
+		logger:            log,
+	}
+}
+
+// LabelTemplateInput is the input for creating or updating a label template
+type LabelTemplateInput struct {
+	Name      string  `json:"name" validate:"required,max=255"`
+	Kind      string  `json:"kind" validate:"required,oneof=batch shelf device"`
+	WidthMM   float64 `json:"width_mm" validate:"required"`
+	HeightMM  float64 `json:"height_mm" validate:"required"`
+	DPI       int     `json:"dpi"`
+	Symbology string  `json:"symbology" validate:"required,oneof=code128 datamatrix qr"`
+	// SheetColumns and SheetRows lay out PDF labels on A4 sheets
+	SheetColumns int  `json:"sheet_columns"`
+	SheetRows    int  `json:"sheet_rows"`
+	IsDefault    bool `json:"is_default"`
+}
+
+// template builds a template from the input; the resolution defaults to 203 dpi
+func (input *LabelTemplateInput) template(id string) (*repository.LabelTemplate, error) {
+	t := &repository.LabelTemplate{
+		ID:           id,
+		Name:         input.Name,
+		Kind:         input.Kind,
+		WidthMM:      input.WidthMM,
+		HeightMM:     input.HeightMM,
+		DPI:          input.DPI,
+		Symbology:    input.Symbology,
+		SheetColumns: input.SheetColumns,
+		SheetRows:    input.SheetRows,
+		IsDefault:    input.IsDefault,
+	}
+	if t.DPI == 0 {
+		t.DPI = label.Resolutions[0]
+	}
+
+	sizeRange := func(field string) error {
+		return errors.Validation(nil).WithDetail(field, "validation.range", map[string]string{
+			"field": field, "min": strconv.Itoa(label.MinSizeMM), "max": strconv.Itoa(label.MaxSizeMM),
+		})
+	}
+	switch {
+	case t.WidthMM < label.MinSizeMM || t.WidthMM > label.MaxSizeMM:
+		return nil, sizeRange("width_mm")
+	case t.HeightMM < label.MinSizeMM || t.HeightMM > label.MaxSizeMM:
+		return nil, sizeRange("height_mm")
+	case t.DPI != 203 && t.DPI != 300 && t.DPI != 600:
+		return nil, errors.Validation(nil).WithDetail("dpi", "validation.one_of", map[string]string{
+			"field": "dpi", "values": "203, 300, 600",
+		})
+	}
+
+	// What is left for Validate to reject is the sheet layout
+	if err := labelTemplate(t).Validate(); err != nil {
+		return nil, errors.BadRequest("errors.label.invalid_sheet", map[string]string{
+			"columns": strconv.Itoa(t.SheetColumns),
+			"rows":    strconv.Itoa(t.SheetRows),
+		})
+	}
+	return t, nil
+}
+
+// labelTemplate converts a stored template for rendering
+func labelTemplate(t *repository.LabelTemplate) label.Template {
+	return label.Template{
+		WidthMM:   t.WidthMM,
+		HeightMM:  t.HeightMM,
+		DPI:       t.DPI,
+		Symbology: t.Symbology,
+		Columns:   t.SheetColumns,
+		Rows:      t.SheetRows,
+	}
+}
+
+// CreateTemplate creates a label template
+func (s *LabelService) CreateTemplate(ctx context.Context, input *LabelTemplateInput, userID string) (*repository.LabelTemplate, error) {
+	t, err := input.template("")
+	if err != nil {
+		return nil, err
+	}
+	if userID != "" {
+		t.CreatedBy = &userID
+	}
+
+	if err := s.templateRepo.Create(ctx, t); err != nil {
+		return nil, err
+	}
+
+	s.auditService.RecordCreate(ctx, "label_template", t.ID, map[string]interface{}{
+		"name":       t.Name,
+		"kind":       t.Kind,
+		"is_default": t.IsDefault,
+	})
+
+	return t, nil
+}
+
+// GetTemplate gets a label template
+func (s *LabelService) GetTemplate(ctx context.Context, id string) (*repository.LabelTemplate, error) {
+	return s.templateRepo.GetByID(ctx, id)
+}
+
+// ListTemplates lists label templates filtered, sorted and paginated by q
+func (s *LabelService) ListTemplates(ctx context.Context, q *database.ListQuery) (*database.Page[*repository.LabelTemplate], error) {
+	return s.templateRepo.List(ctx, q)
+}
+
+// UpdateTemplate replaces a label template
+func (s *LabelService) UpdateTemplate(ctx context.Context, id string, input *LabelTemplateInput) (*repository.LabelTemplate, error) {
+	t, err := input.template(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.templateRepo.Update(ctx, t); err != nil {
+		return nil, err
+	}
+
+	s.auditService.RecordUpdate(ctx, "label_template", id, map[string]interface{}{
+		"name":       t.Name,
+		"kind":       t.Kind,
+		"width_mm":   t.WidthMM,
+		"height_mm":  t.HeightMM,
+		"symbology":  t.Symbology,
+		"is_default": t.IsDefault,
+	}, nil)
+
+	return t, nil
+}
+
+// DeleteTemplate deletes a label template; printing falls back to the
+// built-in template when it was the default
+func (s *LabelService) DeleteTemplate(ctx context.Context, id string) error {
+	if err := s.templateRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.auditService.RecordDelete(ctx, "label_template", id, nil)
+	return nil
+}
+
+// LabelTarget is a batch (kind batch) or an item (kinds shelf and device) to
+// print labels for
+type LabelTarget struct {
+	ID string `json:"id" validate:"required,uuid"`
+	// LocationID overrides the item's default location on shelf labels
+	LocationID *string `json:"location_id" validate:"omitempty,uuid"`
+	Copies     int     `json:"copies" validate:"omitempty,max=100"`
+}
+
+// PrintLabelsInput is the input for printing labels
+type PrintLabelsInput struct {
+	Kind string `json:"kind" validate:"required,oneof=batch shelf device"`
+	// TemplateID defaults to the kind's default template
+	TemplateID *string       `json:"template_id" validate:"omitempty,uuid"`
+	Format     string        `json:"format" validate:"required,oneof=zpl pdf"`
+	Targets    []LabelTarget `json:"targets" validate:"required,dive"`
+}
+
+// ReceiptLabelsInput is the input for printing the batch labels of a goods receipt
+type ReceiptLabelsInput struct {
+	TemplateID *string `json:"template_id" validate:"omitempty,uuid"`
+	Format     string  `json:"format" validate:"required,oneof=zpl pdf"`
+	// PerUnit prints one label per received unit instead of one per batch
+	PerUnit bool `json:"per_unit"`
+}
+
+// LabelDocument is rendered labels, ready to send to a printer
+type LabelDocument struct {
+	Format string
+	Data   []byte
+	// Labels is the number of labels, copies included
+	Labels int
+}
+
+// ContentType returns the media type of the document
+func (d *LabelDocument) ContentType() string {
+	if d.Format == label.FormatPDF {
+		return "application/pdf"
+	}
+	return "text/plain; charset=utf-8"
+}
+
+// Print renders labels for batches, shelves or devices
+func (s *LabelService) Print(ctx context.Context, input *PrintLabelsInput) (*LabelDocument, error) {
+	tmpl, err := s.template(ctx, input.Kind, input.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := make([]label.Label, 0, len(input.Targets))
+	for _, target := range input.Targets {
+		var lb label.Label
+		switch input.Kind {
+		case repository.LabelKindBatch:
+			lb, err = s.batchLabel(ctx, target.ID)
+		case repository.LabelKindShelf:
+			lb, err = s.shelfLabel(ctx, target.ID, target.LocationID)
+		case repository.LabelKindDevice:
+			lb, err = s.deviceLabel(ctx, target.ID)
+		}
+		if err != nil {
+			return nil, err
+		}
+		lb.Copies = target.Copies
+		labels = append(labels, lb)
+	}
+
+	return s.render(tmpl, input.Format, labels)
+}
+
+// PrintReceipt renders the batch labels of a goods receipt, one per received
+// batch or, with PerUnit, one per received unit
+func (s *LabelService) PrintReceipt(ctx context.Context, purchaseOrderID, receiptID string, input *ReceiptLabelsInput) (*LabelDocument, error) {
+	receipt, err := s.purchaseOrderRepo.GetReceipt(ctx, purchaseOrderID, receiptID)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := s.template(ctx, repository.LabelKindBatch, input.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := make([]label.Label, 0, len(receipt.Lines))
+	for _, line := range receipt.Lines {
+		lb, err := s.batchLabel(ctx, line.BatchID)
+		if err != nil {
+			return nil, err
+		}
+		if input.PerUnit {
+			lb.Copies = line.Quantity
+		}
+		labels = append(labels, lb)
+	}
+
+	return s.render(tmpl, input.Format, labels)
+}
+
+// template returns the requested template, the kind's default or the
+// built-in template for the kind
+func (s *LabelService) template(ctx context.Context, kind string, id *string) (*repository.LabelTemplate, error) {
+	if id != nil {
+		t, err := s.templateRepo.GetByID(ctx, *id)
+		if err != nil {
+			return nil, err
+		}
+		if t.Kind != kind {
+			return nil, errors.BadRequest("errors.label.kind_mismatch", map[string]string{
+				"template": t.Name,
+				"kind":     kind,
+			})
+		}
+		return t, nil
+	}
+
+	t, err := s.templateRepo.GetDefault(ctx, kind)
+	if errors.Is(err, errors.ErrNotFound) {
+		builtin := builtinLabelTemplates[kind]
+		return &builtin, nil
+	}
+	return t, err
+}
+
+func (s *LabelService) render(tmpl *repository.LabelTemplate, format string, labels []label.Label) (*LabelDocument, error) {
+	total := 0
+	for _, lb := range labels {
+		total += max(lb.Copies, 1)
+	}
+	if total > MaxLabelsPerPrint {
+		return nil, errors.BadRequest("errors.label.too_many", map[string]string{
+			"count": strconv.Itoa(total),
+			"max":   strconv.Itoa(MaxLabelsPerPrint),
+		})
+	}
+
+	var data []byte
+	var err error
+	if format == label.FormatPDF {
+		data, err = label.PDF(labelTemplate(tmpl), labels)
+	} else {
+		data, err = label.ZPL(labelTemplate(tmpl), labels)
+	}
+	if err != nil {
+		s.logger.Error().Err(err).Str("format", format).Msg("failed to render labels")
+		return nil, errors.Internal("errors.label.render_failed")
+	}
+
+	return &LabelDocument{Format: format, Data: data, Labels: total}, nil
+}
+
+// batchLabel shows the batch number and the date to use the batch by: the
+// expiry date or, for an opened multi-dose container, the end of its
+// shelf life after opening. The code carries the manufacturer's expiry.
+func (s *LabelService) batchLabel(ctx context.Context, batchID string) (label.Label, error) {
+	batch, err := s.batchRepo.GetByID(ctx, batchID)
+	if err != nil {
+		return label.Label{}, err
+	}
+	item, err := s.itemRepo.GetByID(ctx, batch.ItemID)
+	if err != nil {
+		return label.Label{}, err
+	}
+
+	lb := label.Label{Title: item.Name}
+	lb.Lines = append(lb.Lines, "Ch.-B.: "+batch.BatchNumber)
+	if batch.OpenedAt != nil {
+		lb.Lines = append(lb.Lines, "Angebrochen: "+labelDate(*batch.OpenedAt))
+	}
+	if expiry := GetEffectiveExpiry(batch, item); expiry != nil {
+		lb.Lines = append(lb.Lines, "Verw. bis: "+labelDate(*expiry))
+	}
+	if name := s.locationName(ctx, batch.LocationID); name != "" {
+		lb.Lines = append(lb.Lines, "Lagerort: "+name)
+	}
+
+	var extra []barcode.Element
+	if batch.ExpiryDate != nil {
+		extra = append(extra, barcode.Element{AI: "17", Value: batch.ExpiryDate.Format("060102")})
+	}
+	extra = append(extra, barcode.Element{AI: "10", Value: batch.BatchNumber})
+	s.setCode(&lb, item, extra)
+	return lb, nil
+}
+
+// shelfLabel shows an item at its storage location with its reorder point
+func (s *LabelService) shelfLabel(ctx context.Context, itemID string, locationID *string) (label.Label, error) {
+	item, err := s.itemRepo.GetByID(ctx, itemID)
+	if err != nil {
+		return label.Label{}, err
+	}
+	if locationID == nil {
+		locationID = item.DefaultLocationID
+	} else if _, err := s.locationRepo.ResolveLocation(ctx, *locationID); err != nil {
+		return label.Label{}, err
+	}
+
+	lb := label.Label{Title: item.Name}
+	if item.ArticleNumber != nil {
+		lb.Lines = append(lb.Lines, "Art.-Nr.: "+*item.ArticleNumber)
+	}
+	if item.PZN != nil {
+		lb.Lines = append(lb.Lines, "PZN: "+*item.PZN)
+	}
+	if name := s.locationName(ctx, locationID); name != "" {
+		lb.Lines = append(lb.Lines, "Lagerort: "+name)
+	}
+	reorderPoint := item.MinStock
+	if item.ReorderPoint != nil {
+		reorderPoint = *item.ReorderPoint
+	}
+	if reorderPoint > 0 {
+		lb.Lines = append(lb.Lines, fmt.Sprintf("Meldebestand: %d %s", reorderPoint, item.Unit))
+	}
+
+	s.setCode(&lb, item, nil)
+	return lb, nil
+}
+
+// deviceLabel shows a medical device's serial and inventory numbers and the
+// next safety and metrological inspections (STK, MTK)
+func (s *LabelService) deviceLabel(ctx context.Context, itemID string) (label.Label, error) {
+	item, err := s.itemRepo.GetByID(ctx, itemID)
+	if err != nil {
+		return label.Label{}, err
+	}
+	if !item.IsMedicalDevice {
+		return label.Label{}, errors.BadRequest("errors.label.not_a_device", map[string]string{
+			"item": item.Name,
+		})
+	}
+
+	lb := label.Label{Title: item.Name}
+	if item.SerialNumber != nil {
+		lb.Lines = append(lb.Lines, "SN: "+*item.SerialNumber)
+	}
+	if item.OperationalIDNumber != nil {
+		lb.Lines = append(lb.Lines, "Inv.-Nr.: "+*item.OperationalIDNumber)
+	}
+	if item.NextStkDue != nil {
+		lb.Lines = append(lb.Lines, "Nächste STK: "+labelDate(*item.NextStkDue))
+	}
+	if item.NextMtkDue != nil {
+		lb.Lines = append(lb.Lines, "Nächste MTK: "+labelDate(*item.NextMtkDue))
+	}
+
+	var extra []barcode.Element
+	if item.SerialNumber != nil {
+		extra = append(extra, barcode.Element{AI: "21", Value: *item.SerialNumber})
+	}
+	s.setCode(&lb, item, extra)
+	return lb, nil
+}
+
+// setCode sets a GS1 code that ScanCode resolves to the item: the GTIN
+// (barcode or UDI-DI), else the PZN (AI 710), else the article number
+// (AI 240), followed by extra elements. Items without any of them get their
+// plain barcode, if any, and no batch data.
+func (s *LabelService) setCode(lb *label.Label, item *repository.InventoryItem, extra []barcode.Element) {
+	var elements []barcode.Element
+	switch {
+	case itemGTIN(item) != "":
+		elements = append(elements, barcode.Element{AI: "01", Value: itemGTIN(item)})
+	case item.PZN != nil && barcode.ValidPZN(*item.PZN):
+		elements = append(elements, barcode.Element{AI: "710", Value: *item.PZN})
+	case item.ArticleNumber != nil && *item.ArticleNumber != "":
+		elements = append(elements, barcode.Element{AI: "240", Value: *item.ArticleNumber})
+	case item.Barcode != nil && *item.Barcode != "":
+		lb.Code = *item.Barcode
+		lb.Caption = *item.Barcode
+		return
+	default:
+		return
+	}
+
+	// Fixed length elements first keeps the code short; the expiry (17) is
+	// the only one among the extras
+	if elements[0].AI != "01" {
+		for i, e := range extra {
+			if e.AI == "17" {
+				extra = append(extra[:i:i], extra[i+1:]...)
+				elements = append([]barcode.Element{e}, elements...)
+				break
+			}
+		}
+	}
+	elements = append(elements, extra...)
+
+	code, err := barcode.ElementString(elements...)
+	if err != nil {
+		// Batch or serial numbers outside the GS1 limits: the label still
+		// identifies the item
+		s.logger.Warn().Err(err).Str("item_id", item.ID).Msg("label data does not fit a GS1 code")
+		if code, err = barcode.ElementString(elements[0]); err != nil {
+			return
+		}
+		elements = elements[:1]
+	}
+	lb.Code = code
+	lb.GS1 = true
+	lb.Caption = barcode.HumanReadable(elements...)
+}
+
+// itemGTIN returns the item's GTIN-14 from its barcode or UDI-DI
+func itemGTIN(item *repository.InventoryItem) string {
+	for _, s := range []*string{item.Barcode, item.UdiDI} {
+		if s == nil {
+			continue
+		}
+		if code, err := barcode.Parse(*s); err == nil && code.Format == barcode.FormatPlain && code.GTIN != "" {
+			return code.GTIN
+		}
+	}
+	return ""
+}
+
+// locationName returns the name of a room, cabinet or shelf, or "" if it
+// cannot be resolved
+func (s *LabelService) locationName(ctx context.Context, id *string) string {
+	if id == nil {
+		return ""
+	}
+	loc, err := s.locationRepo.ResolveLocation(ctx, *id)
+	if err != nil {
+		return ""
+	}
+	return loc.Name
+}
+
+func labelDate(t time.Time) string {
+	return t.Format("02.01.2006")
+}
@@ -0,0 +1,634 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/medflow/medflow-backend/internal/inventory/events"
+	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/logger"
+)
+
+// PurchaseOrderService handles suppliers, purchase orders and goods receipt.
+// It turns low-stock alerts into draft orders and books deliveries as batches.
+type PurchaseOrderService struct {
+	supplierRepo *repository.SupplierRepository
+	orderRepo    *repository.PurchaseOrderRepository
+	itemRepo     *repository.ItemRepository
+	batchRepo    *repository.BatchRepository
+	alertRepo    *repository.AlertRepository
+	auditService *AuditService
+	publisher    *events.InventoryEventPublisher
+	logger       *logger.Logger
+}
+
+// NewPurchaseOrderService creates a new purchase order service
+func NewPurchaseOrderService(
+	supplierRepo *repository.SupplierRepository,
+	orderRepo *repository.PurchaseOrderRepository,
+	itemRepo *repository.ItemRepository,
+	batchRepo *repository.BatchRepository,
+	alertRepo *repository.AlertRepository,
+	auditService *AuditService,
+	publisher *events.InventoryEventPublisher,
+	log *logger.Logger,
+) *PurchaseOrderService {
+	return &PurchaseOrderService{
+		supplierRepo: supplierRepo,
+		orderRepo:    orderRepo,
+		itemRepo:     itemRepo,
+		batchRepo:    batchRepo,
+		alertRepo:    alertRepo,
+		auditService: auditService,
+		publisher:    publisher,
+		logger:       log,
+	}
+}
+
+// --- Suppliers ---
+
+// CreateSupplier creates a new supplier
+func (s *PurchaseOrderService) CreateSupplier(ctx context.Context, supplier *repository.Supplier) error {
+	if err := s.supplierRepo.Create(ctx, supplier); err != nil {
+		return err
+	}
+
+	s.auditService.RecordCreate(ctx, "supplier", supplier.ID, map[string]interface{}{
+		"name":            supplier.Name,
+		"customer_number": supplier.CustomerNumber,
+	})
+
+	return nil
+}
+
+// GetSupplier gets a supplier by ID
+func (s *PurchaseOrderService) GetSupplier(ctx context.Context, id string) (*repository.Supplier, error) {
+	return s.supplierRepo.GetByID(ctx, id)
+}
+
+// ListSuppliers lists suppliers filtered, sorted and paginated by q
+func (s *PurchaseOrderService) ListSuppliers(ctx context.Context, q *database.ListQuery) (*database.Page[*repository.Supplier], error) {
+	return s.supplierRepo.List(ctx, q)
+}
+
+// UpdateSupplier updates a supplier
+func (s *PurchaseOrderService) UpdateSupplier(ctx context.Context, supplier *repository.Supplier) error {
+	if err := s.supplierRepo.Update(ctx, supplier); err != nil {
+		return err
+	}
+
+	s.auditService.RecordUpdate(ctx, "supplier", supplier.ID, map[string]interface{}{
+		"name":            supplier.Name,
+		"customer_number": supplier.CustomerNumber,
+		"lead_time_days":  supplier.LeadTimeDays,
+		"is_active":       supplier.IsActive,
+	}, nil)
+
+	return nil
+}
+
+// DeleteSupplier soft-deletes a supplier without open orders
+func (s *PurchaseOrderService) DeleteSupplier(ctx context.Context, id string) error {
+	if err := s.supplierRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.auditService.RecordDelete(ctx, "supplier", id, nil)
+
+	return nil
+}
+
+// SetSupplierItem links an item to a supplier with article number, price and pack size
+func (s *PurchaseOrderService) SetSupplierItem(ctx context.Context, si *repository.SupplierItem) error {
+	if _, err := s.supplierRepo.GetByID(ctx, si.SupplierID); err != nil {
+		return err
+	}
+	if _, err := s.itemRepo.GetByID(ctx, si.ItemID); err != nil {
+		return err
+	}
+
+	if err := s.supplierRepo.UpsertItem(ctx, si); err != nil {
+		return err
+	}
+
+	s.auditService.RecordAction(ctx, "supplier", si.SupplierID, "set_item", map[string]interface{}{
+		"item_id":          si.ItemID,
+		"unit_price_cents": si.UnitPriceCents,
+		"pack_size":        si.PackSize,
+		"is_preferred":     si.IsPreferred,
+	})
+
+	return nil
+}
+
+// ListSupplierItems lists the items a supplier delivers
+func (s *PurchaseOrderService) ListSupplierItems(ctx context.Context, supplierID string) ([]*repository.SupplierItem, error) {
+	return s.supplierRepo.ListItems(ctx, supplierID)
+}
+
+// RemoveSupplierItem removes an item from a supplier
+func (s *PurchaseOrderService) RemoveSupplierItem(ctx context.Context, supplierID, itemID string) error {
+	if err := s.supplierRepo.DeleteItem(ctx, supplierID, itemID); err != nil {
+		return err
+	}
+
+	s.auditService.RecordAction(ctx, "supplier", supplierID, "remove_item", map[string]interface{}{
+		"item_id": itemID,
+	})
+
+	return nil
+}
+
+// --- Purchase Orders ---
+
+// CreateOrder creates a draft purchase order. Lines without a price take the
+// supplier's price for the item.
+func (s *PurchaseOrderService) CreateOrder(ctx context.Context, po *repository.PurchaseOrder) error {
+	if err := s.prepareOrder(ctx, po); err != nil {
+		return err
+	}
+
+	po.Status = repository.PurchaseOrderDraft
+	if err := s.orderRepo.Create(ctx, po); err != nil {
+		return err
+	}
+
+	s.auditService.RecordCreate(ctx, "purchase_order", po.ID, map[string]interface{}{
+		"order_number": po.OrderNumber,
+		"supplier_id":  po.SupplierID,
+		"source":       po.Source,
+		"lines":        len(po.Lines),
+	})
+
+	return nil
+}
+
+// UpdateOrder replaces the supplier, expected date, notes and lines of a draft order
+func (s *PurchaseOrderService) UpdateOrder(ctx context.Context, po *repository.PurchaseOrder) error {
+	if err := s.prepareOrder(ctx, po); err != nil {
+		return err
+	}
+
+	if err := s.orderRepo.Update(ctx, po); err != nil {
+		return err
+	}
+
+	s.auditService.RecordUpdate(ctx, "purchase_order", po.ID, map[string]interface{}{
+		"supplier_id":   po.SupplierID,
+		"expected_date": po.ExpectedDate,
+		"lines":         len(po.Lines),
+	}, nil)
+
+	return nil
+}
+
+// prepareOrder validates an order's supplier and lines and fills in
+// supplier prices and article numbers
+func (s *PurchaseOrderService) prepareOrder(ctx context.Context, po *repository.PurchaseOrder) error {
+	if len(po.Lines) == 0 {
+		return errors.BadRequest("errors.purchase_order.no_lines")
+	}
+
+	supplier, err := s.supplierRepo.GetByID(ctx, po.SupplierID)
+	if err != nil {
+		return err
+	}
+	if !supplier.IsActive {
+		return errors.BadRequest("errors.supplier.inactive", map[string]string{"name": supplier.Name})
+	}
+
+	supplierItems, err := s.supplierRepo.ListItems(ctx, po.SupplierID)
+	if err != nil {
+		return err
+	}
+	catalog := make(map[string]*repository.SupplierItem, len(supplierItems))
+	for _, si := range supplierItems {
+		catalog[si.ItemID] = si
+	}
+
+	seen := make(map[string]bool, len(po.Lines))
+	for _, line := range po.Lines {
+		if line.QuantityOrdered <= 0 {
+			return errors.Validation(nil).WithDetail("quantity_ordered", "validation.positive", map[string]string{"field": "quantity_ordered"})
+		}
+		if seen[line.ItemID] {
+			return errors.BadRequest("errors.purchase_order.duplicate_item")
+		}
+		seen[line.ItemID] = true
+
+		if _, err := s.itemRepo.GetByID(ctx, line.ItemID); err != nil {
+			return err
+		}
+
+		if si, ok := catalog[line.ItemID]; ok {
+			if line.UnitPriceCents == nil {
+				line.UnitPriceCents = si.UnitPriceCents
+			}
+			if line.SupplierArticleNumber == nil {
+				line.SupplierArticleNumber = si.SupplierArticleNumber
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetOrder gets a purchase order with its lines
+func (s *PurchaseOrderService) GetOrder(ctx context.Context, id string) (*repository.PurchaseOrder, error) {
+	return s.orderRepo.GetByID(ctx, id)
+}
+
+// ListOrders lists purchase orders filtered, sorted and paginated by q
+func (s *PurchaseOrderService) ListOrders(ctx context.Context, q *database.ListQuery) (*database.Page[*repository.PurchaseOrder], error) {
+	return s.orderRepo.List(ctx, q)
+}
+
+// DeleteOrder deletes a draft order
+func (s *PurchaseOrderService) DeleteOrder(ctx context.Context, id string) error {
+	if err := s.orderRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.auditService.RecordDelete(ctx, "purchase_order", id, nil)
+
+	return nil
+}
+
+// SendOrder marks a draft order as sent. Without an expected date the
+// supplier's lead time determines it.
+func (s *PurchaseOrderService) SendOrder(ctx context.Context, id string) (*repository.PurchaseOrder, error) {
+	po, err := s.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	supplier, err := s.supplierRepo.GetByID(ctx, po.SupplierID)
+	if err != nil {
+		return nil, err
+	}
+
+	expected := time.Now().AddDate(0, 0, supplier.LeadTimeDays)
+	if err := s.orderRepo.MarkSent(ctx, id, &expected); err != nil {
+		return nil, err
+	}
+
+	s.auditService.RecordAction(ctx, "purchase_order", id, "send", map[string]interface{}{
+		"order_number": po.OrderNumber,
+		"supplier_id":  po.SupplierID,
+	})
+
+	return s.orderRepo.GetByID(ctx, id)
+}
+
+// CancelOrder cancels an order that has not been fully received
+func (s *PurchaseOrderService) CancelOrder(ctx context.Context, id string, reason *string) (*repository.PurchaseOrder, error) {
+	if err := s.orderRepo.Cancel(ctx, id, reason); err != nil {
+		return nil, err
+	}
+
+	s.auditService.RecordAction(ctx, "purchase_order", id, "cancel", map[string]interface{}{
+		"reason": reason,
+	})
+
+	return s.orderRepo.GetByID(ctx, id)
+}
+
+// --- Goods Receipt ---
+
+// ReceiptLineInput is one received batch in a goods receipt
+type ReceiptLineInput struct {
+	LineID           string     `json:"line_id" validate:"required"`
+	Quantity         int        `json:"quantity" validate:"required,gt=0"`
+	BatchNumber      string     `json:"batch_number"`
+	LotNumber        *string    `json:"lot_number,omitempty"`
+	ExpiryDate       *time.Time `json:"expiry_date,omitempty"`
+	ManufacturedDate *time.Time `json:"manufactured_date,omitempty"`
+	LocationID       *string    `json:"location_id,omitempty"`
+}
+
+// ReceiveGoodsInput is a delivery against a purchase order
+type ReceiveGoodsInput struct {
+	DeliveryNoteNumber *string            `json:"delivery_note_number,omitempty"`
+	Notes              *string            `json:"notes,omitempty"`
+	Lines              []ReceiptLineInput `json:"lines" validate:"required,min=1,dive"`
+}
+
+// ReceiveGoods books a delivery against an order. Every line becomes a batch
+// at the item's default location unless one is given. Batch-tracked items
+// need a batch number; others default to the order number. Without an
+// expiry date, items with a shelf life get one from the receipt date.
+// Stock alerts for the delivered items are resolved once stock is back
+// above the minimum.
+func (s *PurchaseOrderService) ReceiveGoods(ctx context.Context, orderID string, input *ReceiveGoodsInput, userID, userName string) (*repository.GoodsReceipt, error) {
+	po, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make(map[string]*repository.PurchaseOrderLine, len(po.Lines))
+	for _, line := range po.Lines {
+		lines[line.ID] = line
+	}
+
+	receipt := &repository.GoodsReceipt{
+		PurchaseOrderID:    orderID,
+		DeliveryNoteNumber: input.DeliveryNoteNumber,
+		ReceivedAt:         time.Now(),
+		Notes:              input.Notes,
+	}
+	if userID != "" {
+		receipt.ReceivedBy = &userID
+	}
+	if userName != "" {
+		receipt.ReceivedByName = &userName
+	}
+
+	items := make(map[string]*repository.InventoryItem)
+	for _, in := range input.Lines {
+		line, ok := lines[in.LineID]
+		if !ok {
+			return nil, errors.NotFound("purchase_order_line")
+		}
+
+		item, ok := items[line.ItemID]
+		if !ok {
+			item, err = s.itemRepo.GetByID(ctx, line.ItemID)
+			if err != nil {
+				return nil, err
+			}
+			items[item.ID] = item
+		}
+
+		batch, err := receiptBatch(item, po, &in, receipt.ReceivedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		receipt.Lines = append(receipt.Lines, &repository.GoodsReceiptLine{
+			PurchaseOrderLineID: in.LineID,
+			Quantity:            in.Quantity,
+			Batch:               batch,
+		})
+	}
+
+	if err := s.orderRepo.Receive(ctx, receipt); err != nil {
+		return nil, err
+	}
+
+	for _, line := range receipt.Lines {
+		s.publisher.PublishStockAdjusted(ctx, line.Adjustment)
+	}
+	for _, item := range items {
+		s.resolveStockAlerts(ctx, item, userID)
+	}
+
+	s.auditService.RecordAction(ctx, "purchase_order", orderID, "receive", map[string]interface{}{
+		"order_number":         po.OrderNumber,
+		"goods_receipt_id":     receipt.ID,
+		"delivery_note_number": receipt.DeliveryNoteNumber,
+		"lines":                len(receipt.Lines),
+	})
+
+	return receipt, nil
+}
+
+// receiptBatch builds the batch for a received line
+func receiptBatch(item *repository.InventoryItem, po *repository.PurchaseOrder, in *ReceiptLineInput, receivedAt time.Time) (*repository.InventoryBatch, error) {
+	batch := &repository.InventoryBatch{
+		BatchNumber:      in.BatchNumber,
+		LotNumber:        in.LotNumber,
+		ExpiryDate:       in.ExpiryDate,
+		ManufacturedDate: in.ManufacturedDate,
+		LocationID:       in.LocationID,
+	}
+
+	if batch.BatchNumber == "" {
+		if item.UseBatchTracking {
+			return nil, errors.Validation(nil).WithDetail("batch_number", "validation.required", map[string]string{"field": "batch_number"})
+		}
+		batch.BatchNumber = po.OrderNumber
+	}
+	if batch.LocationID == nil {
+		batch.LocationID = item.DefaultLocationID
+	}
+	if batch.ExpiryDate == nil && item.ShelfLifeDays != nil && *item.ShelfLifeDays > 0 {
+		expiry := receivedAt.AddDate(0, 0, *item.ShelfLifeDays)
+		batch.ExpiryDate = &expiry
+	}
+	if batch.ExpiryDate != nil && !batch.ExpiryDate.After(receivedAt) {
+		return nil, errors.BadRequest("errors.purchase_order.expired_on_receipt", map[string]string{
+			"batch": batch.BatchNumber,
+			"date":  batch.ExpiryDate.Format("2006-01-02"),
+		})
+	}
+
+	return batch, nil
+}
+
+// resolveStockAlerts resolves an item's low/out-of-stock alerts once its
+// stock is back at the minimum
+func (s *PurchaseOrderService) resolveStockAlerts(ctx context.Context, item *repository.InventoryItem, userID string) {
+	stock, err := s.batchRepo.GetTotalStock(ctx, item.ID)
+	if err != nil {
+		s.logger.Error().Err(err).Str("item_id", item.ID).Msg("failed to get total stock after goods receipt")
+		return
+	}
+	if stock < item.MinStock {
+		return
+	}
+
+	for _, alertType := range []string{"low_stock", "out_of_stock"} {
+		if err := s.alertRepo.BulkResolve(ctx, alertType, item.ID, userID); err != nil {
+			s.logger.Error().Err(err).Str("item_id", item.ID).Str("alert_type", alertType).Msg("failed to resolve stock alerts after goods receipt")
+		}
+	}
+}
+
+// ListReceipts lists the goods receipts of an order
+func (s *PurchaseOrderService) ListReceipts(ctx context.Context, orderID string) ([]*repository.GoodsReceipt, error) {
+	if _, err := s.orderRepo.GetByID(ctx, orderID); err != nil {
+		return nil, err
+	}
+	return s.orderRepo.ListReceipts(ctx, orderID)
+}
+
+// --- Order Suggestions ---
+
+// UnassignedItem is an item that needs reordering but has no supplier
+type UnassignedItem struct {
+	ItemID   string `json:"item_id"`
+	ItemName string `json:"item_name"`
+	Quantity int    `json:"quantity"`
+}
+
+// OrderSuggestions is the result of SuggestOrders
+type OrderSuggestions struct {
+	Orders     []*repository.PurchaseOrder `json:"orders"`
+	Unassigned []UnassignedItem            `json:"unassigned"`
+}
+
+// SuggestOrders creates draft purchase orders for the items with open
+// low-stock or out-of-stock alerts, one draft per supplier. Quantities
+// already on open orders count towards the stock, so running it again does
+// not order twice. Items go to their preferred supplier (see
+// SupplierRepository.GetSourcing), falling back to the supplier named on the
+// item; items without either are returned as unassigned.
+func (s *PurchaseOrderService) SuggestOrders(ctx context.Context) (*OrderSuggestions, error) {
+	alerts, err := s.alertRepo.ListActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	onOrder, err := s.orderRepo.OpenQuantities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &OrderSuggestions{Orders: []*repository.PurchaseOrder{}, Unassigned: []UnassignedItem{}}
+	drafts := make(map[string]*repository.PurchaseOrder)
+	seen := make(map[string]bool)
+
+	for _, alert := range alerts {
+		if alert.AlertType != "low_stock" && alert.AlertType != "out_of_stock" {
+			continue
+		}
+		if seen[alert.ItemID] {
+			continue
+		}
+		seen[alert.ItemID] = true
+
+		item, err := s.itemRepo.GetByID(ctx, alert.ItemID)
+		if err != nil {
+			s.logger.Error().Err(err).Str("item_id", alert.ItemID).Msg("order suggestions: failed to get item")
+			continue
+		}
+		if !item.IsActive {
+			continue
+		}
+		stock, err := s.batchRepo.GetTotalStock(ctx, item.ID)
+		if err != nil {
+			s.logger.Error().Err(err).Str("item_id", item.ID).Msg("order suggestions: failed to get total stock")
+			continue
+		}
+		sourcing, err := s.supplierRepo.GetSourcing(ctx, item.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		packSize := 1
+		if sourcing != nil {
+			packSize = sourcing.PackSize
+		}
+		quantity := suggestOrderQuantity(item, stock, onOrder[item.ID], packSize)
+		if quantity == 0 {
+			continue
+		}
+
+		line := &repository.PurchaseOrderLine{ItemID: item.ID, QuantityOrdered: quantity}
+		var supplierID string
+		if sourcing != nil {
+			supplierID = sourcing.SupplierID
+			line.UnitPriceCents = sourcing.UnitPriceCents
+			line.SupplierArticleNumber = sourcing.SupplierArticleNumber
+		} else if item.Supplier != nil && *item.Supplier != "" {
+			supplier, err := s.supplierRepo.GetByName(ctx, *item.Supplier)
+			if err != nil {
+				return nil, err
+			}
+			if supplier != nil {
+				supplierID = supplier.ID
+			}
+		}
+
+		if supplierID == "" {
+			result.Unassigned = append(result.Unassigned, UnassignedItem{ItemID: item.ID, ItemName: item.Name, Quantity: quantity})
+			continue
+		}
+
+		po, ok := drafts[supplierID]
+		if !ok {
+			po = &repository.PurchaseOrder{SupplierID: supplierID, Source: "suggestion"}
+			drafts[supplierID] = po
+		}
+		po.Lines = append(po.Lines, line)
+	}
+
+	supplierIDs := make([]string, 0, len(drafts))
+	for id := range drafts {
+		supplierIDs = append(supplierIDs, id)
+	}
+	sort.Strings(supplierIDs)
+
+	for _, id := range supplierIDs {
+		po := drafts[id]
+		if err := s.CreateOrder(ctx, po); err != nil {
+			return nil, err
+		}
+		result.Orders = append(result.Orders, po)
+	}
+
+	return result, nil
+}
+
+// SuggestOrdersJob runs SuggestOrders for the tenant in ctx (background job)
+func (s *PurchaseOrderService) SuggestOrdersJob(ctx context.Context) error {
+	result, err := s.SuggestOrders(ctx)
+	if err != nil {
+		return err
+	}
+	if len(result.Orders) > 0 || len(result.Unassigned) > 0 {
+		s.logger.Info().
+			Int("orders", len(result.Orders)).
+			Int("unassigned", len(result.Unassigned)).
+			Msg("created draft purchase orders from stock alerts")
+	}
+	return nil
+}
+
+// suggestOrderQuantity returns how much of an item to order given its stock
+// and the quantity already on order, or 0 if nothing is needed.
+//
+// An item needs ordering when stock plus open orders is at or below its
+// reorder point, or below its minimum stock if no reorder point is set.
+// It then gets its reorder quantity, or enough to reach its maximum stock
+// (twice the larger of minimum stock and reorder point if no maximum is
+// set), and never less than what lifts it back to the minimum. The result is
+// rounded up to whole packs.
+func suggestOrderQuantity(item *repository.InventoryItem, stock, onOrder, packSize int) int {
+	available := stock + onOrder
+
+	if item.ReorderPoint != nil {
+		if available > *item.ReorderPoint {
+			return 0
+		}
+	} else if available >= item.MinStock {
+		return 0
+	}
+
+	var quantity int
+	if item.ReorderQuantity != nil && *item.ReorderQuantity > 0 {
+		quantity = *item.ReorderQuantity
+	} else {
+		target := item.MinStock
+		if item.ReorderPoint != nil && *item.ReorderPoint > target {
+			target = *item.ReorderPoint
+		}
+		target *= 2
+		if item.MaxStock != nil && *item.MaxStock > 0 {
+			target = *item.MaxStock
+		}
+		quantity = target - available
+	}
+	if shortfall := item.MinStock - available; quantity < shortfall {
+		quantity = shortfall
+	}
+	if quantity <= 0 {
+		return 0
+	}
+
+	if packSize > 1 {
+		quantity = (quantity + packSize - 1) / packSize * packSize
+	}
+	return quantity
+}
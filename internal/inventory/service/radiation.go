@@ -4,22 +4,26 @@ import (
 	"context"
 
 	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/pkg/database"
 	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/medflow/medflow-backend/pkg/retention"
 )
 
 // RadiationService handles radiation protection business logic (StrlSchV/RoV compliance)
 type RadiationService struct {
-	radiationRepo *repository.RadiationRepository
-	auditService  *AuditService
-	logger        *logger.Logger
+	radiationRepo  *repository.RadiationRepository
+	retentionGuard *retention.Guard
+	auditService   *AuditService
+	logger         *logger.Logger
 }
 
 // NewRadiationService creates a new radiation service
-func NewRadiationService(radiationRepo *repository.RadiationRepository, auditService *AuditService, log *logger.Logger) *RadiationService {
+func NewRadiationService(radiationRepo *repository.RadiationRepository, retentionGuard *retention.Guard, auditService *AuditService, log *logger.Logger) *RadiationService {
 	return &RadiationService{
-		radiationRepo: radiationRepo,
-		auditService:  auditService,
-		logger:        log,
+		radiationRepo:  radiationRepo,
+		retentionGuard: retentionGuard,
+		auditService:   auditService,
+		logger:         log,
 	}
 }
 
@@ -66,6 +70,9 @@ func (s *RadiationService) UpdateDevice(ctx context.Context, device *repository.
 
 // DeleteDevice soft-deletes a radiation device
 func (s *RadiationService) DeleteDevice(ctx context.Context, id string) error {
+	if err := s.retentionGuard.Check(ctx, id, RetentionRadiationDevice); err != nil {
+		return err
+	}
 	if err := s.radiationRepo.DeleteDevice(ctx, id); err != nil {
 		return err
 	}
@@ -113,6 +120,9 @@ func (s *RadiationService) UpdateTest(ctx context.Context, test *repository.Cons
 
 // DeleteTest soft-deletes a constancy test
 func (s *RadiationService) DeleteTest(ctx context.Context, id string) error {
+	if err := s.retentionGuard.Check(ctx, id, RetentionConstancyTest); err != nil {
+		return err
+	}
 	if err := s.radiationRepo.DeleteTest(ctx, id); err != nil {
 		return err
 	}
@@ -160,6 +170,9 @@ func (s *RadiationService) UpdateExpertInspection(ctx context.Context, insp *rep
 
 // DeleteExpertInspection soft-deletes an expert inspection
 func (s *RadiationService) DeleteExpertInspection(ctx context.Context, id string) error {
+	if err := s.retentionGuard.Check(ctx, id, RetentionExpertInspection); err != nil {
+		return err
+	}
 	if err := s.radiationRepo.DeleteExpertInspection(ctx, id); err != nil {
 		return err
 	}
@@ -207,6 +220,9 @@ func (s *RadiationService) UpdateCertification(ctx context.Context, cert *reposi
 
 // DeleteCertification soft-deletes a staff radiation certification
 func (s *RadiationService) DeleteCertification(ctx context.Context, id string) error {
+	if err := s.retentionGuard.Check(ctx, id, RetentionRadiationCertification); err != nil {
+		return err
+	}
 	if err := s.radiationRepo.DeleteCertification(ctx, id); err != nil {
 		return err
 	}
@@ -239,7 +255,7 @@ func (s *RadiationService) ListDosimetryByEmployee(ctx context.Context, employee
 	return s.radiationRepo.ListDosimetryByEmployee(ctx, employeeID)
 }
 
-// ListAllDosimetry lists all dosimetry records with pagination
-func (s *RadiationService) ListAllDosimetry(ctx context.Context, page, perPage int) ([]*repository.DosimetryRecord, int64, error) {
-	return s.radiationRepo.ListAllDosimetry(ctx, page, perPage)
+// ListAllDosimetry lists all dosimetry records filtered, sorted and paginated by q
+func (s *RadiationService) ListAllDosimetry(ctx context.Context, q *database.ListQuery) (*database.Page[*repository.DosimetryRecord], error) {
+	return s.radiationRepo.ListAllDosimetry(ctx, q)
 }
@@ -5,19 +5,22 @@ import (
 
 	"github.com/medflow/medflow-backend/internal/inventory/repository"
 	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/medflow/medflow-backend/pkg/retention"
 )
 
 // ReprocessingService handles reprocessing and sterilization business logic (KRINKO compliance)
 type ReprocessingService struct {
 	reprocessingRepo *repository.ReprocessingRepository
+	retentionGuard   *retention.Guard
 	auditService     *AuditService
 	logger           *logger.Logger
 }
 
 // NewReprocessingService creates a new reprocessing service
-func NewReprocessingService(reprocessingRepo *repository.ReprocessingRepository, auditService *AuditService, log *logger.Logger) *ReprocessingService {
+func NewReprocessingService(reprocessingRepo *repository.ReprocessingRepository, retentionGuard *retention.Guard, auditService *AuditService, log *logger.Logger) *ReprocessingService {
 	return &ReprocessingService{
 		reprocessingRepo: reprocessingRepo,
+		retentionGuard:   retentionGuard,
 		auditService:     auditService,
 		logger:           log,
 	}
@@ -66,6 +69,9 @@ func (s *ReprocessingService) UpdateBatch(ctx context.Context, batch *repository
 
 // DeleteBatch soft-deletes a sterilization batch
 func (s *ReprocessingService) DeleteBatch(ctx context.Context, id string) error {
+	if err := s.retentionGuard.Check(ctx, id, RetentionSterilizationBatch); err != nil {
+		return err
+	}
 	if err := s.reprocessingRepo.DeleteBatch(ctx, id); err != nil {
 		return err
 	}
@@ -116,6 +122,9 @@ func (s *ReprocessingService) UpdateCycle(ctx context.Context, cycle *repository
 
 // DeleteCycle soft-deletes a reprocessing cycle
 func (s *ReprocessingService) DeleteCycle(ctx context.Context, id string) error {
+	if err := s.retentionGuard.Check(ctx, id, RetentionReprocessingCycle); err != nil {
+		return err
+	}
 	if err := s.reprocessingRepo.DeleteCycle(ctx, id); err != nil {
 		return err
 	}
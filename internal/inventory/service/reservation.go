@@ -0,0 +1,376 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/medflow/medflow-backend/internal/inventory/events"
+	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/logger"
+)
+
+// reservationGracePeriod is how long after it was needed an unused
+// reservation is kept before the job releases it
+const reservationGracePeriod = 24 * time.Hour
+
+// Reasons a batch is left off a pick list
+const (
+	PickSkipQuarantine = "quarantine"
+	PickSkipRecalled   = "recalled"
+	PickSkipExpired    = "expired"
+	PickSkipReserved   = "reserved"
+	PickSkipStatus     = "status"
+)
+
+// ReservationService reserves stock and suggests batches to pick
+// first-expiry-first-out (FEFO)
+type ReservationService struct {
+	reservationRepo *repository.ReservationRepository
+	itemRepo        *repository.ItemRepository
+	locationRepo    *repository.LocationRepository
+	auditService    *AuditService
+	publisher       *events.InventoryEventPublisher
+	logger          *logger.Logger
+}
+
+// NewReservationService creates a new reservation service
+func NewReservationService(
+	reservationRepo *repository.ReservationRepository,
+	itemRepo *repository.ItemRepository,
+	locationRepo *repository.LocationRepository,
+	auditService *AuditService,
+	publisher *events.InventoryEventPublisher,
+	log *logger.Logger,
+) *ReservationService {
+	return &ReservationService{
+		reservationRepo: reservationRepo,
+		itemRepo:        itemRepo,
+		locationRepo:    locationRepo,
+		auditService:    auditService,
+		publisher:       publisher,
+		logger:          log,
+	}
+}
+
+// PickLine is a batch to take stock from
+type PickLine struct {
+	BatchID         string     `json:"batch_id"`
+	BatchNumber     string     `json:"batch_number"`
+	LocationID      *string    `json:"location_id,omitempty"`
+	LocationName    string     `json:"location_name,omitempty"`
+	EffectiveExpiry *time.Time `json:"effective_expiry,omitempty"` // earlier of expiry and post-opening expiry
+	Opened          bool       `json:"opened"`
+	Available       int        `json:"available"`
+	Quantity        int        `json:"quantity"` // suggested quantity to pick
+}
+
+// SkippedBatch is a batch with stock that must not be picked
+type SkippedBatch struct {
+	BatchID     string `json:"batch_id"`
+	BatchNumber string `json:"batch_number"`
+	Quantity    int    `json:"quantity"`
+	Reason      string `json:"reason"` // quarantine, recalled, expired, reserved, status
+}
+
+// PickList suggests the batches to take a quantity of an item from
+type PickList struct {
+	ItemID    string          `json:"item_id"`
+	ItemName  string          `json:"item_name"`
+	Unit      string          `json:"unit"`
+	Requested int             `json:"requested"`
+	Available int             `json:"available"`
+	Shortfall int             `json:"shortfall"`
+	Lines     []*PickLine     `json:"lines"`
+	Skipped   []*SkippedBatch `json:"skipped"`
+}
+
+// PickList suggests batches for a quantity of an item, first expiry first
+// out by effective expiry, so an opened batch whose post-opening shelf life
+// ends first is used first. Quarantined, recalled and expired batches are
+// skipped. A quantity of 0 lists all available stock.
+func (s *ReservationService) PickList(ctx context.Context, itemID string, quantity int) (*PickList, error) {
+	return s.pickList(ctx, itemID, quantity, time.Now())
+}
+
+// pickList builds the pick list for stock needed at neededAt: batches whose
+// effective expiry is before then are skipped as expired
+func (s *ReservationService) pickList(ctx context.Context, itemID string, quantity int, neededAt time.Time) (*PickList, error) {
+	if quantity < 0 {
+		return nil, errors.Validation(nil).WithDetail("quantity", "validation.positive", map[string]string{"field": "quantity"})
+	}
+
+	item, err := s.itemRepo.GetByID(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := s.reservationRepo.ListPickCandidates(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &PickList{
+		ItemID:    item.ID,
+		ItemName:  item.Name,
+		Unit:      item.Unit,
+		Requested: quantity,
+		Lines:     []*PickLine{},
+		Skipped:   []*SkippedBatch{},
+	}
+
+	lines, skipped := pickLines(item, candidates, neededAt)
+	list.Skipped = skipped
+	for _, l := range lines {
+		list.Available += l.Available
+	}
+
+	remaining := quantity
+	if quantity == 0 {
+		remaining = list.Available
+	}
+	for _, l := range lines {
+		if remaining == 0 {
+			break
+		}
+		l.Quantity = min(l.Available, remaining)
+		remaining -= l.Quantity
+		if l.LocationID != nil {
+			if loc, err := s.locationRepo.ResolveLocation(ctx, *l.LocationID); err == nil {
+				l.LocationName = loc.Name
+			}
+		}
+		list.Lines = append(list.Lines, l)
+	}
+	list.Shortfall = remaining
+
+	return list, nil
+}
+
+// pickLines splits the candidates of item into the batches that may be
+// picked for use at neededAt, first expiry first out, and the skipped ones
+func pickLines(item *repository.InventoryItem, candidates []*repository.PickCandidate, neededAt time.Time) ([]*PickLine, []*SkippedBatch) {
+	var lines []*PickLine
+	skipped := []*SkippedBatch{}
+	for _, c := range candidates {
+		expiry := GetEffectiveExpiry(&c.InventoryBatch, item)
+		if reason := pickSkipReason(c, expiry, neededAt); reason != "" {
+			skipped = append(skipped, &SkippedBatch{
+				BatchID:     c.ID,
+				BatchNumber: c.BatchNumber,
+				Quantity:    c.CurrentQuantity,
+				Reason:      reason,
+			})
+			continue
+		}
+		lines = append(lines, &PickLine{
+			BatchID:         c.ID,
+			BatchNumber:     c.BatchNumber,
+			LocationID:      c.LocationID,
+			EffectiveExpiry: expiry,
+			Opened:          c.OpenedAt != nil,
+			Available:       c.Available(),
+		})
+	}
+
+	// FEFO; batches without expiry last. The candidates come ordered by
+	// expiry and receipt, which the stable sort keeps for ties.
+	sort.SliceStable(lines, func(i, j int) bool {
+		a, b := lines[i].EffectiveExpiry, lines[j].EffectiveExpiry
+		if a == nil || b == nil {
+			return a != nil && b == nil
+		}
+		return a.Before(*b)
+	})
+
+	return lines, skipped
+}
+
+// pickOrder returns the order in which to take stock of items from their
+// pick candidates at neededAt. It applies the pick list rules to candidates
+// read under lock, so a booking never relies on a pick list built earlier.
+func (s *ReservationService) pickOrder(ctx context.Context, itemIDs []string, neededAt time.Time) (repository.PickOrder, error) {
+	items := make(map[string]*repository.InventoryItem, len(itemIDs))
+	for _, id := range itemIDs {
+		item, err := s.itemRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		items[id] = item
+	}
+
+	return func(itemID string, candidates []*repository.PickCandidate) []string {
+		item, ok := items[itemID]
+		if !ok {
+			return nil
+		}
+		lines, _ := pickLines(item, candidates, neededAt)
+		batchIDs := make([]string, len(lines))
+		for i, l := range lines {
+			batchIDs[i] = l.BatchID
+		}
+		return batchIDs
+	}, nil
+}
+
+// pickSkipReason returns why a batch must not be picked for use at neededAt,
+// or "" if it may be
+func pickSkipReason(c *repository.PickCandidate, expiry *time.Time, neededAt time.Time) string {
+	switch {
+	case c.Status == "quarantine":
+		return PickSkipQuarantine
+	case c.Recalled:
+		return PickSkipRecalled
+	case c.Status == "expired" || (expiry != nil && expiry.Before(neededAt)):
+		return PickSkipExpired
+	case c.Status != "available":
+		return PickSkipStatus
+	case c.Available() <= 0:
+		return PickSkipReserved
+	}
+	return ""
+}
+
+// ReserveInput is the input for reserving stock
+type ReserveInput struct {
+	ItemID        string     `json:"item_id" validate:"required,uuid"`
+	Quantity      int        `json:"quantity" validate:"required,gt=0"`
+	ProcedureName *string    `json:"procedure_name" validate:"omitempty,max=255"`
+	NeededAt      *time.Time `json:"needed_at"`
+	ReferenceType *string    `json:"reference_type" validate:"omitempty,max=50"`
+	ReferenceID   *string    `json:"reference_id" validate:"omitempty,uuid"`
+	Notes         *string    `json:"notes"`
+}
+
+// Reserve reserves stock of an item on the batches of its pick list. A
+// batch that expires before the stock is needed is not reserved.
+func (s *ReservationService) Reserve(ctx context.Context, input *ReserveInput, userID, userName string) (*repository.StockReservation, error) {
+	if input.Quantity <= 0 {
+		return nil, errors.Validation(nil).WithDetail("quantity", "validation.positive", map[string]string{"field": "quantity"})
+	}
+
+	neededAt := time.Now()
+	if input.NeededAt != nil && input.NeededAt.After(neededAt) {
+		neededAt = *input.NeededAt
+	}
+	list, err := s.pickList(ctx, input.ItemID, input.Quantity, neededAt)
+	if err != nil {
+		return nil, err
+	}
+	batchIDs := make([]string, len(list.Lines))
+	for i, l := range list.Lines {
+		batchIDs[i] = l.BatchID
+	}
+
+	res := &repository.StockReservation{
+		ItemID:        input.ItemID,
+		Quantity:      input.Quantity,
+		ProcedureName: input.ProcedureName,
+		NeededAt:      input.NeededAt,
+		ReferenceType: input.ReferenceType,
+		ReferenceID:   input.ReferenceID,
+		Notes:         input.Notes,
+	}
+	if userID != "" {
+		res.ReservedBy = &userID
+	}
+	if userName != "" {
+		res.ReservedByName = &userName
+	}
+
+	// The repository checks availability again under lock
+	if err := s.reservationRepo.Create(ctx, res, batchIDs); err != nil {
+		return nil, err
+	}
+
+	s.auditService.RecordCreate(ctx, "reservation", res.ID, map[string]interface{}{
+		"item_id":        res.ItemID,
+		"quantity":       res.Quantity,
+		"procedure_name": res.ProcedureName,
+		"needed_at":      res.NeededAt,
+		"batches":        len(res.Batches),
+	})
+
+	return s.reservationRepo.GetByID(ctx, res.ID)
+}
+
+// Get gets a reservation with its batches
+func (s *ReservationService) Get(ctx context.Context, id string) (*repository.StockReservation, error) {
+	return s.reservationRepo.GetByID(ctx, id)
+}
+
+// List lists reservations filtered, sorted and paginated by q
+func (s *ReservationService) List(ctx context.Context, q *database.ListQuery) (*database.Page[*repository.StockReservation], error) {
+	return s.reservationRepo.List(ctx, q)
+}
+
+// Release gives the stock of a reservation back
+func (s *ReservationService) Release(ctx context.Context, id string) (*repository.StockReservation, error) {
+	if err := s.reservationRepo.Release(ctx, id); err != nil {
+		return nil, err
+	}
+
+	s.auditService.RecordAction(ctx, "reservation", id, "release", nil)
+
+	return s.reservationRepo.GetByID(ctx, id)
+}
+
+// Consume books out the reserved stock; a smaller quantity consumes that
+// much and releases the rest
+func (s *ReservationService) Consume(ctx context.Context, id string, quantity *int, userID, userName string) (*repository.StockReservation, error) {
+	if quantity != nil && *quantity < 0 {
+		return nil, errors.Validation(nil).WithDetail("quantity", "validation.positive", map[string]string{"field": "quantity"})
+	}
+
+	var performedByName *string
+	if userName != "" {
+		performedByName = &userName
+	}
+
+	adjustments, err := s.reservationRepo.Consume(ctx, id, quantity, userID, performedByName)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.reservationRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditService.RecordAction(ctx, "reservation", id, "consume", map[string]interface{}{
+		"item_id":           res.ItemID,
+		"quantity":          res.Quantity,
+		"consumed_quantity": res.ConsumedQuantity,
+	})
+
+	for _, adj := range adjustments {
+		s.publisher.PublishStockAdjusted(ctx, adj)
+	}
+
+	return res, nil
+}
+
+// ReleaseOverdueJob releases reservations that were not used within the
+// grace period after they were needed
+func (s *ReservationService) ReleaseOverdueJob(ctx context.Context) error {
+	ids, err := s.reservationRepo.ListOverdue(ctx, time.Now().Add(-reservationGracePeriod))
+	if err != nil {
+		return err
+	}
+
+	released := 0
+	for _, id := range ids {
+		if err := s.reservationRepo.Release(ctx, id); err != nil {
+			s.logger.Warn().Err(err).Str("reservation_id", id).Msg("failed to release overdue reservation")
+			continue
+		}
+		s.auditService.RecordAction(ctx, "reservation", id, "release", map[string]interface{}{"reason": "overdue"})
+		released++
+	}
+
+	if released > 0 {
+		s.logger.Info().Int("released", released).Msg("released overdue stock reservations")
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/internal/inventory/service"
+	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReservationService_PickListFEFO(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tenant := suite.SetupInventoryTenant(t, ctx, "pick-list-fefo")
+	tenantCtx := suite.TenantContext(tenant)
+
+	log := logger.New("test", "test")
+	itemRepo := repository.NewItemRepository(suite.DB)
+	batchRepo := repository.NewBatchRepository(suite.DB)
+	auditService := service.NewAuditService(repository.NewAuditTrailRepository(suite.DB), log)
+	svc := service.NewReservationService(repository.NewReservationRepository(suite.DB), itemRepo,
+		repository.NewLocationRepository(suite.DB), auditService, nil, log)
+
+	shelfLife := 28
+	item := &repository.InventoryItem{Name: "Lidocain 1%", Unit: "ml", ShelfLifeAfterOpeningDays: &shelfLife, IsActive: true}
+	require.NoError(t, itemRepo.Create(tenantCtx, item))
+
+	newBatch := func(number string, expiresIn int, opened *time.Time, status string) *repository.InventoryBatch {
+		expiry := time.Now().AddDate(0, 0, expiresIn).UTC().Truncate(24 * time.Hour)
+		b := &repository.InventoryBatch{
+			ItemID: item.ID, BatchNumber: number, InitialQuantity: 10, CurrentQuantity: 10,
+			ExpiryDate: &expiry, ReceivedDate: time.Now().UTC(), OpenedAt: opened, Status: status,
+		}
+		require.NoError(t, batchRepo.Create(tenantCtx, b))
+		return b
+	}
+
+	openedAgo := time.Now().AddDate(0, 0, -20)
+	sealed := newBatch("SEALED-90", 90, nil, "available")
+	opened := newBatch("OPENED-365", 365, &openedAgo, "available") // post-opening expiry in 8 days
+	newBatch("QUARANTINE-30", 30, nil, "quarantine")
+	newBatch("EXPIRED", -1, nil, "available")
+
+	list, err := svc.PickList(tenantCtx, item.ID, 15)
+	require.NoError(t, err)
+	assert.Equal(t, 20, list.Available)
+	require.Len(t, list.Lines, 2)
+	assert.Equal(t, opened.ID, list.Lines[0].BatchID)
+	assert.True(t, list.Lines[0].Opened)
+	assert.Equal(t, 10, list.Lines[0].Quantity)
+	assert.Equal(t, sealed.ID, list.Lines[1].BatchID)
+	assert.Equal(t, 5, list.Lines[1].Quantity)
+	assert.Equal(t, 0, list.Shortfall)
+
+	reasons := map[string]string{}
+	for _, s := range list.Skipped {
+		reasons[s.BatchNumber] = s.Reason
+	}
+	assert.Equal(t, service.PickSkipQuarantine, reasons["QUARANTINE-30"])
+	assert.Equal(t, service.PickSkipExpired, reasons["EXPIRED"])
+
+	// Reserving takes the pick list's batches; the pick list then shows the rest
+	res, err := svc.Reserve(tenantCtx, &service.ReserveInput{ItemID: item.ID, Quantity: 12}, "", "")
+	require.NoError(t, err)
+	require.Len(t, res.Batches, 2)
+	assert.Equal(t, opened.ID, res.Batches[0].BatchID)
+
+	list, err = svc.PickList(tenantCtx, item.ID, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 8, list.Available)
+}
+
+func TestReservationService_ReserveSkipsBatchesExpiringBeforeNeeded(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tenant := suite.SetupInventoryTenant(t, ctx, "reserve-needed-at")
+	tenantCtx := suite.TenantContext(tenant)
+
+	log := logger.New("test", "test")
+	itemRepo := repository.NewItemRepository(suite.DB)
+	batchRepo := repository.NewBatchRepository(suite.DB)
+	auditService := service.NewAuditService(repository.NewAuditTrailRepository(suite.DB), log)
+	svc := service.NewReservationService(repository.NewReservationRepository(suite.DB), itemRepo,
+		repository.NewLocationRepository(suite.DB), auditService, nil, log)
+
+	item := &repository.InventoryItem{Name: "Adrenalin 1mg", Unit: "Amp", IsActive: true}
+	require.NoError(t, itemRepo.Create(tenantCtx, item))
+
+	newBatch := func(number string, expiresIn int) *repository.InventoryBatch {
+		expiry := time.Now().AddDate(0, 0, expiresIn).UTC().Truncate(24 * time.Hour)
+		b := &repository.InventoryBatch{
+			ItemID: item.ID, BatchNumber: number, InitialQuantity: 10, CurrentQuantity: 10,
+			ExpiryDate: &expiry, ReceivedDate: time.Now().UTC(), Status: "available",
+		}
+		require.NoError(t, batchRepo.Create(tenantCtx, b))
+		return b
+	}
+	soon := newBatch("EXPIRES-5", 5)
+	later := newBatch("EXPIRES-60", 60)
+
+	// Needed in 10 days: the batch expiring in 5 days is of no use then
+	neededAt := time.Now().AddDate(0, 0, 10)
+	res, err := svc.Reserve(tenantCtx, &service.ReserveInput{ItemID: item.ID, Quantity: 4, NeededAt: &neededAt}, "", "")
+	require.NoError(t, err)
+	require.Len(t, res.Batches, 1)
+	assert.Equal(t, later.ID, res.Batches[0].BatchID)
+
+	// A needed date in the past reserves against today, so FEFO applies again
+	past := time.Now().AddDate(0, 0, -1)
+	res, err = svc.Reserve(tenantCtx, &service.ReserveInput{ItemID: item.ID, Quantity: 4, NeededAt: &past}, "", "")
+	require.NoError(t, err)
+	require.Len(t, res.Batches, 1)
+	assert.Equal(t, soon.ID, res.Batches[0].BatchID)
+}
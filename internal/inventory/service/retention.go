@@ -5,27 +5,135 @@ import (
 	"time"
 
 	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
 	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/medflow/medflow-backend/pkg/retention"
+)
+
+// Inventory records subject to retention policies. Deletes of these records
+// are checked by the retention guard; RetentionPurgeRecords are purged by the
+// inventory.retention_purge job, children before the records they reference.
+var (
+	RetentionItem = retention.Record{
+		EntityType: "inventory_item", Table: "inventory.inventory_items", DateColumn: "t.created_at",
+	}
+	RetentionItemDocument = retention.Record{
+		EntityType: "item_document", Table: "inventory.item_documents", DateColumn: "t.uploaded_at",
+	}
+	RetentionBtMBatch = retention.Record{
+		EntityType: "btm_register", Table: "inventory.inventory_batches", DateColumn: "t.received_date",
+		Filter: "EXISTS (SELECT 1 FROM inventory.inventory_items i WHERE i.id = t.item_id AND i.is_controlled_substance)",
+	}
+	RetentionHazardousDetails = retention.Record{
+		EntityType: "hazardous_substances", Table: "inventory.hazardous_substance_details",
+		DateColumn: "t.created_at", IDColumn: "item_id",
+	}
+
+	RetentionDeviceInspection = retention.Record{
+		EntityType: "device_book", Table: "inventory.device_inspections", DateColumn: "t.inspection_date",
+	}
+	RetentionDeviceTraining = retention.Record{
+		EntityType: "device_book", Table: "inventory.device_trainings", DateColumn: "t.training_date",
+		Anonymize: "trainer_name = 'Anonymisiert', attendee_names = 'Anonymisiert'",
+	}
+	RetentionDeviceIncident = retention.Record{
+		EntityType: "device_book", Table: "inventory.device_incidents", DateColumn: "t.incident_date",
+	}
+
+	RetentionRadiationDevice = retention.Record{
+		EntityType: "radiation", Table: "inventory.radiation_devices", DateColumn: "t.created_at",
+	}
+	RetentionConstancyTest = retention.Record{
+		EntityType: "radiation", Table: "inventory.constancy_tests", DateColumn: "t.test_date",
+	}
+	RetentionExpertInspection = retention.Record{
+		EntityType: "radiation", Table: "inventory.expert_inspections", DateColumn: "t.inspection_date",
+	}
+	RetentionRadiationCertification = retention.Record{
+		EntityType: "radiation", Table: "inventory.staff_radiation_certifications", DateColumn: "t.expiry_date",
+		Anonymize: "employee_name = 'Anonymisiert'",
+	}
+	RetentionDosimetryRecord = retention.Record{
+		EntityType: "radiation", Table: "inventory.dosimetry_records", DateColumn: "t.measurement_period_end",
+		Anonymize: "employee_name = 'Anonymisiert'",
+	}
+
+	RetentionSterilizationBatch = retention.Record{
+		EntityType: "sterilization", Table: "inventory.sterilization_batches", DateColumn: "t.cycle_date",
+	}
+	RetentionReprocessingCycle = retention.Record{
+		EntityType: "sterilization", Table: "inventory.reprocessing_cycles", DateColumn: "t.cycle_date",
+	}
+
+	RetentionHygienePlan = retention.Record{
+		EntityType: "hygiene", Table: "inventory.hygiene_plans", DateColumn: "COALESCE(t.effective_until, t.created_at)",
+	}
+	RetentionHygieneInspection = retention.Record{
+		EntityType: "hygiene", Table: "inventory.hygiene_inspections", DateColumn: "t.inspection_date",
+	}
+
+	RetentionBioRiskAssessment = retention.Record{
+		EntityType: "biosafety", Table: "inventory.bio_risk_assessments", DateColumn: "t.assessment_date",
+	}
+	RetentionBioTraining = retention.Record{
+		EntityType: "biosafety", Table: "inventory.bio_trainings", DateColumn: "t.training_date",
+		Anonymize: "trainer_name = 'Anonymisiert', attendee_names = 'Anonymisiert'",
+	}
+
+	// RetentionPurgeRecords are the records the purge job may delete or
+	// anonymize: those no other retained record references
+	RetentionPurgeRecords = []retention.Record{
+		RetentionDeviceInspection,
+		RetentionDeviceTraining,
+		RetentionDeviceIncident,
+		RetentionConstancyTest,
+		RetentionExpertInspection,
+		RetentionRadiationCertification,
+		RetentionDosimetryRecord,
+		RetentionReprocessingCycle,
+		RetentionHygieneInspection,
+		RetentionBioRiskAssessment,
+		RetentionBioTraining,
+	}
 )
 
 // RetentionService handles retention policy business logic
 type RetentionService struct {
 	retentionRepo *repository.RetentionRepository
+	holdRepo      *repository.LegalHoldRepository
 	auditService  *AuditService
 	logger        *logger.Logger
 }
 
 // NewRetentionService creates a new retention service
-func NewRetentionService(retentionRepo *repository.RetentionRepository, auditService *AuditService, log *logger.Logger) *RetentionService {
+func NewRetentionService(retentionRepo *repository.RetentionRepository, holdRepo *repository.LegalHoldRepository, auditService *AuditService, log *logger.Logger) *RetentionService {
 	return &RetentionService{
 		retentionRepo: retentionRepo,
+		holdRepo:      holdRepo,
 		auditService:  auditService,
 		logger:        log,
 	}
 }
 
+func validatePolicy(policy *repository.RetentionPolicy) error {
+	if policy.PurgeAction == "" {
+		policy.PurgeAction = retention.ActionNone
+	}
+	if !retention.ValidAction(policy.PurgeAction) {
+		return errors.Validation(nil).WithDetail("purge_action", "validation.one_of", map[string]string{
+			"field":  "purge_action",
+			"values": "none, delete, anonymize",
+		})
+	}
+	return nil
+}
+
 // Create creates a new retention policy
 func (s *RetentionService) Create(ctx context.Context, policy *repository.RetentionPolicy) error {
+	if err := validatePolicy(policy); err != nil {
+		return err
+	}
 	if err := s.retentionRepo.Create(ctx, policy); err != nil {
 		return err
 	}
@@ -33,6 +141,7 @@ func (s *RetentionService) Create(ctx context.Context, policy *repository.Retent
 	s.auditService.RecordCreate(ctx, "retention_policy", policy.ID, map[string]interface{}{
 		"entity_type":     policy.EntityType,
 		"retention_years": policy.RetentionYears,
+		"purge_action":    policy.PurgeAction,
 	})
 
 	return nil
@@ -50,6 +159,9 @@ func (s *RetentionService) List(ctx context.Context) ([]*repository.RetentionPol
 
 // Update updates a retention policy
 func (s *RetentionService) Update(ctx context.Context, policy *repository.RetentionPolicy) error {
+	if err := validatePolicy(policy); err != nil {
+		return err
+	}
 	if err := s.retentionRepo.Update(ctx, policy); err != nil {
 		return err
 	}
@@ -57,6 +169,7 @@ func (s *RetentionService) Update(ctx context.Context, policy *repository.Retent
 	s.auditService.RecordUpdate(ctx, "retention_policy", policy.ID, map[string]interface{}{
 		"entity_type":     policy.EntityType,
 		"retention_years": policy.RetentionYears,
+		"purge_action":    policy.PurgeAction,
 	}, nil)
 
 	return nil
@@ -77,3 +190,50 @@ func (s *RetentionService) Delete(ctx context.Context, id string) error {
 func (s *RetentionService) ValidateDeletion(ctx context.Context, entityType string, recordDate time.Time) error {
 	return s.retentionRepo.ValidateDeletion(ctx, entityType, recordDate)
 }
+
+// --- Legal holds ---
+
+// PlaceHold places a legal hold on an entity type or one record
+func (s *RetentionService) PlaceHold(ctx context.Context, hold *repository.LegalHold) error {
+	if err := s.holdRepo.Create(ctx, hold); err != nil {
+		return err
+	}
+
+	s.auditService.RecordCreate(ctx, "legal_hold", hold.ID, map[string]interface{}{
+		"entity_type": hold.EntityType,
+		"entity_id":   hold.EntityID,
+		"reason":      hold.Reason,
+	})
+
+	return nil
+}
+
+// GetHold gets a legal hold by ID
+func (s *RetentionService) GetHold(ctx context.Context, id string) (*repository.LegalHold, error) {
+	return s.holdRepo.GetByID(ctx, id)
+}
+
+// ListHolds lists legal holds
+func (s *RetentionService) ListHolds(ctx context.Context, q *database.ListQuery) (*database.Page[*repository.LegalHold], error) {
+	return s.holdRepo.List(ctx, q)
+}
+
+// ReleaseHold lifts a legal hold; the records become deletable again once
+// their retention period has ended
+func (s *RetentionService) ReleaseHold(ctx context.Context, hold *repository.LegalHold) error {
+	if err := s.holdRepo.Release(ctx, hold); err != nil {
+		return err
+	}
+
+	s.auditService.RecordUpdate(ctx, "legal_hold", hold.ID, map[string]interface{}{
+		"released_at":  hold.ReleasedAt,
+		"release_note": hold.ReleaseNote,
+	}, nil)
+
+	return nil
+}
+
+// ListProtocols lists the deletion protocol of the purge job
+func (s *RetentionService) ListProtocols(ctx context.Context, q *database.ListQuery) (*database.Page[*retention.Protocol], error) {
+	return s.holdRepo.ListProtocols(ctx, q)
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/pkg/barcode"
+	apperrors "github.com/medflow/medflow-backend/pkg/errors"
+)
+
+// ScanResult is a decoded code with the item and batch it refers to
+type ScanResult struct {
+	Code    *barcode.Code              `json:"code"`
+	Matched bool                       `json:"matched"`
+	Item    *ItemWithBatches           `json:"item,omitempty"`
+	Batch   *repository.InventoryBatch `json:"batch,omitempty"`
+	Prefill ScanPrefill                `json:"prefill"`
+}
+
+// ScanPrefill holds the values a goods receipt or consumption form can take
+// from the scan. Batch number and expiry come from the code even when the
+// batch is not in stock yet.
+type ScanPrefill struct {
+	ItemID       string     `json:"item_id,omitempty"`
+	BatchID      string     `json:"batch_id,omitempty"`
+	BatchNumber  string     `json:"batch_number,omitempty"`
+	ExpiryDate   *time.Time `json:"expiry_date,omitempty"`
+	SerialNumber string     `json:"serial_number,omitempty"`
+}
+
+// ScanCode decodes a scanned GS1, securPharm, HIBC or plain code and looks up
+// the item by GTIN/UDI-DI, then PZN, then the code as scanned. An unknown
+// product is not an error: the result is returned with Matched false so the
+// item can be created from it.
+func (s *InventoryService) ScanCode(ctx context.Context, raw string) (*ScanResult, error) {
+	if raw == "" {
+		return nil, apperrors.BadRequest("validation.required", map[string]string{"field": "code"})
+	}
+
+	code, err := barcode.Parse(raw)
+	if err != nil {
+		return nil, invalidCode(err)
+	}
+
+	result := &ScanResult{
+		Code: code,
+		Prefill: ScanPrefill{
+			BatchNumber:  code.Batch,
+			ExpiryDate:   code.Expiry,
+			SerialNumber: code.Serial,
+		},
+	}
+
+	item, err := s.lookupCode(ctx, code)
+	if apperrors.Is(err, apperrors.ErrNotFound) {
+		if item, err = s.lookupPlain(ctx, code.Raw); apperrors.Is(err, apperrors.ErrNotFound) {
+			return result, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result.Matched = true
+	result.Prefill.ItemID = item.ID
+	if result.Item, err = s.enrichItemWithBatches(ctx, item); err != nil {
+		return nil, err
+	}
+
+	if code.Batch != "" {
+		batch, err := s.batchRepo.GetByItemAndBatchNumber(ctx, item.ID, code.Batch)
+		if err != nil && !apperrors.Is(err, apperrors.ErrNotFound) {
+			return nil, err
+		}
+		if batch != nil {
+			result.Batch = batch
+			result.Prefill.BatchID = batch.ID
+			if batch.ExpiryDate != nil {
+				result.Prefill.ExpiryDate = batch.ExpiryDate
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// lookupCode finds the item for the identifiers decoded from a code: GTIN or
+// UDI-DI in the barcode or udi_di column, then the PZN, then the article
+// number of labels printed for items without either
+func (s *InventoryService) lookupCode(ctx context.Context, code *barcode.Code) (*repository.InventoryItem, error) {
+	if codes := code.ProductCodes(); len(codes) > 0 {
+		item, err := s.itemRepo.GetByProductCode(ctx, codes)
+		if !apperrors.Is(err, apperrors.ErrNotFound) {
+			return item, err
+		}
+	}
+	if code.PZN != "" {
+		item, err := s.itemRepo.GetByPZN(ctx, code.PZN)
+		if !apperrors.Is(err, apperrors.ErrNotFound) {
+			return item, err
+		}
+	}
+	if code.ArticleNumber != "" {
+		return s.itemRepo.GetByArticleNumber(ctx, code.ArticleNumber)
+	}
+	return nil, apperrors.NotFound("item")
+}
+
+// lookupDecoded decodes raw and finds the item for it; a code that does not
+// parse is not found
+func (s *InventoryService) lookupDecoded(ctx context.Context, raw string) (*repository.InventoryItem, error) {
+	code, err := barcode.Parse(raw)
+	if err != nil {
+		return nil, apperrors.NotFound("item")
+	}
+	return s.lookupCode(ctx, code)
+}
+
+// lookupPlain finds the item by the code as scanned in the barcode or article
+// number column
+func (s *InventoryService) lookupPlain(ctx context.Context, raw string) (*repository.InventoryItem, error) {
+	item, err := s.itemRepo.GetByBarcode(ctx, raw)
+	if !apperrors.Is(err, apperrors.ErrNotFound) {
+		return item, err
+	}
+	return s.itemRepo.GetByArticleNumber(ctx, raw)
+}
+
+// invalidCode maps a parse error to a localized bad request
+func invalidCode(err error) error {
+	var codeErr *barcode.Error
+	if apperrors.As(err, &codeErr) {
+		return apperrors.BadRequest("errors.scan.invalid_code", map[string]string{
+			"format": codeErr.Format,
+			"reason": codeErr.Reason,
+		})
+	}
+	return err
+}
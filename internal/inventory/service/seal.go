@@ -0,0 +1,40 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/medflow/medflow-backend/pkg/hmacsig"
+)
+
+// VerifySealInput holds the digest and seal printed on a sealed document
+type VerifySealInput struct {
+	SHA256 string `json:"sha256" validate:"required,len=64,hexadecimal"`
+	Seal   string `json:"seal" validate:"required,len=64,hexadecimal"`
+}
+
+// SealVerification is the result of checking a sealed document. Valid is
+// only true when the digest recomputed from the database matches the stored
+// and the printed digest and the seal was issued with the server key.
+type SealVerification struct {
+	Valid         bool   `json:"valid"`
+	SHA256        string `json:"sha256"`
+	DigestMatches bool   `json:"digest_matches"`
+	SealValid     bool   `json:"seal_valid"`
+}
+
+// verifySeal checks the printed digest and seal of a document against the
+// digest recomputed from the database and the stored digest and seal
+func verifySeal(key, digest string, storedDigest, storedSeal *string, input *VerifySealInput) *SealVerification {
+	printedDigest := strings.ToLower(input.SHA256)
+	printedSeal := strings.ToLower(input.Seal)
+
+	result := &SealVerification{
+		SHA256: digest,
+		DigestMatches: storedDigest != nil && *storedDigest == digest &&
+			printedDigest == digest,
+		SealValid: storedSeal != nil && *storedSeal == printedSeal &&
+			hmacsig.VerifySeal(key, digest, printedSeal),
+	}
+	result.Valid = result.DigestMatches && result.SealValid
+	return result
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+
+	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/medflow/medflow-backend/pkg/search"
+)
+
+// SearchService searches inventory items and hygiene plans
+type SearchService struct {
+	itemRepo    *repository.ItemRepository
+	hygieneRepo *repository.HygieneRepository
+	logger      *logger.Logger
+}
+
+// NewSearchService creates a new search service
+func NewSearchService(itemRepo *repository.ItemRepository, hygieneRepo *repository.HygieneRepository, log *logger.Logger) *SearchService {
+	return &SearchService{
+		itemRepo:    itemRepo,
+		hygieneRepo: hygieneRepo,
+		logger:      log,
+	}
+}
+
+// Search runs the requested searches and merges the results by type
+func (s *SearchService) Search(ctx context.Context, req *search.Request) (*search.Response, error) {
+	var sets [][]*search.Result
+
+	if req.Wants(search.TypeItem) {
+		items, err := s.itemRepo.Search(ctx, req.Query, req.Limit)
+		if err != nil {
+			return nil, err
+		}
+		sets = append(sets, items)
+	}
+
+	if req.Wants(search.TypeHygienePlan) {
+		plans, err := s.hygieneRepo.SearchPlans(ctx, req.Query, req.Limit)
+		if err != nil {
+			return nil, err
+		}
+		sets = append(sets, plans)
+	}
+
+	return &search.Response{
+		Query:  req.Query,
+		Groups: search.Merge(req.Limit, sets...),
+	}, nil
+}
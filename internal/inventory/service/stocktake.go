@@ -0,0 +1,418 @@
+package service
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/lib/pq"
+	"github.com/medflow/medflow-backend/internal/inventory/events"
+	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/hmacsig"
+	"github.com/medflow/medflow-backend/pkg/logger"
+)
+
+// StocktakeService handles stocktake (Inventur) sessions: frozen expected
+// quantities, blind counting, variance review and posting
+type StocktakeService struct {
+	stocktakeRepo *repository.StocktakeRepository
+	auditService  *AuditService
+	publisher     *events.InventoryEventPublisher
+	sealKey       string
+	logger        *logger.Logger
+}
+
+// NewStocktakeService creates a new stocktake service. sealKey seals the
+// protocol digest on approval.
+func NewStocktakeService(stocktakeRepo *repository.StocktakeRepository, auditService *AuditService, publisher *events.InventoryEventPublisher, sealKey string, log *logger.Logger) *StocktakeService {
+	return &StocktakeService{
+		stocktakeRepo: stocktakeRepo,
+		auditService:  auditService,
+		publisher:     publisher,
+		sealKey:       sealKey,
+		logger:        log,
+	}
+}
+
+// StartStocktakeInput is the input for starting a stocktake
+type StartStocktakeInput struct {
+	Title       string   `json:"title" validate:"required,max=255"`
+	LocationIDs []string `json:"location_ids" validate:"omitempty,dive,uuid"`
+	Blind       *bool    `json:"blind"` // default true
+	Notes       *string  `json:"notes"`
+}
+
+// Start starts a stocktake and freezes the expected quantities in scope
+func (s *StocktakeService) Start(ctx context.Context, input *StartStocktakeInput, userID, userName string) (*repository.Stocktake, error) {
+	st := &repository.Stocktake{
+		Title:       input.Title,
+		Blind:       true,
+		LocationIDs: pq.StringArray(input.LocationIDs),
+		Notes:       input.Notes,
+	}
+	if input.Blind != nil {
+		st.Blind = *input.Blind
+	}
+	if userID != "" {
+		st.StartedBy = &userID
+	}
+	if userName != "" {
+		st.StartedByName = &userName
+	}
+
+	if err := s.stocktakeRepo.Create(ctx, st); err != nil {
+		return nil, err
+	}
+
+	s.auditService.RecordCreate(ctx, "stocktake", st.ID, map[string]interface{}{
+		"stocktake_number": st.StocktakeNumber,
+		"title":            st.Title,
+		"blind":            st.Blind,
+		"location_ids":     input.LocationIDs,
+		"lines":            st.LineCount,
+	})
+
+	return s.stocktakeRepo.GetByID(ctx, st.ID)
+}
+
+// Get gets a stocktake by ID
+func (s *StocktakeService) Get(ctx context.Context, id string) (*repository.Stocktake, error) {
+	return s.stocktakeRepo.GetByID(ctx, id)
+}
+
+// List lists stocktakes
+func (s *StocktakeService) List(ctx context.Context, q *database.ListQuery) (*database.Page[*repository.Stocktake], error) {
+	return s.stocktakeRepo.List(ctx, q)
+}
+
+// CountSheetLine is a line of the count sheet. Expected quantities are left
+// out of blind stocktakes until counting is finished.
+type CountSheetLine struct {
+	LineID           string  `json:"line_id"`
+	ItemID           string  `json:"item_id"`
+	ItemName         string  `json:"item_name"`
+	Unit             string  `json:"unit"`
+	BatchID          string  `json:"batch_id"`
+	BatchNumber      string  `json:"batch_number"`
+	ExpiryDate       *string `json:"expiry_date,omitempty"`
+	LocationID       *string `json:"location_id,omitempty"`
+	ExpectedQuantity *int    `json:"expected_quantity,omitempty"`
+	CountedQuantity  *int    `json:"counted_quantity,omitempty"`
+}
+
+// CountSheet returns the lines to count
+func (s *StocktakeService) CountSheet(ctx context.Context, id string) ([]*CountSheetLine, error) {
+	st, err := s.stocktakeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	lines, err := s.stocktakeRepo.ListLines(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	sheet := make([]*CountSheetLine, len(lines))
+	for i, l := range lines {
+		sheet[i] = newCountSheetLine(st, l)
+	}
+	return sheet, nil
+}
+
+// newCountSheetLine builds a count sheet line, leaving out the expected
+// quantity while a blind stocktake is being counted
+func newCountSheetLine(st *repository.Stocktake, l *repository.StocktakeLine) *CountSheetLine {
+	line := &CountSheetLine{
+		LineID:          l.ID,
+		ItemID:          l.ItemID,
+		ItemName:        l.ItemName,
+		Unit:            l.Unit,
+		BatchID:         l.BatchID,
+		BatchNumber:     l.BatchNumber,
+		LocationID:      l.LocationID,
+		CountedQuantity: l.CountedQuantity,
+	}
+	if l.ExpiryDate != nil {
+		expiry := l.ExpiryDate.Format("2006-01-02")
+		line.ExpiryDate = &expiry
+	}
+	if !st.Blind || st.Status != repository.StocktakeCounting {
+		expected := l.ExpectedQuantity
+		line.ExpectedQuantity = &expected
+	}
+	return line
+}
+
+// CountInput is a count sheet entry. The line is given directly or found by
+// scanning the item code (barcode, article number or PZN) and batch number.
+type CountInput struct {
+	LineID      *string `json:"line_id" validate:"omitempty,uuid"`
+	Code        *string `json:"code" validate:"omitempty,max=200"`
+	BatchNumber *string `json:"batch_number" validate:"omitempty,max=100"`
+	Quantity    *int    `json:"quantity"` // scans default to 1; negative corrects a miscount
+	Method      string  `json:"method" validate:"omitempty,oneof=manual scan"`
+}
+
+// Count adds a count sheet entry and returns the updated count sheet line
+func (s *StocktakeService) Count(ctx context.Context, id string, input *CountInput, userID, userName string) (*CountSheetLine, error) {
+	method := input.Method
+	if method == "" {
+		method = "manual"
+		if input.LineID == nil {
+			method = "scan"
+		}
+	}
+
+	var quantity int
+	switch {
+	case input.Quantity != nil:
+		quantity = *input.Quantity
+	case method == "scan":
+		quantity = 1
+	default:
+		return nil, errors.Validation(nil).WithDetail("quantity", "validation.required", map[string]string{"field": "quantity"})
+	}
+
+	lineID, err := s.resolveLine(ctx, id, input)
+	if err != nil {
+		return nil, err
+	}
+
+	count := &repository.StocktakeCount{
+		StocktakeID: id,
+		LineID:      lineID,
+		Quantity:    quantity,
+		Method:      method,
+	}
+	if userID != "" {
+		count.CountedBy = &userID
+	}
+	if userName != "" {
+		count.CountedByName = &userName
+	}
+
+	line, err := s.stocktakeRepo.AddCount(ctx, count)
+	if err != nil {
+		return nil, err
+	}
+
+	// Counting is only possible while the stocktake is counting, so a blind
+	// stocktake never reveals the expected quantity here
+	st, err := s.stocktakeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return newCountSheetLine(st, line), nil
+}
+
+// resolveLine finds the line a count entry is for
+func (s *StocktakeService) resolveLine(ctx context.Context, id string, input *CountInput) (string, error) {
+	if input.LineID != nil {
+		return *input.LineID, nil
+	}
+
+	code, batchNumber := "", ""
+	if input.Code != nil {
+		code = *input.Code
+	}
+	if input.BatchNumber != nil {
+		batchNumber = *input.BatchNumber
+	}
+	if code == "" && batchNumber == "" {
+		return "", errors.Validation(nil).WithDetail("line_id", "validation.required", map[string]string{"field": "line_id"})
+	}
+
+	lines, err := s.stocktakeRepo.FindLines(ctx, id, code, batchNumber)
+	if err != nil {
+		return "", err
+	}
+	switch len(lines) {
+	case 0:
+		return "", errors.NotFound("stocktake_line")
+	case 1:
+		return lines[0].ID, nil
+	default:
+		return "", errors.BadRequest("errors.stocktake.ambiguous_scan", map[string]string{"count": strconv.Itoa(len(lines))})
+	}
+}
+
+// ListCounts lists the count sheet entries of a stocktake
+func (s *StocktakeService) ListCounts(ctx context.Context, id string) ([]*repository.StocktakeCount, error) {
+	if _, err := s.stocktakeRepo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.stocktakeRepo.ListCounts(ctx, id)
+}
+
+// FinishCounting closes counting and makes variances visible for review
+func (s *StocktakeService) FinishCounting(ctx context.Context, id string) (*repository.Stocktake, error) {
+	if err := s.stocktakeRepo.FinishCounting(ctx, id); err != nil {
+		return nil, err
+	}
+	s.auditService.RecordAction(ctx, "stocktake", id, "finish_counting", nil)
+	return s.stocktakeRepo.GetByID(ctx, id)
+}
+
+// Reopen returns a stocktake under review to counting for recounts
+func (s *StocktakeService) Reopen(ctx context.Context, id string) (*repository.Stocktake, error) {
+	if err := s.stocktakeRepo.Reopen(ctx, id); err != nil {
+		return nil, err
+	}
+	s.auditService.RecordAction(ctx, "stocktake", id, "reopen", nil)
+	return s.stocktakeRepo.GetByID(ctx, id)
+}
+
+// Cancel cancels a stocktake without posting anything
+func (s *StocktakeService) Cancel(ctx context.Context, id string) (*repository.Stocktake, error) {
+	if err := s.stocktakeRepo.Cancel(ctx, id); err != nil {
+		return nil, err
+	}
+	s.auditService.RecordAction(ctx, "stocktake", id, "cancel", nil)
+	return s.stocktakeRepo.GetByID(ctx, id)
+}
+
+// VarianceReport compares counted with frozen expected quantities, valued at
+// the unit prices frozen at the start
+type VarianceReport struct {
+	Stocktake          *repository.Stocktake       `json:"stocktake"`
+	Lines              []*repository.StocktakeLine `json:"lines"`
+	ExpectedValueCents int64                       `json:"expected_value_cents"`
+	CountedValueCents  int64                       `json:"counted_value_cents"`
+	SurplusValueCents  int64                       `json:"surplus_value_cents"`
+	ShortageValueCents int64                       `json:"shortage_value_cents"`
+	VarianceValueCents int64                       `json:"variance_value_cents"`
+	LinesWithVariance  int                         `json:"lines_with_variance"`
+	UncountedLines     int                         `json:"uncounted_lines"`
+}
+
+// Variance builds the variance report. Blind stocktakes have no report
+// until counting is finished.
+func (s *StocktakeService) Variance(ctx context.Context, id string) (*VarianceReport, error) {
+	st, err := s.stocktakeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if st.Blind && st.Status == repository.StocktakeCounting {
+		return nil, errors.Conflict("errors.stocktake.blind_counting")
+	}
+
+	lines, err := s.stocktakeRepo.ListLines(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return buildVarianceReport(st, lines), nil
+}
+
+// buildVarianceReport totals the variances of a stocktake's lines
+func buildVarianceReport(st *repository.Stocktake, lines []*repository.StocktakeLine) *VarianceReport {
+	report := &VarianceReport{Stocktake: st, Lines: lines}
+	for _, l := range lines {
+		price := int64(l.UnitPriceCents)
+		report.ExpectedValueCents += int64(l.ExpectedQuantity) * price
+		if l.CountedQuantity == nil {
+			report.UncountedLines++
+		} else {
+			report.CountedValueCents += int64(*l.CountedQuantity) * price
+		}
+
+		if l.Variance() == 0 {
+			continue
+		}
+		report.LinesWithVariance++
+		value := l.VarianceValueCents()
+		if value > 0 {
+			report.SurplusValueCents += value
+		} else {
+			report.ShortageValueCents += -value
+		}
+		report.VarianceValueCents += value
+	}
+	return report
+}
+
+// Approve posts all variances as stock adjustments in one transaction and
+// seals the stocktake for the protocol
+func (s *StocktakeService) Approve(ctx context.Context, id, userID, userName string) (*repository.Stocktake, error) {
+	var approvedBy, approvedByName *string
+	if userID != "" {
+		approvedBy = &userID
+	}
+	if userName != "" {
+		approvedByName = &userName
+	}
+
+	seal := func(digest string) string { return hmacsig.Seal(s.sealKey, digest) }
+	adjustments, err := s.stocktakeRepo.Approve(ctx, id, approvedBy, approvedByName, seal)
+	if err != nil {
+		return nil, err
+	}
+
+	st, err := s.stocktakeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := map[string]interface{}{
+		"stocktake_number": st.StocktakeNumber,
+		"adjustments":      len(adjustments),
+		"protocol_sha256":  st.ProtocolSHA256,
+		"protocol_seal":    st.ProtocolSeal,
+	}
+	if st.VarianceValueCents != nil {
+		metadata["variance_value_cents"] = *st.VarianceValueCents
+	}
+	s.auditService.RecordAction(ctx, "stocktake", id, "approve", metadata)
+
+	for _, adj := range adjustments {
+		s.publisher.PublishStockAdjusted(ctx, adj)
+	}
+
+	return st, nil
+}
+
+// Protocol generates the signed stocktake protocol PDF of an approved stocktake
+func (s *StocktakeService) Protocol(ctx context.Context, id string) (*repository.Stocktake, []byte, error) {
+	st, err := s.stocktakeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if st.Status != repository.StocktakeApproved {
+		return nil, nil, errors.Conflict("errors.stocktake.not_approved")
+	}
+
+	lines, err := s.stocktakeRepo.ListLines(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	counts, err := s.stocktakeRepo.ListCounts(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pdf, err := renderStocktakeProtocol(buildVarianceReport(st, lines), counts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return st, pdf, nil
+}
+
+// VerifyProtocol checks a stocktake protocol against the database: the digest
+// recomputed from the approved lines must match the one sealed on approval
+// and the one printed on the protocol, and the seal must have been issued
+// with the server key
+func (s *StocktakeService) VerifyProtocol(ctx context.Context, id string, input *VerifySealInput) (*SealVerification, error) {
+	st, err := s.stocktakeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if st.Status != repository.StocktakeApproved {
+		return nil, errors.Conflict("errors.stocktake.not_approved")
+	}
+
+	lines, err := s.stocktakeRepo.ListLines(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return verifySeal(s.sealKey, repository.StocktakeDigest(st, lines), st.ProtocolSHA256, st.ProtocolSeal, input), nil
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/medflow/medflow-backend/internal/inventory/repository"
+)
+
+// renderStocktakeProtocol renders the stocktake protocol (Inventurprotokoll)
+// for the tax advisor: every line with expected and counted quantity and the
+// valued variance, the counters, the approval and the SHA-256 digest with
+// the seal issued on approval
+func renderStocktakeProtocol(report *VarianceReport, counts []*repository.StocktakeCount) ([]byte, error) {
+	st := report.Stocktake
+
+	pdf := fpdf.New("L", "mm", "A4", "")
+	pdf.SetAutoPageBreak(true, 20)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	// Header
+	pdf.SetHeaderFunc(func() {
+		pdf.SetFont("Arial", "B", 14)
+		pdf.Cell(0, 10, tr(fmt.Sprintf("Inventurprotokoll %s - %s", st.StocktakeNumber, st.Title)))
+		pdf.Ln(6)
+		pdf.SetFont("Arial", "", 9)
+		pdf.Cell(0, 6, tr(fmt.Sprintf("Beginn: %s | Freigabe: %s | Erstellt: %s",
+			st.StartedAt.Format("2006-01-02 15:04"), formatTimePtr(st.ApprovedAt), time.Now().Format("2006-01-02 15:04"))))
+		pdf.Ln(10)
+	})
+
+	// Footer carries the digest and seal on every page
+	digest := deref(st.ProtocolSHA256)
+	seal := deref(st.ProtocolSeal)
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-15)
+		pdf.SetFont("Arial", "I", 7)
+		pdf.CellFormat(0, 5, fmt.Sprintf("SHA-256: %s | Siegel: %s", digest, seal), "", 1, "C", false, 0, "")
+		pdf.CellFormat(0, 5, fmt.Sprintf("Page %d/{nb} - Generated by MedFlow", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+	pdf.AliasNbPages("")
+
+	pdf.AddPage()
+
+	// Table header
+	colWidths := []float64{60, 30, 22, 22, 20, 20, 20, 25, 25, 28}
+	headers := []string{"Artikel", "Charge", "Verfall", "Einheit", "Soll", "Ist", "Differenz", "Preis (EUR)", "Wert Ist (EUR)", "Differenz (EUR)"}
+
+	pdf.SetFont("Arial", "B", 8)
+	pdf.SetFillColor(220, 220, 220)
+	for i, h := range headers {
+		pdf.CellFormat(colWidths[i], 7, tr(h), "1", 0, "C", true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	// Table rows
+	pdf.SetFont("Arial", "", 7)
+	fill := false
+	for _, l := range report.Lines {
+		if fill {
+			pdf.SetFillColor(245, 245, 245)
+		} else {
+			pdf.SetFillColor(255, 255, 255)
+		}
+
+		counted := 0
+		if l.CountedQuantity != nil {
+			counted = *l.CountedQuantity
+		}
+
+		pdf.CellFormat(colWidths[0], 6, tr(truncate(l.ItemName, 40)), "1", 0, "L", fill, 0, "")
+		pdf.CellFormat(colWidths[1], 6, tr(truncate(l.BatchNumber, 20)), "1", 0, "L", fill, 0, "")
+		pdf.CellFormat(colWidths[2], 6, formatDatePtr(l.ExpiryDate), "1", 0, "C", fill, 0, "")
+		pdf.CellFormat(colWidths[3], 6, tr(l.Unit), "1", 0, "C", fill, 0, "")
+		pdf.CellFormat(colWidths[4], 6, fmt.Sprintf("%d", l.ExpectedQuantity), "1", 0, "R", fill, 0, "")
+		pdf.CellFormat(colWidths[5], 6, fmt.Sprintf("%d", counted), "1", 0, "R", fill, 0, "")
+		pdf.CellFormat(colWidths[6], 6, fmt.Sprintf("%+d", l.Variance()), "1", 0, "R", fill, 0, "")
+		pdf.CellFormat(colWidths[7], 6, formatCents(int64(l.UnitPriceCents)), "1", 0, "R", fill, 0, "")
+		pdf.CellFormat(colWidths[8], 6, formatCents(int64(counted)*int64(l.UnitPriceCents)), "1", 0, "R", fill, 0, "")
+		pdf.CellFormat(colWidths[9], 6, formatCents(l.VarianceValueCents()), "1", 0, "R", fill, 0, "")
+		pdf.Ln(-1)
+
+		fill = !fill
+	}
+
+	// Totals
+	pdf.Ln(4)
+	pdf.SetFont("Arial", "B", 9)
+	totals := [][2]string{
+		{"Positionen", fmt.Sprintf("%d (davon mit Differenz: %d)", len(report.Lines), report.LinesWithVariance)},
+		{"Buchwert Soll", formatCents(report.ExpectedValueCents) + " EUR"},
+		{"Wert Ist", formatCents(report.CountedValueCents) + " EUR"},
+		{"Mehrbestand", formatCents(report.SurplusValueCents) + " EUR"},
+		{"Fehlbestand", formatCents(report.ShortageValueCents) + " EUR"},
+		{"Inventurdifferenz", formatCents(report.VarianceValueCents) + " EUR"},
+	}
+	for _, t := range totals {
+		pdf.CellFormat(50, 6, tr(t[0]), "", 0, "L", false, 0, "")
+		pdf.SetFont("Arial", "", 9)
+		pdf.CellFormat(0, 6, tr(t[1]), "", 1, "L", false, 0, "")
+		pdf.SetFont("Arial", "B", 9)
+	}
+
+	// Signatures: counters from the count sheets, approver from the approval
+	pdf.Ln(6)
+	pdf.SetFont("Arial", "B", 10)
+	pdf.Cell(0, 6, "Unterschriften")
+	pdf.Ln(8)
+	pdf.SetFont("Arial", "", 9)
+	pdf.CellFormat(50, 6, tr("Gezählt von:"), "", 0, "L", false, 0, "")
+	pdf.MultiCell(0, 6, tr(strings.Join(stocktakeCounters(counts), ", ")), "", "L", false)
+	pdf.CellFormat(50, 6, "Freigegeben von:", "", 0, "L", false, 0, "")
+	pdf.CellFormat(0, 6, tr(fmt.Sprintf("%s am %s", deref(st.ApprovedByName), formatTimePtr(st.ApprovedAt))), "", 1, "L", false, 0, "")
+	pdf.CellFormat(50, 6, "Protokoll-Hash (SHA-256):", "", 0, "L", false, 0, "")
+	pdf.SetFont("Courier", "", 8)
+	pdf.CellFormat(0, 6, digest, "", 1, "L", false, 0, "")
+	pdf.SetFont("Arial", "", 9)
+	pdf.CellFormat(50, 6, "Protokoll-Siegel:", "", 0, "L", false, 0, "")
+	pdf.SetFont("Courier", "", 8)
+	pdf.CellFormat(0, 6, seal, "", 1, "L", false, 0, "")
+
+	pdf.Ln(14)
+	pdf.SetFont("Arial", "", 9)
+	pdf.CellFormat(110, 6, "", "B", 0, "L", false, 0, "")
+	pdf.CellFormat(20, 6, "", "", 0, "L", false, 0, "")
+	pdf.CellFormat(110, 6, "", "B", 1, "L", false, 0, "")
+	pdf.CellFormat(110, 6, tr("Ort, Datum, Unterschrift Zählverantwortliche/r"), "", 0, "L", false, 0, "")
+	pdf.CellFormat(20, 6, "", "", 0, "L", false, 0, "")
+	pdf.CellFormat(110, 6, tr("Ort, Datum, Unterschrift Praxisinhaber/in"), "", 1, "L", false, 0, "")
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to generate PDF: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// stocktakeCounters lists the distinct people who entered counts
+func stocktakeCounters(counts []*repository.StocktakeCount) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, c := range counts {
+		name := deref(c.CountedByName)
+		if name == "" {
+			name = deref(c.CountedBy)
+		}
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// formatCents formats cents as euros with a decimal comma
+func formatCents(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d,%02d", sign, cents/100, cents%100)
+}
+
+func formatDatePtr(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+func formatTimePtr(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format("2006-01-02 15:04")
+}
@@ -0,0 +1,674 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/pkg/hmacsig"
+)
+
+// TemperatureReport is the fridge log (Kühlschrankprotokoll) of a cabinet
+// for a period, as pharmacy inspectors ask for it
+type TemperatureReport struct {
+	Cabinet  *repository.StorageCabinet
+	RoomName string
+	From     time.Time // first day (Europe/Berlin)
+	To       time.Time // last day, inclusive
+
+	// Days has an entry for every day of the period; days without readings
+	// have a ReadingCount of 0
+	Days               []*DailyTemperatureSummary
+	ReadingCount       int
+	MinCelsius         float64
+	MaxCelsius         float64
+	MeanKineticCelsius float64
+
+	Excursions []*repository.TemperatureExcursion
+	Checks     []*repository.TemperatureReading // manual readings with who took them
+
+	// SHA256 is the digest over the readings, excursions and decisions the
+	// report shows, and Seal its HMAC with the server key. Both are printed
+	// on every page; generating the report again yields the same digest and
+	// seal as long as the records are unchanged.
+	SHA256 string
+	Seal   string
+}
+
+// TemperatureReport builds the fridge log of a cabinet for the days
+// from..to (inclusive) and renders it as PDF
+func (s *ColdChainService) TemperatureReport(ctx context.Context, cabinetID string, from, to time.Time) (*TemperatureReport, []byte, error) {
+	cabinet, err := s.locationRepo.GetCabinet(ctx, cabinetID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	report, err := s.buildTemperatureReport(ctx, cabinet, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pdf, err := renderTemperatureReports([]*TemperatureReport{report}, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	return report, pdf, nil
+}
+
+// TemperatureReports renders the fridge logs of all monitored cabinets for
+// the days from..to (inclusive) into one PDF, one section per cabinet
+func (s *ColdChainService) TemperatureReports(ctx context.Context, from, to time.Time) ([]byte, error) {
+	cabinets, err := s.locationRepo.ListAllCabinets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var reports []*TemperatureReport
+	for _, cabinet := range cabinets {
+		if !cabinet.TemperatureMonitoringEnabled {
+			continue
+		}
+		report, err := s.buildTemperatureReport(ctx, cabinet, from, to)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+
+	return renderTemperatureReports(reports, from, to)
+}
+
+func (s *ColdChainService) buildTemperatureReport(ctx context.Context, cabinet *repository.StorageCabinet, from, to time.Time) (*TemperatureReport, error) {
+	loc := berlin()
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+	end := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, 1)
+
+	report := &TemperatureReport{Cabinet: cabinet, From: start, To: end.AddDate(0, 0, -1)}
+	if room, err := s.locationRepo.GetRoom(ctx, cabinet.RoomID); err == nil {
+		report.RoomName = room.Name
+	}
+
+	readings, err := s.temperatureRepo.ListBetween(ctx, cabinet.ID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if report.Excursions, err = s.excursionRepo.ListForPeriod(ctx, cabinet.ID, start, end); err != nil {
+		return nil, err
+	}
+	if report.Checks, err = s.temperatureRepo.ListManualBetween(ctx, cabinet.ID, start, end); err != nil {
+		return nil, err
+	}
+
+	byDate := make(map[string]*DailyTemperatureSummary)
+	for _, d := range summarizeDays(readings, loc) {
+		byDate[d.Date] = d
+	}
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		if d, ok := byDate[date]; ok {
+			report.Days = append(report.Days, d)
+		} else {
+			report.Days = append(report.Days, &DailyTemperatureSummary{Date: date})
+		}
+	}
+
+	temps := make([]float64, 0, len(readings))
+	for i, reading := range readings {
+		t := reading.TemperatureCelsius
+		if i == 0 {
+			report.MinCelsius, report.MaxCelsius = t, t
+		}
+		report.MinCelsius = math.Min(report.MinCelsius, t)
+		report.MaxCelsius = math.Max(report.MaxCelsius, t)
+		temps = append(temps, t)
+	}
+	report.ReadingCount = len(readings)
+	report.MeanKineticCelsius = round2(MeanKineticTemperature(temps))
+
+	report.SHA256 = temperatureReportDigest(report, readings)
+	report.Seal = hmacsig.Seal(s.sealKey, report.SHA256)
+	return report, nil
+}
+
+// VerifyTemperatureReport checks the fridge log of a cabinet for the days
+// from..to (inclusive) against the database. Reports are not stored: the
+// digest is recomputed from the records and must match the printed one, and
+// the printed seal must be the one the server key issues for it.
+func (s *ColdChainService) VerifyTemperatureReport(ctx context.Context, cabinetID string, from, to time.Time, input *VerifySealInput) (*SealVerification, error) {
+	cabinet, err := s.locationRepo.GetCabinet(ctx, cabinetID)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := s.buildTemperatureReport(ctx, cabinet, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return verifySeal(s.sealKey, report.SHA256, &report.SHA256, &report.Seal, input), nil
+}
+
+// temperatureReportDigest is the SHA-256 over the cabinet and period of a
+// report, every reading in it and every excursion with its decision
+func temperatureReportDigest(report *TemperatureReport, readings []*repository.TemperatureReading) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s|%s|%s|%s\n", report.Cabinet.ID, report.Cabinet.Name,
+		report.From.Format("2006-01-02"), report.To.Format("2006-01-02"))
+	for _, r := range readings {
+		fmt.Fprintf(h, "r|%s|%s|%.2f|%s|%s|%s\n", r.ID, r.RecordedAt.UTC().Format(time.RFC3339Nano),
+			r.TemperatureCelsius, r.Source, deref(r.RecordedBy), deref(r.Notes))
+	}
+	for _, e := range report.Excursions {
+		fmt.Fprintf(h, "e|%s|%s|%s|%s|%d|%s|%s|%s\n", e.ID, e.Status, e.StartedAt.UTC().Format(time.RFC3339Nano),
+			utcPtr(e.EndedAt), e.DurationSeconds, deref(e.DecisionReason), deref(e.DecidedBy), utcPtr(e.DecidedAt))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func utcPtr(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// excursionStatusLabels names excursion statuses on the report
+var excursionStatusLabels = map[string]string{
+	repository.ExcursionPending:   "laufend",
+	repository.ExcursionTransient: "kurzzeitig (Toleranz)",
+	repository.ExcursionConfirmed: "Bewertung offen",
+	repository.ExcursionReleased:  "freigegeben",
+	repository.ExcursionDiscarded: "verworfen",
+}
+
+// renderTemperatureReports renders fridge logs into one PDF: per cabinet the
+// daily min/max table, a chart of the daily extremes against the allowed
+// range, the excursions with the documented decision, the manual checks and
+// the signature block. The footer carries the section's digest and seal.
+func renderTemperatureReports(reports []*TemperatureReport, from, to time.Time) ([]byte, error) {
+	pdf := fpdf.New("L", "mm", "A4", "")
+	pdf.SetAutoPageBreak(true, 20)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	loc := berlin()
+	generated := time.Now().In(loc).Format("2006-01-02 15:04")
+
+	// The header of a page starts the section of the next report, so the
+	// footer of the previous page still shows the previous digest
+	var current, next *TemperatureReport
+
+	// Header
+	pdf.SetHeaderFunc(func() {
+		current = next
+		pdf.SetFont("Arial", "B", 14)
+		title := "Kühlschrankprotokoll"
+		if current != nil {
+			title += " - " + cabinetLabel(current)
+		}
+		pdf.Cell(0, 10, tr(title))
+		pdf.Ln(6)
+		pdf.SetFont("Arial", "", 9)
+		line := fmt.Sprintf("Zeitraum: %s bis %s", from.Format("2006-01-02"), to.Format("2006-01-02"))
+		if current != nil {
+			line += " | Sollbereich: " + allowedRange(current.Cabinet)
+		}
+		pdf.Cell(0, 6, tr(line+" | Erstellt: "+generated))
+		pdf.Ln(10)
+	})
+
+	// Footer
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-15)
+		pdf.SetFont("Arial", "I", 7)
+		if current != nil {
+			pdf.CellFormat(0, 5, fmt.Sprintf("SHA-256: %s | Siegel: %s", current.SHA256, current.Seal), "", 1, "C", false, 0, "")
+		}
+		pdf.CellFormat(0, 5, fmt.Sprintf("Page %d/{nb} - Generated by MedFlow", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+	pdf.AliasNbPages("")
+
+	if len(reports) == 0 {
+		pdf.AddPage()
+		pdf.SetFont("Arial", "I", 10)
+		pdf.Ln(5)
+		pdf.Cell(0, 10, tr("Keine überwachten Kühlschränke / No monitored cabinets."))
+	}
+
+	for _, report := range reports {
+		next = report
+		pdf.AddPage()
+		renderTemperatureSummary(pdf, tr, report)
+		renderTemperatureChart(pdf, tr, report)
+		renderDailyTemperatureTable(pdf, tr, report)
+		renderExcursionTable(pdf, tr, report, loc)
+		renderCheckTable(pdf, tr, report, loc)
+		renderTemperatureSignatures(pdf, tr, report)
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to generate PDF: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func renderTemperatureSummary(pdf *fpdf.Fpdf, tr func(string) string, report *TemperatureReport) {
+	missing := 0
+	excursionReadings := 0
+	for _, d := range report.Days {
+		if d.ReadingCount == 0 {
+			missing++
+		}
+		excursionReadings += d.ExcursionReadings
+	}
+
+	extremes := "-"
+	if report.ReadingCount > 0 {
+		extremes = fmt.Sprintf("%s / %s °C (MKT %s °C)", formatCelsius(report.MinCelsius),
+			formatCelsius(report.MaxCelsius), formatCelsius(report.MeanKineticCelsius))
+	}
+	rows := [][2]string{
+		{"Messwerte", fmt.Sprintf("%d (außerhalb Sollbereich: %d)", report.ReadingCount, excursionReadings)},
+		{"Min / Max", extremes},
+		{"Abweichungen", fmt.Sprintf("%d", len(report.Excursions))},
+		{"Tage ohne Messung", fmt.Sprintf("%d von %d", missing, len(report.Days))},
+	}
+	for _, row := range rows {
+		pdf.SetFont("Arial", "B", 9)
+		pdf.CellFormat(40, 5, tr(row[0]), "", 0, "L", false, 0, "")
+		pdf.SetFont("Arial", "", 9)
+		pdf.CellFormat(0, 5, tr(row[1]), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(4)
+}
+
+// renderTemperatureChart plots the daily minimum and maximum against the
+// allowed range of the cabinet
+func renderTemperatureChart(pdf *fpdf.Fpdf, tr func(string) string, report *TemperatureReport) {
+	const h = 60.0
+	left, _, right, _ := pdf.GetMargins()
+	pageW, _ := pdf.GetPageSize()
+	x := left + 12
+	w := pageW - right - x
+	if pdf.GetY()+h+14 > 190 {
+		pdf.AddPage()
+	}
+	y := pdf.GetY()
+
+	// Scale to the readings and the allowed range
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, d := range report.Days {
+		if d.ReadingCount > 0 {
+			lo, hi = math.Min(lo, d.MinCelsius), math.Max(hi, d.MaxCelsius)
+		}
+	}
+	for _, limit := range []*float64{report.Cabinet.MinTemperature, report.Cabinet.MaxTemperature} {
+		if limit != nil {
+			lo, hi = math.Min(lo, *limit), math.Max(hi, *limit)
+		}
+	}
+	if math.IsInf(lo, 0) {
+		lo, hi = 0, 10
+	}
+	lo, hi = math.Floor(lo-1), math.Ceil(hi+1)
+	step := 1.0
+	for _, s := range []float64{1, 2, 5, 10, 20} {
+		step = s
+		if (hi-lo)/s <= 8 {
+			break
+		}
+	}
+	lo = math.Floor(lo/step) * step
+	hi = math.Ceil(hi/step) * step
+
+	py := func(t float64) float64 { return y + h - (t-lo)/(hi-lo)*h }
+	n := float64(len(report.Days))
+	px := func(i int) float64 { return x + (float64(i)+0.5)*w/n }
+
+	// Allowed range
+	minT, maxT := report.Cabinet.MinTemperature, report.Cabinet.MaxTemperature
+	if minT != nil && maxT != nil {
+		pdf.SetFillColor(228, 244, 228)
+		pdf.Rect(x, py(*maxT), w, py(*minT)-py(*maxT), "F")
+	}
+
+	// Grid and axis labels
+	pdf.SetFont("Arial", "", 6)
+	pdf.SetDrawColor(210, 210, 210)
+	pdf.SetLineWidth(0.1)
+	for t := lo; t <= hi+step/2; t += step {
+		pdf.Line(x, py(t), x+w, py(t))
+		pdf.SetXY(x-12, py(t)-2)
+		pdf.CellFormat(11, 4, tr(formatCelsius(t)+" °C"), "", 0, "R", false, 0, "")
+	}
+	every := int(math.Ceil(n / 31))
+	for i, d := range report.Days {
+		if i%every != 0 {
+			continue
+		}
+		pdf.SetXY(px(i)-5, y+h+1)
+		pdf.CellFormat(10, 3, d.Date[8:10]+"."+d.Date[5:7]+".", "", 0, "C", false, 0, "")
+	}
+	pdf.SetDrawColor(0, 0, 0)
+	pdf.Rect(x, y, w, h, "D")
+
+	// Limits
+	pdf.SetDrawColor(200, 0, 0)
+	pdf.SetDashPattern([]float64{1, 1}, 0)
+	for _, limit := range []*float64{minT, maxT} {
+		if limit != nil {
+			pdf.Line(x, py(*limit), x+w, py(*limit))
+		}
+	}
+	pdf.SetDashPattern([]float64{}, 0)
+
+	// Daily extremes; a day without readings breaks the line
+	series := []struct {
+		r, g, b int
+		value   func(*DailyTemperatureSummary) float64
+	}{
+		{210, 60, 40, func(d *DailyTemperatureSummary) float64 { return d.MaxCelsius }},
+		{40, 90, 200, func(d *DailyTemperatureSummary) float64 { return d.MinCelsius }},
+	}
+	pdf.SetLineWidth(0.4)
+	for _, s := range series {
+		pdf.SetDrawColor(s.r, s.g, s.b)
+		pdf.SetFillColor(s.r, s.g, s.b)
+		for i, d := range report.Days {
+			if d.ReadingCount == 0 {
+				continue
+			}
+			if i > 0 && report.Days[i-1].ReadingCount > 0 {
+				pdf.Line(px(i-1), py(s.value(report.Days[i-1])), px(i), py(s.value(d)))
+			}
+			pdf.Circle(px(i), py(s.value(d)), 0.6, "F")
+		}
+	}
+	pdf.SetLineWidth(0.2)
+	pdf.SetDrawColor(0, 0, 0)
+
+	// Legend
+	pdf.SetXY(x, y+h+5)
+	pdf.SetFont("Arial", "", 7)
+	pdf.SetTextColor(210, 60, 40)
+	pdf.CellFormat(30, 4, tr("Tageshöchstwert"), "", 0, "L", false, 0, "")
+	pdf.SetTextColor(40, 90, 200)
+	pdf.CellFormat(30, 4, tr("Tagestiefstwert"), "", 0, "L", false, 0, "")
+	pdf.SetTextColor(200, 0, 0)
+	pdf.CellFormat(40, 4, tr("Grenzen des Sollbereichs"), "", 1, "L", false, 0, "")
+	pdf.SetTextColor(0, 0, 0)
+	pdf.SetX(left)
+	pdf.Ln(4)
+}
+
+func renderDailyTemperatureTable(pdf *fpdf.Fpdf, tr func(string) string, report *TemperatureReport) {
+	checkers := make(map[string][]string)
+	for _, c := range report.Checks {
+		date := c.RecordedAt.In(berlin()).Format("2006-01-02")
+		checkers[date] = appendUnique(checkers[date], checkerName(c))
+	}
+
+	colWidths := []float64{25, 22, 22, 22, 22, 22, 27, 115}
+	headers := []string{"Datum", "Messwerte", "Min (°C)", "Max (°C)", "Mittel (°C)", "MKT (°C)", "Abweichungen", "Kontrolliert von"}
+
+	header := func() {
+		pdf.SetFont("Arial", "B", 8)
+		pdf.SetFillColor(220, 220, 220)
+		for i, h := range headers {
+			pdf.CellFormat(colWidths[i], 7, tr(h), "1", 0, "C", true, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetFont("Arial", "", 7)
+	}
+	header()
+
+	fill := false
+	for _, d := range report.Days {
+		if pdf.GetY()+6 > 190 {
+			pdf.AddPage()
+			header()
+		}
+		if d.ReadingCount > 0 && d.ExcursionReadings > 0 {
+			pdf.SetFillColor(253, 228, 222)
+		} else if fill {
+			pdf.SetFillColor(245, 245, 245)
+		} else {
+			pdf.SetFillColor(255, 255, 255)
+		}
+
+		values := []string{d.Date, "0", "-", "-", "-", "-", "-"}
+		if d.ReadingCount > 0 {
+			values = []string{d.Date, fmt.Sprintf("%d", d.ReadingCount), formatCelsius(d.MinCelsius), formatCelsius(d.MaxCelsius),
+				formatCelsius(d.MeanCelsius), formatCelsius(d.MeanKineticCelsius), fmt.Sprintf("%d", d.ExcursionReadings)}
+		}
+		for i, v := range values {
+			align := "R"
+			if i == 0 {
+				align = "C"
+			}
+			pdf.CellFormat(colWidths[i], 6, v, "1", 0, align, true, 0, "")
+		}
+		pdf.CellFormat(colWidths[7], 6, tr(truncate(strings.Join(checkers[d.Date], ", "), 80)), "1", 0, "L", true, 0, "")
+		pdf.Ln(-1)
+
+		fill = !fill
+	}
+	pdf.Ln(6)
+}
+
+func renderExcursionTable(pdf *fpdf.Fpdf, tr func(string) string, report *TemperatureReport, loc *time.Location) {
+	pdf.SetFont("Arial", "B", 10)
+	pdf.Cell(0, 6, tr("Abweichungen vom Sollbereich und Maßnahmen"))
+	pdf.Ln(8)
+
+	if len(report.Excursions) == 0 {
+		pdf.SetFont("Arial", "I", 9)
+		pdf.Cell(0, 6, tr("Keine Abweichungen im Zeitraum."))
+		pdf.Ln(10)
+		return
+	}
+
+	colWidths := []float64{32, 32, 25, 20, 20, 38, 60, 50}
+	headers := []string{"Beginn", "Ende", "Dauer", "Min (°C)", "Max (°C)", "Status", "Bewertet von", "Bewertet am"}
+
+	for _, e := range report.Excursions {
+		if pdf.GetY()+20 > 190 {
+			pdf.AddPage()
+		}
+		pdf.SetFont("Arial", "B", 8)
+		pdf.SetFillColor(220, 220, 220)
+		for i, h := range headers {
+			pdf.CellFormat(colWidths[i], 7, tr(h), "1", 0, "C", true, 0, "")
+		}
+		pdf.Ln(-1)
+
+		ended := "andauernd"
+		if e.EndedAt != nil {
+			ended = e.EndedAt.In(loc).Format("2006-01-02 15:04")
+		}
+		decidedBy := deref(e.DecidedByName)
+		if decidedBy == "" {
+			decidedBy = deref(e.DecidedBy)
+		}
+		decidedAt := ""
+		if e.DecidedAt != nil {
+			decidedAt = e.DecidedAt.In(loc).Format("2006-01-02 15:04")
+		}
+
+		pdf.SetFont("Arial", "", 7)
+		pdf.CellFormat(colWidths[0], 6, e.StartedAt.In(loc).Format("2006-01-02 15:04"), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(colWidths[1], 6, tr(ended), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(colWidths[2], 6, formatExcursionDuration(e.DurationSeconds), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(colWidths[3], 6, formatCelsius(e.MinTemperature), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(colWidths[4], 6, formatCelsius(e.MaxTemperature), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(colWidths[5], 6, tr(excursionStatusLabels[e.Status]), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(colWidths[6], 6, tr(truncate(decidedBy, 40)), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(colWidths[7], 6, decidedAt, "1", 1, "C", false, 0, "")
+
+		action := deref(e.DecisionReason)
+		switch {
+		case action != "":
+		case e.Status == repository.ExcursionTransient:
+			action = "Keine erforderlich: innerhalb der Toleranzzeit wieder im Sollbereich."
+		default:
+			action = "Noch nicht dokumentiert."
+		}
+		pdf.SetFont("Arial", "B", 7)
+		pdf.CellFormat(25, 6, tr("Maßnahme:"), "LB", 0, "L", false, 0, "")
+		pdf.SetFont("Arial", "", 7)
+		pdf.MultiCell(0, 6, tr(action), "RB", "L", false)
+		pdf.Ln(2)
+	}
+	pdf.Ln(4)
+}
+
+func renderCheckTable(pdf *fpdf.Fpdf, tr func(string) string, report *TemperatureReport, loc *time.Location) {
+	pdf.SetFont("Arial", "B", 10)
+	pdf.Cell(0, 6, "Manuelle Kontrollen")
+	pdf.Ln(8)
+
+	if len(report.Checks) == 0 {
+		pdf.SetFont("Arial", "I", 9)
+		pdf.Cell(0, 6, tr("Keine manuellen Kontrollen im Zeitraum."))
+		pdf.Ln(10)
+		return
+	}
+
+	colWidths := []float64{35, 25, 30, 70, 117}
+	headers := []string{"Zeitpunkt", "Temperatur (°C)", "Im Sollbereich", "Kontrolliert von", "Bemerkung"}
+
+	header := func() {
+		pdf.SetFont("Arial", "B", 8)
+		pdf.SetFillColor(220, 220, 220)
+		for i, h := range headers {
+			pdf.CellFormat(colWidths[i], 7, tr(h), "1", 0, "C", true, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetFont("Arial", "", 7)
+	}
+	header()
+
+	fill := false
+	for _, c := range report.Checks {
+		if pdf.GetY()+6 > 190 {
+			pdf.AddPage()
+			header()
+		}
+		if fill {
+			pdf.SetFillColor(245, 245, 245)
+		} else {
+			pdf.SetFillColor(255, 255, 255)
+		}
+
+		inRange := "ja"
+		if outOfRange(report.Cabinet, c.TemperatureCelsius) {
+			inRange = "nein"
+		}
+
+		pdf.CellFormat(colWidths[0], 6, c.RecordedAt.In(loc).Format("2006-01-02 15:04"), "1", 0, "C", fill, 0, "")
+		pdf.CellFormat(colWidths[1], 6, formatCelsius(c.TemperatureCelsius), "1", 0, "R", fill, 0, "")
+		pdf.CellFormat(colWidths[2], 6, inRange, "1", 0, "C", fill, 0, "")
+		pdf.CellFormat(colWidths[3], 6, tr(truncate(checkerName(c), 45)), "1", 0, "L", fill, 0, "")
+		pdf.CellFormat(colWidths[4], 6, tr(truncate(deref(c.Notes), 80)), "1", 0, "L", fill, 0, "")
+		pdf.Ln(-1)
+
+		fill = !fill
+	}
+	pdf.Ln(6)
+}
+
+func renderTemperatureSignatures(pdf *fpdf.Fpdf, tr func(string) string, report *TemperatureReport) {
+	var checkers []string
+	for _, c := range report.Checks {
+		checkers = appendUnique(checkers, checkerName(c))
+	}
+	sort.Strings(checkers)
+	if len(checkers) == 0 {
+		checkers = []string{"-"}
+	}
+
+	if pdf.GetY()+45 > 190 {
+		pdf.AddPage()
+	}
+	pdf.SetFont("Arial", "B", 10)
+	pdf.Cell(0, 6, "Unterschriften")
+	pdf.Ln(8)
+	pdf.SetFont("Arial", "", 9)
+	pdf.CellFormat(50, 6, "Kontrolliert von:", "", 0, "L", false, 0, "")
+	pdf.MultiCell(0, 6, tr(strings.Join(checkers, ", ")), "", "L", false)
+	pdf.CellFormat(50, 6, "Protokoll-Hash (SHA-256):", "", 0, "L", false, 0, "")
+	pdf.SetFont("Courier", "", 8)
+	pdf.CellFormat(0, 6, report.SHA256, "", 1, "L", false, 0, "")
+	pdf.SetFont("Arial", "", 9)
+	pdf.CellFormat(50, 6, "Protokoll-Siegel:", "", 0, "L", false, 0, "")
+	pdf.SetFont("Courier", "", 8)
+	pdf.CellFormat(0, 6, report.Seal, "", 1, "L", false, 0, "")
+
+	pdf.Ln(14)
+	pdf.SetFont("Arial", "", 9)
+	pdf.CellFormat(110, 6, "", "B", 0, "L", false, 0, "")
+	pdf.CellFormat(20, 6, "", "", 0, "L", false, 0, "")
+	pdf.CellFormat(110, 6, "", "B", 1, "L", false, 0, "")
+	pdf.CellFormat(110, 6, tr("Ort, Datum, Unterschrift Verantwortliche/r Kühlkette"), "", 0, "L", false, 0, "")
+	pdf.CellFormat(20, 6, "", "", 0, "L", false, 0, "")
+	pdf.CellFormat(110, 6, tr("Ort, Datum, Unterschrift Praxisinhaber/in"), "", 1, "L", false, 0, "")
+}
+
+func cabinetLabel(report *TemperatureReport) string {
+	if report.RoomName == "" {
+		return report.Cabinet.Name
+	}
+	return report.RoomName + " / " + report.Cabinet.Name
+}
+
+func allowedRange(cabinet *repository.StorageCabinet) string {
+	minT, maxT := "-", "-"
+	if cabinet.MinTemperature != nil {
+		minT = formatCelsius(*cabinet.MinTemperature)
+	}
+	if cabinet.MaxTemperature != nil {
+		maxT = formatCelsius(*cabinet.MaxTemperature)
+	}
+	return minT + " bis " + maxT + " °C"
+}
+
+// checkerName names who took a manual reading
+func checkerName(c *repository.TemperatureReading) string {
+	if name := deref(c.RecordedByName); name != "" {
+		return name
+	}
+	if id := deref(c.RecordedBy); id != "" {
+		return id
+	}
+	return "unbekannt"
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, v := range list {
+		if v == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
+
+// formatCelsius formats a temperature with one decimal and a decimal comma
+func formatCelsius(v float64) string {
+	return strings.Replace(fmt.Sprintf("%.1f", v), ".", ",", 1)
+}
+
+// formatExcursionDuration formats a duration in seconds as hours and minutes
+func formatExcursionDuration(seconds int) string {
+	minutes := (seconds + 59) / 60
+	if minutes < 60 {
+		return fmt.Sprintf("%d min", minutes)
+	}
+	return fmt.Sprintf("%d h %02d min", minutes/60, minutes%60)
+}
@@ -0,0 +1,132 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/internal/inventory/service"
+	"github.com/medflow/medflow-backend/pkg/hmacsig"
+	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColdChainService_TemperatureReport(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tenant := suite.SetupInventoryTenant(t, ctx, "cold-chain-report")
+	tenantCtx := suite.TenantContext(tenant)
+
+	log := logger.New("test", "test")
+	locationRepo := repository.NewLocationRepository(suite.DB)
+	auditService := service.NewAuditService(repository.NewAuditTrailRepository(suite.DB), log)
+	svc := service.NewColdChainService(repository.NewSensorRepository(suite.DB), repository.NewExcursionRepository(suite.DB),
+		repository.NewTemperatureRepository(suite.DB), locationRepo, repository.NewAlertRepository(suite.DB), auditService, nil, "test-seal-key", log)
+
+	room := &repository.StorageRoom{Name: "Labor", IsActive: true}
+	require.NoError(t, locationRepo.CreateRoom(tenantCtx, room))
+	minT, maxT := 2.0, 8.0
+	newCabinet := func(name string, monitored bool) *repository.StorageCabinet {
+		c := &repository.StorageCabinet{
+			RoomID: room.ID, Name: name, IsActive: true, TemperatureControlled: true,
+			MinTemperature: &minT, MaxTemperature: &maxT, TemperatureMonitoringEnabled: monitored,
+		}
+		require.NoError(t, locationRepo.CreateCabinet(tenantCtx, c))
+		return c
+	}
+	cabinet := newCabinet("Impfstoffkühlschrank", true)
+	newCabinet("Lagerschrank", false)
+
+	checker := "00000000-0000-0000-0000-0000000000c1"
+	require.NoError(t, repository.NewUserCacheRepository(suite.DB).Set(tenantCtx, &repository.CachedUser{
+		UserID: checker, FirstName: "Erika", LastName: "Muster",
+	}))
+
+	loc, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	day := time.Now().In(loc).AddDate(0, 0, -3)
+	base := time.Date(day.Year(), day.Month(), day.Day(), 8, 0, 0, 0, loc)
+	at := func(minutes int, celsius float64) service.ReadingInput {
+		return service.ReadingInput{RecordedAt: base.Add(time.Duration(minutes) * time.Minute), TemperatureCelsius: celsius}
+	}
+
+	// Logger readings with a confirmed excursion, and the morning check
+	result, err := svc.Ingest(tenantCtx, cabinet.ID, service.ReadingSource{Source: "webhook"},
+		[]service.ReadingInput{at(0, 5), at(20, 12), at(40, 13), at(60, 6)})
+	require.NoError(t, err)
+	require.Len(t, result.Excursions, 1)
+	_, err = svc.DecideExcursion(tenantCtx, result.Excursions[0].ID, repository.ExcursionReleased,
+		"Herstellerauskunft: bis 25 °C für 24 h stabil", checker)
+	require.NoError(t, err)
+	notes := "Thermometer abgelesen"
+	_, err = svc.Ingest(tenantCtx, cabinet.ID, service.ReadingSource{Source: "manual", RecordedBy: &checker, Notes: &notes},
+		[]service.ReadingInput{at(90, 4.5)})
+	require.NoError(t, err)
+
+	from, to := base.AddDate(0, 0, -1), base.AddDate(0, 0, 1)
+	report, pdf, err := svc.TemperatureReport(tenantCtx, cabinet.ID, from, to)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF")))
+	assert.Equal(t, "Labor", report.RoomName)
+
+	// Every day of the period, with the readings on the day they were taken
+	require.Len(t, report.Days, 3)
+	assert.Equal(t, 0, report.Days[0].ReadingCount)
+	assert.Equal(t, 5, report.Days[1].ReadingCount)
+	assert.Equal(t, 4.5, report.Days[1].MinCelsius)
+	assert.Equal(t, 13.0, report.Days[1].MaxCelsius)
+	assert.Equal(t, 2, report.Days[1].ExcursionReadings)
+
+	require.Len(t, report.Excursions, 1)
+	assert.Equal(t, repository.ExcursionReleased, report.Excursions[0].Status)
+	require.NotNil(t, report.Excursions[0].DecidedByName)
+	assert.Equal(t, "Erika Muster", *report.Excursions[0].DecidedByName)
+
+	require.Len(t, report.Checks, 1)
+	require.NotNil(t, report.Checks[0].RecordedByName)
+	assert.Equal(t, "Erika Muster", *report.Checks[0].RecordedByName)
+
+	// The seal is reproducible and covers every reading
+	require.Len(t, report.SHA256, 64)
+	again, _, err := svc.TemperatureReport(tenantCtx, cabinet.ID, from, to)
+	require.NoError(t, err)
+	assert.Equal(t, report.SHA256, again.SHA256)
+	require.Len(t, report.Seal, 64)
+	assert.Equal(t, report.Seal, again.Seal)
+
+	printed := &service.VerifySealInput{SHA256: report.SHA256, Seal: report.Seal}
+	verification, err := svc.VerifyTemperatureReport(tenantCtx, cabinet.ID, from, to, printed)
+	require.NoError(t, err)
+	assert.True(t, verification.Valid)
+
+	// A digest recomputed without the server key does not carry its seal
+	forged := &service.VerifySealInput{SHA256: report.SHA256, Seal: hmacsig.Seal("other-key", report.SHA256)}
+	verification, err = svc.VerifyTemperatureReport(tenantCtx, cabinet.ID, from, to, forged)
+	require.NoError(t, err)
+	assert.True(t, verification.DigestMatches)
+	assert.False(t, verification.SealValid)
+	assert.False(t, verification.Valid)
+
+	_, err = svc.Ingest(tenantCtx, cabinet.ID, service.ReadingSource{Source: "webhook"}, []service.ReadingInput{at(120, 5)})
+	require.NoError(t, err)
+	changed, _, err := svc.TemperatureReport(tenantCtx, cabinet.ID, from, to)
+	require.NoError(t, err)
+	assert.NotEqual(t, report.SHA256, changed.SHA256)
+
+	// The printed report no longer matches the records
+	verification, err = svc.VerifyTemperatureReport(tenantCtx, cabinet.ID, from, to, printed)
+	require.NoError(t, err)
+	assert.False(t, verification.DigestMatches)
+	assert.False(t, verification.Valid)
+	assert.Equal(t, changed.SHA256, verification.SHA256)
+
+	// Bulk export of the monitored cabinets
+	bulk, err := svc.TemperatureReports(tenantCtx, from, to)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(bulk, []byte("%PDF")))
+}
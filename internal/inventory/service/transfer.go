@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+
+	"github.com/medflow/medflow-backend/internal/inventory/events"
+	"github.com/medflow/medflow-backend/internal/inventory/repository"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/logger"
+)
+
+// TransferService moves stock between rooms, cabinets and shelves
+type TransferService struct {
+	batchRepo    *repository.BatchRepository
+	itemRepo     *repository.ItemRepository
+	locationRepo *repository.LocationRepository
+	auditService *AuditService
+	publisher    *events.InventoryEventPublisher
+	logger       *logger.Logger
+}
+
+// NewTransferService creates a new transfer service
+func NewTransferService(
+	batchRepo *repository.BatchRepository,
+	itemRepo *repository.ItemRepository,
+	locationRepo *repository.LocationRepository,
+	auditService *AuditService,
+	publisher *events.InventoryEventPublisher,
+	log *logger.Logger,
+) *TransferService {
+	return &TransferService{
+		batchRepo:    batchRepo,
+		itemRepo:     itemRepo,
+		locationRepo: locationRepo,
+		auditService: auditService,
+		publisher:    publisher,
+		logger:       log,
+	}
+}
+
+// Transfer moves a quantity of a batch to another location.
+// Items that require cooling may only be moved into a temperature-controlled
+// cabinet (or one of its shelves).
+func (s *TransferService) Transfer(ctx context.Context, t *repository.StockTransfer) error {
+	if t.Quantity <= 0 {
+		return errors.Validation(nil).WithDetail("quantity", "validation.positive", map[string]string{"field": "quantity"})
+	}
+
+	batch, err := s.batchRepo.GetByID(ctx, t.BatchID)
+	if err != nil {
+		return err
+	}
+	item, err := s.itemRepo.GetByID(ctx, batch.ItemID)
+	if err != nil {
+		return err
+	}
+	destination, err := s.locationRepo.ResolveLocation(ctx, t.ToLocationID)
+	if err != nil {
+		return err
+	}
+	if err := checkCooling(item, destination); err != nil {
+		return err
+	}
+
+	if err := s.batchRepo.Transfer(ctx, t); err != nil {
+		return err
+	}
+
+	s.auditService.RecordAction(ctx, "batch", t.BatchID, "transfer", map[string]interface{}{
+		"transfer_id":      t.ID,
+		"item_id":          t.ItemID,
+		"batch_number":     batch.BatchNumber,
+		"quantity":         t.Quantity,
+		"from_location_id": t.FromLocationID,
+		"to_location_id":   t.ToLocationID,
+		"to_location":      destination.Name,
+		"target_batch_id":  t.TargetBatchID,
+		"split":            t.Split,
+	})
+
+	s.publisher.PublishStockTransferred(ctx, t)
+
+	return nil
+}
+
+// checkCooling rejects moving a cooled item outside a temperature-controlled cabinet
+func checkCooling(item *repository.InventoryItem, destination *repository.ResolvedLocation) error {
+	if !item.RequiresCooling || (destination.CabinetID != nil && destination.TemperatureControlled) {
+		return nil
+	}
+	return errors.BadRequest("errors.transfer.cooling_required", map[string]string{
+		"item":     item.Name,
+		"location": destination.Name,
+	})
+}
@@ -0,0 +1,119 @@
+package consumers
+
+import (
+	"context"
+
+	"github.com/medflow/medflow-backend/internal/notification/repository"
+	"github.com/medflow/medflow-backend/internal/notification/service"
+	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/medflow/medflow-backend/pkg/messaging"
+	"github.com/medflow/medflow-backend/pkg/tenant"
+)
+
+// AlertEventConsumer routes inventory and staff compliance alerts to
+// notifications
+type AlertEventConsumer struct {
+	consumer *messaging.Consumer
+	service  *service.NotificationService
+	logger   *logger.Logger
+}
+
+// NewAlertEventConsumer creates a new alert event consumer
+func NewAlertEventConsumer(
+	rmq *messaging.RabbitMQ,
+	notificationService *service.NotificationService,
+	log *logger.Logger,
+) (*AlertEventConsumer, error) {
+	consumer, err := messaging.NewConsumer(rmq, "user-service.notification-alerts", log)
+	if err != nil {
+		return nil, err
+	}
+
+	// Subscribe to inventory alerts and staff compliance alerts
+	if err := consumer.Subscribe(messaging.ExchangeInventoryEvents, "inventory.alert.#"); err != nil {
+		return nil, err
+	}
+	if err := consumer.Subscribe(messaging.ExchangeStaffEvents, "staff.compliance.#"); err != nil {
+		return nil, err
+	}
+
+	c := &AlertEventConsumer{
+		consumer: consumer,
+		service:  notificationService,
+		logger:   log,
+	}
+
+	// Register handlers
+	consumer.RegisterHandler(messaging.EventAlertGenerated, c.handleAlertGenerated)
+	consumer.RegisterHandler(messaging.EventAlertAcknowledged, c.handleAlertAcknowledged)
+	consumer.RegisterHandler(messaging.EventComplianceAlertCreated, c.handleComplianceAlertCreated)
+	consumer.RegisterHandler(messaging.EventComplianceAlertDismissed, c.handleComplianceAlertDismissed)
+
+	return c, nil
+}
+
+// Start starts consuming messages
+func (c *AlertEventConsumer) Start(ctx context.Context) error {
+	return c.consumer.Start(ctx)
+}
+
+// hasTenant reports whether the event carried its tenant; events published
+// before events did cannot be routed
+func hasTenant(ctx context.Context, event *messaging.Event, log *logger.Logger) bool {
+	if _, err := tenant.TenantID(ctx); err != nil {
+		log.Warn().
+			Str("event_id", event.ID).
+			Str("event_type", event.Type).
+			Msg("skipping alert event without tenant")
+		return false
+	}
+	return true
+}
+
+func (c *AlertEventConsumer) handleAlertGenerated(ctx context.Context, event *messaging.Event) error {
+	if !hasTenant(ctx, event, c.logger) {
+		return nil
+	}
+	var data messaging.AlertGeneratedEvent
+	if err := event.UnmarshalData(&data); err != nil {
+		return err
+	}
+
+	return c.service.HandleAlert(ctx, service.InventoryAlert(&data))
+}
+
+func (c *AlertEventConsumer) handleAlertAcknowledged(ctx context.Context, event *messaging.Event) error {
+	if !hasTenant(ctx, event, c.logger) {
+		return nil
+	}
+	var data messaging.AlertAcknowledgedEvent
+	if err := event.UnmarshalData(&data); err != nil {
+		return err
+	}
+
+	return c.service.AcknowledgeSource(ctx, repository.SourceInventory, data.AlertID, data.AcknowledgedBy)
+}
+
+func (c *AlertEventConsumer) handleComplianceAlertCreated(ctx context.Context, event *messaging.Event) error {
+	if !hasTenant(ctx, event, c.logger) {
+		return nil
+	}
+	var data messaging.ComplianceAlertCreatedEvent
+	if err := event.UnmarshalData(&data); err != nil {
+		return err
+	}
+
+	return c.service.HandleAlert(ctx, service.ComplianceAlert(&data))
+}
+
+func (c *AlertEventConsumer) handleComplianceAlertDismissed(ctx context.Context, event *messaging.Event) error {
+	if !hasTenant(ctx, event, c.logger) {
+		return nil
+	}
+	var data messaging.ComplianceAlertDismissedEvent
+	if err := event.UnmarshalData(&data); err != nil {
+		return err
+	}
+
+	return c.service.AcknowledgeSource(ctx, repository.SourceStaff, data.AlertID, data.DismissedBy)
+}
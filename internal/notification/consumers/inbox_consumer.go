@@ -0,0 +1,124 @@
+package consumers
+
+import (
+	"context"
+
+	"github.com/medflow/medflow-backend/internal/notification/service"
+	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/medflow/medflow-backend/pkg/messaging"
+)
+
+// InboxEventConsumer feeds inventory alerts, ArbZG warnings and absence and
+// correction request decisions into users' inboxes, independent of the
+// alert routing rules
+type InboxEventConsumer struct {
+	consumer *messaging.Consumer
+	service  *service.NotificationService
+	logger   *logger.Logger
+}
+
+// NewInboxEventConsumer creates a new inbox event consumer
+func NewInboxEventConsumer(
+	rmq *messaging.RabbitMQ,
+	notificationService *service.NotificationService,
+	log *logger.Logger,
+) (*InboxEventConsumer, error) {
+	consumer, err := messaging.NewConsumer(rmq, "user-service.notification-inbox", log)
+	if err != nil {
+		return nil, err
+	}
+
+	bindings := []struct{ exchange, pattern string }{
+		{messaging.ExchangeInventoryEvents, messaging.EventAlertGenerated},
+		{messaging.ExchangeStaffEvents, messaging.EventComplianceAlertCreated},
+		{messaging.ExchangeStaffEvents, messaging.EventAbsenceApproved},
+		{messaging.ExchangeStaffEvents, messaging.EventAbsenceRejected},
+		{messaging.ExchangeStaffEvents, "staff.correction.#"},
+	}
+	for _, b := range bindings {
+		if err := consumer.Subscribe(b.exchange, b.pattern); err != nil {
+			return nil, err
+		}
+	}
+
+	c := &InboxEventConsumer{
+		consumer: consumer,
+		service:  notificationService,
+		logger:   log,
+	}
+
+	// Register handlers
+	consumer.RegisterHandler(messaging.EventAlertGenerated, c.handleAlertGenerated)
+	consumer.RegisterHandler(messaging.EventComplianceAlertCreated, c.handleComplianceAlertCreated)
+	consumer.RegisterHandler(messaging.EventAbsenceApproved, c.handleAbsenceApproved)
+	consumer.RegisterHandler(messaging.EventAbsenceRejected, c.handleAbsenceRejected)
+	consumer.RegisterHandler(messaging.EventCorrectionApproved, c.handleCorrectionDecided)
+	consumer.RegisterHandler(messaging.EventCorrectionRejected, c.handleCorrectionDecided)
+
+	return c, nil
+}
+
+// Start starts consuming messages
+func (c *InboxEventConsumer) Start(ctx context.Context) error {
+	return c.consumer.Start(ctx)
+}
+
+func (c *InboxEventConsumer) handleAlertGenerated(ctx context.Context, event *messaging.Event) error {
+	if !hasTenant(ctx, event, c.logger) {
+		return nil
+	}
+	var data messaging.AlertGeneratedEvent
+	if err := event.UnmarshalData(&data); err != nil {
+		return err
+	}
+
+	return c.service.FeedInventoryAlert(ctx, &data)
+}
+
+func (c *InboxEventConsumer) handleComplianceAlertCreated(ctx context.Context, event *messaging.Event) error {
+	if !hasTenant(ctx, event, c.logger) {
+		return nil
+	}
+	var data messaging.ComplianceAlertCreatedEvent
+	if err := event.UnmarshalData(&data); err != nil {
+		return err
+	}
+
+	return c.service.FeedComplianceAlert(ctx, &data)
+}
+
+func (c *InboxEventConsumer) handleAbsenceApproved(ctx context.Context, event *messaging.Event) error {
+	if !hasTenant(ctx, event, c.logger) {
+		return nil
+	}
+	var data messaging.AbsenceApprovedEvent
+	if err := event.UnmarshalData(&data); err != nil {
+		return err
+	}
+
+	return c.service.FeedAbsenceDecision(ctx, data.UserID, data.AbsenceID, data.StartDate, data.EndDate, true, "")
+}
+
+func (c *InboxEventConsumer) handleAbsenceRejected(ctx context.Context, event *messaging.Event) error {
+	if !hasTenant(ctx, event, c.logger) {
+		return nil
+	}
+	var data messaging.AbsenceRejectedEvent
+	if err := event.UnmarshalData(&data); err != nil {
+		return err
+	}
+
+	return c.service.FeedAbsenceDecision(ctx, data.UserID, data.AbsenceID, data.StartDate, data.EndDate, false, data.Reason)
+}
+
+func (c *InboxEventConsumer) handleCorrectionDecided(ctx context.Context, event *messaging.Event) error {
+	if !hasTenant(ctx, event, c.logger) {
+		return nil
+	}
+	var data messaging.CorrectionDecidedEvent
+	if err := event.UnmarshalData(&data); err != nil {
+		return err
+	}
+
+	return c.service.FeedCorrectionDecision(ctx, &data, event.Type == messaging.EventCorrectionApproved)
+}
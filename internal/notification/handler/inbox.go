@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/medflow/medflow-backend/internal/notification/service"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/httputil"
+)
+
+// viewer returns the user of a request with the permissions the gateway
+// forwarded
+func viewer(r *http.Request) *service.Viewer {
+	var perms []string
+	if header := r.Header.Get("X-User-Permissions"); header != "" {
+		_ = json.Unmarshal([]byte(header), &perms)
+	}
+	return service.NewViewer(r.Header.Get("X-User-ID"), r.Header.Get("X-User-Role"), perms)
+}
+
+// ListInbox lists the current user's inbox
+// GET /notifications/inbox?status=unread&filter[category]=absence_decision
+func (h *NotificationHandler) ListInbox(w http.ResponseWriter, r *http.Request) {
+	q, err := httputil.ParseListQuery(r)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	page, err := h.service.ListInbox(database.ReadOnly(r.Context()), viewer(r), r.URL.Query().Get("status"), q)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to list inbox")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSONWithMeta(w, http.StatusOK, page.Items, httputil.PageMeta(q, page))
+}
+
+// UnreadCount counts the current user's unread inbox items
+// GET /notifications/inbox/unread-count
+func (h *NotificationHandler) UnreadCount(w http.ResponseWriter, r *http.Request) {
+	count, err := h.service.UnreadCount(database.ReadOnly(r.Context()), viewer(r))
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]int{"unread": count})
+}
+
+// MarkRead marks an inbox item read
+// POST /notifications/inbox/{id}/read
+func (h *NotificationHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	h.setRead(w, r, true)
+}
+
+// MarkUnread marks an inbox item unread
+// POST /notifications/inbox/{id}/unread
+func (h *NotificationHandler) MarkUnread(w http.ResponseWriter, r *http.Request) {
+	h.setRead(w, r, false)
+}
+
+func (h *NotificationHandler) setRead(w http.ResponseWriter, r *http.Request, read bool) {
+	item, err := h.service.MarkRead(r.Context(), viewer(r), chi.URLParam(r, "id"), read)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, item)
+}
+
+// Archive archives an inbox item
+// POST /notifications/inbox/{id}/archive
+func (h *NotificationHandler) Archive(w http.ResponseWriter, r *http.Request) {
+	item, err := h.service.Archive(r.Context(), viewer(r), chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, item)
+}
+
+// MarkAllRead marks all of the current user's inbox items read
+// POST /notifications/inbox/read-all
+func (h *NotificationHandler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	n, err := h.service.MarkAllRead(r.Context(), viewer(r))
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to mark inbox read")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, map[string]int{"marked": n})
+}
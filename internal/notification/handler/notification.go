@@ -0,0 +1,308 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/medflow/medflow-backend/internal/notification/service"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/httputil"
+	"github.com/medflow/medflow-backend/pkg/logger"
+)
+
+// NotificationHandler handles alert notification, routing rule, webhook and
+// settings endpoints
+type NotificationHandler struct {
+	service *service.NotificationService
+	logger  *logger.Logger
+}
+
+// NewNotificationHandler creates a new notification handler
+func NewNotificationHandler(svc *service.NotificationService, log *logger.Logger) *NotificationHandler {
+	return &NotificationHandler{
+		service: svc,
+		logger:  log,
+	}
+}
+
+// List lists notifications
+// GET /notifications?filter[acknowledged_at][null]=true
+func (h *NotificationHandler) List(w http.ResponseWriter, r *http.Request) {
+	q, err := httputil.ParseListQuery(r)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	page, err := h.service.List(database.ReadOnly(r.Context()), q)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to list notifications")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSONWithMeta(w, http.StatusOK, page.Items, httputil.PageMeta(q, page))
+}
+
+// Get gets a notification
+// GET /notifications/{id}
+func (h *NotificationHandler) Get(w http.ResponseWriter, r *http.Request) {
+	n, err := h.service.Get(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, n)
+}
+
+// Acknowledge acknowledges a notification, stopping its escalation
+// POST /notifications/{id}/acknowledge
+func (h *NotificationHandler) Acknowledge(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	n, err := h.service.Acknowledge(r.Context(), id, r.Header.Get("X-User-ID"))
+	if err != nil {
+		h.logger.Error().Err(err).Str("id", id).Msg("failed to acknowledge notification")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, n)
+}
+
+// ListDeliveries lists the email and webhook deliveries of a notification
+// GET /notifications/{id}/deliveries
+func (h *NotificationHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	deliveries, err := h.service.ListDeliveries(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, deliveries)
+}
+
+// decodeRule decodes and validates a notification rule request body
+func decodeRule(r *http.Request) (*service.RuleInput, error) {
+	var input service.RuleInput
+	if err := httputil.DecodeJSON(r, &input); err != nil {
+		return nil, err
+	}
+	if err := httputil.Validate(&input); err != nil {
+		return nil, err
+	}
+	return &input, nil
+}
+
+// CreateRule creates a notification rule
+// POST /notifications/rules
+func (h *NotificationHandler) CreateRule(w http.ResponseWriter, r *http.Request) {
+	input, err := decodeRule(r)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	rule, err := h.service.CreateRule(r.Context(), input, r.Header.Get("X-User-ID"))
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to create notification rule")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.Created(w, rule)
+}
+
+// ListRules lists notification rules
+// GET /notifications/rules?filter[source]=inventory
+func (h *NotificationHandler) ListRules(w http.ResponseWriter, r *http.Request) {
+	q, err := httputil.ParseListQuery(r)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	page, err := h.service.ListRules(database.ReadOnly(r.Context()), q)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to list notification rules")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSONWithMeta(w, http.StatusOK, page.Items, httputil.PageMeta(q, page))
+}
+
+// GetRule gets a notification rule
+// GET /notifications/rules/{id}
+func (h *NotificationHandler) GetRule(w http.ResponseWriter, r *http.Request) {
+	rule, err := h.service.GetRule(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, rule)
+}
+
+// UpdateRule replaces a notification rule
+// PUT /notifications/rules/{id}
+func (h *NotificationHandler) UpdateRule(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	input, err := decodeRule(r)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	rule, err := h.service.UpdateRule(r.Context(), id, input)
+	if err != nil {
+		h.logger.Error().Err(err).Str("id", id).Msg("failed to update notification rule")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, rule)
+}
+
+// DeleteRule deletes a notification rule
+// DELETE /notifications/rules/{id}
+func (h *NotificationHandler) DeleteRule(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if err := h.service.DeleteRule(r.Context(), id); err != nil {
+		h.logger.Error().Err(err).Str("id", id).Msg("failed to delete notification rule")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.NoContent(w)
+}
+
+// GetSettings gets the tenant's notification settings
+// GET /notifications/settings
+func (h *NotificationHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	settings, err := h.service.GetSettings(r.Context())
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, settings)
+}
+
+// SaveSettings saves the tenant's notification settings
+// PUT /notifications/settings
+func (h *NotificationHandler) SaveSettings(w http.ResponseWriter, r *http.Request) {
+	var input service.SettingsInput
+	if err := httputil.DecodeJSON(r, &input); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+	if err := httputil.Validate(&input); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	settings, err := h.service.SaveSettings(r.Context(), &input, r.Header.Get("X-User-ID"))
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to save notification settings")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, settings)
+}
+
+// decodeWebhook decodes and validates a webhook request body
+func decodeWebhook(r *http.Request) (*service.WebhookInput, error) {
+	var input service.WebhookInput
+	if err := httputil.DecodeJSON(r, &input); err != nil {
+		return nil, err
+	}
+	if err := httputil.Validate(&input); err != nil {
+		return nil, err
+	}
+	return &input, nil
+}
+
+// CreateWebhook creates a webhook; the response carries its signing secret,
+// which is not returned again
+// POST /notifications/webhooks
+func (h *NotificationHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	input, err := decodeWebhook(r)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	webhook, err := h.service.CreateWebhook(r.Context(), input, r.Header.Get("X-User-ID"))
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to create webhook")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.Created(w, webhook)
+}
+
+// ListWebhooks lists webhooks
+// GET /notifications/webhooks
+func (h *NotificationHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := h.service.ListWebhooks(database.ReadOnly(r.Context()))
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to list webhooks")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, webhooks)
+}
+
+// GetWebhook gets a webhook
+// GET /notifications/webhooks/{id}
+func (h *NotificationHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	webhook, err := h.service.GetWebhook(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, webhook)
+}
+
+// UpdateWebhook updates a webhook
+// PUT /notifications/webhooks/{id}
+func (h *NotificationHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	input, err := decodeWebhook(r)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	webhook, err := h.service.UpdateWebhook(r.Context(), id, input)
+	if err != nil {
+		h.logger.Error().Err(err).Str("id", id).Msg("failed to update webhook")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, webhook)
+}
+
+// DeleteWebhook deletes a webhook
+// DELETE /notifications/webhooks/{id}
+func (h *NotificationHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if err := h.service.DeleteWebhook(r.Context(), id); err != nil {
+		h.logger.Error().Err(err).Str("id", id).Msg("failed to delete webhook")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.NoContent(w)
+}
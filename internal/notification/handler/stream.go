@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/medflow/medflow-backend/internal/notification/repository"
+	"github.com/medflow/medflow-backend/internal/notification/service"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/httputil"
+	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/medflow/medflow-backend/pkg/tenant"
+)
+
+const (
+	// streamBatch is how many inbox items are read per query
+	streamBatch = 100
+	// streamHeartbeat keeps proxies from closing idle streams; the inbox is
+	// also polled on every heartbeat in case a notification was missed
+	streamHeartbeat = 25 * time.Second
+	// streamRetry is the reconnection delay suggested to clients
+	streamRetry = 5 * time.Second
+)
+
+// StreamHandler streams the current user's new inbox items as Server-Sent
+// Events
+type StreamHandler struct {
+	service *service.NotificationService
+	hub     *service.Hub
+	logger  *logger.Logger
+}
+
+// NewStreamHandler creates a new stream handler
+func NewStreamHandler(svc *service.NotificationService, hub *service.Hub, log *logger.Logger) *StreamHandler {
+	return &StreamHandler{
+		service: svc,
+		hub:     hub,
+		logger:  log,
+	}
+}
+
+// Stream sends every item added to the current user's inbox as a
+// "notification" event whose id is the item's seq. A client reconnecting
+// with Last-Event-ID (or ?last_event_id= where it cannot set headers) first
+// receives the items it missed.
+// GET /notifications/stream
+func (h *StreamHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+	v := viewer(r)
+
+	after, ok, err := lastEventID(r)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	// Subscribe before reading the cursor so no item falls in between
+	wakeups, unsubscribe := h.hub.Subscribe(tenantID, v.UserID)
+	defer unsubscribe()
+
+	if !ok {
+		if after, err = h.service.LatestSeq(ctx, v); err != nil {
+			httputil.ErrorLocalized(w, r, err)
+			return
+		}
+	}
+
+	// Streams outlive the server's write timeout
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		h.logger.Debug().Err(err).Msg("cannot clear write deadline of notification stream")
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry.Milliseconds())
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		if after, err = h.send(ctx, w, v, after); err != nil {
+			if ctx.Err() == nil {
+				h.logger.Warn().Err(err).Str("user_id", v.UserID).Msg("notification stream failed")
+			}
+			return
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-h.hub.Done():
+			// Shutting down; the client reconnects to another instance
+			return
+		case <-wakeups:
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// send writes the viewer's items after seq and returns the last seq sent
+func (h *StreamHandler) send(ctx context.Context, w io.Writer, v *service.Viewer, after int64) (int64, error) {
+	for {
+		items, err := h.service.InboxSince(ctx, v, after, streamBatch)
+		if err != nil {
+			return after, err
+		}
+		for _, item := range items {
+			if err := writeEvent(w, item); err != nil {
+				return after, err
+			}
+			after = item.Seq
+		}
+		if len(items) < streamBatch {
+			return after, nil
+		}
+	}
+}
+
+// writeEvent writes an inbox item as a Server-Sent Event
+func writeEvent(w io.Writer, item *repository.InboxItem) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: notification\ndata: %s\n\n", item.Seq, data)
+	return err
+}
+
+// lastEventID returns the seq a reconnecting client last received; ok is
+// false for a new stream
+func lastEventID(r *http.Request) (seq int64, ok bool, err error) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("last_event_id")
+	}
+	if value == "" {
+		return 0, false, nil
+	}
+	seq, err = strconv.ParseInt(value, 10, 64)
+	if err != nil || seq < 0 {
+		return 0, false, errors.Validation(nil).WithDetail("last_event_id", "validation.integer")
+	}
+	return seq, true, nil
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/medflow/medflow-backend/internal/notification/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteEvent(t *testing.T) {
+	var buf bytes.Buffer
+	body := "Line one\nline two"
+	require.NoError(t, writeEvent(&buf, &repository.InboxItem{
+		ID:        "i1",
+		Seq:       42,
+		Category:  "absence_decision",
+		Title:     "Approved",
+		Body:      &body,
+		CreatedAt: time.Date(2026, 3, 6, 9, 0, 0, 0, time.UTC),
+	}))
+
+	frame := buf.String()
+	assert.True(t, bytes.HasPrefix(buf.Bytes(), []byte("id: 42\nevent: notification\ndata: {")))
+	assert.True(t, bytes.HasSuffix(buf.Bytes(), []byte("}\n\n")))
+	// Newlines in the item must not end the data line
+	assert.Equal(t, 4, bytes.Count(buf.Bytes(), []byte("\n")), frame)
+	assert.Contains(t, frame, `"body":"Line one\nline two"`)
+}
+
+func TestLastEventID(t *testing.T) {
+	r := httptest.NewRequest("GET", "/notifications/stream", nil)
+	_, ok, err := lastEventID(r)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	r.Header.Set("Last-Event-ID", "17")
+	seq, ok, err := lastEventID(r)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int64(17), seq)
+
+	r = httptest.NewRequest("GET", "/notifications/stream?last_event_id=5", nil)
+	seq, ok, err = lastEventID(r)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int64(5), seq)
+
+	r.Header.Set("Last-Event-ID", "abc")
+	_, _, err = lastEventID(r)
+	assert.Error(t, err)
+}
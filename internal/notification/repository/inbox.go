@@ -0,0 +1,299 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/tenant"
+)
+
+// Inbox item states, as filtered by GET /notifications/inbox?status=
+const (
+	InboxUnread   = "unread"
+	InboxRead     = "read"
+	InboxArchived = "archived"
+)
+
+// InboxItem is an entry of a user's in-app notification inbox. Seq orders a
+// user's items and is the event id of the notification stream.
+type InboxItem struct {
+	ID             string  `db:"id" json:"id"`
+	Seq            int64   `db:"seq" json:"seq"`
+	UserID         string  `db:"user_id" json:"user_id"`
+	NotificationID *string `db:"notification_id" json:"notification_id,omitempty"`
+	Category       string  `db:"category" json:"category"`
+	Severity       string  `db:"severity" json:"severity"`
+	Title          string  `db:"title" json:"title"`
+	Body           *string `db:"body" json:"body,omitempty"`
+	Link           *string `db:"link" json:"link,omitempty"`
+	// EntityType and EntityID are the deep link target, e.g. item, cabinet,
+	// employee, time_entry, absence or correction_request
+	EntityType *string `db:"entity_type" json:"entity_type,omitempty"`
+	EntityID   *string `db:"entity_id" json:"entity_id,omitempty"`
+	// DedupKey identifies what the item is about; a second item with the
+	// same key is dropped while the first is not archived
+	DedupKey *string `db:"dedup_key" json:"-"`
+	// RequiredPermission hides the item from users who lost the permission
+	// it was delivered for; nil for items about the user
+	RequiredPermission *string    `db:"required_permission" json:"-"`
+	ReadAt             *time.Time `db:"read_at" json:"read_at,omitempty"`
+	ArchivedAt         *time.Time `db:"archived_at" json:"archived_at,omitempty"`
+	CreatedAt          time.Time  `db:"created_at" json:"created_at"`
+}
+
+// InboxRepository handles inbox persistence. Every read and update is
+// scoped to one user and the permissions they currently hold.
+type InboxRepository struct {
+	db *database.DB
+}
+
+// NewInboxRepository creates a new inbox repository
+func NewInboxRepository(db *database.DB) *InboxRepository {
+	return &InboxRepository{db: db}
+}
+
+const inboxSelect = `
+	SELECT id, seq, user_id, notification_id, category, severity, title, body, link,
+	       entity_type, entity_id, dedup_key, required_permission, read_at, archived_at, created_at
+	FROM notification_inbox
+`
+
+// inboxVisible restricts a query to the items of user $1 that the
+// permissions $2 allow
+const inboxVisible = ` WHERE user_id = $1 AND (required_permission IS NULL OR required_permission = ANY($2))`
+
+// Create adds items to users' inboxes. Items whose DedupKey the user's inbox
+// already holds are skipped and keep a zero Seq.
+// TENANT-ISOLATED: Inserts with tenant_id for RLS
+func (r *InboxRepository) Create(ctx context.Context, items []*InboxItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		for _, item := range items {
+			if item.ID == "" {
+				item.ID = uuid.New().String()
+			}
+			err := r.db.QueryRowxContext(ctx, `
+				INSERT INTO notification_inbox (
+					id, tenant_id, user_id, notification_id, category, severity, title, body, link,
+					entity_type, entity_id, dedup_key, required_permission
+				) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+				ON CONFLICT (tenant_id, user_id, dedup_key) WHERE archived_at IS NULL AND dedup_key IS NOT NULL
+				DO NOTHING
+				RETURNING seq, created_at
+			`,
+				item.ID, tenantID, item.UserID, item.NotificationID, item.Category,
+				item.Severity, item.Title, item.Body, item.Link,
+				item.EntityType, item.EntityID, item.DedupKey, item.RequiredPermission,
+			).Scan(&item.Seq, &item.CreatedAt)
+			if err == sql.ErrNoRows {
+				continue
+			}
+			if err != nil {
+				if appErr := database.MapPQError(err); appErr != nil {
+					return appErr
+				}
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Get gets an inbox item of a user
+// TENANT-ISOLATED: Queries via RLS
+func (r *InboxRepository) Get(ctx context.Context, userID string, allowed []string, id string) (*InboxItem, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var item InboxItem
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		return r.db.GetContext(ctx, &item, inboxSelect+inboxVisible+` AND id = $3`, userID, pq.Array(allowed), id)
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFound("inbox_item")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+// inboxListSchema whitelists the filter and sort fields of GET /notifications/inbox
+var inboxListSchema = database.ListSchema{
+	Fields: map[string]database.Field{
+		"category":    {Column: "category", Type: database.FieldText, Filter: true},
+		"severity":    {Column: "severity", Type: database.FieldText, Filter: true},
+		"entity_type": {Column: "entity_type", Type: database.FieldText, Filter: true, Nullable: true},
+		"entity_id":   {Column: "entity_id", Type: database.FieldUUID, Filter: true, Nullable: true},
+		"read_at":     {Column: "read_at", Type: database.FieldTime, Filter: true, Sort: true, Nullable: true},
+		"seq":         {Column: "seq", Type: database.FieldInt, Filter: true, Sort: true},
+		"created_at":  {Column: "created_at", Type: database.FieldTime, Filter: true, Sort: true},
+	},
+	DefaultSort: []string{"-seq"},
+}
+
+// inboxStatus maps a status to its condition; the empty status lists all
+// items that are not archived
+var inboxStatus = map[string]string{
+	"":            ` AND archived_at IS NULL`,
+	InboxUnread:   ` AND archived_at IS NULL AND read_at IS NULL`,
+	InboxRead:     ` AND archived_at IS NULL AND read_at IS NOT NULL`,
+	InboxArchived: ` AND archived_at IS NOT NULL`,
+}
+
+// List lists the inbox items of a user in a status, filtered, sorted and
+// paginated by q
+// TENANT-ISOLATED: Returns only inbox items via RLS
+func (r *InboxRepository) List(ctx context.Context, userID string, allowed []string, status string, q *database.ListQuery) (*database.Page[*InboxItem], error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var page *database.Page[*InboxItem]
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		var err error
+		page, err = database.SelectPage[*InboxItem](ctx, r.db, &inboxListSchema, q,
+			inboxSelect+inboxVisible+inboxStatus[status], userID, pq.Array(allowed))
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// UnreadCount counts the unread items of a user that are not archived
+// TENANT-ISOLATED: Queries via RLS
+func (r *InboxRepository) UnreadCount(ctx context.Context, userID string, allowed []string) (int, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		return r.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM notification_inbox`+inboxVisible+inboxStatus[InboxUnread],
+			userID, pq.Array(allowed))
+	})
+
+	return count, err
+}
+
+// Since returns up to limit items of a user created after the item with seq
+// after, oldest first. Archived items are included so a client replaying
+// after a reconnect sees every item it missed.
+// TENANT-ISOLATED: Queries via RLS
+func (r *InboxRepository) Since(ctx context.Context, userID string, allowed []string, after int64, limit int) ([]*InboxItem, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []*InboxItem
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		return r.db.SelectContext(ctx, &items, inboxSelect+inboxVisible+` AND seq > $3 ORDER BY seq LIMIT $4`,
+			userID, pq.Array(allowed), after, limit)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// LatestSeq returns the seq of a user's newest item, or 0 if the inbox is
+// empty
+// TENANT-ISOLATED: Queries via RLS
+func (r *InboxRepository) LatestSeq(ctx context.Context, userID string) (int64, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var seq int64
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		return r.db.GetContext(ctx, &seq, `SELECT COALESCE(MAX(seq), 0) FROM notification_inbox WHERE user_id = $1`, userID)
+	})
+
+	return seq, err
+}
+
+// SetRead marks an inbox item of a user read or unread
+// TENANT-ISOLATED: Updates via RLS
+func (r *InboxRepository) SetRead(ctx context.Context, userID string, allowed []string, id string, read bool) error {
+	return r.update(ctx, userID, allowed, id, `read_at = CASE WHEN $4::boolean THEN COALESCE(read_at, NOW()) END`, read)
+}
+
+// Archive archives an inbox item of a user, marking it read
+// TENANT-ISOLATED: Updates via RLS
+func (r *InboxRepository) Archive(ctx context.Context, userID string, allowed []string, id string) error {
+	return r.update(ctx, userID, allowed, id, `read_at = COALESCE(read_at, NOW()), archived_at = COALESCE(archived_at, NOW())`)
+}
+
+// update sets the columns of an inbox item of a user; set's parameters
+// start at $4
+func (r *InboxRepository) update(ctx context.Context, userID string, allowed []string, id, set string, args ...interface{}) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		result, err := r.db.ExecContext(ctx, `UPDATE notification_inbox SET `+set+inboxVisible+` AND id = $3`,
+			append([]interface{}{userID, pq.Array(allowed), id}, args...)...)
+		if err != nil {
+			if appErr := database.MapPQError(err); appErr != nil {
+				return appErr
+			}
+			return err
+		}
+		rows, _ := result.RowsAffected()
+		if rows == 0 {
+			return errors.NotFound("inbox_item")
+		}
+		return nil
+	})
+}
+
+// MarkAllRead marks all unread items of a user read and returns how many
+// there were
+// TENANT-ISOLATED: Updates via RLS
+func (r *InboxRepository) MarkAllRead(ctx context.Context, userID string, allowed []string) (int, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var n int64
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		result, err := r.db.ExecContext(ctx, `UPDATE notification_inbox SET read_at = NOW()`+inboxVisible+inboxStatus[InboxUnread],
+			userID, pq.Array(allowed))
+		if err != nil {
+			return err
+		}
+		n, _ = result.RowsAffected()
+		return nil
+	})
+
+	return int(n), err
+}
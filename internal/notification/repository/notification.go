@@ -0,0 +1,546 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/permissions"
+	"github.com/medflow/medflow-backend/pkg/tenant"
+)
+
+// Delivery statuses
+const (
+	DeliveryPending   = "pending"
+	DeliverySent      = "sent"
+	DeliveryFailed    = "failed"
+	DeliveryCancelled = "cancelled" // the notification was acknowledged before it was sent
+)
+
+// Notification is the routing of one alert condition by one rule. It stays
+// open, and escalates, until it is acknowledged; alerts re-raised for the
+// same condition (DedupKey) while it is open update it instead of notifying
+// again.
+type Notification struct {
+	ID               string     `db:"id" json:"id"`
+	RuleID           string     `db:"rule_id" json:"rule_id"`
+	Source           string     `db:"source" json:"source"`
+	SourceID         string     `db:"source_id" json:"source_id"`
+	DedupKey         string     `db:"dedup_key" json:"dedup_key"`
+	AlertType        string     `db:"alert_type" json:"alert_type"`
+	Severity         string     `db:"severity" json:"severity"`
+	Message          string     `db:"message" json:"message"`
+	Link             *string    `db:"link" json:"link,omitempty"`
+	EscalationLevel  int        `db:"escalation_level" json:"escalation_level"`
+	NextEscalationAt *time.Time `db:"next_escalation_at" json:"next_escalation_at,omitempty"`
+	AcknowledgedAt   *time.Time `db:"acknowledged_at" json:"acknowledged_at,omitempty"`
+	AcknowledgedBy   *string    `db:"acknowledged_by" json:"acknowledged_by,omitempty"`
+	CreatedAt        time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+// Delivery is an outgoing email or webhook call of a notification
+type Delivery struct {
+	ID              string     `db:"id" json:"id"`
+	NotificationID  string     `db:"notification_id" json:"notification_id"`
+	EscalationLevel int        `db:"escalation_level" json:"escalation_level"`
+	Channel         string     `db:"channel" json:"channel"`
+	RecipientUserID *string    `db:"recipient_user_id" json:"recipient_user_id,omitempty"`
+	Recipient       string     `db:"recipient" json:"recipient"`
+	Status          string     `db:"status" json:"status"`
+	Attempts        int        `db:"attempts" json:"attempts"`
+	SendAfter       time.Time  `db:"send_after" json:"send_after"`
+	LastError       *string    `db:"last_error" json:"last_error,omitempty"`
+	SentAt          *time.Time `db:"sent_at" json:"sent_at,omitempty"`
+	CreatedAt       time.Time  `db:"created_at" json:"created_at"`
+}
+
+// Recipient is an active user a notification is addressed to
+type Recipient struct {
+	UserID    string `db:"id"`
+	Email     string `db:"email"`
+	FirstName string `db:"first_name"`
+	LastName  string `db:"last_name"`
+}
+
+// NotificationRepository handles notification and delivery persistence
+type NotificationRepository struct {
+	db *database.DB
+}
+
+// NewNotificationRepository creates a new notification repository
+func NewNotificationRepository(db *database.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+const notificationSelect = `
+	SELECT id, rule_id, source, source_id, dedup_key, alert_type, severity, message, link,
+	       escalation_level, next_escalation_at, acknowledged_at, acknowledged_by, created_at, updated_at
+	FROM notifications
+`
+
+const deliverySelect = `
+	SELECT id, notification_id, escalation_level, channel, recipient_user_id, recipient, status,
+	       attempts, send_after, last_error, sent_at, created_at
+	FROM notification_deliveries
+`
+
+// Transaction runs fn in one tenant transaction; repository calls made with
+// the context fn receives join it
+// TENANT-ISOLATED: Runs via RLS
+func (r *NotificationRepository) Transaction(ctx context.Context, fn func(context.Context) error) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		return fn(database.InTx(ctx))
+	})
+}
+
+// Open creates the notification of a rule for an alert condition. If the
+// rule already has an open notification for n.DedupKey, that one is updated
+// to the latest alert instead and opened is false.
+// TENANT-ISOLATED: Upserts with tenant_id for RLS
+func (r *NotificationRepository) Open(ctx context.Context, n *Notification) (opened bool, err error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if n.ID == "" {
+		n.ID = uuid.New().String()
+	}
+
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			INSERT INTO notifications (
+				id, tenant_id, rule_id, source, source_id, dedup_key, alert_type, severity,
+				message, link, next_escalation_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			ON CONFLICT (tenant_id, rule_id, dedup_key) WHERE acknowledged_at IS NULL
+			DO UPDATE SET source_id = EXCLUDED.source_id, message = EXCLUDED.message
+			RETURNING id, escalation_level, next_escalation_at, created_at, updated_at, (xmax = 0)
+		`
+		err := r.db.QueryRowxContext(ctx, query,
+			n.ID, tenantID, n.RuleID, n.Source, n.SourceID, n.DedupKey, n.AlertType, n.Severity,
+			n.Message, n.Link, n.NextEscalationAt,
+		).Scan(&n.ID, &n.EscalationLevel, &n.NextEscalationAt, &n.CreatedAt, &n.UpdatedAt, &opened)
+		if err != nil {
+			if appErr := database.MapPQError(err); appErr != nil {
+				return appErr
+			}
+			return err
+		}
+		return nil
+	})
+
+	return opened, err
+}
+
+// GetByID gets a notification
+// TENANT-ISOLATED: Queries via RLS
+func (r *NotificationRepository) GetByID(ctx context.Context, id string) (*Notification, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var n Notification
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		return r.db.GetContext(ctx, &n, notificationSelect+` WHERE id = $1`, id)
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFound("notification")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &n, nil
+}
+
+// notificationListSchema whitelists the filter and sort fields of GET /notifications
+var notificationListSchema = database.ListSchema{
+	Fields: map[string]database.Field{
+		"rule_id":          {Column: "rule_id", Type: database.FieldUUID, Filter: true},
+		"source":           {Column: "source", Type: database.FieldText, Filter: true},
+		"source_id":        {Column: "source_id", Type: database.FieldText, Filter: true},
+		"alert_type":       {Column: "alert_type", Type: database.FieldText, Filter: true},
+		"severity":         {Column: "severity", Type: database.FieldText, Filter: true},
+		"escalation_level": {Column: "escalation_level", Type: database.FieldInt, Filter: true, Sort: true},
+		"acknowledged_at":  {Column: "acknowledged_at", Type: database.FieldTime, Filter: true, Sort: true, Nullable: true},
+		"created_at":       {Column: "created_at", Type: database.FieldTime, Filter: true, Sort: true},
+	},
+	DefaultSort: []string{"-created_at"},
+}
+
+// List lists notifications filtered, sorted and paginated by q
+// TENANT-ISOLATED: Returns only notifications via RLS
+func (r *NotificationRepository) List(ctx context.Context, q *database.ListQuery) (*database.Page[*Notification], error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var page *database.Page[*Notification]
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		var err error
+		page, err = database.SelectPage[*Notification](ctx, r.db, &notificationListSchema, q, notificationSelect+` WHERE 1=1`)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// Acknowledge acknowledges a notification, which stops its escalation and
+// cancels its deliveries still waiting for the end of quiet hours.
+// Acknowledging twice keeps the first acknowledgement.
+// TENANT-ISOLATED: Updates via RLS
+func (r *NotificationRepository) Acknowledge(ctx context.Context, id, userID string) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		result, err := r.db.ExecContext(ctx, `
+			UPDATE notifications SET
+				acknowledged_at = COALESCE(acknowledged_at, NOW()),
+				acknowledged_by = COALESCE(acknowledged_by, $2),
+				next_escalation_at = NULL
+			WHERE id = $1
+		`, id, nullUUID(userID))
+		if err != nil {
+			return err
+		}
+		rows, _ := result.RowsAffected()
+		if rows == 0 {
+			return errors.NotFound("notification")
+		}
+		return r.cancelPending(ctx, []string{id})
+	})
+}
+
+// AcknowledgeBySource acknowledges the open notifications of an alert, e.g.
+// when the alert was acknowledged on the dashboard, and returns how many
+// there were
+// TENANT-ISOLATED: Updates via RLS
+func (r *NotificationRepository) AcknowledgeBySource(ctx context.Context, source, sourceID, userID string) (int, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var ids []string
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		err := r.db.SelectContext(ctx, &ids, `
+			UPDATE notifications SET
+				acknowledged_at = NOW(), acknowledged_by = $3, next_escalation_at = NULL
+			WHERE source = $1 AND source_id = $2 AND acknowledged_at IS NULL
+			RETURNING id
+		`, source, sourceID, nullUUID(userID))
+		if err != nil {
+			return err
+		}
+		return r.cancelPending(ctx, ids)
+	})
+
+	return len(ids), err
+}
+
+// cancelPending cancels the pending deliveries of notifications
+func (r *NotificationRepository) cancelPending(ctx context.Context, notificationIDs []string) error {
+	if len(notificationIDs) == 0 {
+		return nil
+	}
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE notification_deliveries SET status = 'cancelled'
+		WHERE notification_id = ANY($1) AND status = 'pending'
+	`, pq.Array(notificationIDs))
+	return err
+}
+
+// LockDueEscalations locks up to limit open notifications whose next
+// escalation is due. Notifications locked by a concurrent run are skipped;
+// the locks are held until the transaction in ctx ends.
+// TENANT-ISOLATED: Queries via RLS
+func (r *NotificationRepository) LockDueEscalations(ctx context.Context, now time.Time, limit int) ([]*Notification, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var notifications []*Notification
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		return r.db.SelectContext(ctx, &notifications, notificationSelect+`
+			WHERE next_escalation_at <= $1 AND acknowledged_at IS NULL
+			ORDER BY next_escalation_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		`, now, limit)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+// SetEscalation records that a notification escalated to level; next is
+// when it escalates again, nil after the last step
+// TENANT-ISOLATED: Updates via RLS
+func (r *NotificationRepository) SetEscalation(ctx context.Context, id string, level int, next *time.Time) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		_, err := r.db.ExecContext(ctx, `
+			UPDATE notifications SET escalation_level = $2, next_escalation_at = $3
+			WHERE id = $1
+		`, id, level, next)
+		return err
+	})
+}
+
+// CreateDeliveries creates pending deliveries
+// TENANT-ISOLATED: Inserts with tenant_id for RLS
+func (r *NotificationRepository) CreateDeliveries(ctx context.Context, deliveries []*Delivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		for _, d := range deliveries {
+			if d.ID == "" {
+				d.ID = uuid.New().String()
+			}
+			d.Status = DeliveryPending
+			err := r.db.QueryRowxContext(ctx, `
+				INSERT INTO notification_deliveries (
+					id, tenant_id, notification_id, escalation_level, channel,
+					recipient_user_id, recipient, send_after
+				) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+				RETURNING created_at
+			`,
+				d.ID, tenantID, d.NotificationID, d.EscalationLevel, d.Channel,
+				d.RecipientUserID, d.Recipient, d.SendAfter,
+			).Scan(&d.CreatedAt)
+			if err != nil {
+				if appErr := database.MapPQError(err); appErr != nil {
+					return appErr
+				}
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListDeliveries lists the deliveries of a notification
+// TENANT-ISOLATED: Returns only deliveries via RLS
+func (r *NotificationRepository) ListDeliveries(ctx context.Context, notificationID string) ([]*Delivery, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var deliveries []*Delivery
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		return r.db.SelectContext(ctx, &deliveries, deliverySelect+`
+			WHERE notification_id = $1 ORDER BY created_at, channel
+		`, notificationID)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// LeaseDueDeliveries claims up to limit pending deliveries that are due and
+// counts an attempt for each. A claimed delivery is not due again before the
+// lease ends, so a sender that crashes mid-send retries it afterwards and
+// concurrent senders never claim the same delivery.
+// TENANT-ISOLATED: Updates via RLS
+func (r *NotificationRepository) LeaseDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*Delivery, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var deliveries []*Delivery
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		return r.db.SelectContext(ctx, &deliveries, `
+			UPDATE notification_deliveries SET attempts = attempts + 1, send_after = $2
+			WHERE id IN (
+				SELECT id FROM notification_deliveries
+				WHERE status = 'pending' AND send_after <= $1
+				ORDER BY send_after
+				LIMIT $3
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING id, notification_id, escalation_level, channel, recipient_user_id, recipient,
+			          status, attempts, send_after, last_error, sent_at, created_at
+		`, now, now.Add(lease), limit)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// MarkDeliverySent records a successful delivery
+// TENANT-ISOLATED: Updates via RLS
+func (r *NotificationRepository) MarkDeliverySent(ctx context.Context, id string) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		_, err := r.db.ExecContext(ctx, `
+			UPDATE notification_deliveries SET status = 'sent', sent_at = NOW(), last_error = NULL
+			WHERE id = $1 AND status = 'pending'
+		`, id)
+		return err
+	})
+}
+
+// MarkDeliveryFailed records a failed attempt. The delivery is retried at
+// retryAt, or given up when retryAt is nil.
+// TENANT-ISOLATED: Updates via RLS
+func (r *NotificationRepository) MarkDeliveryFailed(ctx context.Context, id, reason string, retryAt *time.Time) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		var err error
+		if retryAt != nil {
+			_, err = r.db.ExecContext(ctx, `
+				UPDATE notification_deliveries SET last_error = $2, send_after = $3
+				WHERE id = $1 AND status = 'pending'
+			`, id, reason, *retryAt)
+		} else {
+			_, err = r.db.ExecContext(ctx, `
+				UPDATE notification_deliveries SET status = 'failed', last_error = $2
+				WHERE id = $1 AND status = 'pending'
+			`, id, reason)
+		}
+		return err
+	})
+}
+
+// ResolveRecipients returns the active users that have one of roles or are
+// listed in userIDs, each once
+// TENANT-ISOLATED: Queries via RLS
+func (r *NotificationRepository) ResolveRecipients(ctx context.Context, roles, userIDs []string) ([]*Recipient, error) {
+	if len(roles) == 0 && len(userIDs) == 0 {
+		return nil, nil
+	}
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var recipients []*Recipient
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		return r.db.SelectContext(ctx, &recipients, `
+			SELECT u.id, u.email, u.first_name, u.last_name
+			FROM users u
+			WHERE u.deleted_at IS NULL AND u.status = 'active'
+			  AND (
+				u.id = ANY($2::uuid[])
+				OR EXISTS (
+					SELECT 1 FROM user_roles ur
+					JOIN roles r ON r.id = ur.role_id AND r.deleted_at IS NULL
+					WHERE ur.user_id = u.id AND r.name = ANY($1)
+				)
+			  )
+			ORDER BY u.last_name, u.first_name
+		`, pq.Array(roles), pq.Array(userIDs))
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return recipients, nil
+}
+
+// RecipientsWithPermission returns the active users whose role grants
+// permission
+// TENANT-ISOLATED: Queries via RLS
+func (r *NotificationRepository) RecipientsWithPermission(ctx context.Context, permission string) ([]*Recipient, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		Recipient
+		Permissions []byte `db:"permissions"`
+	}
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		return r.db.SelectContext(ctx, &rows, `
+			SELECT u.id, u.email, u.first_name, u.last_name, r.permissions
+			FROM users u
+			JOIN user_roles ur ON ur.user_id = u.id
+			JOIN roles r ON r.id = ur.role_id AND r.deleted_at IS NULL
+			WHERE u.deleted_at IS NULL AND u.status = 'active'
+			ORDER BY u.last_name, u.first_name
+		`)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Role permissions hold wildcards (e.g. "staff.*"), so they are matched
+	// here rather than in SQL
+	seen := make(map[string]bool)
+	var recipients []*Recipient
+	for i := range rows {
+		var perms []string
+		if err := json.Unmarshal(rows[i].Permissions, &perms); err != nil {
+			return nil, fmt.Errorf("failed to parse permissions: %w", err)
+		}
+		if seen[rows[i].UserID] || !permissions.HasPermission(perms, permission) {
+			continue
+		}
+		seen[rows[i].UserID] = true
+		recipients = append(recipients, &rows[i].Recipient)
+	}
+
+	return recipients, nil
+}
+
+// nullUUID maps an empty id to NULL
+func nullUUID(id string) *string {
+	if id == "" {
+		return nil
+	}
+	return &id
+}
@@ -0,0 +1,333 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/tenant"
+)
+
+// Alert sources
+const (
+	SourceInventory = "inventory" // inventory.alert.generated
+	SourceStaff     = "staff"     // staff.compliance.alert.created
+)
+
+// Channels
+const (
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+	ChannelInbox   = "inbox"
+)
+
+// Severities, in ascending order
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// SeverityRank orders severities; unknown severities rank lowest
+func SeverityRank(severity string) int {
+	switch severity {
+	case SeverityCritical:
+		return 2
+	case SeverityWarning:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// EscalationStep is notified when a notification is still unacknowledged
+// AfterMinutes after the previous step (or the initial recipients) was
+// delivered; deliveries deferred by quiet hours count from their end
+type EscalationStep struct {
+	AfterMinutes int      `json:"after_minutes" validate:"min=1,max=10080"`
+	Roles        []string `json:"roles,omitempty"`
+	UserIDs      []string `json:"user_ids,omitempty" validate:"omitempty,dive,uuid"`
+}
+
+// EscalationSteps is the JSONB escalation chain of a rule
+type EscalationSteps []EscalationStep
+
+// Value implements driver.Valuer
+func (s EscalationSteps) Value() (driver.Value, error) {
+	if s == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner
+func (s *EscalationSteps) Scan(src interface{}) error {
+	var b []byte
+	switch v := src.(type) {
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	case nil:
+		*s = EscalationSteps{}
+		return nil
+	default:
+		return fmt.Errorf("escalation steps: unsupported type %T", src)
+	}
+	return json.Unmarshal(b, s)
+}
+
+// Rule routes the alerts of a source to channels and recipients
+type Rule struct {
+	ID               string          `db:"id" json:"id"`
+	Name             string          `db:"name" json:"name"`
+	Source           string          `db:"source" json:"source"`
+	AlertTypes       pq.StringArray  `db:"alert_types" json:"alert_types"`
+	MinSeverity      string          `db:"min_severity" json:"min_severity"`
+	Channels         pq.StringArray  `db:"channels" json:"channels"`
+	RecipientRoles   pq.StringArray  `db:"recipient_roles" json:"recipient_roles"`
+	RecipientUserIDs pq.StringArray  `db:"recipient_user_ids" json:"recipient_user_ids"`
+	Escalation       EscalationSteps `db:"escalation" json:"escalation"`
+	IsActive         bool            `db:"is_active" json:"is_active"`
+	CreatedBy        *string         `db:"created_by" json:"created_by,omitempty"`
+	CreatedAt        time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time       `db:"updated_at" json:"updated_at"`
+}
+
+// Matches reports whether an alert of the given type and severity from the
+// rule's source is routed by the rule
+func (r *Rule) Matches(source, alertType, severity string) bool {
+	if !r.IsActive || r.Source != source || SeverityRank(severity) < SeverityRank(r.MinSeverity) {
+		return false
+	}
+	if len(r.AlertTypes) == 0 {
+		return true
+	}
+	for _, t := range r.AlertTypes {
+		if t == alertType {
+			return true
+		}
+	}
+	return false
+}
+
+// HasChannel reports whether the rule delivers through channel
+func (r *Rule) HasChannel(channel string) bool {
+	for _, c := range r.Channels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// RuleRepository handles notification rule persistence
+type RuleRepository struct {
+	db *database.DB
+}
+
+// NewRuleRepository creates a new rule repository
+func NewRuleRepository(db *database.DB) *RuleRepository {
+	return &RuleRepository{db: db}
+}
+
+const ruleSelect = `
+	SELECT id, name, source, alert_types, min_severity, channels, recipient_roles,
+	       recipient_user_ids, escalation, is_active, created_by, created_at, updated_at
+	FROM notification_rules
+`
+
+// normalize replaces nil arrays, which would be written as NULL
+func (r *Rule) normalize() {
+	if r.AlertTypes == nil {
+		r.AlertTypes = pq.StringArray{}
+	}
+	if r.RecipientRoles == nil {
+		r.RecipientRoles = pq.StringArray{}
+	}
+	if r.RecipientUserIDs == nil {
+		r.RecipientUserIDs = pq.StringArray{}
+	}
+	if r.Escalation == nil {
+		r.Escalation = EscalationSteps{}
+	}
+}
+
+// Create creates a rule
+// TENANT-ISOLATED: Inserts with tenant_id for RLS
+func (r *RuleRepository) Create(ctx context.Context, rule *Rule) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	if rule.ID == "" {
+		rule.ID = uuid.New().String()
+	}
+	rule.normalize()
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			INSERT INTO notification_rules (
+				id, tenant_id, name, source, alert_types, min_severity, channels,
+				recipient_roles, recipient_user_ids, escalation, is_active, created_by
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+			RETURNING created_at, updated_at
+		`
+		err := r.db.QueryRowxContext(ctx, query,
+			rule.ID, tenantID, rule.Name, rule.Source, rule.AlertTypes, rule.MinSeverity, rule.Channels,
+			rule.RecipientRoles, rule.RecipientUserIDs, rule.Escalation, rule.IsActive, rule.CreatedBy,
+		).Scan(&rule.CreatedAt, &rule.UpdatedAt)
+		if err != nil {
+			if appErr := database.MapPQError(err); appErr != nil {
+				return appErr
+			}
+			return err
+		}
+		return nil
+	})
+}
+
+// GetByID gets a rule
+// TENANT-ISOLATED: Queries via RLS
+func (r *RuleRepository) GetByID(ctx context.Context, id string) (*Rule, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var rule Rule
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		return r.db.GetContext(ctx, &rule, ruleSelect+` WHERE id = $1`, id)
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFound("notification_rule")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+// ListActiveBySource lists the active rules of an alert source
+// TENANT-ISOLATED: Returns only rules via RLS
+func (r *RuleRepository) ListActiveBySource(ctx context.Context, source string) ([]*Rule, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []*Rule
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		return r.db.SelectContext(ctx, &rules, ruleSelect+` WHERE source = $1 AND is_active ORDER BY created_at`, source)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// ruleListSchema whitelists the filter and sort fields of GET /notifications/rules
+var ruleListSchema = database.ListSchema{
+	Fields: map[string]database.Field{
+		"name":         {Column: "name", Type: database.FieldText, Filter: true, Sort: true},
+		"source":       {Column: "source", Type: database.FieldText, Filter: true, Sort: true},
+		"min_severity": {Column: "min_severity", Type: database.FieldText, Filter: true},
+		"is_active":    {Column: "is_active", Type: database.FieldBool, Filter: true},
+		"created_at":   {Column: "created_at", Type: database.FieldTime, Filter: true, Sort: true},
+	},
+	DefaultSort: []string{"source", "name"},
+}
+
+// List lists rules filtered, sorted and paginated by q
+// TENANT-ISOLATED: Returns only rules via RLS
+func (r *RuleRepository) List(ctx context.Context, q *database.ListQuery) (*database.Page[*Rule], error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var page *database.Page[*Rule]
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		var err error
+		page, err = database.SelectPage[*Rule](ctx, r.db, &ruleListSchema, q, ruleSelect+` WHERE 1=1`)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// Update replaces a rule
+// TENANT-ISOLATED: Updates via RLS
+func (r *RuleRepository) Update(ctx context.Context, rule *Rule) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	rule.normalize()
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			UPDATE notification_rules SET
+				name = $2, source = $3, alert_types = $4, min_severity = $5, channels = $6,
+				recipient_roles = $7, recipient_user_ids = $8, escalation = $9, is_active = $10
+			WHERE id = $1
+			RETURNING created_by, created_at, updated_at
+		`
+		err := r.db.QueryRowxContext(ctx, query,
+			rule.ID, rule.Name, rule.Source, rule.AlertTypes, rule.MinSeverity, rule.Channels,
+			rule.RecipientRoles, rule.RecipientUserIDs, rule.Escalation, rule.IsActive,
+		).Scan(&rule.CreatedBy, &rule.CreatedAt, &rule.UpdatedAt)
+		if err == sql.ErrNoRows {
+			return errors.NotFound("notification_rule")
+		}
+		if err != nil {
+			if appErr := database.MapPQError(err); appErr != nil {
+				return appErr
+			}
+			return err
+		}
+		return nil
+	})
+}
+
+// Delete deletes a rule with its notifications
+// TENANT-ISOLATED: Deletes via RLS
+func (r *RuleRepository) Delete(ctx context.Context, id string) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		result, err := r.db.ExecContext(ctx, `DELETE FROM notification_rules WHERE id = $1`, id)
+		if err != nil {
+			return err
+		}
+		rows, _ := result.RowsAffected()
+		if rows == 0 {
+			return errors.NotFound("notification_rule")
+		}
+		return nil
+	})
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/tenant"
+)
+
+// Settings are a tenant's notification settings. Quiet hours are "HH:MM"
+// wall-clock times in Timezone and may wrap midnight (20:00-07:00).
+type Settings struct {
+	QuietHoursStart *string `db:"quiet_hours_start" json:"quiet_hours_start"`
+	QuietHoursEnd   *string `db:"quiet_hours_end" json:"quiet_hours_end"`
+	Timezone        string  `db:"timezone" json:"timezone"`
+	// QuietHoursBypassSeverity is the lowest severity delivered during quiet
+	// hours; "never" defers every email and webhook delivery
+	QuietHoursBypassSeverity string     `db:"quiet_hours_bypass_severity" json:"quiet_hours_bypass_severity"`
+	Locale                   string     `db:"locale" json:"locale"`
+	UpdatedBy                *string    `db:"updated_by" json:"updated_by,omitempty"`
+	UpdatedAt                *time.Time `db:"updated_at" json:"updated_at,omitempty"`
+}
+
+// DefaultSettings are the settings of a tenant that has not saved any
+func DefaultSettings() *Settings {
+	return &Settings{
+		Timezone:                 "Europe/Berlin",
+		QuietHoursBypassSeverity: SeverityCritical,
+		Locale:                   "de",
+	}
+}
+
+// SettingsRepository handles notification settings persistence
+type SettingsRepository struct {
+	db *database.DB
+}
+
+// NewSettingsRepository creates a new settings repository
+func NewSettingsRepository(db *database.DB) *SettingsRepository {
+	return &SettingsRepository{db: db}
+}
+
+// Get gets the tenant's settings, or the defaults if none were saved
+// TENANT-ISOLATED: Queries via RLS
+func (r *SettingsRepository) Get(ctx context.Context) (*Settings, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var s Settings
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		return r.db.GetContext(ctx, &s, `
+			SELECT to_char(quiet_hours_start, 'HH24:MI') AS quiet_hours_start,
+			       to_char(quiet_hours_end, 'HH24:MI') AS quiet_hours_end,
+			       timezone, quiet_hours_bypass_severity, locale, updated_by, updated_at
+			FROM notification_settings
+		`)
+	})
+
+	if err == sql.ErrNoRows {
+		return DefaultSettings(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// Save creates or replaces the tenant's settings
+// TENANT-ISOLATED: Upserts with tenant_id for RLS
+func (r *SettingsRepository) Save(ctx context.Context, s *Settings) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			INSERT INTO notification_settings (
+				tenant_id, quiet_hours_start, quiet_hours_end, timezone,
+				quiet_hours_bypass_severity, locale, updated_by
+			) VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (tenant_id) DO UPDATE SET
+				quiet_hours_start = EXCLUDED.quiet_hours_start,
+				quiet_hours_end = EXCLUDED.quiet_hours_end,
+				timezone = EXCLUDED.timezone,
+				quiet_hours_bypass_severity = EXCLUDED.quiet_hours_bypass_severity,
+				locale = EXCLUDED.locale,
+				updated_by = EXCLUDED.updated_by,
+				updated_at = NOW()
+			RETURNING updated_at
+		`
+		err := r.db.QueryRowxContext(ctx, query,
+			tenantID, s.QuietHoursStart, s.QuietHoursEnd, s.Timezone,
+			s.QuietHoursBypassSeverity, s.Locale, s.UpdatedBy,
+		).Scan(&s.UpdatedAt)
+		if err != nil {
+			if appErr := database.MapPQError(err); appErr != nil {
+				return appErr
+			}
+			return err
+		}
+		return nil
+	})
+}
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/tenant"
+)
+
+// Webhook is an HTTP endpoint notifications of the webhook channel are
+// posted to, signed with Secret
+type Webhook struct {
+	ID        string    `db:"id" json:"id"`
+	Name      string    `db:"name" json:"name"`
+	URL       string    `db:"url" json:"url"`
+	Secret    string    `db:"secret" json:"-"`
+	IsActive  bool      `db:"is_active" json:"is_active"`
+	CreatedBy *string   `db:"created_by" json:"created_by,omitempty"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// WebhookRepository handles webhook persistence
+type WebhookRepository struct {
+	db *database.DB
+}
+
+// NewWebhookRepository creates a new webhook repository
+func NewWebhookRepository(db *database.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+const webhookSelect = `
+	SELECT id, name, url, secret, is_active, created_by, created_at, updated_at
+	FROM notification_webhooks
+`
+
+// Create creates a webhook
+// TENANT-ISOLATED: Inserts with tenant_id for RLS
+func (r *WebhookRepository) Create(ctx context.Context, w *Webhook) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	if w.ID == "" {
+		w.ID = uuid.New().String()
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			INSERT INTO notification_webhooks (id, tenant_id, name, url, secret, is_active, created_by)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING created_at, updated_at
+		`
+		err := r.db.QueryRowxContext(ctx, query,
+			w.ID, tenantID, w.Name, w.URL, w.Secret, w.IsActive, w.CreatedBy,
+		).Scan(&w.CreatedAt, &w.UpdatedAt)
+		if err != nil {
+			if appErr := database.MapPQError(err); appErr != nil {
+				return appErr
+			}
+			return err
+		}
+		return nil
+	})
+}
+
+// GetByID gets a webhook
+// TENANT-ISOLATED: Queries via RLS
+func (r *WebhookRepository) GetByID(ctx context.Context, id string) (*Webhook, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var w Webhook
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		return r.db.GetContext(ctx, &w, webhookSelect+` WHERE id = $1`, id)
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFound("notification_webhook")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &w, nil
+}
+
+// List lists the tenant's webhooks
+// TENANT-ISOLATED: Returns only webhooks via RLS
+func (r *WebhookRepository) List(ctx context.Context) ([]*Webhook, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var webhooks []*Webhook
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		return r.db.SelectContext(ctx, &webhooks, webhookSelect+` ORDER BY name`)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// ListActive lists the webhooks the webhook channel delivers to
+// TENANT-ISOLATED: Returns only webhooks via RLS
+func (r *WebhookRepository) ListActive(ctx context.Context) ([]*Webhook, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var webhooks []*Webhook
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		return r.db.SelectContext(ctx, &webhooks, webhookSelect+` WHERE is_active ORDER BY name`)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// Update updates name, URL and state of a webhook; the secret is kept
+// TENANT-ISOLATED: Updates via RLS
+func (r *WebhookRepository) Update(ctx context.Context, w *Webhook) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `
+			UPDATE notification_webhooks SET name = $2, url = $3, is_active = $4
+			WHERE id = $1
+			RETURNING secret, created_by, created_at, updated_at
+		`
+		err := r.db.QueryRowxContext(ctx, query, w.ID, w.Name, w.URL, w.IsActive).
+			Scan(&w.Secret, &w.CreatedBy, &w.CreatedAt, &w.UpdatedAt)
+		if err == sql.ErrNoRows {
+			return errors.NotFound("notification_webhook")
+		}
+		if err != nil {
+			if appErr := database.MapPQError(err); appErr != nil {
+				return appErr
+			}
+			return err
+		}
+		return nil
+	})
+}
+
+// Delete deletes a webhook
+// TENANT-ISOLATED: Deletes via RLS
+func (r *WebhookRepository) Delete(ctx context.Context, id string) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		result, err := r.db.ExecContext(ctx, `DELETE FROM notification_webhooks WHERE id = $1`, id)
+		if err != nil {
+			return err
+		}
+		rows, _ := result.RowsAffected()
+		if rows == 0 {
+			return errors.NotFound("notification_webhook")
+		}
+		return nil
+	})
+}
@@ -0,0 +1,306 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/medflow/medflow-backend/internal/notification/repository"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/i18n"
+)
+
+// RuleInput is the input for creating or updating a notification rule
+type RuleInput struct {
+	Name   string `json:"name" validate:"required,max=255"`
+	Source string `json:"source" validate:"required,oneof=inventory staff"`
+	// AlertTypes limits the rule to these alert types; empty routes all
+	AlertTypes       []string                    `json:"alert_types" validate:"omitempty,dive,required,max=50"`
+	MinSeverity      string                      `json:"min_severity" validate:"omitempty,oneof=info warning critical"`
+	Channels         []string                    `json:"channels" validate:"required,min=1,dive,oneof=email webhook inbox"`
+	RecipientRoles   []string                    `json:"recipient_roles" validate:"omitempty,dive,required,max=50"`
+	RecipientUserIDs []string                    `json:"recipient_user_ids" validate:"omitempty,dive,uuid"`
+	Escalation       []repository.EscalationStep `json:"escalation" validate:"omitempty,max=5,dive"`
+	IsActive         *bool                       `json:"is_active"`
+}
+
+// rule builds a rule from the input; rules are active and route all
+// severities by default
+func (input *RuleInput) rule(id string) (*repository.Rule, error) {
+	r := &repository.Rule{
+		ID:               id,
+		Name:             input.Name,
+		Source:           input.Source,
+		AlertTypes:       pq.StringArray(dedup(input.AlertTypes)),
+		MinSeverity:      input.MinSeverity,
+		Channels:         pq.StringArray(dedup(input.Channels)),
+		RecipientRoles:   pq.StringArray(dedup(input.RecipientRoles)),
+		RecipientUserIDs: pq.StringArray(dedup(input.RecipientUserIDs)),
+		Escalation:       repository.EscalationSteps(input.Escalation),
+		IsActive:         input.IsActive == nil || *input.IsActive,
+	}
+	if r.MinSeverity == "" {
+		r.MinSeverity = repository.SeverityInfo
+	}
+
+	if len(r.RecipientRoles) == 0 && len(r.RecipientUserIDs) == 0 && !r.HasChannel(repository.ChannelWebhook) {
+		return nil, errors.Validation(nil).WithDetail("recipient_roles", "errors.notification_rule.no_recipients")
+	}
+	for i, step := range r.Escalation {
+		if len(step.Roles) == 0 && len(step.UserIDs) == 0 {
+			return nil, errors.Validation(nil).WithDetail("escalation", "errors.notification_rule.empty_escalation_step", map[string]string{
+				"step": strconv.Itoa(i + 1),
+			})
+		}
+	}
+	return r, nil
+}
+
+// CreateRule creates a notification rule
+func (s *NotificationService) CreateRule(ctx context.Context, input *RuleInput, userID string) (*repository.Rule, error) {
+	r, err := input.rule("")
+	if err != nil {
+		return nil, err
+	}
+	if userID != "" {
+		r.CreatedBy = &userID
+	}
+	if err := s.ruleRepo.Create(ctx, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// UpdateRule replaces a notification rule. Open notifications keep their
+// escalation level and continue with the rule's new chain.
+func (s *NotificationService) UpdateRule(ctx context.Context, id string, input *RuleInput) (*repository.Rule, error) {
+	r, err := input.rule(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ruleRepo.Update(ctx, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetRule gets a notification rule
+func (s *NotificationService) GetRule(ctx context.Context, id string) (*repository.Rule, error) {
+	return s.ruleRepo.GetByID(ctx, id)
+}
+
+// ListRules lists notification rules
+func (s *NotificationService) ListRules(ctx context.Context, q *database.ListQuery) (*database.Page[*repository.Rule], error) {
+	return s.ruleRepo.List(ctx, q)
+}
+
+// DeleteRule deletes a notification rule with its notifications
+func (s *NotificationService) DeleteRule(ctx context.Context, id string) error {
+	return s.ruleRepo.Delete(ctx, id)
+}
+
+// SettingsInput is the input for saving a tenant's notification settings
+type SettingsInput struct {
+	QuietHoursStart          *string `json:"quiet_hours_start"`
+	QuietHoursEnd            *string `json:"quiet_hours_end"`
+	Timezone                 string  `json:"timezone" validate:"required,max=64"`
+	QuietHoursBypassSeverity string  `json:"quiet_hours_bypass_severity" validate:"required,oneof=info warning critical never"`
+	Locale                   string  `json:"locale" validate:"required"`
+}
+
+// GetSettings gets the tenant's notification settings
+func (s *NotificationService) GetSettings(ctx context.Context) (*repository.Settings, error) {
+	return s.settingsRepo.Get(ctx)
+}
+
+// SaveSettings saves the tenant's notification settings. Deliveries already
+// deferred by quiet hours keep their time.
+func (s *NotificationService) SaveSettings(ctx context.Context, input *SettingsInput, userID string) (*repository.Settings, error) {
+	if (input.QuietHoursStart == nil) != (input.QuietHoursEnd == nil) {
+		return nil, errors.Validation(nil).WithDetail("quiet_hours_start", "errors.notification_settings.invalid_quiet_hours")
+	}
+	for field, v := range map[string]*string{"quiet_hours_start": input.QuietHoursStart, "quiet_hours_end": input.QuietHoursEnd} {
+		if v == nil {
+			continue
+		}
+		if _, err := time.Parse("15:04", *v); err != nil {
+			return nil, errors.Validation(nil).WithDetail(field, "errors.notification_settings.invalid_quiet_hours")
+		}
+	}
+	if _, err := time.LoadLocation(input.Timezone); err != nil {
+		return nil, errors.Validation(nil).WithDetail("timezone", "errors.notification_settings.invalid_timezone", map[string]string{
+			"timezone": input.Timezone,
+		})
+	}
+	if !i18n.IsSupported(input.Locale) {
+		return nil, errors.Validation(nil).WithDetail("locale", "errors.notification_settings.unsupported_locale", map[string]string{
+			"locale": input.Locale,
+		})
+	}
+
+	settings := &repository.Settings{
+		QuietHoursStart:          input.QuietHoursStart,
+		QuietHoursEnd:            input.QuietHoursEnd,
+		Timezone:                 input.Timezone,
+		QuietHoursBypassSeverity: input.QuietHoursBypassSeverity,
+		Locale:                   input.Locale,
+	}
+	if userID != "" {
+		settings.UpdatedBy = &userID
+	}
+	if err := s.settingsRepo.Save(ctx, settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// QuietHoursEnd reports whether now falls into the tenant's quiet hours and
+// when they end
+func QuietHoursEnd(settings *repository.Settings, now time.Time) (time.Time, bool) {
+	if settings.QuietHoursStart == nil || settings.QuietHoursEnd == nil {
+		return time.Time{}, false
+	}
+	start, err1 := time.Parse("15:04", *settings.QuietHoursStart)
+	end, err2 := time.Parse("15:04", *settings.QuietHoursEnd)
+	loc, err3 := time.LoadLocation(settings.Timezone)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return time.Time{}, false
+	}
+
+	local := now.In(loc)
+	at := func(day time.Time, clock time.Time) time.Time {
+		return time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, loc)
+	}
+	startToday, endToday := at(local, start), at(local, end)
+
+	switch {
+	case startToday.Equal(endToday):
+		// Equal start and end disable quiet hours
+		return time.Time{}, false
+	case startToday.Before(endToday):
+		// Same-day window, e.g. 12:00-14:00
+		if !local.Before(startToday) && local.Before(endToday) {
+			return endToday, true
+		}
+	default:
+		// Window wrapping midnight, e.g. 20:00-07:00
+		if local.Before(endToday) {
+			return endToday, true
+		}
+		if !local.Before(startToday) {
+			return at(local.AddDate(0, 0, 1), end), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// BypassesQuietHours reports whether alerts of severity are delivered
+// during quiet hours
+func BypassesQuietHours(settings *repository.Settings, severity string) bool {
+	if settings.QuietHoursBypassSeverity == "never" {
+		return false
+	}
+	return repository.SeverityRank(severity) >= repository.SeverityRank(settings.QuietHoursBypassSeverity)
+}
+
+// DeliveryStart returns when an escalation level of a notification of
+// severity reaches the recipients of rule: at the end of the quiet hours if
+// they defer its email and webhook deliveries, else now. The escalation
+// clock of the level starts then, so a deferred alert is not escalated
+// before anyone could see it.
+func DeliveryStart(rule *repository.Rule, settings *repository.Settings, severity string, now time.Time) time.Time {
+	if !rule.HasChannel(repository.ChannelEmail) && !rule.HasChannel(repository.ChannelWebhook) {
+		return now
+	}
+	if end, quiet := QuietHoursEnd(settings, now); quiet && !BypassesQuietHours(settings, severity) {
+		return end
+	}
+	return now
+}
+
+// WebhookInput is the input for creating or updating a webhook
+type WebhookInput struct {
+	Name     string `json:"name" validate:"required,max=255"`
+	URL      string `json:"url" validate:"required,max=2048"`
+	IsActive *bool  `json:"is_active"`
+}
+
+// webhook builds a webhook from the input; webhooks are active by default
+func (input *WebhookInput) webhook(id string) (*repository.Webhook, error) {
+	if !validWebhookURL(input.URL) {
+		return nil, errors.Validation(nil).WithDetail("url", "errors.notification_webhook.invalid_url")
+	}
+	return &repository.Webhook{
+		ID:       id,
+		Name:     input.Name,
+		URL:      input.URL,
+		IsActive: input.IsActive == nil || *input.IsActive,
+	}, nil
+}
+
+// CreatedWebhook is a new webhook with its signing secret, which is only
+// ever returned on creation
+type CreatedWebhook struct {
+	*repository.Webhook
+	Secret string `json:"secret"`
+}
+
+// CreateWebhook creates a webhook with a new signing secret
+func (s *NotificationService) CreateWebhook(ctx context.Context, input *WebhookInput, userID string) (*CreatedWebhook, error) {
+	w, err := input.webhook("")
+	if err != nil {
+		return nil, err
+	}
+	if w.Secret, err = newWebhookSecret(); err != nil {
+		return nil, err
+	}
+	if userID != "" {
+		w.CreatedBy = &userID
+	}
+	if err := s.webhookRepo.Create(ctx, w); err != nil {
+		return nil, err
+	}
+	return &CreatedWebhook{Webhook: w, Secret: w.Secret}, nil
+}
+
+// UpdateWebhook updates a webhook, keeping its secret
+func (s *NotificationService) UpdateWebhook(ctx context.Context, id string, input *WebhookInput) (*repository.Webhook, error) {
+	w, err := input.webhook(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.webhookRepo.Update(ctx, w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// GetWebhook gets a webhook
+func (s *NotificationService) GetWebhook(ctx context.Context, id string) (*repository.Webhook, error) {
+	return s.webhookRepo.GetByID(ctx, id)
+}
+
+// ListWebhooks lists webhooks
+func (s *NotificationService) ListWebhooks(ctx context.Context) ([]*repository.Webhook, error) {
+	return s.webhookRepo.List(ctx)
+}
+
+// DeleteWebhook deletes a webhook; its pending deliveries fail
+func (s *NotificationService) DeleteWebhook(ctx context.Context, id string) error {
+	return s.webhookRepo.Delete(ctx, id)
+}
+
+// dedup removes duplicates, keeping the first occurrence
+func dedup(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
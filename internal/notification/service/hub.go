@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/medflow/medflow-backend/pkg/logger"
+)
+
+// InboxChannel is the Postgres channel inserts into the inbox notify with
+// "<tenant_id>:<user_id>" (see migration 000042)
+const InboxChannel = "notification_inbox"
+
+// Hub wakes the notification streams of a user when an item was added to
+// their inbox. Items are inserted by any user service instance, so the hub
+// learns of them through Postgres LISTEN/NOTIFY rather than in process.
+type Hub struct {
+	mu     sync.Mutex
+	subs   map[string]map[chan struct{}]struct{}
+	done   chan struct{}
+	closed sync.Once
+	logger *logger.Logger
+}
+
+// NewHub creates a new hub
+func NewHub(log *logger.Logger) *Hub {
+	return &Hub{
+		subs:   make(map[string]map[chan struct{}]struct{}),
+		done:   make(chan struct{}),
+		logger: log,
+	}
+}
+
+// Subscribe returns a channel that receives a value whenever the user's
+// inbox may have new items, and a function that ends the subscription.
+// Wake-ups coalesce; the stream reads all new items on each.
+func (h *Hub) Subscribe(tenantID, userID string) (<-chan struct{}, func()) {
+	key := tenantID + ":" + userID
+	ch := make(chan struct{}, 1)
+
+	h.mu.Lock()
+	if h.subs[key] == nil {
+		h.subs[key] = make(map[chan struct{}]struct{})
+	}
+	h.subs[key][ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		delete(h.subs[key], ch)
+		if len(h.subs[key]) == 0 {
+			delete(h.subs, key)
+		}
+		h.mu.Unlock()
+	}
+}
+
+// Notify wakes the streams of a user
+func (h *Hub) Notify(tenantID, userID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subs[tenantID+":"+userID] {
+		wake(ch)
+	}
+}
+
+// NotifyAll wakes every stream, e.g. after notifications may have been
+// missed while the listener reconnected
+func (h *Hub) NotifyAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, subs := range h.subs {
+		for ch := range subs {
+			wake(ch)
+		}
+	}
+}
+
+// Close ends all streams, so a graceful shutdown does not wait for them
+func (h *Hub) Close() {
+	h.closed.Do(func() { close(h.done) })
+}
+
+// Done is closed when the hub is closed
+func (h *Hub) Done() <-chan struct{} {
+	return h.done
+}
+
+func wake(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+// Listen relays inbox notifications from Postgres until ctx is done. The
+// listener reconnects by itself; streams also poll on every heartbeat, so a
+// lost connection delays items but does not drop them.
+func (h *Hub) Listen(ctx context.Context, dsn string) error {
+	listener := pq.NewListener(dsn, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			h.logger.Warn().Err(err).Int("event", int(ev)).Msg("notification inbox listener")
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(InboxChannel); err != nil {
+		return err
+	}
+	h.logger.Info().Str("channel", InboxChannel).Msg("listening for inbox notifications")
+
+	ping := time.NewTicker(90 * time.Second)
+	defer ping.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case n := <-listener.Notify:
+			if n == nil {
+				// Sent after the listener reconnected
+				h.NotifyAll()
+				continue
+			}
+			tenantID, userID, ok := strings.Cut(n.Extra, ":")
+			if ok {
+				h.Notify(tenantID, userID)
+			}
+		case <-ping.C:
+			if err := listener.Ping(); err != nil {
+				h.logger.Warn().Err(err).Msg("notification inbox listener ping failed")
+			}
+		}
+	}
+}
@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/medflow/medflow-backend/internal/notification/repository"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/i18n"
+	"github.com/medflow/medflow-backend/pkg/messaging"
+	"github.com/medflow/medflow-backend/pkg/permissions"
+)
+
+// Permissions inbox items can require. An item delivered because of a
+// permission stays hidden from users who no longer hold it.
+const (
+	PermissionInventoryAlerts = "inventory.alerts.manage"
+	PermissionStaffRead       = "staff.read"
+)
+
+var inboxPermissions = []string{PermissionInventoryAlerts, PermissionStaffRead}
+
+// Inbox item categories
+const (
+	CategoryInventoryAlert     = "inventory_alert"
+	CategoryComplianceAlert    = "compliance_alert"
+	CategoryArbZGWarning       = "arbzg_warning"
+	CategoryAbsenceDecision    = "absence_decision"
+	CategoryCorrectionDecision = "correction_decision"
+)
+
+// alertCategories maps an alert source to the category of its inbox items
+var alertCategories = map[string]string{
+	repository.SourceInventory: CategoryInventoryAlert,
+	repository.SourceStaff:     CategoryComplianceAlert,
+}
+
+// Viewer is the user reading their inbox, with the inbox permissions they
+// hold
+type Viewer struct {
+	UserID  string
+	Allowed []string
+}
+
+// NewViewer creates the viewer of a request from the user, role and
+// permissions the gateway forwards
+func NewViewer(userID, role string, perms []string) *Viewer {
+	v := &Viewer{UserID: userID, Allowed: []string{}}
+	for _, p := range inboxPermissions {
+		if role == "admin" || permissions.HasPermission(perms, p) {
+			v.Allowed = append(v.Allowed, p)
+		}
+	}
+	return v
+}
+
+// ListInbox lists the viewer's inbox items in a status (unread, read or
+// archived); without a status all items that are not archived are listed
+func (s *NotificationService) ListInbox(ctx context.Context, v *Viewer, status string, q *database.ListQuery) (*database.Page[*repository.InboxItem], error) {
+	switch status {
+	case "", repository.InboxUnread, repository.InboxRead, repository.InboxArchived:
+	default:
+		return nil, errors.Validation(nil).WithDetail("status", "validation.one_of", map[string]string{
+			"field":  "status",
+			"values": "unread, read, archived",
+		})
+	}
+	return s.inboxRepo.List(ctx, v.UserID, v.Allowed, status, q)
+}
+
+// UnreadCount counts the viewer's unread inbox items
+func (s *NotificationService) UnreadCount(ctx context.Context, v *Viewer) (int, error) {
+	return s.inboxRepo.UnreadCount(ctx, v.UserID, v.Allowed)
+}
+
+// MarkRead marks an inbox item of the viewer read or unread
+func (s *NotificationService) MarkRead(ctx context.Context, v *Viewer, id string, read bool) (*repository.InboxItem, error) {
+	if err := s.inboxRepo.SetRead(ctx, v.UserID, v.Allowed, id, read); err != nil {
+		return nil, err
+	}
+	return s.inboxRepo.Get(ctx, v.UserID, v.Allowed, id)
+}
+
+// Archive archives an inbox item of the viewer
+func (s *NotificationService) Archive(ctx context.Context, v *Viewer, id string) (*repository.InboxItem, error) {
+	if err := s.inboxRepo.Archive(ctx, v.UserID, v.Allowed, id); err != nil {
+		return nil, err
+	}
+	return s.inboxRepo.Get(ctx, v.UserID, v.Allowed, id)
+}
+
+// MarkAllRead marks all unread inbox items of the viewer read and returns
+// how many there were
+func (s *NotificationService) MarkAllRead(ctx context.Context, v *Viewer) (int, error) {
+	return s.inboxRepo.MarkAllRead(ctx, v.UserID, v.Allowed)
+}
+
+// InboxSince returns up to limit of the viewer's inbox items after seq,
+// oldest first, for the notification stream
+func (s *NotificationService) InboxSince(ctx context.Context, v *Viewer, after int64, limit int) ([]*repository.InboxItem, error) {
+	return s.inboxRepo.Since(ctx, v.UserID, v.Allowed, after, limit)
+}
+
+// LatestSeq returns the seq of the viewer's newest inbox item; a stream
+// opened without Last-Event-ID starts after it
+func (s *NotificationService) LatestSeq(ctx context.Context, v *Viewer) (int64, error) {
+	return s.inboxRepo.LatestSeq(ctx, v.UserID)
+}
+
+// FeedInventoryAlert puts an inventory alert into the inbox of every user
+// who may manage inventory alerts
+func (s *NotificationService) FeedInventoryAlert(ctx context.Context, e *messaging.AlertGeneratedEvent) error {
+	alert := InventoryAlert(e)
+	recipients, err := s.notificationRepo.RecipientsWithPermission(ctx, PermissionInventoryAlerts)
+	if err != nil || len(recipients) == 0 {
+		return err
+	}
+	l, err := s.localizer(ctx)
+	if err != nil {
+		return err
+	}
+
+	items := make([]*repository.InboxItem, 0, len(recipients))
+	for _, r := range recipients {
+		item := alertItem(l, r.UserID, alert)
+		item.RequiredPermission = strPtr(PermissionInventoryAlerts)
+		items = append(items, item)
+	}
+	return s.inboxRepo.Create(ctx, items)
+}
+
+// FeedComplianceAlert puts an ArbZG compliance alert into the inbox of the
+// employee it is about, as a warning, and of every user who may read staff
+// data
+func (s *NotificationService) FeedComplianceAlert(ctx context.Context, e *messaging.ComplianceAlertCreatedEvent) error {
+	alert := ComplianceAlert(e)
+	recipients, err := s.notificationRepo.RecipientsWithPermission(ctx, PermissionStaffRead)
+	if err != nil {
+		return err
+	}
+	l, err := s.localizer(ctx)
+	if err != nil {
+		return err
+	}
+
+	var items []*repository.InboxItem
+	if e.UserID != "" {
+		item := alertItem(l, e.UserID, alert)
+		item.Category = CategoryArbZGWarning
+		item.Title = l.T("notifications.inbox.arbzg_warning")
+		item.DedupKey = strPtr(CategoryArbZGWarning + ":" + alert.DedupKey)
+		items = append(items, item)
+	}
+	for _, r := range recipients {
+		if r.UserID == e.UserID {
+			continue
+		}
+		item := alertItem(l, r.UserID, alert)
+		item.RequiredPermission = strPtr(PermissionStaffRead)
+		items = append(items, item)
+	}
+	return s.inboxRepo.Create(ctx, items)
+}
+
+// FeedAbsenceDecision tells an employee that their absence was approved or
+// rejected
+func (s *NotificationService) FeedAbsenceDecision(ctx context.Context, userID, absenceID string, start, end time.Time, approved bool, reason string) error {
+	if userID == "" {
+		return nil
+	}
+	l, err := s.localizer(ctx)
+	if err != nil {
+		return err
+	}
+
+	key := "notifications.inbox.absence_rejected"
+	severity := repository.SeverityWarning
+	if approved {
+		key = "notifications.inbox.absence_approved"
+		severity = repository.SeverityInfo
+	}
+	link := "/staff/absences/" + absenceID
+	return s.inboxRepo.Create(ctx, []*repository.InboxItem{{
+		UserID:   userID,
+		Category: CategoryAbsenceDecision,
+		Severity: severity,
+		Title: l.T(key, map[string]string{
+			"start": l.FormatDate(start),
+			"end":   l.FormatDate(end),
+		}),
+		Body:       optional(reason),
+		Link:       &link,
+		EntityType: strPtr("absence"),
+		EntityID:   &absenceID,
+		DedupKey:   strPtr(CategoryAbsenceDecision + ":" + absenceID),
+	}})
+}
+
+// FeedCorrectionDecision tells an employee that their time correction
+// request was approved or rejected
+func (s *NotificationService) FeedCorrectionDecision(ctx context.Context, e *messaging.CorrectionDecidedEvent, approved bool) error {
+	if e.UserID == "" {
+		return nil
+	}
+	l, err := s.localizer(ctx)
+	if err != nil {
+		return err
+	}
+
+	key := "notifications.inbox.correction_rejected"
+	severity := repository.SeverityWarning
+	if approved {
+		key = "notifications.inbox.correction_approved"
+		severity = repository.SeverityInfo
+	}
+	item := &repository.InboxItem{
+		UserID:     e.UserID,
+		Category:   CategoryCorrectionDecision,
+		Severity:   severity,
+		Title:      l.T(key, map[string]string{"date": l.FormatDate(e.RequestedDate)}),
+		Body:       optional(e.Reason),
+		EntityType: strPtr("correction_request"),
+		EntityID:   strPtr(e.RequestID),
+		DedupKey:   strPtr(CategoryCorrectionDecision + ":" + e.RequestID),
+	}
+	// Approved corrections changed the time entry, so link to it
+	if e.TimeEntryID != "" {
+		item.Link = strPtr("/staff/time-entries/" + e.TimeEntryID)
+		item.EntityType, item.EntityID = strPtr("time_entry"), strPtr(e.TimeEntryID)
+	} else {
+		item.Link = strPtr("/staff/corrections/" + e.RequestID)
+	}
+	return s.inboxRepo.Create(ctx, []*repository.InboxItem{item})
+}
+
+// localizer returns the localizer of the tenant's notification locale
+func (s *NotificationService) localizer(ctx context.Context) (*i18n.Localizer, error) {
+	settings, err := s.settingsRepo.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return i18n.NewLocalizer(settings.Locale), nil
+}
+
+// alertItem is the inbox item of an alert for a user
+func alertItem(l *i18n.Localizer, userID string, alert *Alert) *repository.InboxItem {
+	category := alertCategories[alert.Source]
+	item := &repository.InboxItem{
+		UserID:   userID,
+		Category: category,
+		Severity: alert.Severity,
+		Title:    alertTitle(l, alert.Severity, alert.Source, 0),
+		Body:     optional(alert.Message),
+		Link:     optional(alert.Link),
+		DedupKey: strPtr(category + ":" + alert.DedupKey),
+	}
+	item.EntityType, item.EntityID = linkEntity(alert.Link)
+	return item
+}
+
+// linkEntities maps the web app paths alerts link to to the entity types
+// of their inbox items
+var linkEntities = []struct {
+	prefix, entityType string
+}{
+	{"/inventory/cabinets/", "cabinet"},
+	{"/inventory/items/", "item"},
+	{"/staff/time-entries/", "time_entry"},
+	{"/staff/employees/", "employee"},
+}
+
+// linkEntity returns the entity a link points to, if it is known
+func linkEntity(link string) (entityType, entityID *string) {
+	for _, e := range linkEntities {
+		if !strings.HasPrefix(link, e.prefix) {
+			continue
+		}
+		id, _, _ := strings.Cut(strings.TrimPrefix(link, e.prefix), "?")
+		if id == "" {
+			return nil, nil
+		}
+		return strPtr(e.entityType), &id
+	}
+	return nil, nil
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+// optional maps an empty string to nil
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/medflow/medflow-backend/pkg/i18n"
+	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/medflow/medflow-backend/pkg/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewViewer(t *testing.T) {
+	assert.Equal(t, []string{PermissionInventoryAlerts, PermissionStaffRead}, NewViewer("u1", "admin", nil).Allowed)
+	assert.Equal(t, []string{PermissionInventoryAlerts, PermissionStaffRead}, NewViewer("u1", "manager", []string{"staff.*", "inventory.*"}).Allowed)
+	assert.Equal(t, []string{}, NewViewer("u1", "staff", []string{"inventory.read", "inventory.adjust"}).Allowed)
+	assert.Equal(t, []string{PermissionStaffRead}, NewViewer("u1", "", []string{"staff.read"}).Allowed)
+}
+
+func TestLinkEntity(t *testing.T) {
+	typ, id := linkEntity("/inventory/items/item-1?batch=b-1")
+	require.NotNil(t, typ)
+	assert.Equal(t, "item", *typ)
+	assert.Equal(t, "item-1", *id)
+
+	typ, id = linkEntity("/staff/time-entries/te-1")
+	require.NotNil(t, typ)
+	assert.Equal(t, "time_entry", *typ)
+	assert.Equal(t, "te-1", *id)
+
+	typ, id = linkEntity("/inventory/cabinets/")
+	assert.Nil(t, typ)
+	assert.Nil(t, id)
+
+	typ, _ = linkEntity("")
+	assert.Nil(t, typ)
+}
+
+func TestAlertItem(t *testing.T) {
+	alert := ComplianceAlert(&messaging.ComplianceAlertCreatedEvent{
+		AlertID: "a1", EmployeeID: "emp-1", AlertType: "max_daily_hours", Severity: "critical", Message: "10h exceeded",
+	})
+	item := alertItem(i18n.NewLocalizer("de"), "u1", alert)
+
+	assert.Equal(t, "u1", item.UserID)
+	assert.Equal(t, CategoryComplianceAlert, item.Category)
+	assert.Equal(t, "Kritisch: Meldung Arbeitszeit", item.Title)
+	assert.Equal(t, "10h exceeded", *item.Body)
+	assert.Equal(t, "employee", *item.EntityType)
+	assert.Equal(t, "emp-1", *item.EntityID)
+	// Shared with the inbox items of alert rules, so users get the alert once
+	assert.Equal(t, "compliance_alert:max_daily_hours:emp-1:", *item.DedupKey)
+	assert.Nil(t, item.RequiredPermission)
+}
+
+func TestHub(t *testing.T) {
+	hub := NewHub(logger.New("test", "development"))
+	received := func(ch <-chan struct{}) bool {
+		select {
+		case <-ch:
+			return true
+		case <-time.After(10 * time.Millisecond):
+			return false
+		}
+	}
+
+	a, unsubscribeA := hub.Subscribe("t1", "u1")
+	b, unsubscribeB := hub.Subscribe("t1", "u2")
+	defer unsubscribeB()
+
+	hub.Notify("t1", "u1")
+	hub.Notify("t1", "u1") // coalesces with the first
+	assert.True(t, received(a))
+	assert.False(t, received(a))
+	assert.False(t, received(b), "other user")
+
+	hub.Notify("t2", "u2")
+	assert.False(t, received(b), "other tenant")
+
+	hub.NotifyAll()
+	assert.True(t, received(a))
+	assert.True(t, received(b))
+
+	unsubscribeA()
+	hub.Notify("t1", "u1")
+	assert.False(t, received(a), "unsubscribed")
+
+	hub.Close()
+	hub.Close()
+	assert.True(t, received(hub.Done()))
+}
@@ -0,0 +1,571 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/medflow/medflow-backend/internal/notification/repository"
+	"github.com/medflow/medflow-backend/pkg/config"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/i18n"
+	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/medflow/medflow-backend/pkg/mail"
+	"github.com/medflow/medflow-backend/pkg/messaging"
+	"github.com/medflow/medflow-backend/pkg/tenant"
+)
+
+const (
+	escalationBatch     = 100
+	deliveryBatch       = 100
+	deliveryLease       = 5 * time.Minute
+	maxDeliveryAttempts = 5
+)
+
+// Alert is an alert of another service to route
+type Alert struct {
+	Source   string
+	SourceID string // the alert's id in its service
+	// DedupKey identifies the alert condition; alerts re-raised for it while
+	// a notification is open do not notify again
+	DedupKey  string
+	AlertType string
+	Severity  string
+	Message   string
+	Link      string // web app path of the affected item, employee or time entry
+}
+
+// InventoryAlert converts an inventory.alert.generated event
+func InventoryAlert(e *messaging.AlertGeneratedEvent) *Alert {
+	var link string
+	switch {
+	case strings.HasPrefix(e.AlertType, "temperature_"):
+		// Temperature alerts reference the cabinet as their item
+		link = "/inventory/cabinets/" + e.ItemID
+	case e.ItemID != "" && e.BatchID != "":
+		link = "/inventory/items/" + e.ItemID + "?batch=" + e.BatchID
+	case e.ItemID != "":
+		link = "/inventory/items/" + e.ItemID
+	}
+	return &Alert{
+		Source:    repository.SourceInventory,
+		SourceID:  e.AlertID,
+		DedupKey:  e.AlertType + ":" + e.ItemID + ":" + e.BatchID,
+		AlertType: e.AlertType,
+		Severity:  e.Severity,
+		Message:   e.Message,
+		Link:      link,
+	}
+}
+
+// ComplianceAlert converts a staff.compliance.alert.created event
+func ComplianceAlert(e *messaging.ComplianceAlertCreatedEvent) *Alert {
+	link := "/staff/employees/" + e.EmployeeID
+	if e.TimeEntryID != "" {
+		link = "/staff/time-entries/" + e.TimeEntryID
+	}
+	return &Alert{
+		Source:    repository.SourceStaff,
+		SourceID:  e.AlertID,
+		DedupKey:  e.AlertType + ":" + e.EmployeeID + ":" + e.TimeEntryID,
+		AlertType: e.AlertType,
+		Severity:  e.Severity,
+		Message:   e.Message,
+		Link:      link,
+	}
+}
+
+// NotificationService routes alerts to email, webhooks and inboxes and
+// escalates them until they are acknowledged
+type NotificationService struct {
+	ruleRepo         *repository.RuleRepository
+	settingsRepo     *repository.SettingsRepository
+	webhookRepo      *repository.WebhookRepository
+	notificationRepo *repository.NotificationRepository
+	inboxRepo        *repository.InboxRepository
+	mail             mail.Transport
+	webhooks         *WebhookSender
+	mailFrom         string
+	appURL           string
+	now              func() time.Time
+	logger           *logger.Logger
+}
+
+// NewNotificationService creates a new notification service
+func NewNotificationService(
+	ruleRepo *repository.RuleRepository,
+	settingsRepo *repository.SettingsRepository,
+	webhookRepo *repository.WebhookRepository,
+	notificationRepo *repository.NotificationRepository,
+	inboxRepo *repository.InboxRepository,
+	transport mail.Transport,
+	mailCfg *config.MailConfig,
+	log *logger.Logger,
+) *NotificationService {
+	return &NotificationService{
+		ruleRepo:         ruleRepo,
+		settingsRepo:     settingsRepo,
+		webhookRepo:      webhookRepo,
+		notificationRepo: notificationRepo,
+		inboxRepo:        inboxRepo,
+		mail:             transport,
+		webhooks:         NewWebhookSender(),
+		mailFrom:         mailCfg.From,
+		appURL:           strings.TrimRight(mailCfg.AppURL, "/"),
+		now:              time.Now,
+		logger:           log,
+	}
+}
+
+// HandleAlert opens a notification for every active rule matching the
+// alert and notifies the rule's recipients. Alerts of a condition that
+// already has an open notification are only recorded on it.
+func (s *NotificationService) HandleAlert(ctx context.Context, alert *Alert) error {
+	rules, err := s.ruleRepo.ListActiveBySource(ctx, alert.Source)
+	if err != nil {
+		return err
+	}
+	settings, err := s.settingsRepo.Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := s.now()
+	opened := 0
+	for _, rule := range rules {
+		if !rule.Matches(alert.Source, alert.AlertType, alert.Severity) {
+			continue
+		}
+
+		err := s.notificationRepo.Transaction(ctx, func(ctx context.Context) error {
+			n := &repository.Notification{
+				RuleID:    rule.ID,
+				Source:    alert.Source,
+				SourceID:  alert.SourceID,
+				DedupKey:  alert.DedupKey,
+				AlertType: alert.AlertType,
+				Severity:  alert.Severity,
+				Message:   alert.Message,
+			}
+			if alert.Link != "" {
+				n.Link = &alert.Link
+			}
+			sendAfter := DeliveryStart(rule, settings, alert.Severity, now)
+			if len(rule.Escalation) > 0 {
+				next := sendAfter.Add(minutes(rule.Escalation[0].AfterMinutes))
+				n.NextEscalationAt = &next
+			}
+
+			ok, err := s.notificationRepo.Open(ctx, n)
+			if err != nil || !ok {
+				return err
+			}
+			opened++
+			return s.notify(ctx, rule, n, settings, 0, rule.RecipientRoles, rule.RecipientUserIDs, sendAfter)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if opened > 0 {
+		s.logger.Info().
+			Str("source", alert.Source).
+			Str("source_id", alert.SourceID).
+			Int("notifications", opened).
+			Msg("alert notifications opened")
+
+		// Send right away instead of waiting for the next delivery run
+		if err := s.DeliverDue(ctx); err != nil {
+			s.logger.Warn().Err(err).Msg("failed to deliver notifications")
+		}
+	}
+
+	return nil
+}
+
+// notify creates the deliveries and inbox items of one escalation level;
+// the deliveries are sent from sendAfter on
+func (s *NotificationService) notify(
+	ctx context.Context,
+	rule *repository.Rule,
+	n *repository.Notification,
+	settings *repository.Settings,
+	level int,
+	roles, userIDs []string,
+	sendAfter time.Time,
+) error {
+	recipients, err := s.notificationRepo.ResolveRecipients(ctx, roles, userIDs)
+	if err != nil {
+		return err
+	}
+
+	var deliveries []*repository.Delivery
+	if rule.HasChannel(repository.ChannelEmail) {
+		for _, r := range recipients {
+			deliveries = append(deliveries, &repository.Delivery{
+				NotificationID:  n.ID,
+				EscalationLevel: level,
+				Channel:         repository.ChannelEmail,
+				RecipientUserID: &r.UserID,
+				Recipient:       r.Email,
+				SendAfter:       sendAfter,
+			})
+		}
+	}
+	if rule.HasChannel(repository.ChannelWebhook) {
+		webhooks, err := s.webhookRepo.ListActive(ctx)
+		if err != nil {
+			return err
+		}
+		for _, w := range webhooks {
+			deliveries = append(deliveries, &repository.Delivery{
+				NotificationID:  n.ID,
+				EscalationLevel: level,
+				Channel:         repository.ChannelWebhook,
+				Recipient:       w.ID,
+				SendAfter:       sendAfter,
+			})
+		}
+	}
+	if err := s.notificationRepo.CreateDeliveries(ctx, deliveries); err != nil {
+		return err
+	}
+
+	// The inbox is not interrupting, so quiet hours do not apply to it. The
+	// first level shares its dedup key with the inbox feed, which puts the
+	// same alert into the inboxes of users with the matching permission.
+	var items []*repository.InboxItem
+	if rule.HasChannel(repository.ChannelInbox) {
+		title := s.title(i18n.NewLocalizer(settings.Locale), n, level)
+		category := alertCategories[n.Source]
+		dedupKey := category + ":" + n.DedupKey
+		if level > 0 {
+			dedupKey += ":" + strconv.Itoa(level)
+		}
+		var link string
+		if n.Link != nil {
+			link = *n.Link
+		}
+		entityType, entityID := linkEntity(link)
+		for _, r := range recipients {
+			items = append(items, &repository.InboxItem{
+				UserID:         r.UserID,
+				NotificationID: &n.ID,
+				Category:       category,
+				Severity:       n.Severity,
+				Title:          title,
+				Body:           &n.Message,
+				Link:           n.Link,
+				EntityType:     entityType,
+				EntityID:       entityID,
+				DedupKey:       &dedupKey,
+			})
+		}
+	}
+	if err := s.inboxRepo.Create(ctx, items); err != nil {
+		return err
+	}
+
+	if len(deliveries) == 0 && len(items) == 0 {
+		s.logger.Warn().
+			Str("rule_id", rule.ID).
+			Str("notification_id", n.ID).
+			Int("level", level).
+			Msg("notification has no recipients")
+	}
+	return nil
+}
+
+// Acknowledge acknowledges a notification, stopping its escalation
+func (s *NotificationService) Acknowledge(ctx context.Context, id, userID string) (*repository.Notification, error) {
+	if err := s.notificationRepo.Acknowledge(ctx, id, userID); err != nil {
+		return nil, err
+	}
+	return s.notificationRepo.GetByID(ctx, id)
+}
+
+// AcknowledgeSource acknowledges the notifications of an alert that was
+// acknowledged or dismissed in its service
+func (s *NotificationService) AcknowledgeSource(ctx context.Context, source, sourceID, userID string) error {
+	n, err := s.notificationRepo.AcknowledgeBySource(ctx, source, sourceID, userID)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		s.logger.Info().
+			Str("source", source).
+			Str("source_id", sourceID).
+			Int("notifications", n).
+			Msg("alert notifications acknowledged")
+	}
+	return nil
+}
+
+// Get gets a notification
+func (s *NotificationService) Get(ctx context.Context, id string) (*repository.Notification, error) {
+	return s.notificationRepo.GetByID(ctx, id)
+}
+
+// List lists notifications
+func (s *NotificationService) List(ctx context.Context, q *database.ListQuery) (*database.Page[*repository.Notification], error) {
+	return s.notificationRepo.List(ctx, q)
+}
+
+// ListDeliveries lists the deliveries of a notification
+func (s *NotificationService) ListDeliveries(ctx context.Context, id string) ([]*repository.Delivery, error) {
+	if _, err := s.notificationRepo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.notificationRepo.ListDeliveries(ctx, id)
+}
+
+// Dispatch escalates due notifications and sends due deliveries. It runs as
+// a scheduled job every minute.
+func (s *NotificationService) Dispatch(ctx context.Context) error {
+	if err := s.Escalate(ctx); err != nil {
+		return err
+	}
+	return s.DeliverDue(ctx)
+}
+
+// Escalate notifies the next escalation step of every notification that
+// stayed unacknowledged for the step's delay
+func (s *NotificationService) Escalate(ctx context.Context) error {
+	settings, err := s.settingsRepo.Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := s.now()
+	escalated := 0
+	err = s.notificationRepo.Transaction(ctx, func(ctx context.Context) error {
+		due, err := s.notificationRepo.LockDueEscalations(ctx, now, escalationBatch)
+		if err != nil {
+			return err
+		}
+
+		rules := make(map[string]*repository.Rule)
+		for _, n := range due {
+			rule, ok := rules[n.RuleID]
+			if !ok {
+				if rule, err = s.ruleRepo.GetByID(ctx, n.RuleID); err != nil {
+					return err
+				}
+				rules[n.RuleID] = rule
+			}
+
+			level := n.EscalationLevel + 1
+			if !rule.IsActive || level > len(rule.Escalation) {
+				// The rule was disabled or its chain shortened meanwhile
+				if err := s.notificationRepo.SetEscalation(ctx, n.ID, n.EscalationLevel, nil); err != nil {
+					return err
+				}
+				continue
+			}
+
+			step := rule.Escalation[level-1]
+			sendAfter := DeliveryStart(rule, settings, n.Severity, now)
+			if err := s.notify(ctx, rule, n, settings, level, step.Roles, step.UserIDs, sendAfter); err != nil {
+				return err
+			}
+
+			var next *time.Time
+			if level < len(rule.Escalation) {
+				t := sendAfter.Add(minutes(rule.Escalation[level].AfterMinutes))
+				next = &t
+			}
+			if err := s.notificationRepo.SetEscalation(ctx, n.ID, level, next); err != nil {
+				return err
+			}
+			escalated++
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if escalated > 0 {
+		s.logger.Info().Int("notifications", escalated).Msg("notifications escalated")
+	}
+	return nil
+}
+
+// DeliverDue sends the email and webhook deliveries that are due. Failed
+// deliveries are retried with growing delays.
+func (s *NotificationService) DeliverDue(ctx context.Context) error {
+	now := s.now()
+	deliveries, err := s.notificationRepo.LeaseDueDeliveries(ctx, now, deliveryLease, deliveryBatch)
+	if err != nil || len(deliveries) == 0 {
+		return err
+	}
+	settings, err := s.settingsRepo.Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	notifications := make(map[string]*repository.Notification)
+	for _, d := range deliveries {
+		n, ok := notifications[d.NotificationID]
+		if !ok {
+			if n, err = s.notificationRepo.GetByID(ctx, d.NotificationID); err != nil {
+				return err
+			}
+			notifications[d.NotificationID] = n
+		}
+
+		retry, err := s.send(ctx, d, n, settings)
+		if err == nil {
+			if err := s.notificationRepo.MarkDeliverySent(ctx, d.ID); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var retryAt *time.Time
+		if retry && d.Attempts < maxDeliveryAttempts {
+			t := now.Add(retryBackoff(d.Attempts))
+			retryAt = &t
+		}
+		s.logger.Warn().Err(err).
+			Str("delivery_id", d.ID).
+			Str("channel", d.Channel).
+			Int("attempts", d.Attempts).
+			Bool("retry", retryAt != nil).
+			Msg("notification delivery failed")
+		if err := s.notificationRepo.MarkDeliveryFailed(ctx, d.ID, err.Error(), retryAt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// send sends one delivery; retry is false for failures a retry cannot fix
+func (s *NotificationService) send(ctx context.Context, d *repository.Delivery, n *repository.Notification, settings *repository.Settings) (retry bool, err error) {
+	switch d.Channel {
+	case repository.ChannelEmail:
+		return true, s.mail.Send(ctx, s.email(d, n, settings))
+
+	case repository.ChannelWebhook:
+		w, err := s.webhookRepo.GetByID(ctx, d.Recipient)
+		if err != nil {
+			return false, err
+		}
+		if !w.IsActive {
+			return false, errWebhookDisabled
+		}
+
+		tenantID, _ := tenant.TenantID(ctx)
+		event := WebhookEventCreated
+		if d.EscalationLevel > 0 {
+			event = WebhookEventEscalated
+		}
+		body, err := json.Marshal(&WebhookPayload{
+			Event:           event,
+			DeliveryID:      d.ID,
+			TenantID:        tenantID,
+			EscalationLevel: d.EscalationLevel,
+			URL:             s.url(n),
+			Notification:    n,
+		})
+		if err != nil {
+			return false, err
+		}
+		return true, s.webhooks.Send(ctx, w, event, d.ID, body)
+
+	default:
+		return false, errUnknownChannel
+	}
+}
+
+// email renders the mail of a delivery in the tenant's locale
+func (s *NotificationService) email(d *repository.Delivery, n *repository.Notification, settings *repository.Settings) *mail.Message {
+	l := i18n.NewLocalizer(settings.Locale)
+	text := l.T("notifications.email.body", map[string]string{
+		"message": n.Message,
+		"link":    s.url(n),
+	})
+	if d.EscalationLevel > 0 {
+		since := n.CreatedAt
+		if loc, err := time.LoadLocation(settings.Timezone); err == nil {
+			since = since.In(loc)
+		}
+		text = l.T("notifications.email.unacknowledged", map[string]string{
+			"since": l.FormatDateTime(since),
+		}) + "\n\n" + text
+	}
+	return &mail.Message{
+		From:    s.mailFrom,
+		To:      []string{d.Recipient},
+		Subject: s.title(l, n, d.EscalationLevel),
+		Text:    text,
+	}
+}
+
+// title is the mail subject and inbox title of a notification
+func (s *NotificationService) title(l *i18n.Localizer, n *repository.Notification, level int) string {
+	return alertTitle(l, n.Severity, n.Source, level)
+}
+
+// alertTitle is the title of an alert at an escalation level
+func alertTitle(l *i18n.Localizer, severity, source string, level int) string {
+	params := map[string]string{
+		"severity": label(l, severityKeys, severity),
+		"source":   label(l, sourceKeys, source),
+	}
+	if level == 0 {
+		return l.T("notifications.title", params)
+	}
+	params["level"] = strconv.Itoa(level)
+	return l.T("notifications.title_escalated", params)
+}
+
+// url is the absolute web app link of a notification
+func (s *NotificationService) url(n *repository.Notification) string {
+	if n.Link == nil {
+		return s.appURL + "/notifications/" + n.ID
+	}
+	return s.appURL + *n.Link
+}
+
+var severityKeys = map[string]string{
+	repository.SeverityInfo:     "notifications.severity.info",
+	repository.SeverityWarning:  "notifications.severity.warning",
+	repository.SeverityCritical: "notifications.severity.critical",
+}
+
+var sourceKeys = map[string]string{
+	repository.SourceInventory: "notifications.source.inventory",
+	repository.SourceStaff:     "notifications.source.staff",
+}
+
+// label translates a severity or source, keeping unknown ones as they are
+func label(l *i18n.Localizer, keys map[string]string, value string) string {
+	if key, ok := keys[value]; ok {
+		return l.T(key)
+	}
+	return value
+}
+
+type sendError string
+
+func (e sendError) Error() string { return string(e) }
+
+const (
+	errWebhookDisabled = sendError("webhook is disabled")
+	errUnknownChannel  = sendError("unknown channel")
+)
+
+// retryBackoff is the delay before retrying a delivery after its nth
+// attempt: 1, 4, 9, 16 minutes
+func retryBackoff(attempts int) time.Duration {
+	return time.Duration(attempts*attempts) * time.Minute
+}
+
+func minutes(n int) time.Duration {
+	return time.Duration(n) * time.Minute
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/medflow/medflow-backend/internal/notification/repository"
+	"github.com/medflow/medflow-backend/pkg/hmacsig"
+)
+
+// Webhook request headers
+const (
+	// SignatureHeader carries "t=<unix seconds>,v1=<hex HMAC-SHA256>" of
+	// "<t>.<body>" keyed with the webhook secret
+	SignatureHeader = "X-MedFlow-Signature"
+	EventHeader     = "X-MedFlow-Event"
+	DeliveryHeader  = "X-MedFlow-Delivery"
+)
+
+// webhookTimeout bounds a single webhook call
+const webhookTimeout = 10 * time.Second
+
+// WebhookPayload is the JSON body posted to webhooks
+type WebhookPayload struct {
+	Event           string                   `json:"event"`
+	DeliveryID      string                   `json:"delivery_id"`
+	TenantID        string                   `json:"tenant_id"`
+	EscalationLevel int                      `json:"escalation_level"`
+	URL             string                   `json:"url"`
+	Notification    *repository.Notification `json:"notification"`
+}
+
+// Webhook events
+const (
+	WebhookEventCreated   = "notification.created"
+	WebhookEventEscalated = "notification.escalated"
+)
+
+// Sign returns the signature header value of body sent at t
+func Sign(secret string, t time.Time, body []byte) string {
+	return hmacsig.Sign(secret, t, body)
+}
+
+// VerifySignature checks a signature header value against body, rejecting
+// signatures older than tolerance. Webhook receivers written in Go can use
+// it as is.
+func VerifySignature(secret, header string, body []byte, tolerance time.Duration, now time.Time) bool {
+	return hmacsig.Verify(secret, header, body, tolerance, now)
+}
+
+// newWebhookSecret generates the signing secret of a new webhook
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// WebhookSender posts signed payloads to webhooks
+type WebhookSender struct {
+	client *http.Client
+}
+
+// NewWebhookSender creates a sender that refuses to connect to loopback,
+// private and link-local addresses, so tenants cannot reach internal
+// services through their webhooks
+func NewWebhookSender() *WebhookSender {
+	dialer := &net.Dialer{Timeout: webhookTimeout, Control: refusePrivate}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	transport.Proxy = nil
+	return &WebhookSender{client: &http.Client{
+		Timeout:   webhookTimeout,
+		Transport: transport,
+		// Redirects could lead to internal hosts the URL check never saw
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}}
+}
+
+// Send posts body to the webhook; any status but 2xx is an error
+func (s *WebhookSender) Send(ctx context.Context, w *repository.Webhook, event, deliveryID string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "MedFlow-Webhooks/1.0")
+	req.Header.Set(EventHeader, event)
+	req.Header.Set(DeliveryHeader, deliveryID)
+	req.Header.Set(SignatureHeader, Sign(w.Secret, time.Now(), body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded %s", resp.Status)
+	}
+	return nil
+}
+
+// refusePrivate is a net.Dialer Control func rejecting non-public addresses
+func refusePrivate(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !isPublic(ip) {
+		return fmt.Errorf("webhook address %s is not public", host)
+	}
+	return nil
+}
+
+func isPublic(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsUnspecified() || ip.IsMulticast())
+}
+
+// validWebhookURL reports whether raw is an http(s) URL whose host is not
+// an address refusePrivate rejects anyway
+func validWebhookURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" || u.User != nil {
+		return false
+	}
+	if strings.EqualFold(u.Hostname(), "localhost") {
+		return false
+	}
+	if ip := net.ParseIP(u.Hostname()); ip != nil && !isPublic(ip) {
+		return false
+	}
+	return true
+}
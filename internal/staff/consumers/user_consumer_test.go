@@ -8,6 +8,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/medflow/medflow-backend/internal/staff/repository"
+	"github.com/medflow/medflow-backend/pkg/database"
 	"github.com/medflow/medflow-backend/pkg/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -104,10 +105,10 @@ func TestUserConsumer_Idempotency(t *testing.T) {
 	}
 
 	// Verify only one employee exists
-	employees, total, err := employeeRepo.List(tenantCtx, 1, 10)
+	employees, err := employeeRepo.List(tenantCtx, &database.ListQuery{Page: 1, Limit: 10})
 	require.NoError(t, err)
-	assert.Equal(t, int64(1), total)
-	assert.Len(t, employees, 1)
+	assert.Equal(t, int64(1), employees.Total)
+	assert.Len(t, employees.Items, 1)
 }
 
 func TestUserConsumer_TenantIsolation(t *testing.T) {
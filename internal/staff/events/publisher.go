@@ -202,29 +202,39 @@ func (p *StaffEventPublisher) PublishAbsenceUpdated(ctx context.Context, absence
 }
 
 // PublishAbsenceApproved publishes an absence approved event
-func (p *StaffEventPublisher) PublishAbsenceApproved(ctx context.Context, absenceID, reviewerID string) {
+func (p *StaffEventPublisher) PublishAbsenceApproved(ctx context.Context, absence *repository.Absence, reviewerID string) {
 	if p == nil { return }
 	data := messaging.AbsenceApprovedEvent{
-		AbsenceID:  absenceID,
-		ReviewerID: reviewerID,
+		AbsenceID:   absence.ID,
+		EmployeeID:  absence.EmployeeID,
+		UserID:      deref(absence.EmployeeUserID),
+		AbsenceType: absence.AbsenceType,
+		StartDate:   absence.StartDate,
+		EndDate:     absence.EndDate,
+		ReviewerID:  reviewerID,
 	}
 
 	if err := p.publisher.Publish(ctx, messaging.EventAbsenceApproved, data); err != nil {
-		p.logger.Error().Err(err).Str("absence_id", absenceID).Msg("failed to publish absence approved event")
+		p.logger.Error().Err(err).Str("absence_id", absence.ID).Msg("failed to publish absence approved event")
 	}
 }
 
 // PublishAbsenceRejected publishes an absence rejected event
-func (p *StaffEventPublisher) PublishAbsenceRejected(ctx context.Context, absenceID, reviewerID, reason string) {
+func (p *StaffEventPublisher) PublishAbsenceRejected(ctx context.Context, absence *repository.Absence, reviewerID, reason string) {
 	if p == nil { return }
 	data := messaging.AbsenceRejectedEvent{
-		AbsenceID:  absenceID,
-		ReviewerID: reviewerID,
-		Reason:     reason,
+		AbsenceID:   absence.ID,
+		EmployeeID:  absence.EmployeeID,
+		UserID:      deref(absence.EmployeeUserID),
+		AbsenceType: absence.AbsenceType,
+		StartDate:   absence.StartDate,
+		EndDate:     absence.EndDate,
+		ReviewerID:  reviewerID,
+		Reason:      reason,
 	}
 
 	if err := p.publisher.Publish(ctx, messaging.EventAbsenceRejected, data); err != nil {
-		p.logger.Error().Err(err).Str("absence_id", absenceID).Msg("failed to publish absence rejected event")
+		p.logger.Error().Err(err).Str("absence_id", absence.ID).Msg("failed to publish absence rejected event")
 	}
 }
 
@@ -320,3 +330,76 @@ func (p *StaffEventPublisher) PublishTimeBreakEnd(ctx context.Context, brk *repo
 		p.logger.Error().Err(err).Str("time_break_id", brk.ID).Msg("failed to publish time break end event")
 	}
 }
+
+// ============================================================================
+// COMPLIANCE EVENTS
+// ============================================================================
+
+// PublishComplianceAlertCreated publishes a compliance alert created event
+func (p *StaffEventPublisher) PublishComplianceAlertCreated(ctx context.Context, alert *repository.ComplianceAlert, timeEntryID string) {
+	if p == nil {
+		return
+	}
+	data := messaging.ComplianceAlertCreatedEvent{
+		AlertID:     alert.ID,
+		EmployeeID:  alert.EmployeeID,
+		UserID:      deref(alert.EmployeeUserID),
+		TimeEntryID: timeEntryID,
+		AlertType:   alert.AlertType,
+		Severity:    alert.Severity,
+		Message:     alert.Message,
+	}
+
+	if err := p.publisher.Publish(ctx, messaging.EventComplianceAlertCreated, data); err != nil {
+		p.logger.Error().Err(err).Str("alert_id", alert.ID).Msg("failed to publish compliance alert created event")
+	}
+}
+
+// PublishComplianceAlertDismissed publishes a compliance alert dismissed event
+func (p *StaffEventPublisher) PublishComplianceAlertDismissed(ctx context.Context, alertID, userID string) {
+	if p == nil {
+		return
+	}
+	data := messaging.ComplianceAlertDismissedEvent{
+		AlertID:     alertID,
+		DismissedBy: userID,
+	}
+
+	if err := p.publisher.Publish(ctx, messaging.EventComplianceAlertDismissed, data); err != nil {
+		p.logger.Error().Err(err).Str("alert_id", alertID).Msg("failed to publish compliance alert dismissed event")
+	}
+}
+
+// PublishCorrectionDecided publishes a correction request approved or
+// rejected event, depending on the request's status
+func (p *StaffEventPublisher) PublishCorrectionDecided(ctx context.Context, req *repository.CorrectionRequest, reviewerID string) {
+	if p == nil {
+		return
+	}
+	data := messaging.CorrectionDecidedEvent{
+		RequestID:     req.ID,
+		EmployeeID:    req.EmployeeID,
+		UserID:        deref(req.EmployeeUserID),
+		TimeEntryID:   deref(req.TimeEntryID),
+		RequestType:   req.RequestType,
+		RequestedDate: req.RequestedDate,
+		ReviewerID:    reviewerID,
+		Reason:        deref(req.RejectionReason),
+	}
+
+	eventType := messaging.EventCorrectionApproved
+	if req.Status == repository.CorrectionStatusRejected {
+		eventType = messaging.EventCorrectionRejected
+	}
+	if err := p.publisher.Publish(ctx, eventType, data); err != nil {
+		p.logger.Error().Err(err).Str("request_id", req.ID).Msg("failed to publish correction decided event")
+	}
+}
+
+// deref returns the value of an optional string, or "" if it is unset
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
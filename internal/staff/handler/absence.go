@@ -8,6 +8,7 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/medflow/medflow-backend/internal/staff/repository"
 	"github.com/medflow/medflow-backend/internal/staff/service"
+	"github.com/medflow/medflow-backend/pkg/database"
 	"github.com/medflow/medflow-backend/pkg/errors"
 	"github.com/medflow/medflow-backend/pkg/httputil"
 	"github.com/medflow/medflow-backend/pkg/logger"
@@ -32,19 +33,16 @@ func NewAbsenceHandler(svc *service.AbsenceService, log *logger.Logger) *Absence
 // ============================================================================
 
 // List lists absences with filters
+// GET /absences?employee_id=...&start_date=...&filter[status][in]=pending,approved&sort=-start_date
 func (h *AbsenceHandler) List(w http.ResponseWriter, r *http.Request) {
-	params := repository.AbsenceListParams{
-		Page:    1,
-		PerPage: 20,
+	q, err := httputil.ParseListQuery(r)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
 	}
+	params := repository.AbsenceListParams{Query: q}
 
 	// Parse query parameters
-	if page, _ := strconv.Atoi(r.URL.Query().Get("page")); page > 0 {
-		params.Page = page
-	}
-	if perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page")); perPage > 0 && perPage <= 100 {
-		params.PerPage = perPage
-	}
 	if employeeID := r.URL.Query().Get("employee_id"); employeeID != "" {
 		params.EmployeeID = &employeeID
 	}
@@ -65,23 +63,13 @@ func (h *AbsenceHandler) List(w http.ResponseWriter, r *http.Request) {
 		params.AbsenceType = &absenceType
 	}
 
-	absences, total, err := h.service.List(r.Context(), params)
+	page, err := h.service.List(database.ReadOnly(r.Context()), params)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
-	totalPages := int(total) / params.PerPage
-	if int(total)%params.PerPage > 0 {
-		totalPages++
-	}
-
-	httputil.JSONWithMeta(w, http.StatusOK, absences, &httputil.Meta{
-		Page:       params.Page,
-		PerPage:    params.PerPage,
-		Total:      total,
-		TotalPages: totalPages,
-	})
+	httputil.JSONWithMeta(w, http.StatusOK, page.Items, httputil.PageMeta(q, page))
 }
 
 // Get gets an absence by ID
@@ -90,7 +78,7 @@ func (h *AbsenceHandler) Get(w http.ResponseWriter, r *http.Request) {
 
 	absence, err := h.service.GetByID(r.Context(), id)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -101,25 +89,25 @@ func (h *AbsenceHandler) Get(w http.ResponseWriter, r *http.Request) {
 func (h *AbsenceHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var req CreateAbsenceRequest
 	if err := httputil.DecodeJSON(r, &req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	// Parse dates
 	startDate, err := time.Parse("2006-01-02", req.StartDate)
 	if err != nil {
-		httputil.Error(w, errors.BadRequest("invalid start_date format, expected YYYY-MM-DD"))
+		httputil.ErrorLocalized(w, r, errors.BadRequest("errors.invalid_date", map[string]string{"field": "start_date"}))
 		return
 	}
 	endDate, err := time.Parse("2006-01-02", req.EndDate)
 	if err != nil {
-		httputil.Error(w, errors.BadRequest("invalid end_date format, expected YYYY-MM-DD"))
+		httputil.ErrorLocalized(w, r, errors.BadRequest("errors.invalid_date", map[string]string{"field": "end_date"}))
 		return
 	}
 
 	// Validate dates
 	if endDate.Before(startDate) {
-		httputil.Error(w, errors.BadRequest("end_date cannot be before start_date"))
+		httputil.ErrorLocalized(w, r, errors.BadRequest("errors.end_before_start"))
 		return
 	}
 
@@ -138,7 +126,7 @@ func (h *AbsenceHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.service.Create(r.Context(), absence); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -151,20 +139,20 @@ func (h *AbsenceHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 	var req UpdateAbsenceRequest
 	if err := httputil.DecodeJSON(r, &req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	// Get existing absence
 	absence, err := h.service.GetByID(r.Context(), id)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	// Only allow updates to pending absences
 	if absence.Status != "pending" {
-		httputil.Error(w, errors.BadRequest("can only update pending absences"))
+		httputil.ErrorLocalized(w, r, errors.BadRequest("errors.staff.absence_not_pending"))
 		return
 	}
 
@@ -188,7 +176,7 @@ func (h *AbsenceHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 	// Validate dates
 	if absence.EndDate.Before(absence.StartDate) {
-		httputil.Error(w, errors.BadRequest("end_date cannot be before start_date"))
+		httputil.ErrorLocalized(w, r, errors.BadRequest("errors.end_before_start"))
 		return
 	}
 
@@ -199,7 +187,7 @@ func (h *AbsenceHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.service.Update(r.Context(), absence); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -211,7 +199,7 @@ func (h *AbsenceHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	if err := h.service.Delete(r.Context(), id); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -230,19 +218,19 @@ func (h *AbsenceHandler) Approve(w http.ResponseWriter, r *http.Request) {
 
 	reviewerID := r.Header.Get("X-User-ID")
 	if reviewerID == "" {
-		httputil.Error(w, errors.BadRequest("reviewer ID required"))
+		httputil.ErrorLocalized(w, r, errors.BadRequest("validation.required", map[string]string{"field": "X-User-ID"}))
 		return
 	}
 
 	if err := h.service.Approve(r.Context(), id, reviewerID, req.Note); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	// Get updated absence
 	absence, err := h.service.GetByID(r.Context(), id)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -255,30 +243,30 @@ func (h *AbsenceHandler) Reject(w http.ResponseWriter, r *http.Request) {
 
 	var req ApproveRejectRequest
 	if err := httputil.DecodeJSON(r, &req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	if req.Reason == "" {
-		httputil.Error(w, errors.BadRequest("rejection reason is required"))
+		httputil.ErrorLocalized(w, r, errors.BadRequest("validation.required", map[string]string{"field": "reason"}))
 		return
 	}
 
 	reviewerID := r.Header.Get("X-User-ID")
 	if reviewerID == "" {
-		httputil.Error(w, errors.BadRequest("reviewer ID required"))
+		httputil.ErrorLocalized(w, r, errors.BadRequest("validation.required", map[string]string{"field": "X-User-ID"}))
 		return
 	}
 
 	if err := h.service.Reject(r.Context(), id, reviewerID, req.Reason, req.Note); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	// Get updated absence
 	absence, err := h.service.GetByID(r.Context(), id)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -307,7 +295,7 @@ func (h *AbsenceHandler) GetEmployeeAbsences(w http.ResponseWriter, r *http.Requ
 
 	absences, err := h.service.GetEmployeeAbsences(r.Context(), employeeID, startDate, endDate)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -327,7 +315,7 @@ func (h *AbsenceHandler) ListVacationBalances(w http.ResponseWriter, r *http.Req
 
 	balances, err := h.service.ListVacationBalances(r.Context(), year)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -345,7 +333,7 @@ func (h *AbsenceHandler) GetEmployeeVacationBalance(w http.ResponseWriter, r *ht
 
 	balance, err := h.service.GetVacationBalance(r.Context(), employeeID, year)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -372,7 +360,7 @@ func (h *AbsenceHandler) SetVacationEntitlement(w http.ResponseWriter, r *http.R
 
 	var req SetVacationEntitlementRequest
 	if err := httputil.DecodeJSON(r, &req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -382,14 +370,14 @@ func (h *AbsenceHandler) SetVacationEntitlement(w http.ResponseWriter, r *http.R
 	}
 
 	if err := h.service.SetVacationEntitlement(r.Context(), employeeID, year, req.Entitlement); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	// Return updated balance
 	balance, err := h.service.GetVacationBalance(r.Context(), employeeID, year)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
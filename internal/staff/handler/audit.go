@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/medflow/medflow-backend/internal/staff/service"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/httputil"
+	"github.com/medflow/medflow-backend/pkg/logger"
+)
+
+// auditStatementTimeout bounds the statements of audit exports and chain
+// verification, which read the whole trail
+const auditStatementTimeout = 2 * time.Minute
+
+// AuditHandler handles staff audit trail endpoints
+type AuditHandler struct {
+	auditService *service.AuditService
+	logger       *logger.Logger
+}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler(auditService *service.AuditService, log *logger.Logger) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+		logger:       log,
+	}
+}
+
+// List lists audit entries
+// GET /audit?filter[employee_id]=...&filter[entity_type]=time_entry&filter[created_at][gte]=...&sort=-sequence
+func (h *AuditHandler) List(w http.ResponseWriter, r *http.Request) {
+	q, err := httputil.ParseListQuery(r)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	page, err := h.auditService.List(database.ReadOnly(r.Context()), q)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to list staff audit entries")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSONWithMeta(w, http.StatusOK, page.Items, httputil.PageMeta(q, page))
+}
+
+// Export exports the audit trail as JSON lines in chain order, e.g. for a
+// labour inspection
+// GET /audit/export?from=2026-01-01T00:00:00Z&to=2026-12-31T23:59:59Z
+func (h *AuditHandler) Export(w http.ResponseWriter, r *http.Request) {
+	var from, to *time.Time
+	for _, p := range []struct {
+		name string
+		dst  **time.Time
+	}{{"from", &from}, {"to", &to}} {
+		v := r.URL.Query().Get(p.name)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			httputil.ErrorLocalized(w, r, errors.BadRequest("errors.invalid_date", map[string]string{"field": p.name}))
+			return
+		}
+		*p.dst = &t
+	}
+
+	ctx := database.WithStatementTimeout(database.ReadOnly(r.Context()), auditStatementTimeout)
+	entries, err := h.auditService.Export(ctx, from, to)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to export staff audit trail")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	// Set headers for download
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=staff_audit_export_%s.jsonl", time.Now().Format("2006-01-02")))
+	w.WriteHeader(http.StatusOK)
+
+	encoder := json.NewEncoder(w)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			h.logger.Error().Err(err).Msg("failed to encode staff audit entry for export")
+			return
+		}
+	}
+}
+
+// Verify re-walks the tenant's staff audit trail hash chain and reports the
+// first changed, deleted or reordered entry
+// GET /audit/verify
+func (h *AuditHandler) Verify(w http.ResponseWriter, r *http.Request) {
+	ctx := database.WithStatementTimeout(r.Context(), auditStatementTimeout)
+	result, err := h.auditService.Verify(ctx)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("failed to verify staff audit trail")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, result)
+}
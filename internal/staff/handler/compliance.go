@@ -42,21 +42,21 @@ func NewComplianceHandler(
 func (h *ComplianceHandler) CheckBreakEnd(w http.ResponseWriter, r *http.Request) {
 	userID := r.Header.Get("X-User-ID")
 	if userID == "" {
-		httputil.Error(w, errors.Unauthorized("user not authenticated"))
+		httputil.ErrorLocalized(w, r, errors.Unauthorized("errors.auth.not_authenticated"))
 		return
 	}
 
 	// Get employee for user
 	employee, err := h.staffService.GetByUserID(r.Context(), userID)
 	if err != nil {
-		httputil.Error(w, errors.NotFound("employee"))
+		httputil.ErrorLocalized(w, r, errors.NotFound("employee"))
 		return
 	}
 
 	result, err := h.service.CheckBreakEndAllowed(r.Context(), employee.ID)
 	if err != nil {
 		h.logger.Error().Err(err).Msg("failed to check break end")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -71,7 +71,7 @@ func (h *ComplianceHandler) CheckBreakEndForEmployee(w http.ResponseWriter, r *h
 	result, err := h.service.CheckBreakEndAllowed(r.Context(), employeeID)
 	if err != nil {
 		h.logger.Error().Err(err).Str("employee_id", employeeID).Msg("failed to check break end for employee")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -87,21 +87,21 @@ func (h *ComplianceHandler) CheckBreakEndForEmployee(w http.ResponseWriter, r *h
 func (h *ComplianceHandler) CheckClockOut(w http.ResponseWriter, r *http.Request) {
 	userID := r.Header.Get("X-User-ID")
 	if userID == "" {
-		httputil.Error(w, errors.Unauthorized("user not authenticated"))
+		httputil.ErrorLocalized(w, r, errors.Unauthorized("errors.auth.not_authenticated"))
 		return
 	}
 
 	// Get employee for user
 	employee, err := h.staffService.GetByUserID(r.Context(), userID)
 	if err != nil {
-		httputil.Error(w, errors.NotFound("employee"))
+		httputil.ErrorLocalized(w, r, errors.NotFound("employee"))
 		return
 	}
 
 	result, err := h.service.CheckClockOutCompliance(r.Context(), employee.ID)
 	if err != nil {
 		h.logger.Error().Err(err).Msg("failed to check clock out compliance")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -124,19 +124,19 @@ type ValidateShiftRequest struct {
 func (h *ComplianceHandler) ValidateShift(w http.ResponseWriter, r *http.Request) {
 	var req ValidateShiftRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		httputil.Error(w, errors.BadRequest("invalid request body"))
+		httputil.ErrorLocalized(w, r, errors.BadRequest("errors.invalid_json"))
 		return
 	}
 
 	if req.EmployeeID == "" {
-		httputil.Error(w, errors.BadRequest("employee_id is required"))
+		httputil.ErrorLocalized(w, r, errors.BadRequest("validation.required", map[string]string{"field": "employee_id"}))
 		return
 	}
 
 	result, err := h.service.ValidateShiftAssignment(r.Context(), req.EmployeeID, req.ShiftStart, req.ShiftEnd)
 	if err != nil {
 		h.logger.Error().Err(err).Msg("failed to validate shift")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -153,7 +153,7 @@ func (h *ComplianceHandler) GetActiveAlerts(w http.ResponseWriter, r *http.Reque
 	alerts, err := h.service.GetActiveAlerts(r.Context())
 	if err != nil {
 		h.logger.Error().Err(err).Msg("failed to get alerts")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -170,7 +170,7 @@ func (h *ComplianceHandler) DismissAlert(w http.ResponseWriter, r *http.Request)
 
 	if err := h.service.DismissAlert(r.Context(), alertID, userID); err != nil {
 		h.logger.Error().Err(err).Str("alert_id", alertID).Msg("failed to dismiss alert")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -212,7 +212,7 @@ func (h *ComplianceHandler) GetViolations(w http.ResponseWriter, r *http.Request
 	violations, err := h.service.GetViolations(r.Context(), employeeID, startDate, endDate, status)
 	if err != nil {
 		h.logger.Error().Err(err).Msg("failed to get violations")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -229,7 +229,7 @@ func (h *ComplianceHandler) AcknowledgeViolation(w http.ResponseWriter, r *http.
 
 	if err := h.service.AcknowledgeViolation(r.Context(), violationID, userID); err != nil {
 		h.logger.Error().Err(err).Str("violation_id", violationID).Msg("failed to acknowledge violation")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -246,7 +246,7 @@ func (h *ComplianceHandler) GetSettings(w http.ResponseWriter, r *http.Request)
 	settings, err := h.service.GetSettings(r.Context())
 	if err != nil {
 		h.logger.Error().Err(err).Msg("failed to get settings")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -258,13 +258,13 @@ func (h *ComplianceHandler) GetSettings(w http.ResponseWriter, r *http.Request)
 func (h *ComplianceHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
 	var settings repository.ComplianceSettings
 	if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
-		httputil.Error(w, errors.BadRequest("invalid request body"))
+		httputil.ErrorLocalized(w, r, errors.BadRequest("errors.invalid_json"))
 		return
 	}
 
 	if err := h.service.UpdateSettings(r.Context(), &settings); err != nil {
 		h.logger.Error().Err(err).Msg("failed to update settings")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -272,7 +272,7 @@ func (h *ComplianceHandler) UpdateSettings(w http.ResponseWriter, r *http.Reques
 	updated, err := h.service.GetSettings(r.Context())
 	if err != nil {
 		h.logger.Error().Err(err).Msg("failed to get updated settings")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -288,7 +288,7 @@ func (h *ComplianceHandler) UpdateSettings(w http.ResponseWriter, r *http.Reques
 func (h *ComplianceHandler) RunComplianceCheck(w http.ResponseWriter, r *http.Request) {
 	if err := h.service.CheckAllActiveEmployees(r.Context()); err != nil {
 		h.logger.Error().Err(err).Msg("failed to run compliance check")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -314,27 +314,27 @@ type CreateCorrectionRequestInput struct {
 func (h *ComplianceHandler) CreateCorrectionRequest(w http.ResponseWriter, r *http.Request) {
 	userID := r.Header.Get("X-User-ID")
 	if userID == "" {
-		httputil.Error(w, errors.Unauthorized("user not authenticated"))
+		httputil.ErrorLocalized(w, r, errors.Unauthorized("errors.auth.not_authenticated"))
 		return
 	}
 
 	// Get employee for user
 	employee, err := h.staffService.GetByUserID(r.Context(), userID)
 	if err != nil {
-		httputil.Error(w, errors.NotFound("employee"))
+		httputil.ErrorLocalized(w, r, errors.NotFound("employee"))
 		return
 	}
 
 	var input CreateCorrectionRequestInput
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		httputil.Error(w, errors.BadRequest("invalid request body"))
+		httputil.ErrorLocalized(w, r, errors.BadRequest("errors.invalid_json"))
 		return
 	}
 
 	// Parse date
 	requestedDate, err := time.Parse("2006-01-02", input.RequestedDate)
 	if err != nil {
-		httputil.Error(w, errors.BadRequest("invalid date format, use YYYY-MM-DD"))
+		httputil.ErrorLocalized(w, r, errors.BadRequest("errors.invalid_date", map[string]string{"field": "requested_date"}))
 		return
 	}
 
@@ -343,7 +343,7 @@ func (h *ComplianceHandler) CreateCorrectionRequest(w http.ResponseWriter, r *ht
 	if input.RequestedClockIn != nil {
 		t, err := time.Parse(time.RFC3339, *input.RequestedClockIn)
 		if err != nil {
-			httputil.Error(w, errors.BadRequest("invalid clock_in format"))
+			httputil.ErrorLocalized(w, r, errors.BadRequest("errors.invalid_timestamp", map[string]string{"field": "clock_in"}))
 			return
 		}
 		clockIn = &t
@@ -351,7 +351,7 @@ func (h *ComplianceHandler) CreateCorrectionRequest(w http.ResponseWriter, r *ht
 	if input.RequestedClockOut != nil {
 		t, err := time.Parse(time.RFC3339, *input.RequestedClockOut)
 		if err != nil {
-			httputil.Error(w, errors.BadRequest("invalid clock_out format"))
+			httputil.ErrorLocalized(w, r, errors.BadRequest("errors.invalid_timestamp", map[string]string{"field": "clock_out"}))
 			return
 		}
 		clockOut = &t
@@ -369,7 +369,7 @@ func (h *ComplianceHandler) CreateCorrectionRequest(w http.ResponseWriter, r *ht
 
 	if err := h.service.CreateCorrectionRequest(r.Context(), req); err != nil {
 		h.logger.Error().Err(err).Msg("failed to create correction request")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -381,20 +381,20 @@ func (h *ComplianceHandler) CreateCorrectionRequest(w http.ResponseWriter, r *ht
 func (h *ComplianceHandler) GetMyCorrectionRequests(w http.ResponseWriter, r *http.Request) {
 	userID := r.Header.Get("X-User-ID")
 	if userID == "" {
-		httputil.Error(w, errors.Unauthorized("user not authenticated"))
+		httputil.ErrorLocalized(w, r, errors.Unauthorized("errors.auth.not_authenticated"))
 		return
 	}
 
 	employee, err := h.staffService.GetByUserID(r.Context(), userID)
 	if err != nil {
-		httputil.Error(w, errors.NotFound("employee"))
+		httputil.ErrorLocalized(w, r, errors.NotFound("employee"))
 		return
 	}
 
 	requests, err := h.service.ListEmployeeCorrectionRequests(r.Context(), employee.ID)
 	if err != nil {
 		h.logger.Error().Err(err).Msg("failed to get correction requests")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -409,7 +409,7 @@ func (h *ComplianceHandler) GetPendingCorrectionRequests(w http.ResponseWriter,
 	requests, err := h.service.ListPendingCorrectionRequests(r.Context())
 	if err != nil {
 		h.logger.Error().Err(err).Msg("failed to get pending correction requests")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -426,7 +426,7 @@ func (h *ComplianceHandler) GetCorrectionRequest(w http.ResponseWriter, r *http.
 	req, err := h.service.GetCorrectionRequest(r.Context(), requestID)
 	if err != nil {
 		h.logger.Error().Err(err).Str("request_id", requestID).Msg("failed to get correction request")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -442,13 +442,13 @@ func (h *ComplianceHandler) ApproveCorrectionRequest(w http.ResponseWriter, r *h
 	// Get reviewer employee ID
 	employee, err := h.staffService.GetByUserID(r.Context(), userID)
 	if err != nil {
-		httputil.Error(w, errors.NotFound("employee"))
+		httputil.ErrorLocalized(w, r, errors.NotFound("employee"))
 		return
 	}
 
 	if err := h.service.ApproveCorrectionRequest(r.Context(), requestID, employee.ID); err != nil {
 		h.logger.Error().Err(err).Str("request_id", requestID).Msg("failed to approve correction request")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -468,20 +468,20 @@ func (h *ComplianceHandler) RejectCorrectionRequest(w http.ResponseWriter, r *ht
 
 	var input RejectCorrectionRequestInput
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		httputil.Error(w, errors.BadRequest("invalid request body"))
+		httputil.ErrorLocalized(w, r, errors.BadRequest("errors.invalid_json"))
 		return
 	}
 
 	// Get reviewer employee ID
 	employee, err := h.staffService.GetByUserID(r.Context(), userID)
 	if err != nil {
-		httputil.Error(w, errors.NotFound("employee"))
+		httputil.ErrorLocalized(w, r, errors.NotFound("employee"))
 		return
 	}
 
 	if err := h.service.RejectCorrectionRequest(r.Context(), requestID, employee.ID, input.Reason); err != nil {
 		h.logger.Error().Err(err).Str("request_id", requestID).Msg("failed to reject correction request")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -2,63 +2,89 @@ package handler
 
 import (
 	"net/http"
-	"strconv"
+	"path"
 	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/medflow/medflow-backend/internal/staff/client"
 	"github.com/medflow/medflow-backend/internal/staff/repository"
 	"github.com/medflow/medflow-backend/internal/staff/service"
+	"github.com/medflow/medflow-backend/pkg/database"
 	"github.com/medflow/medflow-backend/pkg/errors"
 	"github.com/medflow/medflow-backend/pkg/httputil"
 	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/medflow/medflow-backend/pkg/scan"
+	"github.com/medflow/medflow-backend/pkg/search"
+	"github.com/medflow/medflow-backend/pkg/storage"
 )
 
+// multipartMemory is how much of an upload form is buffered in memory before
+// parts spill to temporary files
+const multipartMemory = 8 << 20
+
+// fileCategoryTypes lists the file types each employee file category accepts
+var fileCategoryTypes = map[string][]string{
+	"contract":      storage.PDFOnly,
+	"payroll":       storage.PDFOnly,
+	"id_document":   storage.ScannedDocument,
+	"certificate":   storage.ScannedDocument,
+	"qualification": storage.ScannedDocument,
+	"medical":       storage.ScannedDocument,
+	"other":         storage.AnyDocument,
+}
+
 // EmployeeHandler handles employee endpoints
 type EmployeeHandler struct {
 	service    *service.StaffService
 	userClient *client.UserClient
+	documents  *storage.Documents
 	logger     *logger.Logger
 }
 
 // NewEmployeeHandler creates a new employee handler
-func NewEmployeeHandler(svc *service.StaffService, userClient *client.UserClient, log *logger.Logger) *EmployeeHandler {
+func NewEmployeeHandler(svc *service.StaffService, userClient *client.UserClient, documents *storage.Documents, log *logger.Logger) *EmployeeHandler {
 	return &EmployeeHandler{
 		service:    svc,
 		userClient: userClient,
+		documents:  documents,
 		logger:     log,
 	}
 }
 
 // List lists all employees
+// GET /employees?filter[department]=...&sort=last_name,first_name
 func (h *EmployeeHandler) List(w http.ResponseWriter, r *http.Request) {
-	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
-	if page < 1 {
-		page = 1
+	q, err := httputil.ParseListQuery(r)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
 	}
 
-	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
-	if perPage < 1 || perPage > 100 {
-		perPage = 20
+	page, err := h.service.List(database.ReadOnly(r.Context()), q)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
 	}
 
-	employees, total, err := h.service.List(r.Context(), page, perPage)
+	httputil.JSONWithMeta(w, http.StatusOK, page.Items, httputil.PageMeta(q, page))
+}
+
+// Search searches employees by name or personnel number
+// GET /search?q=&limit=
+func (h *EmployeeHandler) Search(w http.ResponseWriter, r *http.Request) {
+	req, err := search.ParseRequest(r)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
-	totalPages := int(total) / perPage
-	if int(total)%perPage > 0 {
-		totalPages++
+	resp, err := h.service.Search(database.ReadOnly(r.Context()), req)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
 	}
 
-	httputil.JSONWithMeta(w, http.StatusOK, employees, &httputil.Meta{
-		Page:       page,
-		PerPage:    perPage,
-		Total:      total,
-		TotalPages: totalPages,
-	})
+	httputil.JSON(w, http.StatusOK, resp)
 }
 
 // Get gets an employee by ID
@@ -67,7 +93,7 @@ func (h *EmployeeHandler) Get(w http.ResponseWriter, r *http.Request) {
 
 	employee, err := h.service.GetByID(r.Context(), id)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -97,7 +123,7 @@ type CreateEmployeeRequest struct {
 func (h *EmployeeHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var req CreateEmployeeRequest
 	if err := httputil.DecodeJSON(r, &req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -107,7 +133,7 @@ func (h *EmployeeHandler) Create(w http.ResponseWriter, r *http.Request) {
 	if req.Credentials != nil {
 		// Validate email is provided when creating user account
 		if req.Employee.Email == nil || *req.Employee.Email == "" {
-			httputil.Error(w, errors.BadRequest("email is required when creating user account"))
+			httputil.ErrorLocalized(w, r, errors.BadRequest("errors.staff.email_required_for_account"))
 			return
 		}
 
@@ -137,13 +163,13 @@ func (h *EmployeeHandler) Create(w http.ResponseWriter, r *http.Request) {
 			// Convert user service errors to AppErrors for proper HTTP status codes
 			errMsg := err.Error()
 			if strings.Contains(errMsg, "status 400") || strings.Contains(errMsg, "VALIDATION_ERROR") {
-				httputil.Error(w, errors.Validation(map[string]string{
-					"credentials": "user account creation failed: invalid data (check email format and password)",
+				httputil.ErrorLocalized(w, r, errors.Validation(map[string]string{
+					"credentials": "validation.account_data",
 				}))
 			} else if strings.Contains(errMsg, "status 409") {
-				httputil.Error(w, errors.Conflict("a user with this email already exists"))
+				httputil.ErrorLocalized(w, r, errors.Conflict("errors.user.email_in_use"))
 			} else {
-				httputil.Error(w, errors.Internal("failed to create user account"))
+				httputil.ErrorLocalized(w, r, errors.Internal("errors.user.create_failed"))
 			}
 			return
 		}
@@ -167,7 +193,7 @@ func (h *EmployeeHandler) Create(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -212,7 +238,7 @@ func (h *EmployeeHandler) Update(w http.ResponseWriter, r *http.Request) {
 	// because the repository does a full-column UPDATE.
 	existing, err := h.service.GetByID(r.Context(), id)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -220,14 +246,14 @@ func (h *EmployeeHandler) Update(w http.ResponseWriter, r *http.Request) {
 	// Go's JSON decoder only overwrites fields present in the JSON body,
 	// leaving all other fields at their current database values.
 	if err := httputil.DecodeJSON(r, existing); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	existing.ID = id // Ensure ID can't be changed via request body
 
 	if err := h.service.Update(r.Context(), existing); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -239,7 +265,7 @@ func (h *EmployeeHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	if err := h.service.Delete(r.Context(), id); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -253,7 +279,7 @@ func (h *EmployeeHandler) UpdatePersonal(w http.ResponseWriter, r *http.Request)
 	// Get existing employee
 	emp, err := h.service.GetByID(r.Context(), id)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -268,7 +294,7 @@ func (h *EmployeeHandler) UpdatePersonal(w http.ResponseWriter, r *http.Request)
 		MaritalStatus *string `json:"marital_status"`
 	}
 	if err := httputil.DecodeJSON(r, &req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -295,7 +321,7 @@ func (h *EmployeeHandler) UpdatePersonal(w http.ResponseWriter, r *http.Request)
 	}
 
 	if err := h.service.Update(r.Context(), emp); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -308,14 +334,14 @@ func (h *EmployeeHandler) UpdateContact(w http.ResponseWriter, r *http.Request)
 
 	var contact repository.EmployeeContact
 	if err := httputil.DecodeJSON(r, &contact); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	contact.EmployeeID = id
 
 	if err := h.service.SaveContact(r.Context(), &contact); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -328,14 +354,14 @@ func (h *EmployeeHandler) UpdateFinancials(w http.ResponseWriter, r *http.Reques
 
 	var fin repository.EmployeeFinancials
 	if err := httputil.DecodeJSON(r, &fin); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	fin.EmployeeID = id
 
 	if err := h.service.SaveFinancials(r.Context(), &fin); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -348,7 +374,7 @@ func (h *EmployeeHandler) ListFiles(w http.ResponseWriter, r *http.Request) {
 
 	files, err := h.service.ListFiles(r.Context(), id)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -359,21 +385,69 @@ func (h *EmployeeHandler) ListFiles(w http.ResponseWriter, r *http.Request) {
 func (h *EmployeeHandler) UploadFile(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	// Parse multipart form (max 10MB)
-	if err := r.ParseMultipartForm(10 << 20); err != nil {
-		httputil.Error(w, err)
+	// Leave room for the multipart envelope; the file itself is limited by the upload policy
+	r.Body = http.MaxBytesReader(w, r.Body, h.documents.Policy.MaxBytes+(1<<20))
+	if err := r.ParseMultipartForm(multipartMemory); err != nil {
+		httputil.ErrorLocalized(w, r, errors.BadRequest("errors.file.too_large", map[string]string{"max": storage.FormatSize(h.documents.Policy.MaxBytes)}))
 		return
 	}
 
-	// TODO: Implement actual file upload to storage
-	// For now, just create a record
+	upload, header, err := r.FormFile("file")
+	if err != nil {
+		httputil.ErrorLocalized(w, r, errors.BadRequest("validation.required", map[string]string{"field": "file"}))
+		return
+	}
+	defer upload.Close()
+
+	category := r.FormValue("category")
+	if category == "" {
+		category = "other"
+	}
+	fileTypes, ok := fileCategoryTypes[category]
+	if !ok {
+		httputil.ErrorLocalized(w, r, errors.Validation(nil).WithDetail("category", "validation.one_of", map[string]string{
+			"field":  "category",
+			"values": "contract, id_document, certificate, qualification, medical, payroll, other",
+		}))
+		return
+	}
+
+	// Make sure the employee exists in this tenant before storing anything
+	if _, err := h.service.GetByID(r.Context(), id); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	// Content type, size and checksum come from the content, not the client;
+	// the upload is type-checked and scanned before it is stored
+	obj, err := h.documents.Upload(r.Context(), "staff/files", id, upload, fileTypes)
+	if err != nil {
+		var appErr *errors.AppError
+		if !errors.As(err, &appErr) {
+			h.logger.Error().Err(err).Str("employee_id", id).Msg("failed to store employee file")
+			err = errors.Internal("errors.file.store_failed")
+		}
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
 
+	size := int(obj.Size)
 	var file repository.EmployeeFile
 	file.EmployeeID = id
 	file.Name = r.FormValue("name")
+	if file.Name == "" {
+		file.Name = header.Filename
+	}
 	file.FileType = r.FormValue("type")
-	file.Category = strPtr(r.FormValue("category"))
-	file.FilePath = "/uploads/" + file.Name // Placeholder
+	if file.FileType == "" {
+		file.FileType = strings.TrimPrefix(path.Ext(obj.Key), ".")
+	}
+	file.Category = &category
+	file.FilePath = obj.Key
+	file.FileSize = &size
+	file.MimeType = &obj.ContentType
+	file.SHA256 = &obj.SHA256
+	file.ScanStatus = obj.ScanStatus
 
 	userID := r.Header.Get("X-User-ID")
 	if userID != "" {
@@ -381,7 +455,9 @@ func (h *EmployeeHandler) UploadFile(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.service.CreateFile(r.Context(), &file); err != nil {
-		httputil.Error(w, err)
+		// Clean up the object on DB error
+		h.documents.Delete(r.Context(), obj.Key)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -390,16 +466,72 @@ func (h *EmployeeHandler) UploadFile(w http.ResponseWriter, r *http.Request) {
 
 // DeleteFile deletes a file
 func (h *EmployeeHandler) DeleteFile(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
 	fileID := chi.URLParam(r, "fileId")
 
+	// Get the record to find the object key
+	file, err := h.service.GetFile(r.Context(), id, fileID)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
 	if err := h.service.DeleteFile(r.Context(), fileID); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
+	// Remove object (best-effort)
+	if err := h.documents.Delete(r.Context(), file.FilePath); err != nil {
+		h.logger.Warn().Err(err).Str("key", file.FilePath).Msg("failed to delete stored employee file")
+	}
+
 	httputil.NoContent(w)
 }
 
+// GetFileURL returns a signed, short-lived download URL for a file
+// GET /employees/{id}/files/{fileId}/url
+func (h *EmployeeHandler) GetFileURL(w http.ResponseWriter, r *http.Request) {
+	link, ok := h.fileURL(w, r)
+	if !ok {
+		return
+	}
+	httputil.JSON(w, http.StatusOK, link)
+}
+
+// DownloadFile redirects to a signed, short-lived download URL
+// GET /employees/{id}/files/{fileId}/download
+func (h *EmployeeHandler) DownloadFile(w http.ResponseWriter, r *http.Request) {
+	link, ok := h.fileURL(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	http.Redirect(w, r, link.URL, http.StatusFound)
+}
+
+// fileURL signs a download URL for the file in the path.
+// Files that are not scanned clean are never served.
+func (h *EmployeeHandler) fileURL(w http.ResponseWriter, r *http.Request) (*storage.DownloadURL, bool) {
+	file, err := h.service.GetFile(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "fileId"))
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return nil, false
+	}
+	if err := scan.CheckDownload(file.ScanStatus); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return nil, false
+	}
+
+	link, err := h.documents.DownloadURL(r.Context(), file.FilePath, file.Name)
+	if err != nil {
+		h.logger.Error().Err(err).Str("key", file.FilePath).Msg("failed to sign file URL")
+		httputil.ErrorLocalized(w, r, errors.Internal("errors.file.read_failed"))
+		return nil, false
+	}
+	return link, true
+}
+
 func strPtr(s string) *string {
 	if s == "" {
 		return nil
@@ -412,13 +544,13 @@ func strPtr(s string) *string {
 func (h *EmployeeHandler) GetMe(w http.ResponseWriter, r *http.Request) {
 	userID := r.Header.Get("X-User-ID")
 	if userID == "" {
-		httputil.Error(w, errors.Unauthorized("missing user context"))
+		httputil.ErrorLocalized(w, r, errors.Unauthorized("errors.auth.not_authenticated"))
 		return
 	}
 
 	emp, err := h.service.GetMyEmployee(r.Context(), userID)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -430,7 +562,7 @@ func (h *EmployeeHandler) GetMe(w http.ResponseWriter, r *http.Request) {
 func (h *EmployeeHandler) UpdateMyVisibility(w http.ResponseWriter, r *http.Request) {
 	userID := r.Header.Get("X-User-ID")
 	if userID == "" {
-		httputil.Error(w, errors.Unauthorized("missing user context"))
+		httputil.ErrorLocalized(w, r, errors.Unauthorized("errors.auth.not_authenticated"))
 		return
 	}
 
@@ -438,12 +570,12 @@ func (h *EmployeeHandler) UpdateMyVisibility(w http.ResponseWriter, r *http.Requ
 		ShowInStaffList bool `json:"show_in_staff_list"`
 	}
 	if err := httputil.DecodeJSON(r, &req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	if err := h.service.UpdateMyVisibility(r.Context(), userID, req.ShowInStaffList); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -467,13 +599,13 @@ func (h *EmployeeHandler) AddCredentials(w http.ResponseWriter, r *http.Request)
 
 	var req AddCredentialsRequest
 	if err := httputil.DecodeJSON(r, &req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	// Validate password length
 	if len(req.Password) < 8 {
-		httputil.Error(w, errors.BadRequest("password must be at least 8 characters"))
+		httputil.ErrorLocalized(w, r, errors.BadRequest("errors.user.password_too_short", map[string]string{"min": "8"}))
 		return
 	}
 
@@ -485,7 +617,7 @@ func (h *EmployeeHandler) AddCredentials(w http.ResponseWriter, r *http.Request)
 	// Get actor ID from request headers (set by API Gateway from JWT)
 	actorID := r.Header.Get("X-User-ID")
 	if actorID == "" {
-		httputil.Error(w, errors.Unauthorized("missing user context"))
+		httputil.ErrorLocalized(w, r, errors.Unauthorized("errors.auth.not_authenticated"))
 		return
 	}
 
@@ -500,7 +632,7 @@ func (h *EmployeeHandler) AddCredentials(w http.ResponseWriter, r *http.Request)
 		h.logger.Error().Err(err).
 			Str("employee_id", employeeID).
 			Msg("failed to add credentials to employee")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -529,7 +661,7 @@ func (h *EmployeeHandler) RemoveCredentials(w http.ResponseWriter, r *http.Reque
 	// Get actor ID from request headers
 	actorID := r.Header.Get("X-User-ID")
 	if actorID == "" {
-		httputil.Error(w, errors.Unauthorized("missing user context"))
+		httputil.ErrorLocalized(w, r, errors.Unauthorized("errors.auth.not_authenticated"))
 		return
 	}
 
@@ -544,7 +676,7 @@ func (h *EmployeeHandler) RemoveCredentials(w http.ResponseWriter, r *http.Reque
 		h.logger.Error().Err(err).
 			Str("employee_id", employeeID).
 			Msg("failed to remove credentials from employee")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -565,7 +697,7 @@ func (h *EmployeeHandler) GetCredentialStatus(w http.ResponseWriter, r *http.Req
 		h.logger.Error().Err(err).
 			Str("employee_id", employeeID).
 			Msg("failed to get credential status")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -2,12 +2,12 @@ package handler
 
 import (
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/medflow/medflow-backend/internal/staff/repository"
 	"github.com/medflow/medflow-backend/internal/staff/service"
+	"github.com/medflow/medflow-backend/pkg/database"
 	"github.com/medflow/medflow-backend/pkg/errors"
 	"github.com/medflow/medflow-backend/pkg/httputil"
 	"github.com/medflow/medflow-backend/pkg/logger"
@@ -37,7 +37,7 @@ func (h *ShiftHandler) ListTemplates(w http.ResponseWriter, r *http.Request) {
 
 	templates, err := h.service.ListTemplates(r.Context(), activeOnly)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -50,7 +50,7 @@ func (h *ShiftHandler) GetTemplate(w http.ResponseWriter, r *http.Request) {
 
 	tmpl, err := h.service.GetTemplateByID(r.Context(), id)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -61,7 +61,7 @@ func (h *ShiftHandler) GetTemplate(w http.ResponseWriter, r *http.Request) {
 func (h *ShiftHandler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
 	var tmpl repository.ShiftTemplate
 	if err := httputil.DecodeJSON(r, &tmpl); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -72,7 +72,7 @@ func (h *ShiftHandler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.service.CreateTemplate(r.Context(), &tmpl); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -85,14 +85,14 @@ func (h *ShiftHandler) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
 
 	var tmpl repository.ShiftTemplate
 	if err := httputil.DecodeJSON(r, &tmpl); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	tmpl.ID = id
 
 	if err := h.service.UpdateTemplate(r.Context(), &tmpl); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -104,7 +104,7 @@ func (h *ShiftHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	if err := h.service.DeleteTemplate(r.Context(), id); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -116,19 +116,19 @@ func (h *ShiftHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
 // ============================================================================
 
 // List lists shift assignments with filters
+// GET /shifts?start_date=...&end_date=...&filter[shift_type]=night&sort=shift_date,start_time
 func (h *ShiftHandler) List(w http.ResponseWriter, r *http.Request) {
-	params := repository.ShiftListParams{
-		Page:    1,
-		PerPage: 50,
+	q, err := httputil.ParseListQuery(r)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
 	}
+	if q.Limit == 0 {
+		q.Limit = 50
+	}
+	params := repository.ShiftListParams{Query: q}
 
 	// Parse query parameters
-	if page, _ := strconv.Atoi(r.URL.Query().Get("page")); page > 0 {
-		params.Page = page
-	}
-	if perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page")); perPage > 0 && perPage <= 100 {
-		params.PerPage = perPage
-	}
 	if employeeID := r.URL.Query().Get("employee_id"); employeeID != "" {
 		params.EmployeeID = &employeeID
 	}
@@ -149,23 +149,13 @@ func (h *ShiftHandler) List(w http.ResponseWriter, r *http.Request) {
 		params.ShiftType = &shiftType
 	}
 
-	shifts, total, err := h.service.ListAssignments(r.Context(), params)
+	page, err := h.service.ListAssignments(database.ReadOnly(r.Context()), params)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
-	totalPages := int(total) / params.PerPage
-	if int(total)%params.PerPage > 0 {
-		totalPages++
-	}
-
-	httputil.JSONWithMeta(w, http.StatusOK, shifts, &httputil.Meta{
-		Page:       params.Page,
-		PerPage:    params.PerPage,
-		Total:      total,
-		TotalPages: totalPages,
-	})
+	httputil.JSONWithMeta(w, http.StatusOK, page.Items, httputil.PageMeta(q, page))
 }
 
 // Get gets a shift assignment by ID
@@ -174,7 +164,7 @@ func (h *ShiftHandler) Get(w http.ResponseWriter, r *http.Request) {
 
 	shift, err := h.service.GetAssignmentByID(r.Context(), id)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -185,14 +175,14 @@ func (h *ShiftHandler) Get(w http.ResponseWriter, r *http.Request) {
 func (h *ShiftHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var req CreateShiftRequest
 	if err := httputil.DecodeJSON(r, &req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	// Parse date
 	shiftDate, err := time.Parse("2006-01-02", req.ShiftDate)
 	if err != nil {
-		httputil.Error(w, errors.BadRequest("invalid shift_date format, expected YYYY-MM-DD"))
+		httputil.ErrorLocalized(w, r, errors.BadRequest("errors.invalid_date", map[string]string{"field": "shift_date"}))
 		return
 	}
 
@@ -214,7 +204,7 @@ func (h *ShiftHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.service.CreateAssignment(r.Context(), shift); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -227,14 +217,14 @@ func (h *ShiftHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 	var req UpdateShiftRequest
 	if err := httputil.DecodeJSON(r, &req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	// Get existing shift
 	shift, err := h.service.GetAssignmentByID(r.Context(), id)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -273,7 +263,7 @@ func (h *ShiftHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.service.UpdateAssignment(r.Context(), shift); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -285,7 +275,7 @@ func (h *ShiftHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	if err := h.service.DeleteAssignment(r.Context(), id); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -296,7 +286,7 @@ func (h *ShiftHandler) Delete(w http.ResponseWriter, r *http.Request) {
 func (h *ShiftHandler) BulkCreate(w http.ResponseWriter, r *http.Request) {
 	var req BulkCreateShiftsRequest
 	if err := httputil.DecodeJSON(r, &req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -306,7 +296,7 @@ func (h *ShiftHandler) BulkCreate(w http.ResponseWriter, r *http.Request) {
 	for _, s := range req.Shifts {
 		shiftDate, err := time.Parse("2006-01-02", s.ShiftDate)
 		if err != nil {
-			httputil.Error(w, errors.BadRequest("invalid shift_date format, expected YYYY-MM-DD"))
+			httputil.ErrorLocalized(w, r, errors.BadRequest("errors.invalid_date", map[string]string{"field": "shift_date"}))
 			return
 		}
 
@@ -327,7 +317,7 @@ func (h *ShiftHandler) BulkCreate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.service.BulkCreateAssignments(r.Context(), shifts); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -359,7 +349,7 @@ func (h *ShiftHandler) GetEmployeeShifts(w http.ResponseWriter, r *http.Request)
 
 	shifts, err := h.service.GetEmployeeShifts(r.Context(), employeeID, startDate, endDate)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
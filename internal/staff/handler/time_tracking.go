@@ -35,7 +35,7 @@ func NewTimeTrackingHandler(svc *service.TimeTrackingService, staffSvc *service.
 func (h *TimeTrackingHandler) GetAllStatuses(w http.ResponseWriter, r *http.Request) {
 	statuses, err := h.service.GetAllStatuses(r.Context())
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -60,7 +60,7 @@ func (h *TimeTrackingHandler) GetMyStatus(w http.ResponseWriter, r *http.Request
 	// Get user ID from header (set by API Gateway from JWT)
 	userID := r.Header.Get("X-User-ID")
 	if userID == "" {
-		httputil.Error(w, errors.Unauthorized("user not authenticated"))
+		httputil.ErrorLocalized(w, r, errors.Unauthorized("errors.auth.not_authenticated"))
 		return
 	}
 
@@ -75,14 +75,14 @@ func (h *TimeTrackingHandler) GetMyStatus(w http.ResponseWriter, r *http.Request
 			})
 			return
 		}
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	// Get time status for this employee
 	status, err := h.service.GetEmployeeStatus(r.Context(), employee.ID)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -155,13 +155,13 @@ func (h *TimeTrackingHandler) GetEntriesByDate(w http.ResponseWriter, r *http.Re
 
 	date, err := time.Parse("2006-01-02", dateStr)
 	if err != nil {
-		httputil.Error(w, errors.BadRequest("invalid date format, expected YYYY-MM-DD"))
+		httputil.ErrorLocalized(w, r, errors.BadRequest("errors.invalid_date", map[string]string{"field": "date"}))
 		return
 	}
 
 	entries, err := h.service.GetEntriesByDate(r.Context(), date)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -181,7 +181,7 @@ func (h *TimeTrackingHandler) UpdateEntry(w http.ResponseWriter, r *http.Request
 		Notes    *string          `json:"notes"`
 	}
 	if err := httputil.DecodeJSON(r, &req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -189,7 +189,7 @@ func (h *TimeTrackingHandler) UpdateEntry(w http.ResponseWriter, r *http.Request
 	if req.ClockIn != nil {
 		clockIn, err := time.Parse(time.RFC3339, *req.ClockIn)
 		if err != nil {
-			httputil.Error(w, errors.BadRequest("invalid clock_in format"))
+			httputil.ErrorLocalized(w, r, errors.BadRequest("errors.invalid_timestamp", map[string]string{"field": "clock_in"}))
 			return
 		}
 		updates["clock_in"] = clockIn
@@ -204,12 +204,12 @@ func (h *TimeTrackingHandler) UpdateEntry(w http.ResponseWriter, r *http.Request
 			// String value — parse as RFC3339
 			var clockOutStr string
 			if err := json.Unmarshal(req.ClockOut, &clockOutStr); err != nil {
-				httputil.Error(w, errors.BadRequest("invalid clock_out format"))
+				httputil.ErrorLocalized(w, r, errors.BadRequest("errors.invalid_timestamp", map[string]string{"field": "clock_out"}))
 				return
 			}
 			clockOut, err := time.Parse(time.RFC3339, clockOutStr)
 			if err != nil {
-				httputil.Error(w, errors.BadRequest("invalid clock_out format"))
+				httputil.ErrorLocalized(w, r, errors.BadRequest("errors.invalid_timestamp", map[string]string{"field": "clock_out"}))
 				return
 			}
 			updates["clock_out"] = clockOut
@@ -224,7 +224,7 @@ func (h *TimeTrackingHandler) UpdateEntry(w http.ResponseWriter, r *http.Request
 
 	entry, err := h.service.UpdateEntry(r.Context(), id, updates, userID)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -244,7 +244,7 @@ func (h *TimeTrackingHandler) UpdateEntryBreaks(w http.ResponseWriter, r *http.R
 		} `json:"breaks"`
 	}
 	if err := httputil.DecodeJSON(r, &req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -253,7 +253,7 @@ func (h *TimeTrackingHandler) UpdateEntryBreaks(w http.ResponseWriter, r *http.R
 	for _, b := range req.Breaks {
 		startTime, err := time.Parse(time.RFC3339, b.StartTime)
 		if err != nil {
-			httputil.Error(w, errors.BadRequest("invalid start_time format for break"))
+			httputil.ErrorLocalized(w, r, errors.BadRequest("errors.invalid_timestamp", map[string]string{"field": "breaks.start_time"}))
 			return
 		}
 
@@ -266,7 +266,7 @@ func (h *TimeTrackingHandler) UpdateEntryBreaks(w http.ResponseWriter, r *http.R
 		if b.EndTime != nil && *b.EndTime != "" {
 			endTime, err := time.Parse(time.RFC3339, *b.EndTime)
 			if err != nil {
-				httputil.Error(w, errors.BadRequest("invalid end_time format for break"))
+				httputil.ErrorLocalized(w, r, errors.BadRequest("errors.invalid_timestamp", map[string]string{"field": "breaks.end_time"}))
 				return
 			}
 			bi.EndTime = &endTime
@@ -279,7 +279,7 @@ func (h *TimeTrackingHandler) UpdateEntryBreaks(w http.ResponseWriter, r *http.R
 
 	entry, err := h.service.ReplaceBreaksForEntry(r.Context(), id, breaks, userID)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -292,7 +292,7 @@ func (h *TimeTrackingHandler) DeleteEntry(w http.ResponseWriter, r *http.Request
 	id := chi.URLParam(r, "id")
 
 	if err := h.service.DeleteEntry(r.Context(), id); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -306,7 +306,7 @@ func (h *TimeTrackingHandler) ClockIn(w http.ResponseWriter, r *http.Request) {
 
 	entry, err := h.service.ClockIn(r.Context(), employeeID)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -320,7 +320,7 @@ func (h *TimeTrackingHandler) ClockOut(w http.ResponseWriter, r *http.Request) {
 
 	entry, err := h.service.ClockOut(r.Context(), employeeID)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -334,7 +334,7 @@ func (h *TimeTrackingHandler) StartBreak(w http.ResponseWriter, r *http.Request)
 
 	entry, err := h.service.StartBreak(r.Context(), employeeID)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -348,7 +348,7 @@ func (h *TimeTrackingHandler) EndBreak(w http.ResponseWriter, r *http.Request) {
 
 	entry, err := h.service.EndBreak(r.Context(), employeeID)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -365,7 +365,7 @@ func (h *TimeTrackingHandler) ManualClockIn(w http.ResponseWriter, r *http.Reque
 		Date string `json:"date"` // Optional: "YYYY-MM-DD", defaults to today
 	}
 	if err := httputil.DecodeJSON(r, &req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -375,7 +375,7 @@ func (h *TimeTrackingHandler) ManualClockIn(w http.ResponseWriter, r *http.Reque
 		var err error
 		date, err = time.Parse("2006-01-02", req.Date)
 		if err != nil {
-			httputil.Error(w, errors.BadRequest("invalid date format, expected YYYY-MM-DD"))
+			httputil.ErrorLocalized(w, r, errors.BadRequest("errors.invalid_date", map[string]string{"field": "date"}))
 			return
 		}
 	} else {
@@ -387,7 +387,7 @@ func (h *TimeTrackingHandler) ManualClockIn(w http.ResponseWriter, r *http.Reque
 	if len(req.Time) == 5 { // HH:mm format
 		t, err := time.Parse("15:04", req.Time)
 		if err != nil {
-			httputil.Error(w, errors.BadRequest("invalid time format, expected HH:mm"))
+			httputil.ErrorLocalized(w, r, errors.BadRequest("errors.invalid_time", map[string]string{"field": "time"}))
 			return
 		}
 		clockInTime = time.Date(date.Year(), date.Month(), date.Day(),
@@ -396,7 +396,7 @@ func (h *TimeTrackingHandler) ManualClockIn(w http.ResponseWriter, r *http.Reque
 		var err error
 		clockInTime, err = time.Parse(time.RFC3339, req.Time)
 		if err != nil {
-			httputil.Error(w, errors.BadRequest("invalid time format"))
+			httputil.ErrorLocalized(w, r, errors.BadRequest("errors.invalid_timestamp", map[string]string{"field": "time"}))
 			return
 		}
 	}
@@ -405,7 +405,7 @@ func (h *TimeTrackingHandler) ManualClockIn(w http.ResponseWriter, r *http.Reque
 
 	entry, err := h.service.ManualClockIn(r.Context(), employeeID, clockInTime, userID)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -422,7 +422,7 @@ func (h *TimeTrackingHandler) ManualClockOut(w http.ResponseWriter, r *http.Requ
 		Date string `json:"date"` // Optional: "YYYY-MM-DD", defaults to today
 	}
 	if err := httputil.DecodeJSON(r, &req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -432,7 +432,7 @@ func (h *TimeTrackingHandler) ManualClockOut(w http.ResponseWriter, r *http.Requ
 		var err error
 		date, err = time.Parse("2006-01-02", req.Date)
 		if err != nil {
-			httputil.Error(w, errors.BadRequest("invalid date format, expected YYYY-MM-DD"))
+			httputil.ErrorLocalized(w, r, errors.BadRequest("errors.invalid_date", map[string]string{"field": "date"}))
 			return
 		}
 	} else {
@@ -444,7 +444,7 @@ func (h *TimeTrackingHandler) ManualClockOut(w http.ResponseWriter, r *http.Requ
 	if len(req.Time) == 5 { // HH:mm format
 		t, err := time.Parse("15:04", req.Time)
 		if err != nil {
-			httputil.Error(w, errors.BadRequest("invalid time format, expected HH:mm"))
+			httputil.ErrorLocalized(w, r, errors.BadRequest("errors.invalid_time", map[string]string{"field": "time"}))
 			return
 		}
 		clockOutTime = time.Date(date.Year(), date.Month(), date.Day(),
@@ -453,7 +453,7 @@ func (h *TimeTrackingHandler) ManualClockOut(w http.ResponseWriter, r *http.Requ
 		var err error
 		clockOutTime, err = time.Parse(time.RFC3339, req.Time)
 		if err != nil {
-			httputil.Error(w, errors.BadRequest("invalid time format"))
+			httputil.ErrorLocalized(w, r, errors.BadRequest("errors.invalid_timestamp", map[string]string{"field": "time"}))
 			return
 		}
 	}
@@ -462,7 +462,7 @@ func (h *TimeTrackingHandler) ManualClockOut(w http.ResponseWriter, r *http.Requ
 
 	entry, err := h.service.ManualClockOut(r.Context(), employeeID, clockOutTime, userID)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -484,7 +484,7 @@ func (h *TimeTrackingHandler) GetEmployeeHistory(w http.ResponseWriter, r *http.
 	if startStr != "" {
 		startDate, err = time.Parse("2006-01-02", startStr)
 		if err != nil {
-			httputil.Error(w, errors.BadRequest("invalid start date format"))
+			httputil.ErrorLocalized(w, r, errors.BadRequest("errors.invalid_date", map[string]string{"field": "start"}))
 			return
 		}
 	} else {
@@ -496,7 +496,7 @@ func (h *TimeTrackingHandler) GetEmployeeHistory(w http.ResponseWriter, r *http.
 	if endStr != "" {
 		endDate, err = time.Parse("2006-01-02", endStr)
 		if err != nil {
-			httputil.Error(w, errors.BadRequest("invalid end date format"))
+			httputil.ErrorLocalized(w, r, errors.BadRequest("errors.invalid_date", map[string]string{"field": "end"}))
 			return
 		}
 	} else {
@@ -507,7 +507,7 @@ func (h *TimeTrackingHandler) GetEmployeeHistory(w http.ResponseWriter, r *http.
 
 	summary, err := h.service.GetEmployeeHistory(r.Context(), employeeID, startDate, endDate)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -529,7 +529,7 @@ func (h *TimeTrackingHandler) GetEmployeeCorrections(w http.ResponseWriter, r *h
 	if startStr != "" {
 		startDate, err = time.Parse("2006-01-02", startStr)
 		if err != nil {
-			httputil.Error(w, errors.BadRequest("invalid start date format"))
+			httputil.ErrorLocalized(w, r, errors.BadRequest("errors.invalid_date", map[string]string{"field": "start"}))
 			return
 		}
 	} else {
@@ -539,7 +539,7 @@ func (h *TimeTrackingHandler) GetEmployeeCorrections(w http.ResponseWriter, r *h
 	if endStr != "" {
 		endDate, err = time.Parse("2006-01-02", endStr)
 		if err != nil {
-			httputil.Error(w, errors.BadRequest("invalid end date format"))
+			httputil.ErrorLocalized(w, r, errors.BadRequest("errors.invalid_date", map[string]string{"field": "end"}))
 			return
 		}
 	} else {
@@ -548,7 +548,7 @@ func (h *TimeTrackingHandler) GetEmployeeCorrections(w http.ResponseWriter, r *h
 
 	corrections, err := h.service.GetEmployeeCorrections(r.Context(), employeeID, startDate, endDate)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -566,14 +566,14 @@ func (h *TimeTrackingHandler) CreateCorrection(w http.ResponseWriter, r *http.Re
 		Reason     string  `json:"reason"`
 	}
 	if err := httputil.DecodeJSON(r, &req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	// Parse date
 	date, err := time.Parse("2006-01-02", req.Date)
 	if err != nil {
-		httputil.Error(w, errors.BadRequest("invalid date format, expected YYYY-MM-DD"))
+		httputil.ErrorLocalized(w, r, errors.BadRequest("errors.invalid_date", map[string]string{"field": "date"}))
 		return
 	}
 
@@ -590,7 +590,7 @@ func (h *TimeTrackingHandler) CreateCorrection(w http.ResponseWriter, r *http.Re
 	if req.ClockIn != nil {
 		clockIn, err := parseTimeWithDate(*req.ClockIn, date)
 		if err != nil {
-			httputil.Error(w, errors.BadRequest("invalid clock_in format"))
+			httputil.ErrorLocalized(w, r, errors.BadRequest("errors.invalid_timestamp", map[string]string{"field": "clock_in"}))
 			return
 		}
 		corr.CorrectedClockIn = &clockIn
@@ -600,14 +600,14 @@ func (h *TimeTrackingHandler) CreateCorrection(w http.ResponseWriter, r *http.Re
 	if req.ClockOut != nil {
 		clockOut, err := parseTimeWithDate(*req.ClockOut, date)
 		if err != nil {
-			httputil.Error(w, errors.BadRequest("invalid clock_out format"))
+			httputil.ErrorLocalized(w, r, errors.BadRequest("errors.invalid_timestamp", map[string]string{"field": "clock_out"}))
 			return
 		}
 		corr.CorrectedClockOut = &clockOut
 	}
 
 	if err := h.service.CreateCorrection(r.Context(), corr); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
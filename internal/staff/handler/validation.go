@@ -28,7 +28,7 @@ func (h *ValidationHandler) ValidateIBAN(w http.ResponseWriter, r *http.Request)
 		IBAN string `json:"iban" validate:"required"`
 	}
 	if err := httputil.DecodeJSON(r, &req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -42,7 +42,7 @@ func (h *ValidationHandler) ValidateTaxID(w http.ResponseWriter, r *http.Request
 		TaxID string `json:"tax_id" validate:"required"`
 	}
 	if err := httputil.DecodeJSON(r, &req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -56,7 +56,7 @@ func (h *ValidationHandler) ValidateSVNumber(w http.ResponseWriter, r *http.Requ
 		SVNumber string `json:"sv_number" validate:"required"`
 	}
 	if err := httputil.DecodeJSON(r, &req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
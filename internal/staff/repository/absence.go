@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -34,7 +35,8 @@ type Absence struct {
 	UpdatedBy        *string    `db:"updated_by" json:"updated_by,omitempty"`
 
 	// Joined fields
-	EmployeeName *string `db:"employee_name" json:"employee_name,omitempty"`
+	EmployeeName   *string `db:"employee_name" json:"employee_name,omitempty"`
+	EmployeeUserID *string `db:"employee_user_id" json:"-"` // set by GetByID
 }
 
 // VacationBalance represents an employee's vacation balance for a year
@@ -83,8 +85,8 @@ type AbsenceListParams struct {
 	EndDate     *time.Time
 	Status      *string
 	AbsenceType *string
-	Page        int
-	PerPage     int
+	// Query holds generic filters, sort and paging; nil returns the first page
+	Query *database.ListQuery
 }
 
 // AbsenceRepository handles absence persistence
@@ -148,7 +150,8 @@ func (r *AbsenceRepository) GetByID(ctx context.Context, id string) (*Absence, e
 			       a.requested_at, a.reviewed_by, a.reviewed_at, a.rejection_reason,
 			       a.vacation_days_used, a.employee_note, a.manager_note,
 			       a.created_at, a.updated_at, a.created_by, a.updated_by,
-			       CONCAT(e.first_name, ' ', e.last_name) as employee_name
+			       CONCAT(e.first_name, ' ', e.last_name) as employee_name,
+			       e.user_id as employee_user_id
 			FROM absences a
 			LEFT JOIN employees e ON a.employee_id = e.id
 			WHERE a.id = $1 AND a.deleted_at IS NULL
@@ -166,64 +169,62 @@ func (r *AbsenceRepository) GetByID(ctx context.Context, id string) (*Absence, e
 	return &absence, nil
 }
 
+// absenceListSchema whitelists the filter and sort fields of GET /absences
+var absenceListSchema = database.ListSchema{
+	Fields: map[string]database.Field{
+		"employee_id":  {Column: "a.employee_id", Type: database.FieldUUID, Filter: true},
+		"start_date":   {Column: "a.start_date", Type: database.FieldDate, Filter: true, Sort: true},
+		"end_date":     {Column: "a.end_date", Type: database.FieldDate, Filter: true, Sort: true},
+		"absence_type": {Column: "a.absence_type", Type: database.FieldText, Filter: true, Sort: true},
+		"status":       {Column: "a.status", Type: database.FieldText, Filter: true, Sort: true},
+		"requested_at": {Column: "a.requested_at", Type: database.FieldTime, Filter: true, Sort: true},
+		"created_at":   {Column: "a.created_at", Type: database.FieldTime, Filter: true, Sort: true},
+	},
+	DefaultSort: []string{"-start_date"},
+	IDColumn:    "a.id",
+}
+
 // List lists absences with filters
 // TENANT-ISOLATED: Queries only the tenant's schema
-func (r *AbsenceRepository) List(ctx context.Context, params AbsenceListParams) ([]*Absence, int64, error) {
+func (r *AbsenceRepository) List(ctx context.Context, params AbsenceListParams) (*database.Page[*Absence], error) {
 	tenantID, err := tenant.TenantID(ctx)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	var total int64
-	var absences []*Absence
+	q := params.Query
+	if q == nil {
+		q = &database.ListQuery{}
+	}
+
+	var page *database.Page[*Absence]
 
 	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
-		// Build WHERE clause
+		// Typed parameters become fixed conditions; StartDate/EndDate select overlapping absences
 		whereClause := "WHERE a.deleted_at IS NULL"
 		args := []interface{}{}
-		argNum := 1
 
 		if params.EmployeeID != nil {
-			whereClause += " AND a.employee_id = $" + string(rune('0'+argNum))
 			args = append(args, *params.EmployeeID)
-			argNum++
+			whereClause += fmt.Sprintf(" AND a.employee_id = $%d", len(args))
 		}
 		if params.StartDate != nil {
-			whereClause += " AND a.end_date >= $" + string(rune('0'+argNum))
 			args = append(args, *params.StartDate)
-			argNum++
+			whereClause += fmt.Sprintf(" AND a.end_date >= $%d", len(args))
 		}
 		if params.EndDate != nil {
-			whereClause += " AND a.start_date <= $" + string(rune('0'+argNum))
 			args = append(args, *params.EndDate)
-			argNum++
+			whereClause += fmt.Sprintf(" AND a.start_date <= $%d", len(args))
 		}
 		if params.Status != nil {
-			whereClause += " AND a.status = $" + string(rune('0'+argNum))
 			args = append(args, *params.Status)
-			argNum++
+			whereClause += fmt.Sprintf(" AND a.status = $%d", len(args))
 		}
 		if params.AbsenceType != nil {
-			whereClause += " AND a.absence_type = $" + string(rune('0'+argNum))
 			args = append(args, *params.AbsenceType)
-			argNum++
-		}
-
-		// Count total
-		countQuery := "SELECT COUNT(*) FROM absences a " + whereClause
-		if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
-			return err
+			whereClause += fmt.Sprintf(" AND a.absence_type = $%d", len(args))
 		}
 
-		// Get paginated absences
-		if params.PerPage <= 0 {
-			params.PerPage = 20
-		}
-		if params.Page <= 0 {
-			params.Page = 1
-		}
-		offset := (params.Page - 1) * params.PerPage
-
 		query := `
 			SELECT a.id, a.employee_id, a.start_date, a.end_date, a.absence_type, a.status,
 			       a.requested_at, a.reviewed_by, a.reviewed_at, a.rejection_reason,
@@ -232,19 +233,18 @@ func (r *AbsenceRepository) List(ctx context.Context, params AbsenceListParams)
 			       CONCAT(e.first_name, ' ', e.last_name) as employee_name
 			FROM absences a
 			LEFT JOIN employees e ON a.employee_id = e.id
-		` + whereClause + `
-			ORDER BY a.start_date DESC
-			LIMIT $` + string(rune('0'+argNum)) + ` OFFSET $` + string(rune('0'+argNum+1))
+		` + whereClause
 
-		args = append(args, params.PerPage, offset)
-		return r.db.SelectContext(ctx, &absences, query, args...)
+		var err error
+		page, err = database.SelectPage[*Absence](ctx, r.db, &absenceListSchema, q, query, args...)
+		return err
 	})
 
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	return absences, total, nil
+	return page, nil
 }
 
 // ListPending lists all pending absence requests
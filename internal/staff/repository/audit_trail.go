@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/medflow/medflow-backend/pkg/auditchain"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/tenant"
+)
+
+// AuditChain is the name of the hash chain of the staff audit trail
+const AuditChain = "staff.audit_trail"
+
+// AuditEntry is an entry of the staff audit trail: who changed which field
+// of a working time record, absence or personnel record, from what to what.
+// Entries are append-only and hash-chained per tenant (pkg/auditchain).
+type AuditEntry struct {
+	ID              string    `db:"id" json:"id"`
+	EntityType      string    `db:"entity_type" json:"entity_type"`
+	EntityID        string    `db:"entity_id" json:"entity_id"`
+	EmployeeID      *string   `db:"employee_id" json:"employee_id,omitempty"`
+	Action          string    `db:"action" json:"action"`
+	FieldChanges    *string   `db:"field_changes" json:"field_changes,omitempty"`
+	Metadata        *string   `db:"metadata" json:"metadata,omitempty"`
+	PerformedBy     *string   `db:"performed_by" json:"performed_by,omitempty"`
+	PerformedByName *string   `db:"performed_by_name" json:"performed_by_name,omitempty"`
+	IPAddress       *string   `db:"ip_address" json:"ip_address,omitempty"`
+	CreatedAt       time.Time `db:"created_at" json:"created_at"`
+	Sequence        int64     `db:"sequence" json:"sequence"`
+	PrevHash        string    `db:"prev_hash" json:"prev_hash"`
+	Hash            string    `db:"hash" json:"hash"`
+}
+
+const auditEntryColumns = `
+	id, entity_type, entity_id, employee_id, action, field_changes, metadata,
+	performed_by, performed_by_name, ip_address, created_at, sequence, prev_hash, hash
+`
+
+// chainEntry returns the entry as its hash chain sees it
+func (e *AuditEntry) chainEntry() *auditchain.Entry {
+	return &auditchain.Entry{
+		Sequence:        e.Sequence,
+		PrevHash:        e.PrevHash,
+		Hash:            e.Hash,
+		ID:              e.ID,
+		EntityType:      e.EntityType,
+		EntityID:        e.EntityID,
+		EmployeeID:      e.EmployeeID,
+		Action:          e.Action,
+		FieldChanges:    e.FieldChanges,
+		Metadata:        e.Metadata,
+		PerformedBy:     e.PerformedBy,
+		PerformedByName: e.PerformedByName,
+		IPAddress:       e.IPAddress,
+		CreatedAt:       e.CreatedAt,
+	}
+}
+
+// AuditTrailRepository handles staff audit trail persistence.
+// All operations are append-only: no UPDATE or DELETE is permitted.
+type AuditTrailRepository struct {
+	db *database.DB
+}
+
+// NewAuditTrailRepository creates a new audit trail repository
+func NewAuditTrailRepository(db *database.DB) *AuditTrailRepository {
+	return &AuditTrailRepository{db: db}
+}
+
+// Transaction runs fn in one tenant transaction. Repository calls made with
+// the context fn receives join it, so a change and its audit entries commit
+// or roll back together.
+// TENANT-ISOLATED: Runs via RLS
+func (r *AuditTrailRepository) Transaction(ctx context.Context, fn func(context.Context) error) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		return fn(database.InTx(ctx))
+	})
+}
+
+// Create appends an entry and links it to the tenant's hash chain. The chain
+// head stays locked until the entry is committed, so concurrent entries get
+// consecutive sequences.
+// TENANT-ISOLATED: Inserts with tenant_id for RLS
+func (r *AuditTrailRepository) Create(ctx context.Context, entry *AuditEntry) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	if entry.ID == "" {
+		entry.ID = uuid.New().String()
+	}
+	entry.ID = auditchain.CanonicalUUID(entry.ID)
+	entry.EntityID = auditchain.CanonicalUUID(entry.EntityID)
+	entry.EmployeeID = auditchain.CanonicalUUIDPtr(entry.EmployeeID)
+	entry.PerformedBy = auditchain.CanonicalUUIDPtr(entry.PerformedBy)
+
+	chain := auditchain.New(r.db, AuditChain, nil)
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		head, err := chain.Lock(ctx, tenantID)
+		if err != nil {
+			return err
+		}
+
+		entry.CreatedAt = auditchain.Now()
+		entry.Sequence = head.Sequence + 1
+		content, err := entry.chainEntry().Content(tenantID)
+		if err != nil {
+			return err
+		}
+		next := head.Next(content)
+		entry.PrevHash = head.Hash
+		entry.Hash = next.Hash
+
+		_, err = r.db.ExecContext(ctx, `
+			INSERT INTO audit_trail (
+				id, tenant_id, entity_type, entity_id, employee_id, action, field_changes,
+				metadata, performed_by, performed_by_name, ip_address, created_at,
+				sequence, prev_hash, hash
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		`,
+			entry.ID, tenantID, entry.EntityType, entry.EntityID, entry.EmployeeID, entry.Action,
+			entry.FieldChanges, entry.Metadata, entry.PerformedBy, entry.PerformedByName,
+			entry.IPAddress, entry.CreatedAt, entry.Sequence, entry.PrevHash, entry.Hash,
+		)
+		if err != nil {
+			if appErr := database.MapPQError(err); appErr != nil {
+				return appErr
+			}
+			return err
+		}
+
+		return chain.Advance(ctx, tenantID, next)
+	})
+}
+
+// auditListSchema whitelists the filter and sort fields of GET /audit
+var auditListSchema = database.ListSchema{
+	Fields: map[string]database.Field{
+		"entity_type":  {Column: "entity_type", Type: database.FieldText, Filter: true},
+		"entity_id":    {Column: "entity_id", Type: database.FieldUUID, Filter: true},
+		"employee_id":  {Column: "employee_id", Type: database.FieldUUID, Filter: true, Nullable: true},
+		"action":       {Column: "action", Type: database.FieldText, Filter: true},
+		"performed_by": {Column: "performed_by", Type: database.FieldUUID, Filter: true, Nullable: true},
+		"created_at":   {Column: "created_at", Type: database.FieldTime, Filter: true, Sort: true},
+		"sequence":     {Column: "sequence", Type: database.FieldInt, Filter: true, Sort: true},
+	},
+	DefaultSort: []string{"-sequence"},
+}
+
+// List lists audit entries filtered, sorted and paginated by q
+// TENANT-ISOLATED: Returns only entries via RLS
+func (r *AuditTrailRepository) List(ctx context.Context, q *database.ListQuery) (*database.Page[*AuditEntry], error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var page *database.Page[*AuditEntry]
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		var err error
+		page, err = database.SelectPage[*AuditEntry](ctx, r.db, &auditListSchema, q,
+			`SELECT`+auditEntryColumns+`FROM audit_trail WHERE 1=1`)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// Export returns the audit entries in a time range in chain order
+// TENANT-ISOLATED: Returns only entries via RLS
+func (r *AuditTrailRepository) Export(ctx context.Context, from, to *time.Time) ([]*AuditEntry, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*AuditEntry
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		args := []interface{}{}
+		query := `SELECT` + auditEntryColumns + `FROM audit_trail WHERE 1=1`
+
+		if from != nil {
+			args = append(args, *from)
+			query += fmt.Sprintf(` AND created_at >= $%d`, len(args))
+		}
+		if to != nil {
+			args = append(args, *to)
+			query += fmt.Sprintf(` AND created_at <= $%d`, len(args))
+		}
+
+		query += ` ORDER BY sequence`
+		return r.db.SelectContext(ctx, &entries, query, args...)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// Verify re-walks the tenant's hash chain and reports the first entry that
+// was changed, deleted or reordered, checking the chain head and its latest
+// anchor in the tenant audit log as well
+// TENANT-ISOLATED: Reads entries via RLS
+func (r *AuditTrailRepository) Verify(ctx context.Context) (*auditchain.Result, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *auditchain.Result
+	chain := auditchain.New(r.db, AuditChain, nil)
+	err = r.db.WithTenantRLS(database.ReadOnly(ctx), tenantID, func(ctx context.Context) error {
+		var err error
+		result, err = chain.Verify(ctx, tenantID, func(ctx context.Context, after, upto int64, limit int) ([]*auditchain.Entry, error) {
+			var entries []*AuditEntry
+			err := r.db.SelectContext(ctx, &entries, `SELECT`+auditEntryColumns+`
+				FROM audit_trail
+				WHERE sequence > $1 AND sequence <= $2
+				ORDER BY sequence
+				LIMIT $3
+			`, after, upto, limit)
+			if err != nil {
+				return nil, err
+			}
+
+			chained := make([]*auditchain.Entry, len(entries))
+			for i, e := range entries {
+				chained[i] = e.chainEntry()
+			}
+			return chained, nil
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,116 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/medflow/medflow-backend/internal/staff/repository"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ============================================================================
+// AUDIT TRAIL TESTS
+// ============================================================================
+
+func TestAuditTrail_CommitsAndRollsBackWithChange(t *testing.T) {
+	ctx := context.Background()
+
+	tenant := suite.SetupStaffWithTimeTrackingTenant(t, ctx, "test-staff-audit-tx")
+	tenantCtx := suite.TenantContext(tenant)
+
+	timeRepo := repository.NewTimeTrackingRepository(suite.DB)
+	auditRepo := repository.NewAuditTrailRepository(suite.DB)
+	employeeID := createTestEmployee(t, tenantCtx, "Jonas", "Weber")
+	entry := clockInEmployee(t, tenantCtx, timeRepo, employeeID)
+
+	update := func(ctx context.Context, notes string, fail error) error {
+		return auditRepo.Transaction(ctx, func(ctx context.Context) error {
+			entry.Notes = &notes
+			if err := timeRepo.UpdateEntry(ctx, entry); err != nil {
+				return err
+			}
+			changes := `{"notes": {"old": null, "new": "` + notes + `"}}`
+			if err := auditRepo.Create(ctx, &repository.AuditEntry{
+				EntityType:   "time_entry",
+				EntityID:     entry.ID,
+				EmployeeID:   &employeeID,
+				Action:       "update",
+				FieldChanges: &changes,
+			}); err != nil {
+				return err
+			}
+			return fail
+		})
+	}
+
+	// A failing change takes its audit entry with it
+	require.ErrorIs(t, update(tenantCtx, "rolled back", assert.AnError), assert.AnError)
+	got, err := timeRepo.GetEntryByID(tenantCtx, entry.ID)
+	require.NoError(t, err)
+	assert.Nil(t, got.Notes)
+
+	page, err := auditRepo.List(tenantCtx, &database.ListQuery{Page: 1, Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, page.Items)
+
+	// A committed change is committed with its audit entry
+	require.NoError(t, update(tenantCtx, "Arzttermin", nil))
+	require.NoError(t, update(tenantCtx, "Arzttermin, nachgetragen", nil))
+
+	page, err = auditRepo.List(tenantCtx, &database.ListQuery{Page: 1, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 2)
+	assert.Equal(t, int64(2), page.Items[0].Sequence)
+	assert.Equal(t, employeeID, *page.Items[0].EmployeeID)
+	assert.JSONEq(t, `{"notes": {"old": null, "new": "Arzttermin, nachgetragen"}}`, *page.Items[0].FieldChanges)
+
+	from := time.Now().Add(-time.Hour)
+	exported, err := auditRepo.Export(tenantCtx, &from, nil)
+	require.NoError(t, err)
+	require.Len(t, exported, 2)
+	assert.Equal(t, exported[0].Hash, exported[1].PrevHash)
+
+	result, err := auditRepo.Verify(tenantCtx)
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, int64(2), result.Entries)
+}
+
+func TestAuditTrail_LockedEntryHoldsConcurrentChanges(t *testing.T) {
+	ctx := context.Background()
+
+	tenant := suite.SetupStaffWithTimeTrackingTenant(t, ctx, "test-staff-lock-entry")
+	tenantCtx := suite.TenantContext(tenant)
+
+	timeRepo := repository.NewTimeTrackingRepository(suite.DB)
+	auditRepo := repository.NewAuditTrailRepository(suite.DB)
+	employeeID := createTestEmployee(t, tenantCtx, "Mia", "Krause")
+	entry := clockInEmployee(t, tenantCtx, timeRepo, employeeID)
+
+	err := auditRepo.Transaction(tenantCtx, func(ctx context.Context) error {
+		locked, err := timeRepo.LockEntry(ctx, entry.ID)
+		if err != nil {
+			return err
+		}
+
+		// A second change cannot read the entry until this one commits, so
+		// its before state cannot go stale
+		waitCtx, cancel := context.WithTimeout(tenantCtx, 300*time.Millisecond)
+		defer cancel()
+		_, err = timeRepo.LockEntry(waitCtx, entry.ID)
+		assert.Error(t, err)
+
+		notes := "Fortbildung"
+		locked.Notes = &notes
+		return timeRepo.UpdateEntry(ctx, locked)
+	})
+	require.NoError(t, err)
+
+	got, err := timeRepo.LockEntry(tenantCtx, entry.ID)
+	require.NoError(t, err)
+	require.NotNil(t, got.Notes)
+	assert.Equal(t, "Fortbildung", *got.Notes)
+}
@@ -91,7 +91,8 @@ type ComplianceAlert struct {
 	UpdatedAt   time.Time  `db:"updated_at" json:"updated_at"`
 
 	// Joined fields
-	EmployeeName *string `db:"employee_name" json:"employee_name,omitempty"`
+	EmployeeName   *string `db:"employee_name" json:"employee_name,omitempty"`
+	EmployeeUserID *string `db:"employee_user_id" json:"-"` // set by CreateAlert
 }
 
 // Alert types
@@ -132,8 +133,9 @@ type CorrectionRequest struct {
 	DeletedAt         *time.Time `db:"deleted_at" json:"-"`
 
 	// Joined fields
-	EmployeeName *string `db:"employee_name" json:"employee_name,omitempty"`
-	ReviewerName *string `db:"reviewer_name" json:"reviewer_name,omitempty"`
+	EmployeeName   *string `db:"employee_name" json:"employee_name,omitempty"`
+	ReviewerName   *string `db:"reviewer_name" json:"reviewer_name,omitempty"`
+	EmployeeUserID *string `db:"employee_user_id" json:"-"` // set by GetCorrectionRequestByID
 }
 
 // Correction request types
@@ -392,11 +394,12 @@ func (r *ComplianceRepository) CreateAlert(ctx context.Context, a *ComplianceAle
 			INSERT INTO compliance_alerts (
 				id, tenant_id, employee_id, alert_type, severity, message, action_label, is_active
 			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-			RETURNING created_at, updated_at
+			RETURNING created_at, updated_at,
+			          (SELECT user_id FROM employees WHERE id = employee_id) AS employee_user_id
 		`
 		return r.db.QueryRowxContext(ctx, query,
 			a.ID, tenantID, a.EmployeeID, a.AlertType, a.Severity, a.Message, a.ActionLabel, true,
-		).Scan(&a.CreatedAt, &a.UpdatedAt)
+		).Scan(&a.CreatedAt, &a.UpdatedAt, &a.EmployeeUserID)
 	})
 }
 
@@ -521,7 +524,8 @@ func (r *ComplianceRepository) GetCorrectionRequestByID(ctx context.Context, id
 			       r.status, r.reviewed_by, r.reviewed_at, r.rejection_reason,
 			       r.created_at, r.updated_at,
 			       CONCAT(e.first_name, ' ', e.last_name) as employee_name,
-			       CONCAT(rev.first_name, ' ', rev.last_name) as reviewer_name
+			       CONCAT(rev.first_name, ' ', rev.last_name) as reviewer_name,
+			       e.user_id as employee_user_id
 			FROM time_correction_requests r
 			LEFT JOIN employees e ON r.employee_id = e.id
 			LEFT JOIN employees rev ON r.reviewed_by = rev.id
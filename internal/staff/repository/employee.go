@@ -8,6 +8,9 @@ import (
 	"github.com/google/uuid"
 	"github.com/medflow/medflow-backend/pkg/database"
 	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/scan"
+	"github.com/medflow/medflow-backend/pkg/search"
+	"github.com/medflow/medflow-backend/pkg/storage"
 	"github.com/medflow/medflow-backend/pkg/tenant"
 )
 
@@ -132,14 +135,19 @@ type EmployeeFile struct {
 	EmployeeID  string    `db:"employee_id" json:"employee_id"`
 	Name        string    `db:"name" json:"name"`
 	FileType    string    `db:"file_type" json:"file_type"`
-	FilePath    string    `db:"file_path" json:"file_path"`
+	FilePath    string    `db:"file_path" json:"-"` // object storage key
 	FileSize    *int      `db:"file_size" json:"file_size,omitempty"`
 	MimeType    *string   `db:"mime_type" json:"mime_type,omitempty"`
 	Category    *string   `db:"category" json:"category,omitempty"`
+	SHA256      *string   `db:"sha256" json:"sha256,omitempty"`
+	ScanStatus  string    `db:"scan_status" json:"scan_status"`
 	UploadedAt  time.Time `db:"uploaded_at" json:"uploaded_at"`
 	UploadedBy  *string   `db:"uploaded_by" json:"uploaded_by,omitempty"`
 }
 
+// employeeFileColumns lists the EmployeeFile columns
+const employeeFileColumns = `id, employee_id, name, file_type, file_path, file_size, mime_type, category, sha256, scan_status, uploaded_at, uploaded_by`
+
 // EmployeeRepository handles employee persistence
 type EmployeeRepository struct {
 	db *database.DB
@@ -286,28 +294,36 @@ func (r *EmployeeRepository) GetByUserID(ctx context.Context, userID string) (*E
 	return &emp, nil
 }
 
-// List lists employees with pagination
+// employeeListSchema whitelists the filter and sort fields of GET /employees
+var employeeListSchema = database.ListSchema{
+	Fields: map[string]database.Field{
+		"first_name":         {Column: "first_name", Type: database.FieldText, Filter: true, Sort: true},
+		"last_name":          {Column: "last_name", Type: database.FieldText, Filter: true, Sort: true},
+		"employee_number":    {Column: "employee_number", Type: database.FieldText, Filter: true, Sort: true, Nullable: true},
+		"job_title":          {Column: "job_title", Type: database.FieldText, Filter: true, Sort: true, Nullable: true},
+		"department":         {Column: "department", Type: database.FieldText, Filter: true, Sort: true, Nullable: true},
+		"employment_type":    {Column: "employment_type", Type: database.FieldText, Filter: true, Sort: true},
+		"status":             {Column: "status", Type: database.FieldText, Filter: true, Sort: true},
+		"show_in_staff_list": {Column: "show_in_staff_list", Type: database.FieldBool, Filter: true},
+		"hire_date":          {Column: "hire_date", Type: database.FieldDate, Filter: true, Sort: true},
+		"created_at":         {Column: "created_at", Type: database.FieldTime, Filter: true, Sort: true},
+	},
+	DefaultSort: []string{"last_name", "first_name"},
+}
+
+// List lists employees filtered, sorted and paginated by q
 // TENANT-ISOLATED: Returns only employees from the tenant's schema
-func (r *EmployeeRepository) List(ctx context.Context, page, perPage int) ([]*Employee, int64, error) {
+func (r *EmployeeRepository) List(ctx context.Context, q *database.ListQuery) (*database.Page[*Employee], error) {
 	// Extract tenant schema from context
 	tenantID, err := tenant.TenantID(ctx)
 	if err != nil {
-		return nil, 0, err // Fail-fast if tenant context missing
+		return nil, err // Fail-fast if tenant context missing
 	}
 
-	var total int64
-	var employees []*Employee
+	var page *database.Page[*Employee]
 
 	// Execute queries with tenant's search_path
 	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
-		// Count total employees
-		countQuery := `SELECT COUNT(*) FROM employees WHERE deleted_at IS NULL`
-		if err := r.db.GetContext(ctx, &total, countQuery); err != nil {
-			return err
-		}
-
-		// Get paginated employees
-		offset := (page - 1) * perPage
 		query := `
 			SELECT id, user_id, employee_number, first_name, last_name, avatar_url,
 			       date_of_birth, gender, nationality, birth_place, marital_status,
@@ -319,18 +335,18 @@ func (r *EmployeeRepository) List(ctx context.Context, page, perPage int) ([]*Em
 			       created_at, updated_at
 			FROM employees
 			WHERE deleted_at IS NULL
-			ORDER BY last_name, first_name
-			LIMIT $1 OFFSET $2
 		`
 
-		return r.db.SelectContext(ctx, &employees, query, perPage, offset)
+		var err error
+		page, err = database.SelectPage[*Employee](ctx, r.db, &employeeListSchema, q, query)
+		return err
 	})
 
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	return employees, total, nil
+	return page, nil
 }
 
 // Update updates an employee
@@ -625,7 +641,7 @@ func (r *EmployeeRepository) ListFiles(ctx context.Context, employeeID string) (
 
 	// Execute query with tenant's search_path
 	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
-		query := `SELECT * FROM employee_files WHERE employee_id = $1 ORDER BY uploaded_at DESC`
+		query := `SELECT ` + employeeFileColumns + ` FROM employee_files WHERE employee_id = $1 ORDER BY uploaded_at DESC`
 		return r.db.SelectContext(ctx, &files, query, employeeID)
 	})
 
@@ -636,6 +652,33 @@ func (r *EmployeeRepository) ListFiles(ctx context.Context, employeeID string) (
 	return files, nil
 }
 
+// GetFile gets a file record of an employee
+// TENANT-ISOLATED: Queries only the tenant's schema
+func (r *EmployeeRepository) GetFile(ctx context.Context, employeeID, id string) (*EmployeeFile, error) {
+	// Extract tenant schema from context
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err // Fail-fast if tenant context missing
+	}
+
+	var file EmployeeFile
+
+	// Execute query with tenant's search_path
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `SELECT ` + employeeFileColumns + ` FROM employee_files WHERE id = $1 AND employee_id = $2`
+		return r.db.GetContext(ctx, &file, query, id, employeeID)
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFound("file")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &file, nil
+}
+
 // CreateFile creates a file record
 // TENANT-ISOLATED: Inserts only into the tenant's schema
 func (r *EmployeeRepository) CreateFile(ctx context.Context, file *EmployeeFile) error {
@@ -652,18 +695,59 @@ func (r *EmployeeRepository) CreateFile(ctx context.Context, file *EmployeeFile)
 	// Execute query with tenant's search_path
 	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
 		query := `
-			INSERT INTO employee_files (id, tenant_id, employee_id, name, file_type, file_path, file_size, mime_type, category, uploaded_by)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			INSERT INTO employee_files (id, tenant_id, employee_id, name, file_type, file_path, file_size, mime_type, category, sha256, scan_status, uploaded_by)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 			RETURNING uploaded_at
 		`
 
 		return r.db.QueryRowxContext(ctx, query,
 			file.ID, tenantID, file.EmployeeID, file.Name, file.FileType, file.FilePath,
-			file.FileSize, file.MimeType, file.Category, file.UploadedBy,
+			file.FileSize, file.MimeType, file.Category, file.SHA256, file.ScanStatus, file.UploadedBy,
 		).Scan(&file.UploadedAt)
 	})
 }
 
+// ListPendingScans lists employee files still awaiting a malware scan, oldest first
+// TENANT-ISOLATED: Queries only the tenant's schema
+func (r *EmployeeRepository) ListPendingScans(ctx context.Context, limit int) ([]storage.PendingScan, error) {
+	// Extract tenant schema from context
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err // Fail-fast if tenant context missing
+	}
+
+	var files []storage.PendingScan
+
+	// Execute query with tenant's search_path
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `SELECT id, file_path FROM employee_files WHERE scan_status = $1 ORDER BY uploaded_at LIMIT $2`
+		return r.db.SelectContext(ctx, &files, query, scan.StatusPending, limit)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// SetScanStatus records the malware scan outcome of an employee file
+// TENANT-ISOLATED: Updates only in the tenant's schema
+func (r *EmployeeRepository) SetScanStatus(ctx context.Context, id, status string) error {
+	// Extract tenant schema from context
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err // Fail-fast if tenant context missing
+	}
+
+	// Execute query with tenant's search_path
+	return r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		query := `UPDATE employee_files SET scan_status = $2, scanned_at = NOW() WHERE id = $1`
+		_, err := r.db.ExecContext(ctx, query, id, status)
+		return err
+	})
+}
+
 // DeleteFile deletes a file record
 // TENANT-ISOLATED: Deletes only from the tenant's schema
 func (r *EmployeeRepository) DeleteFile(ctx context.Context, id string) error {
@@ -746,7 +830,7 @@ func (r *EmployeeRepository) UpdateUserID(ctx context.Context, employeeID, userI
 				return err
 			}
 			if existingUserID != nil {
-				return errors.Conflict("employee already has user credentials")
+				return errors.Conflict("errors.staff.credentials_exist")
 			}
 			return errors.NotFound("employee")
 		}
@@ -815,3 +899,42 @@ func (r *EmployeeRepository) ClearUserID(ctx context.Context, employeeID string)
 		return nil
 	})
 }
+
+// employeeSearchDocument must match the idx_employees_search_trgm index expression
+const employeeSearchDocument = `public.search_normalize(first_name || ' ' || last_name || ' ' || COALESCE(employee_number, ''))`
+
+// Search finds employees by name or personnel number with typo tolerance.
+// An exact personnel number match ranks first.
+// TENANT-ISOLATED: Searches only the tenant's employees via RLS
+func (r *EmployeeRepository) Search(ctx context.Context, query string, limit int) ([]*search.Result, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*search.Result
+	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		if err := search.PrepareTx(ctx, r.db); err != nil {
+			return err
+		}
+
+		q := `
+			SELECT id, first_name || ' ' || last_name AS title,
+			       NULLIF(concat_ws(' · ', employee_number, job_title), '') AS subtitle,
+			       CASE WHEN employee_number = $2 THEN 1.0
+			            ELSE word_similarity($1, ` + employeeSearchDocument + `) END AS score
+			FROM employees
+			WHERE deleted_at IS NULL
+			  AND (employee_number = $2 OR $1 <% ` + employeeSearchDocument + `)
+			ORDER BY score DESC, last_name, first_name
+			LIMIT $3
+		`
+		return r.db.SelectContext(ctx, &results, q, search.Normalize(query), query, limit)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return search.Tag(results, search.TypeEmployee), nil
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/medflow/medflow-backend/internal/staff/repository"
+	"github.com/medflow/medflow-backend/pkg/database"
 	"github.com/medflow/medflow-backend/pkg/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -127,11 +128,97 @@ func TestEmployeeRepository_List(t *testing.T) {
 	}
 
 	// Test listing
-	results, total, err := repo.List(tenantCtx, 1, 10)
+	results, err := repo.List(tenantCtx, &database.ListQuery{Page: 1, Limit: 10})
 	require.NoError(t, err)
 
-	assert.Equal(t, int64(3), total)
-	assert.Len(t, results, 3)
+	assert.Equal(t, int64(3), results.Total)
+	assert.Len(t, results.Items, 3)
+}
+
+func TestEmployeeRepository_ListCursor(t *testing.T) {
+	ctx := context.Background()
+
+	tenant := suite.SetupStaffTenant(t, ctx, "test-list-cursor")
+	repo := repository.NewEmployeeRepository(suite.DB)
+	tenantCtx := suite.TenantContext(tenant)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	for _, name := range []string{"Zimmer", "Albers", "Meier", "Becker", "Otto"} {
+		err := repo.Create(tenantCtx, &repository.Employee{
+			FirstName: "Test", LastName: name, EmploymentType: "full_time", HireDate: now, Status: "active",
+		})
+		require.NoError(t, err)
+	}
+	err := repo.Create(tenantCtx, &repository.Employee{
+		FirstName: "Test", LastName: "Keller", EmploymentType: "minijob", HireDate: now, Status: "active",
+	})
+	require.NoError(t, err)
+
+	// Walk all pages by cursor, sorted descending by last name
+	q := &database.ListQuery{
+		Filters: []database.Filter{{Field: "employment_type", Value: "full_time"}},
+		Sort:    []database.SortField{{Field: "last_name", Desc: true}},
+		Limit:   2,
+	}
+	var names []string
+	for i := 0; i < 5; i++ {
+		page, err := repo.List(tenantCtx, q)
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), page.Total)
+		for _, emp := range page.Items {
+			names = append(names, emp.LastName)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		q.Cursor = page.NextCursor
+	}
+	assert.Equal(t, []string{"Zimmer", "Otto", "Meier", "Becker", "Albers"}, names)
+
+	// A cursor is bound to the sort order it was issued for
+	q.Sort = []database.SortField{{Field: "last_name"}}
+	_, err = repo.List(tenantCtx, q)
+	assert.Error(t, err)
+
+	// Fields outside the whitelist are rejected
+	_, err = repo.List(tenantCtx, &database.ListQuery{Sort: []database.SortField{{Field: "notes"}}})
+	assert.Error(t, err)
+}
+
+func TestEmployeeRepository_Search(t *testing.T) {
+	ctx := context.Background()
+
+	tenant := suite.SetupStaffTenant(t, ctx, "test-search-employees")
+	repo := repository.NewEmployeeRepository(suite.DB)
+	tenantCtx := suite.TenantContext(tenant)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	number := "P-0042"
+	for _, emp := range []*repository.Employee{
+		{FirstName: "Jürgen", LastName: "Müller", EmploymentType: "full_time", HireDate: now, Status: "active"},
+		{FirstName: "Anna", LastName: "Weiß", EmployeeNumber: &number, EmploymentType: "part_time", HireDate: now, Status: "active"},
+	} {
+		require.NoError(t, repo.Create(tenantCtx, emp))
+	}
+
+	// Umlaut-free spelling finds the umlaut name
+	results, err := repo.Search(tenantCtx, "juergen mueller", 10)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "Jürgen Müller", results[0].Title)
+
+	// ß folds to ss
+	results, err = repo.Search(tenantCtx, "weiss", 10)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "Anna Weiß", results[0].Title)
+
+	// Exact personnel number
+	results, err = repo.Search(tenantCtx, "P-0042", 10)
+	require.NoError(t, err)
+	require.NotEmpty(t, results)
+	assert.Equal(t, "Anna Weiß", results[0].Title)
+	assert.Equal(t, 1.0, results[0].Score)
 }
 
 func TestEmployeeRepository_Update(t *testing.T) {
@@ -247,18 +334,18 @@ func TestEmployeeRepository_TenantIsolation(t *testing.T) {
 	require.NoError(t, err)
 
 	// Verify tenant 1 only sees its employee
-	results1, total1, err := repo.List(ctx1, 1, 10)
+	results1, err := repo.List(ctx1, &database.ListQuery{Page: 1, Limit: 10})
 	require.NoError(t, err)
-	assert.Equal(t, int64(1), total1)
-	assert.Len(t, results1, 1)
-	assert.Equal(t, "Tenant1", results1[0].FirstName)
+	assert.Equal(t, int64(1), results1.Total)
+	assert.Len(t, results1.Items, 1)
+	assert.Equal(t, "Tenant1", results1.Items[0].FirstName)
 
 	// Verify tenant 2 only sees its employee
-	results2, total2, err := repo.List(ctx2, 1, 10)
+	results2, err := repo.List(ctx2, &database.ListQuery{Page: 1, Limit: 10})
 	require.NoError(t, err)
-	assert.Equal(t, int64(1), total2)
-	assert.Len(t, results2, 1)
-	assert.Equal(t, "Tenant2", results2[0].FirstName)
+	assert.Equal(t, int64(1), results2.Total)
+	assert.Len(t, results2.Items, 1)
+	assert.Equal(t, "Tenant2", results2.Items[0].FirstName)
 
 	// Verify tenant 1 cannot access tenant 2's employee
 	notFound, err := repo.GetByID(ctx1, emp2.ID)
@@ -61,8 +61,8 @@ type ShiftListParams struct {
 	EndDate    *time.Time
 	Status     *string
 	ShiftType  *string
-	Page       int
-	PerPage    int
+	// Query holds generic filters, sort and paging; nil returns the first page
+	Query *database.ListQuery
 }
 
 // ShiftRepository handles shift persistence
@@ -312,64 +312,63 @@ func (r *ShiftRepository) GetAssignmentByID(ctx context.Context, id string) (*Sh
 	return &shift, nil
 }
 
+// shiftListSchema whitelists the filter and sort fields of GET /shifts
+var shiftListSchema = database.ListSchema{
+	Fields: map[string]database.Field{
+		"employee_id":       {Column: "sa.employee_id", Type: database.FieldUUID, Filter: true},
+		"shift_template_id": {Column: "sa.shift_template_id", Type: database.FieldUUID, Filter: true},
+		"shift_date":        {Column: "sa.shift_date", Type: database.FieldDate, Filter: true, Sort: true},
+		"start_time":        {Column: "sa.start_time", Type: database.FieldTimeOfDay, Filter: true, Sort: true},
+		"shift_type":        {Column: "sa.shift_type", Type: database.FieldText, Filter: true, Sort: true},
+		"status":            {Column: "sa.status", Type: database.FieldText, Filter: true, Sort: true},
+		"has_conflict":      {Column: "sa.has_conflict", Type: database.FieldBool, Filter: true},
+		"created_at":        {Column: "sa.created_at", Type: database.FieldTime, Filter: true, Sort: true},
+	},
+	DefaultSort: []string{"shift_date", "start_time"},
+	IDColumn:    "sa.id",
+}
+
 // ListAssignments lists shift assignments with filters
 // TENANT-ISOLATED: Queries only the tenant's schema
-func (r *ShiftRepository) ListAssignments(ctx context.Context, params ShiftListParams) ([]*ShiftAssignment, int64, error) {
+func (r *ShiftRepository) ListAssignments(ctx context.Context, params ShiftListParams) (*database.Page[*ShiftAssignment], error) {
 	tenantID, err := tenant.TenantID(ctx)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	var total int64
-	var shifts []*ShiftAssignment
+	q := params.Query
+	if q == nil {
+		q = &database.ListQuery{}
+	}
+
+	var page *database.Page[*ShiftAssignment]
 
 	err = r.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
-		// Build WHERE clause
+		// Typed parameters become fixed conditions
 		whereClause := "WHERE sa.deleted_at IS NULL"
 		args := []interface{}{}
-		argNum := 1
 
 		if params.EmployeeID != nil {
-			whereClause += fmt.Sprintf(" AND sa.employee_id = $%d", argNum)
 			args = append(args, *params.EmployeeID)
-			argNum++
+			whereClause += fmt.Sprintf(" AND sa.employee_id = $%d", len(args))
 		}
 		if params.StartDate != nil {
-			whereClause += fmt.Sprintf(" AND sa.shift_date >= $%d", argNum)
 			args = append(args, *params.StartDate)
-			argNum++
+			whereClause += fmt.Sprintf(" AND sa.shift_date >= $%d", len(args))
 		}
 		if params.EndDate != nil {
-			whereClause += fmt.Sprintf(" AND sa.shift_date <= $%d", argNum)
 			args = append(args, *params.EndDate)
-			argNum++
+			whereClause += fmt.Sprintf(" AND sa.shift_date <= $%d", len(args))
 		}
 		if params.Status != nil {
-			whereClause += fmt.Sprintf(" AND sa.status = $%d", argNum)
 			args = append(args, *params.Status)
-			argNum++
+			whereClause += fmt.Sprintf(" AND sa.status = $%d", len(args))
 		}
 		if params.ShiftType != nil {
-			whereClause += fmt.Sprintf(" AND sa.shift_type = $%d", argNum)
 			args = append(args, *params.ShiftType)
-			argNum++
-		}
-
-		// Count total
-		countQuery := "SELECT COUNT(*) FROM shift_assignments sa " + whereClause
-		if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
-			return err
+			whereClause += fmt.Sprintf(" AND sa.shift_type = $%d", len(args))
 		}
 
-		// Get paginated shifts
-		if params.PerPage <= 0 {
-			params.PerPage = 50
-		}
-		if params.Page <= 0 {
-			params.Page = 1
-		}
-		offset := (params.Page - 1) * params.PerPage
-
 		query := `
 			SELECT sa.id, sa.employee_id, sa.shift_template_id, sa.shift_date,
 			       sa.start_time::text as start_time, sa.end_time::text as end_time,
@@ -380,19 +379,18 @@ func (r *ShiftRepository) ListAssignments(ctx context.Context, params ShiftListP
 			FROM shift_assignments sa
 			LEFT JOIN employees e ON sa.employee_id = e.id
 			LEFT JOIN shift_templates st ON sa.shift_template_id = st.id
-		` + whereClause + fmt.Sprintf(`
-			ORDER BY sa.shift_date, sa.start_time
-			LIMIT $%d OFFSET $%d`, argNum, argNum+1)
+		` + whereClause
 
-		args = append(args, params.PerPage, offset)
-		return r.db.SelectContext(ctx, &shifts, query, args...)
+		var err error
+		page, err = database.SelectPage[*ShiftAssignment](ctx, r.db, &shiftListSchema, q, query, args...)
+		return err
 	})
 
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	return shifts, total, nil
+	return page, nil
 }
 
 // GetAssignmentsForDate gets all shifts for a specific date
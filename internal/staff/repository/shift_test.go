@@ -246,32 +246,30 @@ func TestShiftRepository_ListAssignments(t *testing.T) {
 	}
 
 	// List all
-	params := repository.ShiftListParams{Page: 1, PerPage: 50}
-	shifts, total, err := repo.ListAssignments(tenantCtx, params)
+	params := repository.ShiftListParams{}
+	shifts, err := repo.ListAssignments(tenantCtx, params)
 	require.NoError(t, err)
-	assert.Equal(t, int64(3), total)
-	assert.Len(t, shifts, 3)
+	assert.Equal(t, int64(3), shifts.Total)
+	assert.Len(t, shifts.Items, 3)
 
 	// List with employee filter
 	empID := emp.ID
 	params.EmployeeID = &empID
-	shifts, total, err = repo.ListAssignments(tenantCtx, params)
+	shifts, err = repo.ListAssignments(tenantCtx, params)
 	require.NoError(t, err)
-	assert.Equal(t, int64(3), total)
+	assert.Equal(t, int64(3), shifts.Total)
 
 	// List with date range filter
 	startDate := baseDate
 	endDate := baseDate.AddDate(0, 0, 1)
 	params = repository.ShiftListParams{
-		Page:      1,
-		PerPage:   50,
 		StartDate: &startDate,
 		EndDate:   &endDate,
 	}
-	shifts, total, err = repo.ListAssignments(tenantCtx, params)
+	shifts, err = repo.ListAssignments(tenantCtx, params)
 	require.NoError(t, err)
-	assert.Equal(t, int64(2), total)
-	assert.Len(t, shifts, 2)
+	assert.Equal(t, int64(2), shifts.Total)
+	assert.Len(t, shifts.Items, 2)
 }
 
 func TestShiftRepository_UpdateAssignment(t *testing.T) {
@@ -509,17 +507,17 @@ func TestShiftRepository_TenantIsolation(t *testing.T) {
 	assert.Error(t, err, "tenant 2 should not access tenant 1's shift")
 
 	// Tenant 2's list should be empty
-	params := repository.ShiftListParams{Page: 1, PerPage: 50}
-	shifts, total, err := repo.ListAssignments(ctx2, params)
+	params := repository.ShiftListParams{}
+	shifts, err := repo.ListAssignments(ctx2, params)
 	require.NoError(t, err)
-	assert.Equal(t, int64(0), total)
-	assert.Len(t, shifts, 0)
+	assert.Equal(t, int64(0), shifts.Total)
+	assert.Len(t, shifts.Items, 0)
 
 	// Tenant 1 should see its own shift
-	shifts, total, err = repo.ListAssignments(ctx1, params)
+	shifts, err = repo.ListAssignments(ctx1, params)
 	require.NoError(t, err)
-	assert.Equal(t, int64(1), total)
-	assert.Len(t, shifts, 1)
+	assert.Equal(t, int64(1), shifts.Total)
+	assert.Len(t, shifts.Items, 1)
 }
 
 func TestShiftRepository_TemplateTenantIsolation(t *testing.T) {
@@ -142,6 +142,17 @@ func (r *TimeTrackingRepository) CreateEntry(ctx context.Context, entry *TimeEnt
 // GetEntryByID gets a time entry by ID
 // TENANT-ISOLATED: Queries only the tenant's schema
 func (r *TimeTrackingRepository) GetEntryByID(ctx context.Context, id string) (*TimeEntry, error) {
+	return r.getEntry(ctx, id, "")
+}
+
+// LockEntry gets a time entry by ID and locks it for the rest of the
+// transaction, so a change and its audit entry start from the same state
+// TENANT-ISOLATED: Queries only the tenant's schema
+func (r *TimeTrackingRepository) LockEntry(ctx context.Context, id string) (*TimeEntry, error) {
+	return r.getEntry(ctx, id, ` FOR UPDATE OF te`)
+}
+
+func (r *TimeTrackingRepository) getEntry(ctx context.Context, id, lock string) (*TimeEntry, error) {
 	tenantID, err := tenant.TenantID(ctx)
 	if err != nil {
 		return nil, err
@@ -158,7 +169,7 @@ func (r *TimeTrackingRepository) GetEntryByID(ctx context.Context, id string) (*
 			FROM time_entries te
 			LEFT JOIN employees e ON te.employee_id = e.id
 			WHERE te.id = $1 AND te.deleted_at IS NULL
-		`
+		` + lock
 		return r.db.GetContext(ctx, &entry, query, id)
 	})
 
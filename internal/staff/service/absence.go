@@ -7,26 +7,34 @@ import (
 
 	"github.com/medflow/medflow-backend/internal/staff/events"
 	"github.com/medflow/medflow-backend/internal/staff/repository"
+	"github.com/medflow/medflow-backend/pkg/database"
 	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/medflow/medflow-backend/pkg/retention"
 )
 
 // AbsenceService handles absence-related business logic
 type AbsenceService struct {
-	absenceRepo *repository.AbsenceRepository
-	publisher   *events.StaffEventPublisher
-	logger      *logger.Logger
+	absenceRepo    *repository.AbsenceRepository
+	retentionGuard *retention.Guard
+	auditService   *AuditService
+	publisher      *events.StaffEventPublisher
+	logger         *logger.Logger
 }
 
 // NewAbsenceService creates a new absence service
 func NewAbsenceService(
 	absenceRepo *repository.AbsenceRepository,
+	retentionGuard *retention.Guard,
+	auditService *AuditService,
 	publisher *events.StaffEventPublisher,
 	log *logger.Logger,
 ) *AbsenceService {
 	return &AbsenceService{
-		absenceRepo: absenceRepo,
-		publisher:   publisher,
-		logger:      log,
+		absenceRepo:    absenceRepo,
+		retentionGuard: retentionGuard,
+		auditService:   auditService,
+		publisher:      publisher,
+		logger:         log,
 	}
 }
 
@@ -79,7 +87,7 @@ func (s *AbsenceService) GetByID(ctx context.Context, id string) (*repository.Ab
 }
 
 // List lists absences with filters
-func (s *AbsenceService) List(ctx context.Context, params repository.AbsenceListParams) ([]*repository.Absence, int64, error) {
+func (s *AbsenceService) List(ctx context.Context, params repository.AbsenceListParams) (*database.Page[*repository.Absence], error) {
 	return s.absenceRepo.List(ctx, params)
 }
 
@@ -101,7 +109,10 @@ func (s *AbsenceService) Update(ctx context.Context, absence *repository.Absence
 		absence.VacationDaysUsed = &days
 	}
 
-	if err := s.absenceRepo.Update(ctx, absence); err != nil {
+	_, err := s.audited(ctx, absence.ID, "update", func(ctx context.Context) error {
+		return s.absenceRepo.Update(ctx, absence)
+	})
+	if err != nil {
 		return err
 	}
 
@@ -125,16 +136,13 @@ func (s *AbsenceService) Update(ctx context.Context, absence *repository.Absence
 
 // Approve approves an absence request
 func (s *AbsenceService) Approve(ctx context.Context, id, reviewerID string, note *string) error {
-	// Get the absence first
-	absence, err := s.absenceRepo.GetByID(ctx, id)
+	absence, err := s.audited(ctx, id, "approve", func(ctx context.Context) error {
+		return s.absenceRepo.Approve(ctx, id, reviewerID, note)
+	})
 	if err != nil {
 		return err
 	}
 
-	if err := s.absenceRepo.Approve(ctx, id, reviewerID, note); err != nil {
-		return err
-	}
-
 	// Update vacation balance if it's a vacation
 	if absence.AbsenceType == "vacation" {
 		year := absence.StartDate.Year()
@@ -144,7 +152,7 @@ func (s *AbsenceService) Approve(ctx context.Context, id, reviewerID string, not
 	}
 
 	// Publish event
-	s.publisher.PublishAbsenceApproved(ctx, id, reviewerID)
+	s.publisher.PublishAbsenceApproved(ctx, absence, reviewerID)
 
 	s.logger.Info().
 		Str("absence_id", id).
@@ -156,16 +164,13 @@ func (s *AbsenceService) Approve(ctx context.Context, id, reviewerID string, not
 
 // Reject rejects an absence request
 func (s *AbsenceService) Reject(ctx context.Context, id, reviewerID, reason string, note *string) error {
-	// Get the absence first
-	absence, err := s.absenceRepo.GetByID(ctx, id)
+	absence, err := s.audited(ctx, id, "reject", func(ctx context.Context) error {
+		return s.absenceRepo.Reject(ctx, id, reviewerID, reason, note)
+	})
 	if err != nil {
 		return err
 	}
 
-	if err := s.absenceRepo.Reject(ctx, id, reviewerID, reason, note); err != nil {
-		return err
-	}
-
 	// Update vacation balance if it's a vacation
 	if absence.AbsenceType == "vacation" {
 		year := absence.StartDate.Year()
@@ -175,7 +180,7 @@ func (s *AbsenceService) Reject(ctx context.Context, id, reviewerID, reason stri
 	}
 
 	// Publish event
-	s.publisher.PublishAbsenceRejected(ctx, id, reviewerID, reason)
+	s.publisher.PublishAbsenceRejected(ctx, absence, reviewerID, reason)
 
 	s.logger.Info().
 		Str("absence_id", id).
@@ -188,13 +193,26 @@ func (s *AbsenceService) Reject(ctx context.Context, id, reviewerID, reason stri
 
 // Delete deletes an absence
 func (s *AbsenceService) Delete(ctx context.Context, id string) error {
+	if err := s.retentionGuard.Check(ctx, id, RetentionAbsence); err != nil {
+		return err
+	}
 	// Get the absence first
 	absence, err := s.absenceRepo.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
 
-	if err := s.absenceRepo.Delete(ctx, id); err != nil {
+	err = s.auditService.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.absenceRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.auditService.RecordDelete(ctx, AuditAbsence, id, absence.EmployeeID, map[string]interface{}{
+			"absence_type": absence.AbsenceType,
+			"start_date":   absence.StartDate.Format("2006-01-02"),
+			"end_date":     absence.EndDate.Format("2006-01-02"),
+		})
+	})
+	if err != nil {
 		return err
 	}
 
@@ -216,6 +234,31 @@ func (s *AbsenceService) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+// audited runs change in one transaction with an audit entry of the fields
+// it changed on the absence, and returns the absence as it was before
+func (s *AbsenceService) audited(ctx context.Context, id, action string, change func(context.Context) error) (*repository.Absence, error) {
+	var before *repository.Absence
+	err := s.auditService.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		before, err = s.absenceRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := change(ctx); err != nil {
+			return err
+		}
+		after, err := s.absenceRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.auditService.RecordChanges(ctx, AuditAbsence, id, before.EmployeeID, action, Diff(before, after), nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return before, nil
+}
+
 // ============================================================================
 // VACATION BALANCE
 // ============================================================================
@@ -278,12 +321,21 @@ func (s *AbsenceService) RecalculateBalance(ctx context.Context, employeeID stri
 		StartDate:   &startOfYear,
 		EndDate:     &endOfYear,
 		AbsenceType: strPtr("vacation"),
-		PerPage:     1000, // Get all
+		Query:       &database.ListQuery{Limit: database.MaxListLimit, SkipCount: true},
 	}
 
-	absences, _, err := s.absenceRepo.List(ctx, params)
-	if err != nil {
-		return err
+	// Follow the cursor to get all of the year's absences
+	var absences []*repository.Absence
+	for {
+		page, err := s.absenceRepo.List(ctx, params)
+		if err != nil {
+			return err
+		}
+		absences = append(absences, page.Items...)
+		if page.NextCursor == "" {
+			break
+		}
+		params.Query.Cursor = page.NextCursor
 	}
 
 	var taken, planned, pending float64
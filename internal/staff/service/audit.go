@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/medflow/medflow-backend/internal/staff/repository"
+	"github.com/medflow/medflow-backend/pkg/auditchain"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/httputil"
+	"github.com/medflow/medflow-backend/pkg/logger"
+)
+
+// Entity types of the staff audit trail
+const (
+	AuditEmployee           = "employee"
+	AuditEmployeeFinancials = "employee_financials"
+	AuditTimeEntry          = "time_entry"
+	AuditAbsence            = "absence"
+	AuditCorrectionRequest  = "correction_request"
+)
+
+// auditIgnoredFields are columns left out of field diffs: they change with
+// every write or only mirror joined data
+var auditIgnoredFields = map[string]bool{
+	"created_at":    true,
+	"updated_at":    true,
+	"employee_name": true,
+}
+
+// auditMaskedFields are columns whose values are masked in field diffs; the
+// audit trail is readable by managers who may not see bank and tax data
+var auditMaskedFields = map[string]bool{
+	"iban":   true,
+	"tax_id": true,
+}
+
+// AuditService records the staff audit trail: field-level before/after
+// diffs of working time records, absences and personnel data with the
+// acting user and IP, as required for labour inspections (ArbZG §16,
+// ECJ C-55/18). Entries are written in the transaction of the change they
+// record, so a change is never committed without its entry:
+//
+//	err := s.auditService.Transaction(ctx, func(ctx context.Context) error {
+//	    if err := s.repo.UpdateEntry(ctx, entry); err != nil {
+//	        return err
+//	    }
+//	    return s.auditService.RecordUpdate(ctx, AuditTimeEntry, entry.ID, entry.EmployeeID, before, entry, nil)
+//	})
+type AuditService struct {
+	repo   *repository.AuditTrailRepository
+	logger *logger.Logger
+}
+
+// NewAuditService creates a new audit service
+func NewAuditService(repo *repository.AuditTrailRepository, log *logger.Logger) *AuditService {
+	return &AuditService{
+		repo:   repo,
+		logger: log,
+	}
+}
+
+// Transaction runs fn in one tenant transaction; changes and audit entries
+// made with the context fn receives commit or roll back together
+func (s *AuditService) Transaction(ctx context.Context, fn func(context.Context) error) error {
+	return s.repo.Transaction(ctx, fn)
+}
+
+// RecordCreate records a created record with its initial field values
+func (s *AuditService) RecordCreate(ctx context.Context, entityType, entityID, employeeID string, created interface{}) error {
+	return s.record(ctx, entityType, entityID, employeeID, "create", Diff(nil, created), nil)
+}
+
+// RecordUpdate records the fields that differ between before and after.
+// Nothing is recorded when no field changed and there is no metadata.
+func (s *AuditService) RecordUpdate(ctx context.Context, entityType, entityID, employeeID string, before, after interface{}, metadata map[string]interface{}) error {
+	return s.RecordChanges(ctx, entityType, entityID, employeeID, "update", Diff(before, after), metadata)
+}
+
+// RecordChanges records an action with precomputed field changes in the
+// {"field": {"old": x, "new": y}} form of Diff
+func (s *AuditService) RecordChanges(ctx context.Context, entityType, entityID, employeeID, action string, changes, metadata map[string]interface{}) error {
+	if len(changes) == 0 && metadata == nil {
+		return nil
+	}
+	return s.record(ctx, entityType, entityID, employeeID, action, changes, metadata)
+}
+
+// RecordDelete records a deleted record
+func (s *AuditService) RecordDelete(ctx context.Context, entityType, entityID, employeeID string, metadata map[string]interface{}) error {
+	return s.record(ctx, entityType, entityID, employeeID, "delete", nil, metadata)
+}
+
+// List lists audit entries filtered, sorted and paginated by q
+func (s *AuditService) List(ctx context.Context, q *database.ListQuery) (*database.Page[*repository.AuditEntry], error) {
+	return s.repo.List(ctx, q)
+}
+
+// Export returns the audit entries in a time range in chain order
+func (s *AuditService) Export(ctx context.Context, from, to *time.Time) ([]*repository.AuditEntry, error) {
+	return s.repo.Export(ctx, from, to)
+}
+
+// Verify checks the tenant's staff audit trail hash chain for changed,
+// deleted or reordered entries
+func (s *AuditService) Verify(ctx context.Context) (*auditchain.Result, error) {
+	result, err := s.repo.Verify(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !result.Valid {
+		s.logger.Warn().
+			Str("chain", result.Chain).
+			Int64("sequence", result.Break.Sequence).
+			Str("entry_id", result.Break.EntryID).
+			Str("reason", result.Break.Reason).
+			Msg("staff audit trail hash chain broken")
+	}
+
+	return result, nil
+}
+
+// record constructs an AuditEntry with the acting user and IP from the
+// request context and persists it
+func (s *AuditService) record(ctx context.Context, entityType, entityID, employeeID, action string, fieldChanges, metadata map[string]interface{}) error {
+	entry := &repository.AuditEntry{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+	}
+	if employeeID != "" {
+		entry.EmployeeID = &employeeID
+	}
+	if userID := httputil.GetUserID(ctx); userID != "" {
+		entry.PerformedBy = &userID
+	}
+	if email := httputil.GetUserEmail(ctx); email != "" {
+		entry.PerformedByName = &email
+	}
+	if ip := httputil.GetClientIP(ctx); ip != "" {
+		entry.IPAddress = &ip
+	}
+
+	if fieldChanges != nil {
+		changesJSON, err := json.Marshal(fieldChanges)
+		if err != nil {
+			return err
+		}
+		changesStr := string(changesJSON)
+		entry.FieldChanges = &changesStr
+	}
+	if metadata != nil {
+		metadataJSON, err := json.Marshal(metadata)
+		if err != nil {
+			return err
+		}
+		metaStr := string(metadataJSON)
+		entry.Metadata = &metaStr
+	}
+
+	if err := s.repo.Create(ctx, entry); err != nil {
+		s.logger.Error().Err(err).
+			Str("entity_type", entityType).
+			Str("entity_id", entityID).
+			Str("action", action).
+			Msg("failed to create audit entry")
+		return err
+	}
+
+	return nil
+}
+
+// Diff compares two records of the same struct type field by field and
+// returns the changed columns as {"column": {"old": x, "new": y}}, keyed by
+// db tag. A nil before (or after) diffs against an absent record. Fields
+// without a db tag, timestamps in auditIgnoredFields and unexported fields
+// are skipped; values in auditMaskedFields are masked.
+func Diff(before, after interface{}) map[string]interface{} {
+	b, a := structValue(before), structValue(after)
+	t := a
+	if !t.IsValid() {
+		t = b
+	}
+	if !t.IsValid() {
+		return nil
+	}
+
+	changes := map[string]interface{}{}
+	typ := t.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		column := f.Tag.Get("db")
+		if !f.IsExported() || column == "" || column == "-" || auditIgnoredFields[column] {
+			continue
+		}
+
+		oldValue, newValue := fieldValue(b, i), fieldValue(a, i)
+		if reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+		if auditMaskedFields[column] {
+			oldValue, newValue = maskValue(oldValue), maskValue(newValue)
+		}
+		changes[column] = map[string]interface{}{"old": oldValue, "new": newValue}
+	}
+	return changes
+}
+
+// structValue returns the struct v points to, or an invalid value for nil
+func structValue(v interface{}) reflect.Value {
+	rv := reflect.ValueOf(v)
+	for rv.IsValid() && rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return reflect.Value{}
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return reflect.Value{}
+	}
+	return rv
+}
+
+// fieldValue returns field i of s dereferenced, or nil for a nil pointer or
+// an absent record. Times are normalized to UTC so that equal instants in
+// different locations compare equal.
+func fieldValue(s reflect.Value, i int) interface{} {
+	if !s.IsValid() {
+		return nil
+	}
+	f := s.Field(i)
+	if f.Kind() == reflect.Ptr {
+		if f.IsNil() {
+			return nil
+		}
+		f = f.Elem()
+	}
+	v := f.Interface()
+	if t, ok := v.(time.Time); ok {
+		return t.UTC()
+	}
+	return v
+}
+
+// maskValue masks all but the last four characters of a string value
+func maskValue(v interface{}) interface{} {
+	s, ok := v.(string)
+	if !ok || s == "" {
+		return v
+	}
+	if len(s) <= 4 {
+		return strings.Repeat("*", len(s))
+	}
+	return strings.Repeat("*", len(s)-4) + s[len(s)-4:]
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/medflow/medflow-backend/internal/staff/events"
 	"github.com/medflow/medflow-backend/internal/staff/repository"
 	"github.com/medflow/medflow-backend/pkg/errors"
 	"github.com/medflow/medflow-backend/pkg/logger"
@@ -15,6 +16,8 @@ type ComplianceService struct {
 	complianceRepo   *repository.ComplianceRepository
 	timeTrackingRepo *repository.TimeTrackingRepository
 	shiftRepo        *repository.ShiftRepository
+	auditService     *AuditService
+	publisher        *events.StaffEventPublisher
 	logger           *logger.Logger
 }
 
@@ -23,16 +26,26 @@ func NewComplianceService(
 	complianceRepo *repository.ComplianceRepository,
 	timeTrackingRepo *repository.TimeTrackingRepository,
 	shiftRepo *repository.ShiftRepository,
+	auditService *AuditService,
 	log *logger.Logger,
 ) *ComplianceService {
 	return &ComplianceService{
 		complianceRepo:   complianceRepo,
 		timeTrackingRepo: timeTrackingRepo,
 		shiftRepo:        shiftRepo,
+		auditService:     auditService,
 		logger:           log,
 	}
 }
 
+// SetPublisher sets the publisher that announces raised and dismissed
+// compliance alerts and correction request decisions, so they reach the
+// notification routing and inboxes of the user service. Without one alerts
+// only show on the dashboard.
+func (s *ComplianceService) SetPublisher(publisher *events.StaffEventPublisher) {
+	s.publisher = publisher
+}
+
 // ============================================================================
 // BREAK VALIDATION (ArbZG §4)
 // ============================================================================
@@ -62,7 +75,7 @@ func (s *ComplianceService) CheckBreakEndAllowed(ctx context.Context, employeeID
 		return nil, err
 	}
 	if entry == nil {
-		return nil, errors.BadRequest("not clocked in")
+		return nil, errors.BadRequest("errors.time.not_clocked_in")
 	}
 
 	// Get active break
@@ -71,7 +84,7 @@ func (s *ComplianceService) CheckBreakEndAllowed(ctx context.Context, employeeID
 		return nil, err
 	}
 	if activeBreak == nil {
-		return nil, errors.BadRequest("not on break")
+		return nil, errors.BadRequest("errors.time.not_on_break")
 	}
 
 	// Calculate current work duration (excluding current break)
@@ -171,7 +184,7 @@ func (s *ComplianceService) CheckClockOutCompliance(ctx context.Context, employe
 		return nil, err
 	}
 	if entry == nil {
-		return nil, errors.BadRequest("not clocked in")
+		return nil, errors.BadRequest("errors.time.not_clocked_in")
 	}
 
 	now := time.Now()
@@ -418,12 +431,7 @@ func (s *ComplianceService) CheckActiveEmployeeCompliance(ctx context.Context, e
 			ActionLabel: ptrString("Pause einleiten"),
 		}
 
-		// Deactivate old alerts of same type first
-		s.complianceRepo.DeactivateAlertsForEmployee(ctx, employeeID, repository.AlertNoBreakWarning)
-
-		if err := s.complianceRepo.CreateAlert(ctx, alert); err != nil {
-			s.logger.Error().Err(err).Msg("failed to create no-break alert")
-		}
+		s.raiseAlert(ctx, entry.ID, alert)
 	}
 
 	// Alert 2: Break too long
@@ -438,11 +446,7 @@ func (s *ComplianceService) CheckActiveEmployeeCompliance(ctx context.Context, e
 				ActionLabel: ptrString("Pause beenden"),
 			}
 
-			s.complianceRepo.DeactivateAlertsForEmployee(ctx, employeeID, repository.AlertBreakTooLong)
-
-			if err := s.complianceRepo.CreateAlert(ctx, alert); err != nil {
-				s.logger.Error().Err(err).Msg("failed to create break-too-long alert")
-			}
+			s.raiseAlert(ctx, entry.ID, alert)
 		}
 	}
 
@@ -457,11 +461,7 @@ func (s *ComplianceService) CheckActiveEmployeeCompliance(ctx context.Context, e
 			ActionLabel: ptrString("Ausstempeln"),
 		}
 
-		s.complianceRepo.DeactivateAlertsForEmployee(ctx, employeeID, repository.AlertMaxHoursApproaching)
-
-		if err := s.complianceRepo.CreateAlert(ctx, alert); err != nil {
-			s.logger.Error().Err(err).Msg("failed to create approaching-max alert")
-		}
+		s.raiseAlert(ctx, entry.ID, alert)
 	}
 
 	// Alert 4: Max hours exceeded
@@ -474,16 +474,24 @@ func (s *ComplianceService) CheckActiveEmployeeCompliance(ctx context.Context, e
 			ActionLabel: ptrString("Sofort ausstempeln"),
 		}
 
-		s.complianceRepo.DeactivateAlertsForEmployee(ctx, employeeID, repository.AlertMaxHoursExceeded)
-
-		if err := s.complianceRepo.CreateAlert(ctx, alert); err != nil {
-			s.logger.Error().Err(err).Msg("failed to create max-exceeded alert")
-		}
+		s.raiseAlert(ctx, entry.ID, alert)
 	}
 
 	return nil
 }
 
+// raiseAlert replaces the employee's active alert of the same type with alert
+// and publishes it
+func (s *ComplianceService) raiseAlert(ctx context.Context, timeEntryID string, alert *repository.ComplianceAlert) {
+	s.complianceRepo.DeactivateAlertsForEmployee(ctx, alert.EmployeeID, alert.AlertType)
+
+	if err := s.complianceRepo.CreateAlert(ctx, alert); err != nil {
+		s.logger.Error().Err(err).Str("alert_type", alert.AlertType).Msg("failed to create compliance alert")
+		return
+	}
+	s.publisher.PublishComplianceAlertCreated(ctx, alert, timeEntryID)
+}
+
 // CheckAllActiveEmployees runs compliance checks for all clocked-in employees
 func (s *ComplianceService) CheckAllActiveEmployees(ctx context.Context) error {
 	statuses, err := s.timeTrackingRepo.GetAllEmployeeStatuses(ctx)
@@ -515,22 +523,22 @@ func (s *ComplianceService) GetSettings(ctx context.Context) (*repository.Compli
 func (s *ComplianceService) UpdateSettings(ctx context.Context, settings *repository.ComplianceSettings) error {
 	// Enforce legal minimums - cannot be less restrictive than law
 	if settings.MinBreak6hMinutes < 30 {
-		return errors.BadRequest("Mindestpause für 6h kann nicht unter 30 Minuten liegen (ArbZG §4)")
+		return errors.BadRequest("errors.compliance.min_break_6h")
 	}
 	if settings.MinBreak9hMinutes < 45 {
-		return errors.BadRequest("Mindestpause für 9h kann nicht unter 45 Minuten liegen (ArbZG §4)")
+		return errors.BadRequest("errors.compliance.min_break_9h")
 	}
 	if settings.MinBreakSegmentMinutes < 15 {
-		return errors.BadRequest("Pausenabschnitte können nicht unter 15 Minuten liegen (ArbZG §4)")
+		return errors.BadRequest("errors.compliance.min_break_segment")
 	}
 	if settings.MaxDailyHours > 10 {
-		return errors.BadRequest("Tägliche Höchstarbeitszeit darf 10 Stunden nicht überschreiten (ArbZG §3)")
+		return errors.BadRequest("errors.compliance.max_daily_hours")
 	}
 	if settings.MaxWeeklyHours > 48 {
-		return errors.BadRequest("Wöchentliche Höchstarbeitszeit darf 48 Stunden nicht überschreiten (ArbZG §3)")
+		return errors.BadRequest("errors.compliance.max_weekly_hours")
 	}
 	if settings.MinRestBetweenShiftsHours < 10 {
-		return errors.BadRequest("Ruhezeit zwischen Schichten muss mindestens 10 Stunden betragen (ArbZG §5)")
+		return errors.BadRequest("errors.compliance.min_rest")
 	}
 
 	return s.complianceRepo.UpdateSettings(ctx, settings)
@@ -543,7 +551,11 @@ func (s *ComplianceService) GetActiveAlerts(ctx context.Context) ([]*repository.
 
 // DismissAlert dismisses an alert
 func (s *ComplianceService) DismissAlert(ctx context.Context, alertID, userID string) error {
-	return s.complianceRepo.DismissAlert(ctx, alertID, userID)
+	if err := s.complianceRepo.DismissAlert(ctx, alertID, userID); err != nil {
+		return err
+	}
+	s.publisher.PublishComplianceAlertDismissed(ctx, alertID, userID)
+	return nil
 }
 
 // GetViolations gets violations with filters
@@ -652,13 +664,13 @@ func ptrString(s string) *string {
 func (s *ComplianceService) CreateCorrectionRequest(ctx context.Context, req *repository.CorrectionRequest) error {
 	// Validate required fields
 	if req.EmployeeID == "" {
-		return errors.BadRequest("employee_id is required")
+		return errors.BadRequest("validation.required", map[string]string{"field": "employee_id"})
 	}
 	if req.RequestType == "" {
-		return errors.BadRequest("request_type is required")
+		return errors.BadRequest("validation.required", map[string]string{"field": "request_type"})
 	}
 	if req.Reason == "" {
-		return errors.BadRequest("reason is required for audit purposes")
+		return errors.BadRequest("errors.compliance.audit_reason_required")
 	}
 
 	return s.complianceRepo.CreateCorrectionRequest(ctx, req)
@@ -679,17 +691,44 @@ func (s *ComplianceService) ListEmployeeCorrectionRequests(ctx context.Context,
 	return s.complianceRepo.ListCorrectionRequestsByEmployee(ctx, employeeID)
 }
 
-// ApproveCorrectionRequest approves a correction request and applies the changes
+// ApproveCorrectionRequest approves a correction request and applies the
+// changes. The corrected time entry, the request status and their audit
+// entries are written in one transaction.
 func (s *ComplianceService) ApproveCorrectionRequest(ctx context.Context, requestID string, reviewerID string) error {
-	// Get the request
-	req, err := s.complianceRepo.GetCorrectionRequestByID(ctx, requestID)
+	var decided *repository.CorrectionRequest
+	err := s.auditService.Transaction(ctx, func(ctx context.Context) error {
+		// Get the request
+		req, err := s.complianceRepo.GetCorrectionRequestByID(ctx, requestID)
+		if err != nil {
+			return err
+		}
+
+		if req.Status != repository.CorrectionStatusPending {
+			return errors.BadRequest("errors.compliance.request_not_pending")
+		}
+
+		if err := s.applyCorrection(ctx, req, reviewerID); err != nil {
+			return err
+		}
+
+		// Update request status
+		if err := s.complianceRepo.UpdateCorrectionRequestStatus(ctx, requestID, repository.CorrectionStatusApproved, reviewerID, nil); err != nil {
+			return err
+		}
+		decided, err = s.recordCorrectionReview(ctx, req)
+		return err
+	})
 	if err != nil {
 		return err
 	}
 
-	if req.Status != repository.CorrectionStatusPending {
-		return errors.BadRequest("request is not pending")
-	}
+	s.publisher.PublishCorrectionDecided(ctx, decided, reviewerID)
+	return nil
+}
+
+// applyCorrection applies an approved correction request to the time entries
+func (s *ComplianceService) applyCorrection(ctx context.Context, req *repository.CorrectionRequest, reviewerID string) error {
+	metadata := map[string]interface{}{"correction_request_id": req.ID, "reason": req.Reason}
 
 	// Apply the correction based on type
 	switch req.RequestType {
@@ -699,12 +738,14 @@ func (s *ComplianceService) ApproveCorrectionRequest(ctx context.Context, reques
 			if err != nil {
 				return err
 			}
+			before := *entry
 			entry.ClockIn = *req.RequestedClockIn
 			entry.IsManualEntry = true
 			entry.UpdatedBy = &reviewerID
 			if err := s.timeTrackingRepo.UpdateEntry(ctx, entry); err != nil {
 				return err
 			}
+			return s.auditService.RecordUpdate(ctx, AuditTimeEntry, entry.ID, entry.EmployeeID, &before, entry, metadata)
 		}
 
 	case repository.CorrectionTypeClockOut:
@@ -713,6 +754,7 @@ func (s *ComplianceService) ApproveCorrectionRequest(ctx context.Context, reques
 			if err != nil {
 				return err
 			}
+			before := *entry
 			entry.ClockOut = req.RequestedClockOut
 			entry.IsManualEntry = true
 			entry.UpdatedBy = &reviewerID
@@ -735,6 +777,7 @@ func (s *ComplianceService) ApproveCorrectionRequest(ctx context.Context, reques
 			if err := s.timeTrackingRepo.UpdateEntry(ctx, entry); err != nil {
 				return err
 			}
+			return s.auditService.RecordUpdate(ctx, AuditTimeEntry, entry.ID, entry.EmployeeID, &before, entry, metadata)
 		}
 
 	case repository.CorrectionTypeMissedEntry:
@@ -754,6 +797,7 @@ func (s *ComplianceService) ApproveCorrectionRequest(ctx context.Context, reques
 			if err := s.timeTrackingRepo.CreateEntry(ctx, entry); err != nil {
 				return err
 			}
+			return s.auditService.RecordChanges(ctx, AuditTimeEntry, entry.ID, entry.EmployeeID, "create", Diff(nil, entry), metadata)
 		}
 
 	case repository.CorrectionTypeDeleteEntry:
@@ -761,24 +805,54 @@ func (s *ComplianceService) ApproveCorrectionRequest(ctx context.Context, reques
 			if err := s.timeTrackingRepo.SoftDeleteEntry(ctx, *req.TimeEntryID); err != nil {
 				return err
 			}
+			return s.auditService.RecordDelete(ctx, AuditTimeEntry, *req.TimeEntryID, req.EmployeeID, metadata)
 		}
 	}
 
-	// Update request status
-	return s.complianceRepo.UpdateCorrectionRequestStatus(ctx, requestID, repository.CorrectionStatusApproved, reviewerID, nil)
+	return nil
 }
 
 // RejectCorrectionRequest rejects a correction request
 func (s *ComplianceService) RejectCorrectionRequest(ctx context.Context, requestID string, reviewerID string, reason string) error {
-	// Get the request
-	req, err := s.complianceRepo.GetCorrectionRequestByID(ctx, requestID)
+	var decided *repository.CorrectionRequest
+	err := s.auditService.Transaction(ctx, func(ctx context.Context) error {
+		// Get the request
+		req, err := s.complianceRepo.GetCorrectionRequestByID(ctx, requestID)
+		if err != nil {
+			return err
+		}
+
+		if req.Status != repository.CorrectionStatusPending {
+			return errors.BadRequest("errors.compliance.request_not_pending")
+		}
+
+		if err := s.complianceRepo.UpdateCorrectionRequestStatus(ctx, requestID, repository.CorrectionStatusRejected, reviewerID, &reason); err != nil {
+			return err
+		}
+		decided, err = s.recordCorrectionReview(ctx, req)
+		return err
+	})
 	if err != nil {
 		return err
 	}
 
-	if req.Status != repository.CorrectionStatusPending {
-		return errors.BadRequest("request is not pending")
-	}
+	s.publisher.PublishCorrectionDecided(ctx, decided, reviewerID)
+	return nil
+}
 
-	return s.complianceRepo.UpdateCorrectionRequestStatus(ctx, requestID, repository.CorrectionStatusRejected, reviewerID, &reason)
+// recordCorrectionReview records the review of a correction request as the
+// diff of the request before and after its status update, and returns the
+// reviewed request
+func (s *ComplianceService) recordCorrectionReview(ctx context.Context, before *repository.CorrectionRequest) (*repository.CorrectionRequest, error) {
+	after, err := s.complianceRepo.GetCorrectionRequestByID(ctx, before.ID)
+	if err != nil {
+		return nil, err
+	}
+	err = s.auditService.RecordChanges(ctx, AuditCorrectionRequest, before.ID, before.EmployeeID, "review", Diff(before, after), map[string]interface{}{
+		"request_type": before.RequestType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return after, nil
 }
@@ -0,0 +1,48 @@
+package service
+
+import "github.com/medflow/medflow-backend/pkg/retention"
+
+// terminationDate is the retention date of the records of an employee: the
+// personnel file is kept for the policy's period after the employee left
+const terminationDate = "(SELECT e.termination_date FROM staff.employees e WHERE e.id = t.employee_id)"
+
+// Staff records subject to retention policies. Deletes of these records are
+// checked by the retention guard; RetentionPurgeRecords are purged by the
+// staff.retention_purge job.
+var (
+	RetentionTimeEntry = retention.Record{
+		EntityType: "working_time", Table: "staff.time_entries", DateColumn: "t.entry_date",
+	}
+	RetentionAbsence = retention.Record{
+		EntityType: "absence", Table: "staff.absences", DateColumn: "t.end_date",
+	}
+	RetentionShiftAssignment = retention.Record{
+		EntityType: "shift_assignment", Table: "staff.shift_assignments", DateColumn: "t.shift_date",
+	}
+
+	// RetentionEmployee is anonymized, never deleted: time entries,
+	// absences and compliance records with their own retention reference it
+	RetentionEmployee = retention.Record{
+		EntityType: "personnel_file", Table: "staff.employees", DateColumn: "t.termination_date",
+		Anonymize: `first_name = 'Anonymisiert', last_name = 'Anonymisiert', date_of_birth = NULL,
+			gender = NULL, nationality = NULL, birth_place = NULL, email = NULL, phone = NULL,
+			mobile = NULL, notes = NULL, avatar_url = NULL`,
+		AnonymizeOnly: true,
+	}
+	// RetentionEmployeeFile is not purged: the file in object storage would
+	// outlive its record
+	RetentionEmployeeFile = retention.Record{
+		EntityType: "personnel_file", Table: "staff.employee_files", DateColumn: terminationDate,
+	}
+
+	// RetentionPurgeRecords are the records the purge job may delete or anonymize
+	RetentionPurgeRecords = []retention.Record{
+		RetentionTimeEntry,
+		RetentionAbsence,
+		{EntityType: "personnel_file", Table: "staff.employee_addresses", DateColumn: terminationDate},
+		{EntityType: "personnel_file", Table: "staff.employee_contacts", DateColumn: terminationDate},
+		{EntityType: "personnel_file", Table: "staff.employee_financials", DateColumn: terminationDate},
+		{EntityType: "personnel_file", Table: "staff.employee_social_insurance", DateColumn: terminationDate},
+		RetentionEmployee,
+	}
+)
@@ -6,26 +6,31 @@ import (
 
 	"github.com/medflow/medflow-backend/internal/staff/events"
 	"github.com/medflow/medflow-backend/internal/staff/repository"
+	"github.com/medflow/medflow-backend/pkg/database"
 	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/medflow/medflow-backend/pkg/retention"
 )
 
 // ShiftService handles shift-related business logic
 type ShiftService struct {
-	shiftRepo *repository.ShiftRepository
-	publisher *events.StaffEventPublisher
-	logger    *logger.Logger
+	shiftRepo      *repository.ShiftRepository
+	retentionGuard *retention.Guard
+	publisher      *events.StaffEventPublisher
+	logger         *logger.Logger
 }
 
 // NewShiftService creates a new shift service
 func NewShiftService(
 	shiftRepo *repository.ShiftRepository,
+	retentionGuard *retention.Guard,
 	publisher *events.StaffEventPublisher,
 	log *logger.Logger,
 ) *ShiftService {
 	return &ShiftService{
-		shiftRepo: shiftRepo,
-		publisher: publisher,
-		logger:    log,
+		shiftRepo:      shiftRepo,
+		retentionGuard: retentionGuard,
+		publisher:      publisher,
+		logger:         log,
 	}
 }
 
@@ -131,7 +136,7 @@ func (s *ShiftService) GetAssignmentByID(ctx context.Context, id string) (*repos
 }
 
 // ListAssignments lists shift assignments with filters
-func (s *ShiftService) ListAssignments(ctx context.Context, params repository.ShiftListParams) ([]*repository.ShiftAssignment, int64, error) {
+func (s *ShiftService) ListAssignments(ctx context.Context, params repository.ShiftListParams) (*database.Page[*repository.ShiftAssignment], error) {
 	return s.shiftRepo.ListAssignments(ctx, params)
 }
 
@@ -187,6 +192,9 @@ func (s *ShiftService) UpdateAssignment(ctx context.Context, shift *repository.S
 
 // DeleteAssignment deletes a shift assignment
 func (s *ShiftService) DeleteAssignment(ctx context.Context, id string) error {
+	if err := s.retentionGuard.Check(ctx, id, RetentionShiftAssignment); err != nil {
+		return err
+	}
 	// Get the shift first for event publishing
 	shift, err := s.shiftRepo.GetAssignmentByID(ctx, id)
 	if err != nil {
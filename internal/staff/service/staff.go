@@ -8,32 +8,41 @@ import (
 	"github.com/medflow/medflow-backend/internal/staff/events"
 	"github.com/medflow/medflow-backend/internal/staff/repository"
 	"github.com/medflow/medflow-backend/internal/staff/validation"
+	"github.com/medflow/medflow-backend/pkg/database"
 	"github.com/medflow/medflow-backend/pkg/errors"
 	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/medflow/medflow-backend/pkg/retention"
+	"github.com/medflow/medflow-backend/pkg/search"
 	"github.com/medflow/medflow-backend/pkg/tenant"
 )
 
 // StaffService handles staff business logic
 type StaffService struct {
-	employeeRepo *repository.EmployeeRepository
-	publisher    *events.StaffEventPublisher
-	validator    *validation.GermanValidator
-	userClient   *client.UserClient
-	logger       *logger.Logger
+	employeeRepo   *repository.EmployeeRepository
+	retentionGuard *retention.Guard
+	auditService   *AuditService
+	publisher      *events.StaffEventPublisher
+	validator      *validation.GermanValidator
+	userClient     *client.UserClient
+	logger         *logger.Logger
 }
 
 // NewStaffService creates a new staff service
 func NewStaffService(
 	employeeRepo *repository.EmployeeRepository,
+	retentionGuard *retention.Guard,
+	auditService *AuditService,
 	publisher *events.StaffEventPublisher,
 	validator *validation.GermanValidator,
 	log *logger.Logger,
 ) *StaffService {
 	return &StaffService{
-		employeeRepo: employeeRepo,
-		publisher:    publisher,
-		validator:    validator,
-		logger:       log,
+		employeeRepo:   employeeRepo,
+		retentionGuard: retentionGuard,
+		auditService:   auditService,
+		publisher:      publisher,
+		validator:      validator,
+		logger:         log,
 	}
 }
 
@@ -82,14 +91,45 @@ func (s *StaffService) GetByUserID(ctx context.Context, userID string) (*reposit
 	return s.employeeRepo.GetByUserID(ctx, userID)
 }
 
-// List lists employees with pagination
-func (s *StaffService) List(ctx context.Context, page, perPage int) ([]*repository.Employee, int64, error) {
-	return s.employeeRepo.List(ctx, page, perPage)
+// List lists employees filtered, sorted and paginated by q
+func (s *StaffService) List(ctx context.Context, q *database.ListQuery) (*database.Page[*repository.Employee], error) {
+	return s.employeeRepo.List(ctx, q)
+}
+
+// Search finds employees by name or personnel number
+func (s *StaffService) Search(ctx context.Context, req *search.Request) (*search.Response, error) {
+	var sets [][]*search.Result
+	if req.Wants(search.TypeEmployee) {
+		employees, err := s.employeeRepo.Search(ctx, req.Query, req.Limit)
+		if err != nil {
+			return nil, err
+		}
+		sets = append(sets, employees)
+	}
+
+	return &search.Response{
+		Query:  req.Query,
+		Groups: search.Merge(req.Limit, sets...),
+	}, nil
 }
 
 // Update updates an employee
 func (s *StaffService) Update(ctx context.Context, emp *repository.Employee) error {
-	if err := s.employeeRepo.Update(ctx, emp); err != nil {
+	err := s.auditService.Transaction(ctx, func(ctx context.Context) error {
+		before, err := s.employeeRepo.GetByID(ctx, emp.ID)
+		if err != nil {
+			return err
+		}
+		if err := s.employeeRepo.Update(ctx, emp); err != nil {
+			return err
+		}
+		after, err := s.employeeRepo.GetByID(ctx, emp.ID)
+		if err != nil {
+			return err
+		}
+		return s.auditService.RecordUpdate(ctx, AuditEmployee, emp.ID, emp.ID, before, after, nil)
+	})
+	if err != nil {
 		return err
 	}
 
@@ -101,7 +141,16 @@ func (s *StaffService) Update(ctx context.Context, emp *repository.Employee) err
 
 // Delete soft deletes an employee
 func (s *StaffService) Delete(ctx context.Context, id string) error {
-	if err := s.employeeRepo.SoftDelete(ctx, id); err != nil {
+	if err := s.retentionGuard.Check(ctx, id, RetentionEmployee); err != nil {
+		return err
+	}
+	err := s.auditService.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.employeeRepo.SoftDelete(ctx, id); err != nil {
+			return err
+		}
+		return s.auditService.RecordDelete(ctx, AuditEmployee, id, id, nil)
+	})
+	if err != nil {
 		return err
 	}
 
@@ -154,7 +203,20 @@ func (s *StaffService) SaveFinancials(ctx context.Context, fin *repository.Emplo
 		}
 	}
 
-	return s.employeeRepo.SaveFinancials(ctx, fin)
+	return s.auditService.Transaction(ctx, func(ctx context.Context) error {
+		before, err := s.employeeRepo.GetFinancials(ctx, fin.EmployeeID)
+		if err != nil {
+			return err
+		}
+		if err := s.employeeRepo.SaveFinancials(ctx, fin); err != nil {
+			return err
+		}
+		after, err := s.employeeRepo.GetFinancials(ctx, fin.EmployeeID)
+		if err != nil {
+			return err
+		}
+		return s.auditService.RecordUpdate(ctx, AuditEmployeeFinancials, after.ID, fin.EmployeeID, before, after, nil)
+	})
 }
 
 // ListFiles lists files for an employee
@@ -162,6 +224,11 @@ func (s *StaffService) ListFiles(ctx context.Context, employeeID string) ([]*rep
 	return s.employeeRepo.ListFiles(ctx, employeeID)
 }
 
+// GetFile gets a file record of an employee
+func (s *StaffService) GetFile(ctx context.Context, employeeID, id string) (*repository.EmployeeFile, error) {
+	return s.employeeRepo.GetFile(ctx, employeeID, id)
+}
+
 // CreateFile creates a file record
 func (s *StaffService) CreateFile(ctx context.Context, file *repository.EmployeeFile) error {
 	return s.employeeRepo.CreateFile(ctx, file)
@@ -169,6 +236,9 @@ func (s *StaffService) CreateFile(ctx context.Context, file *repository.Employee
 
 // DeleteFile deletes a file record
 func (s *StaffService) DeleteFile(ctx context.Context, id string) error {
+	if err := s.retentionGuard.Check(ctx, id, RetentionEmployeeFile); err != nil {
+		return err
+	}
 	return s.employeeRepo.DeleteFile(ctx, id)
 }
 
@@ -222,25 +292,25 @@ func (s *StaffService) AddCredentialsToEmployee(
 
 	// 2. Verify employee has email and no existing user_id
 	if emp.Email == nil || *emp.Email == "" {
-		return nil, errors.BadRequest("employee must have an email to create credentials")
+		return nil, errors.BadRequest("errors.staff.email_required_for_credentials")
 	}
 
 	if emp.UserID != nil {
-		return nil, errors.Conflict("employee already has user credentials")
+		return nil, errors.Conflict("errors.staff.credentials_exist")
 	}
 
 	// 3. Get actor's role level for hierarchy validation
 	actor, err := s.userClient.GetUserByID(ctx, actorID)
 	if err != nil {
 		s.logger.Error().Err(err).Str("actor_id", actorID).Msg("failed to get actor user")
-		return nil, errors.Forbidden("unable to verify actor permissions")
+		return nil, errors.Forbidden("errors.user.permission_check_failed")
 	}
 
 	// 4. Get requested role's level
 	requestedRole, err := s.userClient.GetRole(ctx, roleName)
 	if err != nil {
 		s.logger.Error().Err(err).Str("role", roleName).Msg("failed to get role")
-		return nil, errors.BadRequest("invalid role: " + roleName)
+		return nil, errors.BadRequest("errors.user.unknown_role", map[string]string{"role": roleName})
 	}
 
 	// 5. Validate role hierarchy: actor can only assign roles at their level or lower
@@ -251,7 +321,7 @@ func (s *StaffService) AddCredentialsToEmployee(
 			Str("requested_role", roleName).
 			Int("requested_level", requestedRole.Level).
 			Msg("role hierarchy violation: actor cannot assign higher-level role")
-		return nil, errors.Forbidden("cannot assign role with higher permission level")
+		return nil, errors.Forbidden("errors.user.role_level_too_high")
 	}
 
 	// 6. Create user via UserClient (tenant headers forwarded automatically)
@@ -334,7 +404,7 @@ func (s *StaffService) RemoveCredentialsFromEmployee(
 
 	// 2. Verify employee has credentials
 	if emp.UserID == nil {
-		return errors.BadRequest("employee does not have user credentials")
+		return errors.BadRequest("errors.staff.no_credentials")
 	}
 
 	userID := *emp.UserID
@@ -9,28 +9,35 @@ import (
 	"github.com/medflow/medflow-backend/internal/staff/repository"
 	"github.com/medflow/medflow-backend/pkg/errors"
 	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/medflow/medflow-backend/pkg/retention"
 )
 
 // TimeTrackingService handles time tracking business logic
 type TimeTrackingService struct {
-	repo       *repository.TimeTrackingRepository
-	compliance *ComplianceService
-	publisher  *events.StaffEventPublisher
-	logger     *logger.Logger
+	repo           *repository.TimeTrackingRepository
+	compliance     *ComplianceService
+	retentionGuard *retention.Guard
+	auditService   *AuditService
+	publisher      *events.StaffEventPublisher
+	logger         *logger.Logger
 }
 
 // NewTimeTrackingService creates a new time tracking service
 func NewTimeTrackingService(
 	repo *repository.TimeTrackingRepository,
 	compliance *ComplianceService,
+	retentionGuard *retention.Guard,
+	auditService *AuditService,
 	publisher *events.StaffEventPublisher,
 	log *logger.Logger,
 ) *TimeTrackingService {
 	return &TimeTrackingService{
-		repo:       repo,
-		compliance: compliance,
-		publisher:  publisher,
-		logger:     log,
+		repo:           repo,
+		compliance:     compliance,
+		retentionGuard: retentionGuard,
+		auditService:   auditService,
+		publisher:      publisher,
+		logger:         log,
 	}
 }
 
@@ -64,7 +71,7 @@ func (s *TimeTrackingService) ClockIn(ctx context.Context, employeeID string) (*
 		return nil, err
 	}
 	if activeEntry != nil {
-		return nil, errors.BadRequest("already clocked in")
+		return nil, errors.BadRequest("errors.time.already_clocked_in")
 	}
 
 	// Create new time entry
@@ -97,7 +104,7 @@ func (s *TimeTrackingService) ClockOut(ctx context.Context, employeeID string) (
 		return nil, err
 	}
 	if entry == nil {
-		return nil, errors.BadRequest("not clocked in")
+		return nil, errors.BadRequest("errors.time.not_clocked_in")
 	}
 
 	// End any active break first
@@ -162,7 +169,7 @@ func (s *TimeTrackingService) StartBreak(ctx context.Context, employeeID string)
 		return nil, err
 	}
 	if entry == nil {
-		return nil, errors.BadRequest("not clocked in")
+		return nil, errors.BadRequest("errors.time.not_clocked_in")
 	}
 
 	// Check if already on break
@@ -171,7 +178,7 @@ func (s *TimeTrackingService) StartBreak(ctx context.Context, employeeID string)
 		return nil, err
 	}
 	if activeBreak != nil {
-		return nil, errors.BadRequest("already on break")
+		return nil, errors.BadRequest("errors.time.already_on_break")
 	}
 
 	// Create new break
@@ -209,7 +216,7 @@ func (s *TimeTrackingService) EndBreak(ctx context.Context, employeeID string) (
 		return nil, err
 	}
 	if entry == nil {
-		return nil, errors.BadRequest("not clocked in")
+		return nil, errors.BadRequest("errors.time.not_clocked_in")
 	}
 
 	// Get active break
@@ -218,7 +225,7 @@ func (s *TimeTrackingService) EndBreak(ctx context.Context, employeeID string) (
 		return nil, err
 	}
 	if activeBreak == nil {
-		return nil, errors.BadRequest("not on break")
+		return nil, errors.BadRequest("errors.time.not_on_break")
 	}
 
 	// End the break
@@ -259,7 +266,7 @@ func (s *TimeTrackingService) ManualClockIn(ctx context.Context, employeeID stri
 
 	// Reject clock-in times in the future
 	if clockInTime.After(time.Now()) {
-		return nil, errors.BadRequest("clock in time cannot be in the future")
+		return nil, errors.BadRequest("errors.time.clock_in_future")
 	}
 
 	// Check if already has an active (uncompleted) entry
@@ -269,7 +276,7 @@ func (s *TimeTrackingService) ManualClockIn(ctx context.Context, employeeID stri
 		return nil, err
 	}
 	if activeEntry != nil {
-		return nil, errors.BadRequest("employee already has an active time entry")
+		return nil, errors.BadRequest("errors.time.active_entry_exists")
 	}
 
 	// Create new time entry
@@ -281,7 +288,13 @@ func (s *TimeTrackingService) ManualClockIn(ctx context.Context, employeeID stri
 		CreatedBy:     &userID,
 	}
 
-	if err := s.repo.CreateEntry(ctx, entry); err != nil {
+	err = s.auditService.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateEntry(ctx, entry); err != nil {
+			return err
+		}
+		return s.auditService.RecordCreate(ctx, AuditTimeEntry, entry.ID, entry.EmployeeID, entry)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -298,38 +311,54 @@ func (s *TimeTrackingService) ManualClockIn(ctx context.Context, employeeID stri
 func (s *TimeTrackingService) ManualClockOut(ctx context.Context, employeeID string, clockOutTime time.Time, userID string) (*repository.TimeEntry, error) {
 	// Get entry for the date
 	entryDate := time.Date(clockOutTime.Year(), clockOutTime.Month(), clockOutTime.Day(), 0, 0, 0, 0, clockOutTime.Location())
-	entry, err := s.repo.GetEntryByEmployeeAndDate(ctx, employeeID, entryDate)
+	found, err := s.repo.GetEntryByEmployeeAndDate(ctx, employeeID, entryDate)
 	if err != nil {
 		return nil, err
 	}
-	if entry == nil {
+	if found == nil {
 		return nil, errors.NotFound("time_entry")
 	}
 
-	// Validate clock out time is after clock in
-	if clockOutTime.Before(entry.ClockIn) {
-		return nil, errors.BadRequest("clock out time must be after clock in time")
-	}
+	// The entry is re-read under lock, so the audited before state is the
+	// one the update replaces
+	var entry *repository.TimeEntry
+	err = s.auditService.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		entry, err = s.repo.LockEntry(ctx, found.ID)
+		if err != nil {
+			return err
+		}
 
-	// Update entry
-	entry.ClockOut = &clockOutTime
-	entry.IsManualEntry = true
-	entry.UpdatedBy = &userID
+		// Validate clock out time is after clock in
+		if clockOutTime.Before(entry.ClockIn) {
+			return errors.BadRequest("errors.time.clock_out_before_clock_in")
+		}
+		before := *entry
 
-	// Calculate totals
-	totalBreakMinutes, err := s.repo.CalculateTotalBreakMinutes(ctx, entry.ID)
-	if err != nil {
-		return nil, err
-	}
-	entry.TotalBreakMinutes = totalBreakMinutes
+		// Update entry
+		entry.ClockOut = &clockOutTime
+		entry.IsManualEntry = true
+		entry.UpdatedBy = &userID
 
-	totalMinutes := int(entry.ClockOut.Sub(entry.ClockIn).Minutes())
-	entry.TotalWorkMinutes = totalMinutes - totalBreakMinutes
-	if entry.TotalWorkMinutes < 0 {
-		entry.TotalWorkMinutes = 0
-	}
+		// Calculate totals
+		totalBreakMinutes, err := s.repo.CalculateTotalBreakMinutes(ctx, entry.ID)
+		if err != nil {
+			return err
+		}
+		entry.TotalBreakMinutes = totalBreakMinutes
 
-	if err := s.repo.UpdateEntry(ctx, entry); err != nil {
+		totalMinutes := int(entry.ClockOut.Sub(entry.ClockIn).Minutes())
+		entry.TotalWorkMinutes = totalMinutes - totalBreakMinutes
+		if entry.TotalWorkMinutes < 0 {
+			entry.TotalWorkMinutes = 0
+		}
+
+		if err := s.repo.UpdateEntry(ctx, entry); err != nil {
+			return err
+		}
+		return s.auditService.RecordUpdate(ctx, AuditTimeEntry, entry.ID, entry.EmployeeID, &before, entry, nil)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -352,7 +381,7 @@ func (s *TimeTrackingService) CreateCorrection(ctx context.Context, corr *reposi
 
 	// Validate reason is provided
 	if corr.Reason == "" {
-		return errors.BadRequest("reason is required for corrections")
+		return errors.BadRequest("errors.correction_reason_required")
 	}
 
 	return s.repo.CreateCorrection(ctx, corr)
@@ -476,47 +505,57 @@ func (s *TimeTrackingService) GetEntryByID(ctx context.Context, id string) (*rep
 // UpdateEntry updates a time entry (for partial updates)
 // Supports "clock_out_clear": true to clear the clock-out time
 func (s *TimeTrackingService) UpdateEntry(ctx context.Context, id string, updates map[string]interface{}, userID string) (*repository.TimeEntry, error) {
-	// Get existing entry
-	entry, err := s.repo.GetEntryByID(ctx, id)
-	if err != nil {
-		return nil, err
-	}
-
-	// Apply updates
-	if clockIn, ok := updates["clock_in"].(time.Time); ok {
-		entry.ClockIn = clockIn
-	}
-	if _, ok := updates["clock_out_clear"]; ok {
-		// Explicitly clear clock-out: set to nil, zero out totals
-		entry.ClockOut = nil
-		entry.TotalWorkMinutes = 0
-		entry.TotalBreakMinutes = 0
-	} else if clockOut, ok := updates["clock_out"].(time.Time); ok {
-		entry.ClockOut = &clockOut
-	}
-	if notes, ok := updates["notes"].(string); ok {
-		entry.Notes = &notes
-	}
-
-	entry.IsManualEntry = true
-	entry.UpdatedBy = &userID
-
-	// Recalculate if clock_out is set
-	if entry.ClockOut != nil {
-		totalBreakMinutes, err := s.repo.CalculateTotalBreakMinutes(ctx, entry.ID)
+	// The entry is read under lock, so the audited before state is the one
+	// the update replaces
+	var entry *repository.TimeEntry
+	err := s.auditService.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		entry, err = s.repo.LockEntry(ctx, id)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		entry.TotalBreakMinutes = totalBreakMinutes
+		before := *entry
 
-		totalMinutes := int(entry.ClockOut.Sub(entry.ClockIn).Minutes())
-		entry.TotalWorkMinutes = totalMinutes - totalBreakMinutes
-		if entry.TotalWorkMinutes < 0 {
+		// Apply updates
+		if clockIn, ok := updates["clock_in"].(time.Time); ok {
+			entry.ClockIn = clockIn
+		}
+		if _, ok := updates["clock_out_clear"]; ok {
+			// Explicitly clear clock-out: set to nil, zero out totals
+			entry.ClockOut = nil
 			entry.TotalWorkMinutes = 0
+			entry.TotalBreakMinutes = 0
+		} else if clockOut, ok := updates["clock_out"].(time.Time); ok {
+			entry.ClockOut = &clockOut
+		}
+		if notes, ok := updates["notes"].(string); ok {
+			entry.Notes = &notes
 		}
-	}
 
-	if err := s.repo.UpdateEntry(ctx, entry); err != nil {
+		entry.IsManualEntry = true
+		entry.UpdatedBy = &userID
+
+		// Recalculate if clock_out is set
+		if entry.ClockOut != nil {
+			totalBreakMinutes, err := s.repo.CalculateTotalBreakMinutes(ctx, entry.ID)
+			if err != nil {
+				return err
+			}
+			entry.TotalBreakMinutes = totalBreakMinutes
+
+			totalMinutes := int(entry.ClockOut.Sub(entry.ClockIn).Minutes())
+			entry.TotalWorkMinutes = totalMinutes - totalBreakMinutes
+			if entry.TotalWorkMinutes < 0 {
+				entry.TotalWorkMinutes = 0
+			}
+		}
+
+		if err := s.repo.UpdateEntry(ctx, entry); err != nil {
+			return err
+		}
+		return s.auditService.RecordUpdate(ctx, AuditTimeEntry, entry.ID, entry.EmployeeID, &before, entry, nil)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -538,46 +577,70 @@ type BreakInput struct {
 // ReplaceBreaksForEntry deletes all existing breaks for an entry and creates new ones,
 // then recalculates totals on the parent entry.
 func (s *TimeTrackingService) ReplaceBreaksForEntry(ctx context.Context, entryID string, breaks []BreakInput, userID string) (*repository.TimeEntry, error) {
-	// Get existing entry
-	entry, err := s.repo.GetEntryByID(ctx, entryID)
-	if err != nil {
-		return nil, err
-	}
+	// The breaks are replaced and recorded in one transaction, so a failed
+	// break leaves the previous breaks in place. The entry is read under
+	// lock, so the audited before state is the one the update replaces.
+	var entry *repository.TimeEntry
+	err := s.auditService.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		entry, err = s.repo.LockEntry(ctx, entryID)
+		if err != nil {
+			return err
+		}
+		before := *entry
 
-	// Delete all existing breaks
-	if err := s.repo.DeleteBreaksForEntry(ctx, entryID); err != nil {
-		return nil, fmt.Errorf("failed to delete existing breaks: %w", err)
-	}
+		oldBreaks, err := s.repo.ListBreaksForEntry(ctx, entryID)
+		if err != nil {
+			return err
+		}
 
-	// Create new breaks
-	for _, b := range breaks {
-		brk := &repository.TimeBreak{
-			TimeEntryID: entryID,
-			StartTime:   b.StartTime,
-			EndTime:     b.EndTime,
+		// Delete all existing breaks
+		if err := s.repo.DeleteBreaksForEntry(ctx, entryID); err != nil {
+			return fmt.Errorf("failed to delete existing breaks: %w", err)
 		}
-		if err := s.repo.CreateBreak(ctx, brk); err != nil {
-			return nil, fmt.Errorf("failed to create break: %w", err)
+
+		// Create new breaks
+		newBreaks := make([]*repository.TimeBreak, 0, len(breaks))
+		for _, b := range breaks {
+			brk := &repository.TimeBreak{
+				TimeEntryID: entryID,
+				StartTime:   b.StartTime,
+				EndTime:     b.EndTime,
+			}
+			if err := s.repo.CreateBreak(ctx, brk); err != nil {
+				return fmt.Errorf("failed to create break: %w", err)
+			}
+			newBreaks = append(newBreaks, brk)
 		}
-	}
 
-	// Recalculate totals
-	totalBreakMinutes, err := s.repo.CalculateTotalBreakMinutes(ctx, entryID)
-	if err != nil {
-		return nil, err
-	}
-	entry.TotalBreakMinutes = totalBreakMinutes
+		// Recalculate totals
+		totalBreakMinutes, err := s.repo.CalculateTotalBreakMinutes(ctx, entryID)
+		if err != nil {
+			return err
+		}
+		entry.TotalBreakMinutes = totalBreakMinutes
 
-	if entry.ClockOut != nil {
-		totalMinutes := int(entry.ClockOut.Sub(entry.ClockIn).Minutes())
-		entry.TotalWorkMinutes = totalMinutes - totalBreakMinutes
-		if entry.TotalWorkMinutes < 0 {
-			entry.TotalWorkMinutes = 0
+		if entry.ClockOut != nil {
+			totalMinutes := int(entry.ClockOut.Sub(entry.ClockIn).Minutes())
+			entry.TotalWorkMinutes = totalMinutes - totalBreakMinutes
+			if entry.TotalWorkMinutes < 0 {
+				entry.TotalWorkMinutes = 0
+			}
 		}
-	}
 
-	entry.UpdatedBy = &userID
-	if err := s.repo.UpdateEntry(ctx, entry); err != nil {
+		entry.UpdatedBy = &userID
+		if err := s.repo.UpdateEntry(ctx, entry); err != nil {
+			return err
+		}
+
+		changes := Diff(&before, entry)
+		changes["breaks"] = map[string]interface{}{
+			"old": auditBreaks(oldBreaks),
+			"new": auditBreaks(newBreaks),
+		}
+		return s.auditService.RecordChanges(ctx, AuditTimeEntry, entry.ID, entry.EmployeeID, "replace_breaks", changes, nil)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -589,9 +652,39 @@ func (s *TimeTrackingService) ReplaceBreaksForEntry(ctx context.Context, entryID
 	return entry, nil
 }
 
+// auditBreak is a break as recorded in the audit trail
+type auditBreak struct {
+	StartTime time.Time  `json:"start_time"`
+	EndTime   *time.Time `json:"end_time"`
+}
+
+// auditBreaks returns breaks as recorded in the audit trail
+func auditBreaks(breaks []*repository.TimeBreak) []auditBreak {
+	out := make([]auditBreak, 0, len(breaks))
+	for _, b := range breaks {
+		out = append(out, auditBreak{StartTime: b.StartTime.UTC(), EndTime: b.EndTime})
+	}
+	return out
+}
+
 // DeleteEntry soft deletes a time entry
 func (s *TimeTrackingService) DeleteEntry(ctx context.Context, id string) error {
-	return s.repo.SoftDeleteEntry(ctx, id)
+	if err := s.retentionGuard.Check(ctx, id, RetentionTimeEntry); err != nil {
+		return err
+	}
+	entry, err := s.repo.GetEntryByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.auditService.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.SoftDeleteEntry(ctx, id); err != nil {
+			return err
+		}
+		return s.auditService.RecordDelete(ctx, AuditTimeEntry, id, entry.EmployeeID, map[string]interface{}{
+			"entry_date": entry.EntryDate.Format("2006-01-02"),
+		})
+	})
 }
 
 // GetActiveEntry gets the active time entry for an employee
@@ -43,7 +43,7 @@ func (h *AuditHandler) List(w http.ResponseWriter, r *http.Request) {
 
 	logs, total, err := h.repo.List(r.Context(), filter, page, perPage)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -27,7 +27,7 @@ func NewRoleHandler(repo *repository.RoleRepository, log *logger.Logger) *RoleHa
 func (h *RoleHandler) List(w http.ResponseWriter, r *http.Request) {
 	roles, err := h.repo.List(r.Context())
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -40,7 +40,7 @@ func (h *RoleHandler) Get(w http.ResponseWriter, r *http.Request) {
 
 	role, err := h.repo.GetByID(r.Context(), id)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
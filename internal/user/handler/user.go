@@ -39,7 +39,7 @@ func (h *UserHandler) List(w http.ResponseWriter, r *http.Request) {
 
 	users, total, err := h.service.List(r.Context(), page, perPage)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -67,7 +67,7 @@ func (h *UserHandler) Get(w http.ResponseWriter, r *http.Request) {
 
 	user, err := h.service.GetByID(r.Context(), id)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -90,7 +90,7 @@ func (h *UserHandler) GetUserInternal(w http.ResponseWriter, r *http.Request) {
 
 	user, err := h.service.GetByID(ctx, id)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -113,12 +113,12 @@ func (h *UserHandler) GetUserInternal(w http.ResponseWriter, r *http.Request) {
 func (h *UserHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var req service.CreateUserRequest
 	if err := httputil.DecodeJSON(r, &req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	if err := httputil.Validate(&req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -127,7 +127,7 @@ func (h *UserHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	user, err := h.service.Create(r.Context(), &req, actorID, actorName)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -140,7 +140,7 @@ func (h *UserHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 	var req service.UpdateUserRequest
 	if err := httputil.DecodeJSON(r, &req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -149,7 +149,7 @@ func (h *UserHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 	user, err := h.service.Update(r.Context(), id, &req, actorID, actorName)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -164,7 +164,7 @@ func (h *UserHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	actorName := r.Header.Get("X-User-Email")
 
 	if err := h.service.Delete(r.Context(), id, actorID, actorName); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -179,12 +179,12 @@ func (h *UserHandler) ChangeRole(w http.ResponseWriter, r *http.Request) {
 		Role string `json:"role" validate:"required"`
 	}
 	if err := httputil.DecodeJSON(r, &req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	if err := httputil.Validate(&req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -193,7 +193,7 @@ func (h *UserHandler) ChangeRole(w http.ResponseWriter, r *http.Request) {
 
 	user, err := h.service.ChangeRole(r.Context(), id, req.Role, actorID, actorName)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -206,7 +206,7 @@ func (h *UserHandler) GetPermissions(w http.ResponseWriter, r *http.Request) {
 
 	user, err := h.service.GetByID(r.Context(), id)
 	if err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -228,12 +228,12 @@ func (h *UserHandler) GrantPermission(w http.ResponseWriter, r *http.Request) {
 		Reason     string `json:"reason"`
 	}
 	if err := httputil.DecodeJSON(r, &req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	if err := httputil.Validate(&req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -241,7 +241,7 @@ func (h *UserHandler) GrantPermission(w http.ResponseWriter, r *http.Request) {
 	actorName := r.Header.Get("X-User-Email")
 
 	if err := h.service.GrantPermission(r.Context(), id, req.Permission, req.Reason, actorID, actorName); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -257,12 +257,12 @@ func (h *UserHandler) RevokePermission(w http.ResponseWriter, r *http.Request) {
 		Reason     string `json:"reason"`
 	}
 	if err := httputil.DecodeJSON(r, &req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	if err := httputil.Validate(&req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -270,7 +270,7 @@ func (h *UserHandler) RevokePermission(w http.ResponseWriter, r *http.Request) {
 	actorName := r.Header.Get("X-User-Email")
 
 	if err := h.service.RevokePermission(r.Context(), id, req.Permission, req.Reason, actorID, actorName); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -285,12 +285,12 @@ func (h *UserHandler) GrantAccessGiver(w http.ResponseWriter, r *http.Request) {
 		Scope []string `json:"scope" validate:"required"`
 	}
 	if err := httputil.DecodeJSON(r, &req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
 	if err := httputil.Validate(&req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -298,7 +298,7 @@ func (h *UserHandler) GrantAccessGiver(w http.ResponseWriter, r *http.Request) {
 	actorName := r.Header.Get("X-User-Email")
 
 	if err := h.service.GrantAccessGiver(r.Context(), id, req.Scope, actorID, actorName); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -313,7 +313,7 @@ func (h *UserHandler) RevokeAccessGiver(w http.ResponseWriter, r *http.Request)
 	actorName := r.Header.Get("X-User-Email")
 
 	if err := h.service.RevokeAccessGiver(r.Context(), id, actorID, actorName); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -331,7 +331,7 @@ func (h *UserHandler) ValidateCredentials(w http.ResponseWriter, r *http.Request
 		Password   string `json:"password" validate:"required"`
 	}
 	if err := httputil.DecodeJSON(r, &req); err != nil {
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
@@ -352,7 +352,7 @@ func (h *UserHandler) ValidateCredentials(w http.ResponseWriter, r *http.Request
 		user, err := h.service.ValidateCredentialsInTenant(ctx, req.Identifier, req.Password)
 		if err != nil {
 			h.logger.Debug().Str("identifier", req.Identifier).Msg("credential validation failed")
-			httputil.Error(w, err)
+			httputil.ErrorLocalized(w, r, err)
 			return
 		}
 
@@ -385,7 +385,7 @@ func (h *UserHandler) ValidateCredentials(w http.ResponseWriter, r *http.Request
 	user, tenantInfo, err := h.service.ValidateCredentials(r.Context(), req.Identifier, req.Password)
 	if err != nil {
 		h.logger.Debug().Str("identifier", req.Identifier).Msg("credential validation failed")
-		httputil.Error(w, err)
+		httputil.ErrorLocalized(w, r, err)
 		return
 	}
 
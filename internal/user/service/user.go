@@ -66,19 +66,19 @@ func (s *UserService) Create(ctx context.Context, req *CreateUserRequest, actorI
 	// Check if email already exists
 	existing, _ := s.userRepo.GetByEmail(ctx, req.Email)
 	if existing != nil {
-		return nil, errors.Conflict("email already in use")
+		return nil, errors.Conflict("errors.user.email_in_use")
 	}
 
 	// Get role
 	role, err := s.roleRepo.GetByName(ctx, req.RoleName)
 	if err != nil {
-		return nil, errors.BadRequest("invalid role")
+		return nil, errors.BadRequest("errors.user.invalid_role")
 	}
 
 	// Hash password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
-		return nil, errors.Internal("failed to hash password")
+		return nil, errors.Internal("errors.user.password_hash_failed")
 	}
 
 	user := &domain.User{
@@ -92,14 +92,14 @@ func (s *UserService) Create(ctx context.Context, req *CreateUserRequest, actorI
 	}
 
 	if err := s.userRepo.Create(ctx, user); err != nil {
-		return nil, errors.Internal("failed to create user")
+		return nil, errors.Internal("errors.user.create_failed")
 	}
 
 	// Assign role to user via user_roles junction table
 	if err := s.userRepo.AssignRole(ctx, user.ID, role.ID); err != nil {
 		// Rollback: delete the user if role assignment fails
 		s.userRepo.SoftDelete(ctx, user.ID)
-		return nil, errors.Internal("failed to assign role to user")
+		return nil, errors.Internal("errors.user.role_assign_failed")
 	}
 
 	// Get full user with role
@@ -163,7 +163,7 @@ func (s *UserService) Update(ctx context.Context, id string, req *UpdateUserRequ
 		// Check if email already exists
 		existing, _ := s.userRepo.GetByEmail(ctx, *req.Email)
 		if existing != nil && existing.ID != id {
-			return nil, errors.Conflict("email already in use")
+			return nil, errors.Conflict("errors.user.email_in_use")
 		}
 		oldEmail = user.Email // Save old email for event
 		changes["email"] = map[string]string{"from": user.Email, "to": *req.Email}
@@ -262,19 +262,19 @@ func (s *UserService) ChangeRole(ctx context.Context, userID, roleName, actorID,
 	// Get new role
 	newRole, err := s.roleRepo.GetByName(ctx, roleName)
 	if err != nil {
-		return nil, errors.BadRequest("invalid role")
+		return nil, errors.BadRequest("errors.user.invalid_role")
 	}
 
 	// NEW ROLE CHECK: Can assign this role?
 	actor, err := s.userRepo.GetWithRole(ctx, actorID)
 	if err != nil {
-		return nil, errors.Internal("failed to get actor")
+		return nil, errors.Internal("errors.user.actor_lookup_failed")
 	}
 
 	// Can only assign roles lower than your own (unless admin)
 	if actor.Role != nil && actor.Role.Name != "admin" {
 		if newRole.Level >= actor.Role.Level {
-			return nil, errors.Forbidden("cannot assign role equal or higher than your own")
+			return nil, errors.Forbidden("errors.user.role_too_high")
 		}
 	}
 
@@ -320,20 +320,20 @@ func (s *UserService) GrantPermission(ctx context.Context, userID, permission, r
 	if isAdminOnlyPermission(permission) {
 		actor, err := s.userRepo.GetWithRole(ctx, actorID)
 		if err != nil {
-			return errors.Internal("failed to get actor")
+			return errors.Internal("errors.user.actor_lookup_failed")
 		}
 		if actor.Role == nil || actor.Role.Name != "admin" {
-			return errors.Forbidden("only admin can grant this permission")
+			return errors.Forbidden("errors.user.admin_only_permission")
 		}
 	}
 
 	// PERMISSION CHECK: Can only grant permissions you have
 	hasPermission, err := s.actorHasPermission(ctx, actorID, permission)
 	if err != nil {
-		return errors.Internal("failed to check actor permissions")
+		return errors.Internal("errors.user.permission_check_failed")
 	}
 	if !hasPermission {
-		return errors.Forbidden("cannot grant permission you do not have")
+		return errors.Forbidden("errors.user.permission_not_held")
 	}
 
 	user, err := s.userRepo.GetByID(ctx, userID)
@@ -344,7 +344,7 @@ func (s *UserService) GrantPermission(ctx context.Context, userID, permission, r
 	// Validate permission exists
 	_, err = s.roleRepo.GetPermissionByName(ctx, permission)
 	if err != nil {
-		return errors.BadRequest("invalid permission")
+		return errors.BadRequest("errors.user.invalid_permission")
 	}
 
 	override := &domain.PermissionOverride{
@@ -412,10 +412,10 @@ func (s *UserService) GrantAccessGiver(ctx context.Context, userID string, scope
 	// ADMIN ONLY: Only admin can grant access giver status
 	actor, err := s.userRepo.GetWithRole(ctx, actorID)
 	if err != nil {
-		return errors.Internal("failed to get actor")
+		return errors.Internal("errors.user.actor_lookup_failed")
 	}
 	if actor.Role == nil || actor.Role.Name != "admin" {
-		return errors.Forbidden("only admin can grant access giver status")
+		return errors.Forbidden("errors.user.access_giver_grant_admin_only")
 	}
 
 	// Validate target is a manager (only managers can be access givers)
@@ -424,20 +424,20 @@ func (s *UserService) GrantAccessGiver(ctx context.Context, userID string, scope
 		return err
 	}
 	if target.Role == nil {
-		return errors.Internal("target role not found")
+		return errors.Internal("errors.user.role_info_unavailable")
 	}
 	if !target.Role.IsManager || target.Role.Name == "admin" {
-		return errors.BadRequest("only managers can be access givers")
+		return errors.BadRequest("errors.user.access_giver_managers_only")
 	}
 
 	// Validate scope only includes roles below the target's role
 	for _, roleName := range scope {
 		role, err := s.roleRepo.GetByName(ctx, roleName)
 		if err != nil {
-			return errors.BadRequest("invalid role in scope: " + roleName)
+			return errors.BadRequest("errors.user.invalid_scope_role", map[string]string{"role": roleName})
 		}
 		if role.Level >= target.Role.Level {
-			return errors.BadRequest("invalid scope: can only manage roles lower than own role")
+			return errors.BadRequest("errors.user.invalid_scope")
 		}
 	}
 
@@ -469,10 +469,10 @@ func (s *UserService) RevokeAccessGiver(ctx context.Context, userID, actorID, ac
 	// ADMIN ONLY: Only admin can revoke access giver status
 	actor, err := s.userRepo.GetWithRole(ctx, actorID)
 	if err != nil {
-		return errors.Internal("failed to get actor")
+		return errors.Internal("errors.user.actor_lookup_failed")
 	}
 	if actor.Role == nil || actor.Role.Name != "admin" {
-		return errors.Forbidden("only admin can revoke access giver status")
+		return errors.Forbidden("errors.user.access_giver_revoke_admin_only")
 	}
 
 	user, err := s.userRepo.GetByID(ctx, userID)
@@ -529,17 +529,17 @@ func isAdminOnlyPermission(permission string) bool {
 func (s *UserService) canActorManageTarget(ctx context.Context, actorID, targetID string) error {
 	// Cannot manage self
 	if actorID == targetID {
-		return errors.Forbidden("cannot modify own permissions")
+		return errors.Forbidden("errors.user.cannot_modify_own_permissions")
 	}
 
 	actor, err := s.userRepo.GetWithRole(ctx, actorID)
 	if err != nil {
-		return errors.Internal("failed to get actor")
+		return errors.Internal("errors.user.actor_lookup_failed")
 	}
 
 	target, err := s.userRepo.GetWithRole(ctx, targetID)
 	if err != nil {
-		return errors.NotFound("target user not found")
+		return errors.NotFound("user")
 	}
 
 	// Admin can manage anyone
@@ -549,24 +549,24 @@ func (s *UserService) canActorManageTarget(ctx context.Context, actorID, targetI
 
 	// Check role hierarchy (actor level must be > target level)
 	if actor.Role == nil || target.Role == nil {
-		return errors.Internal("role information not available")
+		return errors.Internal("errors.user.role_info_unavailable")
 	}
 
 	if actor.Role.Level <= target.Role.Level {
-		return errors.Forbidden("insufficient privileges to manage this user")
+		return errors.Forbidden("errors.user.insufficient_privileges")
 	}
 
 	// If actor is access giver (has scope entries), check scope
 	if len(actor.AccessGiverScope) > 0 {
 		if !contains(actor.AccessGiverScope, target.Role.Name) {
-			return errors.Forbidden("user not in access giver scope")
+			return errors.Forbidden("errors.user.outside_access_scope")
 		}
 		return nil
 	}
 
 	// Non-access givers need to be managers to manage users
 	if !actor.Role.IsManager {
-		return errors.Forbidden("not authorized to manage users")
+		return errors.Forbidden("errors.user.not_authorized_to_manage")
 	}
 
 	return nil
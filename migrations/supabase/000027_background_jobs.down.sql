@@ -0,0 +1,3 @@
+-- Rollback migration 000027: Remove background job run history
+
+DROP TABLE IF EXISTS public.job_runs;
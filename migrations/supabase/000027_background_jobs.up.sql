@@ -0,0 +1,36 @@
+-- MedFlow: Background Job Framework
+-- Run history for the shared pkg/jobs scheduler. One row per job execution
+-- per tenant (tenant_id is NULL for global jobs such as session cleanup).
+--
+-- Lives in the public schema (no RLS) because the scheduler fans out across
+-- tenants before any tenant context exists. Admin endpoints always filter
+-- by the caller's tenant_id.
+
+-- ============================================================================
+-- public.job_runs
+-- ============================================================================
+CREATE TABLE public.job_runs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    job_name VARCHAR(100) NOT NULL,
+    tenant_id UUID REFERENCES public.tenants(id),
+
+    trigger VARCHAR(20) NOT NULL DEFAULT 'schedule',
+    status VARCHAR(20) NOT NULL DEFAULT 'running',
+    attempts INTEGER NOT NULL DEFAULT 1,
+    error TEXT,
+    instance_id VARCHAR(100),
+
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMPTZ,
+    duration_ms BIGINT,
+
+    CONSTRAINT job_runs_trigger_valid CHECK (trigger IN ('schedule', 'manual')),
+    CONSTRAINT job_runs_status_valid CHECK (status IN ('running', 'succeeded', 'failed'))
+);
+
+CREATE INDEX idx_job_runs_job_started ON public.job_runs(job_name, started_at DESC);
+CREATE INDEX idx_job_runs_tenant_started ON public.job_runs(tenant_id, started_at DESC);
+CREATE INDEX idx_job_runs_failed ON public.job_runs(job_name, started_at DESC)
+    WHERE status = 'failed';
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON public.job_runs TO medflow_app;
@@ -0,0 +1,8 @@
+-- Rollback migration 000028: Remove search indexes and normalization function
+
+DROP INDEX IF EXISTS staff.idx_employees_search_trgm;
+DROP INDEX IF EXISTS inventory.idx_hygiene_plans_content_fts;
+DROP INDEX IF EXISTS inventory.idx_hygiene_plans_title_trgm;
+DROP INDEX IF EXISTS inventory.idx_inventory_items_search_trgm;
+DROP FUNCTION IF EXISTS public.search_normalize(TEXT);
+-- pg_trgm is left installed; other objects may depend on it
@@ -0,0 +1,64 @@
+-- MedFlow: Full-text and fuzzy search
+-- Backs the per-service search endpoints (inventory items, hygiene plans,
+-- employees) and the gateway fan-out search (see pkg/search).
+--
+-- Matching strategy:
+--   - public.search_normalize() lowercases and folds ä/ö/ü/ß to ae/oe/ue/ss so
+--     "Müller", "Mueller" and "MUELLER" compare equal. pkg/search.Normalize
+--     applies the same folding to the query and must stay in sync.
+--   - pg_trgm GIN indexes on the normalized text give typo tolerance via the
+--     word similarity operator (<%).
+--   - A German full-text index covers long text (hygiene plan content).
+--
+-- Index expressions must match the expressions in the repository Search
+-- queries exactly, otherwise the planner falls back to sequential scans.
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm WITH SCHEMA public;
+
+-- ============================================================================
+-- public.search_normalize
+-- ============================================================================
+CREATE OR REPLACE FUNCTION public.search_normalize(input TEXT)
+RETURNS TEXT
+LANGUAGE sql
+IMMUTABLE
+PARALLEL SAFE
+AS $$
+    SELECT replace(replace(replace(replace(lower(COALESCE(input, '')),
+        'ä', 'ae'), 'ö', 'oe'), 'ü', 'ue'), 'ß', 'ss')
+$$;
+
+GRANT EXECUTE ON FUNCTION public.search_normalize(TEXT) TO medflow_app;
+
+-- ============================================================================
+-- inventory.inventory_items: name, manufacturer, PZN, article number
+-- ============================================================================
+CREATE INDEX IF NOT EXISTS idx_inventory_items_search_trgm
+    ON inventory.inventory_items
+    USING gin (public.search_normalize(
+        name || ' ' || COALESCE(manufacturer, '') || ' ' || COALESCE(pzn, '') || ' ' || COALESCE(article_number, '')
+    ) gin_trgm_ops)
+    WHERE deleted_at IS NULL;
+
+-- ============================================================================
+-- inventory.hygiene_plans: title (fuzzy) and content (full-text)
+-- ============================================================================
+CREATE INDEX IF NOT EXISTS idx_hygiene_plans_title_trgm
+    ON inventory.hygiene_plans
+    USING gin (public.search_normalize(title) gin_trgm_ops)
+    WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_hygiene_plans_content_fts
+    ON inventory.hygiene_plans
+    USING gin (to_tsvector('german', public.search_normalize(title || ' ' || COALESCE(content, ''))))
+    WHERE deleted_at IS NULL;
+
+-- ============================================================================
+-- staff.employees: name and personnel number
+-- ============================================================================
+CREATE INDEX IF NOT EXISTS idx_employees_search_trgm
+    ON staff.employees
+    USING gin (public.search_normalize(
+        first_name || ' ' || last_name || ' ' || COALESCE(employee_number, '')
+    ) gin_trgm_ops)
+    WHERE deleted_at IS NULL;
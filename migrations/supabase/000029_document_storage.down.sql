@@ -0,0 +1,4 @@
+-- Rollback migration 000029: Remove document checksums
+
+ALTER TABLE staff.employee_files DROP COLUMN IF EXISTS sha256;
+ALTER TABLE inventory.item_documents DROP COLUMN IF EXISTS sha256;
@@ -0,0 +1,16 @@
+-- MedFlow: Object storage for uploaded documents
+-- Uploads now go through pkg/storage (local filesystem or an S3-compatible
+-- bucket). file_path holds the tenant-prefixed object key, e.g.
+-- tenants/<tenant-id>/inventory/documents/<item-id>/<uuid>.pdf, and sha256
+-- the hex checksum computed while storing the upload.
+
+ALTER TABLE inventory.item_documents
+    ADD COLUMN IF NOT EXISTS sha256 CHAR(64);
+
+ALTER TABLE staff.employee_files
+    ADD COLUMN IF NOT EXISTS sha256 CHAR(64);
+
+COMMENT ON COLUMN inventory.item_documents.file_path IS 'Object storage key (tenant-prefixed)';
+COMMENT ON COLUMN inventory.item_documents.sha256 IS 'Hex SHA-256 of the stored content';
+COMMENT ON COLUMN staff.employee_files.file_path IS 'Object storage key (tenant-prefixed)';
+COMMENT ON COLUMN staff.employee_files.sha256 IS 'Hex SHA-256 of the stored content';
@@ -0,0 +1,13 @@
+-- Rollback migration 000030: Remove upload scan status
+
+DROP INDEX IF EXISTS staff.idx_employee_files_scan_pending;
+ALTER TABLE staff.employee_files
+    DROP CONSTRAINT IF EXISTS employee_files_scan_status_valid,
+    DROP COLUMN IF EXISTS scanned_at,
+    DROP COLUMN IF EXISTS scan_status;
+
+DROP INDEX IF EXISTS inventory.idx_item_documents_scan_pending;
+ALTER TABLE inventory.item_documents
+    DROP CONSTRAINT IF EXISTS item_documents_scan_status_valid,
+    DROP COLUMN IF EXISTS scanned_at,
+    DROP COLUMN IF EXISTS scan_status;
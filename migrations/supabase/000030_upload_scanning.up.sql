@@ -0,0 +1,26 @@
+-- MedFlow: Malware scanning for uploaded documents
+-- Uploads are scanned before they are stored (pkg/scan). Files stored while
+-- the scanner was unavailable stay 'pending' until the rescan job checks them;
+-- downloads are refused unless scan_status = 'clean'.
+--
+-- Existing rows start as 'pending' so the rescan job checks them too.
+
+ALTER TABLE inventory.item_documents
+    ADD COLUMN IF NOT EXISTS scan_status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    ADD COLUMN IF NOT EXISTS scanned_at TIMESTAMPTZ,
+    ADD CONSTRAINT item_documents_scan_status_valid
+        CHECK (scan_status IN ('pending', 'clean', 'infected', 'error'));
+
+CREATE INDEX IF NOT EXISTS idx_item_documents_scan_pending
+    ON inventory.item_documents (tenant_id, uploaded_at)
+    WHERE scan_status = 'pending' AND deleted_at IS NULL;
+
+ALTER TABLE staff.employee_files
+    ADD COLUMN IF NOT EXISTS scan_status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    ADD COLUMN IF NOT EXISTS scanned_at TIMESTAMPTZ,
+    ADD CONSTRAINT employee_files_scan_status_valid
+        CHECK (scan_status IN ('pending', 'clean', 'infected', 'error'));
+
+CREATE INDEX IF NOT EXISTS idx_employee_files_scan_pending
+    ON staff.employee_files (tenant_id, uploaded_at)
+    WHERE scan_status = 'pending';
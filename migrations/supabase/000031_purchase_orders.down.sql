@@ -0,0 +1,12 @@
+-- Rollback migration 000031: Remove suppliers, purchase orders and goods receipts
+-- The stock_adjustments columns and the widened type constraint stay: the
+-- repository has always written them.
+
+DROP INDEX IF EXISTS inventory.idx_stock_adjustments_reference;
+
+DROP TABLE IF EXISTS inventory.goods_receipt_lines;
+DROP TABLE IF EXISTS inventory.goods_receipts;
+DROP TABLE IF EXISTS inventory.purchase_order_lines;
+DROP TABLE IF EXISTS inventory.purchase_orders;
+DROP TABLE IF EXISTS inventory.supplier_items;
+DROP TABLE IF EXISTS inventory.suppliers;
@@ -0,0 +1,270 @@
+-- MedFlow: Suppliers, purchase orders and goods receipt
+-- Closes the reorder loop: low-stock alerts become draft purchase orders,
+-- orders are sent to suppliers, and goods receipts against an order create
+-- inventory batches (with batch number and expiry) and receipt bookings.
+
+-- ============================================================================
+-- 1. inventory.suppliers
+-- Supplier master data (Lieferanten) with the practice's customer number
+-- ============================================================================
+CREATE TABLE inventory.suppliers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+
+    name VARCHAR(255) NOT NULL,
+    customer_number VARCHAR(100),
+    contact_name VARCHAR(255),
+    email VARCHAR(255),
+    phone VARCHAR(50),
+    fax VARCHAR(50),
+    website TEXT,
+    address TEXT,
+
+    lead_time_days INTEGER NOT NULL DEFAULT 0,
+    min_order_value_cents INTEGER,
+    notes TEXT,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+    created_by UUID,
+    updated_by UUID,
+
+    CONSTRAINT suppliers_lead_time_valid CHECK (lead_time_days >= 0)
+);
+
+ALTER TABLE inventory.suppliers ENABLE ROW LEVEL SECURITY;
+ALTER TABLE inventory.suppliers FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON inventory.suppliers
+    FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+CREATE INDEX idx_suppliers_tenant ON inventory.suppliers(tenant_id);
+CREATE UNIQUE INDEX idx_suppliers_name ON inventory.suppliers(tenant_id, lower(name))
+    WHERE deleted_at IS NULL;
+
+CREATE TRIGGER suppliers_updated_at
+    BEFORE UPDATE ON inventory.suppliers
+    FOR EACH ROW EXECUTE FUNCTION public.update_updated_at();
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON inventory.suppliers TO medflow_app;
+
+-- ============================================================================
+-- 2. inventory.supplier_items
+-- Which supplier delivers an item, under which article number, at what price.
+-- The preferred supplier receives the item's draft purchase orders.
+-- ============================================================================
+CREATE TABLE inventory.supplier_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+
+    supplier_id UUID NOT NULL REFERENCES inventory.suppliers(id) ON DELETE CASCADE,
+    item_id UUID NOT NULL REFERENCES inventory.inventory_items(id) ON DELETE CASCADE,
+
+    supplier_article_number VARCHAR(100),
+    unit_price_cents INTEGER,
+    pack_size INTEGER NOT NULL DEFAULT 1,
+    is_preferred BOOLEAN NOT NULL DEFAULT FALSE,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT supplier_items_unique UNIQUE (supplier_id, item_id),
+    CONSTRAINT supplier_items_pack_size_valid CHECK (pack_size > 0)
+);
+
+ALTER TABLE inventory.supplier_items ENABLE ROW LEVEL SECURITY;
+ALTER TABLE inventory.supplier_items FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON inventory.supplier_items
+    FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+CREATE INDEX idx_supplier_items_tenant ON inventory.supplier_items(tenant_id);
+CREATE INDEX idx_supplier_items_item ON inventory.supplier_items(item_id);
+CREATE UNIQUE INDEX idx_supplier_items_preferred ON inventory.supplier_items(item_id)
+    WHERE is_preferred;
+
+CREATE TRIGGER supplier_items_updated_at
+    BEFORE UPDATE ON inventory.supplier_items
+    FOR EACH ROW EXECUTE FUNCTION public.update_updated_at();
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON inventory.supplier_items TO medflow_app;
+
+-- ============================================================================
+-- 3. inventory.purchase_orders
+-- Orders (Bestellungen): draft -> sent -> partially_received -> received,
+-- or cancelled before they are fully received
+-- ============================================================================
+CREATE TABLE inventory.purchase_orders (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+
+    order_number VARCHAR(50) NOT NULL,
+    supplier_id UUID NOT NULL REFERENCES inventory.suppliers(id),
+    status VARCHAR(30) NOT NULL DEFAULT 'draft',
+    source VARCHAR(20) NOT NULL DEFAULT 'manual',
+
+    expected_date DATE,
+    sent_at TIMESTAMPTZ,
+    received_at TIMESTAMPTZ,
+    cancelled_at TIMESTAMPTZ,
+    cancel_reason TEXT,
+    notes TEXT,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+    created_by UUID,
+    updated_by UUID,
+
+    CONSTRAINT purchase_orders_number_unique UNIQUE (tenant_id, order_number),
+    CONSTRAINT purchase_orders_status_valid CHECK (
+        status IN ('draft', 'sent', 'partially_received', 'received', 'cancelled')
+    ),
+    CONSTRAINT purchase_orders_source_valid CHECK (
+        source IN ('manual', 'suggestion')
+    )
+);
+
+ALTER TABLE inventory.purchase_orders ENABLE ROW LEVEL SECURITY;
+ALTER TABLE inventory.purchase_orders FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON inventory.purchase_orders
+    FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+CREATE INDEX idx_purchase_orders_tenant ON inventory.purchase_orders(tenant_id);
+CREATE INDEX idx_purchase_orders_supplier ON inventory.purchase_orders(supplier_id);
+CREATE INDEX idx_purchase_orders_open ON inventory.purchase_orders(status)
+    WHERE deleted_at IS NULL AND status IN ('draft', 'sent', 'partially_received');
+
+CREATE TRIGGER purchase_orders_updated_at
+    BEFORE UPDATE ON inventory.purchase_orders
+    FOR EACH ROW EXECUTE FUNCTION public.update_updated_at();
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON inventory.purchase_orders TO medflow_app;
+
+-- ============================================================================
+-- 4. inventory.purchase_order_lines
+-- ============================================================================
+CREATE TABLE inventory.purchase_order_lines (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+
+    purchase_order_id UUID NOT NULL REFERENCES inventory.purchase_orders(id) ON DELETE CASCADE,
+    item_id UUID NOT NULL REFERENCES inventory.inventory_items(id) ON DELETE RESTRICT,
+
+    quantity_ordered INTEGER NOT NULL,
+    quantity_received INTEGER NOT NULL DEFAULT 0,
+    unit_price_cents INTEGER,
+    supplier_article_number VARCHAR(100),
+    notes TEXT,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT purchase_order_lines_quantity_valid CHECK (quantity_ordered > 0),
+    CONSTRAINT purchase_order_lines_received_valid CHECK (
+        quantity_received >= 0 AND quantity_received <= quantity_ordered
+    )
+);
+
+ALTER TABLE inventory.purchase_order_lines ENABLE ROW LEVEL SECURITY;
+ALTER TABLE inventory.purchase_order_lines FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON inventory.purchase_order_lines
+    FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+CREATE INDEX idx_purchase_order_lines_tenant ON inventory.purchase_order_lines(tenant_id);
+CREATE INDEX idx_purchase_order_lines_order ON inventory.purchase_order_lines(purchase_order_id);
+CREATE INDEX idx_purchase_order_lines_item ON inventory.purchase_order_lines(item_id);
+
+CREATE TRIGGER purchase_order_lines_updated_at
+    BEFORE UPDATE ON inventory.purchase_order_lines
+    FOR EACH ROW EXECUTE FUNCTION public.update_updated_at();
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON inventory.purchase_order_lines TO medflow_app;
+
+-- ============================================================================
+-- 5. inventory.goods_receipts / inventory.goods_receipt_lines
+-- Wareneingang: one receipt per delivery, one line per received batch
+-- ============================================================================
+CREATE TABLE inventory.goods_receipts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+
+    purchase_order_id UUID NOT NULL REFERENCES inventory.purchase_orders(id),
+    delivery_note_number VARCHAR(100),
+    received_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    received_by UUID,
+    received_by_name VARCHAR(255),
+    notes TEXT,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE inventory.goods_receipts ENABLE ROW LEVEL SECURITY;
+ALTER TABLE inventory.goods_receipts FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON inventory.goods_receipts
+    FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+CREATE INDEX idx_goods_receipts_tenant ON inventory.goods_receipts(tenant_id);
+CREATE INDEX idx_goods_receipts_order ON inventory.goods_receipts(purchase_order_id);
+
+GRANT SELECT, INSERT ON inventory.goods_receipts TO medflow_app;
+
+CREATE TABLE inventory.goods_receipt_lines (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+
+    goods_receipt_id UUID NOT NULL REFERENCES inventory.goods_receipts(id) ON DELETE CASCADE,
+    purchase_order_line_id UUID NOT NULL REFERENCES inventory.purchase_order_lines(id),
+    item_id UUID NOT NULL REFERENCES inventory.inventory_items(id),
+    batch_id UUID NOT NULL REFERENCES inventory.inventory_batches(id),
+    quantity INTEGER NOT NULL,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT goods_receipt_lines_quantity_valid CHECK (quantity > 0)
+);
+
+ALTER TABLE inventory.goods_receipt_lines ENABLE ROW LEVEL SECURITY;
+ALTER TABLE inventory.goods_receipt_lines FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON inventory.goods_receipt_lines
+    FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+CREATE INDEX idx_goods_receipt_lines_tenant ON inventory.goods_receipt_lines(tenant_id);
+CREATE INDEX idx_goods_receipt_lines_receipt ON inventory.goods_receipt_lines(goods_receipt_id);
+CREATE INDEX idx_goods_receipt_lines_batch ON inventory.goods_receipt_lines(batch_id);
+
+GRANT SELECT, INSERT ON inventory.goods_receipt_lines TO medflow_app;
+
+-- ============================================================================
+-- 6. inventory.stock_adjustments
+-- Bring the table in line with the repository: before/after quantities and
+-- performer, and the adjustment types the service books ('add', 'deduct',
+-- 'adjust' from manual corrections, 'receipt' from goods receipts).
+-- reference_type/reference_id link a booking to its source document.
+-- ============================================================================
+ALTER TABLE inventory.stock_adjustments
+    ADD COLUMN IF NOT EXISTS previous_quantity INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS new_quantity INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS performed_by VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS performed_by_name VARCHAR(255);
+
+ALTER TABLE inventory.stock_adjustments DROP CONSTRAINT IF EXISTS adjustments_type_valid;
+ALTER TABLE inventory.stock_adjustments ADD CONSTRAINT adjustments_type_valid CHECK (
+    adjustment_type IN ('add', 'deduct', 'adjust', 'receipt', 'issue', 'transfer', 'adjustment', 'disposal', 'return', 'count')
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_adjustments_reference
+    ON inventory.stock_adjustments(reference_type, reference_id)
+    WHERE reference_id IS NOT NULL;
@@ -0,0 +1,5 @@
+-- Rollback migration 000032: Remove stocktake sessions
+
+DROP TABLE IF EXISTS inventory.stocktake_counts;
+DROP TABLE IF EXISTS inventory.stocktake_lines;
+DROP TABLE IF EXISTS inventory.stocktakes;
@@ -0,0 +1,136 @@
+-- MedFlow: Stocktake (Inventur) sessions
+-- A stocktake freezes the expected quantity of every batch in scope when it
+-- starts. Several users count concurrently (blind: expected quantities are
+-- hidden until counting is closed); each count is kept as an immutable entry.
+-- Approval posts the variances as 'count' stock adjustments in one
+-- transaction and seals the result with a SHA-256 digest for the protocol.
+
+-- ============================================================================
+-- 1. inventory.stocktakes
+-- counting -> review -> approved, review -> counting for recounts,
+-- cancelled from counting or review
+-- ============================================================================
+CREATE TABLE inventory.stocktakes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+
+    stocktake_number VARCHAR(50) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'counting',
+    blind BOOLEAN NOT NULL DEFAULT TRUE,
+    location_ids UUID[] NOT NULL DEFAULT '{}', -- empty: all locations
+    notes TEXT,
+
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    started_by UUID,
+    started_by_name VARCHAR(255),
+    review_at TIMESTAMPTZ,
+    approved_at TIMESTAMPTZ,
+    approved_by UUID,
+    approved_by_name VARCHAR(255),
+    cancelled_at TIMESTAMPTZ,
+
+    -- Sealed on approval
+    variance_value_cents BIGINT,
+    protocol_sha256 CHAR(64),
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT stocktakes_number_unique UNIQUE (tenant_id, stocktake_number),
+    CONSTRAINT stocktakes_status_valid CHECK (
+        status IN ('counting', 'review', 'approved', 'cancelled')
+    )
+);
+
+ALTER TABLE inventory.stocktakes ENABLE ROW LEVEL SECURITY;
+ALTER TABLE inventory.stocktakes FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON inventory.stocktakes
+    FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+CREATE INDEX idx_stocktakes_tenant ON inventory.stocktakes(tenant_id);
+CREATE INDEX idx_stocktakes_status ON inventory.stocktakes(status);
+
+CREATE TRIGGER stocktakes_updated_at
+    BEFORE UPDATE ON inventory.stocktakes
+    FOR EACH ROW EXECUTE FUNCTION public.update_updated_at();
+
+GRANT SELECT, INSERT, UPDATE ON inventory.stocktakes TO medflow_app;
+
+-- ============================================================================
+-- 2. inventory.stocktake_lines
+-- One line per batch in scope; expected quantity and unit price are frozen
+-- when the stocktake starts
+-- ============================================================================
+CREATE TABLE inventory.stocktake_lines (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+
+    stocktake_id UUID NOT NULL REFERENCES inventory.stocktakes(id) ON DELETE CASCADE,
+    item_id UUID NOT NULL REFERENCES inventory.inventory_items(id),
+    batch_id UUID NOT NULL REFERENCES inventory.inventory_batches(id),
+    location_id UUID,
+
+    expected_quantity INTEGER NOT NULL,
+    unit_price_cents INTEGER NOT NULL DEFAULT 0,
+    counted_quantity INTEGER, -- NULL until the first count
+    last_counted_at TIMESTAMPTZ,
+    adjustment_id UUID REFERENCES inventory.stock_adjustments(id),
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT stocktake_lines_batch_unique UNIQUE (stocktake_id, batch_id),
+    CONSTRAINT stocktake_lines_counted_valid CHECK (counted_quantity IS NULL OR counted_quantity >= 0)
+);
+
+ALTER TABLE inventory.stocktake_lines ENABLE ROW LEVEL SECURITY;
+ALTER TABLE inventory.stocktake_lines FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON inventory.stocktake_lines
+    FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+CREATE INDEX idx_stocktake_lines_tenant ON inventory.stocktake_lines(tenant_id);
+CREATE INDEX idx_stocktake_lines_stocktake ON inventory.stocktake_lines(stocktake_id);
+
+CREATE TRIGGER stocktake_lines_updated_at
+    BEFORE UPDATE ON inventory.stocktake_lines
+    FOR EACH ROW EXECUTE FUNCTION public.update_updated_at();
+
+GRANT SELECT, INSERT, UPDATE ON inventory.stocktake_lines TO medflow_app;
+
+-- ============================================================================
+-- 3. inventory.stocktake_counts
+-- Count sheet entries (Zählliste). Entries are added up per line; a
+-- negative entry corrects a miscount.
+-- ============================================================================
+CREATE TABLE inventory.stocktake_counts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+
+    stocktake_id UUID NOT NULL REFERENCES inventory.stocktakes(id) ON DELETE CASCADE,
+    line_id UUID NOT NULL REFERENCES inventory.stocktake_lines(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL,
+    method VARCHAR(20) NOT NULL DEFAULT 'manual',
+    counted_by UUID,
+    counted_by_name VARCHAR(255),
+    counted_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT stocktake_counts_method_valid CHECK (method IN ('manual', 'scan'))
+);
+
+ALTER TABLE inventory.stocktake_counts ENABLE ROW LEVEL SECURITY;
+ALTER TABLE inventory.stocktake_counts FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON inventory.stocktake_counts
+    FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+CREATE INDEX idx_stocktake_counts_tenant ON inventory.stocktake_counts(tenant_id);
+CREATE INDEX idx_stocktake_counts_line ON inventory.stocktake_counts(line_id);
+
+-- Count entries are never changed, only added
+GRANT SELECT, INSERT ON inventory.stocktake_counts TO medflow_app;
@@ -0,0 +1,4 @@
+-- Rollback migration 000033: Remove stock reservations
+
+DROP TABLE IF EXISTS inventory.stock_reservation_batches;
+DROP TABLE IF EXISTS inventory.stock_reservations;
@@ -0,0 +1,89 @@
+-- MedFlow: Stock reservations
+-- A reservation holds stock of an item for a procedure or date. It is
+-- allocated to batches first-expiry-first-out when it is made; each
+-- allocation raises the batch's reserved_quantity, so reserved stock is no
+-- longer available for other reservations, transfers or deductions.
+-- Consuming books the stock out; releasing gives it back.
+
+-- ============================================================================
+-- 1. inventory.stock_reservations
+-- active -> consumed | released
+-- ============================================================================
+CREATE TABLE inventory.stock_reservations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+
+    item_id UUID NOT NULL REFERENCES inventory.inventory_items(id),
+    quantity INTEGER NOT NULL,
+    consumed_quantity INTEGER NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+
+    -- What the stock is held for
+    procedure_name VARCHAR(255),
+    needed_at TIMESTAMPTZ,
+    reference_type VARCHAR(50), -- e.g. appointment
+    reference_id UUID,
+    notes TEXT,
+
+    reserved_by UUID,
+    reserved_by_name VARCHAR(255),
+    consumed_at TIMESTAMPTZ,
+    released_at TIMESTAMPTZ,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT stock_reservations_quantity_valid CHECK (quantity > 0),
+    CONSTRAINT stock_reservations_consumed_valid CHECK (consumed_quantity >= 0 AND consumed_quantity <= quantity),
+    CONSTRAINT stock_reservations_status_valid CHECK (status IN ('active', 'consumed', 'released'))
+);
+
+ALTER TABLE inventory.stock_reservations ENABLE ROW LEVEL SECURITY;
+ALTER TABLE inventory.stock_reservations FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON inventory.stock_reservations
+    FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+CREATE INDEX idx_stock_reservations_tenant ON inventory.stock_reservations(tenant_id);
+CREATE INDEX idx_stock_reservations_item ON inventory.stock_reservations(item_id);
+CREATE INDEX idx_stock_reservations_active ON inventory.stock_reservations(needed_at)
+    WHERE status = 'active';
+
+CREATE TRIGGER stock_reservations_updated_at
+    BEFORE UPDATE ON inventory.stock_reservations
+    FOR EACH ROW EXECUTE FUNCTION public.update_updated_at();
+
+GRANT SELECT, INSERT, UPDATE ON inventory.stock_reservations TO medflow_app;
+
+-- ============================================================================
+-- 2. inventory.stock_reservation_batches
+-- The batches a reservation is allocated to, in pick order
+-- ============================================================================
+CREATE TABLE inventory.stock_reservation_batches (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+
+    reservation_id UUID NOT NULL REFERENCES inventory.stock_reservations(id) ON DELETE CASCADE,
+    batch_id UUID NOT NULL REFERENCES inventory.inventory_batches(id),
+    position INTEGER NOT NULL,
+    quantity INTEGER NOT NULL,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT stock_reservation_batches_unique UNIQUE (reservation_id, batch_id),
+    CONSTRAINT stock_reservation_batches_quantity_valid CHECK (quantity > 0)
+);
+
+ALTER TABLE inventory.stock_reservation_batches ENABLE ROW LEVEL SECURITY;
+ALTER TABLE inventory.stock_reservation_batches FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON inventory.stock_reservation_batches
+    FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+CREATE INDEX idx_stock_reservation_batches_tenant ON inventory.stock_reservation_batches(tenant_id);
+CREATE INDEX idx_stock_reservation_batches_reservation ON inventory.stock_reservation_batches(reservation_id);
+CREATE INDEX idx_stock_reservation_batches_batch ON inventory.stock_reservation_batches(batch_id);
+
+GRANT SELECT, INSERT ON inventory.stock_reservation_batches TO medflow_app;
@@ -0,0 +1,6 @@
+-- Rollback migration 000034: Remove procedure kits
+
+DROP TABLE IF EXISTS inventory.kit_consumption_lines;
+DROP TABLE IF EXISTS inventory.kit_consumptions;
+DROP TABLE IF EXISTS inventory.procedure_kit_items;
+DROP TABLE IF EXISTS inventory.procedure_kits;
@@ -0,0 +1,134 @@
+-- MedFlow: Procedure kits (Behandlungssets)
+-- A kit is the bill of materials of a treatment: the items and quantities it
+-- uses. Consuming a kit books out all of them first-expiry-first-out in one
+-- transaction; the consumption record carries the correlation ID that every
+-- stock adjustment of the booking references, for usage analytics.
+
+-- ============================================================================
+-- 1. inventory.procedure_kits
+-- ============================================================================
+CREATE TABLE inventory.procedure_kits (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+
+    created_by UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ
+);
+
+ALTER TABLE inventory.procedure_kits ENABLE ROW LEVEL SECURITY;
+ALTER TABLE inventory.procedure_kits FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON inventory.procedure_kits
+    FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+CREATE INDEX idx_procedure_kits_tenant ON inventory.procedure_kits(tenant_id);
+CREATE UNIQUE INDEX idx_procedure_kits_name ON inventory.procedure_kits(tenant_id, name)
+    WHERE deleted_at IS NULL;
+
+CREATE TRIGGER procedure_kits_updated_at
+    BEFORE UPDATE ON inventory.procedure_kits
+    FOR EACH ROW EXECUTE FUNCTION public.update_updated_at();
+
+GRANT SELECT, INSERT, UPDATE ON inventory.procedure_kits TO medflow_app;
+
+-- ============================================================================
+-- 2. inventory.procedure_kit_items
+-- ============================================================================
+CREATE TABLE inventory.procedure_kit_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+
+    kit_id UUID NOT NULL REFERENCES inventory.procedure_kits(id) ON DELETE CASCADE,
+    item_id UUID NOT NULL REFERENCES inventory.inventory_items(id),
+    quantity INTEGER NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT procedure_kit_items_unique UNIQUE (kit_id, item_id),
+    CONSTRAINT procedure_kit_items_quantity_valid CHECK (quantity > 0)
+);
+
+ALTER TABLE inventory.procedure_kit_items ENABLE ROW LEVEL SECURITY;
+ALTER TABLE inventory.procedure_kit_items FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON inventory.procedure_kit_items
+    FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+CREATE INDEX idx_procedure_kit_items_tenant ON inventory.procedure_kit_items(tenant_id);
+CREATE INDEX idx_procedure_kit_items_kit ON inventory.procedure_kit_items(kit_id);
+
+GRANT SELECT, INSERT, DELETE ON inventory.procedure_kit_items TO medflow_app;
+
+-- ============================================================================
+-- 3. inventory.kit_consumptions
+-- One row per consume operation; its ID is the correlation ID
+-- (reference_id of the stock adjustments)
+-- ============================================================================
+CREATE TABLE inventory.kit_consumptions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+
+    kit_id UUID NOT NULL REFERENCES inventory.procedure_kits(id),
+    kit_name VARCHAR(255) NOT NULL, -- as named when consumed
+    kit_count INTEGER NOT NULL DEFAULT 1,
+    reference_type VARCHAR(50), -- e.g. appointment
+    reference_id UUID,
+    notes TEXT,
+
+    performed_by VARCHAR(255),
+    performed_by_name VARCHAR(255),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT kit_consumptions_count_valid CHECK (kit_count > 0)
+);
+
+ALTER TABLE inventory.kit_consumptions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE inventory.kit_consumptions FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON inventory.kit_consumptions
+    FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+CREATE INDEX idx_kit_consumptions_tenant ON inventory.kit_consumptions(tenant_id);
+CREATE INDEX idx_kit_consumptions_kit ON inventory.kit_consumptions(kit_id, created_at);
+
+GRANT SELECT, INSERT ON inventory.kit_consumptions TO medflow_app;
+
+-- ============================================================================
+-- 4. inventory.kit_consumption_lines
+-- The batches each consumption booked out
+-- ============================================================================
+CREATE TABLE inventory.kit_consumption_lines (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+
+    consumption_id UUID NOT NULL REFERENCES inventory.kit_consumptions(id) ON DELETE CASCADE,
+    item_id UUID NOT NULL REFERENCES inventory.inventory_items(id),
+    batch_id UUID NOT NULL REFERENCES inventory.inventory_batches(id),
+    quantity INTEGER NOT NULL,
+    adjustment_id UUID NOT NULL REFERENCES inventory.stock_adjustments(id),
+
+    CONSTRAINT kit_consumption_lines_quantity_valid CHECK (quantity > 0)
+);
+
+ALTER TABLE inventory.kit_consumption_lines ENABLE ROW LEVEL SECURITY;
+ALTER TABLE inventory.kit_consumption_lines FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON inventory.kit_consumption_lines
+    FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+CREATE INDEX idx_kit_consumption_lines_tenant ON inventory.kit_consumption_lines(tenant_id);
+CREATE INDEX idx_kit_consumption_lines_consumption ON inventory.kit_consumption_lines(consumption_id);
+CREATE INDEX idx_kit_consumption_lines_item ON inventory.kit_consumption_lines(item_id);
+
+GRANT SELECT, INSERT ON inventory.kit_consumption_lines TO medflow_app;
@@ -0,0 +1,4 @@
+-- Rollback migration 000035: Remove consumption forecasts
+
+DROP INDEX IF EXISTS inventory.idx_stock_adjustments_item_created;
+DROP TABLE IF EXISTS inventory.consumption_forecasts;
@@ -0,0 +1,61 @@
+-- MedFlow: Consumption forecasts
+-- Daily consumption is aggregated from stock_adjustments (deductions and
+-- issues) and forecast per item by exponential smoothing with weekday
+-- factors. The latest forecast of each item is stored with the reorder
+-- point and quantity it suggests and the projected stock-out date; accepting
+-- a forecast copies the suggestions to the item.
+
+-- ============================================================================
+-- 1. inventory.consumption_forecasts
+-- ============================================================================
+CREATE TABLE inventory.consumption_forecasts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+    item_id UUID NOT NULL REFERENCES inventory.inventory_items(id) ON DELETE CASCADE,
+
+    -- Model
+    history_days INTEGER NOT NULL,
+    daily_demand DOUBLE PRECISION NOT NULL, -- smoothed level before weekday factors
+    weekday_factors DOUBLE PRECISION[] NOT NULL, -- Sunday first, averaging 1
+    std_dev DOUBLE PRECISION NOT NULL,
+
+    -- Parameters
+    lead_time_days INTEGER NOT NULL,
+    service_level NUMERIC(4,3) NOT NULL,
+    coverage_days INTEGER NOT NULL,
+
+    -- Results
+    lead_time_demand DOUBLE PRECISION NOT NULL,
+    safety_stock DOUBLE PRECISION NOT NULL,
+    suggested_reorder_point INTEGER NOT NULL,
+    suggested_reorder_quantity INTEGER NOT NULL,
+    current_stock INTEGER NOT NULL,
+    projected_stockout_date DATE,
+
+    computed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    accepted_at TIMESTAMPTZ,
+    accepted_by UUID,
+
+    CONSTRAINT consumption_forecasts_item_unique UNIQUE (tenant_id, item_id),
+    CONSTRAINT consumption_forecasts_service_level_valid CHECK (service_level >= 0.5 AND service_level < 1)
+);
+
+ALTER TABLE inventory.consumption_forecasts ENABLE ROW LEVEL SECURITY;
+ALTER TABLE inventory.consumption_forecasts FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON inventory.consumption_forecasts
+    FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+CREATE INDEX idx_consumption_forecasts_tenant ON inventory.consumption_forecasts(tenant_id);
+CREATE INDEX idx_consumption_forecasts_stockout ON inventory.consumption_forecasts(tenant_id, projected_stockout_date)
+    WHERE projected_stockout_date IS NOT NULL;
+
+GRANT SELECT, INSERT, UPDATE ON inventory.consumption_forecasts TO medflow_app;
+
+-- ============================================================================
+-- 2. Consumption history lookups
+-- ============================================================================
+CREATE INDEX IF NOT EXISTS idx_stock_adjustments_item_created
+    ON inventory.stock_adjustments(item_id, created_at)
+    WHERE adjustment_type IN ('deduct', 'issue');
@@ -0,0 +1,3 @@
+-- Rollback migration 000036: Remove label templates
+
+DROP TABLE IF EXISTS inventory.label_templates;
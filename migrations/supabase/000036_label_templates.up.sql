@@ -0,0 +1,54 @@
+-- MedFlow: Label templates
+-- A template is the label stock (size, sheet layout), the printer resolution
+-- and the barcode symbology for one kind of label: batches (incl. opened
+-- multi-dose containers with their use-by date), shelves and medical
+-- devices. The content of each kind is fixed by the application; a tenant
+-- may keep several templates per kind and mark one as the default.
+
+-- ============================================================================
+-- 1. inventory.label_templates
+-- ============================================================================
+CREATE TABLE inventory.label_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+
+    name VARCHAR(255) NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    width_mm NUMERIC(5,1) NOT NULL,
+    height_mm NUMERIC(5,1) NOT NULL,
+    dpi INTEGER NOT NULL DEFAULT 203,
+    symbology VARCHAR(20) NOT NULL,
+    sheet_columns INTEGER NOT NULL DEFAULT 0, -- PDF on A4 sheets; 0 = one label per page
+    sheet_rows INTEGER NOT NULL DEFAULT 0,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+
+    created_by UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+
+    CONSTRAINT label_templates_kind_valid CHECK (kind IN ('batch', 'shelf', 'device')),
+    CONSTRAINT label_templates_symbology_valid CHECK (symbology IN ('code128', 'datamatrix', 'qr')),
+    CONSTRAINT label_templates_dpi_valid CHECK (dpi IN (203, 300, 600)),
+    CONSTRAINT label_templates_size_valid CHECK (width_mm BETWEEN 15 AND 150 AND height_mm BETWEEN 15 AND 150),
+    CONSTRAINT label_templates_sheet_valid CHECK (sheet_columns >= 0 AND sheet_rows >= 0)
+);
+
+ALTER TABLE inventory.label_templates ENABLE ROW LEVEL SECURITY;
+ALTER TABLE inventory.label_templates FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON inventory.label_templates
+    FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+CREATE INDEX idx_label_templates_tenant ON inventory.label_templates(tenant_id);
+CREATE UNIQUE INDEX idx_label_templates_name ON inventory.label_templates(tenant_id, name)
+    WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX idx_label_templates_default ON inventory.label_templates(tenant_id, kind)
+    WHERE is_default AND deleted_at IS NULL;
+
+CREATE TRIGGER label_templates_updated_at
+    BEFORE UPDATE ON inventory.label_templates
+    FOR EACH ROW EXECUTE FUNCTION public.update_updated_at();
+
+GRANT SELECT, INSERT, UPDATE ON inventory.label_templates TO medflow_app;
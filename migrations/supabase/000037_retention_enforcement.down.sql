@@ -0,0 +1,38 @@
+-- Rollback migration 000037: Remove retention enforcement
+
+CREATE OR REPLACE FUNCTION inventory.seed_retention_policies(p_tenant_id UUID)
+RETURNS void AS $$
+    INSERT INTO inventory.retention_policies
+        (tenant_id, entity_type, retention_years, legal_basis, description)
+    VALUES
+        (p_tenant_id, 'device_book', 20, 'MPBetreibV §12', 'Medizinproduktebuch entries'),
+        (p_tenant_id, 'btm_register', 3, 'BtMG §13 Abs. 3', 'Betäubungsmittelbuch entries'),
+        (p_tenant_id, 'sterilization', 15, 'RKI/KRINKO', 'Sterilization batch records'),
+        (p_tenant_id, 'working_time', 2, 'ArbZG §16', 'Working time records'),
+        (p_tenant_id, 'radiation', 30, 'StrlSchV §85', 'Dosimetry and radiation records'),
+        (p_tenant_id, 'tax_documents', 10, 'AO §147', 'Tax-relevant inventory documents'),
+        (p_tenant_id, 'hazardous_substances', 40, 'GefStoffV §14', 'Gefahrstoffverzeichnis entries')
+    ON CONFLICT (tenant_id, entity_type) DO NOTHING;
+$$ LANGUAGE sql;
+
+ALTER TABLE staff.time_correction_requests
+    DROP CONSTRAINT IF EXISTS time_correction_requests_time_entry_id_fkey,
+    ADD CONSTRAINT time_correction_requests_time_entry_id_fkey
+        FOREIGN KEY (time_entry_id) REFERENCES staff.time_entries(id);
+ALTER TABLE staff.compliance_violations
+    DROP CONSTRAINT IF EXISTS compliance_violations_time_entry_id_fkey,
+    ADD CONSTRAINT compliance_violations_time_entry_id_fkey
+        FOREIGN KEY (time_entry_id) REFERENCES staff.time_entries(id);
+
+ALTER TABLE staff.employees DROP COLUMN IF EXISTS anonymized_at;
+ALTER TABLE inventory.dosimetry_records DROP COLUMN IF EXISTS anonymized_at;
+ALTER TABLE inventory.staff_radiation_certifications DROP COLUMN IF EXISTS anonymized_at;
+ALTER TABLE inventory.bio_trainings DROP COLUMN IF EXISTS anonymized_at;
+ALTER TABLE inventory.device_trainings DROP COLUMN IF EXISTS anonymized_at;
+
+DROP TABLE IF EXISTS inventory.deletion_protocols;
+DROP TABLE IF EXISTS inventory.legal_holds;
+
+ALTER TABLE inventory.retention_policies
+    DROP CONSTRAINT IF EXISTS retention_policies_purge_action_valid,
+    DROP COLUMN IF EXISTS purge_action;
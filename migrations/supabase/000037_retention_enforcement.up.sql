@@ -0,0 +1,131 @@
+-- MedFlow: Retention enforcement
+-- Deletes are checked against the retention policy of the record's entity
+-- type and against legal holds. A nightly job per service deletes or
+-- anonymizes records whose retention period has ended, as the policy's
+-- purge action says, and writes a deletion protocol (Löschprotokoll).
+
+-- ============================================================================
+-- 1. Purge action per retention policy
+-- 'none' keeps records after the retention period (default, so existing
+-- policies change nothing until a tenant opts in)
+-- ============================================================================
+ALTER TABLE inventory.retention_policies
+    ADD COLUMN purge_action VARCHAR(20) NOT NULL DEFAULT 'none',
+    ADD CONSTRAINT retention_policies_purge_action_valid CHECK (purge_action IN ('none', 'delete', 'anonymize'));
+
+-- ============================================================================
+-- 2. inventory.legal_holds
+-- A hold on an entity type (entity_id NULL) or on one record blocks deleting
+-- and purging until it is released, e.g. during litigation or an audit
+-- ============================================================================
+CREATE TABLE inventory.legal_holds (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id UUID,
+    reason TEXT NOT NULL,
+    reference VARCHAR(255), -- case or file number
+
+    created_by UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    released_at TIMESTAMPTZ,
+    released_by UUID,
+    release_note TEXT
+);
+
+ALTER TABLE inventory.legal_holds ENABLE ROW LEVEL SECURITY;
+ALTER TABLE inventory.legal_holds FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON inventory.legal_holds
+    FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+CREATE INDEX idx_legal_holds_tenant ON inventory.legal_holds(tenant_id);
+CREATE INDEX idx_legal_holds_active ON inventory.legal_holds(tenant_id, entity_type, entity_id)
+    WHERE released_at IS NULL;
+
+-- Holds are released, never deleted
+GRANT SELECT, INSERT, UPDATE ON inventory.legal_holds TO medflow_app;
+
+-- ============================================================================
+-- 3. inventory.deletion_protocols
+-- One entry per table and purge run: what was deleted or anonymized, under
+-- which policy and cutoff. Append-only.
+-- ============================================================================
+CREATE TABLE inventory.deletion_protocols (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+
+    entity_type VARCHAR(50) NOT NULL,
+    table_name VARCHAR(100) NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    legal_basis TEXT,
+    retention_years INTEGER NOT NULL,
+    cutoff_date DATE NOT NULL,
+    record_count INTEGER NOT NULL,
+    record_ids UUID[] NOT NULL,
+
+    executed_by UUID,
+    executed_by_name VARCHAR(255),
+    executed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT deletion_protocols_action_valid CHECK (action IN ('delete', 'anonymize'))
+);
+
+ALTER TABLE inventory.deletion_protocols ENABLE ROW LEVEL SECURITY;
+ALTER TABLE inventory.deletion_protocols FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON inventory.deletion_protocols
+    FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+CREATE INDEX idx_deletion_protocols_tenant ON inventory.deletion_protocols(tenant_id, executed_at DESC);
+
+GRANT SELECT, INSERT ON inventory.deletion_protocols TO medflow_app;
+
+-- ============================================================================
+-- 4. Anonymization markers
+-- Records with personal data that are anonymized instead of deleted
+-- ============================================================================
+ALTER TABLE inventory.device_trainings ADD COLUMN anonymized_at TIMESTAMPTZ;
+ALTER TABLE inventory.bio_trainings ADD COLUMN anonymized_at TIMESTAMPTZ;
+ALTER TABLE inventory.staff_radiation_certifications ADD COLUMN anonymized_at TIMESTAMPTZ;
+ALTER TABLE inventory.dosimetry_records ADD COLUMN anonymized_at TIMESTAMPTZ;
+ALTER TABLE staff.employees ADD COLUMN anonymized_at TIMESTAMPTZ;
+
+-- ============================================================================
+-- 5. Purgeable time entries
+-- Violations and correction requests outlive the entries they refer to
+-- ============================================================================
+ALTER TABLE staff.compliance_violations
+    DROP CONSTRAINT IF EXISTS compliance_violations_time_entry_id_fkey,
+    ADD CONSTRAINT compliance_violations_time_entry_id_fkey
+        FOREIGN KEY (time_entry_id) REFERENCES staff.time_entries(id) ON DELETE SET NULL;
+ALTER TABLE staff.time_correction_requests
+    DROP CONSTRAINT IF EXISTS time_correction_requests_time_entry_id_fkey,
+    ADD CONSTRAINT time_correction_requests_time_entry_id_fkey
+        FOREIGN KEY (time_entry_id) REFERENCES staff.time_entries(id) ON DELETE SET NULL;
+
+-- ============================================================================
+-- 6. Default policies for new tenants
+-- Adds the purge action and the entity types the retention guard knows
+-- beyond those of migration 000021. Existing tenants keep their policies.
+-- ============================================================================
+CREATE OR REPLACE FUNCTION inventory.seed_retention_policies(p_tenant_id UUID)
+RETURNS void AS $$
+    INSERT INTO inventory.retention_policies
+        (tenant_id, entity_type, retention_years, legal_basis, description, purge_action)
+    VALUES
+        (p_tenant_id, 'device_book', 20, 'MPBetreibV §12', 'Medizinproduktebuch entries', 'none'),
+        (p_tenant_id, 'btm_register', 3, 'BtMG §13 Abs. 3', 'Betäubungsmittelbuch entries', 'none'),
+        (p_tenant_id, 'sterilization', 15, 'RKI/KRINKO', 'Sterilization batch records', 'none'),
+        (p_tenant_id, 'working_time', 2, 'ArbZG §16', 'Working time records', 'delete'),
+        (p_tenant_id, 'radiation', 30, 'StrlSchV §85', 'Dosimetry and radiation records', 'anonymize'),
+        (p_tenant_id, 'tax_documents', 10, 'AO §147', 'Tax-relevant inventory documents', 'none'),
+        (p_tenant_id, 'hazardous_substances', 40, 'GefStoffV §14', 'Gefahrstoffverzeichnis entries', 'none'),
+        (p_tenant_id, 'biosafety', 10, 'BioStoffV §7 Abs. 3', 'Biological agent assessments and trainings', 'anonymize'),
+        (p_tenant_id, 'hygiene', 10, 'IfSG §23 Abs. 4', 'Hygiene plans and inspections', 'none'),
+        (p_tenant_id, 'personnel_file', 3, 'BGB §195, DSGVO Art. 17', 'Personal data of former employees', 'delete')
+    ON CONFLICT (tenant_id, entity_type) DO NOTHING;
+$$ LANGUAGE sql;
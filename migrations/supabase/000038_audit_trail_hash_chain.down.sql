@@ -0,0 +1,42 @@
+-- Rollback migration 000038: Remove the audit trail hash chain
+-- The entity type and action lists of 000017 are restored for new entries;
+-- entries written since are kept (NOT VALID).
+
+DROP INDEX IF EXISTS public.idx_tenant_audit_chain_anchor;
+DELETE FROM public.tenant_audit_log WHERE event_type = 'audit_chain_anchored';
+
+ALTER TABLE public.tenant_audit_log
+    DROP CONSTRAINT tenant_audit_event_type_valid,
+    ADD CONSTRAINT tenant_audit_event_type_valid CHECK (
+        event_type IN (
+            'created', 'updated', 'suspended', 'reactivated', 'deleted',
+            'tier_changed', 'settings_updated', 'data_exported',
+            'user_invited', 'user_removed'
+        )
+    );
+
+DROP TABLE IF EXISTS public.audit_chain_heads;
+
+DROP INDEX IF EXISTS inventory.idx_audit_trail_tenant_sequence;
+ALTER TABLE inventory.audit_trail
+    DROP CONSTRAINT IF EXISTS audit_trail_chain_complete,
+    DROP COLUMN IF EXISTS hash,
+    DROP COLUMN IF EXISTS prev_hash,
+    DROP COLUMN IF EXISTS sequence;
+
+ALTER TABLE inventory.audit_trail
+    DROP CONSTRAINT audit_trail_entity_type_valid,
+    ADD CONSTRAINT audit_trail_entity_type_valid CHECK (
+        entity_type IN (
+            'item', 'batch', 'alert', 'hazardous', 'inspection',
+            'training', 'incident', 'temperature', 'document'
+        )
+    ) NOT VALID,
+    DROP CONSTRAINT audit_trail_action_valid,
+    ADD CONSTRAINT audit_trail_action_valid CHECK (
+        action IN (
+            'create', 'update', 'delete', 'adjust',
+            'open', 'btm_receipt', 'btm_dispense', 'btm_disposal',
+            'btm_correction', 'btm_check', 'recall_match', 'recall_resolve'
+        )
+    ) NOT VALID;
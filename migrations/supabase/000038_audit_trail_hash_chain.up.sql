@@ -0,0 +1,98 @@
+-- MedFlow: Tamper-evident GoBD audit trail
+-- Every new inventory.audit_trail entry gets a per-tenant sequence number and
+-- the SHA-256 hash of its content chained to the previous entry's hash
+-- (pkg/auditchain). Changing, deleting or reordering entries breaks the
+-- chain, which GET /api/v1/inventory/audit/verify and cmd/audit-verify
+-- report. The chain head is anchored hourly in public.tenant_audit_log.
+
+-- ============================================================================
+-- 1. Chain columns on inventory.audit_trail
+-- Entries written before this migration stay unchained (sequence NULL); a
+-- tenant's first chained entry links to the genesis hash (64 zeros)
+-- ============================================================================
+ALTER TABLE inventory.audit_trail
+    ADD COLUMN sequence BIGINT,
+    ADD COLUMN prev_hash CHAR(64),
+    ADD COLUMN hash CHAR(64),
+    ADD CONSTRAINT audit_trail_chain_complete CHECK (
+        (sequence IS NULL AND prev_hash IS NULL AND hash IS NULL)
+        OR (sequence IS NOT NULL AND prev_hash IS NOT NULL AND hash IS NOT NULL)
+    );
+
+CREATE UNIQUE INDEX idx_audit_trail_tenant_sequence ON inventory.audit_trail(tenant_id, sequence)
+    WHERE sequence IS NOT NULL;
+
+-- The entity type and action lists of 000017 predate most of the audited
+-- entities (orders, stocktakes, kits, cold chain, device books, ...), whose
+-- entries were rejected. Extend them to what the services record.
+ALTER TABLE inventory.audit_trail
+    DROP CONSTRAINT audit_trail_entity_type_valid,
+    ADD CONSTRAINT audit_trail_entity_type_valid CHECK (
+        entity_type IN (
+            'item', 'batch', 'alert', 'hazardous', 'inspection',
+            'training', 'incident', 'temperature', 'document',
+            'supplier', 'purchase_order', 'stocktake', 'reservation', 'procedure_kit',
+            'label_template', 'retention_policy', 'legal_hold', 'gdpdu_export',
+            'btm', 'btm_period_closing', 'recall_match', 'field_safety_notice',
+            'temperature_sensor', 'temperature_excursion',
+            'radiation_device', 'expert_inspection', 'constancy_test',
+            'staff_radiation_certification', 'dosimetry_record',
+            'sterilization_batch', 'reprocessing_cycle', 'hygiene_plan', 'hygiene_inspection',
+            'bio_risk_assessment', 'bio_training'
+        )
+    ),
+    DROP CONSTRAINT audit_trail_action_valid,
+    ADD CONSTRAINT audit_trail_action_valid CHECK (
+        action IN (
+            'create', 'update', 'delete', 'adjust',
+            'open', 'btm_receipt', 'btm_dispense', 'btm_disposal',
+            'btm_correction', 'btm_check', 'recall_match', 'recall_resolve',
+            'btm_inventory_check', 'btm_period_closed', 'resolve', 'transfer',
+            'send', 'receive', 'cancel', 'set_item', 'remove_item',
+            'finish_counting', 'reopen', 'approve', 'consume', 'release',
+            'request', 'download', 'rotate_secret', 'revoke',
+            'quarantine', 'released', 'discarded'
+        )
+    );
+
+-- ============================================================================
+-- 2. public.audit_chain_heads
+-- Sequence and hash of the last entry of each tenant's chain. Writers lock
+-- the row (SELECT ... FOR UPDATE), so concurrent inserts are serialized per
+-- tenant and get consecutive sequence numbers.
+-- ============================================================================
+CREATE TABLE public.audit_chain_heads (
+    tenant_id UUID NOT NULL REFERENCES public.tenants(id) ON DELETE CASCADE,
+    chain VARCHAR(100) NOT NULL, -- e.g. 'inventory.audit_trail'
+    sequence BIGINT NOT NULL DEFAULT 0,
+    hash CHAR(64) NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (tenant_id, chain)
+);
+
+ALTER TABLE public.audit_chain_heads ENABLE ROW LEVEL SECURITY;
+ALTER TABLE public.audit_chain_heads FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON public.audit_chain_heads
+    FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+GRANT SELECT, INSERT, UPDATE ON public.audit_chain_heads TO medflow_app;
+
+-- ============================================================================
+-- 3. Chain anchors in public.tenant_audit_log
+-- event_data: {"chain": ..., "sequence": ..., "hash": ...}
+-- ============================================================================
+ALTER TABLE public.tenant_audit_log
+    DROP CONSTRAINT tenant_audit_event_type_valid,
+    ADD CONSTRAINT tenant_audit_event_type_valid CHECK (
+        event_type IN (
+            'created', 'updated', 'suspended', 'reactivated', 'deleted',
+            'tier_changed', 'settings_updated', 'data_exported',
+            'user_invited', 'user_removed', 'audit_chain_anchored'
+        )
+    );
+
+CREATE INDEX idx_tenant_audit_chain_anchor ON public.tenant_audit_log(tenant_id, performed_at DESC)
+    WHERE event_type = 'audit_chain_anchored';
@@ -0,0 +1,3 @@
+-- Rollback migration 000039: Remove GDPdU data exports
+
+DROP TABLE IF EXISTS inventory.gdpdu_exports;
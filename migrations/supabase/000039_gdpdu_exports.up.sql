@@ -0,0 +1,46 @@
+-- MedFlow: GDPdU data export (Datenträgerüberlassung)
+-- A tax auditor receives the inventory's tax-relevant data for a period as a
+-- ZIP of CSV files with an index.xml in the GDPdU description standard
+-- (pkg/gdpdu). Exports are requested through the API and built by the
+-- inventory.gdpdu_exports job; the ZIP is kept in object storage.
+
+-- ============================================================================
+-- inventory.gdpdu_exports
+-- ============================================================================
+CREATE TABLE inventory.gdpdu_exports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+
+    period_from DATE NOT NULL,
+    period_to DATE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+
+    file_key TEXT,
+    file_size BIGINT,
+    sha256 CHAR(64),       -- checksum of the ZIP
+    record_count BIGINT,
+    manifest JSONB,        -- files of the ZIP with size, checksum and record count
+    error TEXT,
+
+    requested_by UUID,
+    requested_by_name VARCHAR(255),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ,
+
+    CONSTRAINT gdpdu_exports_period_valid CHECK (period_to >= period_from),
+    CONSTRAINT gdpdu_exports_status_valid CHECK (status IN ('pending', 'running', 'completed', 'failed'))
+);
+
+ALTER TABLE inventory.gdpdu_exports ENABLE ROW LEVEL SECURITY;
+ALTER TABLE inventory.gdpdu_exports FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON inventory.gdpdu_exports
+    FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+CREATE INDEX idx_gdpdu_exports_tenant ON inventory.gdpdu_exports(tenant_id, created_at DESC);
+CREATE INDEX idx_gdpdu_exports_open ON inventory.gdpdu_exports(tenant_id, created_at)
+    WHERE status IN ('pending', 'running');
+
+GRANT SELECT, INSERT, UPDATE ON inventory.gdpdu_exports TO medflow_app;
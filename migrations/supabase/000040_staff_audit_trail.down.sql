@@ -0,0 +1,8 @@
+-- Rollback migration 000040: Remove the staff audit trail
+
+DELETE FROM public.tenant_audit_log
+WHERE event_type = 'audit_chain_anchored' AND event_data->>'chain' = 'staff.audit_trail';
+
+DELETE FROM public.audit_chain_heads WHERE chain = 'staff.audit_trail';
+
+DROP TABLE IF EXISTS staff.audit_trail;
@@ -0,0 +1,58 @@
+-- MedFlow: GoBD-style audit trail for the staff service
+-- Append-only, hash-chained log of changes to working time records, breaks,
+-- correction requests, absences and personnel data, with field-level
+-- before/after diffs, actor and client IP. Entries are written in the
+-- transaction of the change they record, so a change without its entry
+-- cannot be committed. The chain (pkg/auditchain, chain 'staff.audit_trail')
+-- shares public.audit_chain_heads with the inventory audit trail and is
+-- anchored hourly in public.tenant_audit_log.
+
+-- ============================================================================
+-- staff.audit_trail (append-only, immutable)
+-- ============================================================================
+CREATE TABLE staff.audit_trail (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+
+    entity_type VARCHAR(50) NOT NULL,   -- time_entry, absence, correction_request, employee, ...
+    entity_id UUID NOT NULL,
+    employee_id UUID,                   -- employee the record belongs to
+    action VARCHAR(50) NOT NULL,
+
+    field_changes JSONB,                -- {"field": {"old": ..., "new": ...}}
+    metadata JSONB,
+
+    performed_by UUID,
+    performed_by_name VARCHAR(255),
+    ip_address VARCHAR(45),
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    sequence BIGINT NOT NULL,
+    prev_hash CHAR(64) NOT NULL,
+    hash CHAR(64) NOT NULL
+);
+
+-- NO updated_at column (immutable)
+-- NO deleted_at column (immutable)
+
+ALTER TABLE staff.audit_trail ENABLE ROW LEVEL SECURITY;
+ALTER TABLE staff.audit_trail FORCE ROW LEVEL SECURITY;
+
+-- Separate SELECT and INSERT policies (no UPDATE/DELETE allowed)
+CREATE POLICY audit_trail_select ON staff.audit_trail
+    FOR SELECT USING (tenant_id = current_setting('app.current_tenant')::uuid);
+
+CREATE POLICY audit_trail_insert ON staff.audit_trail
+    FOR INSERT WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+-- Indexes
+CREATE UNIQUE INDEX idx_staff_audit_trail_tenant_sequence ON staff.audit_trail(tenant_id, sequence);
+CREATE INDEX idx_staff_audit_trail_entity ON staff.audit_trail(tenant_id, entity_type, entity_id);
+CREATE INDEX idx_staff_audit_trail_employee ON staff.audit_trail(tenant_id, employee_id, created_at DESC)
+    WHERE employee_id IS NOT NULL;
+CREATE INDEX idx_staff_audit_trail_created ON staff.audit_trail(tenant_id, created_at);
+CREATE INDEX idx_staff_audit_trail_performed_by ON staff.audit_trail(performed_by);
+
+-- Append-only: SELECT + INSERT only, NO UPDATE, NO DELETE
+GRANT SELECT, INSERT ON staff.audit_trail TO medflow_app;
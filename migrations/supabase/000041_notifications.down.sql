@@ -0,0 +1,8 @@
+-- Rollback migration 000041: Remove alert notifications
+
+DROP TABLE IF EXISTS users.notification_inbox;
+DROP TABLE IF EXISTS users.notification_deliveries;
+DROP TABLE IF EXISTS users.notifications;
+DROP TABLE IF EXISTS users.notification_rules;
+DROP TABLE IF EXISTS users.notification_webhooks;
+DROP TABLE IF EXISTS users.notification_settings;
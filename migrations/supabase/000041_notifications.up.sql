@@ -0,0 +1,236 @@
+-- MedFlow: Alert notifications
+-- Inventory alerts (inventory.alert.generated) and ArbZG compliance alerts
+-- (staff.compliance.alert.created) are routed by rules to email, signed
+-- webhooks and the in-app inbox. A notification that stays unacknowledged
+-- escalates along the rule's escalation steps; email and webhook deliveries
+-- outside the tenant's quiet hours wait until they end unless the alert is
+-- severe enough to bypass them. Owned by the user service.
+
+-- ============================================================================
+-- users.notification_settings (one row per tenant)
+-- ============================================================================
+CREATE TABLE users.notification_settings (
+    tenant_id UUID PRIMARY KEY REFERENCES public.tenants(id),
+
+    quiet_hours_start TIME,                 -- e.g. 20:00; NULL = no quiet hours
+    quiet_hours_end TIME,                   -- e.g. 07:00 (next day)
+    timezone VARCHAR(64) NOT NULL DEFAULT 'Europe/Berlin',
+    quiet_hours_bypass_severity VARCHAR(20) NOT NULL DEFAULT 'critical',
+    locale VARCHAR(5) NOT NULL DEFAULT 'de', -- language of mails and inbox items
+
+    updated_by UUID,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT notification_settings_quiet_hours_valid CHECK (
+        (quiet_hours_start IS NULL) = (quiet_hours_end IS NULL)
+    ),
+    CONSTRAINT notification_settings_bypass_valid CHECK (
+        quiet_hours_bypass_severity IN ('info', 'warning', 'critical', 'never')
+    )
+);
+
+ALTER TABLE users.notification_settings ENABLE ROW LEVEL SECURITY;
+ALTER TABLE users.notification_settings FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON users.notification_settings
+    FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+-- ============================================================================
+-- users.notification_webhooks
+-- ============================================================================
+CREATE TABLE users.notification_webhooks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+
+    name VARCHAR(255) NOT NULL,
+    url TEXT NOT NULL,
+    secret VARCHAR(128) NOT NULL,           -- HMAC-SHA256 key of X-MedFlow-Signature
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+
+    created_by UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT notification_webhooks_url_valid CHECK (url ~* '^https?://')
+);
+
+ALTER TABLE users.notification_webhooks ENABLE ROW LEVEL SECURITY;
+ALTER TABLE users.notification_webhooks FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON users.notification_webhooks
+    FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+CREATE INDEX idx_notification_webhooks_tenant ON users.notification_webhooks(tenant_id) WHERE is_active;
+
+CREATE TRIGGER notification_webhooks_updated_at
+    BEFORE UPDATE ON users.notification_webhooks
+    FOR EACH ROW EXECUTE FUNCTION public.update_updated_at();
+
+-- ============================================================================
+-- users.notification_rules
+-- ============================================================================
+CREATE TABLE users.notification_rules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+
+    name VARCHAR(255) NOT NULL,
+    source VARCHAR(20) NOT NULL,            -- inventory, staff
+    alert_types TEXT[] NOT NULL DEFAULT '{}',  -- empty = every alert type of the source
+    min_severity VARCHAR(20) NOT NULL DEFAULT 'info',
+    channels TEXT[] NOT NULL,               -- email, webhook, inbox
+
+    recipient_roles TEXT[] NOT NULL DEFAULT '{}',
+    recipient_user_ids UUID[] NOT NULL DEFAULT '{}',
+
+    -- [{"after_minutes": 30, "roles": ["practice_manager"], "user_ids": []}, ...]
+    -- Step n is notified when the notification is still unacknowledged
+    -- after_minutes after step n-1
+    escalation JSONB NOT NULL DEFAULT '[]'::jsonb,
+
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+
+    created_by UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT notification_rules_source_valid CHECK (source IN ('inventory', 'staff')),
+    CONSTRAINT notification_rules_severity_valid CHECK (min_severity IN ('info', 'warning', 'critical')),
+    CONSTRAINT notification_rules_channels_valid CHECK (
+        cardinality(channels) > 0 AND channels <@ ARRAY['email', 'webhook', 'inbox']
+    ),
+    CONSTRAINT notification_rules_escalation_array CHECK (jsonb_typeof(escalation) = 'array')
+);
+
+ALTER TABLE users.notification_rules ENABLE ROW LEVEL SECURITY;
+ALTER TABLE users.notification_rules FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON users.notification_rules
+    FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+CREATE INDEX idx_notification_rules_source ON users.notification_rules(tenant_id, source) WHERE is_active;
+
+CREATE TRIGGER notification_rules_updated_at
+    BEFORE UPDATE ON users.notification_rules
+    FOR EACH ROW EXECUTE FUNCTION public.update_updated_at();
+
+-- ============================================================================
+-- users.notifications (one per rule and alert)
+-- ============================================================================
+CREATE TABLE users.notifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+    rule_id UUID NOT NULL REFERENCES users.notification_rules(id) ON DELETE CASCADE,
+
+    source VARCHAR(20) NOT NULL,
+    source_id VARCHAR(100) NOT NULL,        -- id of the (latest) alert
+    dedup_key VARCHAR(255) NOT NULL,        -- identifies the condition across re-raised alerts
+    alert_type VARCHAR(50) NOT NULL,
+    severity VARCHAR(20) NOT NULL,
+    message TEXT NOT NULL,
+    link TEXT,                              -- web app path of the alerted item
+
+    escalation_level INTEGER NOT NULL DEFAULT 0,
+    next_escalation_at TIMESTAMPTZ,         -- NULL once acknowledged or fully escalated
+
+    acknowledged_at TIMESTAMPTZ,
+    acknowledged_by UUID,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE users.notifications ENABLE ROW LEVEL SECURITY;
+ALTER TABLE users.notifications FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON users.notifications
+    FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+-- One open notification per rule and condition
+CREATE UNIQUE INDEX idx_notifications_open ON users.notifications(tenant_id, rule_id, dedup_key)
+    WHERE acknowledged_at IS NULL;
+CREATE INDEX idx_notifications_source ON users.notifications(tenant_id, source, source_id);
+CREATE INDEX idx_notifications_escalation ON users.notifications(tenant_id, next_escalation_at)
+    WHERE next_escalation_at IS NOT NULL;
+CREATE INDEX idx_notifications_created ON users.notifications(tenant_id, created_at DESC);
+
+CREATE TRIGGER notifications_updated_at
+    BEFORE UPDATE ON users.notifications
+    FOR EACH ROW EXECUTE FUNCTION public.update_updated_at();
+
+-- ============================================================================
+-- users.notification_deliveries (outgoing email and webhook calls)
+-- ============================================================================
+CREATE TABLE users.notification_deliveries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+    notification_id UUID NOT NULL REFERENCES users.notifications(id) ON DELETE CASCADE,
+
+    escalation_level INTEGER NOT NULL DEFAULT 0,
+    channel VARCHAR(20) NOT NULL,
+    recipient_user_id UUID,                 -- email: the notified user
+    recipient TEXT NOT NULL,                -- email address or webhook id
+
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    send_after TIMESTAMPTZ NOT NULL DEFAULT NOW(),  -- later than created_at during quiet hours
+    last_error TEXT,
+    sent_at TIMESTAMPTZ,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT notification_deliveries_channel_valid CHECK (channel IN ('email', 'webhook')),
+    CONSTRAINT notification_deliveries_status_valid CHECK (status IN ('pending', 'sent', 'failed', 'cancelled'))
+);
+
+ALTER TABLE users.notification_deliveries ENABLE ROW LEVEL SECURITY;
+ALTER TABLE users.notification_deliveries FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON users.notification_deliveries
+    FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+CREATE INDEX idx_notification_deliveries_due ON users.notification_deliveries(tenant_id, send_after)
+    WHERE status = 'pending';
+CREATE INDEX idx_notification_deliveries_notification ON users.notification_deliveries(notification_id);
+
+-- ============================================================================
+-- users.notification_inbox (in-app channel, one item per user)
+-- ============================================================================
+CREATE TABLE users.notification_inbox (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+    user_id UUID NOT NULL REFERENCES users.users(id) ON DELETE CASCADE,
+    notification_id UUID REFERENCES users.notifications(id) ON DELETE SET NULL,
+
+    category VARCHAR(50) NOT NULL,          -- e.g. inventory_alert, compliance_alert
+    severity VARCHAR(20) NOT NULL DEFAULT 'info',
+    title VARCHAR(255) NOT NULL,
+    body TEXT,
+    link TEXT,
+
+    read_at TIMESTAMPTZ,
+    archived_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE users.notification_inbox ENABLE ROW LEVEL SECURITY;
+ALTER TABLE users.notification_inbox FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON users.notification_inbox
+    FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+CREATE INDEX idx_notification_inbox_user ON users.notification_inbox(tenant_id, user_id, created_at DESC)
+    WHERE archived_at IS NULL;
+CREATE INDEX idx_notification_inbox_notification ON users.notification_inbox(notification_id);
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON users.notification_settings TO medflow_app;
+GRANT SELECT, INSERT, UPDATE, DELETE ON users.notification_webhooks TO medflow_app;
+GRANT SELECT, INSERT, UPDATE, DELETE ON users.notification_rules TO medflow_app;
+GRANT SELECT, INSERT, UPDATE ON users.notifications TO medflow_app;
+GRANT SELECT, INSERT, UPDATE ON users.notification_deliveries TO medflow_app;
+GRANT SELECT, INSERT, UPDATE ON users.notification_inbox TO medflow_app;
@@ -0,0 +1,15 @@
+-- Rollback migration 000042: Remove the notification inbox stream
+
+DROP TRIGGER IF EXISTS notification_inbox_notify ON users.notification_inbox;
+DROP FUNCTION IF EXISTS users.notify_notification_inbox();
+
+DROP INDEX IF EXISTS users.idx_notification_inbox_dedup;
+DROP INDEX IF EXISTS users.idx_notification_inbox_stream;
+DROP INDEX IF EXISTS users.idx_notification_inbox_seq;
+
+ALTER TABLE users.notification_inbox
+    DROP COLUMN IF EXISTS entity_id,
+    DROP COLUMN IF EXISTS entity_type,
+    DROP COLUMN IF EXISTS required_permission,
+    DROP COLUMN IF EXISTS dedup_key,
+    DROP COLUMN IF EXISTS seq;
@@ -0,0 +1,40 @@
+-- MedFlow: Notification inbox and event stream
+-- The in-app inbox becomes the per-user notification feed the web app
+-- streams over Server-Sent Events. Besides routed alerts it receives
+-- inventory alerts, absence and correction request decisions and ArbZG
+-- warnings straight from the RabbitMQ exchanges. seq orders the feed and is
+-- the SSE event id a reconnecting client replays from (Last-Event-ID);
+-- every insert notifies the stream on the notification_inbox channel.
+
+ALTER TABLE users.notification_inbox
+    ADD COLUMN seq BIGINT GENERATED ALWAYS AS IDENTITY,
+    -- Identifies what an item is about (e.g. the alert condition), so an
+    -- event delivered twice, re-raised, or received by both the feed and an
+    -- alert rule shows once while the item is not archived
+    ADD COLUMN dedup_key VARCHAR(255),
+    -- Permission the user must still hold to see the item (e.g. staff.read
+    -- for a colleague's ArbZG warning); NULL for items about the user
+    ADD COLUMN required_permission VARCHAR(100),
+    -- Deep link target: item, cabinet, employee, time_entry, absence,
+    -- correction_request
+    ADD COLUMN entity_type VARCHAR(50),
+    ADD COLUMN entity_id UUID;
+
+CREATE UNIQUE INDEX idx_notification_inbox_seq ON users.notification_inbox(seq);
+CREATE INDEX idx_notification_inbox_stream ON users.notification_inbox(tenant_id, user_id, seq);
+CREATE UNIQUE INDEX idx_notification_inbox_dedup ON users.notification_inbox(tenant_id, user_id, dedup_key)
+    WHERE archived_at IS NULL AND dedup_key IS NOT NULL;
+
+CREATE OR REPLACE FUNCTION users.notify_notification_inbox()
+RETURNS trigger AS $$
+BEGIN
+    -- Delivered on commit; the payload only wakes the user's streams, which
+    -- read the items themselves under RLS
+    PERFORM pg_notify('notification_inbox', NEW.tenant_id::text || ':' || NEW.user_id::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER notification_inbox_notify
+    AFTER INSERT ON users.notification_inbox
+    FOR EACH ROW EXECUTE FUNCTION users.notify_notification_inbox();
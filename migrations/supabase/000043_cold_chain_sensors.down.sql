@@ -0,0 +1,14 @@
+-- Rollback migration 000043: Remove cold-chain sensors and temperature excursions
+
+DROP TABLE IF EXISTS inventory.temperature_excursion_batches;
+DROP TABLE IF EXISTS inventory.temperature_excursions;
+
+DROP INDEX IF EXISTS inventory.idx_temperature_readings_webhook_dedup;
+DROP INDEX IF EXISTS inventory.idx_temperature_readings_sensor_dedup;
+ALTER TABLE inventory.temperature_readings DROP COLUMN IF EXISTS sensor_id;
+
+DROP TABLE IF EXISTS public.temperature_sensor_lookup;
+DROP TABLE IF EXISTS inventory.temperature_sensors;
+
+ALTER TABLE inventory.storage_cabinets DROP CONSTRAINT IF EXISTS storage_cabinets_excursion_grace_valid;
+ALTER TABLE inventory.storage_cabinets DROP COLUMN IF EXISTS excursion_grace_minutes;
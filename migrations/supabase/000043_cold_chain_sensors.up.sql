@@ -0,0 +1,163 @@
+-- MedFlow: Cold-chain sensors and temperature excursions
+-- Data loggers authenticate per sensor with an HMAC-signed payload and send
+-- buffered readings in batches with their original timestamps. Readings out
+-- of a cabinet's range form excursion episodes; an episode that lasts longer
+-- than the cabinet's grace period (a door left open is shorter) is confirmed,
+-- quarantines the cooled batches stored in the cabinet and needs a documented
+-- release decision.
+
+-- ============================================================================
+-- 1. Grace period of a cabinet (NULL = service default of 15 minutes)
+-- ============================================================================
+ALTER TABLE inventory.storage_cabinets ADD COLUMN IF NOT EXISTS excursion_grace_minutes INTEGER;
+ALTER TABLE inventory.storage_cabinets ADD CONSTRAINT storage_cabinets_excursion_grace_valid
+    CHECK (excursion_grace_minutes IS NULL OR excursion_grace_minutes BETWEEN 0 AND 1440);
+
+-- ============================================================================
+-- 2. inventory.temperature_sensors
+-- ============================================================================
+CREATE TABLE inventory.temperature_sensors (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+    cabinet_id UUID NOT NULL REFERENCES inventory.storage_cabinets(id) ON DELETE CASCADE,
+
+    name VARCHAR(255) NOT NULL,
+    key_id VARCHAR(64) NOT NULL UNIQUE,     -- sent in X-MedFlow-Sensor
+    secret VARCHAR(128) NOT NULL,           -- HMAC-SHA256 key of X-MedFlow-Signature
+    last_seen_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+
+    created_by UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE inventory.temperature_sensors ENABLE ROW LEVEL SECURITY;
+ALTER TABLE inventory.temperature_sensors FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON inventory.temperature_sensors
+    FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+CREATE INDEX idx_temperature_sensors_tenant ON inventory.temperature_sensors(tenant_id);
+CREATE INDEX idx_temperature_sensors_cabinet ON inventory.temperature_sensors(cabinet_id);
+
+CREATE TRIGGER temperature_sensors_updated_at
+    BEFORE UPDATE ON inventory.temperature_sensors
+    FOR EACH ROW EXECUTE FUNCTION public.update_updated_at();
+
+-- ============================================================================
+-- 3. public.temperature_sensor_lookup (NO RLS)
+-- Resolves the tenant of a sensor request before a tenant context exists,
+-- like public.user_tenant_lookup does for logins. Holds no secrets.
+-- ============================================================================
+CREATE TABLE public.temperature_sensor_lookup (
+    key_id VARCHAR(64) PRIMARY KEY,
+    tenant_id UUID NOT NULL REFERENCES public.tenants(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_temperature_sensor_lookup_tenant ON public.temperature_sensor_lookup(tenant_id);
+
+COMMENT ON TABLE public.temperature_sensor_lookup IS
+    'Maps sensor key IDs to their tenant for signed sensor ingestion';
+
+-- ============================================================================
+-- 4. Readings: sensor and deduplication of re-sent readings
+-- ============================================================================
+ALTER TABLE inventory.temperature_readings
+    ADD COLUMN IF NOT EXISTS sensor_id UUID REFERENCES inventory.temperature_sensors(id);
+
+CREATE UNIQUE INDEX idx_temperature_readings_sensor_dedup
+    ON inventory.temperature_readings(sensor_id, recorded_at)
+    WHERE sensor_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_temperature_readings_webhook_dedup
+    ON inventory.temperature_readings(cabinet_id, recorded_at)
+    WHERE sensor_id IS NULL AND source = 'webhook';
+
+-- ============================================================================
+-- 5. inventory.temperature_excursions
+-- pending: still out of range, within the grace period
+-- transient: back in range within the grace period (e.g. door opened)
+-- confirmed: out of range longer than the grace period, awaiting a decision
+-- released / discarded: quarantined stock released or to be disposed of
+-- ============================================================================
+CREATE TABLE inventory.temperature_excursions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+    cabinet_id UUID NOT NULL REFERENCES inventory.storage_cabinets(id) ON DELETE CASCADE,
+
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    started_at TIMESTAMPTZ NOT NULL,        -- first reading out of range
+    ended_at TIMESTAMPTZ,                   -- first reading back in range; NULL while ongoing
+    last_reading_at TIMESTAMPTZ NOT NULL,   -- last reading out of range
+    duration_seconds INTEGER NOT NULL DEFAULT 0,
+    reading_count INTEGER NOT NULL DEFAULT 0,
+    min_temperature_celsius DECIMAL(5,2) NOT NULL,
+    max_temperature_celsius DECIMAL(5,2) NOT NULL,
+    allowed_min_celsius DECIMAL(5,2),       -- cabinet range when the excursion started
+    allowed_max_celsius DECIMAL(5,2),
+    grace_minutes INTEGER NOT NULL,
+    confirmed_at TIMESTAMPTZ,
+    alert_id UUID,
+
+    decision_reason TEXT,
+    decided_by UUID,
+    decided_at TIMESTAMPTZ,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT temperature_excursions_status_valid CHECK (
+        status IN ('pending', 'transient', 'confirmed', 'released', 'discarded')
+    ),
+    CONSTRAINT temperature_excursions_decision_documented CHECK (
+        status NOT IN ('released', 'discarded')
+        OR (decision_reason IS NOT NULL AND decided_by IS NOT NULL AND decided_at IS NOT NULL)
+    )
+);
+
+ALTER TABLE inventory.temperature_excursions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE inventory.temperature_excursions FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON inventory.temperature_excursions
+    FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+CREATE INDEX idx_temperature_excursions_tenant ON inventory.temperature_excursions(tenant_id, status);
+CREATE INDEX idx_temperature_excursions_cabinet ON inventory.temperature_excursions(cabinet_id, started_at DESC);
+-- At most one ongoing excursion per cabinet
+CREATE UNIQUE INDEX idx_temperature_excursions_ongoing
+    ON inventory.temperature_excursions(cabinet_id) WHERE ended_at IS NULL;
+
+CREATE TRIGGER temperature_excursions_updated_at
+    BEFORE UPDATE ON inventory.temperature_excursions
+    FOR EACH ROW EXECUTE FUNCTION public.update_updated_at();
+
+-- ============================================================================
+-- 6. inventory.temperature_excursion_batches
+-- Batches quarantined by a confirmed excursion and their status before
+-- ============================================================================
+CREATE TABLE inventory.temperature_excursion_batches (
+    excursion_id UUID NOT NULL REFERENCES inventory.temperature_excursions(id) ON DELETE CASCADE,
+    batch_id UUID NOT NULL REFERENCES inventory.inventory_batches(id) ON DELETE CASCADE,
+    tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+    previous_status VARCHAR(20) NOT NULL,
+    quarantined_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (excursion_id, batch_id)
+);
+
+ALTER TABLE inventory.temperature_excursion_batches ENABLE ROW LEVEL SECURITY;
+ALTER TABLE inventory.temperature_excursion_batches FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON inventory.temperature_excursion_batches
+    FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+CREATE INDEX idx_temperature_excursion_batches_batch ON inventory.temperature_excursion_batches(batch_id);
+
+GRANT SELECT, INSERT, UPDATE ON inventory.temperature_sensors TO medflow_app;
+GRANT SELECT, INSERT, DELETE ON public.temperature_sensor_lookup TO medflow_app;
+GRANT SELECT, INSERT, UPDATE ON inventory.temperature_excursions TO medflow_app;
+GRANT SELECT, INSERT ON inventory.temperature_excursion_batches TO medflow_app;
@@ -0,0 +1,7 @@
+-- Rollback migration 000044: Restore webhook-only deduplication
+
+DROP INDEX IF EXISTS inventory.idx_temperature_readings_unsigned_dedup;
+
+CREATE UNIQUE INDEX idx_temperature_readings_webhook_dedup
+    ON inventory.temperature_readings(cabinet_id, recorded_at)
+    WHERE sensor_id IS NULL AND source = 'webhook';
@@ -0,0 +1,11 @@
+-- MedFlow: Deduplicate MQTT sensor readings
+-- The MQTT subscriber records readings of fridge data loggers as source
+-- 'sensor' without a registered sensor. QoS 1 delivers at least once, so a
+-- reading redelivered after a reconnect must not be stored twice: extend the
+-- webhook deduplication to all readings without a sensor except manual ones.
+
+DROP INDEX IF EXISTS inventory.idx_temperature_readings_webhook_dedup;
+
+CREATE UNIQUE INDEX idx_temperature_readings_unsigned_dedup
+    ON inventory.temperature_readings(cabinet_id, recorded_at)
+    WHERE sensor_id IS NULL AND source IN ('webhook', 'sensor');
@@ -0,0 +1,7 @@
+-- Rollback migration 000045: Remove BtM monthly closing
+
+DROP TABLE IF EXISTS inventory.btm_period_balances;
+DROP TABLE IF EXISTS inventory.btm_period_closings;
+
+DROP INDEX IF EXISTS inventory.idx_btm_register_item_date;
+ALTER TABLE inventory.btm_register DROP COLUMN IF EXISTS entry_date;
@@ -0,0 +1,96 @@
+-- MedFlow: BtM monthly closing (BtMVV §13)
+-- Every register entry carries the date of the movement, which may lie before
+-- the day it was recorded. At the end of each month the responsible physician
+-- verifies the balances of all substances and signs the closing; the closing
+-- stores the reconciled balances with a SHA-256 seal and locks the period
+-- against entries dated on or before its last day. Months are closed in order.
+
+-- ============================================================================
+-- 1. Date of the movement on each register entry
+-- ============================================================================
+ALTER TABLE inventory.btm_register ADD COLUMN IF NOT EXISTS entry_date DATE;
+UPDATE inventory.btm_register
+    SET entry_date = (created_at AT TIME ZONE 'Europe/Berlin')::date
+    WHERE entry_date IS NULL;
+ALTER TABLE inventory.btm_register ALTER COLUMN entry_date SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_btm_register_item_date
+    ON inventory.btm_register(item_id, entry_date, entry_number);
+
+-- ============================================================================
+-- 2. inventory.btm_period_closings (append-only)
+-- ============================================================================
+CREATE TABLE inventory.btm_period_closings (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+
+    period_start DATE NOT NULL,
+    period_end DATE NOT NULL,
+
+    substance_count INTEGER NOT NULL DEFAULT 0,
+    difference_count INTEGER NOT NULL DEFAULT 0,
+    notes TEXT,                              -- required when differences were found
+
+    closed_by UUID NOT NULL,
+    closed_by_name VARCHAR(255) NOT NULL,
+    closed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    protocol_sha256 CHAR(64) NOT NULL,
+
+    CONSTRAINT btm_period_closings_period_valid CHECK (period_end >= period_start),
+    CONSTRAINT btm_period_closings_differences_explained CHECK (
+        difference_count = 0 OR (notes IS NOT NULL AND notes <> '')
+    ),
+    CONSTRAINT btm_period_closings_period_unique UNIQUE (tenant_id, period_start)
+);
+
+ALTER TABLE inventory.btm_period_closings ENABLE ROW LEVEL SECURITY;
+ALTER TABLE inventory.btm_period_closings FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY btm_period_closings_select ON inventory.btm_period_closings
+    FOR SELECT USING (tenant_id = current_setting('app.current_tenant')::uuid);
+
+CREATE POLICY btm_period_closings_insert ON inventory.btm_period_closings
+    FOR INSERT WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+CREATE INDEX idx_btm_period_closings_tenant_end
+    ON inventory.btm_period_closings(tenant_id, period_end DESC);
+
+GRANT SELECT, INSERT ON inventory.btm_period_closings TO medflow_app;
+
+-- ============================================================================
+-- 3. inventory.btm_period_balances (append-only)
+-- Reconciled balances of each substance at the time of the closing
+-- ============================================================================
+CREATE TABLE inventory.btm_period_balances (
+    closing_id UUID NOT NULL REFERENCES inventory.btm_period_closings(id),
+    tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+    item_id UUID NOT NULL REFERENCES inventory.inventory_items(id),
+
+    item_name VARCHAR(255) NOT NULL,
+    unit VARCHAR(50) NOT NULL,
+    opening_balance DECIMAL(12,4) NOT NULL,
+    receipts DECIMAL(12,4) NOT NULL,
+    dispensed DECIMAL(12,4) NOT NULL,
+    disposed DECIMAL(12,4) NOT NULL,
+    corrections DECIMAL(12,4) NOT NULL,
+    check_difference DECIMAL(12,4) NOT NULL,  -- sum of counted minus book balance
+    closing_balance DECIMAL(12,4) NOT NULL,
+    entry_count INTEGER NOT NULL,
+    check_count INTEGER NOT NULL,
+    difference_count INTEGER NOT NULL,
+
+    PRIMARY KEY (closing_id, item_id)
+);
+
+ALTER TABLE inventory.btm_period_balances ENABLE ROW LEVEL SECURITY;
+ALTER TABLE inventory.btm_period_balances FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY btm_period_balances_select ON inventory.btm_period_balances
+    FOR SELECT USING (tenant_id = current_setting('app.current_tenant')::uuid);
+
+CREATE POLICY btm_period_balances_insert ON inventory.btm_period_balances
+    FOR INSERT WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+CREATE INDEX idx_btm_period_balances_item ON inventory.btm_period_balances(item_id);
+
+GRANT SELECT, INSERT ON inventory.btm_period_balances TO medflow_app;
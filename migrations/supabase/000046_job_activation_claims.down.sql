@@ -0,0 +1,4 @@
+-- Rollback migration 000046: Remove background job activation claims
+
+ALTER TABLE public.job_runs DROP COLUMN IF EXISTS scheduled_at;
+DROP TABLE IF EXISTS public.job_activations;
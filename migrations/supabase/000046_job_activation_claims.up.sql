@@ -0,0 +1,24 @@
+-- MedFlow: Background Job Activation Claims
+-- The advisory lock in pkg/jobs only serialises runs: a replica whose timer
+-- fires after a fast run finished elsewhere would run the same activation
+-- again. Each scheduled activation is now claimed once, under the lock,
+-- before it runs.
+
+-- ============================================================================
+-- public.job_activations
+-- ============================================================================
+CREATE TABLE public.job_activations (
+    job_name VARCHAR(100) NOT NULL,
+    scheduled_at TIMESTAMPTZ NOT NULL,
+    instance_id VARCHAR(100),
+    claimed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (job_name, scheduled_at)
+);
+
+CREATE INDEX idx_job_activations_claimed ON public.job_activations(claimed_at);
+
+-- Run history records the activation a scheduled run belongs to
+ALTER TABLE public.job_runs ADD COLUMN scheduled_at TIMESTAMPTZ;
+
+GRANT SELECT, INSERT, DELETE ON public.job_activations TO medflow_app;
@@ -0,0 +1,3 @@
+-- Rollback migration 000047: Remove the stocktake protocol seal
+
+ALTER TABLE inventory.stocktakes DROP COLUMN IF EXISTS protocol_seal;
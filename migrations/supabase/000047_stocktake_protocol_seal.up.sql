@@ -0,0 +1,7 @@
+-- MedFlow: Stocktake Protocol Seal
+-- The SHA-256 digest printed on a stocktake protocol can be recomputed by
+-- anyone who edits the data. The seal is the HMAC of that digest with a key
+-- only the inventory service holds (MEDFLOW_SEAL_KEY), so a protocol can be
+-- verified against the server.
+
+ALTER TABLE inventory.stocktakes ADD COLUMN protocol_seal CHAR(64);
@@ -0,0 +1,13 @@
+-- Rollback migration 000048: Restore the item list indexes of 000005
+
+DROP INDEX IF EXISTS inventory.idx_inventory_batches_item_expiry;
+
+CREATE INDEX IF NOT EXISTS idx_inventory_items_name ON inventory.inventory_items(name) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_inventory_items_category ON inventory.inventory_items(tenant_id, category) WHERE deleted_at IS NULL;
+
+DROP INDEX IF EXISTS inventory.idx_inventory_items_keyset_updated;
+DROP INDEX IF EXISTS inventory.idx_inventory_items_keyset_created;
+DROP INDEX IF EXISTS inventory.idx_inventory_items_keyset_supplier;
+DROP INDEX IF EXISTS inventory.idx_inventory_items_keyset_manufacturer;
+DROP INDEX IF EXISTS inventory.idx_inventory_items_keyset_category;
+DROP INDEX IF EXISTS inventory.idx_inventory_items_keyset_name;
@@ -0,0 +1,36 @@
+-- MedFlow: Keyset Indexes for the Item List
+-- GET /items pages with a keyset on (sort field, id) under RLS, i.e.
+-- WHERE tenant_id = <tenant> AND deleted_at IS NULL ORDER BY <field>, id.
+-- Without a matching index every page sorts all of the tenant's items.
+-- Nullable sort fields are ordered as COALESCE(<field>, <sentinel>) (see
+-- pkg/database/list.go), so their indexes are on that expression.
+--
+-- expiry_date is the earliest expiry of the item's available batches. It is
+-- read with a LATERAL LIMIT 1 lookup per item that the batch index below
+-- answers from its first entry.
+
+-- ============================================================================
+-- inventory.inventory_items: one index per sortable field
+-- ============================================================================
+CREATE INDEX IF NOT EXISTS idx_inventory_items_keyset_name
+    ON inventory.inventory_items(tenant_id, name, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_inventory_items_keyset_category
+    ON inventory.inventory_items(tenant_id, category, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_inventory_items_keyset_manufacturer
+    ON inventory.inventory_items(tenant_id, COALESCE(manufacturer, ''), id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_inventory_items_keyset_supplier
+    ON inventory.inventory_items(tenant_id, COALESCE(supplier, ''), id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_inventory_items_keyset_created
+    ON inventory.inventory_items(tenant_id, created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_inventory_items_keyset_updated
+    ON inventory.inventory_items(tenant_id, updated_at, id) WHERE deleted_at IS NULL;
+
+-- Superseded by the keyset indexes above
+DROP INDEX IF EXISTS inventory.idx_inventory_items_name;
+DROP INDEX IF EXISTS inventory.idx_inventory_items_category;
+
+-- ============================================================================
+-- inventory.inventory_batches: earliest expiry per item
+-- ============================================================================
+CREATE INDEX IF NOT EXISTS idx_inventory_batches_item_expiry
+    ON inventory.inventory_batches(item_id, expiry_date) WHERE status = 'available' AND deleted_at IS NULL;
@@ -0,0 +1,25 @@
+-- Rollback migration 000049: Move the retention tables back to inventory
+
+DROP FUNCTION IF EXISTS public.seed_retention_policies(UUID);
+
+ALTER TABLE public.deletion_protocols SET SCHEMA inventory;
+ALTER TABLE public.legal_holds SET SCHEMA inventory;
+ALTER TABLE public.retention_policies SET SCHEMA inventory;
+
+CREATE OR REPLACE FUNCTION inventory.seed_retention_policies(p_tenant_id UUID)
+RETURNS void AS $$
+    INSERT INTO inventory.retention_policies
+        (tenant_id, entity_type, retention_years, legal_basis, description, purge_action)
+    VALUES
+        (p_tenant_id, 'device_book', 20, 'MPBetreibV §12', 'Medizinproduktebuch entries', 'none'),
+        (p_tenant_id, 'btm_register', 3, 'BtMG §13 Abs. 3', 'Betäubungsmittelbuch entries', 'none'),
+        (p_tenant_id, 'sterilization', 15, 'RKI/KRINKO', 'Sterilization batch records', 'none'),
+        (p_tenant_id, 'working_time', 2, 'ArbZG §16', 'Working time records', 'delete'),
+        (p_tenant_id, 'radiation', 30, 'StrlSchV §85', 'Dosimetry and radiation records', 'anonymize'),
+        (p_tenant_id, 'tax_documents', 10, 'AO §147', 'Tax-relevant inventory documents', 'none'),
+        (p_tenant_id, 'hazardous_substances', 40, 'GefStoffV §14', 'Gefahrstoffverzeichnis entries', 'none'),
+        (p_tenant_id, 'biosafety', 10, 'BioStoffV §7 Abs. 3', 'Biological agent assessments and trainings', 'anonymize'),
+        (p_tenant_id, 'hygiene', 10, 'IfSG §23 Abs. 4', 'Hygiene plans and inspections', 'none'),
+        (p_tenant_id, 'personnel_file', 3, 'BGB §195, DSGVO Art. 17', 'Personal data of former employees', 'delete')
+    ON CONFLICT (tenant_id, entity_type) DO NOTHING;
+$$ LANGUAGE sql;
@@ -0,0 +1,37 @@
+-- MedFlow: Shared retention tables
+-- Retention policies, legal holds and deletion protocols cover records of
+-- every service (pkg/retention guards and purges staff records too), so
+-- they move from the inventory schema to public. Rows, RLS policies,
+-- indexes, triggers and grants move with the tables; the inventory service
+-- finds them through its search_path.
+
+-- ============================================================================
+-- 1. Tables
+-- ============================================================================
+ALTER TABLE inventory.retention_policies SET SCHEMA public;
+ALTER TABLE inventory.legal_holds SET SCHEMA public;
+ALTER TABLE inventory.deletion_protocols SET SCHEMA public;
+
+-- ============================================================================
+-- 2. Default policies for new tenants
+-- The function body names the table, so it is recreated next to it
+-- ============================================================================
+DROP FUNCTION IF EXISTS inventory.seed_retention_policies(UUID);
+
+CREATE OR REPLACE FUNCTION public.seed_retention_policies(p_tenant_id UUID)
+RETURNS void AS $$
+    INSERT INTO public.retention_policies
+        (tenant_id, entity_type, retention_years, legal_basis, description, purge_action)
+    VALUES
+        (p_tenant_id, 'device_book', 20, 'MPBetreibV §12', 'Medizinproduktebuch entries', 'none'),
+        (p_tenant_id, 'btm_register', 3, 'BtMG §13 Abs. 3', 'Betäubungsmittelbuch entries', 'none'),
+        (p_tenant_id, 'sterilization', 15, 'RKI/KRINKO', 'Sterilization batch records', 'none'),
+        (p_tenant_id, 'working_time', 2, 'ArbZG §16', 'Working time records', 'delete'),
+        (p_tenant_id, 'radiation', 30, 'StrlSchV §85', 'Dosimetry and radiation records', 'anonymize'),
+        (p_tenant_id, 'tax_documents', 10, 'AO §147', 'Tax-relevant inventory documents', 'none'),
+        (p_tenant_id, 'hazardous_substances', 40, 'GefStoffV §14', 'Gefahrstoffverzeichnis entries', 'none'),
+        (p_tenant_id, 'biosafety', 10, 'BioStoffV §7 Abs. 3', 'Biological agent assessments and trainings', 'anonymize'),
+        (p_tenant_id, 'hygiene', 10, 'IfSG §23 Abs. 4', 'Hygiene plans and inspections', 'none'),
+        (p_tenant_id, 'personnel_file', 3, 'BGB §195, DSGVO Art. 17', 'Personal data of former employees', 'delete')
+    ON CONFLICT (tenant_id, entity_type) DO NOTHING;
+$$ LANGUAGE sql;
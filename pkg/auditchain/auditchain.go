@@ -0,0 +1,72 @@
+// Package auditchain makes the append-only GoBD audit trails tamper-evident.
+//
+// Every entry of a tenant's trail carries a sequence number and the SHA-256
+// hash of its content chained to the hash of the previous entry. Changing,
+// deleting or reordering an entry breaks the chain from that entry on, which
+// Verifier reports. The chain head of each tenant lives in
+// public.audit_chain_heads; writers lock it, so concurrent inserts get
+// consecutive sequence numbers. A per-tenant job anchors the head in
+// public.tenant_audit_log, so that rewriting a whole chain is detected too.
+//
+// Usage (inside WithTenantRLS):
+//
+//	head, err := chain.Lock(ctx, tenantID)
+//	entry := &auditchain.Entry{Sequence: head.Sequence + 1, CreatedAt: auditchain.Now(), ...}
+//	content, err := entry.Content(tenantID)
+//	next := head.Next(content)
+//	// INSERT the entry with next.Sequence, head.Hash and next.Hash
+//	err = chain.Advance(ctx, tenantID, next)
+//
+// Chain.Verify re-walks a chain with the entries an EntryLoader reads.
+package auditchain
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+)
+
+// GenesisHash is the previous hash of the first entry of a chain
+var GenesisHash = strings.Repeat("0", 64)
+
+// Link is a position in a chain: an entry's sequence number and hash
+type Link struct {
+	Sequence int64  `db:"sequence" json:"sequence"`
+	Hash     string `db:"hash" json:"hash"`
+}
+
+// Next returns the link of the entry after l with the given content
+func (l Link) Next(content []byte) Link {
+	return Link{Sequence: l.Sequence + 1, Hash: Hash(l.Hash, content)}
+}
+
+// Hash returns the hex SHA-256 of an entry's content chained to prevHash
+func Hash(prevHash string, content []byte) string {
+	h := sha256.New()
+	h.Write([]byte(prevHash))
+	h.Write([]byte{'\n'})
+	h.Write(content)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// CanonicalJSON returns a JSON document in a form that survives a round trip
+// through a JSONB column (which reorders keys and drops whitespace), or nil
+// for a nil document
+func CanonicalJSON(doc *string) (json.RawMessage, error) {
+	if doc == nil {
+		return nil, nil
+	}
+	var v interface{}
+	if err := json.Unmarshal([]byte(*doc), &v); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
@@ -0,0 +1,212 @@
+package auditchain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHash(t *testing.T) {
+	h := Hash(GenesisHash, []byte(`{"sequence":1}`))
+	assert.Len(t, h, 64)
+	assert.Equal(t, h, Hash(GenesisHash, []byte(`{"sequence":1}`)))
+	assert.NotEqual(t, h, Hash(GenesisHash, []byte(`{"sequence":2}`)))
+	assert.NotEqual(t, h, Hash(h, []byte(`{"sequence":1}`)))
+}
+
+func TestCanonicalJSON(t *testing.T) {
+	got, err := CanonicalJSON(nil)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	// JSONB reorders keys and drops whitespace
+	written := `{"quantity": {"old": 5, "new": 3}, "note": "<a & b>"}`
+	stored := `{"note": "<a & b>", "quantity": {"new": 3, "old": 5}}`
+	a, err := CanonicalJSON(&written)
+	require.NoError(t, err)
+	b, err := CanonicalJSON(&stored)
+	require.NoError(t, err)
+	assert.Equal(t, string(a), string(b))
+	assert.Equal(t, `{"note":"<a & b>","quantity":{"new":3,"old":5}}`, string(a))
+
+	invalid := `{`
+	_, err = CanonicalJSON(&invalid)
+	assert.Error(t, err)
+}
+
+func TestEntryContent(t *testing.T) {
+	written := `{"status": {"old": "draft", "new": "approved"}}`
+	stored := `{"status": {"new": "approved", "old": "draft"}}`
+	createdAt := time.Date(2026, 3, 31, 23, 30, 0, 123456000, time.UTC)
+
+	e := &Entry{
+		Sequence: 7, ID: "a", EntityType: "time_entry", EntityID: "b", Action: "update",
+		FieldChanges: &written, CreatedAt: createdAt.In(time.FixedZone("CEST", 2*3600)),
+	}
+	a, err := e.Content("tenant-1")
+	require.NoError(t, err)
+
+	// The same entry as read back from the database hashes the same
+	e.FieldChanges, e.CreatedAt = &stored, createdAt
+	b, err := e.Content("tenant-1")
+	require.NoError(t, err)
+	assert.Equal(t, string(a), string(b))
+	assert.Contains(t, string(a), `"created_at":"2026-03-31T23:30:00.123456Z"`)
+	assert.NotContains(t, string(a), "employee_id")
+
+	// The sequence, the tenant and the employee are part of the content
+	other, err := e.Content("tenant-2")
+	require.NoError(t, err)
+	assert.NotEqual(t, string(a), string(other))
+	employee := "c"
+	e.EmployeeID = &employee
+	withEmployee, err := e.Content("tenant-1")
+	require.NoError(t, err)
+	assert.Contains(t, string(withEmployee), `"employee_id":"c"`)
+}
+
+func TestCanonicalUUID(t *testing.T) {
+	assert.Equal(t, "0f8fad5b-d9cb-469f-a165-70867728950e", CanonicalUUID("0F8FAD5B-D9CB-469F-A165-70867728950E"))
+	assert.Equal(t, "0f8fad5b-d9cb-469f-a165-70867728950e", CanonicalUUID("{0f8fad5b-d9cb-469f-a165-70867728950e}"))
+	assert.Equal(t, "not-a-uuid", CanonicalUUID("not-a-uuid"))
+
+	assert.Nil(t, CanonicalUUIDPtr(nil))
+	upper := "0F8FAD5B-D9CB-469F-A165-70867728950E"
+	assert.Equal(t, "0f8fad5b-d9cb-469f-a165-70867728950e", *CanonicalUUIDPtr(&upper))
+}
+
+func TestNow(t *testing.T) {
+	now := Now()
+	assert.Equal(t, time.UTC, now.Location())
+	assert.Zero(t, now.Nanosecond()%1000)
+}
+
+type testEntry struct {
+	id       string
+	sequence int64
+	prevHash string
+	hash     string
+	content  []byte
+}
+
+func buildChain(contents ...string) ([]testEntry, Link) {
+	head := Link{Hash: GenesisHash}
+	entries := make([]testEntry, 0, len(contents))
+	for i, c := range contents {
+		next := head.Next([]byte(c))
+		entries = append(entries, testEntry{
+			id: string(rune('a' + i)), sequence: next.Sequence, prevHash: head.Hash, hash: next.Hash, content: []byte(c),
+		})
+		head = next
+	}
+	return entries, head
+}
+
+func verify(entries []testEntry, head Link, anchor *Anchor) *Result {
+	v := NewVerifier("inventory.audit_trail", anchor)
+	for _, e := range entries {
+		if !v.Add(e.id, e.sequence, e.prevHash, e.hash, e.content) {
+			break
+		}
+	}
+	return v.Finish(head)
+}
+
+func TestVerifier_Valid(t *testing.T) {
+	entries, head := buildChain("one", "two", "three")
+	result := verify(entries, head, &Anchor{Link: Link{Sequence: 2, Hash: entries[1].hash}})
+	assert.True(t, result.Valid)
+	assert.Nil(t, result.Break)
+	assert.Equal(t, int64(3), result.Entries)
+	assert.Equal(t, head, *result.Head)
+
+	empty := verify(nil, Link{Hash: GenesisHash}, nil)
+	assert.True(t, empty.Valid)
+}
+
+func TestVerifier_Breaks(t *testing.T) {
+	tests := []struct {
+		name     string
+		tamper   func(entries []testEntry, head Link) ([]testEntry, Link, *Anchor)
+		reason   string
+		sequence int64
+	}{
+		{
+			name: "changed content",
+			tamper: func(e []testEntry, head Link) ([]testEntry, Link, *Anchor) {
+				e[1].content = []byte("TWO")
+				return e, head, nil
+			},
+			reason: BreakHash, sequence: 2,
+		},
+		{
+			name: "deleted entry",
+			tamper: func(e []testEntry, head Link) ([]testEntry, Link, *Anchor) {
+				return append(e[:1], e[2:]...), head, nil
+			},
+			reason: BreakSequence, sequence: 3,
+		},
+		{
+			name: "deleted and renumbered",
+			tamper: func(e []testEntry, head Link) ([]testEntry, Link, *Anchor) {
+				e[2].sequence = 2
+				return append(e[:1], e[2]), head, nil
+			},
+			reason: BreakPrevHash, sequence: 2,
+		},
+		{
+			name: "truncated end",
+			tamper: func(e []testEntry, head Link) ([]testEntry, Link, *Anchor) {
+				return e[:2], head, nil
+			},
+			reason: BreakHead, sequence: 3,
+		},
+		{
+			name: "rewritten chain",
+			tamper: func(e []testEntry, head Link) ([]testEntry, Link, *Anchor) {
+				anchor := &Anchor{Link: Link{Sequence: 2, Hash: e[1].hash}}
+				rewritten, newHead := buildChain("one", "TWO", "three")
+				return rewritten, newHead, anchor
+			},
+			reason: BreakAnchor, sequence: 2,
+		},
+		{
+			name: "rewritten and truncated before anchor",
+			tamper: func(e []testEntry, head Link) ([]testEntry, Link, *Anchor) {
+				anchor := &Anchor{Link: head}
+				rewritten, newHead := buildChain("one")
+				return rewritten, newHead, anchor
+			},
+			reason: BreakSequence, sequence: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, head := buildChain("one", "two", "three")
+			entries, head, anchor := tt.tamper(entries, head)
+
+			result := verify(entries, head, anchor)
+			assert.False(t, result.Valid)
+			require.NotNil(t, result.Break)
+			assert.Equal(t, tt.reason, result.Break.Reason)
+			assert.Equal(t, tt.sequence, result.Break.Sequence)
+		})
+	}
+}
+
+func TestVerifier_StopsAtFirstBreak(t *testing.T) {
+	entries, head := buildChain("one", "two", "three")
+	entries[0].content = []byte("ONE")
+	entries[2].content = []byte("THREE")
+
+	v := NewVerifier("inventory.audit_trail", nil)
+	assert.False(t, v.Add(entries[0].id, entries[0].sequence, entries[0].prevHash, entries[0].hash, entries[0].content))
+	assert.False(t, v.Add(entries[1].id, entries[1].sequence, entries[1].prevHash, entries[1].hash, entries[1].content))
+	result := v.Finish(head)
+	assert.Equal(t, int64(1), result.Break.Sequence)
+	assert.Equal(t, "a", result.Break.EntryID)
+	assert.Equal(t, int64(0), result.Entries)
+}
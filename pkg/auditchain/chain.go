@@ -0,0 +1,168 @@
+package auditchain
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/medflow/medflow-backend/pkg/tenant"
+)
+
+// AnchorEventType is the tenant_audit_log event type of chain anchors
+const AnchorEventType = "audit_chain_anchored"
+
+// Anchor is a chain head recorded in public.tenant_audit_log
+type Anchor struct {
+	Link
+	AnchoredAt time.Time `json:"anchored_at"`
+}
+
+// anchorData is the event_data of an anchor event
+type anchorData struct {
+	Chain    string `json:"chain"`
+	Sequence int64  `json:"sequence"`
+	Hash     string `json:"hash"`
+}
+
+// Chain is the hash chain of one audit trail, e.g. "inventory.audit_trail"
+type Chain struct {
+	db     *database.DB
+	name   string
+	logger *logger.Logger
+}
+
+// New creates the chain with the given name
+func New(db *database.DB, name string, log *logger.Logger) *Chain {
+	return &Chain{db: db, name: name, logger: log}
+}
+
+// Name returns the name of the chain
+func (c *Chain) Name() string {
+	return c.name
+}
+
+// Lock returns the head of the tenant's chain and locks it until the
+// transaction in ctx ends; an empty chain's head is sequence 0 with the
+// genesis hash
+func (c *Chain) Lock(ctx context.Context, tenantID string) (Link, error) {
+	_, err := c.db.ExecContext(ctx, `
+		INSERT INTO public.audit_chain_heads (tenant_id, chain, sequence, hash)
+		VALUES ($1, $2, 0, $3)
+		ON CONFLICT (tenant_id, chain) DO NOTHING
+	`, tenantID, c.name, GenesisHash)
+	if err != nil {
+		return Link{}, err
+	}
+
+	var head Link
+	err = c.db.GetContext(ctx, &head, `
+		SELECT sequence, hash FROM public.audit_chain_heads
+		WHERE tenant_id = $1 AND chain = $2
+		FOR UPDATE
+	`, tenantID, c.name)
+	return head, err
+}
+
+// Advance moves the locked head of the tenant's chain to next
+func (c *Chain) Advance(ctx context.Context, tenantID string, next Link) error {
+	_, err := c.db.ExecContext(ctx, `
+		UPDATE public.audit_chain_heads
+		SET sequence = $3, hash = $4, updated_at = NOW()
+		WHERE tenant_id = $1 AND chain = $2
+	`, tenantID, c.name, next.Sequence, next.Hash)
+	return err
+}
+
+// Head returns the head of the tenant's chain without locking it
+func (c *Chain) Head(ctx context.Context, tenantID string) (Link, error) {
+	var head Link
+	err := c.db.GetContext(ctx, &head, `
+		SELECT sequence, hash FROM public.audit_chain_heads
+		WHERE tenant_id = $1 AND chain = $2
+	`, tenantID, c.name)
+	if err == sql.ErrNoRows {
+		return Link{Hash: GenesisHash}, nil
+	}
+	return head, err
+}
+
+// LatestAnchor returns the latest anchor of the tenant's chain, or nil
+func (c *Chain) LatestAnchor(ctx context.Context, tenantID string) (*Anchor, error) {
+	var row struct {
+		Data       []byte    `db:"event_data"`
+		AnchoredAt time.Time `db:"performed_at"`
+	}
+	err := c.db.GetContext(ctx, &row, `
+		SELECT event_data, performed_at FROM public.tenant_audit_log
+		WHERE tenant_id = $1 AND event_type = $2 AND event_data->>'chain' = $3
+		ORDER BY performed_at DESC, (event_data->>'sequence')::bigint DESC
+		LIMIT 1
+	`, tenantID, AnchorEventType, c.name)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var data anchorData
+	if err := json.Unmarshal(row.Data, &data); err != nil {
+		return nil, err
+	}
+	return &Anchor{Link: Link{Sequence: data.Sequence, Hash: data.Hash}, AnchoredAt: row.AnchoredAt}, nil
+}
+
+// Anchor records the head of the chain of the tenant in ctx in
+// public.tenant_audit_log unless it is already anchored. It is a per-tenant
+// job function.
+// TENANT-ISOLATED: Reads the head via RLS
+func (c *Chain) Anchor(ctx context.Context) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return c.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		head, err := c.Head(ctx, tenantID)
+		if err != nil {
+			return err
+		}
+		if head.Sequence == 0 {
+			return nil
+		}
+		latest, err := c.LatestAnchor(ctx, tenantID)
+		if err != nil {
+			return err
+		}
+		if latest != nil && latest.Link == head {
+			return nil
+		}
+
+		data, err := json.Marshal(anchorData{Chain: c.name, Sequence: head.Sequence, Hash: head.Hash})
+		if err != nil {
+			return err
+		}
+		_, err = c.db.ExecContext(ctx, `
+			INSERT INTO public.tenant_audit_log (tenant_id, event_type, event_data)
+			VALUES ($1, $2, $3)
+		`, tenantID, AnchorEventType, data)
+		if err != nil {
+			return err
+		}
+
+		c.logger.Info().
+			Str("tenant_id", tenantID).
+			Str("chain", c.name).
+			Int64("sequence", head.Sequence).
+			Msg("audit chain anchored")
+		return nil
+	})
+}
+
+func itoa(n int64) string {
+	return strconv.FormatInt(n, 10)
+}
@@ -0,0 +1,99 @@
+package auditchain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Entry is an audit trail entry as the chain sees it: its link and the
+// content that is hashed
+type Entry struct {
+	Sequence        int64
+	PrevHash        string
+	Hash            string
+	ID              string
+	EntityType      string
+	EntityID        string
+	EmployeeID      *string // staff audit trail only
+	Action          string
+	FieldChanges    *string // JSON document
+	Metadata        *string // JSON document
+	PerformedBy     *string
+	PerformedByName *string
+	IPAddress       *string
+	CreatedAt       time.Time
+}
+
+// content is the hashed content of an entry. Its fields are marshaled in
+// this order; changing them invalidates every existing chain.
+type content struct {
+	Sequence        int64           `json:"sequence"`
+	TenantID        string          `json:"tenant_id"`
+	ID              string          `json:"id"`
+	EntityType      string          `json:"entity_type"`
+	EntityID        string          `json:"entity_id"`
+	EmployeeID      *string         `json:"employee_id,omitempty"`
+	Action          string          `json:"action"`
+	FieldChanges    json.RawMessage `json:"field_changes"`
+	Metadata        json.RawMessage `json:"metadata"`
+	PerformedBy     *string         `json:"performed_by"`
+	PerformedByName *string         `json:"performed_by_name"`
+	IPAddress       *string         `json:"ip_address"`
+	CreatedAt       string          `json:"created_at"`
+}
+
+// Content returns the hashed content of the entry in the tenant's chain. It
+// is stable across a round trip through the database: JSON documents are
+// canonicalized and the timestamp is written in UTC.
+func (e *Entry) Content(tenantID string) ([]byte, error) {
+	fieldChanges, err := CanonicalJSON(e.FieldChanges)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := CanonicalJSON(e.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(content{
+		Sequence:        e.Sequence,
+		TenantID:        tenantID,
+		ID:              e.ID,
+		EntityType:      e.EntityType,
+		EntityID:        e.EntityID,
+		EmployeeID:      e.EmployeeID,
+		Action:          e.Action,
+		FieldChanges:    fieldChanges,
+		Metadata:        metadata,
+		PerformedBy:     e.PerformedBy,
+		PerformedByName: e.PerformedByName,
+		IPAddress:       e.IPAddress,
+		CreatedAt:       e.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+}
+
+// CanonicalUUID returns id as PostgreSQL returns a UUID column, so the hash
+// of an entry does not depend on how its ids were written. Other strings are
+// returned unchanged.
+func CanonicalUUID(id string) string {
+	if parsed, err := uuid.Parse(id); err == nil {
+		return parsed.String()
+	}
+	return id
+}
+
+// CanonicalUUIDPtr is CanonicalUUID for an optional id
+func CanonicalUUIDPtr(id *string) *string {
+	if id == nil {
+		return nil
+	}
+	s := CanonicalUUID(*id)
+	return &s
+}
+
+// Now returns the creation time of a new entry. Timestamps are hashed, so
+// they are taken at the microsecond precision a TIMESTAMPTZ column stores.
+func Now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
@@ -0,0 +1,155 @@
+package auditchain
+
+import (
+	"context"
+	"time"
+)
+
+// Reasons a chain breaks
+const (
+	// BreakSequence: an entry is missing or the entries were reordered
+	BreakSequence = "sequence_gap"
+	// BreakPrevHash: an entry does not point to the previous entry's hash
+	BreakPrevHash = "prev_hash_mismatch"
+	// BreakHash: an entry's content does not match its hash
+	BreakHash = "hash_mismatch"
+	// BreakAnchor: the anchored entry's hash differs from the anchor, i.e.
+	// the chain was rewritten up to and including it
+	BreakAnchor = "anchor_mismatch"
+	// BreakHead: the last entry is not the chain head, i.e. entries at the
+	// end were deleted or the head was changed
+	BreakHead = "head_mismatch"
+)
+
+// Break is the first entry at which a chain does not verify
+type Break struct {
+	Sequence int64  `json:"sequence"`
+	EntryID  string `json:"entry_id,omitempty"`
+	Reason   string `json:"reason"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+}
+
+// Result is the outcome of verifying a tenant's chain
+type Result struct {
+	Chain      string    `json:"chain"`
+	Valid      bool      `json:"valid"`
+	Entries    int64     `json:"entries"`
+	Head       *Link     `json:"head,omitempty"`
+	Anchor     *Anchor   `json:"anchor,omitempty"`
+	Break      *Break    `json:"break,omitempty"`
+	VerifiedAt time.Time `json:"verified_at"`
+}
+
+// Verifier re-walks a chain entry by entry, in sequence order
+type Verifier struct {
+	result *Result
+	last   Link
+}
+
+// NewVerifier starts verifying chain; anchor is the latest anchor of the
+// tenant's chain, or nil
+func NewVerifier(chain string, anchor *Anchor) *Verifier {
+	return &Verifier{
+		result: &Result{Chain: chain, Valid: true, Anchor: anchor},
+		last:   Link{Hash: GenesisHash},
+	}
+}
+
+// Add checks the next entry; it returns false at the first break, after
+// which further entries are ignored
+func (v *Verifier) Add(entryID string, sequence int64, prevHash, hash string, content []byte) bool {
+	if !v.result.Valid {
+		return false
+	}
+
+	switch want := v.last.Sequence + 1; {
+	case sequence != want:
+		return v.fail(sequence, entryID, BreakSequence, itoa(want), itoa(sequence))
+	case prevHash != v.last.Hash:
+		return v.fail(sequence, entryID, BreakPrevHash, v.last.Hash, prevHash)
+	}
+	if computed := Hash(prevHash, content); hash != computed {
+		return v.fail(sequence, entryID, BreakHash, computed, hash)
+	}
+	if a := v.result.Anchor; a != nil && a.Sequence == sequence && a.Hash != hash {
+		return v.fail(sequence, entryID, BreakAnchor, a.Hash, hash)
+	}
+
+	v.last = Link{Sequence: sequence, Hash: hash}
+	v.result.Entries++
+	return true
+}
+
+// Finish compares the last verified entry with the chain head and the
+// anchor and returns the result
+func (v *Verifier) Finish(head Link) *Result {
+	v.result.Head = &head
+	v.result.VerifiedAt = time.Now()
+	if !v.result.Valid {
+		return v.result
+	}
+
+	if a := v.result.Anchor; a != nil && a.Sequence > v.last.Sequence {
+		v.fail(v.last.Sequence+1, "", BreakSequence, itoa(a.Sequence), itoa(v.last.Sequence))
+		return v.result
+	}
+	if head != v.last {
+		v.fail(head.Sequence, "", BreakHead, head.Hash, v.last.Hash)
+	}
+	return v.result
+}
+
+func (v *Verifier) fail(sequence int64, entryID, reason, expected, actual string) bool {
+	v.result.Valid = false
+	v.result.Break = &Break{Sequence: sequence, EntryID: entryID, Reason: reason, Expected: expected, Actual: actual}
+	return false
+}
+
+// verifyBatch is the number of entries Verify loads at a time
+const verifyBatch = 1000
+
+// EntryLoader loads up to limit entries of a tenant's trail with a sequence
+// in (after, upto], in sequence order
+type EntryLoader func(ctx context.Context, after, upto int64, limit int) ([]*Entry, error)
+
+// Verify re-walks the tenant's chain and reports the first entry that was
+// changed, deleted or reordered, checking the chain head and its latest
+// anchor as well. Call it inside WithTenantRLS. Head and anchor are read
+// first: entries committed during the walk are beyond the head and not
+// loaded.
+func (c *Chain) Verify(ctx context.Context, tenantID string, load EntryLoader) (*Result, error) {
+	head, err := c.Head(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	anchor, err := c.LatestAnchor(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	verifier := NewVerifier(c.name, anchor)
+	var after int64
+	for {
+		entries, err := load(ctx, after, head.Sequence, verifyBatch)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, e := range entries {
+			content, err := e.Content(tenantID)
+			if err != nil {
+				return nil, err
+			}
+			if !verifier.Add(e.ID, e.Sequence, e.PrevHash, e.Hash, content) {
+				return verifier.Finish(head), nil
+			}
+			after = e.Sequence
+		}
+		if len(entries) < verifyBatch {
+			break
+		}
+	}
+
+	return verifier.Finish(head), nil
+}
@@ -0,0 +1,236 @@
+// Package barcode decodes the codes printed on medical products and drug
+// packs into product identifier, batch, expiry and serial number.
+//
+// Supported formats:
+//   - GS1 element strings (GS1 DataMatrix, GS1-128, GS1 QR) with FNC1/GS
+//     separators or in the human readable "(01)...(17)..." form. The GTIN
+//     is the EU MDR UDI-DI; batch, expiry and serial make up the UDI-PI.
+//   - IFA / securPharm codes on German drug packs (ISO/IEC 15434 with the
+//     PPN, which contains the PZN)
+//   - HIBC (Health Industry Bar Code) primary and secondary data
+//   - plain EAN/GTIN and Code 39 PZN ("-12345678") barcodes
+package barcode
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Formats of a decoded code
+const (
+	FormatGS1   = "gs1"
+	FormatIFA   = "ifa" // securPharm / ISO/IEC 15434 with the PPN
+	FormatHIBC  = "hibc"
+	FormatPlain = "plain"
+)
+
+// Control characters used as separators
+const (
+	gs  = "\x1d" // group separator, FNC1 in GS1 element strings
+	rs  = "\x1e" // record separator
+	eot = "\x04" // end of transmission
+)
+
+// MaxLength is the longest code accepted; a GS1 DataMatrix holds far less
+// product data in practice
+const MaxLength = 512
+
+// Code is a decoded barcode
+type Code struct {
+	Format string `json:"format"`
+	Raw    string `json:"raw"`
+
+	GTIN   string     `json:"gtin,omitempty"`   // 14 digits
+	PZN    string     `json:"pzn,omitempty"`    // 8 digits
+	UDIDI  string     `json:"udi_di,omitempty"` // device identifier: the GTIN, or for HIBC the labeler and product code
+	Batch  string     `json:"batch,omitempty"`
+	Serial string     `json:"serial,omitempty"`
+	Expiry *time.Time `json:"expiry,omitempty"`
+	// ArticleNumber is GS1 AI 240, which labels printed for items without
+	// GTIN or PZN carry
+	ArticleNumber string `json:"article_number,omitempty"`
+
+	// All GS1 application identifiers and their values
+	Elements map[string]string `json:"elements,omitempty"`
+}
+
+// ProductCodes returns the identifiers an item may be stored under: the
+// GTIN as 14 digits and, with its leading zero dropped, as EAN-13, and the
+// UDI-DI
+func (c *Code) ProductCodes() []string {
+	var codes []string
+	add := func(s string) {
+		if s == "" {
+			return
+		}
+		for _, existing := range codes {
+			if existing == s {
+				return
+			}
+		}
+		codes = append(codes, s)
+	}
+
+	if c.GTIN != "" {
+		add(c.GTIN)
+		add(strings.TrimPrefix(c.GTIN, "0"))
+	}
+	add(c.UDIDI)
+	if c.Format == FormatHIBC && c.UDIDI != "" {
+		add("+" + c.UDIDI)
+	}
+	return codes
+}
+
+// Error is returned for a code that looks like a known format but is malformed
+type Error struct {
+	Format string
+	Reason string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("barcode: invalid %s code: %s", e.Format, e.Reason)
+}
+
+func invalid(format, reason string, args ...interface{}) *Error {
+	return &Error{Format: format, Reason: fmt.Sprintf(reason, args...)}
+}
+
+// symbologyIdentifiers are the AIM prefixes scanners may send in front of
+// GS1 data: DataMatrix, GS1-128, QR Code and GS1 DataBar
+var symbologyIdentifiers = []string{"]d2", "]C1", "]Q3", "]e0"}
+
+// Parse decodes a scanned code. Codes that match no structured format are
+// returned as FormatPlain with only Raw set, so the caller can still look
+// them up as they are.
+func Parse(raw string) (*Code, error) {
+	if len(raw) > MaxLength {
+		return nil, invalid(FormatPlain, "longer than %d characters", MaxLength)
+	}
+	s := strings.TrimRight(raw, "\r\n")
+
+	for _, id := range symbologyIdentifiers {
+		if strings.HasPrefix(s, id) {
+			return parseGS1(raw, strings.TrimPrefix(s, id))
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(s, "[)>"):
+		return parseIFA(raw, s)
+	case strings.HasPrefix(s, gs), strings.HasPrefix(s, "(") && len(s) > 4:
+		return parseGS1(raw, s)
+	case strings.HasPrefix(s, "+") || strings.HasPrefix(s, "*+"):
+		return parseHIBC(raw, strings.Trim(s, "*"))
+	case looksLikeGS1(s):
+		return parseGS1(raw, s)
+	}
+
+	return parsePlain(raw, s), nil
+}
+
+// looksLikeGS1 reports whether s starts with a GTIN element, for scanners
+// configured to send no symbology identifier
+func looksLikeGS1(s string) bool {
+	return len(s) >= 18 && strings.HasPrefix(s, "01") && isDigits(s[2:16]) && ValidGTIN(s[2:16])
+}
+
+// parsePlain recognizes EAN/GTIN and Code 39 PZN barcodes
+func parsePlain(raw, s string) *Code {
+	code := &Code{Format: FormatPlain, Raw: raw}
+
+	switch {
+	case isDigits(s) && (len(s) == 8 || len(s) == 12 || len(s) == 13 || len(s) == 14) && ValidGTIN(s):
+		code.GTIN = strings.Repeat("0", 14-len(s)) + s
+		code.UDIDI = code.GTIN
+		code.PZN = pznFromGTIN(code.GTIN)
+	case strings.HasPrefix(s, "-") && len(s) == 9 && isDigits(s[1:]) && ValidPZN(s[1:]):
+		code.PZN = s[1:]
+	}
+	return code
+}
+
+// ValidGTIN checks the GS1 mod 10 check digit of a GTIN-8, -12, -13 or -14
+func ValidGTIN(gtin string) bool {
+	if len(gtin) < 8 || !isDigits(gtin) {
+		return false
+	}
+	sum := 0
+	for i := len(gtin) - 2; i >= 0; i-- {
+		d := int(gtin[i] - '0')
+		// Weights alternate 3, 1, ... from the digit left of the check digit
+		if (len(gtin)-2-i)%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	return (10-sum%10)%10 == int(gtin[len(gtin)-1]-'0')
+}
+
+// ValidPZN checks the mod 11 check digit of an 8-digit PZN
+func ValidPZN(pzn string) bool {
+	if len(pzn) != 8 || !isDigits(pzn) {
+		return false
+	}
+	sum := 0
+	for i := 0; i < 7; i++ {
+		sum += int(pzn[i]-'0') * (i + 1)
+	}
+	check := sum % 11
+	return check != 10 && check == int(pzn[7]-'0')
+}
+
+// pznFromGTIN extracts the PZN from a German NTIN (GTIN 04150 + PZN)
+func pznFromGTIN(gtin string) string {
+	if len(gtin) == 14 && strings.HasPrefix(gtin, "04150") && ValidPZN(gtin[5:13]) {
+		return gtin[5:13]
+	}
+	return ""
+}
+
+// parseDate parses YYMMDD. A day of 00 means the last day of the month, as
+// GS1 and IFA allow for expiry dates.
+func parseDate(s string) (*time.Time, bool) {
+	if len(s) != 6 || !isDigits(s) {
+		return nil, false
+	}
+	year := 2000 + atoi(s[0:2])
+	month := atoi(s[2:4])
+	day := atoi(s[4:6])
+	if month < 1 || month > 12 {
+		return nil, false
+	}
+
+	var t time.Time
+	if day == 0 {
+		t = time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC)
+	} else {
+		t = time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+		if t.Day() != day {
+			return nil, false
+		}
+	}
+	return &t, true
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// atoi converts a string of digits
+func atoi(s string) int {
+	n := 0
+	for i := 0; i < len(s); i++ {
+		n = n*10 + int(s[i]-'0')
+	}
+	return n
+}
@@ -0,0 +1,148 @@
+package barcode
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParse_GS1DataMatrix(t *testing.T) {
+	// Symbology identifier, fixed length GTIN and expiry, GS after the batch
+	code, err := Parse("]d2010950600013435217261100" + "10ABC123\x1d21SN-0042")
+	require.NoError(t, err)
+
+	assert.Equal(t, FormatGS1, code.Format)
+	assert.Equal(t, "09506000134352", code.GTIN)
+	assert.Equal(t, "09506000134352", code.UDIDI)
+	assert.Equal(t, "ABC123", code.Batch)
+	assert.Equal(t, "SN-0042", code.Serial)
+	require.NotNil(t, code.Expiry)
+	assert.Equal(t, date(2026, time.November, 30), *code.Expiry, "day 00 is the end of the month")
+	assert.Equal(t, []string{"09506000134352", "9506000134352"}, code.ProductCodes())
+}
+
+func TestParse_GS1Variants(t *testing.T) {
+	tests := map[string]string{
+		"leading FNC1":       "\x1d01095060001343521726123110ABC123",
+		"no identifier":      "01095060001343521726123110ABC123",
+		"human readable":     "(01)09506000134352(17)261231(10)ABC123",
+		"GS after fixed AI":  "]C101095060001343521726123110ABC123",
+		"trailing newline":   "]Q301095060001343521726123110ABC123\r\n",
+		"separator in fixed": "010950600013435217261231\x1d10ABC123",
+	}
+	for name, raw := range tests {
+		code, err := Parse(raw)
+		require.NoError(t, err, name)
+		assert.Equal(t, "09506000134352", code.GTIN, name)
+		assert.Equal(t, "ABC123", code.Batch, name)
+		require.NotNil(t, code.Expiry, name)
+		assert.Equal(t, date(2026, time.December, 31), *code.Expiry, name)
+	}
+}
+
+func TestParse_GS1PZN(t *testing.T) {
+	// German NTIN: 4150 and the PZN
+	code, err := Parse("]d20104150012345623" + "10B1\x1d17270131")
+	require.NoError(t, err)
+	assert.Equal(t, "01234562", code.PZN)
+
+	// AI 240 carries the article number of practice-printed labels
+	code, err = Parse("]d2240ART-4711\x1d10B1")
+	require.NoError(t, err)
+	assert.Equal(t, "ART-4711", code.ArticleNumber)
+	assert.Empty(t, code.ProductCodes())
+
+	// AI 710 carries the PZN directly
+	code, err = Parse("]d20109506000134352710" + "01234562\x1d10B1")
+	require.NoError(t, err)
+	assert.Equal(t, "01234562", code.PZN)
+}
+
+func TestParse_GS1Invalid(t *testing.T) {
+	tests := map[string]string{
+		"bad GTIN check":   "]d20109506000134353",
+		"bad expiry":       "]d2010950600013435217261331",
+		"short fixed AI":   "]d2010950600",
+		"batch too long":   "]d2010950600013435210" + "ABCDEFGHIJKLMNOPQRSTU",
+		"not an AI":        "]d2AB12",
+		"empty human form": "(01)(10)ABC",
+	}
+	for name, raw := range tests {
+		_, err := Parse(raw)
+		var codeErr *Error
+		assert.ErrorAs(t, err, &codeErr, name)
+	}
+}
+
+func TestParse_SecurPharm(t *testing.T) {
+	code, err := Parse("[)>\x1e06\x1d9N110123456224\x1d1TCH-7781\x1dD270600\x1dSABCD1234EFGH\x1e\x04")
+	require.NoError(t, err)
+
+	assert.Equal(t, FormatIFA, code.Format)
+	assert.Equal(t, "01234562", code.PZN)
+	assert.Equal(t, "CH-7781", code.Batch)
+	assert.Equal(t, "ABCD1234EFGH", code.Serial)
+	require.NotNil(t, code.Expiry)
+	assert.Equal(t, date(2027, time.June, 30), *code.Expiry)
+
+	_, err = Parse("[)>\x1e06\x1d9N110123456225\x1d1TX\x1e\x04")
+	assert.Error(t, err, "wrong PPN check digits")
+}
+
+func TestParse_HIBC(t *testing.T) {
+	code, err := Parse("+A99912345/$$52001510X0")
+	require.NoError(t, err)
+	assert.Equal(t, FormatHIBC, code.Format)
+	assert.Equal(t, "A99912345", code.UDIDI)
+	assert.Equal(t, "10X", code.Batch)
+	require.NotNil(t, code.Expiry)
+	assert.Equal(t, date(2020, time.January, 15), *code.Expiry)
+	assert.Equal(t, []string{"A99912345", "+A99912345"}, code.ProductCodes())
+
+	code, err = Parse("+A123BJC5D6E71/$$3231231ABC-1239")
+	require.NoError(t, err)
+	assert.Equal(t, "A123BJC5D6E71", code.UDIDI)
+	assert.Equal(t, "ABC-123", code.Batch)
+	assert.Equal(t, date(2023, time.December, 31), *code.Expiry)
+
+	code, err = Parse("+A123BJC5D6E71/$+SN0042L")
+	require.NoError(t, err)
+	assert.Equal(t, "SN0042", code.Serial)
+	assert.Empty(t, code.Batch)
+
+	code, err = Parse("*+A999123457*")
+	require.NoError(t, err)
+	assert.Equal(t, "A99912345", code.UDIDI)
+
+	_, err = Parse("+A99912345/$$52001510X1")
+	assert.Error(t, err, "wrong check character")
+}
+
+func TestParse_Plain(t *testing.T) {
+	code, err := Parse("4006381333931")
+	require.NoError(t, err)
+	assert.Equal(t, FormatPlain, code.Format)
+	assert.Equal(t, "04006381333931", code.GTIN)
+
+	code, err = Parse("-01234562")
+	require.NoError(t, err)
+	assert.Equal(t, "01234562", code.PZN)
+
+	code, err = Parse("ART-4711")
+	require.NoError(t, err)
+	assert.Equal(t, FormatPlain, code.Format)
+	assert.Empty(t, code.GTIN)
+	assert.Empty(t, code.ProductCodes())
+}
+
+func TestValidPZN(t *testing.T) {
+	assert.True(t, ValidPZN("01234562"))
+	assert.False(t, ValidPZN("01234563"))
+	assert.False(t, ValidPZN("1234562"))
+}
@@ -0,0 +1,248 @@
+package barcode
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/makiuchi-d/gozxing"
+	dmencoder "github.com/makiuchi-d/gozxing/datamatrix/encoder"
+	"github.com/makiuchi-d/gozxing/oned"
+	"github.com/makiuchi-d/gozxing/qrcode"
+)
+
+// Symbologies Encode can produce
+const (
+	SymbologyCode128    = "code128" // GS1-128 when the data is a GS1 element string
+	SymbologyDataMatrix = "datamatrix"
+	SymbologyQR         = "qr"
+)
+
+// ValidSymbology reports whether s is a supported symbology
+func ValidSymbology(s string) bool {
+	return s == SymbologyCode128 || s == SymbologyDataMatrix || s == SymbologyQR
+}
+
+// Element is one GS1 application identifier and its value
+type Element struct {
+	AI    string
+	Value string
+}
+
+// ElementString joins elements into a GS1 element string with GS after each
+// variable length element that is not the last. Place fixed length elements
+// (01, 17) first to keep the code short.
+func ElementString(elements ...Element) (string, error) {
+	var b strings.Builder
+	for i, e := range elements {
+		if e.Value == "" {
+			return "", invalid(FormatGS1, "AI %s has no value", e.AI)
+		}
+		n, fixed := fixedDataLengths[e.AI[:2]]
+		if fixed && len(e.Value) != n {
+			return "", invalid(FormatGS1, "AI %s needs %d characters", e.AI, n)
+		}
+		if !fixed && len(e.Value) > maxLength(e.AI) {
+			return "", invalid(FormatGS1, "AI %s is longer than %d characters", e.AI, maxLength(e.AI))
+		}
+		if strings.Contains(e.Value, gs) {
+			return "", invalid(FormatGS1, "AI %s contains a group separator", e.AI)
+		}
+		b.WriteString(e.AI)
+		b.WriteString(e.Value)
+		if !fixed && i < len(elements)-1 {
+			b.WriteString(gs)
+		}
+	}
+	return b.String(), nil
+}
+
+// HumanReadable formats elements as "(01)09506000134352(10)ABC123"
+func HumanReadable(elements ...Element) string {
+	var b strings.Builder
+	for _, e := range elements {
+		fmt.Fprintf(&b, "(%s)%s", e.AI, e.Value)
+	}
+	return b.String()
+}
+
+// Matrix is an encoded symbol without quiet zone. A linear symbol has a
+// height of one module.
+type Matrix struct {
+	Width   int
+	Height  int
+	modules []bool
+}
+
+func newMatrix(width, height int) *Matrix {
+	return &Matrix{Width: width, Height: height, modules: make([]bool, width*height)}
+}
+
+// Dark reports whether the module at column x, row y is dark
+func (m *Matrix) Dark(x, y int) bool {
+	return m.modules[y*m.Width+x]
+}
+
+func (m *Matrix) set(x, y int, dark bool) {
+	m.modules[y*m.Width+x] = dark
+}
+
+// Encode encodes data in the given symbology. With gs1 set, data is a GS1
+// element string as built by ElementString: the symbol starts with FNC1 and
+// each GS becomes FNC1, which scanners report as a GS1 symbol.
+func Encode(symbology, data string, gs1 bool) (*Matrix, error) {
+	if data == "" {
+		return nil, fmt.Errorf("barcode: nothing to encode")
+	}
+	switch symbology {
+	case SymbologyCode128:
+		return encodeCode128(data, gs1)
+	case SymbologyDataMatrix:
+		return encodeDataMatrix(data, gs1)
+	case SymbologyQR:
+		return encodeQR(data, gs1)
+	}
+	return nil, fmt.Errorf("barcode: unsupported symbology %q", symbology)
+}
+
+// fnc1 is the character the Code 128 writer encodes as FNC1
+const fnc1 = "ñ"
+
+func encodeCode128(data string, gs1 bool) (*Matrix, error) {
+	if gs1 {
+		data = fnc1 + strings.ReplaceAll(data, gs, fnc1)
+	}
+	hints := map[gozxing.EncodeHintType]interface{}{gozxing.EncodeHintType_MARGIN: 0}
+	bits, err := oned.NewCode128Writer().Encode(data, gozxing.BarcodeFormat_CODE_128, 0, 1, hints)
+	if err != nil {
+		return nil, fmt.Errorf("barcode: encode code 128: %w", err)
+	}
+	return fromBitMatrix(bits), nil
+}
+
+func encodeQR(data string, gs1 bool) (*Matrix, error) {
+	hints := map[gozxing.EncodeHintType]interface{}{
+		gozxing.EncodeHintType_MARGIN:           0,
+		gozxing.EncodeHintType_ERROR_CORRECTION: "M",
+		gozxing.EncodeHintType_CHARACTER_SET:    "UTF-8",
+	}
+	if gs1 {
+		hints[gozxing.EncodeHintType_GS1_FORMAT] = true
+	}
+	bits, err := qrcode.NewQRCodeWriter().Encode(data, gozxing.BarcodeFormat_QR_CODE, 0, 0, hints)
+	if err != nil {
+		return nil, fmt.Errorf("barcode: encode QR code: %w", err)
+	}
+	return fromBitMatrix(bits), nil
+}
+
+// encodeDataMatrix builds an ECC 200 symbol. The codewords are produced here
+// in ASCII encodation because the GS1 form needs FNC1 (codeword 232), which
+// the library's high level encoder cannot emit; error correction and module
+// placement come from the library.
+func encodeDataMatrix(data string, gs1 bool) (*Matrix, error) {
+	codewords := dataMatrixCodewords(data, gs1)
+
+	info, err := dmencoder.SymbolInfo_Lookup(len(codewords), dmencoder.SymbolShapeHint_FORCE_SQUARE, nil, nil, true)
+	if err != nil {
+		return nil, fmt.Errorf("barcode: data matrix: %w", err)
+	}
+	codewords = padDataMatrix(codewords, info.GetDataCapacity())
+
+	codewords, err = dmencoder.ErrorCorrection_EncodeECC200(codewords, info)
+	if err != nil {
+		return nil, fmt.Errorf("barcode: data matrix: %w", err)
+	}
+	placement := dmencoder.NewDefaultPlacement(codewords, info.GetSymbolDataWidth(), info.GetSymbolDataHeight())
+	placement.Place()
+
+	// Wrap each data region in its finder pattern: solid left and bottom
+	// edges, alternating top and right edges
+	m := newMatrix(info.GetSymbolWidth(), info.GetSymbolHeight())
+	regionWidth, regionHeight := info.GetMatrixWidth(), info.GetMatrixHeight()
+	my := 0
+	for y := 0; y < info.GetSymbolDataHeight(); y++ {
+		if y%regionHeight == 0 {
+			for x := 0; x < m.Width; x++ {
+				m.set(x, my, x%2 == 0)
+			}
+			my++
+		}
+		mx := 0
+		for x := 0; x < info.GetSymbolDataWidth(); x++ {
+			if x%regionWidth == 0 {
+				m.set(mx, my, true)
+				mx++
+			}
+			m.set(mx, my, placement.GetBit(x, y))
+			mx++
+			if x%regionWidth == regionWidth-1 {
+				m.set(mx, my, y%2 == 0)
+				mx++
+			}
+		}
+		my++
+		if y%regionHeight == regionHeight-1 {
+			for x := 0; x < m.Width; x++ {
+				m.set(x, my, true)
+			}
+			my++
+		}
+	}
+	return m, nil
+}
+
+// dataMatrixCodewords encodes data in ASCII encodation: digit pairs as one
+// codeword, FNC1 first and for each GS in GS1 mode
+func dataMatrixCodewords(data string, gs1 bool) []byte {
+	var codewords []byte
+	if gs1 {
+		codewords = append(codewords, 232)
+	}
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case isDigit(c) && i+1 < len(data) && isDigit(data[i+1]):
+			codewords = append(codewords, 130+(c-'0')*10+(data[i+1]-'0'))
+			i++
+		case gs1 && c == gs[0]:
+			codewords = append(codewords, 232)
+		case c > 127:
+			// Upper shift
+			codewords = append(codewords, 235, c-127)
+		default:
+			codewords = append(codewords, c+1)
+		}
+	}
+	return codewords
+}
+
+// padDataMatrix fills the data capacity: 129, then pad codewords randomized
+// by the 253-state algorithm (ISO/IEC 16022, 5.2.3)
+func padDataMatrix(codewords []byte, capacity int) []byte {
+	if len(codewords) < capacity {
+		codewords = append(codewords, 129)
+	}
+	for len(codewords) < capacity {
+		position := len(codewords) + 1
+		v := 129 + (149*position)%253 + 1
+		if v > 254 {
+			v -= 254
+		}
+		codewords = append(codewords, byte(v))
+	}
+	return codewords
+}
+
+func fromBitMatrix(bits *gozxing.BitMatrix) *Matrix {
+	m := newMatrix(bits.GetWidth(), bits.GetHeight())
+	for y := 0; y < m.Height; y++ {
+		for x := 0; x < m.Width; x++ {
+			m.set(x, y, bits.Get(x, y))
+		}
+	}
+	return m
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,117 @@
+package barcode
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/datamatrix"
+	"github.com/makiuchi-d/gozxing/oned"
+	"github.com/makiuchi-d/gozxing/qrcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// decode renders m with a quiet zone and reads it back with the zxing readers
+func decode(t *testing.T, symbology string, m *Matrix) string {
+	t.Helper()
+	const scale, quiet = 4, 10
+	height := m.Height
+	if symbology == SymbologyCode128 {
+		height = 20 // bar height for the linear reader
+	}
+	img := image.NewGray(image.Rect(0, 0, (m.Width+2*quiet)*scale, (height+2*quiet)*scale))
+	for i := range img.Pix {
+		img.Pix[i] = 255
+	}
+	for y := 0; y < height; y++ {
+		for x := 0; x < m.Width; x++ {
+			if m.Dark(x, min(y, m.Height-1)) {
+				for dy := 0; dy < scale; dy++ {
+					for dx := 0; dx < scale; dx++ {
+						img.SetGray((x+quiet)*scale+dx, (y+quiet)*scale+dy, color.Gray{})
+					}
+				}
+			}
+		}
+	}
+
+	bmp, err := gozxing.NewBinaryBitmapFromImage(img)
+	require.NoError(t, err)
+	hints := map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_PURE_BARCODE: true,
+		// Report FNC1 separators in Code 128 as GS
+		gozxing.DecodeHintType_ASSUME_GS1: true,
+	}
+
+	var reader gozxing.Reader
+	switch symbology {
+	case SymbologyCode128:
+		reader = oned.NewCode128Reader()
+	case SymbologyDataMatrix:
+		reader = datamatrix.NewDataMatrixReader()
+	case SymbologyQR:
+		reader = qrcode.NewQRCodeReader()
+	}
+	result, err := reader.Decode(bmp, hints)
+	require.NoError(t, err)
+	return result.GetText()
+}
+
+var gs1Elements = []Element{
+	{AI: "01", Value: "09506000134352"},
+	{AI: "17", Value: "261231"},
+	{AI: "10", Value: "ABC123"},
+	{AI: "21", Value: "SN-0042"},
+}
+
+func TestElementString(t *testing.T) {
+	s, err := ElementString(gs1Elements...)
+	require.NoError(t, err)
+	assert.Equal(t, "0109506000134352172612311"+"0ABC123\x1d21SN-0042", s)
+	assert.Equal(t, "(01)09506000134352(17)261231(10)ABC123(21)SN-0042", HumanReadable(gs1Elements...))
+
+	_, err = ElementString(Element{AI: "01", Value: "123"})
+	assert.Error(t, err)
+	_, err = ElementString(Element{AI: "10", Value: "ABCDEFGHIJKLMNOPQRSTU"})
+	assert.Error(t, err)
+}
+
+func TestEncode_GS1RoundTrip(t *testing.T) {
+	data, err := ElementString(gs1Elements...)
+	require.NoError(t, err)
+
+	for _, symbology := range []string{SymbologyCode128, SymbologyDataMatrix, SymbologyQR} {
+		m, err := Encode(symbology, data, true)
+		require.NoError(t, err, symbology)
+
+		// The readers report FNC1 as GS, which Parse takes as a GS1 code
+		code, err := Parse(decode(t, symbology, m))
+		require.NoError(t, err, symbology)
+		assert.Equal(t, FormatGS1, code.Format, symbology)
+		assert.Equal(t, "09506000134352", code.GTIN, symbology)
+		assert.Equal(t, "ABC123", code.Batch, symbology)
+		assert.Equal(t, "SN-0042", code.Serial, symbology)
+	}
+}
+
+func TestEncode_Plain(t *testing.T) {
+	for _, symbology := range []string{SymbologyCode128, SymbologyDataMatrix, SymbologyQR} {
+		m, err := Encode(symbology, "ART-4711", false)
+		require.NoError(t, err, symbology)
+		assert.Equal(t, "ART-4711", decode(t, symbology, m), symbology)
+	}
+}
+
+func TestEncode_DataMatrixSize(t *testing.T) {
+	// FNC1 and 8 digit pairs need 9 codewords: a 14x14 symbol holds 8,
+	// 16x16 holds 12
+	m, err := Encode(SymbologyDataMatrix, "0109506000134352", true)
+	require.NoError(t, err)
+	assert.Equal(t, 16, m.Width)
+	assert.Equal(t, 16, m.Height)
+
+	_, err = Encode("pdf417", "x", false)
+	assert.Error(t, err)
+}
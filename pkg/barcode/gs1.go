@@ -0,0 +1,167 @@
+package barcode
+
+import (
+	"regexp"
+	"strings"
+)
+
+// aiLengths is the length of the application identifier by its first two
+// digits (GS1 General Specifications, figure 3.2-1)
+var aiLengths = map[string]int{
+	"23": 3, "24": 3, "25": 3,
+	"31": 4, "32": 4, "33": 4, "34": 4, "35": 4, "36": 4,
+	"39": 4, "40": 3, "41": 3, "42": 3, "43": 4,
+	"70": 4, "71": 3, "72": 4, "80": 4, "81": 4, "82": 4,
+}
+
+// fixedDataLengths is the data length of AIs that need no separator, by
+// their first two digits (GS1 General Specifications, figure 7.8.5-2)
+var fixedDataLengths = map[string]int{
+	"00": 18, "01": 14, "02": 14, "03": 14, "04": 16,
+	"11": 6, "12": 6, "13": 6, "15": 6, "16": 6, "17": 6, "18": 6, "19": 6,
+	"20": 2,
+	"31": 6, "32": 6, "33": 6, "34": 6, "35": 6, "36": 6,
+	"41": 13,
+}
+
+// maxDataLengths limits variable length AIs; others may take up to 90
+var maxDataLengths = map[string]int{
+	"10": 20, "21": 20, "22": 20, "30": 8, "37": 8,
+	"240": 30, "241": 30, "250": 30, "251": 30, "710": 20, "711": 20, "712": 20, "713": 20, "714": 20, "715": 20,
+}
+
+// AIs read into Code
+const (
+	aiGTIN    = "01"
+	aiBatch   = "10"
+	aiExpiry  = "17"
+	aiSerial  = "21"
+	aiNHRNDE  = "710" // national healthcare reimbursement number, Germany: the PZN
+	aiArticle = "240" // additional product identification
+)
+
+// parenthesized matches one element of the human readable form
+var parenthesized = regexp.MustCompile(`\((\d{2,4})\)([^(]*)`)
+
+// parseGS1 decodes a GS1 element string
+func parseGS1(raw, s string) (*Code, error) {
+	var elements map[string]string
+	var err error
+	if strings.HasPrefix(s, "(") {
+		elements, err = splitParenthesized(s)
+	} else {
+		elements, err = splitElements(strings.TrimPrefix(s, gs))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	code := &Code{Format: FormatGS1, Raw: raw, Elements: elements}
+
+	if gtin, ok := elements[aiGTIN]; ok {
+		if !ValidGTIN(gtin) {
+			return nil, invalid(FormatGS1, "GTIN %s has a wrong check digit", gtin)
+		}
+		code.GTIN = gtin
+		code.UDIDI = gtin
+		code.PZN = pznFromGTIN(gtin)
+	}
+	if expiry, ok := elements[aiExpiry]; ok {
+		t, valid := parseDate(expiry)
+		if !valid {
+			return nil, invalid(FormatGS1, "expiry date %s is not a valid YYMMDD date", expiry)
+		}
+		code.Expiry = t
+	}
+	code.Batch = elements[aiBatch]
+	code.Serial = elements[aiSerial]
+	code.ArticleNumber = elements[aiArticle]
+	if pzn, ok := elements[aiNHRNDE]; ok && ValidPZN(pzn) {
+		code.PZN = pzn
+	}
+
+	return code, nil
+}
+
+// splitElements splits a GS-separated element string into AIs and values
+func splitElements(s string) (map[string]string, error) {
+	elements := make(map[string]string)
+	for s != "" {
+		if len(s) < 2 || !isDigits(s[:2]) {
+			return nil, invalid(FormatGS1, "expected an application identifier at %q", truncate(s))
+		}
+		aiLen, ok := aiLengths[s[:2]]
+		if !ok {
+			aiLen = 2
+		}
+		if len(s) < aiLen || !isDigits(s[:aiLen]) {
+			return nil, invalid(FormatGS1, "truncated application identifier %q", s)
+		}
+		ai := s[:aiLen]
+		s = s[aiLen:]
+
+		var value string
+		if n, fixed := fixedDataLengths[ai[:2]]; fixed {
+			if len(s) < n {
+				return nil, invalid(FormatGS1, "AI %s needs %d characters", ai, n)
+			}
+			value, s = s[:n], s[n:]
+		} else {
+			end := strings.Index(s, gs)
+			if end < 0 {
+				end = len(s)
+			}
+			value, s = s[:end], s[end:]
+			if max := maxLength(ai); len(value) > max {
+				return nil, invalid(FormatGS1, "AI %s is longer than %d characters", ai, max)
+			}
+		}
+		// A separator after a fixed length element is allowed, though not needed
+		s = strings.TrimPrefix(s, gs)
+
+		if value == "" {
+			return nil, invalid(FormatGS1, "AI %s has no value", ai)
+		}
+		elements[ai] = value
+	}
+
+	if len(elements) == 0 {
+		return nil, invalid(FormatGS1, "no elements")
+	}
+	return elements, nil
+}
+
+// splitParenthesized splits the human readable form "(01)...(10)..."
+func splitParenthesized(s string) (map[string]string, error) {
+	matches := parenthesized.FindAllStringSubmatch(s, -1)
+	if len(matches) == 0 {
+		return nil, invalid(FormatGS1, "no elements")
+	}
+
+	elements := make(map[string]string, len(matches))
+	for _, m := range matches {
+		ai, value := m[1], strings.TrimSpace(m[2])
+		if value == "" {
+			return nil, invalid(FormatGS1, "AI %s has no value", ai)
+		}
+		if n, fixed := fixedDataLengths[ai[:2]]; fixed && len(value) != n {
+			return nil, invalid(FormatGS1, "AI %s needs %d characters", ai, n)
+		}
+		elements[ai] = value
+	}
+	return elements, nil
+}
+
+func maxLength(ai string) int {
+	if n, ok := maxDataLengths[ai]; ok {
+		return n
+	}
+	return 90
+}
+
+func truncate(s string) string {
+	if len(s) > 20 {
+		return s[:20] + "..."
+	}
+	return s
+}
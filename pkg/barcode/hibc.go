@@ -0,0 +1,170 @@
+package barcode
+
+import (
+	"strings"
+	"time"
+)
+
+// hibcCharset gives each character its value for the modulo 43 check
+// character (HIBC LIC standard, appendix B)
+const hibcCharset = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ-. $/+%"
+
+// parseHIBC decodes a HIBC LIC code: "+", the labeler identification code
+// (4 characters, the first a letter), the product code (1-18), the unit of
+// measure (1 digit), optionally "/" and secondary data, and a check
+// character.
+func parseHIBC(raw, s string) (*Code, error) {
+	s = strings.ToUpper(s)
+	if len(s) < 4 {
+		return nil, invalid(FormatHIBC, "too short")
+	}
+	data, check := s[:len(s)-1], s[len(s)-1]
+	if hibcCheck(data) != check {
+		return nil, invalid(FormatHIBC, "wrong check character")
+	}
+	data = strings.TrimPrefix(data, "+")
+
+	primary, secondary, hasSecondary := strings.Cut(data, "/")
+	if len(primary) < 6 || len(primary) > 23 {
+		return nil, invalid(FormatHIBC, "primary data must be 6 to 23 characters")
+	}
+	if primary[0] < 'A' || primary[0] > 'Z' {
+		return nil, invalid(FormatHIBC, "labeler code must start with a letter")
+	}
+	if unit := primary[len(primary)-1]; unit < '0' || unit > '9' {
+		return nil, invalid(FormatHIBC, "unit of measure must be a digit")
+	}
+
+	code := &Code{Format: FormatHIBC, Raw: raw, UDIDI: primary}
+	if hasSecondary {
+		if err := parseHIBCSecondary(code, secondary); err != nil {
+			return nil, err
+		}
+	}
+	return code, nil
+}
+
+// parseHIBCSecondary reads lot or serial number and expiry from secondary
+// data. Further fields after "/" use data identifiers, of which 14D
+// (expiry YYYYMMDD) and S (serial) are read.
+func parseHIBCSecondary(code *Code, secondary string) error {
+	fields := strings.Split(secondary, "/")
+	first := fields[0]
+
+	var value string
+	serial := false
+	switch {
+	case strings.HasPrefix(first, "$$"):
+		rest := first[2:]
+		if strings.HasPrefix(rest, "+") {
+			serial, rest = true, rest[1:]
+		}
+		expiry, remaining, err := hibcExpiry(rest)
+		if err != nil {
+			return err
+		}
+		code.Expiry, value = expiry, remaining
+	case strings.HasPrefix(first, "$+"):
+		serial, value = true, first[2:]
+	case strings.HasPrefix(first, "$"):
+		value = first[1:]
+	case len(first) >= 5 && isDigits(first[:5]):
+		// Julian date YYJJJ, then the lot
+		code.Expiry = julian(first[:2], first[2:5])
+		value = first[5:]
+	default:
+		value = first
+	}
+	if serial {
+		code.Serial = value
+	} else {
+		code.Batch = value
+	}
+
+	for _, field := range fields[1:] {
+		switch {
+		case strings.HasPrefix(field, "14D") && len(field) == 11 && isDigits(field[3:]):
+			t, err := time.Parse("20060102", field[3:])
+			if err != nil {
+				return invalid(FormatHIBC, "expiry date %s is not a valid date", field[3:])
+			}
+			code.Expiry = &t
+		case strings.HasPrefix(field, "S"):
+			code.Serial = field[1:]
+		}
+	}
+	return nil
+}
+
+// hibcExpiry reads the expiry after "$$": a format digit selects how the
+// date is written, 0 or 1 are the first digit of MMYY
+func hibcExpiry(s string) (*time.Time, string, error) {
+	if s == "" {
+		return nil, "", nil
+	}
+
+	// The date starts after the format digit, except for MMYY
+	layout, offset, n := "", 1, 0
+	switch s[0] {
+	case '0', '1':
+		layout, offset, n = "0106", 0, 4
+	case '2':
+		layout, n = "010206", 6
+	case '3':
+		layout, n = "060102", 6
+	case '4':
+		layout, n = "06010215", 8
+	case '5':
+		if len(s) < 6 || !isDigits(s[1:6]) {
+			return nil, "", invalid(FormatHIBC, "julian expiry date is malformed")
+		}
+		return julian(s[1:3], s[3:6]), s[6:], nil
+	case '6':
+		if len(s) < 8 || !isDigits(s[1:8]) {
+			return nil, "", invalid(FormatHIBC, "julian expiry date is malformed")
+		}
+		return julian(s[1:3], s[3:6]), s[8:], nil
+	case '7':
+		return nil, s[1:], nil
+	default:
+		// Quantity formats (8, 9) are not supported; the rest is the lot
+		return nil, s, nil
+	}
+
+	date := ""
+	if len(s) >= offset+n {
+		date = s[offset : offset+n]
+	}
+	if !isDigits(date) {
+		return nil, "", invalid(FormatHIBC, "expiry date is malformed")
+	}
+	t, err := time.Parse(layout, date)
+	if err != nil {
+		return nil, "", invalid(FormatHIBC, "expiry date %s is not a valid date", date)
+	}
+	if layout == "0106" {
+		// MMYY: expires at the end of the month
+		t = t.AddDate(0, 1, -1)
+	}
+	t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return &t, s[offset+n:], nil
+}
+
+// julian converts a two-digit year and day of the year
+func julian(yy, ddd string) *time.Time {
+	t := time.Date(2000+atoi(yy), 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, atoi(ddd)-1)
+	return &t
+}
+
+// hibcCheck computes the modulo 43 check character of data
+func hibcCheck(data string) byte {
+	sum := 0
+	for i := 0; i < len(data); i++ {
+		v := strings.IndexByte(hibcCharset, data[i])
+		if v < 0 {
+			return 0
+		}
+		sum += v
+	}
+	return hibcCharset[sum%43]
+}
@@ -0,0 +1,94 @@
+package barcode
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IFA data identifiers (ASC MH10.8.2) used on securPharm packs
+const (
+	diPPN    = "9N"
+	diBatch  = "1T"
+	diGTIN   = "8P"
+	diExpiry = "D"
+	diSerial = "S"
+)
+
+// ifaIdentifiers are matched longest first
+var ifaIdentifiers = []string{diPPN, diBatch, diGTIN, diExpiry, diSerial}
+
+// parseIFA decodes an ISO/IEC 15434 format 06 message as printed on German
+// drug packs: [)> RS 06 GS 9N<PPN> GS 1T<batch> GS D<YYMMDD> GS S<serial> RS EOT
+func parseIFA(raw, s string) (*Code, error) {
+	body := strings.TrimPrefix(s, "[)>")
+	body = strings.TrimPrefix(body, rs)
+	if !strings.HasPrefix(body, "06"+gs) {
+		return nil, invalid(FormatIFA, "only format 06 is supported")
+	}
+	body = strings.TrimPrefix(body, "06"+gs)
+	body = strings.TrimSuffix(body, eot)
+	body = strings.TrimSuffix(body, rs)
+
+	code := &Code{Format: FormatIFA, Raw: raw}
+	for _, field := range strings.Split(body, gs) {
+		if field == "" {
+			continue
+		}
+		di := ""
+		for _, candidate := range ifaIdentifiers {
+			if strings.HasPrefix(field, candidate) {
+				di = candidate
+				break
+			}
+		}
+		value := strings.TrimPrefix(field, di)
+
+		switch di {
+		case diPPN:
+			pzn, err := pznFromPPN(value)
+			if err != nil {
+				return nil, err
+			}
+			code.PZN = pzn
+		case diGTIN:
+			if !ValidGTIN(value) {
+				return nil, invalid(FormatIFA, "GTIN %s has a wrong check digit", value)
+			}
+			code.GTIN = strings.Repeat("0", max(14-len(value), 0)) + value
+			code.UDIDI = code.GTIN
+		case diBatch:
+			code.Batch = value
+		case diExpiry:
+			t, ok := parseDate(value)
+			if !ok {
+				return nil, invalid(FormatIFA, "expiry date %s is not a valid YYMMDD date", value)
+			}
+			code.Expiry = t
+		case diSerial:
+			code.Serial = value
+		}
+		// Other data identifiers are not needed and skipped
+	}
+
+	if code.PZN == "" && code.GTIN == "" {
+		return nil, invalid(FormatIFA, "no PPN or GTIN")
+	}
+	return code, nil
+}
+
+// pznFromPPN checks a Pharmacy Product Number and returns the PZN in it.
+// A PPN is "11", the 8-digit PZN and two check digits: the sum of each
+// character's ASCII value weighted 2, 3, 4, ... modulo 97.
+func pznFromPPN(ppn string) (string, error) {
+	if len(ppn) != 12 || !strings.HasPrefix(ppn, "11") {
+		return "", invalid(FormatIFA, "PPN %s is not a PZN based PPN", ppn)
+	}
+	sum := 0
+	for i := 0; i < 10; i++ {
+		sum += int(ppn[i]) * (i + 2)
+	}
+	if fmt.Sprintf("%02d", sum%97) != ppn[10:] {
+		return "", invalid(FormatIFA, "PPN %s has wrong check digits", ppn)
+	}
+	return ppn[2:10], nil
+}
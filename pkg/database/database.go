@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/jmoiron/sqlx"
@@ -17,9 +19,17 @@ type DB struct {
 	*sqlx.DB
 	logger     *logger.Logger
 	searchPath string // Service-specific search_path (e.g., "users, public")
+
+	// Read replicas for work marked with ReadOnly (see replica.go)
+	replicas         []*sqlx.DB
+	nextReplica      atomic.Uint32
+	statementTimeout time.Duration
+	routing          routingCounters
 }
 
-// New creates a new database connection
+// New creates a new database connection.
+// Configured read replicas are connected as well; an unreachable replica is
+// logged and skipped so the service can still start on the primary alone.
 func New(cfg *config.DatabaseConfig, log *logger.Logger) (*DB, error) {
 	db, err := sqlx.Connect("postgres", cfg.DSN())
 	if err != nil {
@@ -30,10 +40,32 @@ func New(cfg *config.DatabaseConfig, log *logger.Logger) (*DB, error) {
 	db.SetMaxIdleConns(cfg.MaxIdleConns)
 	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
 
-	return &DB{
-		DB:     db,
-		logger: log,
-	}, nil
+	wrapped := &DB{
+		DB:               db,
+		logger:           log,
+		statementTimeout: cfg.StatementTimeout,
+	}
+
+	replicaDSNs, err := cfg.ReplicaDSNs()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	for i, dsn := range replicaDSNs {
+		if err := wrapped.AddReplica(dsn); err != nil {
+			log.Warn().Err(err).Int("replica", i+1).Msg("read replica unavailable, skipping")
+			continue
+		}
+		replica := wrapped.replicas[len(wrapped.replicas)-1]
+		replica.SetMaxOpenConns(cfg.MaxOpenConns)
+		replica.SetMaxIdleConns(cfg.MaxIdleConns)
+		replica.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	}
+	if len(wrapped.replicas) > 0 {
+		log.Info().Int("replicas", len(wrapped.replicas)).Msg("read replica routing enabled")
+	}
+
+	return wrapped, nil
 }
 
 // NewWithDSN creates a new database connection with a DSN string
@@ -87,12 +119,16 @@ func (db *DB) Ping(ctx context.Context) error {
 	return db.PingContext(ctx)
 }
 
-// Close closes the database connection
+// Close closes the database connection and all replica connections
 func (db *DB) Close() error {
+	for _, replica := range db.replicas {
+		replica.Close()
+	}
 	return db.DB.Close()
 }
 
-// Health returns the health status of the database
+// Health returns the health status of the database, including pool metrics
+// and replica availability
 func (db *DB) Health(ctx context.Context) map[string]string {
 	status := map[string]string{
 		"status": "up",
@@ -106,16 +142,38 @@ func (db *DB) Health(ctx context.Context) map[string]string {
 		status["error"] = err.Error()
 	}
 
+	stats := db.DB.Stats()
+	status["open_connections"] = strconv.Itoa(stats.OpenConnections)
+	status["in_use"] = strconv.Itoa(stats.InUse)
+	status["idle"] = strconv.Itoa(stats.Idle)
+	status["wait_count"] = strconv.FormatInt(stats.WaitCount, 10)
+
+	if len(db.replicas) > 0 {
+		up := 0
+		for _, replica := range db.replicas {
+			if err := replica.PingContext(ctx); err == nil {
+				up++
+			}
+		}
+		status["replicas"] = fmt.Sprintf("%d/%d up", up, len(db.replicas))
+	}
+
 	return status
 }
 
-// Transaction executes a function within a transaction
+// Transaction executes a function within a transaction on the primary.
+// The statement timeout (see WithStatementTimeout) applies to the whole transaction.
 func (db *DB) Transaction(ctx context.Context, fn func(*sqlx.Tx) error) error {
-	tx, err := db.BeginTxx(ctx, nil)
+	tx, err := db.beginTx(ctx, db.DB, nil)
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return err
 	}
 
+	return db.runTx(tx, fn)
+}
+
+// runTx runs fn in tx, rolling back on error and committing otherwise
+func (db *DB) runTx(tx *sqlx.Tx, fn func(*sqlx.Tx) error) error {
 	if err := fn(tx); err != nil {
 		if rbErr := tx.Rollback(); rbErr != nil {
 			db.logger.Error().Err(rbErr).Msg("failed to rollback transaction")
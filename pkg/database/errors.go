@@ -22,21 +22,19 @@ func MapPQError(err error) *errors.AppError {
 
 	// Unique constraint violation (23505)
 	case "23505":
-		return errors.Conflict(formatConstraintMessage(pqErr))
+		return mapUniqueViolation(pqErr)
 
 	// Foreign key violation (23503)
 	case "23503":
-		return errors.BadRequest("referenced record does not exist")
+		return errors.BadRequest("errors.database.reference_missing")
 
 	// Not null violation (23502)
 	case "23502":
 		col := pqErr.Column
 		if col == "" {
-			col = "required field"
+			col = "field"
 		}
-		return errors.Validation(map[string]string{
-			col: "must not be empty",
-		})
+		return errors.Validation(nil).WithDetail(col, "validation.required", map[string]string{"field": col})
 
 	default:
 		return nil
@@ -49,35 +47,37 @@ func mapCheckConstraint(pqErr *pq.Error) *errors.AppError {
 
 	switch {
 	case strings.Contains(constraint, "email_format"):
-		return errors.Validation(map[string]string{
-			"email": "must be a valid email address",
-		})
+		return errors.Validation(map[string]string{"email": "validation.email"})
 
 	case strings.Contains(constraint, "employment_type_valid"):
-		return errors.Validation(map[string]string{
-			"employment_type": "must be one of: full_time, part_time, contractor, intern, temporary",
+		return errors.Validation(nil).WithDetail("employment_type", "validation.one_of", map[string]string{
+			"field":  "employment_type",
+			"values": "full_time, part_time, contractor, intern, temporary",
 		})
 
 	case strings.Contains(constraint, "status_valid"):
-		return errors.Validation(map[string]string{
-			"status": "must be one of: active, on_leave, suspended, terminated, pending",
+		return errors.Validation(nil).WithDetail("status", "validation.one_of", map[string]string{
+			"field":  "status",
+			"values": "active, on_leave, suspended, terminated, pending",
 		})
 
 	default:
-		return errors.BadRequest("data validation failed: " + constraint)
+		return errors.BadRequest("errors.database.check_failed", map[string]string{"constraint": constraint})
 	}
 }
 
-// formatConstraintMessage creates a user-friendly message for unique constraint violations.
-func formatConstraintMessage(pqErr *pq.Error) string {
+// mapUniqueViolation creates a user-friendly conflict for unique constraint violations.
+func mapUniqueViolation(pqErr *pq.Error) *errors.AppError {
 	constraint := pqErr.Constraint
 
 	switch {
 	case strings.Contains(constraint, "employee_number") || strings.Contains(constraint, "tenant_number"):
-		return "an employee with this employee number already exists"
+		return errors.Conflict("errors.database.duplicate_employee_number")
 	case strings.Contains(constraint, "email"):
-		return "an employee with this email already exists"
+		return errors.Conflict("errors.database.duplicate_employee_email")
+	case strings.Contains(constraint, "suppliers_name"):
+		return errors.Conflict("errors.database.duplicate_supplier_name")
 	default:
-		return "a record with these values already exists"
+		return errors.Conflict("errors.database.duplicate")
 	}
 }
@@ -0,0 +1,585 @@
+package database
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/medflow/medflow-backend/pkg/errors"
+)
+
+// List query defaults
+const (
+	DefaultListLimit = 20
+	MaxListLimit     = 200
+)
+
+// FieldType determines how filter and cursor values are parsed and cast
+type FieldType int
+
+const (
+	FieldText FieldType = iota
+	FieldInt
+	FieldNumeric
+	FieldBool
+	FieldDate
+	FieldTime
+	FieldTimeOfDay
+	FieldUUID
+)
+
+// sqlType returns the PostgreSQL type used to cast parameters
+func (t FieldType) sqlType() string {
+	switch t {
+	case FieldInt:
+		return "bigint"
+	case FieldNumeric:
+		return "numeric"
+	case FieldBool:
+		return "boolean"
+	case FieldDate:
+		return "date"
+	case FieldTime:
+		return "timestamptz"
+	case FieldTimeOfDay:
+		return "time"
+	case FieldUUID:
+		return "uuid"
+	default:
+		return "text"
+	}
+}
+
+// nullSentinel is the value NULLs are sorted as, so keyset comparisons stay total.
+// NULL dates and numbers sort last ascending; NULL text and booleans sort first.
+func (t FieldType) nullSentinel() string {
+	switch t {
+	case FieldInt:
+		return "9223372036854775807::bigint"
+	case FieldNumeric:
+		return "'Infinity'::numeric"
+	case FieldBool:
+		return "false"
+	case FieldDate:
+		return "'infinity'::date"
+	case FieldTime:
+		return "'infinity'::timestamptz"
+	case FieldTimeOfDay:
+		return "'24:00'::time"
+	case FieldUUID:
+		return "'00000000-0000-0000-0000-000000000000'::uuid"
+	default:
+		return "''"
+	}
+}
+
+// Field whitelists one API field of a list endpoint
+type Field struct {
+	// Column is the SQL column, qualified with the table alias if the query joins
+	// (e.g. "a.start_date"). The unqualified name must match the db tag of the row struct.
+	Column   string
+	Type     FieldType
+	Filter   bool // may be used in filter[...]
+	Sort     bool // may be used in sort=
+	Nullable bool
+}
+
+// ListSchema describes which fields a list endpoint can filter and sort by
+type ListSchema struct {
+	Fields map[string]Field
+	// DefaultSort is used when the request has no sort (e.g. []string{"name"} or {"-created_at"})
+	DefaultSort []string
+	// IDColumn is the unique tie-breaker appended to every sort (default "id")
+	IDColumn string
+}
+
+// Filter operators supported in filter[field][op]=value
+const (
+	OpEq   = "eq"
+	OpNe   = "ne"
+	OpLt   = "lt"
+	OpLte  = "lte"
+	OpGt   = "gt"
+	OpGte  = "gte"
+	OpIn   = "in"
+	OpLike = "like"
+	OpNull = "null"
+)
+
+// Filter is one parsed filter[field][op]=value condition
+type Filter struct {
+	Field string
+	Op    string
+	Value string
+}
+
+// SortField is one parsed sort key; "-field" sorts descending
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// ListQuery is a parsed list request: filters, sort, keyset cursor and limit.
+// Page > 0 selects legacy OFFSET paging (page/per_page) instead of the cursor.
+// SkipCount omits the COUNT(*) behind Page.Total, which scans every matching
+// row; cursor walks only need it once, if at all.
+type ListQuery struct {
+	Filters   []Filter
+	Sort      []SortField
+	Cursor    string
+	Limit     int
+	Page      int
+	SkipCount bool
+}
+
+// Where adds an equality filter unless value is empty. Used to map legacy
+// query parameters (e.g. ?category=) onto the generic filter syntax.
+func (q *ListQuery) Where(field, value string) *ListQuery {
+	if value != "" {
+		q.Filters = append(q.Filters, Filter{Field: field, Op: OpEq, Value: value})
+	}
+	return q
+}
+
+// WhereOp adds a filter with an explicit operator unless value is empty
+func (q *ListQuery) WhereOp(field, op, value string) *ListQuery {
+	if value != "" {
+		q.Filters = append(q.Filters, Filter{Field: field, Op: op, Value: value})
+	}
+	return q
+}
+
+// Offset returns the OFFSET for legacy page-based requests
+func (q *ListQuery) Offset() int {
+	if q.Page <= 1 || q.Cursor != "" {
+		return 0
+	}
+	return (q.Page - 1) * q.limit()
+}
+
+func (q *ListQuery) limit() int {
+	if q.Limit <= 0 {
+		return DefaultListLimit
+	}
+	if q.Limit > MaxListLimit {
+		return MaxListLimit
+	}
+	return q.Limit
+}
+
+// Page is one page of list results. Total is zero when the query skipped counting.
+type Page[T any] struct {
+	Items      []T
+	Total      int64
+	Limit      int
+	NextCursor string
+}
+
+// SelectPage runs a filtered, sorted and keyset-paginated list query.
+//
+// base is a SELECT whose WHERE clause holds the fixed conditions
+// (e.g. "... FROM inventory_items WHERE deleted_at IS NULL"); args are its
+// parameters. Must be called inside WithTenantRLS so RLS applies.
+//
+//	page, err := database.SelectPage[*InventoryItem](ctx, r.db, &itemListSchema, q,
+//	    `SELECT ... FROM inventory_items WHERE deleted_at IS NULL`)
+func SelectPage[T any](ctx context.Context, db *DB, schema *ListSchema, q *ListQuery, base string, args ...interface{}) (*Page[T], error) {
+	sorts, err := schema.resolveSort(q.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &queryBuilder{args: append([]interface{}{}, args...)}
+	var where []string
+	for _, f := range q.Filters {
+		clause, err := schema.filterClause(b, f)
+		if err != nil {
+			return nil, err
+		}
+		where = append(where, clause)
+	}
+
+	filtered := base
+	if len(where) > 0 {
+		filtered += " AND " + strings.Join(where, " AND ")
+	}
+
+	page := &Page[T]{Limit: q.limit()}
+
+	// Total ignores the cursor so clients can show "x of y"
+	if !q.SkipCount {
+		countQuery := "SELECT COUNT(*) FROM (" + filtered + ") AS list_count"
+		if err := db.GetContext(ctx, &page.Total, countQuery, b.args...); err != nil {
+			return nil, err
+		}
+	}
+
+	query := filtered
+	if q.Cursor != "" && q.Page <= 1 {
+		values, err := decodeCursor(q.Cursor, sorts)
+		if err != nil {
+			return nil, err
+		}
+		query += " AND " + keysetClause(b, sorts, values)
+	}
+
+	order := make([]string, len(sorts))
+	for i, s := range sorts {
+		order[i] = s.expr()
+		if s.desc {
+			order[i] += " DESC"
+		}
+	}
+	query += " ORDER BY " + strings.Join(order, ", ")
+	query += fmt.Sprintf(" LIMIT %d", page.Limit+1)
+	if offset := q.Offset(); offset > 0 {
+		query += fmt.Sprintf(" OFFSET %d", offset)
+	}
+
+	var items []T
+	if err := db.SelectContext(ctx, &items, query, b.args...); err != nil {
+		return nil, err
+	}
+
+	if len(items) > page.Limit {
+		items = items[:page.Limit]
+		page.NextCursor, err = encodeCursor(sorts, items[len(items)-1])
+		if err != nil {
+			return nil, err
+		}
+	}
+	if items == nil {
+		items = []T{}
+	}
+	page.Items = items
+
+	return page, nil
+}
+
+// queryBuilder tracks positional parameters
+type queryBuilder struct {
+	args []interface{}
+}
+
+func (b *queryBuilder) param(v interface{}, t FieldType) string {
+	b.args = append(b.args, v)
+	return fmt.Sprintf("$%d::%s", len(b.args), t.sqlType())
+}
+
+// resolvedSort is a validated sort key
+type resolvedSort struct {
+	name  string
+	field Field
+	desc  bool
+}
+
+func (s resolvedSort) expr() string {
+	if s.field.Nullable {
+		return fmt.Sprintf("COALESCE(%s, %s)", s.field.Column, s.field.Type.nullSentinel())
+	}
+	return s.field.Column
+}
+
+// signature identifies the sort order a cursor was issued for
+func signature(sorts []resolvedSort) string {
+	parts := make([]string, len(sorts))
+	for i, s := range sorts {
+		parts[i] = s.name
+		if s.desc {
+			parts[i] = "-" + parts[i]
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+func (s *ListSchema) idColumn() string {
+	if s.IDColumn != "" {
+		return s.IDColumn
+	}
+	return "id"
+}
+
+// resolveSort validates requested sort keys against the whitelist and appends the ID tie-breaker
+func (s *ListSchema) resolveSort(requested []SortField) ([]resolvedSort, error) {
+	if len(requested) == 0 {
+		for _, key := range s.DefaultSort {
+			requested = append(requested, SortField{
+				Field: strings.TrimPrefix(key, "-"),
+				Desc:  strings.HasPrefix(key, "-"),
+			})
+		}
+	}
+
+	sorts := make([]resolvedSort, 0, len(requested)+1)
+	hasID := false
+	for _, r := range requested {
+		field, ok := s.Fields[r.Field]
+		if !ok || !field.Sort {
+			return nil, errors.Validation(nil).WithDetail("sort", "validation.unsortable", map[string]string{"field": r.Field})
+		}
+		if field.Column == s.idColumn() {
+			hasID = true
+		}
+		sorts = append(sorts, resolvedSort{name: r.Field, field: field, desc: r.Desc})
+	}
+
+	if !hasID {
+		desc := len(sorts) > 0 && sorts[len(sorts)-1].desc
+		sorts = append(sorts, resolvedSort{
+			name:  "id",
+			field: Field{Column: s.idColumn(), Type: FieldUUID},
+			desc:  desc,
+		})
+	}
+	return sorts, nil
+}
+
+// filterClause validates a filter against the whitelist and renders it
+func (s *ListSchema) filterClause(b *queryBuilder, f Filter) (string, error) {
+	field, ok := s.Fields[f.Field]
+	if !ok || !field.Filter {
+		return "", errors.Validation(nil).WithDetail("filter", "validation.unfilterable", map[string]string{"field": f.Field})
+	}
+
+	invalid := func(messageKey string, params ...map[string]string) error {
+		return errors.Validation(nil).WithDetail("filter["+f.Field+"]", messageKey, params...)
+	}
+
+	op := f.Op
+	if op == "" {
+		op = OpEq
+	}
+
+	switch op {
+	case OpNull:
+		isNull, err := strconv.ParseBool(f.Value)
+		if err != nil {
+			return "", invalid("validation.boolean")
+		}
+		if isNull {
+			return field.Column + " IS NULL", nil
+		}
+		return field.Column + " IS NOT NULL", nil
+
+	case OpIn:
+		values := strings.Split(f.Value, ",")
+		placeholders := make([]string, 0, len(values))
+		for _, raw := range values {
+			v, invalidKey := parseValue(field.Type, strings.TrimSpace(raw))
+			if invalidKey != "" {
+				return "", invalid(invalidKey)
+			}
+			placeholders = append(placeholders, b.param(v, field.Type))
+		}
+		return fmt.Sprintf("%s IN (%s)", field.Column, strings.Join(placeholders, ", ")), nil
+
+	case OpLike:
+		if field.Type != FieldText {
+			return "", invalid("validation.like_text_only")
+		}
+		pattern := "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(f.Value) + "%"
+		return fmt.Sprintf("%s ILIKE %s", field.Column, b.param(pattern, FieldText)), nil
+	}
+
+	sqlOp, ok := map[string]string{
+		OpEq: "=", OpNe: "<>", OpLt: "<", OpLte: "<=", OpGt: ">", OpGte: ">=",
+	}[op]
+	if !ok {
+		return "", invalid("validation.unsupported_operator", map[string]string{"op": op})
+	}
+
+	v, invalidKey := parseValue(field.Type, f.Value)
+	if invalidKey != "" {
+		return "", invalid(invalidKey)
+	}
+	return fmt.Sprintf("%s %s %s", field.Column, sqlOp, b.param(v, field.Type)), nil
+}
+
+// parseValue validates a filter value for its field type. An invalid value
+// yields the message key describing the expected format.
+func parseValue(t FieldType, raw string) (interface{}, string) {
+	switch t {
+	case FieldInt:
+		v, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, "validation.integer"
+		}
+		return v, ""
+	case FieldNumeric:
+		if _, err := strconv.ParseFloat(raw, 64); err != nil {
+			return nil, "validation.number"
+		}
+		return raw, ""
+	case FieldBool:
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, "validation.boolean"
+		}
+		return v, ""
+	case FieldDate:
+		v, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			return nil, "validation.date"
+		}
+		return v.Format("2006-01-02"), ""
+	case FieldTime:
+		if v, err := time.Parse(time.RFC3339, raw); err == nil {
+			return v, ""
+		}
+		if v, err := time.Parse("2006-01-02", raw); err == nil {
+			return v, ""
+		}
+		return nil, "validation.timestamp"
+	case FieldTimeOfDay:
+		if _, err := time.Parse("15:04", raw); err == nil {
+			return raw, ""
+		}
+		if _, err := time.Parse("15:04:05", raw); err == nil {
+			return raw, ""
+		}
+		return nil, "validation.time_of_day"
+	case FieldUUID:
+		if _, err := uuid.Parse(raw); err != nil {
+			return nil, "validation.uuid"
+		}
+		return raw, ""
+	default:
+		return raw, ""
+	}
+}
+
+// keysetClause renders "rows after the cursor" for mixed sort directions:
+// (a > x) OR (a = x AND b > y) OR (a = x AND b = y AND id > z)
+func keysetClause(b *queryBuilder, sorts []resolvedSort, values []interface{}) string {
+	params := make([]string, len(sorts))
+	for i, s := range sorts {
+		params[i] = b.param(values[i], s.field.Type)
+		if s.field.Nullable {
+			params[i] = fmt.Sprintf("COALESCE(%s, %s)", params[i], s.field.Type.nullSentinel())
+		}
+	}
+
+	var ors []string
+	for i, s := range sorts {
+		var ands []string
+		for j := 0; j < i; j++ {
+			ands = append(ands, fmt.Sprintf("%s = %s", sorts[j].expr(), params[j]))
+		}
+		op := ">"
+		if s.desc {
+			op = "<"
+		}
+		ands = append(ands, fmt.Sprintf("%s %s %s", s.expr(), op, params[i]))
+		ors = append(ors, "("+strings.Join(ands, " AND ")+")")
+	}
+	return "(" + strings.Join(ors, " OR ") + ")"
+}
+
+// cursorPayload is the opaque content of next_cursor
+type cursorPayload struct {
+	Sort   string        `json:"s"`
+	Values []interface{} `json:"v"`
+}
+
+// encodeCursor captures the sort key values of the last row
+func encodeCursor(sorts []resolvedSort, row interface{}) (string, error) {
+	v := reflect.ValueOf(row)
+	for v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return "", fmt.Errorf("cursor: row must be a struct, got %s", v.Kind())
+	}
+
+	payload := cursorPayload{Sort: signature(sorts)}
+	for _, s := range sorts {
+		column := s.field.Column
+		if idx := strings.LastIndex(column, "."); idx >= 0 {
+			column = column[idx+1:]
+		}
+		fv, ok := fieldByTag(v, column)
+		if !ok {
+			return "", fmt.Errorf("cursor: row has no field with db tag %q", column)
+		}
+		for fv.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				break
+			}
+			fv = fv.Elem()
+		}
+		if fv.Kind() == reflect.Pointer {
+			payload.Values = append(payload.Values, nil)
+			continue
+		}
+		value := fv.Interface()
+		if t, ok := value.(time.Time); ok && s.field.Type == FieldDate {
+			value = t.Format("2006-01-02")
+		}
+		payload.Values = append(payload.Values, value)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor validates a cursor against the current sort order
+func decodeCursor(cursor string, sorts []resolvedSort) ([]interface{}, error) {
+	invalid := errors.Validation(map[string]string{"cursor": "validation.cursor"})
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, invalid
+	}
+
+	var payload cursorPayload
+	dec := json.NewDecoder(strings.NewReader(string(data)))
+	dec.UseNumber()
+	if err := dec.Decode(&payload); err != nil {
+		return nil, invalid
+	}
+	if payload.Sort != signature(sorts) || len(payload.Values) != len(sorts) {
+		return nil, invalid
+	}
+
+	for i, v := range payload.Values {
+		if n, ok := v.(json.Number); ok {
+			payload.Values[i] = n.String()
+		}
+	}
+	return payload.Values, nil
+}
+
+// fieldByTag finds a (possibly embedded) struct field by its db tag
+func fieldByTag(v reflect.Value, tag string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if name := strings.Split(sf.Tag.Get("db"), ",")[0]; name == tag {
+			return v.Field(i), true
+		}
+		if sf.Anonymous {
+			inner := v.Field(i)
+			for inner.Kind() == reflect.Pointer {
+				if inner.IsNil() {
+					break
+				}
+				inner = inner.Elem()
+			}
+			if inner.Kind() == reflect.Struct {
+				if fv, ok := fieldByTag(inner, tag); ok {
+					return fv, true
+				}
+			}
+		}
+	}
+	return reflect.Value{}, false
+}
@@ -0,0 +1,179 @@
+package database
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testListSchema = ListSchema{
+	Fields: map[string]Field{
+		"name":        {Column: "i.name", Type: FieldText, Sort: true},
+		"expiry_date": {Column: "i.expiry_date", Type: FieldDate, Sort: true, Nullable: true},
+		"quantity":    {Column: "i.quantity", Type: FieldInt, Sort: true},
+	},
+	DefaultSort: []string{"name"},
+}
+
+type testListRow struct {
+	ID         string     `db:"id"`
+	Name       string     `db:"name"`
+	ExpiryDate *time.Time `db:"expiry_date"`
+	Quantity   int        `db:"quantity"`
+}
+
+func mustResolveSort(t *testing.T, keys ...SortField) []resolvedSort {
+	t.Helper()
+	sorts, err := testListSchema.resolveSort(keys)
+	require.NoError(t, err)
+	return sorts
+}
+
+func TestKeysetClause(t *testing.T) {
+	tests := []struct {
+		name   string
+		sort   []SortField
+		values []interface{}
+		want   string
+	}{
+		{
+			name:   "default sort appends ascending id",
+			values: []interface{}{"Aspirin", "00000000-0000-0000-0000-000000000001"},
+			want:   "((i.name > $1::text) OR (i.name = $1::text AND id > $2::uuid))",
+		},
+		{
+			name:   "mixed directions",
+			sort:   []SortField{{Field: "quantity", Desc: true}, {Field: "name"}},
+			values: []interface{}{"5", "Aspirin", "00000000-0000-0000-0000-000000000001"},
+			want: "((i.quantity < $1::bigint)" +
+				" OR (i.quantity = $1::bigint AND i.name > $2::text)" +
+				" OR (i.quantity = $1::bigint AND i.name = $2::text AND id > $3::uuid))",
+		},
+		{
+			name:   "nullable key compares via sentinel",
+			sort:   []SortField{{Field: "expiry_date", Desc: true}},
+			values: []interface{}{nil, "00000000-0000-0000-0000-000000000001"},
+			want: "((COALESCE(i.expiry_date, 'infinity'::date) < COALESCE($1::date, 'infinity'::date))" +
+				" OR (COALESCE(i.expiry_date, 'infinity'::date) = COALESCE($1::date, 'infinity'::date) AND id < $2::uuid))",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &queryBuilder{args: []interface{}{}}
+			got := keysetClause(b, mustResolveSort(t, tt.sort...), tt.values)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.values, b.args)
+		})
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	expiry := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		sort []SortField
+		row  *testListRow
+		want []interface{}
+	}{
+		{
+			name: "date and id",
+			sort: []SortField{{Field: "expiry_date"}},
+			row:  &testListRow{ID: "00000000-0000-0000-0000-000000000001", ExpiryDate: &expiry},
+			want: []interface{}{"2025-06-30", "00000000-0000-0000-0000-000000000001"},
+		},
+		{
+			name: "NULL sort key",
+			sort: []SortField{{Field: "expiry_date", Desc: true}},
+			row:  &testListRow{ID: "00000000-0000-0000-0000-000000000002"},
+			want: []interface{}{nil, "00000000-0000-0000-0000-000000000002"},
+		},
+		{
+			name: "numbers stay exact",
+			sort: []SortField{{Field: "quantity", Desc: true}, {Field: "name"}},
+			row:  &testListRow{ID: "00000000-0000-0000-0000-000000000003", Name: "Tupfer", Quantity: 9007199254740993},
+			want: []interface{}{"9007199254740993", "Tupfer", "00000000-0000-0000-0000-000000000003"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sorts := mustResolveSort(t, tt.sort...)
+			cursor, err := encodeCursor(sorts, tt.row)
+			require.NoError(t, err)
+
+			values, err := decodeCursor(cursor, sorts)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, values)
+		})
+	}
+}
+
+func TestDecodeCursor_Tampered(t *testing.T) {
+	sorts := mustResolveSort(t, SortField{Field: "name"})
+	encode := func(payload string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(payload))
+	}
+
+	tests := map[string]string{
+		"not base64":        "%%%",
+		"padded base64":     base64.URLEncoding.EncodeToString([]byte(`{"s":"name,id","v":["a","b"]}`)),
+		"not json":          encode(`name,id|a|b`),
+		"other sort order":  encode(`{"s":"-name,-id","v":["a","b"]}`),
+		"missing values":    encode(`{"s":"name,id","v":["a"]}`),
+		"extra values":      encode(`{"s":"name,id","v":["a","b","c"]}`),
+		"values not a list": encode(`{"s":"name,id","v":"a"}`),
+		"empty payload":     encode(`{}`),
+	}
+
+	for name, cursor := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := decodeCursor(cursor, sorts)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseValue(t *testing.T) {
+	tests := []struct {
+		name       string
+		fieldType  FieldType
+		raw        string
+		want       interface{}
+		invalidKey string
+	}{
+		{"int", FieldInt, "42", int64(42), ""},
+		{"int rejects decimals", FieldInt, "4.2", nil, "validation.integer"},
+		{"numeric keeps text", FieldNumeric, "4.20", "4.20", ""},
+		{"numeric rejects words", FieldNumeric, "four", nil, "validation.number"},
+		{"bool", FieldBool, "true", true, ""},
+		{"bool rejects yes", FieldBool, "yes", nil, "validation.boolean"},
+		{"date", FieldDate, "2025-06-30", "2025-06-30", ""},
+		{"date rejects German format", FieldDate, "30.06.2025", nil, "validation.date"},
+		{"timestamp", FieldTime, "2025-06-30T08:00:00+02:00", time.Date(2025, 6, 30, 8, 0, 0, 0, time.FixedZone("", 2*3600)), ""},
+		{"timestamp accepts date", FieldTime, "2025-06-30", time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC), ""},
+		{"timestamp rejects garbage", FieldTime, "yesterday", nil, "validation.timestamp"},
+		{"time of day", FieldTimeOfDay, "08:30", "08:30", ""},
+		{"time of day with seconds", FieldTimeOfDay, "08:30:15", "08:30:15", ""},
+		{"time of day rejects 25h", FieldTimeOfDay, "25:00", nil, "validation.time_of_day"},
+		{"uuid", FieldUUID, "00000000-0000-0000-0000-000000000001", "00000000-0000-0000-0000-000000000001", ""},
+		{"uuid rejects SQL", FieldUUID, "1' OR '1'='1", nil, "validation.uuid"},
+		{"text passes through", FieldText, "O'Brien", "O'Brien", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, invalidKey := parseValue(tt.fieldType, tt.raw)
+			assert.Equal(t, tt.invalidKey, invalidKey)
+			if want, ok := tt.want.(time.Time); ok {
+				require.IsType(t, time.Time{}, got)
+				assert.True(t, want.Equal(got.(time.Time)), "got %v", got)
+			} else if tt.invalidKey == "" {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type readOnlyKey struct{}
+
+type statementTimeoutKey struct{}
+
+// ReadOnly marks ctx as read-only work (exports, list endpoints, alert scans).
+//
+// WithTenantRLS routes read-only work to a read replica when one is configured,
+// inside a READ ONLY transaction with the same search_path and tenant settings.
+// Without replicas the work still runs in a READ ONLY transaction on the
+// primary, so accidental writes fail the same way in every environment.
+//
+// Replicas lag behind the primary. Do not mark reads that must observe a write
+// made earlier in the same request (read-your-writes) or dedup checks that
+// guard an insert.
+func ReadOnly(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readOnlyKey{}, true)
+}
+
+// IsReadOnly reports whether ctx was marked with ReadOnly
+func IsReadOnly(ctx context.Context) bool {
+	readOnly, _ := ctx.Value(readOnlyKey{}).(bool)
+	return readOnly
+}
+
+// WithStatementTimeout overrides the configured statement timeout for
+// transactions started with ctx (e.g. longer for exports, shorter for lookups).
+// A zero duration disables the timeout.
+func WithStatementTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, statementTimeoutKey{}, timeout)
+}
+
+// AddReplica connects a read replica by DSN and adds it to the routing pool
+func (db *DB) AddReplica(dsn string) error {
+	replica, err := sqlx.Connect("postgres", dsn)
+	if err != nil {
+		return fmt.Errorf("failed to connect to replica: %w", err)
+	}
+	db.replicas = append(db.replicas, replica)
+	return nil
+}
+
+// SetStatementTimeout sets the default statement timeout for transactions
+func (db *DB) SetStatementTimeout(timeout time.Duration) {
+	db.statementTimeout = timeout
+}
+
+// HasReplicas reports whether read replicas are configured
+func (db *DB) HasReplicas() bool {
+	return len(db.replicas) > 0
+}
+
+// statementTimeoutFor returns the effective statement timeout for ctx
+func (db *DB) statementTimeoutFor(ctx context.Context) time.Duration {
+	if timeout, ok := ctx.Value(statementTimeoutKey{}).(time.Duration); ok {
+		return timeout
+	}
+	return db.statementTimeout
+}
+
+// beginTx starts a transaction on pool and applies the statement timeout
+func (db *DB) beginTx(ctx context.Context, pool *sqlx.DB, opts *sql.TxOptions) (*sqlx.Tx, error) {
+	tx, err := pool.BeginTxx(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if timeout := db.statementTimeoutFor(ctx); timeout > 0 {
+		// SET LOCAL doesn't support parameters; the value is an integer we format ourselves
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("SET LOCAL statement_timeout = %d", timeout.Milliseconds())); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to set statement_timeout: %w", err)
+		}
+	}
+
+	return tx, nil
+}
+
+// beginReadOnlyTx starts a READ ONLY transaction on the next replica
+// (round-robin). If the replica cannot be reached it falls back to the primary.
+func (db *DB) beginReadOnlyTx(ctx context.Context) (*sqlx.Tx, error) {
+	opts := &sql.TxOptions{ReadOnly: true}
+
+	if len(db.replicas) > 0 {
+		idx := int(db.nextReplica.Add(1)-1) % len(db.replicas)
+		tx, err := db.beginTx(ctx, db.replicas[idx], opts)
+		if err == nil {
+			db.routing.replicaReads.Add(1)
+			return tx, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		db.routing.replicaFallbacks.Add(1)
+		db.logger.Warn().Err(err).Int("replica", idx+1).Msg("read replica unavailable, falling back to primary")
+	}
+
+	db.routing.primaryReads.Add(1)
+	return db.beginTx(ctx, db.DB, opts)
+}
+
+// routingCounters counts how read-only transactions were routed
+type routingCounters struct {
+	replicaReads     atomic.Uint64
+	primaryReads     atomic.Uint64
+	replicaFallbacks atomic.Uint64
+}
+
+// PoolStat is a snapshot of one connection pool
+type PoolStat struct {
+	MaxOpenConnections int           `json:"max_open_connections"`
+	OpenConnections    int           `json:"open_connections"`
+	InUse              int           `json:"in_use"`
+	Idle               int           `json:"idle"`
+	WaitCount          int64         `json:"wait_count"`
+	WaitDuration       time.Duration `json:"wait_duration"`
+	MaxIdleClosed      int64         `json:"max_idle_closed"`
+	MaxLifetimeClosed  int64         `json:"max_lifetime_closed"`
+}
+
+// PoolStats is a snapshot of the primary and replica pools plus routing counters
+type PoolStats struct {
+	Primary          PoolStat   `json:"primary"`
+	Replicas         []PoolStat `json:"replicas,omitempty"`
+	ReplicaReads     uint64     `json:"replica_reads"`
+	PrimaryReads     uint64     `json:"primary_reads"`
+	ReplicaFallbacks uint64     `json:"replica_fallbacks"`
+}
+
+// PoolStats returns connection pool metrics for the primary and all replicas
+func (db *DB) PoolStats() PoolStats {
+	stats := PoolStats{
+		Primary:          toPoolStat(db.DB.Stats()),
+		ReplicaReads:     db.routing.replicaReads.Load(),
+		PrimaryReads:     db.routing.primaryReads.Load(),
+		ReplicaFallbacks: db.routing.replicaFallbacks.Load(),
+	}
+	for _, replica := range db.replicas {
+		stats.Replicas = append(stats.Replicas, toPoolStat(replica.Stats()))
+	}
+	return stats
+}
+
+func toPoolStat(s sql.DBStats) PoolStat {
+	return PoolStat{
+		MaxOpenConnections: s.MaxOpenConnections,
+		OpenConnections:    s.OpenConnections,
+		InUse:              s.InUse,
+		Idle:               s.Idle,
+		WaitCount:          s.WaitCount,
+		WaitDuration:       s.WaitDuration,
+		MaxIdleClosed:      s.MaxIdleClosed,
+		MaxLifetimeClosed:  s.MaxLifetimeClosed,
+	}
+}
@@ -0,0 +1,258 @@
+package database_test
+
+import (
+	"context"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/tenant"
+	"github.com/medflow/medflow-backend/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var suite *testutil.IntegrationSuite
+
+func TestMain(m *testing.M) {
+	ctx := context.Background()
+
+	var err error
+	suite, err = testutil.NewIntegrationSuite(ctx, "inventory, public")
+	if err != nil {
+		log.Fatalf("failed to create integration suite: %v", err)
+	}
+	defer suite.Cleanup(ctx)
+	defer testutil.TerminateContainer(ctx)
+
+	os.Exit(m.Run())
+}
+
+// servedByStandby reports whether the connection serving the transaction is the replica
+func servedByStandby(t *testing.T, db *database.DB, ctx context.Context) bool {
+	t.Helper()
+	tenantID, err := tenant.TenantID(ctx)
+	require.NoError(t, err)
+
+	var inRecovery bool
+	err = db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		return db.GetContext(ctx, &inRecovery, `SELECT pg_is_in_recovery()`)
+	})
+	require.NoError(t, err)
+	return inRecovery
+}
+
+func createRoom(ctx context.Context, db *database.DB, name string) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+	return db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		_, err := db.ExecContext(ctx,
+			`INSERT INTO storage_rooms (tenant_id, name) VALUES ($1, $2)`, tenantID, name)
+		return err
+	})
+}
+
+func countRooms(t *testing.T, db *database.DB, ctx context.Context) int {
+	t.Helper()
+	tenantID, err := tenant.TenantID(ctx)
+	require.NoError(t, err)
+
+	var count int
+	err = db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		return db.GetContext(ctx, &count, `SELECT COUNT(*) FROM storage_rooms`)
+	})
+	require.NoError(t, err)
+	return count
+}
+
+func TestWithTenantRLS_ReadOnlyRoutesToReplica(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tn := suite.SetupInventoryTenant(t, ctx, "replica-routing")
+	tenantCtx := suite.TenantContext(tn)
+
+	db := suite.NewReplicatedDB(t, "inventory, public")
+
+	assert.False(t, servedByStandby(t, db, tenantCtx), "unmarked work must use the primary")
+	assert.True(t, servedByStandby(t, db, database.ReadOnly(tenantCtx)), "read-only work must use the replica")
+
+	stats := db.PoolStats()
+	assert.Equal(t, uint64(1), stats.ReplicaReads)
+	assert.Len(t, stats.Replicas, 1)
+}
+
+func TestWithTenantRLS_WritesStayOnPrimary(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tn := suite.SetupInventoryTenant(t, ctx, "replica-writes")
+	tenantCtx := suite.TenantContext(tn)
+
+	db := suite.NewReplicatedDB(t, "inventory, public")
+
+	require.NoError(t, createRoom(tenantCtx, db, "Primary Room"))
+
+	// Writes in read-only work are rejected instead of silently hitting the primary
+	err := createRoom(database.ReadOnly(tenantCtx), db, "Replica Room")
+	require.Error(t, err)
+
+	// The replica sees the committed write from the primary once replayed
+	require.NoError(t, suite.Replica(t).WaitForReplay(ctx))
+	assert.Equal(t, 1, countRooms(t, db, database.ReadOnly(tenantCtx)))
+}
+
+func TestWithTenantRLS_ReplicaLagHidesRecentWrites(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tn := suite.SetupInventoryTenant(t, ctx, "replica-lag")
+	tenantCtx := suite.TenantContext(tn)
+
+	db := suite.NewReplicatedDB(t, "inventory, public")
+	replica := suite.Replica(t)
+
+	require.NoError(t, createRoom(tenantCtx, db, "Room 1"))
+	require.NoError(t, replica.WaitForReplay(ctx))
+
+	require.NoError(t, replica.PauseReplay(ctx))
+	t.Cleanup(func() { replica.ResumeReplay(ctx) })
+
+	// Read-your-writes gap: the primary has the write, the lagging replica not yet
+	require.NoError(t, createRoom(tenantCtx, db, "Room 2"))
+	assert.Equal(t, 2, countRooms(t, db, tenantCtx))
+	assert.Equal(t, 1, countRooms(t, db, database.ReadOnly(tenantCtx)))
+
+	require.NoError(t, replica.ResumeReplay(ctx))
+	require.NoError(t, replica.WaitForReplay(ctx))
+	assert.Equal(t, 2, countRooms(t, db, database.ReadOnly(tenantCtx)))
+}
+
+func TestWithTenantRLS_ReplicaKeepsTenantIsolation(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tenantA := suite.SetupInventoryTenant(t, ctx, "replica-rls-a")
+	tenantB := suite.SetupInventoryTenant(t, ctx, "replica-rls-b")
+
+	db := suite.NewReplicatedDB(t, "inventory, public")
+
+	require.NoError(t, createRoom(suite.TenantContext(tenantA), db, "Room A1"))
+	require.NoError(t, createRoom(suite.TenantContext(tenantA), db, "Room A2"))
+	require.NoError(t, suite.Replica(t).WaitForReplay(ctx))
+
+	assert.Equal(t, 2, countRooms(t, db, database.ReadOnly(suite.TenantContext(tenantA))))
+	assert.Equal(t, 0, countRooms(t, db, database.ReadOnly(suite.TenantContext(tenantB))))
+}
+
+func TestWithTenantRLS_ReadOnlyWithoutReplicaUsesPrimary(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tn := suite.SetupInventoryTenant(t, ctx, "replica-none")
+	tenantCtx := suite.TenantContext(tn)
+
+	require.NoError(t, createRoom(tenantCtx, suite.DB, "Room"))
+	assert.Equal(t, 1, countRooms(t, suite.DB, database.ReadOnly(tenantCtx)))
+	assert.Error(t, createRoom(database.ReadOnly(tenantCtx), suite.DB, "Rejected Room"))
+	assert.Equal(t, uint64(1), suite.DB.PoolStats().PrimaryReads)
+}
+
+func TestWithTenantRLS_StatementTimeout(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tn := suite.SetupInventoryTenant(t, ctx, "statement-timeout")
+	tenantCtx := suite.TenantContext(tn)
+
+	db := suite.NewReplicatedDB(t, "inventory, public")
+	db.SetStatementTimeout(5 * time.Second)
+
+	sleep := func(ctx context.Context) error {
+		return db.WithTenantRLS(ctx, tn.ID, func(ctx context.Context) error {
+			_, err := db.ExecContext(ctx, `SELECT pg_sleep(0.5)`)
+			return err
+		})
+	}
+
+	// Default timeout is long enough
+	require.NoError(t, sleep(tenantCtx))
+
+	// Per-call override cancels the statement, on primary and replica alike
+	for _, c := range []context.Context{tenantCtx, database.ReadOnly(tenantCtx)} {
+		err := sleep(database.WithStatementTimeout(c, 100*time.Millisecond))
+		require.Error(t, err)
+		var pqErr *pq.Error
+		require.ErrorAs(t, err, &pqErr)
+		assert.Equal(t, pq.ErrorCode("57014"), pqErr.Code, "expected query_canceled")
+	}
+}
+
+func TestWithTenantRLS_NestedCalls(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tn := suite.SetupInventoryTenant(t, ctx, "nested-tx")
+	tenantCtx := suite.TenantContext(tn)
+
+	// Calls marked with InTx roll back with the enclosing transaction
+	err := suite.DB.WithTenantRLS(tenantCtx, tn.ID, func(ctx context.Context) error {
+		ctx = database.InTx(ctx)
+		require.NoError(t, createRoom(ctx, suite.DB, "Room 1"))
+		require.NoError(t, createRoom(ctx, suite.DB, "Room 2"))
+		assert.Equal(t, 2, countRooms(t, suite.DB, ctx), "joined reads see the uncommitted writes")
+		return assert.AnError
+	})
+	require.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, 0, countRooms(t, suite.DB, tenantCtx))
+
+	// Unmarked nested calls commit on their own
+	err = suite.DB.WithTenantRLS(tenantCtx, tn.ID, func(ctx context.Context) error {
+		require.NoError(t, createRoom(ctx, suite.DB, "Room 3"))
+		return assert.AnError
+	})
+	require.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, 1, countRooms(t, suite.DB, tenantCtx))
+}
+
+func TestWithTenantRLS_JoinModeMismatch(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	tn := suite.SetupInventoryTenant(t, ctx, "nested-mismatch")
+	tenantCtx := suite.TenantContext(tn)
+
+	join := func(outer context.Context, inner func(context.Context) context.Context) error {
+		return suite.DB.WithTenantRLS(outer, tn.ID, func(ctx context.Context) error {
+			return suite.DB.WithTenantRLS(inner(database.InTx(ctx)), tn.ID, func(ctx context.Context) error {
+				return nil
+			})
+		})
+	}
+	same := func(ctx context.Context) context.Context { return ctx }
+
+	require.NoError(t, join(tenantCtx, same))
+	require.NoError(t, join(database.ReadOnly(tenantCtx), same))
+
+	// Read-only inner call in a read-write transaction
+	assert.ErrorIs(t, join(tenantCtx, database.ReadOnly), database.ErrTxModeMismatch)
+
+	// Different statement timeout
+	shorter := func(ctx context.Context) context.Context {
+		return database.WithStatementTimeout(ctx, 100*time.Millisecond)
+	}
+	assert.ErrorIs(t, join(tenantCtx, shorter), database.ErrTxModeMismatch)
+}
@@ -2,13 +2,37 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 )
 
 type txKey struct{}
 
+type joinTxKey struct{}
+
+// ErrTxModeMismatch is returned when a call joining an enclosing transaction
+// asks for a different read-only mode or statement timeout than it runs with.
+var ErrTxModeMismatch = errors.New("joined transaction has a different read-only mode or statement timeout")
+
+// tenantTx is the transaction WithTenantRLS stores in the context
+type tenantTx struct {
+	tx       *sqlx.Tx
+	tenantID string
+	readOnly bool
+	timeout  time.Duration
+}
+
+// InTx marks ctx so that WithTenantRLS calls made with it join the enclosing
+// tenant transaction instead of starting their own. Use it to make several
+// repository calls commit or roll back together (e.g. a change and its audit
+// entry). Without it every WithTenantRLS call commits independently.
+func InTx(ctx context.Context) context.Context {
+	return context.WithValue(ctx, joinTxKey{}, true)
+}
+
 // WithTenantRLS executes a function with RLS-based tenant isolation.
 // This is the KEY isolation mechanism for RLS-based pooled multi-tenancy.
 //
@@ -32,8 +56,40 @@ type txKey struct{}
 //   - Even with connection pooling (PgBouncer), next request gets clean state
 //   - RLS policies are enforced by PostgreSQL engine — app code can't bypass them
 //   - WITH CHECK prevents inserting rows for wrong tenant
+//
+// Contexts marked with ReadOnly run in a READ ONLY transaction on a read
+// replica when configured (see replica.go); RLS applies there identically.
+// The statement timeout (see WithStatementTimeout) bounds every query in fn.
+//
+// Nested calls start their own transaction unless ctx is marked with InTx.
+// A marked call for the same tenant joins the enclosing transaction; it
+// fails with ErrTxModeMismatch if ctx asks for a different read-only mode or
+// statement timeout, since those are fixed when the transaction begins.
 func (db *DB) WithTenantRLS(ctx context.Context, tenantID string, fn func(context.Context) error) error {
-	return db.Transaction(ctx, func(tx *sqlx.Tx) error {
+	readOnly := IsReadOnly(ctx)
+	timeout := db.statementTimeoutFor(ctx)
+
+	if join, _ := ctx.Value(joinTxKey{}).(bool); join {
+		if outer, ok := ctx.Value(txKey{}).(*tenantTx); ok && outer.tenantID == tenantID {
+			if readOnly != outer.readOnly || timeout != outer.timeout {
+				return ErrTxModeMismatch
+			}
+			return fn(ctx)
+		}
+	}
+
+	var tx *sqlx.Tx
+	var err error
+	if readOnly {
+		tx, err = db.beginReadOnlyTx(ctx)
+	} else {
+		tx, err = db.beginTx(ctx, db.DB, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	return db.runTx(tx, func(tx *sqlx.Tx) error {
 		// Set search_path for the service schema
 		searchPath := db.searchPath
 		if searchPath == "" {
@@ -52,7 +108,12 @@ func (db *DB) WithTenantRLS(ctx context.Context, tenantID string, fn func(contex
 		}
 
 		// Store transaction in context so DB methods can use it
-		txCtx := context.WithValue(ctx, txKey{}, tx)
+		txCtx := context.WithValue(ctx, txKey{}, &tenantTx{
+			tx:       tx,
+			tenantID: tenantID,
+			readOnly: readOnly,
+			timeout:  timeout,
+		})
 
 		return fn(txCtx)
 	})
@@ -60,8 +121,8 @@ func (db *DB) WithTenantRLS(ctx context.Context, tenantID string, fn func(contex
 
 // getTx extracts transaction from context if present
 func (db *DB) getTx(ctx context.Context) *sqlx.Tx {
-	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
-		return tx
+	if t, ok := ctx.Value(txKey{}).(*tenantTx); ok {
+		return t.tx
 	}
 	return nil
 }
@@ -23,15 +23,20 @@ var (
 	ErrTokenInvalid       = errors.New("invalid token")
 )
 
-// AppError represents an application error with context
+// AppError represents an application error with context.
+//
+// Errors are built from i18n message keys: Message holds the English text for
+// logs and MessageKey/Params are localized per request by ErrorLocalized.
+// Details map field names to message keys, interpolated with DetailParams.
 type AppError struct {
-	Err        error             `json:"-"`
-	Message    string            `json:"message"`
-	MessageKey string            `json:"-"` // i18n key for localization
-	Params     map[string]string `json:"-"` // Parameters for i18n interpolation
-	Code       string            `json:"code"`
-	StatusCode int               `json:"status_code"`
-	Details    map[string]string `json:"details,omitempty"`
+	Err          error                        `json:"-"`
+	Message      string                       `json:"message"`
+	MessageKey   string                       `json:"-"` // i18n key for localization
+	Params       map[string]string            `json:"-"` // Parameters for i18n interpolation
+	Code         string                       `json:"code"`
+	StatusCode   int                          `json:"status_code"`
+	Details      map[string]string            `json:"details,omitempty"`
+	DetailParams map[string]map[string]string `json:"-"` // Parameters per detail field
 }
 
 // Error implements the error interface
@@ -49,10 +54,7 @@ func (e *AppError) Unwrap() error {
 
 // Localize returns a localized version of the error message
 func (e *AppError) Localize(ctx context.Context) string {
-	if e.MessageKey == "" {
-		return e.Message
-	}
-	return i18n.TFromContext(ctx, e.MessageKey, e.Params)
+	return e.LocalizeWith(i18n.LocalizerFromContext(ctx))
 }
 
 // LocalizeWith returns a localized version using a specific localizer
@@ -60,7 +62,35 @@ func (e *AppError) LocalizeWith(l *i18n.Localizer) string {
 	if e.MessageKey == "" {
 		return e.Message
 	}
-	return l.T(e.MessageKey, e.Params)
+	return l.T(e.MessageKey, e.localizedParams(l))
+}
+
+// LocalizeDetails returns the details with every message key translated.
+// Values that are not keys (e.g. from older callers) are returned unchanged.
+func (e *AppError) LocalizeDetails(l *i18n.Localizer) map[string]string {
+	if len(e.Details) == 0 {
+		return e.Details
+	}
+	details := make(map[string]string, len(e.Details))
+	for field, key := range e.Details {
+		details[field] = l.T(key, e.DetailParams[field])
+	}
+	return details
+}
+
+// localizedParams translates the "resource" parameter (a resources.* key
+// suffix such as "employee") into the localizer's language
+func (e *AppError) localizedParams(l *i18n.Localizer) map[string]string {
+	resource, ok := e.Params["resource"]
+	if !ok || !l.Has("resources."+resource) {
+		return e.Params
+	}
+	params := make(map[string]string, len(e.Params))
+	for k, v := range e.Params {
+		params[k] = v
+	}
+	params["resource"] = l.T("resources." + resource)
+	return params
 }
 
 // New creates a new AppError
@@ -74,11 +104,17 @@ func New(code string, message string, statusCode int) *AppError {
 
 // NewWithKey creates a new AppError with an i18n key
 func NewWithKey(code string, messageKey string, statusCode int, params ...map[string]string) *AppError {
+	return newKeyed(nil, code, messageKey, statusCode, params)
+}
+
+// newKeyed builds an AppError from a message key; Message is the English text
+func newKeyed(err error, code, messageKey string, statusCode int, params []map[string]string) *AppError {
 	var p map[string]string
 	if len(params) > 0 {
 		p = params[0]
 	}
 	return &AppError{
+		Err:        err,
 		Code:       code,
 		Message:    i18n.T(messageKey, p), // Default message in English
 		MessageKey: messageKey,
@@ -103,13 +139,35 @@ func (e *AppError) WithDetails(details map[string]string) *AppError {
 	return e
 }
 
-// Common error constructors
+// WithDetail adds one field detail given as a message key with optional parameters
+func (e *AppError) WithDetail(field, messageKey string, params ...map[string]string) *AppError {
+	if e.Details == nil {
+		e.Details = make(map[string]string)
+	}
+	e.Details[field] = messageKey
+	if len(params) > 0 {
+		if e.DetailParams == nil {
+			e.DetailParams = make(map[string]map[string]string)
+		}
+		e.DetailParams[field] = params[0]
+	}
+	return e
+}
+
+// Common error constructors. Message keys must be string literals so the
+// i18n key check can find them.
 
+// NotFound creates a not found error; resource is a resources.* key suffix
+// such as "employee" and is localized together with the message
 func NotFound(resource string) *AppError {
+	resourceName := resource
+	if key := "resources." + resource; i18n.Exists(key) {
+		resourceName = i18n.T(key)
+	}
 	return &AppError{
 		Err:        ErrNotFound,
 		Code:       "NOT_FOUND",
-		Message:    fmt.Sprintf("%s not found", resource),
+		Message:    fmt.Sprintf("%s not found", resourceName),
 		MessageKey: "errors.not_found",
 		Params:     map[string]string{"resource": resource},
 		StatusCode: http.StatusNotFound,
@@ -117,68 +175,33 @@ func NotFound(resource string) *AppError {
 }
 
 // NotFoundWithKey creates a not found error with localized resource name
+//
+// Deprecated: NotFound localizes the resource name itself.
 func NotFoundWithKey(resourceKey string) *AppError {
-	resourceName := i18n.T("resources." + resourceKey)
-	return &AppError{
-		Err:        ErrNotFound,
-		Code:       "NOT_FOUND",
-		Message:    fmt.Sprintf("%s not found", resourceName),
-		MessageKey: "errors.not_found",
-		Params:     map[string]string{"resource": resourceName},
-		StatusCode: http.StatusNotFound,
-	}
+	return NotFound(resourceKey)
 }
 
-func Unauthorized(message string) *AppError {
-	return &AppError{
-		Err:        ErrUnauthorized,
-		Code:       "UNAUTHORIZED",
-		Message:    message,
-		MessageKey: "errors.unauthorized",
-		StatusCode: http.StatusUnauthorized,
-	}
+func Unauthorized(messageKey string, params ...map[string]string) *AppError {
+	return newKeyed(ErrUnauthorized, "UNAUTHORIZED", messageKey, http.StatusUnauthorized, params)
 }
 
-func Forbidden(message string) *AppError {
-	return &AppError{
-		Err:        ErrForbidden,
-		Code:       "FORBIDDEN",
-		Message:    message,
-		MessageKey: "errors.forbidden",
-		StatusCode: http.StatusForbidden,
-	}
+func Forbidden(messageKey string, params ...map[string]string) *AppError {
+	return newKeyed(ErrForbidden, "FORBIDDEN", messageKey, http.StatusForbidden, params)
 }
 
-func BadRequest(message string) *AppError {
-	return &AppError{
-		Err:        ErrBadRequest,
-		Code:       "BAD_REQUEST",
-		Message:    message,
-		MessageKey: "errors.bad_request",
-		StatusCode: http.StatusBadRequest,
-	}
+func BadRequest(messageKey string, params ...map[string]string) *AppError {
+	return newKeyed(ErrBadRequest, "BAD_REQUEST", messageKey, http.StatusBadRequest, params)
 }
 
-func Conflict(message string) *AppError {
-	return &AppError{
-		Err:        ErrConflict,
-		Code:       "CONFLICT",
-		Message:    message,
-		MessageKey: "errors.conflict",
-		StatusCode: http.StatusConflict,
-	}
+func Conflict(messageKey string, params ...map[string]string) *AppError {
+	return newKeyed(ErrConflict, "CONFLICT", messageKey, http.StatusConflict, params)
 }
 
-func Internal(message string) *AppError {
-	return &AppError{
-		Err:        ErrInternal,
-		Code:       "INTERNAL_ERROR",
-		Message:    message,
-		MessageKey: "errors.internal",
-		StatusCode: http.StatusInternalServerError,
-	}
+func Internal(messageKey string, params ...map[string]string) *AppError {
+	return newKeyed(ErrInternal, "INTERNAL_ERROR", messageKey, http.StatusInternalServerError, params)
 }
 
+// Validation creates a validation error; details map field names to message keys
 func Validation(details map[string]string) *AppError {
 	return &AppError{
 		Err:        ErrValidation,
@@ -0,0 +1,39 @@
+package errors
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/medflow/medflow-backend/pkg/i18n"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyedConstructors(t *testing.T) {
+	err := BadRequest("errors.btm.insufficient_balance", map[string]string{"available": "2.00", "requested": "5.00"})
+
+	assert.Equal(t, http.StatusBadRequest, err.StatusCode)
+	assert.Equal(t, "errors.btm.insufficient_balance", err.MessageKey)
+	assert.Equal(t, "Insufficient BtM balance: 2.00 available, 5.00 requested", err.Message, "Message is the English text")
+	assert.Equal(t, "Unzureichender BtM-Bestand: 2.00 verfügbar, 5.00 angefordert", err.LocalizeWith(i18n.NewLocalizer(i18n.LocaleGerman)))
+	assert.ErrorIs(t, err, ErrBadRequest)
+}
+
+func TestNotFound_LocalizesResource(t *testing.T) {
+	err := NotFound("employee")
+
+	assert.Equal(t, "Employee not found", err.Message)
+	assert.Equal(t, "Mitarbeiter nicht gefunden", err.LocalizeWith(i18n.NewLocalizer(i18n.LocaleGerman)))
+	assert.Equal(t, "Çalışan bulunamadı", err.LocalizeWith(i18n.NewLocalizer(i18n.LocaleTurkish)))
+}
+
+func TestLocalizeDetails(t *testing.T) {
+	err := Validation(map[string]string{"email": "validation.email", "legacy": "free text"}).
+		WithDetail("name", "validation.required", map[string]string{"field": "name"})
+
+	details := err.LocalizeDetails(i18n.NewLocalizer(i18n.LocaleGerman))
+	assert.Equal(t, map[string]string{
+		"email":  "Ungültiges E-Mail-Format",
+		"name":   "name ist erforderlich",
+		"legacy": "free text",
+	}, details)
+}
@@ -0,0 +1,265 @@
+// Package forecast projects item demand from daily consumption history.
+//
+// Demand is modelled as a smoothed level times a weekday factor: practices
+// consume little on weekends and often more on particular weekdays, so each
+// weekday gets a multiplicative factor from the history and the level is
+// simple exponential smoothing of the deseasonalized series. From the
+// forecast it derives a reorder point (lead time demand plus safety stock
+// for a service level), a reorder quantity and the date stock runs out.
+package forecast
+
+import (
+	"math"
+	"time"
+)
+
+// Defaults for Settings
+const (
+	DefaultAlpha        = 0.2
+	DefaultServiceLevel = 0.95
+	DefaultLeadTimeDays = 7
+	DefaultCoverageDays = 30
+	DefaultHorizonDays  = 180
+)
+
+// minSeasonalDays is the history needed before weekday factors are used;
+// with less every weekday counts the same
+const minSeasonalDays = 14
+
+// epsilon absorbs floating point residue, so demand of exactly 10 units is
+// not rounded up to 11
+const epsilon = 1e-9
+
+// warmupDays are skipped when measuring forecast error, while the level
+// settles from its initial value
+const warmupDays = 7
+
+// Settings controls a forecast
+type Settings struct {
+	Alpha        float64 // smoothing factor, 0 < alpha <= 1; higher follows recent days faster
+	ServiceLevel float64 // probability of not running out during the lead time, 0.5 <= level < 1
+	LeadTimeDays int     // days from ordering to delivery
+	CoverageDays int     // days of demand a reorder should cover
+	HorizonDays  int     // how far ahead to look for a stock-out
+}
+
+// DefaultSettings returns the settings used when nothing else is configured
+func DefaultSettings() Settings {
+	return Settings{
+		Alpha:        DefaultAlpha,
+		ServiceLevel: DefaultServiceLevel,
+		LeadTimeDays: DefaultLeadTimeDays,
+		CoverageDays: DefaultCoverageDays,
+		HorizonDays:  DefaultHorizonDays,
+	}
+}
+
+// normalized replaces out-of-range values with defaults
+func (s Settings) normalized() Settings {
+	if s.Alpha <= 0 || s.Alpha > 1 {
+		s.Alpha = DefaultAlpha
+	}
+	if s.ServiceLevel < 0.5 || s.ServiceLevel >= 1 {
+		s.ServiceLevel = DefaultServiceLevel
+	}
+	if s.LeadTimeDays < 0 {
+		s.LeadTimeDays = DefaultLeadTimeDays
+	}
+	if s.CoverageDays <= 0 {
+		s.CoverageDays = DefaultCoverageDays
+	}
+	if s.HorizonDays <= 0 {
+		s.HorizonDays = DefaultHorizonDays
+	}
+	return s
+}
+
+// Forecast is the projected demand of an item
+type Forecast struct {
+	HistoryDays     int
+	Level           float64    // smoothed average daily demand, before weekday factors
+	WeekdayFactors  [7]float64 // indexed by time.Weekday, averaging 1
+	StdDev          float64    // standard deviation of the one-day forecast error
+	LeadTimeDemand  float64
+	SafetyStock     float64
+	ReorderPoint    int
+	ReorderQuantity int
+	StockOutDate    *time.Time // nil if stock lasts beyond the horizon
+	settings        Settings
+	from            time.Time
+}
+
+// Compute forecasts demand from history, the consumption per day starting on
+// the date start, and projects stock from the day after the history ends.
+func Compute(history []float64, start time.Time, stock int, settings Settings) *Forecast {
+	s := settings.normalized()
+	start = Day(start)
+
+	f := &Forecast{
+		HistoryDays: len(history),
+		settings:    s,
+		from:        start.AddDate(0, 0, len(history)),
+	}
+	f.WeekdayFactors = weekdayFactors(history, start)
+
+	if len(history) > 0 {
+		f.smooth(history, start)
+	}
+
+	f.LeadTimeDemand = f.demand(0, s.LeadTimeDays)
+	if f.Level > 0 {
+		f.SafetyStock = z(s.ServiceLevel) * f.StdDev * math.Sqrt(float64(s.LeadTimeDays))
+		f.ReorderPoint = ceil(f.LeadTimeDemand + f.SafetyStock)
+		f.ReorderQuantity = max(ceil(f.demand(0, s.CoverageDays)), 1)
+		f.StockOutDate = f.stockOut(stock)
+	}
+
+	return f
+}
+
+// Daily is the forecast demand on a day
+func (f *Forecast) Daily(day time.Time) float64 {
+	return f.Level * f.WeekdayFactors[day.Weekday()]
+}
+
+// demand sums the forecast over n days starting offset days after the history
+func (f *Forecast) demand(offset, n int) float64 {
+	total := 0.0
+	for d := offset; d < offset+n; d++ {
+		total += f.Daily(f.from.AddDate(0, 0, d))
+	}
+	return total
+}
+
+// smooth sets the level and the error spread by exponential smoothing of the
+// deseasonalized history
+func (f *Forecast) smooth(history []float64, start time.Time) {
+	// Start from the mean of the first week so early days do not dominate
+	// (days with a zero factor carry no information about the level)
+	level := 0.0
+	n := 0
+	for i := 0; i < min(warmupDays, len(history)); i++ {
+		if factor := f.WeekdayFactors[start.AddDate(0, 0, i).Weekday()]; factor > 0 {
+			level += history[i] / factor
+			n++
+		}
+	}
+	if n > 0 {
+		level /= float64(n)
+	}
+
+	var sumSq float64
+	var errors int
+	for i, y := range history {
+		factor := f.WeekdayFactors[start.AddDate(0, 0, i).Weekday()]
+		if i >= warmupDays {
+			e := y - level*factor
+			sumSq += e * e
+			errors++
+		}
+		if factor > 0 {
+			level = f.settings.Alpha*(y/factor) + (1-f.settings.Alpha)*level
+		}
+	}
+
+	f.Level = level
+	if errors > 0 {
+		f.StdDev = math.Sqrt(sumSq / float64(errors))
+	} else {
+		f.StdDev = stdDev(history)
+	}
+}
+
+// stockOut returns the first day projected demand exceeds stock
+func (f *Forecast) stockOut(stock int) *time.Time {
+	remaining := float64(stock)
+	for d := 0; d < f.settings.HorizonDays; d++ {
+		day := f.from.AddDate(0, 0, d)
+		remaining -= f.Daily(day)
+		if remaining < -epsilon {
+			return &day
+		}
+	}
+	return nil
+}
+
+// weekdayFactors returns each weekday's mean demand relative to the overall
+// mean. Weekdays never seen count as average.
+func weekdayFactors(history []float64, start time.Time) [7]float64 {
+	var factors [7]float64
+	for i := range factors {
+		factors[i] = 1
+	}
+	if len(history) < minSeasonalDays {
+		return factors
+	}
+
+	var sums [7]float64
+	var counts [7]int
+	total := 0.0
+	for i, y := range history {
+		wd := start.AddDate(0, 0, i).Weekday()
+		sums[wd] += y
+		counts[wd]++
+		total += y
+	}
+	if total == 0 {
+		return factors
+	}
+	mean := total / float64(len(history))
+
+	sum := 0.0
+	for wd := range factors {
+		if counts[wd] > 0 {
+			factors[wd] = sums[wd] / float64(counts[wd]) / mean
+		}
+		sum += factors[wd]
+	}
+	// Normalize so a week of factors sums to 7
+	for wd := range factors {
+		factors[wd] *= 7 / sum
+	}
+	return factors
+}
+
+func ceil(x float64) int {
+	return int(math.Ceil(x - epsilon))
+}
+
+func stdDev(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	sumSq := 0.0
+	for _, v := range values {
+		sumSq += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(sumSq / float64(len(values)-1))
+}
+
+// z is the standard normal quantile of p, e.g. 1.645 for 0.95
+func z(p float64) float64 {
+	return math.Sqrt2 * math.Erfinv(2*p-1)
+}
+
+// practiceLocation is the time zone practice days are counted in
+var practiceLocation = func() *time.Location {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}()
+
+// Day returns the practice day (Europe/Berlin) of t, the granularity of
+// consumption history. The day is given as its date at midnight UTC, so days
+// are counted without daylight saving shifts.
+func Day(t time.Time) time.Time {
+	y, m, d := t.In(practiceLocation).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,97 @@
+package forecast
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// monday is the first day of the test histories
+var monday = time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+// weeks repeats a Monday-first weekly pattern
+func weeks(n int, pattern [7]float64) []float64 {
+	history := make([]float64, 0, n*7)
+	for i := 0; i < n; i++ {
+		history = append(history, pattern[:]...)
+	}
+	return history
+}
+
+func TestCompute_WeekdaySeasonality(t *testing.T) {
+	// Closed on weekends, busy on Mondays: 10 a week
+	history := weeks(8, [7]float64{4, 1, 2, 1, 2, 0, 0})
+
+	f := Compute(history, monday, 100, DefaultSettings())
+
+	assert.InDelta(t, 10.0/7, f.Level, 1e-9)
+	assert.InDelta(t, 0, f.WeekdayFactors[time.Saturday], 1e-9)
+	assert.InDelta(t, 2.8, f.WeekdayFactors[time.Monday], 1e-9)
+	assert.InDelta(t, 4, f.Daily(monday), 1e-9)
+	assert.InDelta(t, 0, f.StdDev, 1e-9, "a pure weekly pattern is forecast exactly")
+
+	// One week of lead time needs one week of demand and no safety stock
+	assert.InDelta(t, 10, f.LeadTimeDemand, 1e-9)
+	assert.Equal(t, 10, f.ReorderPoint)
+	assert.Equal(t, 45, f.ReorderQuantity) // 30 days from a Monday: 4 weeks and Mon+Tue
+
+	// 100 units last 10 weeks; the next Monday's 4 is the first short day
+	require.NotNil(t, f.StockOutDate)
+	assert.Equal(t, monday.AddDate(0, 0, 8*7+10*7), *f.StockOutDate)
+}
+
+func TestCompute_SafetyStockFollowsServiceLevel(t *testing.T) {
+	// Alternating days give a forecast error without a weekly pattern
+	history := make([]float64, 28)
+	for i := range history {
+		history[i] = float64(2 + 2*(i%2))
+	}
+
+	low := Compute(history, monday, 0, Settings{ServiceLevel: 0.9, LeadTimeDays: 4})
+	high := Compute(history, monday, 0, Settings{ServiceLevel: 0.99, LeadTimeDays: 4})
+
+	assert.Greater(t, low.StdDev, 0.0)
+	assert.Greater(t, high.SafetyStock, low.SafetyStock)
+	assert.Greater(t, high.ReorderPoint, low.ReorderPoint)
+	assert.InDelta(t, 2.326*low.StdDev*2, high.SafetyStock, 0.01)
+
+	// Nothing in stock runs out on the first forecast day
+	require.NotNil(t, low.StockOutDate)
+	assert.Equal(t, monday.AddDate(0, 0, 28), *low.StockOutDate)
+}
+
+func TestCompute_NoDemand(t *testing.T) {
+	f := Compute(make([]float64, 30), monday, 5, DefaultSettings())
+	assert.Zero(t, f.Level)
+	assert.Zero(t, f.ReorderPoint)
+	assert.Zero(t, f.ReorderQuantity)
+	assert.Nil(t, f.StockOutDate)
+
+	f = Compute(nil, monday, 5, DefaultSettings())
+	assert.Zero(t, f.HistoryDays)
+	assert.Nil(t, f.StockOutDate)
+}
+
+func TestCompute_ShortHistoryHasNoSeasonality(t *testing.T) {
+	f := Compute([]float64{5, 0, 5, 0, 5}, monday, 10, DefaultSettings())
+	for wd, factor := range f.WeekdayFactors {
+		assert.Equal(t, 1.0, factor, time.Weekday(wd).String())
+	}
+}
+
+func TestZ(t *testing.T) {
+	assert.InDelta(t, 1.645, z(0.95), 0.001)
+	assert.InDelta(t, 2.326, z(0.99), 0.001)
+	assert.InDelta(t, 0, z(0.5), 1e-9)
+	assert.False(t, math.IsInf(z(DefaultServiceLevel), 0))
+}
+
+func TestDay(t *testing.T) {
+	// Sunday evening in UTC is Monday morning in the practice
+	assert.Equal(t, time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC), Day(time.Date(2026, 5, 31, 22, 30, 0, 0, time.UTC)))
+	assert.Equal(t, time.Date(2026, 5, 31, 0, 0, 0, 0, time.UTC), Day(time.Date(2026, 5, 31, 21, 59, 0, 0, time.UTC)))
+	assert.Equal(t, monday, Day(monday))
+}
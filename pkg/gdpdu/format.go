@@ -0,0 +1,120 @@
+package gdpdu
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// format renders a value as the column's type requires. nil and nil
+// pointers are empty fields.
+func (w *Writer) format(c Column, v interface{}) (string, error) {
+	switch x := v.(type) {
+	case nil:
+		return "", nil
+	case *string:
+		if x == nil {
+			return "", nil
+		}
+		v = *x
+	case *int:
+		if x == nil {
+			return "", nil
+		}
+		v = *x
+	case *int64:
+		if x == nil {
+			return "", nil
+		}
+		v = *x
+	case *float64:
+		if x == nil {
+			return "", nil
+		}
+		v = *x
+	case *time.Time:
+		if x == nil {
+			return "", nil
+		}
+		v = *x
+	case []byte:
+		v = string(x)
+	}
+
+	switch c.Type {
+	case Numeric:
+		return formatNumeric(v, c.Accuracy)
+	case Date:
+		return w.formatDate(v)
+	default:
+		return w.formatText(v), nil
+	}
+}
+
+// formatNumeric writes a number with accuracy decimal places and a decimal
+// comma, without digit grouping
+func formatNumeric(v interface{}, accuracy int) (string, error) {
+	var s string
+	switch x := v.(type) {
+	case int:
+		s = strconv.Itoa(x)
+	case int32:
+		s = strconv.FormatInt(int64(x), 10)
+	case int64:
+		s = strconv.FormatInt(x, 10)
+	case float64:
+		s = strconv.FormatFloat(x, 'f', accuracy, 64)
+	case bool:
+		s = "0"
+		if x {
+			s = "1"
+		}
+	case string:
+		r, ok := new(big.Rat).SetString(x)
+		if !ok {
+			return "", fmt.Errorf("not a number: %q", x)
+		}
+		s = r.FloatString(accuracy)
+	default:
+		return "", fmt.Errorf("unsupported numeric value %T", v)
+	}
+
+	if accuracy > 0 && !strings.Contains(s, ".") {
+		s += "." + strings.Repeat("0", accuracy)
+	}
+	return strings.Replace(s, ".", ",", 1), nil
+}
+
+// formatDate writes a date as DD.MM.YYYY in German local time
+func (w *Writer) formatDate(v interface{}) (string, error) {
+	switch x := v.(type) {
+	case time.Time:
+		return x.In(w.location).Format(dateLayout), nil
+	case string:
+		t, err := time.Parse("2006-01-02", x[:min(len(x), 10)])
+		if err != nil {
+			return "", fmt.Errorf("not a date: %q", x)
+		}
+		return t.Format(dateLayout), nil
+	default:
+		return "", fmt.Errorf("unsupported date value %T", v)
+	}
+}
+
+func (w *Writer) formatText(v interface{}) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case time.Time:
+		return x.In(w.location).Format("02.01.2006 15:04:05")
+	case bool:
+		if x {
+			return "ja"
+		}
+		return "nein"
+	default:
+		return fmt.Sprint(x)
+	}
+}
@@ -0,0 +1,267 @@
+// Package gdpdu writes data exports for German tax audits
+// (Datenträgerüberlassung, GoBD Rz. 165 ff.) in the GDPdU description
+// standard: one CSV file per table plus an index.xml (gdpdu-01-09-2004.dtd)
+// that describes the columns, their types and the relations between the
+// tables, so the auditor's software (IDEA) imports them without mapping.
+//
+// CSV files have no header row, use ';' as column delimiter, CRLF as record
+// delimiter and '"' as text encapsulator, and are UTF-8 encoded. Numbers use
+// a decimal comma and dates DD.MM.YYYY. The DTD that index.xml references
+// ships in the export, and a checksums.sha256 file (sha256sum format) covers
+// every file of the export.
+//
+// Usage:
+//
+//	w := gdpdu.NewWriter(zipWriter, gdpdu.DataSupplier{Name: "Praxis Dr. Müller"})
+//	err := w.WriteTable(&itemsTable, func(write gdpdu.WriteFunc) error {
+//	    return write(item.ID, item.Name, item.CreatedAt)
+//	})
+//	manifest, err := w.Close()
+package gdpdu
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ColumnType is the GDPdU data type of a column
+type ColumnType int
+
+const (
+	// AlphaNumeric is text
+	AlphaNumeric ColumnType = iota
+	// Numeric is a number with Column.Accuracy decimal places
+	Numeric
+	// Date is a calendar date, written as DD.MM.YYYY
+	Date
+)
+
+// File names and formats of an export
+const (
+	IndexFile     = "index.xml"
+	ChecksumsFile = "checksums.sha256"
+	DTD           = "gdpdu-01-09-2004.dtd"
+	DateFormat    = "DD.MM.YYYY"
+	dateLayout    = "02.01.2006"
+)
+
+// dtd is the GDPdU description standard that index.xml references
+//
+//go:embed gdpdu-01-09-2004.dtd
+var dtd []byte
+
+// Column is a column of a table. Primary key columns come first.
+type Column struct {
+	Name        string
+	Description string
+	Type        ColumnType
+	// Accuracy is the number of decimal places of a Numeric column
+	Accuracy   int
+	PrimaryKey bool
+}
+
+// ForeignKey relates columns of a table to the primary key of another table
+type ForeignKey struct {
+	Columns []string
+	// References is the Name of the referenced table
+	References string
+}
+
+// Table describes one CSV file of an export
+type Table struct {
+	// Name identifies the table in index.xml, e.g. "Artikel"
+	Name string
+	// File is the CSV file name, e.g. "artikel.csv"
+	File        string
+	Description string
+	Columns     []Column
+	ForeignKeys []ForeignKey
+	// From and To, if set, are the period the table's records cover
+	From, To *time.Time
+}
+
+// validate checks that primary keys come first, as VariableLength requires
+func (t *Table) validate() error {
+	if t.Name == "" || t.File == "" || len(t.Columns) == 0 {
+		return fmt.Errorf("gdpdu: table %q needs a name, a file and columns", t.Name)
+	}
+	if !t.Columns[0].PrimaryKey {
+		return fmt.Errorf("gdpdu: table %q needs a primary key", t.Name)
+	}
+	keys := true
+	for _, c := range t.Columns {
+		if c.PrimaryKey && !keys {
+			return fmt.Errorf("gdpdu: primary key column %q of table %q follows other columns", c.Name, t.Name)
+		}
+		keys = c.PrimaryKey
+	}
+	return nil
+}
+
+// DataSupplier is the company that supplies the data
+type DataSupplier struct {
+	Name     string
+	Location string
+	Comment  string
+}
+
+// File is a file of an export with its checksum
+type File struct {
+	Name    string `json:"name"`
+	Size    int64  `json:"size"`
+	SHA256  string `json:"sha256"`
+	Records int64  `json:"records,omitempty"`
+}
+
+// Manifest lists the files of an export
+type Manifest struct {
+	Files []File `json:"files"`
+}
+
+// Records returns the number of records of all tables
+func (m *Manifest) Records() int64 {
+	var n int64
+	for _, f := range m.Files {
+		n += f.Records
+	}
+	return n
+}
+
+// WriteFunc writes one record; values are in column order
+type WriteFunc func(values ...interface{}) error
+
+// Writer writes the tables of an export into a ZIP archive
+type Writer struct {
+	zip      *zip.Writer
+	supplier DataSupplier
+	location *time.Location
+	tables   []*Table
+	files    []File
+}
+
+// NewWriter creates a writer that adds its files to zw. Times are written
+// in German local time.
+func NewWriter(zw *zip.Writer, supplier DataSupplier) *Writer {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		loc = time.UTC
+	}
+	return &Writer{zip: zw, supplier: supplier, location: loc}
+}
+
+// countingWriter hashes and counts what passes through it
+type countingWriter struct {
+	w    io.Writer
+	hash hash.Hash
+	size int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.hash.Write(p[:n])
+	c.size += int64(n)
+	return n, err
+}
+
+// create adds a file to the archive
+func (w *Writer) create(name string) (*countingWriter, error) {
+	f, err := w.zip.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Now()})
+	if err != nil {
+		return nil, err
+	}
+	return &countingWriter{w: f, hash: sha256.New()}, nil
+}
+
+func (w *Writer) finish(name string, cw *countingWriter, records int64) {
+	w.files = append(w.files, File{Name: name, Size: cw.size, SHA256: hex.EncodeToString(cw.hash.Sum(nil)), Records: records})
+}
+
+// WriteTable writes the CSV file of t; rows calls write once per record
+func (w *Writer) WriteTable(t *Table, rows func(write WriteFunc) error) error {
+	if err := t.validate(); err != nil {
+		return err
+	}
+
+	cw, err := w.create(t.File)
+	if err != nil {
+		return err
+	}
+	out := csv.NewWriter(cw)
+	out.Comma = ';'
+	out.UseCRLF = true
+
+	var records int64
+	record := make([]string, len(t.Columns))
+	err = rows(func(values ...interface{}) error {
+		if len(values) != len(t.Columns) {
+			return fmt.Errorf("gdpdu: table %q has %d columns, got %d values", t.Name, len(t.Columns), len(values))
+		}
+		for i, v := range values {
+			s, err := w.format(t.Columns[i], v)
+			if err != nil {
+				return fmt.Errorf("gdpdu: %s.%s: %w", t.Name, t.Columns[i].Name, err)
+			}
+			record[i] = s
+		}
+		records++
+		return out.Write(record)
+	})
+	if err != nil {
+		return err
+	}
+	out.Flush()
+	if err := out.Error(); err != nil {
+		return err
+	}
+
+	w.tables = append(w.tables, t)
+	w.finish(t.File, cw, records)
+	return nil
+}
+
+// Close writes index.xml, its DTD and the checksums file and returns the
+// manifest. It does not close the ZIP writer.
+func (w *Writer) Close() (*Manifest, error) {
+	cw, err := w.create(IndexFile)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeIndex(cw, w.supplier, w.tables); err != nil {
+		return nil, err
+	}
+	w.finish(IndexFile, cw, 0)
+
+	cw, err = w.create(DTD)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := cw.Write(dtd); err != nil {
+		return nil, err
+	}
+	w.finish(DTD, cw, 0)
+
+	files := append([]File(nil), w.files...)
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+	var sums strings.Builder
+	for _, f := range files {
+		fmt.Fprintf(&sums, "%s  %s\n", f.SHA256, f.Name)
+	}
+	cw, err = w.create(ChecksumsFile)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(cw, sums.String()); err != nil {
+		return nil, err
+	}
+
+	return &Manifest{Files: w.files}, nil
+}
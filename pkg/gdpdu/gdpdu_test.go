@@ -0,0 +1,176 @@
+package gdpdu
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testItems = Table{
+	Name:        "Artikel",
+	File:        "artikel.csv",
+	Description: "Artikelstamm",
+	Columns: []Column{
+		{Name: "id", Type: AlphaNumeric, PrimaryKey: true},
+		{Name: "name", Type: AlphaNumeric},
+		{Name: "preis", Type: Numeric, Accuracy: 2},
+		{Name: "bestand", Type: Numeric},
+		{Name: "angelegt_am", Type: Date},
+	},
+}
+
+var testAdjustments = Table{
+	Name: "Lagerbewegungen",
+	File: "lagerbewegungen.csv",
+	Columns: []Column{
+		{Name: "id", Type: AlphaNumeric, PrimaryKey: true},
+		{Name: "artikel_id", Type: AlphaNumeric},
+		{Name: "menge", Type: Numeric},
+	},
+	ForeignKeys: []ForeignKey{{Columns: []string{"artikel_id"}, References: "Artikel"}},
+}
+
+func readZip(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	files := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+		files[f.Name] = string(content)
+	}
+	return files
+}
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w := NewWriter(zw, DataSupplier{Name: "Praxis Dr. Müller", Location: "München", Comment: "Zeitraum 2026"})
+
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+	items := testItems
+	items.From, items.To = &from, &to
+
+	created := time.Date(2026, 3, 31, 23, 30, 0, 0, time.UTC) // 1 April in Germany
+	var noPrice *string
+	require.NoError(t, w.WriteTable(&items, func(write WriteFunc) error {
+		if err := write("a1", `Handschuhe "Nitril"; Gr. M`, []byte("12.5"), int64(40), created); err != nil {
+			return err
+		}
+		return write("a2", "Tupfer", noPrice, 3, nil)
+	}))
+	require.NoError(t, w.WriteTable(&testAdjustments, func(write WriteFunc) error {
+		return write("b1", "a1", -5)
+	}))
+
+	manifest, err := w.Close()
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	assert.Equal(t, int64(3), manifest.Records())
+
+	files := readZip(t, buf.Bytes())
+	assert.Equal(t,
+		"a1;\"Handschuhe \"\"Nitril\"\"; Gr. M\";12,50;40;01.04.2026\r\n"+
+			"a2;Tupfer;;3;\r\n",
+		files["artikel.csv"])
+	assert.Equal(t, "b1;a1;-5\r\n", files["lagerbewegungen.csv"])
+
+	// Checksums cover the tables, the index and its DTD
+	sums := files[ChecksumsFile]
+	for _, name := range []string{"artikel.csv", "lagerbewegungen.csv", IndexFile, DTD} {
+		sum := sha256.Sum256([]byte(files[name]))
+		assert.Contains(t, sums, hex.EncodeToString(sum[:])+"  "+name+"\n")
+	}
+	assert.Len(t, manifest.Files, 4)
+	assert.Equal(t, string(dtd), files[DTD])
+
+	index := files[IndexFile]
+	assert.True(t, strings.HasPrefix(index, xml.Header+`<!DOCTYPE DataSet SYSTEM "gdpdu-01-09-2004.dtd">`))
+	var ds xmlDataSet
+	require.NoError(t, xml.Unmarshal([]byte(index), &ds))
+	assert.Equal(t, "Praxis Dr. Müller", ds.DataSupplier.Name)
+	require.Len(t, ds.Media.Tables, 2)
+
+	tbl := ds.Media.Tables[0]
+	assert.Equal(t, "artikel.csv", tbl.URL)
+	assert.Equal(t, ",", tbl.DecimalSymbol)
+	assert.Equal(t, "01.01.2026", tbl.Validity.Range.From)
+	assert.Equal(t, "31.12.2026", tbl.Validity.Range.To)
+	assert.Equal(t, "\r\n", tbl.VariableLength.RecordDelimiter)
+	require.Len(t, tbl.VariableLength.VariablePrimaryKey, 1)
+	assert.Equal(t, "id", tbl.VariableLength.VariablePrimaryKey[0].Name)
+	require.Len(t, tbl.VariableLength.VariableColumn, 4)
+	assert.Equal(t, "2", tbl.VariableLength.VariableColumn[1].Numeric.Accuracy)
+	assert.Equal(t, DateFormat, tbl.VariableLength.VariableColumn[3].Date.Format)
+	assert.NotNil(t, tbl.VariableLength.VariableColumn[0].AlphaNumeric)
+
+	fk := ds.Media.Tables[1].VariableLength.ForeignKey
+	require.Len(t, fk, 1)
+	assert.Equal(t, []string{"artikel_id"}, fk[0].Name)
+	assert.Equal(t, "Artikel", fk[0].References)
+
+	// Primary keys precede the other columns, as the DTD orders them
+	assert.Less(t, strings.Index(index, "<VariablePrimaryKey>"), strings.Index(index, "<VariableColumn>"))
+
+	// Every element of the index is declared in the shipped DTD
+	declared := map[string]bool{}
+	for _, m := range regexp.MustCompile(`<!ELEMENT (\w+)`).FindAllStringSubmatch(files[DTD], -1) {
+		declared[m[1]] = true
+	}
+	for _, m := range regexp.MustCompile(`<(\w+)[ />]`).FindAllStringSubmatch(index, -1) {
+		assert.True(t, declared[m[1]], "element %s is not declared in %s", m[1], DTD)
+	}
+}
+
+func TestWriter_Errors(t *testing.T) {
+	w := NewWriter(zip.NewWriter(io.Discard), DataSupplier{})
+
+	err := w.WriteTable(&testItems, func(write WriteFunc) error {
+		return write("a1", "too few")
+	})
+	assert.ErrorContains(t, err, "5 columns, got 2 values")
+
+	err = w.WriteTable(&testItems, func(write WriteFunc) error {
+		return write("a1", "Tupfer", "zwölf", 1, nil)
+	})
+	assert.ErrorContains(t, err, "Artikel.preis")
+
+	noKey := Table{Name: "X", File: "x.csv", Columns: []Column{{Name: "a"}, {Name: "id", PrimaryKey: true}}}
+	assert.Error(t, w.WriteTable(&noKey, func(WriteFunc) error { return nil }))
+}
+
+func TestFormatNumeric(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		accuracy int
+		want     string
+	}{
+		{int64(42), 0, "42"},
+		{-7, 2, "-7,00"},
+		{"1234.5", 2, "1234,50"},
+		{"0.125", 2, "0,13"},
+		{"3", 0, "3"},
+		{1.5, 3, "1,500"},
+		{true, 0, "1"},
+	}
+	for _, tt := range tests {
+		got, err := formatNumeric(tt.value, tt.accuracy)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, got, "%v", tt.value)
+	}
+}
@@ -0,0 +1,148 @@
+package gdpdu
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+)
+
+// Elements of index.xml in the order gdpdu-01-09-2004.dtd requires
+
+type xmlDataSet struct {
+	XMLName      xml.Name         `xml:"DataSet"`
+	Version      string           `xml:"Version"`
+	DataSupplier *xmlDataSupplier `xml:"DataSupplier"`
+	Media        xmlMedia         `xml:"Media"`
+}
+
+type xmlDataSupplier struct {
+	Name     string `xml:"Name"`
+	Location string `xml:"Location"`
+	Comment  string `xml:"Comment"`
+}
+
+type xmlMedia struct {
+	Name   string     `xml:"Name"`
+	Tables []xmlTable `xml:"Table"`
+}
+
+type xmlTable struct {
+	URL                 string            `xml:"URL"`
+	Name                string            `xml:"Name"`
+	Description         string            `xml:"Description,omitempty"`
+	Validity            *xmlValidity      `xml:"Validity"`
+	UTF8                struct{}          `xml:"UTF8"`
+	DecimalSymbol       string            `xml:"DecimalSymbol"`
+	DigitGroupingSymbol string            `xml:"DigitGroupingSymbol"`
+	VariableLength      xmlVariableLength `xml:"VariableLength"`
+}
+
+type xmlValidity struct {
+	Range  xmlRange `xml:"Range"`
+	Format string   `xml:"Format"`
+}
+
+type xmlRange struct {
+	From string `xml:"From"`
+	To   string `xml:"To,omitempty"`
+}
+
+type xmlVariableLength struct {
+	ColumnDelimiter    string          `xml:"ColumnDelimiter"`
+	RecordDelimiter    string          `xml:"RecordDelimiter"`
+	TextEncapsulator   string          `xml:"TextEncapsulator"`
+	VariablePrimaryKey []xmlColumn     `xml:"VariablePrimaryKey"`
+	VariableColumn     []xmlColumn     `xml:"VariableColumn"`
+	ForeignKey         []xmlForeignKey `xml:"ForeignKey"`
+}
+
+type xmlColumn struct {
+	Name         string      `xml:"Name"`
+	Description  string      `xml:"Description,omitempty"`
+	Numeric      *xmlNumeric `xml:"Numeric"`
+	AlphaNumeric *struct{}   `xml:"AlphaNumeric"`
+	Date         *xmlDate    `xml:"Date"`
+}
+
+type xmlNumeric struct {
+	Accuracy string `xml:"Accuracy,omitempty"`
+}
+
+type xmlDate struct {
+	Format string `xml:"Format"`
+}
+
+type xmlForeignKey struct {
+	Name       []string `xml:"Name"`
+	References string   `xml:"References"`
+}
+
+func xmlColumnOf(c Column) xmlColumn {
+	col := xmlColumn{Name: c.Name, Description: c.Description}
+	switch c.Type {
+	case Numeric:
+		col.Numeric = &xmlNumeric{}
+		if c.Accuracy > 0 {
+			col.Numeric.Accuracy = strconv.Itoa(c.Accuracy)
+		}
+	case Date:
+		col.Date = &xmlDate{Format: DateFormat}
+	default:
+		col.AlphaNumeric = &struct{}{}
+	}
+	return col
+}
+
+// writeIndex writes index.xml describing tables
+func writeIndex(out io.Writer, supplier DataSupplier, tables []*Table) error {
+	ds := xmlDataSet{
+		Version: "1.0",
+		Media:   xmlMedia{Name: "Datenträger 1"},
+	}
+	if supplier != (DataSupplier{}) {
+		ds.DataSupplier = &xmlDataSupplier{Name: supplier.Name, Location: supplier.Location, Comment: supplier.Comment}
+	}
+
+	for _, t := range tables {
+		xt := xmlTable{
+			URL:                 t.File,
+			Name:                t.Name,
+			Description:         t.Description,
+			DecimalSymbol:       ",",
+			DigitGroupingSymbol: ".",
+			VariableLength: xmlVariableLength{
+				ColumnDelimiter:  ";",
+				RecordDelimiter:  "\r\n",
+				TextEncapsulator: `"`,
+			},
+		}
+		if t.From != nil {
+			xt.Validity = &xmlValidity{Range: xmlRange{From: t.From.Format(dateLayout)}, Format: DateFormat}
+			if t.To != nil {
+				xt.Validity.Range.To = t.To.Format(dateLayout)
+			}
+		}
+		for _, c := range t.Columns {
+			if c.PrimaryKey {
+				xt.VariableLength.VariablePrimaryKey = append(xt.VariableLength.VariablePrimaryKey, xmlColumnOf(c))
+			} else {
+				xt.VariableLength.VariableColumn = append(xt.VariableLength.VariableColumn, xmlColumnOf(c))
+			}
+		}
+		for _, fk := range t.ForeignKeys {
+			xt.VariableLength.ForeignKey = append(xt.VariableLength.ForeignKey, xmlForeignKey{Name: fk.Columns, References: fk.References})
+		}
+		ds.Media.Tables = append(ds.Media.Tables, xt)
+	}
+
+	if _, err := io.WriteString(out, xml.Header+`<!DOCTYPE DataSet SYSTEM "`+DTD+`">`+"\n"); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(out)
+	enc.Indent("", "  ")
+	if err := enc.Encode(ds); err != nil {
+		return err
+	}
+	_, err := io.WriteString(out, "\n")
+	return err
+}
@@ -0,0 +1,36 @@
+package hmacsig
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignVerify(t *testing.T) {
+	now := time.Unix(1760000000, 0)
+	body := []byte(`{"readings":[]}`)
+	header := Sign("secret", now, body)
+
+	assert.Equal(t, "t=1760000000,v1="+signature("secret", "1760000000", body), header)
+	assert.True(t, Verify("secret", header, body, 5*time.Minute, now.Add(time.Minute)))
+	assert.True(t, Verify("secret", " v1="+signature("secret", "1760000000", body)+", t=1760000000", body, time.Minute, now), "order and spaces")
+	assert.False(t, Verify("other", header, body, 5*time.Minute, now), "wrong secret")
+	assert.False(t, Verify("secret", header, []byte(`{}`), 5*time.Minute, now), "tampered body")
+	assert.False(t, Verify("secret", header, body, 5*time.Minute, now.Add(10*time.Minute)), "replayed")
+	assert.False(t, Verify("secret", header, body, 5*time.Minute, now.Add(-10*time.Minute)), "from the future")
+	assert.False(t, Verify("secret", "v1=abc", body, 5*time.Minute, now), "no timestamp")
+	assert.False(t, Verify("secret", "t=1760000000", body, 5*time.Minute, now), "no signature")
+}
+
+func TestSealVerifySeal(t *testing.T) {
+	digest := "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+	seal := Seal("secret", digest)
+
+	assert.Len(t, seal, 64)
+	assert.True(t, VerifySeal("secret", digest, seal))
+	assert.False(t, VerifySeal("other", digest, seal), "wrong secret")
+	assert.False(t, VerifySeal("secret", "0"+digest[1:], seal), "tampered digest")
+	assert.False(t, VerifySeal("secret", digest, ""), "no seal")
+	assert.NotEqual(t, Sign("secret", time.Unix(0, 0), []byte(digest)), "t=0,v1="+seal, "seals are not request signatures")
+}
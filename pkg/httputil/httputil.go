@@ -29,6 +29,8 @@ type Meta struct {
 	PerPage    int   `json:"per_page,omitempty"`
 	Total      int64 `json:"total,omitempty"`
 	TotalPages int   `json:"total_pages,omitempty"`
+	// NextCursor is passed as ?cursor= to fetch the next page (empty on the last page)
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 // JSON sends a JSON response
@@ -60,55 +62,26 @@ func JSONWithMeta(w http.ResponseWriter, statusCode int, data interface{}, meta
 
 // Error sends an error response (uses default locale)
 func Error(w http.ResponseWriter, err error) {
-	var appErr *errors.AppError
-	if errors.As(err, &appErr) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(appErr.StatusCode)
-
-		response := Response{
-			Success: false,
-			Error: &ErrorBody{
-				Code:    appErr.Code,
-				Message: appErr.Message,
-				Details: appErr.Details,
-			},
-		}
-
-		json.NewEncoder(w).Encode(response)
-		return
-	}
-
-	// Default to internal server error
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusInternalServerError)
-
-	response := Response{
-		Success: false,
-		Error: &ErrorBody{
-			Code:    "INTERNAL_ERROR",
-			Message: "an unexpected error occurred",
-		},
-	}
-
-	json.NewEncoder(w).Encode(response)
+	writeError(w, err, i18n.NewLocalizer(i18n.DefaultLocale))
 }
 
 // ErrorLocalized sends a localized error response using request context
 func ErrorLocalized(w http.ResponseWriter, r *http.Request, err error) {
+	writeError(w, err, i18n.LocalizerFromContext(r.Context()))
+}
+
+func writeError(w http.ResponseWriter, err error, localizer *i18n.Localizer) {
 	var appErr *errors.AppError
 	if errors.As(err, &appErr) {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(appErr.StatusCode)
 
-		// Get localized message
-		message := appErr.Localize(r.Context())
-
 		response := Response{
 			Success: false,
 			Error: &ErrorBody{
 				Code:    appErr.Code,
-				Message: message,
-				Details: appErr.Details,
+				Message: appErr.LocalizeWith(localizer),
+				Details: appErr.LocalizeDetails(localizer),
 			},
 		}
 
@@ -116,8 +89,7 @@ func ErrorLocalized(w http.ResponseWriter, r *http.Request, err error) {
 		return
 	}
 
-	// Default to internal server error with localized message
-	localizer := i18n.LocalizerFromContext(r.Context())
+	// Default to internal server error
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusInternalServerError)
 
@@ -145,16 +117,14 @@ func Created(w http.ResponseWriter, data interface{}) {
 // DecodeJSON decodes the request body into the provided struct
 func DecodeJSON(r *http.Request, v interface{}) error {
 	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
-		return errors.BadRequest("invalid JSON body")
+		return errors.BadRequest("errors.invalid_json")
 	}
 	return nil
 }
 
 // DecodeJSONLocalized decodes the request body with localized error
+//
+// Deprecated: DecodeJSON errors are localized by ErrorLocalized.
 func DecodeJSONLocalized(r *http.Request, v interface{}) error {
-	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
-		localizer := i18n.LocalizerFromContext(r.Context())
-		return errors.BadRequest(localizer.T("errors.invalid_json"))
-	}
-	return nil
+	return DecodeJSON(r, v)
 }
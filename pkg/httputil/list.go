@@ -0,0 +1,117 @@
+package httputil
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
+)
+
+// filterParam matches filter[field] and filter[field][op]
+var filterParam = regexp.MustCompile(`^filter\[([a-z0-9_]+)\](?:\[([a-z]+)\])?$`)
+
+// ParseListQuery parses the generic list query parameters:
+//
+//	?filter[category]=Supplies                  equality
+//	?filter[expiry_date][lte]=2025-06-30        operators: eq ne lt lte gt gte in like null
+//	?filter[status][in]=pending,approved
+//	?sort=-expiry_date,name                     "-" = descending
+//	?limit=50&cursor=<next_cursor>              keyset paging
+//	?page=3&per_page=20                         OFFSET paging (the default without cursor)
+//	?count=true                                 include total (default only without cursor)
+//
+// Field names and operators are validated against the endpoint's
+// database.ListSchema when the query runs.
+func ParseListQuery(r *http.Request) (*database.ListQuery, error) {
+	values := r.URL.Query()
+	q := &database.ListQuery{
+		Cursor: values.Get("cursor"),
+	}
+
+	for key, vals := range values {
+		m := filterParam.FindStringSubmatch(key)
+		if m == nil {
+			if strings.HasPrefix(key, "filter[") {
+				return nil, errors.Validation(map[string]string{key: "validation.malformed_filter"})
+			}
+			continue
+		}
+		for _, v := range vals {
+			q.Filters = append(q.Filters, database.Filter{Field: m[1], Op: m[2], Value: v})
+		}
+	}
+
+	if sort := values.Get("sort"); sort != "" {
+		for _, key := range strings.Split(sort, ",") {
+			key = strings.TrimSpace(key)
+			if key == "" || key == "-" {
+				continue
+			}
+			q.Sort = append(q.Sort, database.SortField{
+				Field: strings.TrimPrefix(key, "-"),
+				Desc:  strings.HasPrefix(key, "-"),
+			})
+		}
+	}
+
+	limit := values.Get("limit")
+	if limit == "" {
+		limit = values.Get("per_page")
+	}
+	if limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			return nil, errors.Validation(nil).WithDetail("limit", "validation.positive", map[string]string{"field": "limit"})
+		}
+		q.Limit = n
+	}
+
+	// The total costs a full COUNT(*) of the filtered rows. Page-based requests
+	// keep it for total_pages; cursor walks fetch it only on request.
+	q.SkipCount = q.Cursor != ""
+	if count := values.Get("count"); count != "" {
+		withCount, err := strconv.ParseBool(count)
+		if err != nil {
+			return nil, errors.Validation(nil).WithDetail("count", "validation.boolean")
+		}
+		q.SkipCount = !withCount
+	}
+
+	// Requests without a cursor are page-based (page 1 by default) so existing
+	// clients keep page/total_pages; next_cursor is returned either way
+	if q.Cursor == "" {
+		q.Page = 1
+		if page := values.Get("page"); page != "" {
+			n, err := strconv.Atoi(page)
+			if err != nil || n < 1 {
+				return nil, errors.Validation(nil).WithDetail("page", "validation.positive", map[string]string{"field": "page"})
+			}
+			q.Page = n
+		}
+	}
+
+	return q, nil
+}
+
+// PageMeta builds response metadata for a list page.
+// Page is only set for page-based requests, Total and TotalPages only when
+// the query counted.
+func PageMeta[T any](q *database.ListQuery, page *database.Page[T]) *Meta {
+	meta := &Meta{
+		PerPage:    page.Limit,
+		NextCursor: page.NextCursor,
+	}
+	if !q.SkipCount {
+		meta.Total = page.Total
+	}
+	if q.Page > 0 {
+		meta.Page = q.Page
+	}
+	if q.Page > 0 && !q.SkipCount {
+		meta.TotalPages = int((page.Total + int64(page.Limit) - 1) / int64(page.Limit))
+	}
+	return meta
+}
@@ -0,0 +1,84 @@
+package httputil
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseListQuery(t *testing.T) {
+	r := httptest.NewRequest("GET", "/items?filter[category]=Supplies&filter[expiry_date][lte]=2025-06-30&sort=-expiry_date,name&limit=50", nil)
+
+	q, err := ParseListQuery(r)
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []database.Filter{
+		{Field: "category", Value: "Supplies"},
+		{Field: "expiry_date", Op: "lte", Value: "2025-06-30"},
+	}, q.Filters)
+	assert.Equal(t, []database.SortField{
+		{Field: "expiry_date", Desc: true},
+		{Field: "name"},
+	}, q.Sort)
+	assert.Equal(t, 50, q.Limit)
+	assert.Equal(t, 1, q.Page, "requests without cursor default to page 1")
+	assert.False(t, q.SkipCount, "page-based requests count by default")
+}
+
+func TestParseListQuery_Cursor(t *testing.T) {
+	r := httptest.NewRequest("GET", "/items?cursor=abc&page=3&per_page=10", nil)
+
+	q, err := ParseListQuery(r)
+	require.NoError(t, err)
+
+	assert.Equal(t, "abc", q.Cursor)
+	assert.Equal(t, 0, q.Page, "cursor takes precedence over page")
+	assert.Equal(t, 10, q.Limit)
+	assert.True(t, q.SkipCount, "cursor requests skip the total by default")
+}
+
+func TestParseListQuery_Count(t *testing.T) {
+	for query, skip := range map[string]bool{
+		"cursor=abc&count=true": false,
+		"cursor=abc&count=1":    false,
+		"page=2&count=false":    true,
+		"count=true":            false,
+	} {
+		r := httptest.NewRequest("GET", "/items?"+query, nil)
+		q, err := ParseListQuery(r)
+		require.NoError(t, err, query)
+		assert.Equal(t, skip, q.SkipCount, query)
+	}
+}
+
+func TestPageMeta_SkipCount(t *testing.T) {
+	page := &database.Page[string]{Items: []string{"a"}, Total: 0, Limit: 20, NextCursor: "next"}
+
+	meta := PageMeta(&database.ListQuery{Page: 2, SkipCount: true}, page)
+	assert.Equal(t, 2, meta.Page)
+	assert.Zero(t, meta.Total)
+	assert.Zero(t, meta.TotalPages)
+
+	page.Total = 45
+	meta = PageMeta(&database.ListQuery{Page: 2}, page)
+	assert.Equal(t, int64(45), meta.Total)
+	assert.Equal(t, 3, meta.TotalPages)
+}
+
+func TestParseListQuery_Invalid(t *testing.T) {
+	for _, query := range []string{
+		"filter[Category]=x",
+		"filter[category]x=1",
+		"limit=0",
+		"limit=abc",
+		"page=-1",
+		"count=maybe",
+	} {
+		r := httptest.NewRequest("GET", "/items?"+query, nil)
+		_, err := ParseListQuery(r)
+		assert.Error(t, err, query)
+	}
+}
@@ -2,10 +2,13 @@ package httputil
 
 import (
 	"context"
+	"net"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/medflow/medflow-backend/pkg/errors"
 	"github.com/medflow/medflow-backend/pkg/logger"
 	"github.com/medflow/medflow-backend/pkg/tenant"
 )
@@ -17,6 +20,7 @@ const (
 	UserIDKey    contextKey = "user_id"
 	UserEmailKey contextKey = "user_email"
 	UserRoleKey  contextKey = "user_role"
+	ClientIPKey  contextKey = "client_ip"
 )
 
 // RequestID middleware adds a request ID to each request
@@ -74,7 +78,7 @@ func Recoverer(log *logger.Logger) func(http.Handler) http.Handler {
 						Str("path", r.URL.Path).
 						Msg("panic recovered")
 
-					http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+					ErrorLocalized(w, r, errors.Internal("errors.internal"))
 				}
 			}()
 
@@ -93,6 +97,12 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+// Unwrap returns the wrapped writer, so http.ResponseController can flush
+// and set deadlines (e.g. for event streams)
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // GetRequestID retrieves the request ID from context
 func GetRequestID(ctx context.Context) string {
 	if id, ok := ctx.Value(RequestIDKey).(string); ok {
@@ -133,6 +143,33 @@ func WithUserContext(ctx context.Context, userID, email, role string) context.Co
 	return ctx
 }
 
+// GetClientIP retrieves the client IP address from context
+func GetClientIP(ctx context.Context) string {
+	if ip, ok := ctx.Value(ClientIPKey).(string); ok {
+		return ip
+	}
+	return ""
+}
+
+// UserContext adds the user the gateway authenticated (X-User-ID,
+// X-User-Email, X-User-Role) and the client IP to the request context, so
+// services can attribute changes without passing them through every call.
+// Run it after middleware.RealIP, which resolves the client IP forwarded by
+// the gateway.
+func UserContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := WithUserContext(r.Context(), r.Header.Get("X-User-ID"), r.Header.Get("X-User-Email"), r.Header.Get("X-User-Role"))
+
+		ip := r.RemoteAddr
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+		ctx = context.WithValue(ctx, ClientIPKey, ip)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // TenantMiddleware extracts tenant context from headers (set by API Gateway)
 // and adds it to the request context.
 //
@@ -144,27 +181,43 @@ func WithUserContext(ctx context.Context, userID, email, role string) context.Co
 //   - X-Tenant-Slug: Tenant slug (optional, for logging/display)
 //
 // Security: Missing X-Tenant-ID returns 403 Forbidden (fail-fast).
-// Exception: /health endpoints are allowed without tenant context for monitoring.
+// Exception: /health endpoints are allowed without tenant context for monitoring,
+// and /files/ signed download URLs, which carry their own signature and name
+// the tenant in the object key.
 func TenantMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Skip tenant validation for health check endpoints
-		if r.URL.Path == "/health" {
-			next.ServeHTTP(w, r)
-			return
-		}
+	return TenantMiddlewareWithBypass()(next)
+}
 
-		tenantID := r.Header.Get("X-Tenant-ID")
-		tenantSlug := r.Header.Get("X-Tenant-Slug")
+// TenantMiddlewareWithBypass is TenantMiddleware that also lets requests to
+// the given paths through without tenant context. A service passes the
+// routes that resolve the tenant themselves, e.g. from a signed request.
+func TenantMiddlewareWithBypass(paths ...string) func(http.Handler) http.Handler {
+	bypass := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		bypass[p] = true
+	}
 
-		// Only X-Tenant-ID is required for RLS-based isolation
-		if tenantID == "" {
-			http.Error(w, `{"error":"missing tenant context"}`, http.StatusForbidden)
-			return
-		}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Skip tenant validation for health check endpoints and signed downloads
+			if r.URL.Path == "/health" || strings.HasPrefix(r.URL.Path, "/files/") || bypass[r.URL.Path] {
+				next.ServeHTTP(w, r)
+				return
+			}
 
-		// Add tenant context to request
-		ctx := tenant.WithTenantContext(r.Context(), tenantID, tenantSlug)
+			tenantID := r.Header.Get("X-Tenant-ID")
+			tenantSlug := r.Header.Get("X-Tenant-Slug")
 
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
+			// Only X-Tenant-ID is required for RLS-based isolation
+			if tenantID == "" {
+				ErrorLocalized(w, r, errors.Forbidden("errors.tenant_required"))
+				return
+			}
+
+			// Add tenant context to request
+			ctx := tenant.WithTenantContext(r.Context(), tenantID, tenantSlug)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
 }
@@ -0,0 +1,26 @@
+package httputil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantMiddlewareWithBypass(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) })
+	serve := func(mw func(http.Handler) http.Handler, path string) int {
+		rec := httptest.NewRecorder()
+		mw(ok).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, nil))
+		return rec.Code
+	}
+
+	bypass := TenantMiddlewareWithBypass("/api/v1/inventory/sensors/readings")
+	assert.Equal(t, http.StatusNoContent, serve(bypass, "/api/v1/inventory/sensors/readings"))
+	assert.Equal(t, http.StatusNoContent, serve(bypass, "/health"))
+	assert.Equal(t, http.StatusForbidden, serve(bypass, "/api/v1/inventory/items"))
+
+	// Other services do not exempt the path
+	assert.Equal(t, http.StatusForbidden, serve(TenantMiddleware, "/api/v1/inventory/sensors/readings"))
+}
@@ -11,33 +11,36 @@ var validate = validator.New()
 func Validate(v interface{}) error {
 	if err := validate.Struct(v); err != nil {
 		validationErrors := err.(validator.ValidationErrors)
-		details := make(map[string]string)
+		appErr := errors.Validation(nil)
 
 		for _, e := range validationErrors {
-			details[e.Field()] = formatValidationError(e)
+			key, params := validationMessage(e)
+			appErr.WithDetail(e.Field(), key, params)
 		}
 
-		return errors.Validation(details)
+		return appErr
 	}
 	return nil
 }
 
-func formatValidationError(e validator.FieldError) string {
+// validationMessage maps a validator tag to a message key and its parameters
+func validationMessage(e validator.FieldError) (string, map[string]string) {
+	field := e.Field()
 	switch e.Tag() {
 	case "required":
-		return "this field is required"
+		return "validation.required", map[string]string{"field": field}
 	case "email":
-		return "must be a valid email address"
+		return "validation.email", nil
 	case "min":
-		return "must be at least " + e.Param() + " characters"
+		return "validation.min_length", map[string]string{"field": field, "min": e.Param()}
 	case "max":
-		return "must be at most " + e.Param() + " characters"
+		return "validation.max_length", map[string]string{"field": field, "max": e.Param()}
 	case "uuid":
-		return "must be a valid UUID"
+		return "validation.uuid", nil
 	case "oneof":
-		return "must be one of: " + e.Param()
+		return "validation.one_of", map[string]string{"field": field, "values": e.Param()}
 	default:
-		return "invalid value"
+		return "validation.invalid", nil
 	}
 }
 
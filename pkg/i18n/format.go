@@ -0,0 +1,106 @@
+package i18n
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// localeFormat holds the date and number conventions of a locale
+type localeFormat struct {
+	date     string // time layout
+	dateTime string // time layout
+	decimal  string
+	group    string
+	// currency wraps a formatted amount, "%s" is replaced by the number
+	currency string
+}
+
+var formats = map[string]localeFormat{
+	LocaleEnglish: {date: "2006-01-02", dateTime: "2006-01-02 15:04", decimal: ".", group: ",", currency: "€%s"},
+	LocaleGerman:  {date: "02.01.2006", dateTime: "02.01.2006 15:04", decimal: ",", group: ".", currency: "%s €"},
+	LocaleTurkish: {date: "02.01.2006", dateTime: "02.01.2006 15:04", decimal: ",", group: ".", currency: "€%s"},
+}
+
+func (l *Localizer) format() localeFormat {
+	if f, ok := formats[l.locale]; ok {
+		return f
+	}
+	return formats[DefaultLocale]
+}
+
+// FormatDate formats a date, e.g. 31.12.2025 in German
+func (l *Localizer) FormatDate(t time.Time) string {
+	return t.Format(l.format().date)
+}
+
+// FormatDateTime formats a date with time of day, e.g. 31.12.2025 14:30 in German
+func (l *Localizer) FormatDateTime(t time.Time) string {
+	return t.Format(l.format().dateTime)
+}
+
+// FormatInt formats an integer with digit grouping, e.g. 1.234 in German
+func (l *Localizer) FormatInt(n int64) string {
+	sign := ""
+	if n < 0 {
+		sign = "-"
+	}
+	return sign + groupDigits(strconv.FormatUint(absInt(n), 10), l.format().group)
+}
+
+// FormatNumber formats a decimal number with the given number of fraction
+// digits, e.g. 1.234,50 in German
+func (l *Localizer) FormatNumber(v float64, decimals int) string {
+	f := l.format()
+	s := strconv.FormatFloat(v, 'f', decimals, 64)
+
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	intPart, frac, hasFrac := strings.Cut(s, ".")
+	s = groupDigits(intPart, f.group)
+	if hasFrac {
+		s += f.decimal + frac
+	}
+	return sign + s
+}
+
+// FormatCurrency formats an amount in euro cents, e.g. 1.234,56 € in German.
+// Cents are formatted exactly, without a float round trip.
+func (l *Localizer) FormatCurrency(cents int64) string {
+	f := l.format()
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+	}
+	abs := absInt(cents)
+	amount := groupDigits(strconv.FormatUint(abs/100, 10), f.group) + f.decimal + strconv.FormatUint(abs%100+100, 10)[1:]
+	return sign + strings.Replace(f.currency, "%s", amount, 1)
+}
+
+// groupDigits inserts sep between groups of three digits
+func groupDigits(digits, sep string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+	var b strings.Builder
+	head := len(digits) % 3
+	if head > 0 {
+		b.WriteString(digits[:head])
+	}
+	for i := head; i < len(digits); i += 3 {
+		if b.Len() > 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
+
+func absInt(n int64) uint64 {
+	if n < 0 {
+		return uint64(-(n + 1)) + 1
+	}
+	return uint64(n)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"embed"
 	"encoding/json"
+	"strconv"
 	"strings"
 	"sync"
 )
@@ -15,9 +16,23 @@ var messagesFS embed.FS
 const (
 	LocaleEnglish = "en"
 	LocaleGerman  = "de"
+	LocaleTurkish = "tr"
 	DefaultLocale = LocaleEnglish
 )
 
+// SupportedLocales lists every locale with a message file, default first
+var SupportedLocales = []string{LocaleEnglish, LocaleGerman, LocaleTurkish}
+
+// IsSupported reports whether locale has a message file
+func IsSupported(locale string) bool {
+	for _, l := range SupportedLocales {
+		if l == locale {
+			return true
+		}
+	}
+	return false
+}
+
 // Context key for locale
 type localeKey struct{}
 
@@ -31,8 +46,7 @@ func loadMessages() {
 	messagesOnce.Do(func() {
 		messages = make(map[string]map[string]interface{})
 
-		locales := []string{LocaleEnglish, LocaleGerman}
-		for _, locale := range locales {
+		for _, locale := range SupportedLocales {
 			data, err := messagesFS.ReadFile("messages/" + locale + ".json")
 			if err != nil {
 				continue
@@ -58,7 +72,7 @@ func NewLocalizer(locale string) *Localizer {
 	loadMessages()
 
 	// Validate locale, default to English
-	if locale != LocaleEnglish && locale != LocaleGerman {
+	if !IsSupported(locale) {
 		locale = DefaultLocale
 	}
 
@@ -94,6 +108,12 @@ func (l *Localizer) T(key string, params ...map[string]string) string {
 	return msg
 }
 
+// Has reports whether key exists in the localizer's own locale (without fallback)
+func (l *Localizer) Has(key string) bool {
+	loadMessages()
+	return l.getMessage(key, l.locale) != ""
+}
+
 // getMessage retrieves a nested message by dot-notation key
 func (l *Localizer) getMessage(key string, locale string) string {
 	localeMessages, ok := messages[locale]
@@ -142,22 +162,30 @@ func GetLocaleFromContext(ctx context.Context) string {
 	return DefaultLocale
 }
 
-// ParseAcceptLanguage parses the Accept-Language header and returns the best matching locale
+// ParseAcceptLanguage parses the Accept-Language header and returns the best
+// matching supported locale, honouring q-values ("tr-TR,tr;q=0.9,de;q=0.8").
+// Region subtags are ignored: de-AT and de-CH map to de.
 func ParseAcceptLanguage(header string) string {
-	if header == "" {
-		return DefaultLocale
-	}
-
-	// Simple parsing - check for supported locales
-	header = strings.ToLower(header)
+	best, bestQ := DefaultLocale, 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, q := strings.TrimSpace(part), 1.0
+		if i := strings.Index(tag, ";"); i >= 0 {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(tag[i+1:]), "q="); ok {
+				parsed, err := strconv.ParseFloat(v, 64)
+				if err != nil {
+					continue
+				}
+				q = parsed
+			}
+			tag = strings.TrimSpace(tag[:i])
+		}
 
-	// Check for German first (de, de-DE, de-AT, de-CH)
-	if strings.Contains(header, "de") {
-		return LocaleGerman
+		lang, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		if q > bestQ && IsSupported(lang) {
+			best, bestQ = lang, q
+		}
 	}
-
-	// Default to English
-	return LocaleEnglish
+	return best
 }
 
 // Global convenience functions
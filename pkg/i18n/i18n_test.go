@@ -0,0 +1,131 @@
+package i18n
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, Validate(), "all locale files must define the same keys and placeholders")
+}
+
+var (
+	// keyLiteral matches string literals that look like message keys
+	keyLiteral = regexp.MustCompile(`"((?:errors|validation|resources|success|units|notifications)\.[a-z0-9_.]+)"`)
+	// notFoundCall matches errors.NotFound("resource")
+	notFoundCall = regexp.MustCompile(`errors\.NotFound(?:WithKey)?\(\s*"([^"]+)"`)
+	// keyedCall matches keyed error constructors whose first argument is not a string literal
+	keyedCall = regexp.MustCompile(`\w*errors\.(?:BadRequest|Unauthorized|Forbidden|Conflict|Internal|NotFound)\(\s*([^"\s)])`)
+)
+
+// TestKeysUsedInCodeExist scans the Go sources for message keys and fails on
+// keys missing from the catalog and on error constructors called with
+// computed messages, which the scan cannot check.
+func TestKeysUsedInCodeExist(t *testing.T) {
+	root := filepath.Join("..", "..")
+	checked := 0
+
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			switch d.Name() {
+			case ".git", "vendor", "testdata", "node_modules":
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		src := string(data)
+		rel, _ := filepath.Rel(root, path)
+
+		for _, m := range keyLiteral.FindAllStringSubmatch(src, -1) {
+			checked++
+			assert.True(t, Exists(m[1]), "%s: message key %q is not defined", rel, m[1])
+		}
+		for _, m := range notFoundCall.FindAllStringSubmatch(src, -1) {
+			checked++
+			assert.True(t, Exists("resources."+m[1]), "%s: resource %q has no resources.%s key", rel, m[1], m[1])
+		}
+		if !strings.HasPrefix(filepath.ToSlash(rel), "pkg/errors/") {
+			for _, m := range keyedCall.FindAllStringIndex(src, -1) {
+				line := strings.Count(src[:m[0]], "\n") + 1
+				t.Errorf("%s:%d: error constructors take a message key literal", rel, line)
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Greater(t, checked, 100, "scan found suspiciously few keys")
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", LocaleEnglish},
+		{"de-DE", LocaleGerman},
+		{"de-AT,de;q=0.9,en;q=0.8", LocaleGerman},
+		{"tr-TR,tr;q=0.9", LocaleTurkish},
+		{"en-US,en;q=0.9,tr;q=0.8", LocaleEnglish},
+		{"fr-FR,tr;q=0.5,de;q=0.7", LocaleGerman},
+		{"fr-FR,es", LocaleEnglish},
+		{"*", LocaleEnglish},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, ParseAcceptLanguage(tt.header), tt.header)
+	}
+}
+
+func TestT_FallsBackToKey(t *testing.T) {
+	assert.Equal(t, "Mitarbeiter nicht gefunden", TWithLocale(LocaleGerman, "errors.not_found", map[string]string{"resource": "Mitarbeiter"}))
+	assert.Equal(t, "errors.does_not_exist", TWithLocale(LocaleTurkish, "errors.does_not_exist"))
+}
+
+func TestTPlural(t *testing.T) {
+	de := NewLocalizer(LocaleGerman)
+	assert.Equal(t, "1 Tag", de.TPlural("units.days", 1))
+	assert.Equal(t, "3 Tage", de.TPlural("units.days", 3))
+	assert.Equal(t, "1.500 Artikel", de.TPlural("units.items", 1500))
+
+	en := NewLocalizer(LocaleEnglish)
+	assert.Equal(t, "0 hours", en.TPlural("units.hours", 0))
+	assert.Equal(t, "1 hour", en.TPlural("units.hours", 1))
+}
+
+func TestFormat(t *testing.T) {
+	date := time.Date(2025, 3, 7, 14, 5, 0, 0, time.UTC)
+
+	de := NewLocalizer(LocaleGerman)
+	assert.Equal(t, "07.03.2025", de.FormatDate(date))
+	assert.Equal(t, "07.03.2025 14:05", de.FormatDateTime(date))
+	assert.Equal(t, "1.234.567", de.FormatInt(1234567))
+	assert.Equal(t, "-1.234,50", de.FormatNumber(-1234.5, 2))
+	assert.Equal(t, "1.234,56 €", de.FormatCurrency(123456))
+	assert.Equal(t, "-0,05 €", de.FormatCurrency(-5))
+
+	en := NewLocalizer(LocaleEnglish)
+	assert.Equal(t, "2025-03-07", en.FormatDate(date))
+	assert.Equal(t, "999", en.FormatInt(999))
+	assert.Equal(t, "1,000.0", en.FormatNumber(1000, 1))
+	assert.Equal(t, "€1,234.56", en.FormatCurrency(123456))
+
+	tr := NewLocalizer(LocaleTurkish)
+	assert.Equal(t, "€1.234,56", tr.FormatCurrency(123456))
+}
@@ -0,0 +1,67 @@
+package i18n
+
+// Plural forms (CLDR category names). A pluralized message is an object with
+// one entry per form the locale uses, e.g.
+//
+//	"items_expiring": {"one": "{count} item expires", "other": "{count} items expire"}
+const (
+	PluralZero  = "zero"
+	PluralOne   = "one"
+	PluralTwo   = "two"
+	PluralFew   = "few"
+	PluralMany  = "many"
+	PluralOther = "other"
+)
+
+// pluralRules maps a count to its CLDR plural form per locale.
+// en, de and tr only distinguish one/other for integers.
+var pluralRules = map[string]func(n int64) string{
+	LocaleEnglish: oneOther,
+	LocaleGerman:  oneOther,
+	LocaleTurkish: oneOther,
+}
+
+func oneOther(n int64) string {
+	if n == 1 || n == -1 {
+		return PluralOne
+	}
+	return PluralOther
+}
+
+// PluralForm returns the plural form of n in the localizer's locale
+func (l *Localizer) PluralForm(n int64) string {
+	if rule, ok := pluralRules[l.locale]; ok {
+		return rule(n)
+	}
+	return oneOther(n)
+}
+
+// TPlural translates a pluralized message. {count} is set to the
+// locale-formatted count; a missing form falls back to "other".
+func (l *Localizer) TPlural(key string, count int64, params ...map[string]string) string {
+	p := map[string]string{"count": l.FormatInt(count)}
+	if len(params) > 0 {
+		for k, v := range params[0] {
+			p[k] = v
+		}
+	}
+
+	if form := key + "." + l.PluralForm(count); l.Has(form) {
+		return l.T(form, p)
+	}
+	return l.T(key+"."+PluralOther, p)
+}
+
+// TPlural translates a pluralized message using the default locale
+func TPlural(key string, count int64, params ...map[string]string) string {
+	return NewLocalizer(DefaultLocale).TPlural(key, count, params...)
+}
+
+// isPluralForm reports whether s is a CLDR plural form name
+func isPluralForm(s string) bool {
+	switch s {
+	case PluralZero, PluralOne, PluralTwo, PluralFew, PluralMany, PluralOther:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// placeholder matches {param} in a message
+var placeholder = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+// Keys returns the sorted message keys of a locale. Pluralized messages are
+// returned once, under their base key.
+func Keys(locale string) []string {
+	loadMessages()
+	flat := make(map[string]string)
+	flatten("", messages[locale], flat)
+
+	keys := make([]string, 0, len(flat))
+	for k := range flat {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Exists reports whether key is defined in the default locale. Pluralized
+// messages exist under their base key.
+func Exists(key string) bool {
+	l := NewLocalizer(DefaultLocale)
+	return l.Has(key) || l.Has(key+"."+PluralOther)
+}
+
+// Validate checks that every supported locale defines exactly the keys of the
+// default locale and uses the same {placeholders} in each message.
+// Services call it at startup; a mismatch is a build defect, not a runtime condition.
+func Validate() error {
+	loadMessages()
+
+	reference := make(map[string]string)
+	flatten("", messages[DefaultLocale], reference)
+
+	var errs []error
+	for _, locale := range SupportedLocales {
+		if locale == DefaultLocale {
+			continue
+		}
+		if _, ok := messages[locale]; !ok {
+			errs = append(errs, fmt.Errorf("i18n: messages/%s.json missing or invalid", locale))
+			continue
+		}
+
+		flat := make(map[string]string)
+		flatten("", messages[locale], flat)
+
+		for key, msg := range reference {
+			other, ok := flat[key]
+			if !ok {
+				errs = append(errs, fmt.Errorf("i18n: %s: missing key %q", locale, key))
+				continue
+			}
+			if want, got := placeholders(msg), placeholders(other); want != got {
+				errs = append(errs, fmt.Errorf("i18n: %s: %q uses placeholders [%s], %s uses [%s]", locale, key, got, DefaultLocale, want))
+			}
+		}
+		for key := range flat {
+			if _, ok := reference[key]; !ok {
+				errs = append(errs, fmt.Errorf("i18n: %s: unknown key %q (not in %s)", locale, key, DefaultLocale))
+			}
+		}
+	}
+
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+	return errors.Join(errs...)
+}
+
+// flatten collects leaf messages by dot key. A plural object collapses to its
+// base key with the concatenated forms, so locales with different plural
+// forms still compare equal by key.
+func flatten(prefix string, m map[string]interface{}, out map[string]string) {
+	for k, v := range m {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		switch val := v.(type) {
+		case string:
+			out[key] = val
+		case map[string]interface{}:
+			if isPluralObject(val) {
+				var forms []string
+				for _, form := range val {
+					forms = append(forms, fmt.Sprint(form))
+				}
+				out[key] = strings.Join(forms, " ")
+				continue
+			}
+			flatten(key, val, out)
+		}
+	}
+}
+
+func isPluralObject(m map[string]interface{}) bool {
+	if _, ok := m[PluralOther]; !ok {
+		return false
+	}
+	for k, v := range m {
+		if _, ok := v.(string); !ok || !isPluralForm(k) {
+			return false
+		}
+	}
+	return true
+}
+
+// placeholders returns the sorted, de-duplicated placeholder names of a message
+func placeholders(msg string) string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, m := range placeholder.FindAllStringSubmatch(msg, -1) {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			names = append(names, m[1])
+		}
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
@@ -0,0 +1,141 @@
+package jobs
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/httputil"
+	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/medflow/medflow-backend/pkg/permissions"
+	"github.com/medflow/medflow-backend/pkg/tenant"
+)
+
+// AdminPermission is required to list and trigger jobs
+const AdminPermission = "admin.settings"
+
+// Handler exposes the admin endpoints of a Scheduler
+type Handler struct {
+	scheduler *Scheduler
+	logger    *logger.Logger
+}
+
+// NewHandler creates a new job admin handler
+func NewHandler(scheduler *Scheduler, log *logger.Logger) *Handler {
+	return &Handler{
+		scheduler: scheduler,
+		logger:    log,
+	}
+}
+
+// Routes returns the admin job routes, to be mounted e.g. at /admin/jobs
+func (h *Handler) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Use(requireAdmin)
+	r.Get("/", h.List)
+	r.Get("/{name}/runs", h.ListRuns)
+	r.Post("/{name}/trigger", h.Trigger)
+	return r
+}
+
+// List lists the per-tenant jobs with their next scheduled run. Global jobs
+// act on every tenant and are not shown to tenant admins.
+// GET /admin/jobs
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	jobs := []*JobInfo{}
+	for _, info := range h.scheduler.Jobs() {
+		if info.PerTenant {
+			jobs = append(jobs, info)
+		}
+	}
+	httputil.JSON(w, http.StatusOK, jobs)
+}
+
+// ListRuns lists recent runs of a job for the caller's tenant.
+// Runs of global jobs are not listed.
+// GET /admin/jobs/{name}/runs?limit=50
+func (h *Handler) ListRuns(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	tenantID, err := tenant.TenantID(r.Context())
+	if err != nil {
+		httputil.ErrorLocalized(w, r, errors.Forbidden("errors.tenant_required"))
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit < 1 || limit > 500 {
+		limit = 50
+	}
+
+	runs, err := h.scheduler.Runs(r.Context(), name, tenantID, limit)
+	if err != nil {
+		h.logger.Error().Err(err).Str("job", name).Msg("failed to list job runs")
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	httputil.JSON(w, http.StatusOK, runs)
+}
+
+// Trigger runs a per-tenant job immediately for the caller's tenant.
+// Global jobs act on every tenant and cannot be triggered by tenant admins.
+// POST /admin/jobs/{name}/trigger
+func (h *Handler) Trigger(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	tenantID, err := tenant.TenantID(r.Context())
+	if err != nil {
+		httputil.ErrorLocalized(w, r, errors.Forbidden("errors.tenant_required"))
+		return
+	}
+
+	job, ok := h.scheduler.job(name)
+	if !ok {
+		httputil.ErrorLocalized(w, r, errors.NotFound("job"))
+		return
+	}
+	if !job.PerTenant {
+		httputil.ErrorLocalized(w, r, errors.Forbidden("errors.jobs.global_job", map[string]string{"job": name}))
+		return
+	}
+
+	if err := h.scheduler.Trigger(name, tenantID); err != nil {
+		httputil.ErrorLocalized(w, r, err)
+		return
+	}
+
+	h.logger.Info().
+		Str("job", name).
+		Str("tenant_id", tenantID).
+		Str("user_id", r.Header.Get("X-User-ID")).
+		Msg("job triggered manually")
+
+	httputil.JSON(w, http.StatusAccepted, map[string]string{
+		"job":    name,
+		"status": "triggered",
+	})
+}
+
+// requireAdmin allows admins and users holding AdminPermission
+func requireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-User-Role") == "admin" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var perms []string
+		if header := r.Header.Get("X-User-Permissions"); header != "" {
+			_ = json.Unmarshal([]byte(header), &perms)
+		}
+		if !permissions.HasPermission(perms, AdminPermission) {
+			httputil.ErrorLocalized(w, r, errors.Forbidden("errors.jobs.admin_required"))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
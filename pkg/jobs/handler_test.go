@@ -0,0 +1,37 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/medflow/medflow-backend/pkg/config"
+	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_ListHidesGlobalJobs(t *testing.T) {
+	log := logger.New("test", "test")
+	scheduler := NewScheduler(nil, &config.JobsConfig{}, log)
+	noop := func(ctx context.Context) error { return nil }
+	scheduler.MustRegister(&Job{Name: "inventory.alert_scan", Schedule: "@hourly", PerTenant: true, Run: noop})
+	scheduler.MustRegister(&Job{Name: "auth.session_cleanup", Schedule: "@daily", Run: noop})
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-User-Role", "admin")
+	NewHandler(scheduler, log).Routes().ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp struct {
+		Data []struct {
+			Name string `json:"name"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Len(t, resp.Data, 1)
+	assert.Equal(t, "inventory.alert_scan", resp.Data[0].Name)
+}
@@ -0,0 +1,140 @@
+// Package jobs provides a tenant-aware background job scheduler shared by all
+// MedFlow services.
+//
+// Features:
+//   - Cron-style schedules (see ParseSchedule)
+//   - Per-tenant fan-out over public.tenants with bounded concurrency
+//   - Distributed locking via PostgreSQL advisory locks, so only one replica
+//     executes a job at a time (Cloud Run scales services horizontally)
+//   - Run history per tenant in public.job_runs (duration, outcome, attempts)
+//   - Retry with exponential backoff per tenant
+//   - Manual triggering through the admin Handler
+//
+// Usage:
+//
+//	scheduler := jobs.NewScheduler(db, &cfg.Jobs, log)
+//	scheduler.MustRegister(&jobs.Job{
+//	    Name:      "inventory.alert_scan",
+//	    Schedule:  "*/15 * * * *",
+//	    PerTenant: true,
+//	    Run:       alertScanner.ScanAll,
+//	})
+//	scheduler.Start(ctx)
+//	defer scheduler.Stop()
+package jobs
+
+import (
+	"context"
+	"time"
+)
+
+// Default job settings
+const (
+	DefaultMaxRetries  = 2
+	DefaultRetryDelay  = 30 * time.Second
+	DefaultTimeout     = 10 * time.Minute
+	DefaultConcurrency = 4
+)
+
+// Trigger types recorded in run history
+const (
+	TriggerSchedule = "schedule"
+	TriggerManual   = "manual"
+)
+
+// Run status values recorded in run history
+const (
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// RunFunc is the unit of work executed by a job.
+// For per-tenant jobs the context carries the tenant ID (tenant.TenantID)
+// and the system actor (actor.FromContext).
+type RunFunc func(ctx context.Context) error
+
+// Job describes a scheduled background job
+type Job struct {
+	// Name uniquely identifies the job across all services (e.g. "inventory.alert_scan").
+	// It is also the key of the distributed lock.
+	Name string `json:"name"`
+
+	// Description is a human-readable summary shown in the admin endpoint
+	Description string `json:"description,omitempty"`
+
+	// Schedule is a cron expression or descriptor (see ParseSchedule)
+	Schedule string `json:"schedule"`
+
+	// PerTenant runs the job once per active tenant with a tenant-scoped context.
+	// Global jobs (e.g. session cleanup in the public schema) run once per activation.
+	PerTenant bool `json:"per_tenant"`
+
+	// RunOnStart triggers an immediate run when the scheduler starts
+	RunOnStart bool `json:"run_on_start"`
+
+	// MaxRetries is the number of retries after the first failed attempt (per tenant).
+	// Zero uses DefaultMaxRetries; use a negative value to disable retries.
+	MaxRetries int `json:"max_retries"`
+
+	// RetryDelay is the initial backoff between attempts; it doubles each retry
+	RetryDelay time.Duration `json:"retry_delay"`
+
+	// Timeout bounds a single attempt
+	Timeout time.Duration `json:"timeout"`
+
+	// Run executes the job
+	Run RunFunc `json:"-"`
+
+	schedule Schedule
+}
+
+// maxRetries returns the effective retry count
+func (j *Job) maxRetries() int {
+	if j.MaxRetries < 0 {
+		return 0
+	}
+	if j.MaxRetries == 0 {
+		return DefaultMaxRetries
+	}
+	return j.MaxRetries
+}
+
+// retryDelay returns the effective initial retry delay
+func (j *Job) retryDelay() time.Duration {
+	if j.RetryDelay <= 0 {
+		return DefaultRetryDelay
+	}
+	return j.RetryDelay
+}
+
+// timeout returns the effective per-attempt timeout
+func (j *Job) timeout() time.Duration {
+	if j.Timeout <= 0 {
+		return DefaultTimeout
+	}
+	return j.Timeout
+}
+
+// JobInfo is the admin view of a registered job
+type JobInfo struct {
+	*Job
+	NextRun *time.Time `json:"next_run,omitempty"`
+	Running bool       `json:"running"`
+}
+
+// Run is a persisted job execution record (public.job_runs)
+type Run struct {
+	ID          string     `db:"id" json:"id"`
+	JobName     string     `db:"job_name" json:"job_name"`
+	TenantID    *string    `db:"tenant_id" json:"tenant_id,omitempty"`
+	Trigger     string     `db:"trigger" json:"trigger"`
+	Status      string     `db:"status" json:"status"`
+	Attempts    int        `db:"attempts" json:"attempts"`
+	Error       *string    `db:"error" json:"error,omitempty"`
+	InstanceID  *string    `db:"instance_id" json:"instance_id,omitempty"`
+	ScheduledAt *time.Time `db:"scheduled_at" json:"scheduled_at,omitempty"` // activation of a scheduled run
+	StartedAt   time.Time  `db:"started_at" json:"started_at"`
+	FinishedAt  *time.Time `db:"finished_at" json:"finished_at,omitempty"`
+	DurationMs  *int64     `db:"duration_ms" json:"duration_ms,omitempty"`
+}
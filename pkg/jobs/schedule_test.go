@@ -0,0 +1,74 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchedule_Next(t *testing.T) {
+	// Wednesday, 2024-01-10 10:07:30 UTC
+	base := time.Date(2024, 1, 10, 10, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		expr string
+		want time.Time
+	}{
+		{"every 15 minutes", "*/15 * * * *", time.Date(2024, 1, 10, 10, 15, 0, 0, time.UTC)},
+		{"hourly", "@hourly", time.Date(2024, 1, 10, 11, 0, 0, 0, time.UTC)},
+		{"daily", "@daily", time.Date(2024, 1, 11, 0, 0, 0, 0, time.UTC)},
+		{"weekly is sunday", "@weekly", time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC)},
+		{"monthly", "@monthly", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"weekdays at 3am", "0 3 * * 1-5", time.Date(2024, 1, 11, 3, 0, 0, 0, time.UTC)},
+		{"sunday as 7", "30 2 * * 7", time.Date(2024, 1, 14, 2, 30, 0, 0, time.UTC)},
+		{"list", "5,20 10 * * *", time.Date(2024, 1, 10, 10, 20, 0, 0, time.UTC)},
+		{"range with step", "0-30/10 * * * *", time.Date(2024, 1, 10, 10, 10, 0, 0, time.UTC)},
+		{"leap day", "0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"dom or dow", "0 0 15 * 5", time.Date(2024, 1, 12, 0, 0, 0, 0, time.UTC)},
+		{"every duration is aligned", "@every 90s", time.Date(2024, 1, 10, 10, 9, 0, 0, time.UTC)},
+		{"every 15m", "@every 15m", time.Date(2024, 1, 10, 10, 15, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ParseSchedule(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, s.Next(base))
+		})
+	}
+}
+
+func TestParseSchedule_NextIsStrictlyAfter(t *testing.T) {
+	s := MustParseSchedule("0 * * * *")
+	at := time.Date(2024, 1, 10, 10, 0, 0, 0, time.UTC)
+	assert.Equal(t, at.Add(time.Hour), s.Next(at))
+}
+
+func TestParseSchedule_Unsatisfiable(t *testing.T) {
+	s := MustParseSchedule("0 0 31 2 *")
+	assert.True(t, s.Next(time.Now()).IsZero())
+}
+
+func TestParseSchedule_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"1,,2 * * * *",
+		"@every 10ms",
+		"@every soon",
+	} {
+		_, err := ParseSchedule(expr)
+		assert.Error(t, err, "expected error for %q", expr)
+	}
+}
@@ -0,0 +1,369 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/medflow/medflow-backend/pkg/actor"
+	"github.com/medflow/medflow-backend/pkg/config"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/medflow/medflow-backend/pkg/tenant"
+)
+
+// Scheduler runs registered jobs on their schedules
+type Scheduler struct {
+	store       *store
+	concurrency int
+	enabled     bool
+	instanceID  string
+	logger      *logger.Logger
+
+	mu      sync.Mutex
+	jobs    map[string]*Job
+	next    map[string]time.Time
+	running map[string]bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewScheduler creates a new job scheduler
+func NewScheduler(db *database.DB, cfg *config.JobsConfig, log *logger.Logger) *Scheduler {
+	concurrency := cfg.Concurrency
+	if concurrency < 1 {
+		concurrency = DefaultConcurrency
+	}
+
+	instanceID := cfg.InstanceID
+	if instanceID == "" {
+		instanceID, _ = os.Hostname()
+	}
+
+	return &Scheduler{
+		store:       &store{db: db},
+		concurrency: concurrency,
+		enabled:     cfg.Enabled,
+		instanceID:  instanceID,
+		logger:      log,
+		jobs:        make(map[string]*Job),
+		next:        make(map[string]time.Time),
+		running:     make(map[string]bool),
+	}
+}
+
+// Register adds a job to the scheduler. Must be called before Start.
+func (s *Scheduler) Register(job *Job) error {
+	if job.Name == "" {
+		return fmt.Errorf("job name is required")
+	}
+	if job.Run == nil {
+		return fmt.Errorf("job %s: run function is required", job.Name)
+	}
+
+	schedule, err := ParseSchedule(job.Schedule)
+	if err != nil {
+		return fmt.Errorf("job %s: %w", job.Name, err)
+	}
+	job.schedule = schedule
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.jobs[job.Name]; exists {
+		return fmt.Errorf("job %s already registered", job.Name)
+	}
+	s.jobs[job.Name] = job
+	return nil
+}
+
+// MustRegister registers a job and panics on error (invalid schedule or duplicate name)
+func (s *Scheduler) MustRegister(job *Job) {
+	if err := s.Register(job); err != nil {
+		panic(err)
+	}
+}
+
+// Start starts one scheduling goroutine per registered job.
+// When jobs are disabled via configuration, jobs can still be triggered manually.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.ctx, s.cancel = context.WithCancel(ctx)
+
+	if !s.enabled {
+		s.logger.Warn().Msg("job scheduler disabled by configuration (manual triggers only)")
+		return
+	}
+
+	s.mu.Lock()
+	jobs := make([]*Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, job)
+	}
+	s.mu.Unlock()
+
+	for _, job := range jobs {
+		s.wg.Add(1)
+		go s.loop(job)
+	}
+
+	s.logger.Info().Int("job_count", len(jobs)).Int("concurrency", s.concurrency).Msg("job scheduler started")
+}
+
+// Stop cancels all scheduling loops and waits for in-flight runs to finish
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+	s.logger.Info().Msg("job scheduler stopped")
+}
+
+// loop waits for each activation of a job and executes it
+func (s *Scheduler) loop(job *Job) {
+	defer s.wg.Done()
+
+	if job.RunOnStart {
+		s.execute(s.ctx, job, TriggerSchedule, "", time.Time{})
+	}
+
+	for {
+		next := job.schedule.Next(time.Now())
+		if next.IsZero() {
+			s.logger.Warn().Str("job", job.Name).Msg("schedule never fires, stopping job loop")
+			return
+		}
+
+		s.mu.Lock()
+		s.next[job.Name] = next
+		s.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			s.execute(s.ctx, job, TriggerSchedule, "", next)
+		}
+	}
+}
+
+// job looks up a registered job by name
+func (s *Scheduler) job(name string) (*Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[name]
+	return job, ok
+}
+
+// Trigger runs a job immediately in the background.
+// For per-tenant jobs, tenantID limits the run to that tenant; an empty
+// tenantID runs across all active tenants.
+func (s *Scheduler) Trigger(name, tenantID string) error {
+	job, ok := s.job(name)
+	if !ok {
+		return errors.NotFound("job")
+	}
+	if s.ctx == nil {
+		return errors.Conflict("errors.jobs.scheduler_not_started")
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.execute(s.ctx, job, TriggerManual, tenantID, time.Time{})
+	}()
+	return nil
+}
+
+// Jobs lists registered jobs sorted by name
+func (s *Scheduler) Jobs() []*JobInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	infos := make([]*JobInfo, 0, len(s.jobs))
+	for name, job := range s.jobs {
+		info := &JobInfo{Job: job, Running: s.running[name]}
+		if next, ok := s.next[name]; ok {
+			info.NextRun = &next
+		}
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+// Runs lists the most recent runs of a job for a tenant
+func (s *Scheduler) Runs(ctx context.Context, name, tenantID string, limit int) ([]*Run, error) {
+	if _, ok := s.job(name); !ok {
+		return nil, errors.NotFound("job")
+	}
+	return s.store.listRuns(ctx, name, tenantID, limit)
+}
+
+// execute runs one activation of a job under the distributed lock.
+// Overlapping activations on the same instance are skipped. A scheduled
+// activation (non-zero scheduledAt) is claimed under the lock first, so a
+// replica whose timer fires after another replica already finished it does
+// not run it again.
+func (s *Scheduler) execute(ctx context.Context, job *Job, trigger, onlyTenant string, scheduledAt time.Time) {
+	s.mu.Lock()
+	if s.running[job.Name] {
+		s.mu.Unlock()
+		s.logger.Warn().Str("job", job.Name).Msg("previous run still in progress, skipping activation")
+		return
+	}
+	s.running[job.Name] = true
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		s.running[job.Name] = false
+		s.mu.Unlock()
+	}()
+
+	release, err := s.store.tryLock(ctx, job.Name)
+	if err != nil {
+		s.logger.Error().Err(err).Str("job", job.Name).Msg("failed to acquire job lock")
+		return
+	}
+	if release == nil {
+		s.logger.Debug().Str("job", job.Name).Msg("job locked by another instance, skipping")
+		return
+	}
+	defer release()
+
+	var activation *time.Time
+	if !scheduledAt.IsZero() {
+		claimed, err := s.store.claimActivation(ctx, job.Name, scheduledAt, s.instanceID)
+		if err != nil {
+			s.logger.Error().Err(err).Str("job", job.Name).Msg("failed to claim job activation")
+			return
+		}
+		if !claimed {
+			s.logger.Debug().Str("job", job.Name).Time("scheduled_at", scheduledAt).Msg("activation already run by another instance, skipping")
+			return
+		}
+		activation = &scheduledAt
+	}
+
+	start := time.Now()
+	ctx = actor.WithActor(ctx, actor.SystemActor())
+
+	if !job.PerTenant {
+		s.runWithRetry(ctx, job, trigger, nil, activation)
+		s.logger.Info().Str("job", job.Name).Dur("duration", time.Since(start)).Msg("job completed")
+		return
+	}
+
+	tenantIDs := []string{onlyTenant}
+	if onlyTenant == "" {
+		tenantIDs, err = s.store.activeTenantIDs(ctx)
+		if err != nil {
+			s.logger.Error().Err(err).Str("job", job.Name).Msg("failed to query active tenants")
+			return
+		}
+	}
+
+	// Fan out across tenants with bounded concurrency
+	sem := make(chan struct{}, s.concurrency)
+	var wg sync.WaitGroup
+	for _, tenantID := range tenantIDs {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(tenantID string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			s.runWithRetry(tenant.WithTenantID(ctx, tenantID), job, trigger, &tenantID, activation)
+		}(tenantID)
+	}
+	wg.Wait()
+
+	s.logger.Info().
+		Str("job", job.Name).
+		Int("tenant_count", len(tenantIDs)).
+		Dur("duration", time.Since(start)).
+		Msg("job completed")
+}
+
+// runWithRetry executes a job for one tenant (or globally), retrying with
+// exponential backoff, and records the outcome in run history.
+func (s *Scheduler) runWithRetry(ctx context.Context, job *Job, trigger string, tenantID *string, scheduledAt *time.Time) {
+	run := &Run{
+		JobName:     job.Name,
+		TenantID:    tenantID,
+		Trigger:     trigger,
+		Attempts:    1,
+		InstanceID:  &s.instanceID,
+		ScheduledAt: scheduledAt,
+	}
+	if err := s.store.startRun(ctx, run); err != nil {
+		s.logger.Error().Err(err).Str("job", job.Name).Msg("failed to record job run start")
+		run.StartedAt = time.Now()
+	}
+
+	log := s.logger.With().Str("job", job.Name).Logger()
+	if tenantID != nil {
+		log = log.With().Str("tenant_id", *tenantID).Logger()
+	}
+
+	delay := job.retryDelay()
+	var err error
+	for attempt := 1; ; attempt++ {
+		run.Attempts = attempt
+		err = s.attempt(ctx, job)
+		if err == nil || attempt > job.maxRetries() || ctx.Err() != nil {
+			break
+		}
+
+		log.Warn().Err(err).Int("attempt", attempt).Dur("retry_in", delay).Msg("job attempt failed, retrying")
+		select {
+		case <-ctx.Done():
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+
+	run.Status = StatusSucceeded
+	if err != nil {
+		run.Status = StatusFailed
+		msg := err.Error()
+		run.Error = &msg
+		log.Error().Err(err).Int("attempts", run.Attempts).Msg("job failed")
+	}
+
+	if run.ID != "" {
+		// Record with a fresh context so shutdown still persists the outcome
+		recordCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := s.store.finishRun(recordCtx, run); err != nil {
+			log.Error().Err(err).Msg("failed to record job run result")
+		}
+	}
+}
+
+// attempt runs a single job attempt with timeout and panic recovery
+func (s *Scheduler) attempt(ctx context.Context, job *Job) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, job.timeout())
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return job.Run(ctx)
+}
@@ -0,0 +1,225 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/medflow/medflow-backend/pkg/config"
+	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/medflow/medflow-backend/pkg/tenant"
+	"github.com/medflow/medflow-backend/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	suite     *testutil.IntegrationSuite
+	suiteErr  error
+	suiteOnce sync.Once
+)
+
+// The schedule and handler tests need no database, so the integration suite
+// is only started by the tests that use it
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if suite != nil {
+		ctx := context.Background()
+		suite.Cleanup(ctx)
+		testutil.TerminateContainer(ctx)
+	}
+	os.Exit(code)
+}
+
+// integrationSuite starts the shared Postgres suite on first use
+func integrationSuite(t *testing.T) *testutil.IntegrationSuite {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	suiteOnce.Do(func() {
+		suite, suiteErr = testutil.NewIntegrationSuite(context.Background(), "public")
+	})
+	if suiteErr != nil {
+		t.Fatalf("failed to create integration suite: %v", suiteErr)
+	}
+	return suite
+}
+
+// newTestScheduler creates a started scheduler without scheduling loops and
+// clears the history of earlier test runs
+func newTestScheduler(t *testing.T, concurrency int) *Scheduler {
+	suite := integrationSuite(t)
+	_, err := suite.RawDB.Exec(`DELETE FROM public.job_runs WHERE job_name LIKE 'test.%'`)
+	require.NoError(t, err)
+
+	s := NewScheduler(suite.DB, &config.JobsConfig{Concurrency: concurrency, InstanceID: "test"}, logger.New("test", "test"))
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	t.Cleanup(s.Stop)
+	return s
+}
+
+// jobRuns lists the recorded runs of a job, oldest first
+func jobRuns(t *testing.T, name string) []*Run {
+	t.Helper()
+	var runs []*Run
+	err := integrationSuite(t).RawDB.Select(&runs, `
+		SELECT id, job_name, tenant_id, trigger, status, attempts, error, instance_id,
+		       scheduled_at, started_at, finished_at, duration_ms
+		FROM public.job_runs WHERE job_name = $1 ORDER BY started_at
+	`, name)
+	require.NoError(t, err)
+	return runs
+}
+
+func TestStore_TryLock(t *testing.T) {
+	st := &store{db: integrationSuite(t).DB}
+	ctx := context.Background()
+
+	release, err := st.tryLock(ctx, "test.lock")
+	require.NoError(t, err)
+	require.NotNil(t, release)
+
+	// Another replica (connection) cannot take the lock
+	other, err := st.tryLock(ctx, "test.lock")
+	require.NoError(t, err)
+	assert.Nil(t, other)
+
+	// Locks of other jobs are independent
+	unrelated, err := st.tryLock(ctx, "test.lock.other")
+	require.NoError(t, err)
+	require.NotNil(t, unrelated)
+	unrelated()
+
+	release()
+	again, err := st.tryLock(ctx, "test.lock")
+	require.NoError(t, err)
+	require.NotNil(t, again)
+	again()
+}
+
+func TestStore_ClaimActivation(t *testing.T) {
+	st := &store{db: integrationSuite(t).DB}
+	ctx := context.Background()
+	at := time.Now().UTC().Truncate(time.Minute)
+
+	claimed, err := st.claimActivation(ctx, "test.claim", at, "replica-a")
+	require.NoError(t, err)
+	assert.True(t, claimed)
+
+	claimed, err = st.claimActivation(ctx, "test.claim", at, "replica-b")
+	require.NoError(t, err)
+	assert.False(t, claimed, "an activation is claimed once")
+
+	claimed, err = st.claimActivation(ctx, "test.claim", at.Add(time.Minute), "replica-b")
+	require.NoError(t, err)
+	assert.True(t, claimed)
+}
+
+func TestScheduler_ScheduledActivationRunsOnce(t *testing.T) {
+	s := newTestScheduler(t, 1)
+	var calls atomic.Int32
+	job := &Job{Name: "test.activation", Schedule: "@every 1m", Run: func(ctx context.Context) error {
+		calls.Add(1)
+		return nil
+	}}
+	require.NoError(t, s.Register(job))
+
+	at := job.schedule.Next(time.Now())
+	s.execute(s.ctx, job, TriggerSchedule, "", at)
+	s.execute(s.ctx, job, TriggerSchedule, "", at)
+	assert.Equal(t, int32(1), calls.Load())
+
+	runs := jobRuns(t, job.Name)
+	require.Len(t, runs, 1)
+	require.NotNil(t, runs[0].ScheduledAt)
+	assert.True(t, at.Equal(*runs[0].ScheduledAt))
+}
+
+func TestScheduler_RetryRecordsAttempts(t *testing.T) {
+	s := newTestScheduler(t, 1)
+	var calls atomic.Int32
+	job := &Job{Name: "test.retry", Schedule: "@daily", MaxRetries: 2, RetryDelay: time.Millisecond, Run: func(ctx context.Context) error {
+		if calls.Add(1) < 3 {
+			return errors.New("transient")
+		}
+		return nil
+	}}
+	require.NoError(t, s.Register(job))
+
+	s.execute(s.ctx, job, TriggerManual, "", time.Time{})
+
+	runs := jobRuns(t, job.Name)
+	require.Len(t, runs, 1)
+	assert.Equal(t, StatusSucceeded, runs[0].Status)
+	assert.Equal(t, 3, runs[0].Attempts)
+	assert.Nil(t, runs[0].TenantID, "global jobs record no tenant")
+
+	// Out of retries the run fails with the last error
+	failing := &Job{Name: "test.retry_exhausted", Schedule: "@daily", MaxRetries: 1, RetryDelay: time.Millisecond, Run: func(ctx context.Context) error {
+		return errors.New("permanent")
+	}}
+	require.NoError(t, s.Register(failing))
+	s.execute(s.ctx, failing, TriggerManual, "", time.Time{})
+
+	runs = jobRuns(t, failing.Name)
+	require.Len(t, runs, 1)
+	assert.Equal(t, StatusFailed, runs[0].Status)
+	assert.Equal(t, 2, runs[0].Attempts)
+	require.NotNil(t, runs[0].Error)
+	assert.Equal(t, "permanent", *runs[0].Error)
+}
+
+func TestScheduler_PerTenantFanOutIsBounded(t *testing.T) {
+	ctx := context.Background()
+	ours := map[string]bool{}
+	for _, name := range []string{"fan-out-a", "fan-out-b", "fan-out-c", "fan-out-d", "fan-out-e"} {
+		ours[integrationSuite(t).SetupTenant(t, ctx, name, nil).ID] = true
+	}
+
+	s := newTestScheduler(t, 2)
+	var mu sync.Mutex
+	var active, maxActive int
+	seen := map[string]bool{}
+	job := &Job{Name: "test.fan_out", Schedule: "@hourly", PerTenant: true, Run: func(ctx context.Context) error {
+		tenantID, err := tenant.TenantID(ctx)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		seen[tenantID] = true
+		active++
+		maxActive = max(maxActive, active)
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		active--
+		mu.Unlock()
+		return nil
+	}}
+	require.NoError(t, s.Register(job))
+
+	s.execute(s.ctx, job, TriggerManual, "", time.Time{})
+
+	for id := range ours {
+		assert.True(t, seen[id], "every active tenant is processed")
+	}
+	assert.LessOrEqual(t, maxActive, 2)
+	assert.Equal(t, 2, maxActive, "tenants are processed in parallel")
+
+	// A manual trigger for one tenant runs only that tenant
+	var one string
+	for id := range ours {
+		one = id
+		break
+	}
+	seen = map[string]bool{}
+	s.execute(s.ctx, job, TriggerManual, one, time.Time{})
+	assert.Equal(t, map[string]bool{one: true}, seen)
+}
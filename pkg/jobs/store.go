@@ -0,0 +1,148 @@
+package jobs
+
+import (
+	"context"
+	"hash/fnv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/medflow/medflow-backend/pkg/database"
+)
+
+// activationRetention is how long claimed activations are kept. It only has
+// to outlast the clock skew between replicas.
+const activationRetention = 7 * 24 * time.Hour
+
+// store persists job run history and resolves tenants.
+// All queries target the public schema (no RLS) and bypass any
+// transaction stored in the context by calling the embedded sqlx.DB directly.
+type store struct {
+	db *database.DB
+}
+
+// activeTenantIDs queries the IDs of tenants that are not deleted and have an
+// active or trial subscription (the tenants that can log in)
+func (s *store) activeTenantIDs(ctx context.Context) ([]string, error) {
+	var tenantIDs []string
+	query := `
+		SELECT id FROM public.tenants
+		WHERE deleted_at IS NULL AND subscription_status IN ('active', 'trial')
+		ORDER BY id
+	`
+	if err := s.db.DB.SelectContext(ctx, &tenantIDs, query); err != nil {
+		return nil, err
+	}
+	return tenantIDs, nil
+}
+
+// startRun inserts a running job_runs row
+func (s *store) startRun(ctx context.Context, run *Run) error {
+	if run.ID == "" {
+		run.ID = uuid.New().String()
+	}
+	query := `
+		INSERT INTO public.job_runs (id, job_name, tenant_id, trigger, status, attempts, instance_id, scheduled_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING started_at
+	`
+	return s.db.DB.QueryRowxContext(ctx, query,
+		run.ID, run.JobName, run.TenantID, run.Trigger, StatusRunning, run.Attempts, run.InstanceID, run.ScheduledAt,
+	).Scan(&run.StartedAt)
+}
+
+// finishRun records the outcome of a job run
+func (s *store) finishRun(ctx context.Context, run *Run) error {
+	now := time.Now()
+	duration := now.Sub(run.StartedAt).Milliseconds()
+	run.FinishedAt = &now
+	run.DurationMs = &duration
+
+	query := `
+		UPDATE public.job_runs
+		SET status = $2, attempts = $3, error = $4, finished_at = $5, duration_ms = $6
+		WHERE id = $1
+	`
+	_, err := s.db.DB.ExecContext(ctx, query,
+		run.ID, run.Status, run.Attempts, run.Error, run.FinishedAt, run.DurationMs,
+	)
+	return err
+}
+
+// claimActivation records that a scheduled activation of a job is being
+// run. Returns false when another replica already ran it. Claims older than
+// activationRetention are pruned on the way.
+func (s *store) claimActivation(ctx context.Context, jobName string, scheduledAt time.Time, instanceID string) (bool, error) {
+	query := `
+		INSERT INTO public.job_activations (job_name, scheduled_at, instance_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (job_name, scheduled_at) DO NOTHING
+	`
+	result, err := s.db.DB.ExecContext(ctx, query, jobName, scheduledAt, instanceID)
+	if err != nil {
+		return false, err
+	}
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	pruneQuery := `DELETE FROM public.job_activations WHERE job_name = $1 AND claimed_at < $2`
+	if _, err := s.db.DB.ExecContext(ctx, pruneQuery, jobName, time.Now().Add(-activationRetention)); err != nil {
+		return false, err
+	}
+	return claimed == 1, nil
+}
+
+// listRuns lists recent runs of a job for a tenant. Runs of global jobs
+// (tenant_id IS NULL) are never part of a tenant's history.
+func (s *store) listRuns(ctx context.Context, jobName, tenantID string, limit int) ([]*Run, error) {
+	var runs []*Run
+	query := `
+		SELECT id, job_name, tenant_id, trigger, status, attempts, error, instance_id,
+		       scheduled_at, started_at, finished_at, duration_ms
+		FROM public.job_runs
+		WHERE job_name = $1 AND tenant_id = $2
+		ORDER BY started_at DESC
+		LIMIT $3
+	`
+	if err := s.db.DB.SelectContext(ctx, &runs, query, jobName, tenantID, limit); err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+// tryLock acquires a session-level advisory lock on a dedicated connection.
+// Returns a release function when the lock was acquired, or nil when another
+// replica already holds it.
+func (s *store) tryLock(ctx context.Context, name string) (func(), error) {
+	conn, err := s.db.DB.Connx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key := lockKey(name)
+	var acquired bool
+	if err := conn.QueryRowxContext(ctx, `SELECT pg_try_advisory_lock($1)`, key).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if !acquired {
+		conn.Close()
+		return nil, nil
+	}
+
+	return func() {
+		// Use a fresh context: the job context may already be cancelled
+		unlockCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		conn.ExecContext(unlockCtx, `SELECT pg_advisory_unlock($1)`, key)
+		conn.Close()
+	}, nil
+}
+
+// lockKey maps a job name to a stable advisory lock key
+func lockKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("medflow.jobs:" + name))
+	return int64(h.Sum64())
+}
@@ -0,0 +1,153 @@
+// Package label lays out small labels with a title, a few lines of text and
+// a barcode, and renders them as ZPL for Zebra-compatible thermal printers
+// or as PDF for office printers and label sheets.
+//
+// The layout depends on the symbology: a square 2D code (DataMatrix, QR)
+// sits at the left edge with the text beside it, a linear code (GS1-128)
+// runs along the bottom edge under the text.
+package label
+
+import (
+	"fmt"
+
+	"github.com/medflow/medflow-backend/pkg/barcode"
+)
+
+// Output formats
+const (
+	FormatZPL = "zpl"
+	FormatPDF = "pdf"
+)
+
+// Printer resolutions in dots per inch supported for ZPL
+var Resolutions = []int{203, 300, 600}
+
+// Template is the label stock and the symbology printed on it
+type Template struct {
+	WidthMM   float64
+	HeightMM  float64
+	DPI       int
+	Symbology string
+	// Columns and Rows lay out labels on A4 sheets in PDF output; zero
+	// prints one label per page of the label's size
+	Columns int
+	Rows    int
+}
+
+// Label is the content of one label
+type Label struct {
+	Title string
+	Lines []string
+	// Code is the data to encode; with GS1 set, a GS1 element string as
+	// built by barcode.ElementString
+	Code string
+	GS1  bool
+	// Caption is printed under a linear code or below the text of a 2D code
+	Caption string
+	Copies  int
+}
+
+// Limits of the label size in millimetres
+const (
+	MinSizeMM = 15
+	MaxSizeMM = 150
+)
+
+// margin around the label content, in millimetres
+const margin = 1.5
+
+// Validate checks that the template can be rendered
+func (t Template) Validate() error {
+	if t.WidthMM < MinSizeMM || t.WidthMM > MaxSizeMM || t.HeightMM < MinSizeMM || t.HeightMM > MaxSizeMM {
+		return fmt.Errorf("label: size %.0fx%.0f mm is outside %d-%d mm", t.WidthMM, t.HeightMM, MinSizeMM, MaxSizeMM)
+	}
+	if !barcode.ValidSymbology(t.Symbology) {
+		return fmt.Errorf("label: unsupported symbology %q", t.Symbology)
+	}
+	if !validResolution(t.DPI) {
+		return fmt.Errorf("label: unsupported resolution %d dpi", t.DPI)
+	}
+	if t.Columns < 0 || t.Rows < 0 || (t.Columns == 0) != (t.Rows == 0) {
+		return fmt.Errorf("label: sheet needs both columns and rows")
+	}
+	if t.Columns > 0 && (float64(t.Columns)*t.WidthMM > a4Width || float64(t.Rows)*t.HeightMM > a4Height) {
+		return fmt.Errorf("label: %dx%d labels do not fit on an A4 sheet", t.Columns, t.Rows)
+	}
+	return nil
+}
+
+func validResolution(dpi int) bool {
+	for _, r := range Resolutions {
+		if r == dpi {
+			return true
+		}
+	}
+	return false
+}
+
+// box is a rectangle in millimetres from the label's top left corner
+type box struct {
+	x, y, w, h float64
+}
+
+// layout places the code and the text on a label
+type layout struct {
+	code     box
+	text     box
+	caption  box
+	titlePt  float64
+	linePt   float64
+	linear   bool
+	maxLines int
+}
+
+// Text sizes in millimetres of cap height plus leading
+const (
+	titleHeight   = 3.6
+	lineHeight    = 2.9
+	captionHeight = 2.4
+)
+
+// quietZone is the blank space, in modules, a linear code needs on either side
+const quietZone = 10
+
+// ptPerMM converts a text height in millimetres to a font size in points
+const ptPerMM = 72 / 25.4
+
+func (t Template) layout() layout {
+	inner := box{margin, margin, t.WidthMM - 2*margin, t.HeightMM - 2*margin}
+	l := layout{
+		titlePt: titleHeight * ptPerMM * 0.85,
+		linePt:  lineHeight * ptPerMM * 0.85,
+	}
+
+	if t.Symbology == barcode.SymbologyCode128 {
+		l.linear = true
+		barHeight := min(12, inner.h*0.4)
+		l.caption = box{inner.x, inner.y + inner.h - captionHeight, inner.w, captionHeight}
+		l.code = box{inner.x, l.caption.y - barHeight, inner.w, barHeight}
+		l.text = box{inner.x, inner.y, inner.w, l.code.y - inner.y - 0.5}
+	} else {
+		side := min(inner.h, inner.w*0.45)
+		l.code = box{inner.x, inner.y, side, side}
+		textX := inner.x + side + margin
+		l.text = box{textX, inner.y, inner.x + inner.w - textX, inner.h - captionHeight}
+		l.caption = box{textX, inner.y + inner.h - captionHeight, l.text.w, captionHeight}
+	}
+
+	l.maxLines = max(int((l.text.h-titleHeight)/lineHeight), 0)
+	return l
+}
+
+// lines returns the text lines of a label that fit the layout
+func (l layout) lines(lb Label) []string {
+	if len(lb.Lines) > l.maxLines {
+		return lb.Lines[:l.maxLines]
+	}
+	return lb.Lines
+}
+
+// copies is the number of times a label is printed
+func (lb Label) copies() int {
+	return max(lb.Copies, 1)
+}
@@ -0,0 +1,103 @@
+package label
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/medflow/medflow-backend/pkg/barcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func batchLabel(t *testing.T) Label {
+	t.Helper()
+	code, err := barcode.ElementString(
+		barcode.Element{AI: "01", Value: "09506000134352"},
+		barcode.Element{AI: "17", Value: "261231"},
+		barcode.Element{AI: "10", Value: "ABC_123"},
+	)
+	require.NoError(t, err)
+	return Label{
+		Title:   "Lidocain 2% ^Ampulle",
+		Lines:   []string{"Ch.-B.: ABC_123", "Verw. bis: 31.12.2026", "Kühlschrank 1"},
+		Code:    code,
+		GS1:     true,
+		Caption: "(01)09506000134352(17)261231(10)ABC_123",
+		Copies:  3,
+	}
+}
+
+func TestTemplate_Validate(t *testing.T) {
+	valid := Template{WidthMM: 57, HeightMM: 32, DPI: 203, Symbology: barcode.SymbologyDataMatrix}
+	assert.NoError(t, valid.Validate())
+
+	tests := map[string]Template{
+		"too small":       {WidthMM: 10, HeightMM: 32, DPI: 203, Symbology: barcode.SymbologyQR},
+		"symbology":       {WidthMM: 57, HeightMM: 32, DPI: 203, Symbology: "ean13"},
+		"resolution":      {WidthMM: 57, HeightMM: 32, DPI: 150, Symbology: barcode.SymbologyQR},
+		"rows missing":    {WidthMM: 57, HeightMM: 32, DPI: 203, Symbology: barcode.SymbologyQR, Columns: 3},
+		"too wide for A4": {WidthMM: 80, HeightMM: 32, DPI: 203, Symbology: barcode.SymbologyQR, Columns: 3, Rows: 8},
+	}
+	for name, tmpl := range tests {
+		assert.Error(t, tmpl.Validate(), name)
+	}
+}
+
+func TestZPL_DataMatrix(t *testing.T) {
+	tmpl := Template{WidthMM: 57, HeightMM: 32, DPI: 203, Symbology: barcode.SymbologyDataMatrix}
+	out, err := ZPL(tmpl, []Label{batchLabel(t), {Title: "Second", Code: "ART-1"}})
+	require.NoError(t, err)
+	zpl := string(out)
+
+	assert.Equal(t, 2, strings.Count(zpl, "^XA"))
+	assert.Contains(t, zpl, "^PW456\n^LL256\n") // 57 x 32 mm at 203 dpi
+	assert.Contains(t, zpl, "^FDLidocain 2% \\5EAmpulle^FS", "^ is hex-escaped in text")
+	assert.Contains(t, zpl, "^FDKühlschrank 1^FS", "UTF-8 with ^CI28")
+	assert.Contains(t, zpl, ",6,_^FD_101095060001343521726123110ABC_d095123^FS", "FNC1 first, _ escaped")
+	assert.Contains(t, zpl, "^PQ3\n")
+	assert.Contains(t, zpl, "^FDART-1^FS")
+	assert.Contains(t, zpl, "^PQ1\n")
+}
+
+func TestZPL_GS1128(t *testing.T) {
+	tmpl := Template{WidthMM: 100, HeightMM: 50, DPI: 300, Symbology: barcode.SymbologyCode128}
+	out, err := ZPL(tmpl, []Label{batchLabel(t)})
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "^BCN,142,N,N,N,N^FD>;>801095060001343521726123110>6ABC_123^FS")
+}
+
+func TestZPLCode128Data(t *testing.T) {
+	tests := []struct {
+		data string
+		gs1  bool
+		want string
+	}{
+		{"0109506000134352\x1d10A1", true, ">;>80109506000134352>810>6A1"},
+		{"10AB\x1d21123456", true, ">;>810>6AB>8>521123456"},
+		{"ART>4711", false, ">:ART><>54711"},
+		{"12345678", false, ">;12345678"},
+		{"123AB", false, ">:123AB"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, zplCode128Data(tt.data, tt.gs1), "%q", tt.data)
+	}
+}
+
+func TestPDF_Pages(t *testing.T) {
+	labels := []Label{batchLabel(t), {Title: "Regal 3", Code: "LOC-3"}}
+
+	// One page per label copy on label stock
+	tmpl := Template{WidthMM: 57, HeightMM: 32, DPI: 203, Symbology: barcode.SymbologyQR}
+	out, err := PDF(tmpl, labels)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(out, []byte("%PDF")))
+	assert.Equal(t, 4, bytes.Count(out, []byte("/Type /Page\n")))
+
+	// Four labels fit on one A4 sheet of 3x8
+	tmpl.Symbology = barcode.SymbologyCode128
+	tmpl.WidthMM, tmpl.HeightMM, tmpl.Columns, tmpl.Rows = 70, 37, 3, 8
+	out, err = PDF(tmpl, labels)
+	require.NoError(t, err)
+	assert.Equal(t, 1, bytes.Count(out, []byte("/Type /Page\n")))
+}
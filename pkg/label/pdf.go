@@ -0,0 +1,137 @@
+package label
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/medflow/medflow-backend/pkg/barcode"
+)
+
+// A4 sheet size in millimetres
+const (
+	a4Width  = 210.0
+	a4Height = 297.0
+)
+
+// PDF renders labels one per page of the label's size, or on A4 sheets when
+// the template has columns and rows. Sheets are centred on the page, as on
+// common label stock without an outer margin of its own.
+func PDF(t Template, labels []Label) ([]byte, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	l := t.layout()
+
+	sheet := t.Columns > 0
+	var pdf *fpdf.Fpdf
+	if sheet {
+		pdf = fpdf.New("P", "mm", "A4", "")
+	} else {
+		pdf = fpdf.NewCustom(&fpdf.InitType{
+			OrientationStr: "P",
+			UnitStr:        "mm",
+			Size:           fpdf.SizeType{Wd: t.WidthMM, Ht: t.HeightMM},
+		})
+	}
+	pdf.SetMargins(0, 0, 0)
+	pdf.SetAutoPageBreak(false, 0)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	offsetX := (a4Width - float64(t.Columns)*t.WidthMM) / 2
+	offsetY := (a4Height - float64(t.Rows)*t.HeightMM) / 2
+	slot := 0
+	perSheet := t.Columns * t.Rows
+
+	for _, lb := range labels {
+		var m *barcode.Matrix
+		if lb.Code != "" {
+			var err error
+			if m, err = barcode.Encode(t.Symbology, lb.Code, lb.GS1); err != nil {
+				return nil, err
+			}
+		}
+
+		for n := 0; n < lb.copies(); n++ {
+			x, y := 0.0, 0.0
+			if sheet {
+				if slot%perSheet == 0 {
+					pdf.AddPage()
+				}
+				x = offsetX + float64(slot%t.Columns)*t.WidthMM
+				y = offsetY + float64(slot%perSheet/t.Columns)*t.HeightMM
+				slot++
+			} else {
+				pdf.AddPage()
+			}
+			pdfLabel(pdf, tr, l, lb, m, x, y)
+		}
+	}
+
+	if len(labels) == 0 {
+		pdf.AddPage()
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to generate PDF: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func pdfLabel(pdf *fpdf.Fpdf, tr func(string) string, l layout, lb Label, m *barcode.Matrix, x, y float64) {
+	pdfText(pdf, tr, x+l.text.x, y+l.text.y, l.text.w, titleHeight, "B", l.titlePt, lb.Title)
+	lineY := y + l.text.y + titleHeight
+	for _, line := range l.lines(lb) {
+		pdfText(pdf, tr, x+l.text.x, lineY, l.text.w, lineHeight, "", l.linePt, line)
+		lineY += lineHeight
+	}
+	if lb.Caption != "" {
+		pdfText(pdf, tr, x+l.caption.x, y+l.caption.y, l.caption.w, captionHeight, "", captionHeight*ptPerMM*0.85, lb.Caption)
+	}
+
+	if m == nil {
+		return
+	}
+	pdf.SetFillColor(0, 0, 0)
+	if l.linear {
+		module := l.code.w / float64(m.Width+2*quietZone)
+		left := x + l.code.x + quietZone*module
+		for col := 0; col < m.Width; col++ {
+			if !m.Dark(col, 0) {
+				continue
+			}
+			// Merge adjacent dark modules into one bar
+			end := col
+			for end+1 < m.Width && m.Dark(end+1, 0) {
+				end++
+			}
+			pdf.Rect(left+float64(col)*module, y+l.code.y, float64(end-col+1)*module, l.code.h, "F")
+			col = end
+		}
+		return
+	}
+
+	module := l.code.w / float64(max(m.Width, m.Height))
+	for row := 0; row < m.Height; row++ {
+		for col := 0; col < m.Width; col++ {
+			if m.Dark(col, row) {
+				pdf.Rect(x+l.code.x+float64(col)*module, y+l.code.y+float64(row)*module, module, module, "F")
+			}
+		}
+	}
+}
+
+// pdfText writes one line of text, shortened to fit the width
+func pdfText(pdf *fpdf.Fpdf, tr func(string) string, x, y, w, h float64, style string, pt float64, text string) {
+	if text == "" {
+		return
+	}
+	pdf.SetFont("Arial", style, pt)
+	s := tr(text)
+	for len(s) > 1 && pdf.GetStringWidth(s) > w {
+		s = s[:len(s)-1]
+	}
+	pdf.SetXY(x, y)
+	pdf.CellFormat(w, h, s, "", 0, "LT", false, 0, "")
+}
@@ -0,0 +1,181 @@
+package label
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/medflow/medflow-backend/pkg/barcode"
+)
+
+// ZPL renders labels as one ZPL II format each (^XA ... ^XZ). Barcodes use
+// the printer's own symbologies so they print at full resolution; their
+// size is taken from encoding the data here.
+func ZPL(t Template, labels []Label) ([]byte, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	l := t.layout()
+	dpmm := float64(t.DPI) / 25.4
+	dots := func(mm float64) int { return int(math.Round(mm * dpmm)) }
+
+	var b bytes.Buffer
+	for _, lb := range labels {
+		b.WriteString("^XA\n^CI28\n")
+		fmt.Fprintf(&b, "^PW%d\n^LL%d\n^LH0,0\n", dots(t.WidthMM), dots(t.HeightMM))
+
+		// Text, each line clipped to the text width by a one-line field block
+		y := l.text.y
+		zplText(&b, dots(l.text.x), dots(y), dots(titleHeight*0.85), dots(l.text.w), lb.Title)
+		y += titleHeight
+		for _, line := range l.lines(lb) {
+			zplText(&b, dots(l.text.x), dots(y), dots(lineHeight*0.85), dots(l.text.w), line)
+			y += lineHeight
+		}
+		if lb.Caption != "" {
+			zplText(&b, dots(l.caption.x), dots(l.caption.y), dots(captionHeight*0.85), dots(l.caption.w), lb.Caption)
+		}
+
+		if lb.Code != "" {
+			if err := zplCode(&b, t.Symbology, lb, l.code, dots); err != nil {
+				return nil, err
+			}
+		}
+
+		fmt.Fprintf(&b, "^PQ%d\n^XZ\n", lb.copies())
+	}
+	return b.Bytes(), nil
+}
+
+// zplText writes a text field; ^FH lets the escaped text contain ^ and ~
+func zplText(b *bytes.Buffer, x, y, height, width int, text string) {
+	if text == "" {
+		return
+	}
+	fmt.Fprintf(b, "^FO%d,%d^A0N,%d,%d^FB%d,1,0,L,0^FH\\^FD%s^FS\n", x, y, height, height, width, zplEscape(text))
+}
+
+// zplEscape hex-escapes the characters ^FH gives a meaning in field data
+func zplEscape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' || c == '^' || c == '~' || c < 0x20:
+			fmt.Fprintf(&b, "\\%02X", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+func zplCode(b *bytes.Buffer, symbology string, lb Label, area box, dots func(float64) int) error {
+	m, err := barcode.Encode(symbology, lb.Code, lb.GS1)
+	if err != nil {
+		return err
+	}
+
+	switch symbology {
+	case barcode.SymbologyDataMatrix:
+		module := clamp(dots(area.w)/m.Width, 1, 20)
+		fmt.Fprintf(b, "^FO%d,%d^BXN,%d,200,%d,%d,6,_^FD%s^FS\n",
+			dots(area.x), dots(area.y), module, m.Width, m.Height, zplDataMatrixData(lb.Code, lb.GS1))
+
+	case barcode.SymbologyQR:
+		// The printer adds no quiet zone; ^BQ cannot start with FNC1, so a
+		// GS1 element string is encoded as text with GS separators
+		module := clamp(dots(area.w)/m.Width, 1, 10)
+		fmt.Fprintf(b, "^FO%d,%d^BQN,2,%d^FH\\^FDMA,%s^FS\n", dots(area.x), dots(area.y), module, zplEscape(lb.Code))
+
+	case barcode.SymbologyCode128:
+		module := clamp(dots(area.w)/(m.Width+2*quietZone), 1, 10)
+		fmt.Fprintf(b, "^BY%d,3,%d\n^FO%d,%d^BCN,%d,N,N,N,N^FD%s^FS\n",
+			module, dots(area.h), dots(area.x)+quietZone*module, dots(area.y), dots(area.h), zplCode128Data(lb.Code, lb.GS1))
+	}
+	return nil
+}
+
+// zplDataMatrixData escapes data for ^BX with "_" as escape character: "_1"
+// is FNC1, "_dNNN" a character by its decimal value
+func zplDataMatrixData(data string, gs1 bool) string {
+	var b strings.Builder
+	if gs1 {
+		b.WriteString("_1")
+	}
+	for i := 0; i < len(data); i++ {
+		switch c := data[i]; {
+		case gs1 && c == 0x1d:
+			b.WriteString("_1")
+		case c == '_' || c == '^' || c == '~' || c < 0x20 || c > 0x7e:
+			fmt.Fprintf(&b, "_d%03d", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// zplCode128Data writes Code 128 data with explicit subset invocation codes
+// (^BC without mode): ">;" starts subset C, ">:" subset B, ">5" and ">6"
+// switch to C and B, ">8" is FNC1. Runs of four or more digits go in
+// subset C, two digits per symbol character.
+func zplCode128Data(data string, gs1 bool) string {
+	digitsAt := func(i int) int {
+		n := 0
+		for i+n < len(data) && data[i+n] >= '0' && data[i+n] <= '9' {
+			n++
+		}
+		return n
+	}
+
+	var b strings.Builder
+	subsetC := digitsAt(0) >= 4 || (gs1 && digitsAt(0) >= 2 && digitsAt(0)%2 == 0)
+	if subsetC {
+		b.WriteString(">;")
+	} else {
+		b.WriteString(">:")
+	}
+	if gs1 {
+		b.WriteString(">8")
+	}
+
+	for i := 0; i < len(data); {
+		c := data[i]
+		if gs1 && c == 0x1d {
+			b.WriteString(">8")
+			i++
+			continue
+		}
+		run := digitsAt(i)
+		if subsetC {
+			if run >= 2 {
+				b.WriteString(data[i : i+2])
+				i += 2
+				continue
+			}
+			b.WriteString(">6")
+			subsetC = false
+		}
+		if run >= 4 && run%2 == 0 {
+			b.WriteString(">5")
+			subsetC = true
+			continue
+		}
+		switch {
+		case c == '>':
+			b.WriteString("><")
+		case c == '^' || c == '~' || c == '\\':
+			// Not in GS1 data; replaced rather than breaking the format
+			b.WriteByte('?')
+		default:
+			b.WriteByte(c)
+		}
+		i++
+	}
+	return b.String()
+}
+
+func clamp(v, lo, hi int) int {
+	return min(max(v, lo), hi)
+}
@@ -0,0 +1,150 @@
+// Package mail sends plain-text email, e.g. alert notifications.
+//
+// The transport is pluggable like the malware scanner: SMTP in production,
+// the service log in development and a no-op in tests. Select it with
+// MEDFLOW_MAIL_TRANSPORT.
+package mail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	netmail "net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/medflow/medflow-backend/pkg/config"
+	"github.com/medflow/medflow-backend/pkg/logger"
+)
+
+// ErrNoRecipients is returned for a message without recipients
+var ErrNoRecipients = errors.New("mail: message has no recipients")
+
+// Message is a plain-text email
+type Message struct {
+	From    string
+	To      []string
+	Subject string
+	Text    string
+}
+
+// Transport delivers messages
+type Transport interface {
+	// Send delivers msg. An error means the message was not accepted and may
+	// be retried.
+	Send(ctx context.Context, msg *Message) error
+	// Name identifies the transport in logs
+	Name() string
+}
+
+// New creates the transport selected by cfg.Transport
+func New(cfg *config.MailConfig, log *logger.Logger) (Transport, error) {
+	switch cfg.Transport {
+	case "none":
+		return Noop{}, nil
+	case "log":
+		return &Log{logger: log}, nil
+	case "smtp":
+		return NewSMTP(&cfg.SMTP, cfg.Timeout), nil
+	default:
+		return nil, fmt.Errorf("mail: unknown transport %q", cfg.Transport)
+	}
+}
+
+// Noop drops every message. It is meant for tests.
+type Noop struct{}
+
+// Send implements Transport
+func (Noop) Send(ctx context.Context, msg *Message) error { return nil }
+
+// Name implements Transport
+func (Noop) Name() string { return "none" }
+
+// Log writes messages to the service log instead of sending them. It is
+// meant for development, where no mail relay runs.
+type Log struct {
+	logger *logger.Logger
+}
+
+// Send implements Transport
+func (l *Log) Send(ctx context.Context, msg *Message) error {
+	if len(msg.To) == 0 {
+		return ErrNoRecipients
+	}
+	l.logger.Info().
+		Str("to", strings.Join(msg.To, ", ")).
+		Str("subject", msg.Subject).
+		Str("text", msg.Text).
+		Msg("mail (log transport)")
+	return nil
+}
+
+// Name implements Transport
+func (l *Log) Name() string { return "log" }
+
+// Bytes renders msg as an RFC 5322 message with a quoted-printable UTF-8
+// body. Addresses are parsed and re-encoded, so header injection through
+// them is not possible.
+func (msg *Message) Bytes(now time.Time) ([]byte, error) {
+	from, err := netmail.ParseAddress(msg.From)
+	if err != nil {
+		return nil, fmt.Errorf("mail: invalid sender %q: %w", msg.From, err)
+	}
+	if len(msg.To) == 0 {
+		return nil, ErrNoRecipients
+	}
+	to := make([]string, len(msg.To))
+	for i, addr := range msg.To {
+		parsed, err := netmail.ParseAddress(addr)
+		if err != nil {
+			return nil, fmt.Errorf("mail: invalid recipient %q: %w", addr, err)
+		}
+		to[i] = parsed.String()
+	}
+
+	domain := "localhost"
+	if at := strings.LastIndex(from.Address, "@"); at >= 0 {
+		domain = from.Address[at+1:]
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%s@%s>\r\n", uuid.New().String(), domain)
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	qp := quotedprintable.NewWriter(&buf)
+	text := strings.ReplaceAll(strings.ReplaceAll(msg.Text, "\r\n", "\n"), "\n", "\r\n")
+	if _, err := qp.Write([]byte(text)); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// envelope returns the bare sender and recipient addresses of msg
+func (msg *Message) envelope() (string, []string, error) {
+	from, err := netmail.ParseAddress(msg.From)
+	if err != nil {
+		return "", nil, fmt.Errorf("mail: invalid sender %q: %w", msg.From, err)
+	}
+	to := make([]string, len(msg.To))
+	for i, addr := range msg.To {
+		parsed, err := netmail.ParseAddress(addr)
+		if err != nil {
+			return "", nil, fmt.Errorf("mail: invalid recipient %q: %w", addr, err)
+		}
+		to[i] = parsed.Address
+	}
+	return from.Address, to, nil
+}
@@ -0,0 +1,141 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/medflow/medflow-backend/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessage_Bytes(t *testing.T) {
+	msg := &Message{
+		From:    "MedFlow <noreply@praxis.de>",
+		To:      []string{"anna.mueller@praxis.de"},
+		Subject: "Temperaturabweichung Kühlschrank 1",
+		Text:    "Kühlschrank 1: 9,4 °C\nBitte prüfen.",
+	}
+
+	raw, err := msg.Bytes(time.Date(2026, 10, 16, 19, 42, 0, 0, time.UTC))
+	require.NoError(t, err)
+	s := string(raw)
+
+	assert.Contains(t, s, "From: \"MedFlow\" <noreply@praxis.de>\r\n")
+	assert.Contains(t, s, "To: <anna.mueller@praxis.de>\r\n")
+	assert.Contains(t, s, "Subject: =?utf-8?q?Temperaturabweichung_K=C3=BChlschrank_1?=\r\n")
+	assert.Contains(t, s, "Date: Fri, 16 Oct 2026 19:42:00 +0000\r\n")
+	assert.Contains(t, s, "@praxis.de>\r\n")
+	assert.Contains(t, s, "\r\n\r\nK=C3=BChlschrank 1: 9,4 =C2=B0C\r\nBitte pr=C3=BCfen.")
+}
+
+func TestMessage_BytesRejectsHeaderInjection(t *testing.T) {
+	msg := &Message{
+		From:    "noreply@praxis.de",
+		To:      []string{"anna@praxis.de\r\nBcc: mallory@example.com"},
+		Subject: "Alarm",
+	}
+	_, err := msg.Bytes(time.Now())
+	assert.Error(t, err)
+
+	msg = &Message{From: "noreply@praxis.de", Subject: "Alarm"}
+	_, err = msg.Bytes(time.Now())
+	assert.ErrorIs(t, err, ErrNoRecipients)
+}
+
+// fakeSMTP accepts one session without TLS or auth and returns the
+// recipients and message data it received
+func fakeSMTP(t *testing.T) (string, <-chan []string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(s string) { conn.Write([]byte(s + "\r\n")) }
+
+		var lines []string
+		reply("220 fake ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.TrimRight(line, "\r\n")
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"):
+				reply("250-fake")
+				reply("250 8BITMIME")
+			case strings.HasPrefix(cmd, "MAIL FROM"), strings.HasPrefix(cmd, "RCPT TO"):
+				lines = append(lines, cmd)
+				reply("250 OK")
+			case cmd == "DATA":
+				reply("354 go ahead")
+				for {
+					data, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if data == ".\r\n" {
+						break
+					}
+					lines = append(lines, strings.TrimRight(data, "\r\n"))
+				}
+				reply("250 queued")
+			case cmd == "QUIT":
+				reply("221 bye")
+				received <- lines
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+	return ln.Addr().String(), received
+}
+
+func TestSMTP_Send(t *testing.T) {
+	addr, received := fakeSMTP(t)
+	host, port, err := net.SplitHostPort(addr)
+	require.NoError(t, err)
+	portNum, err := strconv.Atoi(port)
+	require.NoError(t, err)
+
+	transport, err := New(&config.MailConfig{
+		Transport: "smtp",
+		Timeout:   5 * time.Second,
+		SMTP:      config.SMTPConfig{Host: host, Port: portNum},
+	}, nil)
+	require.NoError(t, err)
+
+	err = transport.Send(context.Background(), &Message{
+		From:    "noreply@praxis.de",
+		To:      []string{"Anna Müller <anna.mueller@praxis.de>", "bereitschaft@praxis.de"},
+		Subject: "Alarm",
+		Text:    "Kühlschrank 1 zu warm",
+	})
+	require.NoError(t, err)
+
+	lines := <-received
+	assert.Equal(t, "MAIL FROM:<noreply@praxis.de> BODY=8BITMIME", lines[0])
+	assert.Equal(t, "RCPT TO:<anna.mueller@praxis.de>", lines[1])
+	assert.Equal(t, "RCPT TO:<bereitschaft@praxis.de>", lines[2])
+	assert.Contains(t, lines, "Subject: Alarm")
+	assert.Contains(t, lines, "K=C3=BChlschrank 1 zu warm")
+}
+
+func TestNew_UnknownTransport(t *testing.T) {
+	_, err := New(&config.MailConfig{Transport: "sendmail"}, nil)
+	assert.Error(t, err)
+}
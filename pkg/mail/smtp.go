@@ -0,0 +1,98 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"github.com/medflow/medflow-backend/pkg/config"
+)
+
+// SMTP sends through an SMTP relay. Port 465 uses implicit TLS; other ports
+// upgrade with STARTTLS unless it is disabled.
+type SMTP struct {
+	cfg     config.SMTPConfig
+	timeout time.Duration
+}
+
+// NewSMTP creates an SMTP transport
+func NewSMTP(cfg *config.SMTPConfig, timeout time.Duration) *SMTP {
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	return &SMTP{cfg: *cfg, timeout: timeout}
+}
+
+// Name implements Transport
+func (s *SMTP) Name() string { return "smtp" }
+
+// Send implements Transport
+func (s *SMTP) Send(ctx context.Context, msg *Message) error {
+	from, to, err := msg.envelope()
+	if err != nil {
+		return err
+	}
+	body, err := msg.Bytes(time.Now())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	addr := net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port))
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("mail: connect to %s: %w", addr, err)
+	}
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+
+	tlsConfig := &tls.Config{ServerName: s.cfg.Host, MinVersion: tls.VersionTLS12}
+	if s.cfg.Port == 465 {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	client, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("mail: smtp handshake: %w", err)
+	}
+	defer client.Close()
+
+	if s.cfg.Port != 465 && s.cfg.StartTLS {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("mail: starttls: %w", err)
+		}
+	}
+	if s.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)); err != nil {
+			return fmt.Errorf("mail: smtp auth: %w", err)
+		}
+	}
+
+	if err := client.Mail(from); err != nil {
+		return fmt.Errorf("mail: MAIL FROM: %w", err)
+	}
+	for _, rcpt := range to {
+		if err := client.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("mail: RCPT TO %s: %w", rcpt, err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("mail: DATA: %w", err)
+	}
+	if _, err := w.Write(body); err != nil {
+		return fmt.Errorf("mail: write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("mail: message rejected: %w", err)
+	}
+	return client.Quit()
+}
@@ -7,6 +7,7 @@ import (
 
 	amqp "github.com/rabbitmq/amqp091-go"
 	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/medflow/medflow-backend/pkg/tenant"
 )
 
 // MessageHandler is a function that handles a message
@@ -107,8 +108,11 @@ func (c *Consumer) handleMessage(ctx context.Context, msg amqp.Delivery) {
 		return
 	}
 
-	// Add correlation ID to context
+	// Add correlation ID and the publishing tenant to context
 	ctx = WithCorrelationID(ctx, event.CorrelationID)
+	if event.TenantID != "" {
+		ctx = tenant.WithTenantContext(ctx, event.TenantID, event.TenantSlug)
+	}
 
 	handler, ok := c.handlers[event.Type]
 	if !ok {
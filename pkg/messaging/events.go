@@ -41,9 +41,19 @@ const (
 	EventTimeBreakEnd   = "staff.time.break_end"
 
 	// Inventory events
-	EventStockAdjusted   = "inventory.stock.adjusted"
-	EventBatchExpiring   = "inventory.batch.expiring"
-	EventAlertGenerated  = "inventory.alert.generated"
+	EventStockAdjusted     = "inventory.stock.adjusted"
+	EventStockTransferred  = "inventory.stock.transferred"
+	EventBatchExpiring     = "inventory.batch.expiring"
+	EventAlertGenerated    = "inventory.alert.generated"
+	EventAlertAcknowledged = "inventory.alert.acknowledged"
+
+	// Compliance events
+	EventComplianceAlertCreated   = "staff.compliance.alert.created"
+	EventComplianceAlertDismissed = "staff.compliance.alert.dismissed"
+
+	// Time correction request events
+	EventCorrectionApproved = "staff.correction.approved"
+	EventCorrectionRejected = "staff.correction.rejected"
 
 	// Audit events
 	EventAuditLogCreated = "audit.log.created"
@@ -57,13 +67,17 @@ const (
 	ExchangeAuditEvents     = "audit.events"
 )
 
-// Event is the base event structure
+// Event is the base event structure. TenantID and TenantSlug are set by the
+// Publisher from the tenant of the publishing request; consumers run the
+// handler in that tenant's context.
 type Event struct {
 	ID            string          `json:"id"`
 	Type          string          `json:"type"`
 	Source        string          `json:"source"`
 	Timestamp     time.Time       `json:"timestamp"`
 	CorrelationID string          `json:"correlation_id"`
+	TenantID      string          `json:"tenant_id,omitempty"`
+	TenantSlug    string          `json:"tenant_slug,omitempty"`
 	Data          json.RawMessage `json:"data"`
 }
 
@@ -236,15 +250,25 @@ type AbsenceUpdatedEvent struct {
 
 // AbsenceApprovedEvent is published when an absence is approved
 type AbsenceApprovedEvent struct {
-	AbsenceID  string `json:"absence_id"`
-	ReviewerID string `json:"reviewer_id"`
+	AbsenceID   string    `json:"absence_id"`
+	EmployeeID  string    `json:"employee_id"`
+	UserID      string    `json:"user_id,omitempty"` // the employee's user account, if any
+	AbsenceType string    `json:"absence_type"`
+	StartDate   time.Time `json:"start_date"`
+	EndDate     time.Time `json:"end_date"`
+	ReviewerID  string    `json:"reviewer_id"`
 }
 
 // AbsenceRejectedEvent is published when an absence is rejected
 type AbsenceRejectedEvent struct {
-	AbsenceID  string `json:"absence_id"`
-	ReviewerID string `json:"reviewer_id"`
-	Reason     string `json:"reason"`
+	AbsenceID   string    `json:"absence_id"`
+	EmployeeID  string    `json:"employee_id"`
+	UserID      string    `json:"user_id,omitempty"` // the employee's user account, if any
+	AbsenceType string    `json:"absence_type"`
+	StartDate   time.Time `json:"start_date"`
+	EndDate     time.Time `json:"end_date"`
+	ReviewerID  string    `json:"reviewer_id"`
+	Reason      string    `json:"reason"`
 }
 
 // AbsenceDeletedEvent is published when an absence is deleted
@@ -303,6 +327,19 @@ type StockAdjustedEvent struct {
 	Reason      string `json:"reason"`
 }
 
+// StockTransferredEvent is published when stock moves between locations
+type StockTransferredEvent struct {
+	TransferID     string `json:"transfer_id"`
+	ItemID         string `json:"item_id"`
+	FromBatchID    string `json:"from_batch_id"`
+	ToBatchID      string `json:"to_batch_id"`
+	FromLocationID string `json:"from_location_id,omitempty"`
+	ToLocationID   string `json:"to_location_id"`
+	Quantity       int    `json:"quantity"`
+	Split          bool   `json:"split"`
+	PerformedBy    string `json:"performed_by"`
+}
+
 // BatchExpiringEvent is published when a batch is nearing expiry
 type BatchExpiringEvent struct {
 	ItemID     string    `json:"item_id"`
@@ -324,6 +361,46 @@ type AlertGeneratedEvent struct {
 	BatchID   string `json:"batch_id,omitempty"`
 }
 
+// AlertAcknowledgedEvent is published when an alert is acknowledged
+type AlertAcknowledgedEvent struct {
+	AlertID        string `json:"alert_id"`
+	AcknowledgedBy string `json:"acknowledged_by"`
+}
+
+// Compliance Events
+
+// ComplianceAlertCreatedEvent is published when the compliance check raises
+// an alert. Alerts of a still-open violation are re-created on every check;
+// TimeEntryID and AlertType identify the violation across them.
+type ComplianceAlertCreatedEvent struct {
+	AlertID     string `json:"alert_id"`
+	EmployeeID  string `json:"employee_id"`
+	UserID      string `json:"user_id,omitempty"` // the employee's user account, if any
+	TimeEntryID string `json:"time_entry_id"`
+	AlertType   string `json:"alert_type"`
+	Severity    string `json:"severity"`
+	Message     string `json:"message"`
+}
+
+// ComplianceAlertDismissedEvent is published when a compliance alert is dismissed
+type ComplianceAlertDismissedEvent struct {
+	AlertID     string `json:"alert_id"`
+	DismissedBy string `json:"dismissed_by"`
+}
+
+// CorrectionDecidedEvent is published when a time correction request is
+// approved or rejected
+type CorrectionDecidedEvent struct {
+	RequestID     string    `json:"request_id"`
+	EmployeeID    string    `json:"employee_id"`
+	UserID        string    `json:"user_id,omitempty"` // the employee's user account, if any
+	TimeEntryID   string    `json:"time_entry_id,omitempty"`
+	RequestType   string    `json:"request_type"`
+	RequestedDate time.Time `json:"requested_date"`
+	ReviewerID    string    `json:"reviewer_id"`
+	Reason        string    `json:"reason,omitempty"` // rejection reason
+}
+
 // Audit Events
 
 // AuditLogCreatedEvent is published when an audit log entry is created
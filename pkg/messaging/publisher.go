@@ -7,6 +7,7 @@ import (
 
 	amqp "github.com/rabbitmq/amqp091-go"
 	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/medflow/medflow-backend/pkg/tenant"
 )
 
 // Publisher handles publishing events to RabbitMQ
@@ -40,6 +41,10 @@ func (p *Publisher) Publish(ctx context.Context, eventType string, data interfac
 	if err != nil {
 		return fmt.Errorf("failed to create event: %w", err)
 	}
+	if tenantID, err := tenant.TenantID(ctx); err == nil {
+		event.TenantID = tenantID
+		event.TenantSlug, _ = tenant.TenantSlug(ctx)
+	}
 
 	body, err := json.Marshal(event)
 	if err != nil {
@@ -0,0 +1,122 @@
+package retention
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/medflow/medflow-backend/pkg/tenant"
+)
+
+// policy is the part of a retention policy the guard and the purger need
+type policy struct {
+	EntityType     string  `db:"entity_type"`
+	RetentionYears int     `db:"retention_years"`
+	LegalBasis     *string `db:"legal_basis"`
+	PurgeAction    string  `db:"purge_action"`
+}
+
+func (p *policy) period() Period {
+	period := Period{EntityType: p.EntityType, Years: p.RetentionYears}
+	if p.LegalBasis != nil {
+		period.LegalBasis = *p.LegalBasis
+	}
+	return period
+}
+
+// getPolicy returns the policy of an entity type, or nil, using the
+// transaction in ctx
+func getPolicy(ctx context.Context, db *database.DB, entityType string) (*policy, error) {
+	var p policy
+	err := db.GetContext(ctx, &p, `
+		SELECT entity_type, retention_years, legal_basis, purge_action
+		FROM public.retention_policies
+		WHERE entity_type = $1 AND deleted_at IS NULL
+	`, entityType)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Guard rejects deletes of records under retention or legal hold
+type Guard struct {
+	db     *database.DB
+	logger *logger.Logger
+}
+
+// NewGuard creates a new retention guard
+func NewGuard(db *database.DB, log *logger.Logger) *Guard {
+	return &Guard{db: db, logger: log}
+}
+
+// Check returns a 409 error if the record id may not be deleted: it is
+// under a legal hold, or the retention period of one of the records it is
+// (records whose filter it does not match are skipped) has not ended.
+// A nil guard allows every delete.
+// TENANT-ISOLATED: Queries via RLS
+func (g *Guard) Check(ctx context.Context, id string, records ...Record) error {
+	if g == nil {
+		return nil
+	}
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return g.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+		for _, rec := range records {
+			if err := g.check(ctx, id, rec); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (g *Guard) check(ctx context.Context, id string, rec Record) error {
+	var row struct {
+		Date sql.NullTime   `db:"record_date"`
+		Hold sql.NullString `db:"hold_reference"`
+	}
+	query := fmt.Sprintf(`
+		SELECT (%s)::timestamptz AS record_date,
+		       (SELECT COALESCE(h.reference, h.reason) FROM public.legal_holds h
+		        WHERE h.entity_type = $2 AND h.released_at IS NULL
+		          AND (h.entity_id IS NULL OR h.entity_id = t.%s)
+		        ORDER BY h.created_at LIMIT 1) AS hold_reference
+		FROM %s t WHERE t.%s = $1%s
+		LIMIT 1
+	`, rec.DateColumn, rec.idColumn(), rec.Table, rec.idColumn(), rec.where())
+	err := g.db.GetContext(ctx, &row, query, id, rec.EntityType)
+	if err == sql.ErrNoRows {
+		// Not a record of this kind, or already gone: the delete decides
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("retention check of %s: %w", rec.Table, err)
+	}
+
+	if row.Hold.Valid {
+		return errors.Conflict("errors.retention.legal_hold", map[string]string{
+			"entity_type": rec.EntityType,
+			"reference":   row.Hold.String,
+		})
+	}
+
+	if !row.Date.Valid {
+		return nil
+	}
+	p, err := getPolicy(ctx, g.db, rec.EntityType)
+	if err != nil || p == nil {
+		return err
+	}
+	return p.period().Check(row.Date.Time, time.Now())
+}
@@ -0,0 +1,157 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/medflow/medflow-backend/pkg/actor"
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/logger"
+	"github.com/medflow/medflow-backend/pkg/tenant"
+)
+
+// Protocol is an entry of the deletion protocol (Löschprotokoll): the
+// records of one table a purge run deleted or anonymized
+type Protocol struct {
+	ID             string         `db:"id" json:"id"`
+	EntityType     string         `db:"entity_type" json:"entity_type"`
+	TableName      string         `db:"table_name" json:"table_name"`
+	Action         string         `db:"action" json:"action"`
+	LegalBasis     *string        `db:"legal_basis" json:"legal_basis,omitempty"`
+	RetentionYears int            `db:"retention_years" json:"retention_years"`
+	CutoffDate     time.Time      `db:"cutoff_date" json:"cutoff_date"`
+	RecordCount    int            `db:"record_count" json:"record_count"`
+	RecordIDs      pq.StringArray `db:"record_ids" json:"record_ids"`
+	ExecutedBy     *string        `db:"executed_by" json:"executed_by,omitempty"`
+	ExecutedByName *string        `db:"executed_by_name" json:"executed_by_name,omitempty"`
+	ExecutedAt     time.Time      `db:"executed_at" json:"executed_at"`
+}
+
+// Purger deletes or anonymizes records whose retention period has ended
+type Purger struct {
+	db      *database.DB
+	records []Record
+	logger  *logger.Logger
+	now     func() time.Time
+}
+
+// NewPurger creates a purger for the given records. Records whose rows own
+// files in object storage do not belong here; deleting the row would leave
+// the file behind.
+func NewPurger(db *database.DB, log *logger.Logger, records ...Record) *Purger {
+	return &Purger{db: db, records: records, logger: log, now: time.Now}
+}
+
+// Run purges the records of the tenant in ctx as their policies' purge
+// actions say, skipping records under legal hold, and protocols each table
+// it changed. It is a per-tenant job function; a failing table does not stop
+// the others.
+// TENANT-ISOLATED: Deletes and updates via RLS
+func (p *Purger) Run(ctx context.Context) error {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, rec := range p.records {
+		var protocol *Protocol
+		err := p.db.WithTenantRLS(ctx, tenantID, func(ctx context.Context) error {
+			var err error
+			protocol, err = p.purge(ctx, tenantID, rec)
+			return err
+		})
+		if err != nil {
+			p.logger.Error().Err(err).Str("tenant_id", tenantID).Str("table", rec.Table).Msg("retention purge failed")
+			errs = append(errs, fmt.Errorf("%s: %w", rec.Table, err))
+			continue
+		}
+		if protocol != nil {
+			p.logger.Info().
+				Str("tenant_id", tenantID).
+				Str("entity_type", rec.EntityType).
+				Str("table", rec.Table).
+				Str("action", protocol.Action).
+				Int("records", protocol.RecordCount).
+				Msg("retention purge")
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// purge purges one table using the transaction in ctx; it returns the
+// protocol entry, or nil if nothing was due
+func (p *Purger) purge(ctx context.Context, tenantID string, rec Record) (*Protocol, error) {
+	pol, err := getPolicy(ctx, p.db, rec.EntityType)
+	if err != nil || pol == nil || pol.PurgeAction == ActionNone {
+		return nil, err
+	}
+
+	action := pol.PurgeAction
+	if rec.AnonymizeOnly {
+		action = ActionAnonymize
+	}
+	if action == ActionAnonymize && rec.Anonymize == "" {
+		// Nothing personal to remove; the record stays until deleted
+		return nil, nil
+	}
+
+	period := pol.period()
+	cutoff := period.Cutoff(p.now())
+	where := fmt.Sprintf(`(%s) < $1 AND NOT EXISTS (
+			SELECT 1 FROM public.legal_holds h
+			WHERE h.entity_type = $2 AND h.released_at IS NULL
+			  AND (h.entity_id IS NULL OR h.entity_id = t.%s)
+		)%s`, rec.DateColumn, rec.idColumn(), rec.where())
+
+	var query string
+	if action == ActionDelete {
+		query = fmt.Sprintf(`DELETE FROM %s t WHERE %s RETURNING t.id`, rec.Table, where)
+	} else {
+		query = fmt.Sprintf(`UPDATE %s t SET %s, anonymized_at = NOW() WHERE %s AND t.anonymized_at IS NULL RETURNING t.id`,
+			rec.Table, rec.Anonymize, where)
+	}
+
+	var ids []string
+	if err := p.db.SelectContext(ctx, &ids, query, cutoff, rec.EntityType); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	protocol := &Protocol{
+		ID:             uuid.New().String(),
+		EntityType:     rec.EntityType,
+		TableName:      rec.Table,
+		Action:         action,
+		LegalBasis:     pol.LegalBasis,
+		RetentionYears: pol.RetentionYears,
+		CutoffDate:     cutoff,
+		RecordCount:    len(ids),
+		RecordIDs:      ids,
+	}
+	if a := actor.FromContext(ctx); a != nil {
+		name := a.String()
+		protocol.ExecutedBy, protocol.ExecutedByName = &a.ID, &name
+	}
+
+	err = p.db.QueryRowxContext(ctx, `
+		INSERT INTO public.deletion_protocols (
+			id, tenant_id, entity_type, table_name, action, legal_basis, retention_years,
+			cutoff_date, record_count, record_ids, executed_by, executed_by_name
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING executed_at
+	`, protocol.ID, tenantID, protocol.EntityType, protocol.TableName, protocol.Action, protocol.LegalBasis,
+		protocol.RetentionYears, protocol.CutoffDate, protocol.RecordCount, protocol.RecordIDs,
+		protocol.ExecutedBy, protocol.ExecutedByName,
+	).Scan(&protocol.ExecutedAt)
+	if err != nil {
+		return nil, err
+	}
+	return protocol, nil
+}
@@ -0,0 +1,116 @@
+// Package retention enforces the statutory retention periods configured per
+// tenant in public.retention_policies, shared by all MedFlow services.
+//
+// A Guard rejects deleting a record while its retention period runs or while
+// it is under a legal hold. A Purger runs as a per-tenant job and deletes or
+// anonymizes records whose period has ended, as the policy's purge action
+// says, and writes a deletion protocol entry (public.deletion_protocols)
+// for each batch of records it removed.
+//
+// Usage:
+//
+//	guard := retention.NewGuard(db, log)
+//	if err := guard.Check(ctx, id, service.RetentionDeviceIncident); err != nil {
+//	    return err // 409 naming the legal basis
+//	}
+//
+//	purger := retention.NewPurger(db, log, service.RetentionPurgeRecords...)
+//	scheduler.MustRegister(&jobs.Job{Name: "inventory.retention_purge", PerTenant: true, Run: purger.Run})
+package retention
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/medflow/medflow-backend/pkg/errors"
+)
+
+// Purge actions of a retention policy
+const (
+	// ActionNone keeps records after the retention period
+	ActionNone = "none"
+	// ActionDelete deletes records after the retention period
+	ActionDelete = "delete"
+	// ActionAnonymize removes personal data from records after the retention period
+	ActionAnonymize = "anonymize"
+)
+
+// ValidAction reports whether a is a purge action
+func ValidAction(a string) bool {
+	return a == ActionNone || a == ActionDelete || a == ActionAnonymize
+}
+
+// Record describes a kind of record that is subject to the retention policy
+// of an entity type. Table, DateColumn, IDColumn, Filter and Anonymize are
+// SQL fragments from code, never from input; they may refer to the table as t.
+type Record struct {
+	// EntityType is the retention_policies.entity_type, e.g. "device_book"
+	EntityType string
+	// Table is the schema-qualified table, e.g. "inventory.device_incidents"
+	Table string
+	// DateColumn is the date the retention period counts from. Records with
+	// a NULL date (e.g. employees still employed) are not subject to it.
+	DateColumn string
+	// IDColumn identifies a record in Guard.Check; defaults to "id"
+	IDColumn string
+	// Filter restricts the records of the table the policy applies to,
+	// e.g. batches of controlled substances only
+	Filter string
+	// Anonymize is the SET clause that removes personal data; empty if the
+	// record holds none. Anonymized records get anonymized_at set.
+	Anonymize string
+	// AnonymizeOnly anonymizes even when the policy says delete, for rows
+	// that other records with their own retention still reference
+	AnonymizeOnly bool
+}
+
+func (r Record) idColumn() string {
+	if r.IDColumn == "" {
+		return "id"
+	}
+	return r.IDColumn
+}
+
+// where returns the record filter as an AND clause, or ""
+func (r Record) where() string {
+	if r.Filter == "" {
+		return ""
+	}
+	return " AND (" + r.Filter + ")"
+}
+
+// Period is the retention period of an entity type
+type Period struct {
+	EntityType string
+	Years      int
+	LegalBasis string
+}
+
+// Until returns the end of the retention period of a record dated recordDate
+func (p Period) Until(recordDate time.Time) time.Time {
+	return recordDate.AddDate(p.Years, 0, 0)
+}
+
+// Check returns a conflict error naming the legal basis while the retention
+// period of a record dated recordDate has not ended at now
+func (p Period) Check(recordDate, now time.Time) error {
+	until := p.Until(recordDate)
+	if !now.Before(until) {
+		return nil
+	}
+	legalBasis := p.LegalBasis
+	if legalBasis == "" {
+		legalBasis = "-"
+	}
+	return errors.Conflict("errors.retention.period_active", map[string]string{
+		"entity_type": p.EntityType,
+		"years":       strconv.Itoa(p.Years),
+		"legal_basis": legalBasis,
+		"until":       until.Format("2006-01-02"),
+	})
+}
+
+// Cutoff returns the date before which records are past the period at now
+func (p Period) Cutoff(now time.Time) time.Time {
+	return now.AddDate(-p.Years, 0, 0)
+}
@@ -0,0 +1,73 @@
+package retention
+
+import (
+	"testing"
+	"time"
+
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeriod_Check(t *testing.T) {
+	p := Period{EntityType: "device_book", Years: 20, LegalBasis: "MPBetreibV §12"}
+	recordDate := time.Date(2010, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	err := p.Check(recordDate, time.Date(2030, 3, 14, 0, 0, 0, 0, time.UTC))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, errors.ErrConflict))
+
+	var appErr *errors.AppError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, "errors.retention.period_active", appErr.MessageKey)
+	assert.Equal(t, map[string]string{
+		"entity_type": "device_book",
+		"years":       "20",
+		"legal_basis": "MPBetreibV §12",
+		"until":       "2030-03-15",
+	}, appErr.Params)
+
+	// The period ends on the anniversary
+	assert.NoError(t, p.Check(recordDate, time.Date(2030, 3, 15, 0, 0, 0, 0, time.UTC)))
+	assert.NoError(t, p.Check(recordDate, time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestPeriod_CheckWithoutLegalBasis(t *testing.T) {
+	p := Period{EntityType: "absence", Years: 1}
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	var appErr *errors.AppError
+	require.True(t, errors.As(p.Check(now.AddDate(0, -1, 0), now), &appErr))
+	assert.Equal(t, "-", appErr.Params["legal_basis"])
+}
+
+func TestPeriod_Cutoff(t *testing.T) {
+	p := Period{EntityType: "working_time", Years: 2}
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	cutoff := p.Cutoff(now)
+	assert.Equal(t, time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC), cutoff)
+
+	// Records dated before the cutoff are past their period
+	assert.NoError(t, p.Check(cutoff.AddDate(0, 0, -1), now))
+	assert.Error(t, p.Check(cutoff.AddDate(0, 0, 1), now))
+}
+
+func TestValidAction(t *testing.T) {
+	for _, a := range []string{ActionNone, ActionDelete, ActionAnonymize} {
+		assert.True(t, ValidAction(a), a)
+	}
+	assert.False(t, ValidAction(""))
+	assert.False(t, ValidAction("archive"))
+}
+
+func TestRecord_Defaults(t *testing.T) {
+	r := Record{EntityType: "device_book", Table: "inventory.device_inspections", DateColumn: "t.inspection_date"}
+	assert.Equal(t, "id", r.idColumn())
+	assert.Equal(t, "", r.where())
+
+	r.IDColumn = "item_id"
+	r.Filter = "t.deleted_at IS NULL"
+	assert.Equal(t, "item_id", r.idColumn())
+	assert.Equal(t, " AND (t.deleted_at IS NULL)", r.where())
+}
@@ -0,0 +1,155 @@
+package scan
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// chunkSize is the INSTREAM chunk size; clamd's StreamMaxLength limits the total
+const chunkSize = 64 << 10
+
+// ClamAV scans through a clamd daemon using the INSTREAM command
+type ClamAV struct {
+	network string
+	address string
+	timeout time.Duration
+}
+
+// NewClamAV creates a clamd client. address is tcp://host:port or
+// unix:///path/to/clamd.sock.
+func NewClamAV(address string, timeout time.Duration) (*ClamAV, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("scan: invalid clamd address %q: %w", address, err)
+	}
+
+	c := &ClamAV{network: u.Scheme, timeout: timeout}
+	switch u.Scheme {
+	case "tcp":
+		c.address = u.Host
+	case "unix":
+		c.address = u.Path
+	default:
+		return nil, fmt.Errorf("scan: clamd address must be tcp:// or unix://, got %q", address)
+	}
+	if c.address == "" {
+		return nil, fmt.Errorf("scan: invalid clamd address %q", address)
+	}
+	if c.timeout <= 0 {
+		c.timeout = 60 * time.Second
+	}
+	return c, nil
+}
+
+// Name implements Scanner
+func (c *ClamAV) Name() string { return "clamav" }
+
+// Ping checks that clamd is reachable
+func (c *ClamAV) Ping(ctx context.Context) error {
+	reply, err := c.command(ctx, "zPING\x00", nil)
+	if err != nil {
+		return err
+	}
+	if reply != "PONG" {
+		return fmt.Errorf("scan: unexpected clamd reply %q", reply)
+	}
+	return nil
+}
+
+// Scan streams r to clamd and parses the verdict
+func (c *ClamAV) Scan(ctx context.Context, r io.Reader) (*Result, error) {
+	reply, err := c.command(ctx, "zINSTREAM\x00", r)
+	if err != nil {
+		return nil, err
+	}
+	return parseReply(reply)
+}
+
+// command sends a null-terminated command, optionally followed by a chunked
+// stream, and returns clamd's reply
+func (c *ClamAV) command(ctx context.Context, cmd string, stream io.Reader) (string, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, c.network, c.address)
+	if err != nil {
+		return "", fmt.Errorf("scan: connect to clamd: %w", err)
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(c.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+
+	w := bufio.NewWriterSize(conn, chunkSize+4)
+	if _, err := w.WriteString(cmd); err != nil {
+		return "", fmt.Errorf("scan: write to clamd: %w", err)
+	}
+	if stream != nil {
+		if err := writeChunks(w, stream); err != nil {
+			return "", err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return "", fmt.Errorf("scan: write to clamd: %w", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("scan: read clamd reply: %w", err)
+	}
+	return strings.TrimRight(reply, "\x00\n"), nil
+}
+
+// writeChunks writes r as length-prefixed chunks terminated by a zero length
+func writeChunks(w io.Writer, r io.Reader) error {
+	buf := make([]byte, chunkSize)
+	var size [4]byte
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size[:], uint32(n))
+			if _, werr := w.Write(size[:]); werr != nil {
+				return fmt.Errorf("scan: write to clamd: %w", werr)
+			}
+			if _, werr := w.Write(buf[:n]); werr != nil {
+				return fmt.Errorf("scan: write to clamd: %w", werr)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("scan: read upload: %w", err)
+		}
+	}
+	_, err := w.Write([]byte{0, 0, 0, 0})
+	return err
+}
+
+// parseReply interprets "stream: OK", "stream: <signature> FOUND" and
+// "<message> ERROR" replies
+func parseReply(reply string) (*Result, error) {
+	msg := reply
+	if i := strings.Index(reply, ": "); i >= 0 {
+		msg = reply[i+2:]
+	}
+
+	switch {
+	case msg == "OK":
+		return &Result{Clean: true}, nil
+	case strings.HasSuffix(msg, " FOUND"):
+		return &Result{Signature: strings.TrimSuffix(msg, " FOUND")}, nil
+	case strings.HasSuffix(msg, " ERROR"):
+		return nil, fmt.Errorf("scan: clamd: %s", msg)
+	default:
+		return nil, fmt.Errorf("scan: unexpected clamd reply %q", reply)
+	}
+}
@@ -0,0 +1,92 @@
+// Package scan checks uploaded files for malware.
+//
+// Uploads are scanned before they are stored. Records keep a scan status and
+// downloads are refused until it is StatusClean; uploads that could not be
+// scanned (scanner unreachable) stay StatusPending until a background job
+// rescans them.
+package scan
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/medflow/medflow-backend/pkg/config"
+	"github.com/medflow/medflow-backend/pkg/errors"
+)
+
+// Scan status values stored alongside documents
+const (
+	// StatusPending means the file has not been scanned yet
+	StatusPending = "pending"
+	// StatusClean means the scanner found nothing
+	StatusClean = "clean"
+	// StatusInfected means the scanner found malware; the content is deleted
+	StatusInfected = "infected"
+	// StatusError means the stored content could not be read for scanning
+	StatusError = "error"
+)
+
+// Result is the outcome of a scan
+type Result struct {
+	Clean bool
+	// Signature names the detected malware, e.g. "Eicar-Test-Signature"
+	Signature string
+}
+
+// Status returns the scan status for the result
+func (r *Result) Status() string {
+	if r.Clean {
+		return StatusClean
+	}
+	return StatusInfected
+}
+
+// Scanner checks content for malware
+type Scanner interface {
+	// Scan reads r to the end and reports whether it is clean. An error means
+	// the content could not be checked, not that it is infected.
+	Scan(ctx context.Context, r io.Reader) (*Result, error)
+	// Name identifies the scanner in logs
+	Name() string
+}
+
+// New creates the scanner selected by cfg.Backend
+func New(cfg *config.ScanConfig) (Scanner, error) {
+	switch cfg.Backend {
+	case "none":
+		return Noop{}, nil
+	case "clamav":
+		return NewClamAV(cfg.ClamAVAddress, cfg.Timeout)
+	default:
+		return nil, fmt.Errorf("scan: unknown backend %q", cfg.Backend)
+	}
+}
+
+// Noop reports every file as clean. It is meant for development and tests,
+// where no scanner daemon runs.
+type Noop struct{}
+
+// Scan drains r and reports it clean
+func (Noop) Scan(ctx context.Context, r io.Reader) (*Result, error) {
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		return nil, err
+	}
+	return &Result{Clean: true}, nil
+}
+
+// Name implements Scanner
+func (Noop) Name() string { return "none" }
+
+// CheckDownload returns an error unless a file with the given scan status
+// may be downloaded. Only clean files are served.
+func CheckDownload(status string) error {
+	switch status {
+	case StatusClean:
+		return nil
+	case StatusPending:
+		return errors.Conflict("errors.file.scan_pending")
+	default:
+		return errors.Forbidden("errors.file.quarantined")
+	}
+}
@@ -0,0 +1,122 @@
+package scan
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClamd accepts INSTREAM sessions and reports streams containing "EICAR"
+func fakeClamd(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveClamd(conn)
+		}
+	}()
+	return "tcp://" + ln.Addr().String()
+}
+
+func serveClamd(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	cmd, err := r.ReadString(0)
+	if err != nil {
+		return
+	}
+
+	switch cmd {
+	case "zPING\x00":
+		conn.Write([]byte("PONG\x00"))
+	case "zINSTREAM\x00":
+		var data bytes.Buffer
+		for {
+			var size uint32
+			if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+				return
+			}
+			if size == 0 {
+				break
+			}
+			io.CopyN(&data, r, int64(size))
+		}
+		if bytes.Contains(data.Bytes(), []byte("EICAR")) {
+			conn.Write([]byte("stream: Eicar-Test-Signature FOUND\x00"))
+		} else {
+			conn.Write([]byte("stream: OK\x00"))
+		}
+	default:
+		conn.Write([]byte("UNKNOWN COMMAND\x00"))
+	}
+}
+
+func TestClamAV_Scan(t *testing.T) {
+	c, err := NewClamAV(fakeClamd(t), 5*time.Second)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, c.Ping(ctx))
+
+	// Larger than one chunk, so the stream is split
+	clean := strings.Repeat("harmless ", chunkSize/4)
+	result, err := c.Scan(ctx, strings.NewReader(clean))
+	require.NoError(t, err)
+	assert.True(t, result.Clean)
+	assert.Equal(t, StatusClean, result.Status())
+
+	result, err = c.Scan(ctx, strings.NewReader(clean+"EICAR"))
+	require.NoError(t, err)
+	assert.False(t, result.Clean)
+	assert.Equal(t, "Eicar-Test-Signature", result.Signature)
+	assert.Equal(t, StatusInfected, result.Status())
+}
+
+func TestClamAV_Unreachable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	ln.Close()
+
+	c, err := NewClamAV("tcp://"+addr, time.Second)
+	require.NoError(t, err)
+	_, err = c.Scan(context.Background(), strings.NewReader("x"))
+	assert.Error(t, err, "an unreachable daemon is an error, never a clean verdict")
+}
+
+func TestNewClamAV_Address(t *testing.T) {
+	_, err := NewClamAV("unix:///var/run/clamav/clamd.ctl", 0)
+	assert.NoError(t, err)
+	_, err = NewClamAV("localhost:3310", 0)
+	assert.Error(t, err)
+}
+
+func TestParseReply(t *testing.T) {
+	_, err := parseReply("INSTREAM size limit exceeded. ERROR")
+	assert.Error(t, err)
+	_, err = parseReply("")
+	assert.Error(t, err)
+}
+
+func TestCheckDownload(t *testing.T) {
+	assert.NoError(t, CheckDownload(StatusClean))
+	assert.ErrorIs(t, CheckDownload(StatusPending), errors.ErrConflict)
+	assert.ErrorIs(t, CheckDownload(StatusInfected), errors.ErrForbidden)
+	assert.ErrorIs(t, CheckDownload(StatusError), errors.ErrForbidden)
+}
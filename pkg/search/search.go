@@ -0,0 +1,187 @@
+// Package search provides the shared pieces of the tenant-scoped search
+// endpoints: query parsing, German-aware text normalization, the ranked
+// result type every service returns and merging of results by type.
+//
+// Matching happens in Postgres (see migration 000028_search):
+//   - public.search_normalize() lowercases and folds ä/ö/ü/ß to ae/oe/ue/ss,
+//     so "Müller", "Mueller" and "MÜLLER" are the same word
+//   - pg_trgm word similarity ("<%") gives typo tolerance on short fields
+//   - German full-text search covers long text such as hygiene plan content
+package search
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/medflow/medflow-backend/pkg/database"
+	"github.com/medflow/medflow-backend/pkg/errors"
+)
+
+// Result types
+const (
+	TypeItem        = "item"
+	TypeHygienePlan = "hygiene_plan"
+	TypeEmployee    = "employee"
+)
+
+// Query limits
+const (
+	MinQueryLength = 2
+	MaxQueryLength = 100
+	DefaultLimit   = 10
+	MaxLimit       = 50
+)
+
+// WordSimilarityThreshold is the minimum pg_trgm word similarity for a fuzzy
+// match. Lower than the pg_trgm default (0.6) so one typo in a short word still matches.
+const WordSimilarityThreshold = 0.4
+
+// Result is one ranked search hit
+type Result struct {
+	Type     string  `db:"-" json:"type"`
+	ID       string  `db:"id" json:"id"`
+	Title    string  `db:"title" json:"title"`
+	Subtitle *string `db:"subtitle" json:"subtitle,omitempty"`
+	// Score is in [0, 1]; exact identifier matches (PZN, personnel number) score 1
+	Score float64 `db:"score" json:"score"`
+}
+
+// Group holds the results of one type, best first
+type Group struct {
+	Type    string    `json:"type"`
+	Results []*Result `json:"results"`
+}
+
+// Response is the body of the search endpoints
+type Response struct {
+	Query  string   `json:"query"`
+	Groups []*Group `json:"groups"`
+	// Unavailable lists result types whose service did not answer (gateway fan-out only)
+	Unavailable []string `json:"unavailable,omitempty"`
+}
+
+// Request is a parsed search request
+type Request struct {
+	Query string
+	Types []string
+	Limit int
+}
+
+// Wants reports whether results of type t were requested
+func (r *Request) Wants(t string) bool {
+	if len(r.Types) == 0 {
+		return true
+	}
+	for _, want := range r.Types {
+		if want == t {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseRequest parses ?q=...&types=item,employee&limit=10
+func ParseRequest(r *http.Request) (*Request, error) {
+	values := r.URL.Query()
+
+	req := &Request{
+		Query: strings.Join(strings.Fields(values.Get("q")), " "),
+		Limit: DefaultLimit,
+	}
+
+	length := len([]rune(req.Query))
+	if length < MinQueryLength {
+		return nil, errors.Validation(nil).WithDetail("q", "validation.min_length", map[string]string{
+			"field": "q",
+			"min":   strconv.Itoa(MinQueryLength),
+		})
+	}
+	if length > MaxQueryLength {
+		return nil, errors.Validation(nil).WithDetail("q", "validation.max_length", map[string]string{
+			"field": "q",
+			"max":   strconv.Itoa(MaxQueryLength),
+		})
+	}
+
+	if types := values.Get("types"); types != "" {
+		for _, t := range strings.Split(types, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				req.Types = append(req.Types, t)
+			}
+		}
+	}
+
+	if limit := values.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			return nil, errors.Validation(nil).WithDetail("limit", "validation.positive", map[string]string{"field": "limit"})
+		}
+		req.Limit = min(n, MaxLimit)
+	}
+
+	return req, nil
+}
+
+// umlauts folds German special characters the same way as public.search_normalize()
+var umlauts = strings.NewReplacer("ä", "ae", "ö", "oe", "ü", "ue", "ß", "ss")
+
+// Normalize lowercases s and folds umlauts and ß (ä→ae, ß→ss).
+// Must stay in sync with public.search_normalize() in the search migration.
+func Normalize(s string) string {
+	return umlauts.Replace(strings.ToLower(s))
+}
+
+// PrepareTx lowers the pg_trgm word similarity threshold for the current
+// transaction. Call inside WithTenantRLS before running search queries.
+func PrepareTx(ctx context.Context, db *database.DB) error {
+	_, err := db.ExecContext(ctx, fmt.Sprintf("SET LOCAL pg_trgm.word_similarity_threshold = %.2f", WordSimilarityThreshold))
+	return err
+}
+
+// Tag sets the type of every result
+func Tag(results []*Result, t string) []*Result {
+	for _, r := range results {
+		r.Type = t
+	}
+	return results
+}
+
+// Merge groups results by type, ranks each group by score and keeps at most
+// limit results per group. Groups are ordered by their best score.
+func Merge(limit int, results ...[]*Result) []*Group {
+	byType := make(map[string]*Group)
+	var groups []*Group
+	for _, set := range results {
+		for _, r := range set {
+			g, ok := byType[r.Type]
+			if !ok {
+				g = &Group{Type: r.Type}
+				byType[r.Type] = g
+				groups = append(groups, g)
+			}
+			g.Results = append(g.Results, r)
+		}
+	}
+
+	for _, g := range groups {
+		sort.SliceStable(g.Results, func(i, j int) bool {
+			return g.Results[i].Score > g.Results[j].Score
+		})
+		if limit > 0 && len(g.Results) > limit {
+			g.Results = g.Results[:limit]
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Results[0].Score > groups[j].Results[0].Score
+	})
+
+	if groups == nil {
+		groups = []*Group{}
+	}
+	return groups
+}
@@ -0,0 +1,64 @@
+package search
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"Müller":              "mueller",
+		"MÜLLER":              "mueller",
+		"Weiß":                "weiss",
+		"Flächendesinfektion": "flaechendesinfektion",
+		"Größe":               "groesse",
+		"PZN 01234567":        "pzn 01234567",
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, Normalize(in), in)
+	}
+}
+
+func TestParseRequest(t *testing.T) {
+	req, err := ParseRequest(httptest.NewRequest("GET", "/search?q=++Spritze++5ml+&types=item,+employee&limit=500", nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Spritze 5ml", req.Query)
+	assert.Equal(t, []string{"item", "employee"}, req.Types)
+	assert.Equal(t, MaxLimit, req.Limit)
+	assert.True(t, req.Wants(TypeEmployee))
+	assert.False(t, req.Wants(TypeHygienePlan))
+
+	_, err = ParseRequest(httptest.NewRequest("GET", "/search?q=a", nil))
+	assert.Error(t, err, "query too short")
+
+	_, err = ParseRequest(httptest.NewRequest("GET", "/search?q=ab&limit=x", nil))
+	assert.Error(t, err)
+}
+
+func TestMerge(t *testing.T) {
+	items := []*Result{
+		{Type: TypeItem, ID: "i1", Score: 0.5},
+		{Type: TypeItem, ID: "i2", Score: 0.7},
+		{Type: TypeItem, ID: "i3", Score: 0.6},
+	}
+	employees := []*Result{
+		{Type: TypeEmployee, ID: "e1", Score: 1},
+	}
+
+	groups := Merge(2, items, employees)
+	require.Len(t, groups, 2)
+
+	// Groups ordered by best score, results ranked and limited per group
+	assert.Equal(t, TypeEmployee, groups[0].Type)
+	assert.Equal(t, TypeItem, groups[1].Type)
+	require.Len(t, groups[1].Results, 2)
+	assert.Equal(t, "i2", groups[1].Results[0].ID)
+	assert.Equal(t, "i3", groups[1].Results[1].ID)
+
+	assert.Empty(t, Merge(10))
+	assert.NotNil(t, Merge(10), "empty result encodes as []")
+}
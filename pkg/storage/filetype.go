@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"regexp"
+	"strconv"
+
+	"github.com/medflow/medflow-backend/pkg/errors"
+)
+
+// Content types accepted for document uploads
+const (
+	TypePDF  = "application/pdf"
+	TypeJPEG = "image/jpeg"
+	TypePNG  = "image/png"
+	TypeGIF  = "image/gif"
+	TypeWebP = "image/webp"
+	TypeTIFF = "image/tiff"
+)
+
+// Common allow-lists for document types
+var (
+	// PDFOnly accepts PDF documents only (e.g. safety data sheets)
+	PDFOnly = []string{TypePDF}
+	// ScannedDocument accepts PDFs and the formats scanners and phones produce
+	ScannedDocument = []string{TypePDF, TypeJPEG, TypePNG, TypeTIFF}
+	// AnyDocument accepts every supported type
+	AnyDocument = []string{TypePDF, TypeJPEG, TypePNG, TypeGIF, TypeWebP, TypeTIFF}
+)
+
+// fileType is a magic-byte signature
+type fileType struct {
+	contentType string
+	ext         string
+	match       func(head []byte) bool
+}
+
+func prefix(magic string) func([]byte) bool {
+	return func(head []byte) bool { return bytes.HasPrefix(head, []byte(magic)) }
+}
+
+var fileTypes = []fileType{
+	{TypePDF, ".pdf", prefix("%PDF-")},
+	{TypePNG, ".png", prefix("\x89PNG\r\n\x1a\n")},
+	{TypeJPEG, ".jpg", prefix("\xff\xd8\xff")},
+	{TypeGIF, ".gif", func(h []byte) bool {
+		return bytes.HasPrefix(h, []byte("GIF87a")) || bytes.HasPrefix(h, []byte("GIF89a"))
+	}},
+	{TypeWebP, ".webp", func(h []byte) bool { return len(h) >= 12 && string(h[:4]) == "RIFF" && string(h[8:12]) == "WEBP" }},
+	{TypeTIFF, ".tif", func(h []byte) bool {
+		return bytes.HasPrefix(h, []byte("II*\x00")) || bytes.HasPrefix(h, []byte("MM\x00*"))
+	}},
+}
+
+// DetectType identifies content by its magic bytes. It only knows the
+// document types above; anything else, including text and HTML, is rejected.
+func DetectType(head []byte) (contentType, ext string, ok bool) {
+	for _, t := range fileTypes {
+		if t.match(head) {
+			return t.contentType, t.ext, true
+		}
+	}
+	return "", "", false
+}
+
+var (
+	// pdfName matches PDF name objects, which may hide characters as #xx
+	pdfName = regexp.MustCompile(`/[A-Za-z0-9#]+`)
+	// pdfStream matches stream bodies, whose dictionary decides the filter
+	pdfStream = regexp.MustCompile(`(?s)<<(.{0,512}?)>>\s*stream\r?\n`)
+	// pdfEndStream ends a stream body
+	pdfEndStream = []byte("endstream")
+)
+
+// maxInflate bounds how much a single compressed PDF stream may expand to
+// while being checked (guards against decompression bombs)
+const maxInflate = 16 << 20
+
+// CheckPDF rejects PDFs that carry JavaScript or launch actions. Names are
+// checked in the file and in Flate-compressed streams, where object streams
+// can hide dictionaries.
+func CheckPDF(content []byte) error {
+	if hasActiveContent(content) {
+		return errors.BadRequest("errors.file.pdf_active_content")
+	}
+
+	for _, loc := range pdfStream.FindAllSubmatchIndex(content, -1) {
+		dict := content[loc[2]:loc[3]]
+		if !bytes.Contains(dict, []byte("/FlateDecode")) {
+			continue
+		}
+		start := loc[1]
+		end := bytes.Index(content[start:], pdfEndStream)
+		if end < 0 {
+			break
+		}
+
+		zr, err := zlib.NewReader(bytes.NewReader(content[start : start+end]))
+		if err != nil {
+			continue // not inflatable, nothing a reader could execute either
+		}
+		inflated, _ := io.ReadAll(io.LimitReader(zr, maxInflate))
+		zr.Close()
+		if hasActiveContent(inflated) {
+			return errors.BadRequest("errors.file.pdf_active_content")
+		}
+	}
+	return nil
+}
+
+// hasActiveContent reports whether data names a JavaScript or Launch action
+func hasActiveContent(data []byte) bool {
+	for _, m := range pdfName.FindAll(data, -1) {
+		switch decodePDFName(m[1:]) {
+		case "JavaScript", "JS", "Launch":
+			return true
+		}
+	}
+	return false
+}
+
+// decodePDFName resolves #xx escapes, e.g. J#61vaScript -> JavaScript
+func decodePDFName(name []byte) string {
+	if !bytes.Contains(name, []byte("#")) {
+		return string(name)
+	}
+	var b bytes.Buffer
+	for i := 0; i < len(name); i++ {
+		if name[i] == '#' && i+2 < len(name) {
+			if v, err := strconv.ParseUint(string(name[i+1:i+3]), 16, 8); err == nil {
+				b.WriteByte(byte(v))
+				i += 2
+				continue
+			}
+		}
+		b.WriteByte(name[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,160 @@
+package storage
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	apperrors "github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/scan"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectType(t *testing.T) {
+	tests := []struct {
+		head string
+		want string
+	}{
+		{"%PDF-1.4\n", TypePDF},
+		{"\x89PNG\r\n\x1a\n\x00\x00", TypePNG},
+		{"\xff\xd8\xff\xe0\x00\x10JFIF", TypeJPEG},
+		{"GIF89a", TypeGIF},
+		{"RIFF\x24\x00\x00\x00WEBPVP8 ", TypeWebP},
+		{"II*\x00\x08\x00", TypeTIFF},
+		{"MZ\x90\x00", ""},              // Windows executable
+		{"PK\x03\x04", ""},              // ZIP / Office document
+		{"<!DOCTYPE html><script>", ""}, // HTML
+		{"  %PDF-1.4", ""},              // magic bytes must come first
+	}
+	for _, tt := range tests {
+		got, _, ok := DetectType([]byte(tt.head))
+		assert.Equal(t, tt.want, got, "%q", tt.head)
+		assert.Equal(t, tt.want != "", ok, "%q", tt.head)
+	}
+}
+
+func flate(s string) string {
+	var b bytes.Buffer
+	w := zlib.NewWriter(&b)
+	w.Write([]byte(s))
+	w.Close()
+	return b.String()
+}
+
+func TestCheckPDF(t *testing.T) {
+	tests := []struct {
+		name   string
+		pdf    string
+		reject bool
+	}{
+		{"plain", "%PDF-1.7\n1 0 obj\n<< /Type /Catalog /Pages 2 0 R >>\nendobj\n", false},
+		{"open action javascript", "%PDF-1.7\n1 0 obj\n<< /OpenAction << /S /JavaScript /JS (app.alert(1)) >> >>\nendobj\n", true},
+		{"hex-escaped name", "%PDF-1.7\n1 0 obj\n<< /S /J#61vaScript >>\nendobj\n", true},
+		{"launch action", "%PDF-1.7\n1 0 obj\n<< /S /Launch /F (cmd.exe) >>\nendobj\n", true},
+		{"javascript in object stream", "%PDF-1.7\n5 0 obj\n<< /Type /ObjStm /Filter /FlateDecode /N 1 >>\nstream\n" +
+			flate("<< /S /JavaScript /JS (x) >>") + "\nendstream\nendobj\n", true},
+		{"clean object stream", "%PDF-1.7\n5 0 obj\n<< /Type /ObjStm /Filter /FlateDecode /N 1 >>\nstream\n" +
+			flate("<< /Type /Page >>") + "\nendstream\nendobj\n", false},
+		{"similar names", "%PDF-1.7\n<< /JSON /JavaScriptless /Launcher >>\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckPDF([]byte(tt.pdf))
+			if !tt.reject {
+				assert.NoError(t, err)
+				return
+			}
+			var appErr *apperrors.AppError
+			require.ErrorAs(t, err, &appErr)
+			assert.Equal(t, "errors.file.pdf_active_content", appErr.MessageKey)
+		})
+	}
+}
+
+// fakeScanner flags content containing "EICAR" and can simulate an outage
+type fakeScanner struct {
+	down bool
+}
+
+func (f *fakeScanner) Name() string { return "fake" }
+
+func (f *fakeScanner) Scan(ctx context.Context, r io.Reader) (*scan.Result, error) {
+	if f.down {
+		return nil, errors.New("connection refused")
+	}
+	data, _ := io.ReadAll(r)
+	if bytes.Contains(data, []byte("EICAR")) {
+		return &scan.Result{Signature: "Eicar-Test-Signature"}, nil
+	}
+	return &scan.Result{Clean: true}, nil
+}
+
+func TestUpload_Scanning(t *testing.T) {
+	store := newTestLocal(t)
+	scanner := &fakeScanner{}
+	docs := &Documents{Storage: store, Policy: UploadPolicy{MaxBytes: 1 << 20, Scanner: scanner}}
+
+	obj, err := docs.Upload(testContext(), "staff/files", "emp-1", bytes.NewReader(pdf), PDFOnly)
+	require.NoError(t, err)
+	assert.Equal(t, scan.StatusClean, obj.ScanStatus)
+
+	_, err = docs.Upload(testContext(), "staff/files", "emp-1", bytes.NewReader(append(append([]byte{}, pdf...), "EICAR"...)), PDFOnly)
+	var appErr *apperrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, "errors.file.infected", appErr.MessageKey)
+	assert.Equal(t, 422, appErr.StatusCode)
+
+	scanner.down = true
+	obj, err = docs.Upload(testContext(), "staff/files", "emp-1", bytes.NewReader(pdf), PDFOnly)
+	require.NoError(t, err, "an unavailable scanner quarantines instead of failing the upload")
+	assert.Equal(t, scan.StatusPending, obj.ScanStatus)
+}
+
+// memoryQueue is an in-memory ScanQueue
+type memoryQueue struct {
+	pending []PendingScan
+	status  map[string]string
+}
+
+func (q *memoryQueue) ListPendingScans(ctx context.Context, limit int) ([]PendingScan, error) {
+	return q.pending, nil
+}
+
+func (q *memoryQueue) SetScanStatus(ctx context.Context, id, status string) error {
+	q.status[id] = status
+	return nil
+}
+
+func TestRescanPending(t *testing.T) {
+	store := newTestLocal(t)
+	scanner := &fakeScanner{}
+	docs := &Documents{Storage: store, Policy: UploadPolicy{Scanner: scanner}}
+	ctx := context.Background()
+
+	put := func(key, content string) {
+		require.NoError(t, store.Put(ctx, Object{Key: key}, bytes.NewReader([]byte(content))))
+	}
+	put("tenants/t/a/o/clean.pdf", "%PDF-1.7")
+	put("tenants/t/a/o/bad.pdf", "%PDF-1.7 EICAR")
+
+	queue := &memoryQueue{status: map[string]string{}}
+	for i, key := range []string{"tenants/t/a/o/clean.pdf", "tenants/t/a/o/bad.pdf", "tenants/t/a/o/missing.pdf"} {
+		queue.pending = append(queue.pending, PendingScan{ID: fmt.Sprint(i), Key: key})
+	}
+
+	scanner.down = true
+	assert.Error(t, docs.RescanPending(ctx, queue), "fails while the scanner is down so the job retries")
+	assert.Empty(t, queue.status)
+
+	scanner.down = false
+	require.NoError(t, docs.RescanPending(ctx, queue))
+	assert.Equal(t, map[string]string{"0": scan.StatusClean, "1": scan.StatusInfected, "2": scan.StatusError}, queue.status)
+
+	_, err := store.Get(ctx, "tenants/t/a/o/bad.pdf")
+	assert.ErrorIs(t, err, ErrNotFound, "infected objects are deleted")
+}
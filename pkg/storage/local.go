@@ -0,0 +1,189 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	apperrors "github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/httputil"
+)
+
+// PublicPathPrefix is the route prefix local signed URLs point to. Services
+// serve it with ServeSigned outside of tenant middleware; the signature is
+// the only authorization.
+const PublicPathPrefix = "/files/"
+
+// Local stores objects as files below a root directory
+type Local struct {
+	root       string
+	signingKey []byte
+	baseURL    string
+	now        func() time.Time
+}
+
+// NewLocal creates a local filesystem backend. Signed URLs are built on
+// baseURL (the public gateway address) and signed with signingKey.
+func NewLocal(root, signingKey, baseURL string) (*Local, error) {
+	if signingKey == "" {
+		return nil, errors.New("storage: local backend requires a signing key")
+	}
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("storage: resolve %s: %w", root, err)
+	}
+	return &Local{
+		root:       abs,
+		signingKey: []byte(signingKey),
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		now:        time.Now,
+	}, nil
+}
+
+// path maps a key to its file, refusing keys that escape the root
+func (l *Local) path(key string) (string, error) {
+	if !validKey(key) {
+		return "", fmt.Errorf("storage: invalid key %q", key)
+	}
+	return filepath.Join(l.root, filepath.FromSlash(key)), nil
+}
+
+// Put writes the object to a temporary file and renames it into place, so
+// readers never see a partial file
+func (l *Local) Put(ctx context.Context, obj Object, body io.Reader) error {
+	path, err := l.path(obj.Key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op after a successful rename
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Get opens the object's file
+func (l *Local) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+// Delete removes the object's file
+func (l *Local) Delete(ctx context.Context, key string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// SignedURL returns <baseURL>/files/<key>?expires=..&filename=..&signature=..
+func (l *Local) SignedURL(ctx context.Context, key string, ttl time.Duration, filename string) (string, error) {
+	if !validKey(key) {
+		return "", fmt.Errorf("storage: invalid key %q", key)
+	}
+	expires := strconv.FormatInt(l.now().Add(ttl).Unix(), 10)
+
+	q := url.Values{}
+	q.Set("expires", expires)
+	if filename != "" {
+		q.Set("filename", filename)
+	}
+	q.Set("signature", l.sign(key, expires, filename))
+
+	return l.baseURL + PublicPathPrefix + key + "?" + q.Encode(), nil
+}
+
+// sign computes the URL signature over key, expiry and attachment name
+func (l *Local) sign(key, expires, filename string) string {
+	mac := hmac.New(sha256.New, l.signingKey)
+	mac.Write([]byte(key + "\n" + expires + "\n" + filename))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verify checks a signed URL's signature and expiry
+func (l *Local) verify(key string, q url.Values) bool {
+	expires := q.Get("expires")
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || l.now().Unix() > unix {
+		return false
+	}
+	want := l.sign(key, expires, q.Get("filename"))
+	return hmac.Equal([]byte(want), []byte(q.Get("signature")))
+}
+
+// ServeHTTP serves an object addressed by a signed URL
+func (l *Local) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, PublicPathPrefix)
+	if !validKey(key) || !l.verify(key, r.URL.Query()) {
+		httputil.ErrorLocalized(w, r, apperrors.Forbidden("errors.file.link_invalid"))
+		return
+	}
+
+	path, _ := l.path(key)
+	f, err := os.Open(path)
+	if err != nil {
+		httputil.ErrorLocalized(w, r, apperrors.NotFound("file"))
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		httputil.ErrorLocalized(w, r, apperrors.Internal("errors.file.read_failed"))
+		return
+	}
+
+	if filename := r.URL.Query().Get("filename"); filename != "" {
+		w.Header().Set("Content-Disposition", contentDisposition(filename))
+	}
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	http.ServeContent(w, r, filepath.Base(path), info.ModTime(), f)
+}
+
+// ServeSigned returns the handler for PublicPathPrefix. Only the local
+// backend serves files itself; other backends sign URLs that point directly
+// at the object store, so the route answers 404 for them.
+func ServeSigned(store Storage) http.Handler {
+	if h, ok := store.(http.Handler); ok {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		httputil.ErrorLocalized(w, r, apperrors.NotFound("file"))
+	})
+}
@@ -0,0 +1,313 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/medflow/medflow-backend/pkg/config"
+)
+
+const (
+	sigV4Algorithm = "AWS4-HMAC-SHA256"
+	amzDateFormat  = "20060102T150405Z"
+	unsignedBody   = "UNSIGNED-PAYLOAD"
+	// emptySHA256 is the SHA-256 of an empty body
+	emptySHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	// maxPresignTTL is the longest expiry S3 accepts for presigned URLs
+	maxPresignTTL = 7 * 24 * time.Hour
+)
+
+// S3 stores objects in an S3-compatible bucket (AWS S3, GCS in
+// interoperability mode, MinIO). Requests are signed with AWS Signature
+// Version 4; no SDK is needed for the four operations used.
+type S3 struct {
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	pathStyle bool
+	client    *http.Client
+	now       func() time.Time
+}
+
+// NewS3 creates an S3-compatible backend
+func NewS3(cfg config.S3Config) (*S3, error) {
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("storage: invalid s3 endpoint %q", cfg.Endpoint)
+	}
+	if cfg.Bucket == "" {
+		return nil, errors.New("storage: s3 bucket is required")
+	}
+	region := cfg.Region
+	if region == "" {
+		region = "auto"
+	}
+	return &S3{
+		endpoint:  endpoint,
+		region:    region,
+		bucket:    cfg.Bucket,
+		accessKey: cfg.AccessKeyID,
+		secretKey: cfg.SecretAccessKey,
+		pathStyle: cfg.PathStyle,
+		client:    &http.Client{Timeout: 60 * time.Second},
+		now:       time.Now,
+	}, nil
+}
+
+// objectURL addresses key virtual-hosted style (bucket.host/key) or path
+// style (host/bucket/key)
+func (s *S3) objectURL(key string) *url.URL {
+	u := *s.endpoint
+	if s.pathStyle {
+		u.Path = "/" + s.bucket + "/" + key
+	} else {
+		u.Host = s.bucket + "." + s.endpoint.Host
+		u.Path = "/" + key
+	}
+	u.RawQuery = ""
+	return &u
+}
+
+// Put uploads the object with its SHA-256 as the signed payload hash, so the
+// store rejects a body corrupted in transit
+func (s *S3) Put(ctx context.Context, obj Object, body io.Reader) error {
+	if !validKey(obj.Key) {
+		return fmt.Errorf("storage: invalid key %q", obj.Key)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(obj.Key).String(), body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = obj.Size
+	req.Header.Set("Content-Type", obj.ContentType)
+	s.sign(req, obj.SHA256)
+
+	return s.do(req, nil)
+}
+
+// Get downloads the object
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.objectURL(key).String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	s.sign(req, emptySHA256)
+
+	var body io.ReadCloser
+	if err := s.do(req, &body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// Delete removes the object
+func (s *S3) Delete(ctx context.Context, key string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectURL(key).String(), nil)
+	if err != nil {
+		return err
+	}
+	s.sign(req, emptySHA256)
+
+	err = s.do(req, nil)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// SignedURL returns a presigned GET URL
+func (s *S3) SignedURL(ctx context.Context, key string, ttl time.Duration, filename string) (string, error) {
+	if !validKey(key) {
+		return "", fmt.Errorf("storage: invalid key %q", key)
+	}
+	if ttl > maxPresignTTL {
+		ttl = maxPresignTTL
+	}
+
+	u := s.objectURL(key)
+	q := url.Values{}
+	if filename != "" {
+		q.Set("response-content-disposition", contentDisposition(filename))
+	}
+	return s.presign(http.MethodGet, u, q, ttl), nil
+}
+
+// do sends a signed request. With body set, a successful response body is
+// handed to the caller; otherwise it is drained and closed.
+func (s *S3) do(req *http.Request, body *io.ReadCloser) error {
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 && body != nil {
+		*body = resp.Body
+		return nil
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case resp.StatusCode >= 300:
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("storage: s3 %s %s: %s: %s", req.Method, req.URL.Path, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// sign adds SigV4 header authentication to req
+func (s *S3) sign(req *http.Request, payloadHash string) {
+	now := s.now().UTC()
+	req.Header.Set("X-Amz-Date", now.Format(amzDateFormat))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers, signed := canonicalHeaders(req)
+	scope := s.scope(now)
+	canonical := strings.Join([]string{
+		req.Method,
+		canonicalURI(req.URL),
+		canonicalQuery(req.URL.Query()),
+		headers,
+		signed,
+		payloadHash,
+	}, "\n")
+
+	signature := s.signature(now, scope, canonical)
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s,SignedHeaders=%s,Signature=%s",
+		sigV4Algorithm, s.accessKey, scope, signed, signature))
+}
+
+// presign builds a query-authenticated URL that signs only the host header
+func (s *S3) presign(method string, u *url.URL, q url.Values, ttl time.Duration) string {
+	now := s.now().UTC()
+	scope := s.scope(now)
+
+	q.Set("X-Amz-Algorithm", sigV4Algorithm)
+	q.Set("X-Amz-Credential", s.accessKey+"/"+scope)
+	q.Set("X-Amz-Date", now.Format(amzDateFormat))
+	q.Set("X-Amz-Expires", strconv.Itoa(int(ttl.Seconds())))
+	q.Set("X-Amz-SignedHeaders", "host")
+
+	canonical := strings.Join([]string{
+		method,
+		canonicalURI(u),
+		canonicalQuery(q),
+		"host:" + u.Host + "\n",
+		"host",
+		unsignedBody,
+	}, "\n")
+
+	query := canonicalQuery(q) + "&X-Amz-Signature=" + s.signature(now, scope, canonical)
+	return u.Scheme + "://" + u.Host + canonicalURI(u) + "?" + query
+}
+
+func (s *S3) scope(t time.Time) string {
+	return t.Format("20060102") + "/" + s.region + "/s3/aws4_request"
+}
+
+// signature derives the signing key for the day and signs the canonical request
+func (s *S3) signature(t time.Time, scope, canonicalRequest string) string {
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		sigV4Algorithm,
+		t.Format(amzDateFormat),
+		scope,
+		hex.EncodeToString(hash[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), t.Format("20060102"))
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// canonicalURI encodes each path segment once; S3 does not normalize paths
+func canonicalURI(u *url.URL) string {
+	segments := strings.Split(u.Path, "/")
+	for i, seg := range segments {
+		segments[i] = uriEncode(seg)
+	}
+	if uri := strings.Join(segments, "/"); uri != "" {
+		return uri
+	}
+	return "/"
+}
+
+// canonicalQuery sorts parameters by name and encodes them per SigV4
+func canonicalQuery(q url.Values) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, k := range keys {
+		values := append([]string(nil), q[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			parts = append(parts, uriEncode(k)+"="+uriEncode(v))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// canonicalHeaders returns the canonical header block and the signed header
+// list. Host, content type, range and all x-amz-* headers are signed.
+func canonicalHeaders(req *http.Request) (string, string) {
+	values := map[string]string{"host": req.URL.Host}
+	for name, vals := range req.Header {
+		lower := strings.ToLower(name)
+		if lower == "content-type" || lower == "range" || strings.HasPrefix(lower, "x-amz-") {
+			values[lower] = strings.TrimSpace(strings.Join(vals, ","))
+		}
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name + ":" + values[name] + "\n")
+	}
+	return b.String(), strings.Join(names, ";")
+}
+
+// uriEncode percent-encodes everything except RFC 3986 unreserved characters
+func uriEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
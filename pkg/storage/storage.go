@@ -0,0 +1,215 @@
+// Package storage stores uploaded documents in a pluggable object store.
+//
+// Objects are addressed by tenant-prefixed keys
+// (tenants/<tenant-id>/<area>/<owner-id>/<object-id><ext>) so that one bucket
+// or directory can hold all tenants without collisions, and a key alone
+// identifies the tenant it belongs to. Downloads are served through
+// short-lived signed URLs instead of streaming through the services.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/medflow/medflow-backend/pkg/config"
+	"github.com/medflow/medflow-backend/pkg/scan"
+)
+
+// ErrNotFound is returned when an object does not exist
+var ErrNotFound = errors.New("storage: object not found")
+
+// Object describes a stored object
+type Object struct {
+	Key         string `json:"key"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	// SHA256 is the hex-encoded checksum of the content
+	SHA256 string `json:"sha256"`
+	// ScanStatus is the malware scan status (see package scan)
+	ScanStatus string `json:"scan_status"`
+}
+
+// Storage is an object store backend
+type Storage interface {
+	// Put stores body under obj.Key. obj.Size and obj.SHA256 must describe body.
+	Put(ctx context.Context, obj Object, body io.Reader) error
+	// Get opens an object for reading; the caller closes it
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes an object. Deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns a URL that downloads the object without further
+	// authentication until ttl elapses. filename, if set, is sent as the
+	// attachment name.
+	SignedURL(ctx context.Context, key string, ttl time.Duration, filename string) (string, error)
+}
+
+// New creates the backend selected by cfg.Backend
+func New(cfg *config.StorageConfig) (Storage, error) {
+	switch cfg.Backend {
+	case "local":
+		return NewLocal(cfg.LocalDir, cfg.SigningKey, cfg.PublicBaseURL)
+	case "s3":
+		return NewS3(cfg.S3)
+	default:
+		return nil, fmt.Errorf("storage: unknown backend %q", cfg.Backend)
+	}
+}
+
+// NewKey builds a tenant-prefixed object key with a random object ID, e.g.
+// tenants/<tenant-id>/inventory/documents/<item-id>/<uuid>.pdf
+func NewKey(tenantID, area, ownerID, ext string) string {
+	return fmt.Sprintf("tenants/%s/%s/%s/%s%s", tenantID, area, ownerID, uuid.New().String(), ext)
+}
+
+// TenantOf returns the tenant ID encoded in a key, or "" for a malformed key
+func TenantOf(key string) string {
+	parts := strings.SplitN(key, "/", 3)
+	if len(parts) < 3 || parts[0] != "tenants" {
+		return ""
+	}
+	return parts[1]
+}
+
+// validKey rejects keys that could escape their prefix
+func validKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	for _, seg := range strings.Split(key, "/") {
+		if seg == "" || seg == "." || seg == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+// contentDisposition builds an attachment header value with an RFC 5987
+// encoded filename, so umlauts in document names survive
+func contentDisposition(filename string) string {
+	ascii := strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e || r == '"' || r == '\\' {
+			return '_'
+		}
+		return r
+	}, filename)
+	return fmt.Sprintf(`attachment; filename="%s"; filename*=UTF-8''%s`, ascii, url.PathEscape(filename))
+}
+
+// Documents bundles a backend with the upload policy and link lifetime that
+// services apply to user-uploaded documents
+type Documents struct {
+	Storage
+	Policy UploadPolicy
+	URLTTL time.Duration
+}
+
+// NewDocuments creates the configured backend for document uploads. Uploads
+// are checked with scanner before they are stored.
+func NewDocuments(cfg *config.StorageConfig, scanner scan.Scanner) (*Documents, error) {
+	store, err := New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Documents{
+		Storage: store,
+		Policy:  UploadPolicy{MaxBytes: cfg.MaxUploadBytes, AllowedTypes: AnyDocument, Scanner: scanner},
+		URLTTL:  cfg.SignedURLTTL,
+	}, nil
+}
+
+// Upload stores a document that must be one of types, see Upload
+func (d *Documents) Upload(ctx context.Context, area, ownerID string, r io.Reader, types []string) (*Object, error) {
+	policy := d.Policy
+	policy.AllowedTypes = types
+	return Upload(ctx, d.Storage, area, ownerID, r, policy)
+}
+
+// DownloadURL signs a download link for key that names the file filename
+func (d *Documents) DownloadURL(ctx context.Context, key, filename string) (*DownloadURL, error) {
+	expiresAt := time.Now().Add(d.URLTTL)
+	u, err := d.SignedURL(ctx, key, d.URLTTL, filename)
+	if err != nil {
+		return nil, err
+	}
+	return &DownloadURL{URL: u, ExpiresAt: expiresAt}, nil
+}
+
+// DownloadURL is a signed, short-lived download link
+type DownloadURL struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// PendingScan is a stored document awaiting a malware scan
+type PendingScan struct {
+	ID  string `db:"id"`
+	Key string `db:"file_path"`
+}
+
+// ScanQueue is implemented by repositories of scanned documents
+type ScanQueue interface {
+	// ListPendingScans returns up to limit documents of the tenant in ctx
+	// with scan status pending
+	ListPendingScans(ctx context.Context, limit int) ([]PendingScan, error)
+	// SetScanStatus records the scan outcome of a document
+	SetScanStatus(ctx context.Context, id, status string) error
+}
+
+// rescanBatch bounds how many documents one rescan run checks per tenant
+const rescanBatch = 100
+
+// RescanPending scans the documents of the tenant in ctx that were stored
+// while the scanner was unavailable. Infected objects are deleted; objects
+// that cannot be read are marked scan.StatusError. It is meant to run as a
+// per-tenant background job and fails when the scanner is still unavailable,
+// so the job is retried.
+func (d *Documents) RescanPending(ctx context.Context, queue ScanQueue) error {
+	if d.Policy.Scanner == nil {
+		return nil
+	}
+
+	pending, err := queue.ListPendingScans(ctx, rescanBatch)
+	if err != nil {
+		return err
+	}
+
+	for _, doc := range pending {
+		status, err := d.rescan(ctx, doc.Key)
+		if err != nil {
+			return fmt.Errorf("storage: rescan %s: %w", doc.ID, err)
+		}
+		if err := queue.SetScanStatus(ctx, doc.ID, status); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rescan scans a stored object and returns its new scan status
+func (d *Documents) rescan(ctx context.Context, key string) (string, error) {
+	rc, err := d.Get(ctx, key)
+	if errors.Is(err, ErrNotFound) {
+		return scan.StatusError, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	result, err := d.Policy.Scanner.Scan(ctx, rc)
+	if err != nil {
+		return "", err
+	}
+	if !result.Clean {
+		if err := d.Delete(ctx, key); err != nil {
+			return "", err
+		}
+	}
+	return result.Status(), nil
+}
@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/medflow/medflow-backend/pkg/errors"
+	"github.com/medflow/medflow-backend/pkg/scan"
+	"github.com/medflow/medflow-backend/pkg/tenant"
+)
+
+// UploadPolicy limits what an upload may contain
+type UploadPolicy struct {
+	// MaxBytes is the largest accepted upload
+	MaxBytes int64
+	// AllowedTypes lists the accepted content types (see DetectType)
+	AllowedTypes []string
+	// Scanner checks the content for malware; nil skips scanning
+	Scanner scan.Scanner
+}
+
+// Upload stores an upload for the tenant in ctx under a new key in area
+// (e.g. "inventory/documents") and owner (e.g. the item ID).
+//
+// The upload passes through the validation pipeline before it is stored:
+//  1. size limit (policy.MaxBytes)
+//  2. content type by magic bytes, checked against policy.AllowedTypes
+//  3. PDFs: no JavaScript or launch actions (CheckPDF)
+//  4. malware scan; infected uploads are never stored
+//
+// If the scanner is unreachable the upload is stored with scan status
+// pending, to be rescanned later. Rejections are AppErrors; backend failures
+// are returned as plain errors for the caller to log. The returned object
+// carries the SHA-256 checksum and scan status to store alongside the metadata.
+func Upload(ctx context.Context, store Storage, area, ownerID string, r io.Reader, policy UploadPolicy) (*Object, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !validKey(area) || !validKey(ownerID) || strings.Contains(ownerID, "/") {
+		return nil, errors.BadRequest("errors.file.invalid_path")
+	}
+
+	// Buffer the upload: S3 needs the length and checksum before the body.
+	// One byte beyond the limit tells an oversized upload from an exact fit.
+	var buf bytes.Buffer
+	hash := sha256.New()
+	n, err := io.Copy(io.MultiWriter(&buf, hash), io.LimitReader(r, policy.MaxBytes+1))
+	if err != nil {
+		return nil, errors.BadRequest("errors.file.read_failed")
+	}
+	if n > policy.MaxBytes {
+		return nil, errors.BadRequest("errors.file.too_large", map[string]string{"max": FormatSize(policy.MaxBytes)})
+	}
+	content := buf.Bytes()
+
+	contentType, ext, ok := DetectType(content)
+	if !ok || !allowed(policy.AllowedTypes, contentType) {
+		return nil, errors.BadRequest("errors.file.type_not_allowed", map[string]string{"types": describeTypes(policy.AllowedTypes)})
+	}
+	if contentType == TypePDF {
+		if err := CheckPDF(content); err != nil {
+			return nil, err
+		}
+	}
+
+	obj := Object{
+		Key:         NewKey(tenantID, area, ownerID, ext),
+		ContentType: contentType,
+		Size:        n,
+		SHA256:      hex.EncodeToString(hash.Sum(nil)),
+		ScanStatus:  scan.StatusPending,
+	}
+
+	if policy.Scanner != nil {
+		result, err := policy.Scanner.Scan(ctx, bytes.NewReader(content))
+		switch {
+		case err != nil:
+			// Scanner down: keep the upload quarantined (pending) for the rescan job
+		case !result.Clean:
+			return nil, errors.NewWithKey("FILE_INFECTED", "errors.file.infected", http.StatusUnprocessableEntity, map[string]string{"signature": result.Signature})
+		default:
+			obj.ScanStatus = scan.StatusClean
+		}
+	}
+
+	if err := store.Put(ctx, obj, bytes.NewReader(content)); err != nil {
+		return nil, fmt.Errorf("storage: put %s: %w", obj.Key, err)
+	}
+	return &obj, nil
+}
+
+func allowed(types []string, contentType string) bool {
+	for _, t := range types {
+		if t == contentType {
+			return true
+		}
+	}
+	return false
+}
+
+// describeTypes lists content types by extension for error messages, e.g. "PDF, JPG"
+func describeTypes(types []string) string {
+	names := make([]string, 0, len(types))
+	for _, t := range types {
+		for _, ft := range fileTypes {
+			if ft.contentType == t {
+				names = append(names, strings.ToUpper(strings.TrimPrefix(ft.ext, ".")))
+			}
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// FormatSize renders a byte limit for error messages, e.g. "20 MB"
+func FormatSize(n int64) string {
+	if n >= 1<<20 && n%(1<<20) == 0 {
+		return fmt.Sprintf("%d MB", n>>20)
+	}
+	if n >= 1<<10 && n%(1<<10) == 0 {
+		return fmt.Sprintf("%d KB", n>>10)
+	}
+	return fmt.Sprintf("%d bytes", n)
+}
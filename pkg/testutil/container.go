@@ -158,6 +158,12 @@ func (c *PostgresContainer) CreateAppRole(ctx context.Context, db *sqlx.DB) erro
 		ALTER TABLE users.users FORCE ROW LEVEL SECURITY;
 		ALTER TABLE users.user_roles FORCE ROW LEVEL SECURITY;
 		ALTER TABLE users.audit_logs FORCE ROW LEVEL SECURITY;
+		ALTER TABLE users.notification_settings FORCE ROW LEVEL SECURITY;
+		ALTER TABLE users.notification_webhooks FORCE ROW LEVEL SECURITY;
+		ALTER TABLE users.notification_rules FORCE ROW LEVEL SECURITY;
+		ALTER TABLE users.notifications FORCE ROW LEVEL SECURITY;
+		ALTER TABLE users.notification_deliveries FORCE ROW LEVEL SECURITY;
+		ALTER TABLE users.notification_inbox FORCE ROW LEVEL SECURITY;
 
 		-- staff schema
 		ALTER TABLE staff.employees FORCE ROW LEVEL SECURITY;
@@ -181,6 +187,7 @@ func (c *PostgresContainer) CreateAppRole(ctx context.Context, db *sqlx.DB) erro
 		ALTER TABLE staff.compliance_alerts FORCE ROW LEVEL SECURITY;
 		ALTER TABLE staff.time_correction_requests FORCE ROW LEVEL SECURITY;
 		ALTER TABLE staff.document_processing_audit FORCE ROW LEVEL SECURITY;
+		ALTER TABLE staff.audit_trail FORCE ROW LEVEL SECURITY;
 
 		-- inventory schema
 		ALTER TABLE inventory.storage_rooms FORCE ROW LEVEL SECURITY;
@@ -197,6 +204,38 @@ func (c *PostgresContainer) CreateAppRole(ctx context.Context, db *sqlx.DB) erro
 		ALTER TABLE inventory.device_trainings FORCE ROW LEVEL SECURITY;
 		ALTER TABLE inventory.device_incidents FORCE ROW LEVEL SECURITY;
 		ALTER TABLE inventory.temperature_readings FORCE ROW LEVEL SECURITY;
+		ALTER TABLE inventory.suppliers FORCE ROW LEVEL SECURITY;
+		ALTER TABLE inventory.supplier_items FORCE ROW LEVEL SECURITY;
+		ALTER TABLE inventory.purchase_orders FORCE ROW LEVEL SECURITY;
+		ALTER TABLE inventory.purchase_order_lines FORCE ROW LEVEL SECURITY;
+		ALTER TABLE inventory.goods_receipts FORCE ROW LEVEL SECURITY;
+		ALTER TABLE inventory.goods_receipt_lines FORCE ROW LEVEL SECURITY;
+		ALTER TABLE inventory.stocktakes FORCE ROW LEVEL SECURITY;
+		ALTER TABLE inventory.stocktake_lines FORCE ROW LEVEL SECURITY;
+		ALTER TABLE inventory.stocktake_counts FORCE ROW LEVEL SECURITY;
+		ALTER TABLE inventory.field_safety_notices FORCE ROW LEVEL SECURITY;
+		ALTER TABLE inventory.recall_matches FORCE ROW LEVEL SECURITY;
+		ALTER TABLE inventory.stock_reservations FORCE ROW LEVEL SECURITY;
+		ALTER TABLE inventory.stock_reservation_batches FORCE ROW LEVEL SECURITY;
+		ALTER TABLE inventory.procedure_kits FORCE ROW LEVEL SECURITY;
+		ALTER TABLE inventory.procedure_kit_items FORCE ROW LEVEL SECURITY;
+		ALTER TABLE inventory.kit_consumptions FORCE ROW LEVEL SECURITY;
+		ALTER TABLE inventory.kit_consumption_lines FORCE ROW LEVEL SECURITY;
+		ALTER TABLE inventory.consumption_forecasts FORCE ROW LEVEL SECURITY;
+		ALTER TABLE inventory.label_templates FORCE ROW LEVEL SECURITY;
+		ALTER TABLE public.retention_policies FORCE ROW LEVEL SECURITY;
+		ALTER TABLE public.legal_holds FORCE ROW LEVEL SECURITY;
+		ALTER TABLE public.deletion_protocols FORCE ROW LEVEL SECURITY;
+		ALTER TABLE inventory.audit_trail FORCE ROW LEVEL SECURITY;
+		ALTER TABLE public.audit_chain_heads FORCE ROW LEVEL SECURITY;
+		ALTER TABLE inventory.gdpdu_exports FORCE ROW LEVEL SECURITY;
+		ALTER TABLE inventory.temperature_sensors FORCE ROW LEVEL SECURITY;
+		ALTER TABLE inventory.temperature_excursions FORCE ROW LEVEL SECURITY;
+		ALTER TABLE inventory.temperature_excursion_batches FORCE ROW LEVEL SECURITY;
+		ALTER TABLE inventory.btm_register FORCE ROW LEVEL SECURITY;
+		ALTER TABLE inventory.btm_authorized_personnel FORCE ROW LEVEL SECURITY;
+		ALTER TABLE inventory.btm_period_closings FORCE ROW LEVEL SECURITY;
+		ALTER TABLE inventory.btm_period_balances FORCE ROW LEVEL SECURITY;
 	`
 
 	if _, err := db.ExecContext(ctx, sql); err != nil {
@@ -248,11 +287,27 @@ func (c *PostgresContainer) CreatePublicSchema(ctx context.Context, db *sqlx.DB)
 		END;
 		$$ LANGUAGE plpgsql;
 
+		-- Search support (migration 000028_search)
+		CREATE EXTENSION IF NOT EXISTS pg_trgm WITH SCHEMA public;
+
+		CREATE OR REPLACE FUNCTION public.search_normalize(input TEXT)
+		RETURNS TEXT
+		LANGUAGE sql
+		IMMUTABLE
+		PARALLEL SAFE
+		AS $$
+			SELECT replace(replace(replace(replace(lower(COALESCE(input, '')),
+				'ä', 'ae'), 'ö', 'oe'), 'ü', 'ue'), 'ß', 'ss')
+		$$;
+
 		-- Tenants registry (public schema, NO RLS)
 		CREATE TABLE IF NOT EXISTS public.tenants (
 			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
 			name VARCHAR(255) NOT NULL,
 			slug VARCHAR(100) UNIQUE NOT NULL,
+			street VARCHAR(255),
+			city VARCHAR(100),
+			postal_code VARCHAR(20),
 			subscription_status VARCHAR(50) DEFAULT 'trial',
 			settings JSONB DEFAULT '{}',
 			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
@@ -274,11 +329,60 @@ func (c *PostgresContainer) CreatePublicSchema(ctx context.Context, db *sqlx.DB)
 		-- Tenant audit log (public schema, NO RLS)
 		CREATE TABLE IF NOT EXISTS public.tenant_audit_log (
 			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+			event_type VARCHAR(50) NOT NULL,
+			event_data JSONB NOT NULL DEFAULT '{}',
+			performed_by UUID,
+			performed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			ip_address INET,
+			user_agent TEXT
+		);
+
+		-- Audit trail hash chain heads (000038)
+		CREATE TABLE IF NOT EXISTS public.audit_chain_heads (
+			tenant_id UUID NOT NULL REFERENCES public.tenants(id) ON DELETE CASCADE,
+			chain VARCHAR(100) NOT NULL,
+			sequence BIGINT NOT NULL DEFAULT 0,
+			hash CHAR(64) NOT NULL,
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (tenant_id, chain)
+		);
+		ALTER TABLE public.audit_chain_heads ENABLE ROW LEVEL SECURITY;
+		DROP POLICY IF EXISTS tenant_isolation ON public.audit_chain_heads;
+		CREATE POLICY tenant_isolation ON public.audit_chain_heads
+			FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+			WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+		-- Job run history and activation claims (000027, 000046, public schema, NO RLS)
+		CREATE TABLE IF NOT EXISTS public.job_runs (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			job_name VARCHAR(100) NOT NULL,
 			tenant_id UUID REFERENCES public.tenants(id),
-			action VARCHAR(100) NOT NULL,
-			actor_id UUID,
-			actor_name VARCHAR(255),
-			details JSONB,
+			trigger VARCHAR(20) NOT NULL DEFAULT 'schedule',
+			status VARCHAR(20) NOT NULL DEFAULT 'running',
+			attempts INTEGER NOT NULL DEFAULT 1,
+			error TEXT,
+			instance_id VARCHAR(100),
+			scheduled_at TIMESTAMPTZ,
+			started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			finished_at TIMESTAMPTZ,
+			duration_ms BIGINT,
+			CONSTRAINT job_runs_trigger_valid CHECK (trigger IN ('schedule', 'manual')),
+			CONSTRAINT job_runs_status_valid CHECK (status IN ('running', 'succeeded', 'failed'))
+		);
+
+		CREATE TABLE IF NOT EXISTS public.job_activations (
+			job_name VARCHAR(100) NOT NULL,
+			scheduled_at TIMESTAMPTZ NOT NULL,
+			instance_id VARCHAR(100),
+			claimed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (job_name, scheduled_at)
+		);
+
+		-- Sensor key lookup (000043, public schema, NO RLS)
+		CREATE TABLE IF NOT EXISTS public.temperature_sensor_lookup (
+			key_id VARCHAR(64) PRIMARY KEY,
+			tenant_id UUID NOT NULL REFERENCES public.tenants(id) ON DELETE CASCADE,
 			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
 		);
 	`
@@ -397,6 +501,145 @@ var usersSchemaSQL = `
 	CREATE POLICY tenant_isolation ON users.audit_logs
 		FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
 		WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+	CREATE TABLE IF NOT EXISTS users.notification_settings (
+		tenant_id UUID PRIMARY KEY REFERENCES public.tenants(id),
+		quiet_hours_start TIME,
+		quiet_hours_end TIME,
+		timezone VARCHAR(64) NOT NULL DEFAULT 'Europe/Berlin',
+		quiet_hours_bypass_severity VARCHAR(20) NOT NULL DEFAULT 'critical',
+		locale VARCHAR(5) NOT NULL DEFAULT 'de',
+		updated_by UUID,
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);
+	ALTER TABLE users.notification_settings ENABLE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS tenant_isolation ON users.notification_settings;
+	CREATE POLICY tenant_isolation ON users.notification_settings
+		FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+		WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+	CREATE TABLE IF NOT EXISTS users.notification_webhooks (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+		name VARCHAR(255) NOT NULL,
+		url TEXT NOT NULL,
+		secret VARCHAR(128) NOT NULL,
+		is_active BOOLEAN NOT NULL DEFAULT TRUE,
+		created_by UUID,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);
+	ALTER TABLE users.notification_webhooks ENABLE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS tenant_isolation ON users.notification_webhooks;
+	CREATE POLICY tenant_isolation ON users.notification_webhooks
+		FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+		WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+	CREATE TABLE IF NOT EXISTS users.notification_rules (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+		name VARCHAR(255) NOT NULL,
+		source VARCHAR(20) NOT NULL,
+		alert_types TEXT[] NOT NULL DEFAULT '{}',
+		min_severity VARCHAR(20) NOT NULL DEFAULT 'info',
+		channels TEXT[] NOT NULL,
+		recipient_roles TEXT[] NOT NULL DEFAULT '{}',
+		recipient_user_ids UUID[] NOT NULL DEFAULT '{}',
+		escalation JSONB NOT NULL DEFAULT '[]'::jsonb,
+		is_active BOOLEAN NOT NULL DEFAULT TRUE,
+		created_by UUID,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);
+	ALTER TABLE users.notification_rules ENABLE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS tenant_isolation ON users.notification_rules;
+	CREATE POLICY tenant_isolation ON users.notification_rules
+		FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+		WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+	CREATE TABLE IF NOT EXISTS users.notifications (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+		rule_id UUID NOT NULL REFERENCES users.notification_rules(id) ON DELETE CASCADE,
+		source VARCHAR(20) NOT NULL,
+		source_id VARCHAR(100) NOT NULL,
+		dedup_key VARCHAR(255) NOT NULL,
+		alert_type VARCHAR(50) NOT NULL,
+		severity VARCHAR(20) NOT NULL,
+		message TEXT NOT NULL,
+		link TEXT,
+		escalation_level INTEGER NOT NULL DEFAULT 0,
+		next_escalation_at TIMESTAMPTZ,
+		acknowledged_at TIMESTAMPTZ,
+		acknowledged_by UUID,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_notifications_open ON users.notifications(tenant_id, rule_id, dedup_key)
+		WHERE acknowledged_at IS NULL;
+	ALTER TABLE users.notifications ENABLE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS tenant_isolation ON users.notifications;
+	CREATE POLICY tenant_isolation ON users.notifications
+		FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+		WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+	CREATE TABLE IF NOT EXISTS users.notification_deliveries (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+		notification_id UUID NOT NULL REFERENCES users.notifications(id) ON DELETE CASCADE,
+		escalation_level INTEGER NOT NULL DEFAULT 0,
+		channel VARCHAR(20) NOT NULL,
+		recipient_user_id UUID,
+		recipient TEXT NOT NULL,
+		status VARCHAR(20) NOT NULL DEFAULT 'pending',
+		attempts INTEGER NOT NULL DEFAULT 0,
+		send_after TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		last_error TEXT,
+		sent_at TIMESTAMPTZ,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);
+	ALTER TABLE users.notification_deliveries ENABLE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS tenant_isolation ON users.notification_deliveries;
+	CREATE POLICY tenant_isolation ON users.notification_deliveries
+		FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+		WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+	CREATE TABLE IF NOT EXISTS users.notification_inbox (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+		user_id UUID NOT NULL REFERENCES users.users(id) ON DELETE CASCADE,
+		notification_id UUID REFERENCES users.notifications(id) ON DELETE SET NULL,
+		category VARCHAR(50) NOT NULL,
+		severity VARCHAR(20) NOT NULL DEFAULT 'info',
+		title VARCHAR(255) NOT NULL,
+		body TEXT,
+		link TEXT,
+		read_at TIMESTAMPTZ,
+		archived_at TIMESTAMPTZ,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		seq BIGINT GENERATED ALWAYS AS IDENTITY,
+		dedup_key VARCHAR(255),
+		required_permission VARCHAR(100),
+		entity_type VARCHAR(50),
+		entity_id UUID
+	);
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_notification_inbox_dedup ON users.notification_inbox(tenant_id, user_id, dedup_key)
+		WHERE archived_at IS NULL AND dedup_key IS NOT NULL;
+	CREATE OR REPLACE FUNCTION users.notify_notification_inbox()
+	RETURNS trigger AS $$
+	BEGIN
+		PERFORM pg_notify('notification_inbox', NEW.tenant_id::text || ':' || NEW.user_id::text);
+		RETURN NULL;
+	END;
+	$$ LANGUAGE plpgsql;
+	DROP TRIGGER IF EXISTS notification_inbox_notify ON users.notification_inbox;
+	CREATE TRIGGER notification_inbox_notify
+		AFTER INSERT ON users.notification_inbox
+		FOR EACH ROW EXECUTE FUNCTION users.notify_notification_inbox();
+	ALTER TABLE users.notification_inbox ENABLE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS tenant_isolation ON users.notification_inbox;
+	CREATE POLICY tenant_isolation ON users.notification_inbox
+		FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+		WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
 `
 
 // staffSchemaSQL creates core staff schema tables with RLS policies.
@@ -436,6 +679,7 @@ var staffSchemaSQL = `
 		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
 		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
 		deleted_at TIMESTAMPTZ,
+		anonymized_at TIMESTAMPTZ,
 		UNIQUE(tenant_id, employee_number)
 	);
 	ALTER TABLE staff.employees ENABLE ROW LEVEL SECURITY;
@@ -561,6 +805,10 @@ var staffSchemaSQL = `
 		file_size INTEGER,
 		mime_type VARCHAR(100),
 		category VARCHAR(50) DEFAULT 'other',
+		sha256 CHAR(64),
+		scan_status VARCHAR(20) NOT NULL DEFAULT 'pending',
+		scanned_at TIMESTAMPTZ,
+		uploaded_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
 		uploaded_by UUID,
 		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
 		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
@@ -898,6 +1146,33 @@ var staffSchemaSQL = `
 	CREATE POLICY tenant_isolation ON staff.document_processing_audit
 		FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
 		WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+	-- Staff audit trail (000040)
+	CREATE TABLE IF NOT EXISTS staff.audit_trail (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+		entity_type VARCHAR(50) NOT NULL,
+		entity_id UUID NOT NULL,
+		employee_id UUID,
+		action VARCHAR(50) NOT NULL,
+		field_changes JSONB,
+		metadata JSONB,
+		performed_by UUID,
+		performed_by_name VARCHAR(255),
+		ip_address VARCHAR(45),
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		sequence BIGINT NOT NULL,
+		prev_hash CHAR(64) NOT NULL,
+		hash CHAR(64) NOT NULL
+	);
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_staff_audit_trail_tenant_sequence ON staff.audit_trail(tenant_id, sequence);
+	ALTER TABLE staff.audit_trail ENABLE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS audit_trail_select ON staff.audit_trail;
+	CREATE POLICY audit_trail_select ON staff.audit_trail
+		FOR SELECT USING (tenant_id = current_setting('app.current_tenant')::uuid);
+	DROP POLICY IF EXISTS audit_trail_insert ON staff.audit_trail;
+	CREATE POLICY audit_trail_insert ON staff.audit_trail
+		FOR INSERT WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
 `
 
 // inventorySchemaSQL creates core inventory schema tables with RLS policies.
@@ -936,6 +1211,7 @@ var inventorySchemaSQL = `
 		min_temperature_celsius DECIMAL(5,2),
 		max_temperature_celsius DECIMAL(5,2),
 		temperature_monitoring_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+		excursion_grace_minutes INTEGER,
 		created_by UUID,
 		updated_by UUID,
 		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
@@ -974,6 +1250,7 @@ var inventorySchemaSQL = `
 		category VARCHAR(100),
 		barcode VARCHAR(100),
 		article_number VARCHAR(100),
+		pzn VARCHAR(8),
 		manufacturer VARCHAR(255),
 		supplier VARCHAR(255),
 		unit VARCHAR(50) NOT NULL,
@@ -1013,6 +1290,8 @@ var inventorySchemaSQL = `
 		last_mtk_date DATE,
 		next_mtk_due DATE,
 		shelf_life_after_opening_days INTEGER,
+		-- BtM (000018)
+		is_controlled_substance BOOLEAN NOT NULL DEFAULT FALSE,
 		created_by UUID,
 		updated_by UUID,
 		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
@@ -1026,6 +1305,20 @@ var inventorySchemaSQL = `
 		WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
 	CREATE INDEX IF NOT EXISTS idx_inventory_items_tenant ON inventory.inventory_items(tenant_id);
 
+	-- Item list keyset indexes (000048)
+	CREATE INDEX IF NOT EXISTS idx_inventory_items_keyset_name
+		ON inventory.inventory_items(tenant_id, name, id) WHERE deleted_at IS NULL;
+	CREATE INDEX IF NOT EXISTS idx_inventory_items_keyset_category
+		ON inventory.inventory_items(tenant_id, category, id) WHERE deleted_at IS NULL;
+	CREATE INDEX IF NOT EXISTS idx_inventory_items_keyset_manufacturer
+		ON inventory.inventory_items(tenant_id, COALESCE(manufacturer, ''), id) WHERE deleted_at IS NULL;
+	CREATE INDEX IF NOT EXISTS idx_inventory_items_keyset_supplier
+		ON inventory.inventory_items(tenant_id, COALESCE(supplier, ''), id) WHERE deleted_at IS NULL;
+	CREATE INDEX IF NOT EXISTS idx_inventory_items_keyset_created
+		ON inventory.inventory_items(tenant_id, created_at, id) WHERE deleted_at IS NULL;
+	CREATE INDEX IF NOT EXISTS idx_inventory_items_keyset_updated
+		ON inventory.inventory_items(tenant_id, updated_at, id) WHERE deleted_at IS NULL;
+
 	CREATE TABLE IF NOT EXISTS inventory.inventory_batches (
 		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
 		tenant_id UUID NOT NULL REFERENCES public.tenants(id),
@@ -1053,6 +1346,8 @@ var inventorySchemaSQL = `
 	CREATE POLICY tenant_isolation ON inventory.inventory_batches
 		FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
 		WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+	CREATE INDEX IF NOT EXISTS idx_inventory_batches_item_expiry
+		ON inventory.inventory_batches(item_id, expiry_date) WHERE status = 'available' AND deleted_at IS NULL;
 
 	CREATE TABLE IF NOT EXISTS inventory.stock_adjustments (
 		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
@@ -1164,6 +1459,7 @@ var inventorySchemaSQL = `
 		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
 		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
 		deleted_at TIMESTAMPTZ,
+		anonymized_at TIMESTAMPTZ,
 		created_by UUID,
 		updated_by UUID
 	);
@@ -1230,6 +1526,9 @@ var inventorySchemaSQL = `
 		file_path TEXT NOT NULL,
 		file_size_bytes INTEGER,
 		mime_type VARCHAR(100),
+		sha256 CHAR(64),
+		scan_status VARCHAR(20) NOT NULL DEFAULT 'pending',
+		scanned_at TIMESTAMPTZ,
 		uploaded_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
 		uploaded_by UUID,
 		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
@@ -1259,4 +1558,786 @@ var inventorySchemaSQL = `
 	CREATE POLICY tenant_isolation ON inventory.user_cache
 		FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
 		WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+	-- Suppliers and purchase orders (000031)
+	CREATE TABLE IF NOT EXISTS inventory.suppliers (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+		name VARCHAR(255) NOT NULL,
+		customer_number VARCHAR(100),
+		contact_name VARCHAR(255),
+		email VARCHAR(255),
+		phone VARCHAR(50),
+		fax VARCHAR(50),
+		website TEXT,
+		address TEXT,
+		lead_time_days INTEGER NOT NULL DEFAULT 0,
+		min_order_value_cents INTEGER,
+		notes TEXT,
+		is_active BOOLEAN NOT NULL DEFAULT TRUE,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		deleted_at TIMESTAMPTZ,
+		created_by UUID,
+		updated_by UUID,
+		CONSTRAINT suppliers_lead_time_valid CHECK (lead_time_days >= 0)
+	);
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_suppliers_name ON inventory.suppliers(tenant_id, lower(name))
+		WHERE deleted_at IS NULL;
+	ALTER TABLE inventory.suppliers ENABLE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS tenant_isolation ON inventory.suppliers;
+	CREATE POLICY tenant_isolation ON inventory.suppliers
+		FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+		WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+	CREATE TABLE IF NOT EXISTS inventory.supplier_items (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+		supplier_id UUID NOT NULL REFERENCES inventory.suppliers(id) ON DELETE CASCADE,
+		item_id UUID NOT NULL REFERENCES inventory.inventory_items(id) ON DELETE CASCADE,
+		supplier_article_number VARCHAR(100),
+		unit_price_cents INTEGER,
+		pack_size INTEGER NOT NULL DEFAULT 1,
+		is_preferred BOOLEAN NOT NULL DEFAULT FALSE,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		CONSTRAINT supplier_items_unique UNIQUE (supplier_id, item_id),
+		CONSTRAINT supplier_items_pack_size_valid CHECK (pack_size > 0)
+	);
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_supplier_items_preferred ON inventory.supplier_items(item_id)
+		WHERE is_preferred;
+	ALTER TABLE inventory.supplier_items ENABLE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS tenant_isolation ON inventory.supplier_items;
+	CREATE POLICY tenant_isolation ON inventory.supplier_items
+		FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+		WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+	CREATE TABLE IF NOT EXISTS inventory.purchase_orders (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+		order_number VARCHAR(50) NOT NULL,
+		supplier_id UUID NOT NULL REFERENCES inventory.suppliers(id),
+		status VARCHAR(30) NOT NULL DEFAULT 'draft',
+		source VARCHAR(20) NOT NULL DEFAULT 'manual',
+		expected_date DATE,
+		sent_at TIMESTAMPTZ,
+		received_at TIMESTAMPTZ,
+		cancelled_at TIMESTAMPTZ,
+		cancel_reason TEXT,
+		notes TEXT,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		deleted_at TIMESTAMPTZ,
+		created_by UUID,
+		updated_by UUID,
+		CONSTRAINT purchase_orders_number_unique UNIQUE (tenant_id, order_number),
+		CONSTRAINT purchase_orders_status_valid CHECK (status IN ('draft', 'sent', 'partially_received', 'received', 'cancelled')),
+		CONSTRAINT purchase_orders_source_valid CHECK (source IN ('manual', 'suggestion'))
+	);
+	ALTER TABLE inventory.purchase_orders ENABLE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS tenant_isolation ON inventory.purchase_orders;
+	CREATE POLICY tenant_isolation ON inventory.purchase_orders
+		FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+		WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+	CREATE TABLE IF NOT EXISTS inventory.purchase_order_lines (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+		purchase_order_id UUID NOT NULL REFERENCES inventory.purchase_orders(id) ON DELETE CASCADE,
+		item_id UUID NOT NULL REFERENCES inventory.inventory_items(id) ON DELETE RESTRICT,
+		quantity_ordered INTEGER NOT NULL,
+		quantity_received INTEGER NOT NULL DEFAULT 0,
+		unit_price_cents INTEGER,
+		supplier_article_number VARCHAR(100),
+		notes TEXT,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		CONSTRAINT purchase_order_lines_quantity_valid CHECK (quantity_ordered > 0),
+		CONSTRAINT purchase_order_lines_received_valid CHECK (quantity_received >= 0 AND quantity_received <= quantity_ordered)
+	);
+	ALTER TABLE inventory.purchase_order_lines ENABLE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS tenant_isolation ON inventory.purchase_order_lines;
+	CREATE POLICY tenant_isolation ON inventory.purchase_order_lines
+		FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+		WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+	CREATE TABLE IF NOT EXISTS inventory.goods_receipts (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+		purchase_order_id UUID NOT NULL REFERENCES inventory.purchase_orders(id),
+		delivery_note_number VARCHAR(100),
+		received_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		received_by UUID,
+		received_by_name VARCHAR(255),
+		notes TEXT,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);
+	ALTER TABLE inventory.goods_receipts ENABLE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS tenant_isolation ON inventory.goods_receipts;
+	CREATE POLICY tenant_isolation ON inventory.goods_receipts
+		FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+		WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+	CREATE TABLE IF NOT EXISTS inventory.goods_receipt_lines (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+		goods_receipt_id UUID NOT NULL REFERENCES inventory.goods_receipts(id) ON DELETE CASCADE,
+		purchase_order_line_id UUID NOT NULL REFERENCES inventory.purchase_order_lines(id),
+		item_id UUID NOT NULL REFERENCES inventory.inventory_items(id),
+		batch_id UUID NOT NULL REFERENCES inventory.inventory_batches(id),
+		quantity INTEGER NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		CONSTRAINT goods_receipt_lines_quantity_valid CHECK (quantity > 0)
+	);
+	ALTER TABLE inventory.goods_receipt_lines ENABLE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS tenant_isolation ON inventory.goods_receipt_lines;
+	CREATE POLICY tenant_isolation ON inventory.goods_receipt_lines
+		FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+		WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+	CREATE TABLE IF NOT EXISTS inventory.stocktakes (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+		stocktake_number VARCHAR(50) NOT NULL,
+		title VARCHAR(255) NOT NULL,
+		status VARCHAR(20) NOT NULL DEFAULT 'counting',
+		blind BOOLEAN NOT NULL DEFAULT TRUE,
+		location_ids UUID[] NOT NULL DEFAULT '{}',
+		notes TEXT,
+		started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		started_by UUID,
+		started_by_name VARCHAR(255),
+		review_at TIMESTAMPTZ,
+		approved_at TIMESTAMPTZ,
+		approved_by UUID,
+		approved_by_name VARCHAR(255),
+		cancelled_at TIMESTAMPTZ,
+		variance_value_cents BIGINT,
+		protocol_sha256 CHAR(64),
+		protocol_seal CHAR(64),
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		CONSTRAINT stocktakes_number_unique UNIQUE (tenant_id, stocktake_number),
+		CONSTRAINT stocktakes_status_valid CHECK (status IN ('counting', 'review', 'approved', 'cancelled'))
+	);
+	ALTER TABLE inventory.stocktakes ENABLE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS tenant_isolation ON inventory.stocktakes;
+	CREATE POLICY tenant_isolation ON inventory.stocktakes
+		FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+		WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+	CREATE TABLE IF NOT EXISTS inventory.stocktake_lines (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+		stocktake_id UUID NOT NULL REFERENCES inventory.stocktakes(id) ON DELETE CASCADE,
+		item_id UUID NOT NULL REFERENCES inventory.inventory_items(id),
+		batch_id UUID NOT NULL REFERENCES inventory.inventory_batches(id),
+		location_id UUID,
+		expected_quantity INTEGER NOT NULL,
+		unit_price_cents INTEGER NOT NULL DEFAULT 0,
+		counted_quantity INTEGER,
+		last_counted_at TIMESTAMPTZ,
+		adjustment_id UUID REFERENCES inventory.stock_adjustments(id),
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		CONSTRAINT stocktake_lines_batch_unique UNIQUE (stocktake_id, batch_id),
+		CONSTRAINT stocktake_lines_counted_valid CHECK (counted_quantity IS NULL OR counted_quantity >= 0)
+	);
+	ALTER TABLE inventory.stocktake_lines ENABLE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS tenant_isolation ON inventory.stocktake_lines;
+	CREATE POLICY tenant_isolation ON inventory.stocktake_lines
+		FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+		WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+	CREATE TABLE IF NOT EXISTS inventory.stocktake_counts (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+		stocktake_id UUID NOT NULL REFERENCES inventory.stocktakes(id) ON DELETE CASCADE,
+		line_id UUID NOT NULL REFERENCES inventory.stocktake_lines(id) ON DELETE CASCADE,
+		quantity INTEGER NOT NULL,
+		method VARCHAR(20) NOT NULL DEFAULT 'manual',
+		counted_by UUID,
+		counted_by_name VARCHAR(255),
+		counted_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		CONSTRAINT stocktake_counts_method_valid CHECK (method IN ('manual', 'scan'))
+	);
+	ALTER TABLE inventory.stocktake_counts ENABLE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS tenant_isolation ON inventory.stocktake_counts;
+	CREATE POLICY tenant_isolation ON inventory.stocktake_counts
+		FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+		WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+	CREATE TABLE IF NOT EXISTS inventory.field_safety_notices (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+		notice_number VARCHAR(100) NOT NULL,
+		notice_type VARCHAR(30) NOT NULL,
+		severity VARCHAR(20) NOT NULL,
+		title VARCHAR(500) NOT NULL,
+		description TEXT,
+		manufacturer VARCHAR(255),
+		affected_product VARCHAR(500),
+		affected_batch_numbers TEXT,
+		affected_udi_dis TEXT,
+		affected_serial_numbers TEXT,
+		source VARCHAR(50),
+		source_url TEXT,
+		notice_date DATE,
+		received_date DATE NOT NULL,
+		status VARCHAR(30) NOT NULL DEFAULT 'open',
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		deleted_at TIMESTAMPTZ,
+		created_by UUID,
+		updated_by UUID
+	);
+	ALTER TABLE inventory.field_safety_notices ENABLE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS tenant_isolation ON inventory.field_safety_notices;
+	CREATE POLICY tenant_isolation ON inventory.field_safety_notices
+		FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+		WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+	CREATE TABLE IF NOT EXISTS inventory.recall_matches (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+		notice_id UUID NOT NULL REFERENCES inventory.field_safety_notices(id),
+		item_id UUID NOT NULL REFERENCES inventory.inventory_items(id),
+		batch_id UUID REFERENCES inventory.inventory_batches(id),
+		match_type VARCHAR(30) NOT NULL,
+		matched_value VARCHAR(500),
+		action_taken TEXT,
+		action_date TIMESTAMPTZ,
+		action_by UUID,
+		status VARCHAR(30) NOT NULL DEFAULT 'pending',
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		deleted_at TIMESTAMPTZ,
+		created_by UUID,
+		updated_by UUID
+	);
+	ALTER TABLE inventory.recall_matches ENABLE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS tenant_isolation ON inventory.recall_matches;
+	CREATE POLICY tenant_isolation ON inventory.recall_matches
+		FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+		WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+	CREATE TABLE IF NOT EXISTS inventory.stock_reservations (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+		item_id UUID NOT NULL REFERENCES inventory.inventory_items(id),
+		quantity INTEGER NOT NULL,
+		consumed_quantity INTEGER NOT NULL DEFAULT 0,
+		status VARCHAR(20) NOT NULL DEFAULT 'active',
+		procedure_name VARCHAR(255),
+		needed_at TIMESTAMPTZ,
+		reference_type VARCHAR(50),
+		reference_id UUID,
+		notes TEXT,
+		reserved_by UUID,
+		reserved_by_name VARCHAR(255),
+		consumed_at TIMESTAMPTZ,
+		released_at TIMESTAMPTZ,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		CONSTRAINT stock_reservations_quantity_valid CHECK (quantity > 0),
+		CONSTRAINT stock_reservations_consumed_valid CHECK (consumed_quantity >= 0 AND consumed_quantity <= quantity),
+		CONSTRAINT stock_reservations_status_valid CHECK (status IN ('active', 'consumed', 'released'))
+	);
+	ALTER TABLE inventory.stock_reservations ENABLE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS tenant_isolation ON inventory.stock_reservations;
+	CREATE POLICY tenant_isolation ON inventory.stock_reservations
+		FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+		WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+	CREATE TABLE IF NOT EXISTS inventory.stock_reservation_batches (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+		reservation_id UUID NOT NULL REFERENCES inventory.stock_reservations(id) ON DELETE CASCADE,
+		batch_id UUID NOT NULL REFERENCES inventory.inventory_batches(id),
+		position INTEGER NOT NULL,
+		quantity INTEGER NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		CONSTRAINT stock_reservation_batches_unique UNIQUE (reservation_id, batch_id),
+		CONSTRAINT stock_reservation_batches_quantity_valid CHECK (quantity > 0)
+	);
+	ALTER TABLE inventory.stock_reservation_batches ENABLE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS tenant_isolation ON inventory.stock_reservation_batches;
+	CREATE POLICY tenant_isolation ON inventory.stock_reservation_batches
+		FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+		WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+	CREATE TABLE IF NOT EXISTS inventory.procedure_kits (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+		name VARCHAR(255) NOT NULL,
+		description TEXT,
+		is_active BOOLEAN NOT NULL DEFAULT TRUE,
+		created_by UUID,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		deleted_at TIMESTAMPTZ
+	);
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_procedure_kits_name ON inventory.procedure_kits(tenant_id, name)
+		WHERE deleted_at IS NULL;
+	ALTER TABLE inventory.procedure_kits ENABLE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS tenant_isolation ON inventory.procedure_kits;
+	CREATE POLICY tenant_isolation ON inventory.procedure_kits
+		FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+		WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+	CREATE TABLE IF NOT EXISTS inventory.procedure_kit_items (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+		kit_id UUID NOT NULL REFERENCES inventory.procedure_kits(id) ON DELETE CASCADE,
+		item_id UUID NOT NULL REFERENCES inventory.inventory_items(id),
+		quantity INTEGER NOT NULL,
+		position INTEGER NOT NULL DEFAULT 0,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		CONSTRAINT procedure_kit_items_unique UNIQUE (kit_id, item_id),
+		CONSTRAINT procedure_kit_items_quantity_valid CHECK (quantity > 0)
+	);
+	ALTER TABLE inventory.procedure_kit_items ENABLE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS tenant_isolation ON inventory.procedure_kit_items;
+	CREATE POLICY tenant_isolation ON inventory.procedure_kit_items
+		FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+		WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+	CREATE TABLE IF NOT EXISTS inventory.kit_consumptions (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+		kit_id UUID NOT NULL REFERENCES inventory.procedure_kits(id),
+		kit_name VARCHAR(255) NOT NULL,
+		kit_count INTEGER NOT NULL DEFAULT 1,
+		reference_type VARCHAR(50),
+		reference_id UUID,
+		notes TEXT,
+		performed_by VARCHAR(255),
+		performed_by_name VARCHAR(255),
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		CONSTRAINT kit_consumptions_count_valid CHECK (kit_count > 0)
+	);
+	ALTER TABLE inventory.kit_consumptions ENABLE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS tenant_isolation ON inventory.kit_consumptions;
+	CREATE POLICY tenant_isolation ON inventory.kit_consumptions
+		FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+		WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+	CREATE TABLE IF NOT EXISTS inventory.kit_consumption_lines (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+		consumption_id UUID NOT NULL REFERENCES inventory.kit_consumptions(id) ON DELETE CASCADE,
+		item_id UUID NOT NULL REFERENCES inventory.inventory_items(id),
+		batch_id UUID NOT NULL REFERENCES inventory.inventory_batches(id),
+		quantity INTEGER NOT NULL,
+		adjustment_id UUID NOT NULL REFERENCES inventory.stock_adjustments(id),
+		CONSTRAINT kit_consumption_lines_quantity_valid CHECK (quantity > 0)
+	);
+	ALTER TABLE inventory.kit_consumption_lines ENABLE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS tenant_isolation ON inventory.kit_consumption_lines;
+	CREATE POLICY tenant_isolation ON inventory.kit_consumption_lines
+		FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+		WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+	CREATE TABLE IF NOT EXISTS inventory.consumption_forecasts (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+		item_id UUID NOT NULL REFERENCES inventory.inventory_items(id) ON DELETE CASCADE,
+		history_days INTEGER NOT NULL,
+		daily_demand DOUBLE PRECISION NOT NULL,
+		weekday_factors DOUBLE PRECISION[] NOT NULL,
+		std_dev DOUBLE PRECISION NOT NULL,
+		lead_time_days INTEGER NOT NULL,
+		service_level NUMERIC(4,3) NOT NULL,
+		coverage_days INTEGER NOT NULL,
+		lead_time_demand DOUBLE PRECISION NOT NULL,
+		safety_stock DOUBLE PRECISION NOT NULL,
+		suggested_reorder_point INTEGER NOT NULL,
+		suggested_reorder_quantity INTEGER NOT NULL,
+		current_stock INTEGER NOT NULL,
+		projected_stockout_date DATE,
+		computed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		accepted_at TIMESTAMPTZ,
+		accepted_by UUID,
+		CONSTRAINT consumption_forecasts_item_unique UNIQUE (tenant_id, item_id)
+	);
+	ALTER TABLE inventory.consumption_forecasts ENABLE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS tenant_isolation ON inventory.consumption_forecasts;
+	CREATE POLICY tenant_isolation ON inventory.consumption_forecasts
+		FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+		WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+	CREATE TABLE IF NOT EXISTS inventory.label_templates (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+		name VARCHAR(255) NOT NULL,
+		kind VARCHAR(20) NOT NULL,
+		width_mm NUMERIC(5,1) NOT NULL,
+		height_mm NUMERIC(5,1) NOT NULL,
+		dpi INTEGER NOT NULL DEFAULT 203,
+		symbology VARCHAR(20) NOT NULL,
+		sheet_columns INTEGER NOT NULL DEFAULT 0,
+		sheet_rows INTEGER NOT NULL DEFAULT 0,
+		is_default BOOLEAN NOT NULL DEFAULT FALSE,
+		created_by UUID,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		deleted_at TIMESTAMPTZ,
+		CONSTRAINT label_templates_kind_valid CHECK (kind IN ('batch', 'shelf', 'device'))
+	);
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_label_templates_name ON inventory.label_templates(tenant_id, name)
+		WHERE deleted_at IS NULL;
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_label_templates_default ON inventory.label_templates(tenant_id, kind)
+		WHERE is_default AND deleted_at IS NULL;
+	ALTER TABLE inventory.label_templates ENABLE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS tenant_isolation ON inventory.label_templates;
+	CREATE POLICY tenant_isolation ON inventory.label_templates
+		FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+		WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+	-- Retention policies (000021, 000037 - retention enforcement, 000049 - moved to public)
+	CREATE TABLE IF NOT EXISTS public.retention_policies (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+		entity_type VARCHAR(50) NOT NULL,
+		retention_years INTEGER NOT NULL,
+		legal_basis TEXT,
+		description TEXT,
+		purge_action VARCHAR(20) NOT NULL DEFAULT 'none',
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		deleted_at TIMESTAMPTZ,
+		created_by UUID,
+		updated_by UUID,
+		UNIQUE(tenant_id, entity_type),
+		CONSTRAINT retention_policies_purge_action_valid CHECK (purge_action IN ('none', 'delete', 'anonymize'))
+	);
+	ALTER TABLE public.retention_policies ENABLE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS tenant_isolation ON public.retention_policies;
+	CREATE POLICY tenant_isolation ON public.retention_policies
+		FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+		WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+	-- Legal holds (000037 - retention enforcement, 000049 - moved to public)
+	CREATE TABLE IF NOT EXISTS public.legal_holds (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+		entity_type VARCHAR(50) NOT NULL,
+		entity_id UUID,
+		reason TEXT NOT NULL,
+		reference VARCHAR(255),
+		created_by UUID,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		released_at TIMESTAMPTZ,
+		released_by UUID,
+		release_note TEXT
+	);
+	ALTER TABLE public.legal_holds ENABLE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS tenant_isolation ON public.legal_holds;
+	CREATE POLICY tenant_isolation ON public.legal_holds
+		FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+		WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+	-- Deletion protocols (000037 - retention enforcement, 000049 - moved to public)
+	CREATE TABLE IF NOT EXISTS public.deletion_protocols (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+		entity_type VARCHAR(50) NOT NULL,
+		table_name VARCHAR(100) NOT NULL,
+		action VARCHAR(20) NOT NULL,
+		legal_basis TEXT,
+		retention_years INTEGER NOT NULL,
+		cutoff_date DATE NOT NULL,
+		record_count INTEGER NOT NULL,
+		record_ids UUID[] NOT NULL,
+		executed_by UUID,
+		executed_by_name VARCHAR(255),
+		executed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);
+	ALTER TABLE public.deletion_protocols ENABLE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS tenant_isolation ON public.deletion_protocols;
+	CREATE POLICY tenant_isolation ON public.deletion_protocols
+		FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+		WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+	-- GoBD audit trail (000017, 000038 - hash chain), append-only
+	CREATE TABLE IF NOT EXISTS inventory.audit_trail (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+		entity_type VARCHAR(50) NOT NULL,
+		entity_id UUID NOT NULL,
+		action VARCHAR(50) NOT NULL,
+		field_changes JSONB,
+		metadata JSONB,
+		performed_by UUID,
+		performed_by_name VARCHAR(255),
+		ip_address VARCHAR(45),
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		sequence BIGINT,
+		prev_hash CHAR(64),
+		hash CHAR(64),
+		CONSTRAINT audit_trail_entity_type_valid CHECK (
+			entity_type IN (
+				'item', 'batch', 'alert', 'hazardous', 'inspection',
+				'training', 'incident', 'temperature', 'document',
+				'supplier', 'purchase_order', 'stocktake', 'reservation', 'procedure_kit',
+				'label_template', 'retention_policy', 'legal_hold', 'gdpdu_export',
+				'btm', 'btm_period_closing', 'recall_match', 'field_safety_notice',
+				'temperature_sensor', 'temperature_excursion',
+				'radiation_device', 'expert_inspection', 'constancy_test',
+				'staff_radiation_certification', 'dosimetry_record',
+				'sterilization_batch', 'reprocessing_cycle', 'hygiene_plan', 'hygiene_inspection',
+				'bio_risk_assessment', 'bio_training'
+			)
+		),
+		CONSTRAINT audit_trail_action_valid CHECK (
+			action IN (
+				'create', 'update', 'delete', 'adjust',
+				'open', 'btm_receipt', 'btm_dispense', 'btm_disposal',
+				'btm_correction', 'btm_check', 'recall_match', 'recall_resolve',
+				'btm_inventory_check', 'btm_period_closed', 'resolve', 'transfer',
+				'send', 'receive', 'cancel', 'set_item', 'remove_item',
+				'finish_counting', 'reopen', 'approve', 'consume', 'release',
+				'request', 'download', 'rotate_secret', 'revoke',
+				'quarantine', 'released', 'discarded'
+			)
+		)
+	);
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_audit_trail_tenant_sequence ON inventory.audit_trail(tenant_id, sequence)
+		WHERE sequence IS NOT NULL;
+	ALTER TABLE inventory.audit_trail ENABLE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS audit_trail_select ON inventory.audit_trail;
+	CREATE POLICY audit_trail_select ON inventory.audit_trail
+		FOR SELECT USING (tenant_id = current_setting('app.current_tenant')::uuid);
+	DROP POLICY IF EXISTS audit_trail_insert ON inventory.audit_trail;
+	CREATE POLICY audit_trail_insert ON inventory.audit_trail
+		FOR INSERT WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+	-- GDPdU data exports (000039)
+	CREATE TABLE IF NOT EXISTS inventory.gdpdu_exports (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+		period_from DATE NOT NULL,
+		period_to DATE NOT NULL,
+		status VARCHAR(20) NOT NULL DEFAULT 'pending',
+		file_key TEXT,
+		file_size BIGINT,
+		sha256 CHAR(64),
+		record_count BIGINT,
+		manifest JSONB,
+		error TEXT,
+		requested_by UUID,
+		requested_by_name VARCHAR(255),
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		started_at TIMESTAMPTZ,
+		completed_at TIMESTAMPTZ,
+		CONSTRAINT gdpdu_exports_period_valid CHECK (period_to >= period_from),
+		CONSTRAINT gdpdu_exports_status_valid CHECK (status IN ('pending', 'running', 'completed', 'failed'))
+	);
+	ALTER TABLE inventory.gdpdu_exports ENABLE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS tenant_isolation ON inventory.gdpdu_exports;
+	CREATE POLICY tenant_isolation ON inventory.gdpdu_exports
+		FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+		WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+	-- Cold-chain sensors and excursions (000043)
+	CREATE TABLE IF NOT EXISTS inventory.temperature_sensors (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+		cabinet_id UUID NOT NULL REFERENCES inventory.storage_cabinets(id) ON DELETE CASCADE,
+		name VARCHAR(255) NOT NULL,
+		key_id VARCHAR(64) NOT NULL UNIQUE,
+		secret VARCHAR(128) NOT NULL,
+		last_seen_at TIMESTAMPTZ,
+		revoked_at TIMESTAMPTZ,
+		created_by UUID,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);
+	ALTER TABLE inventory.temperature_sensors ENABLE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS tenant_isolation ON inventory.temperature_sensors;
+	CREATE POLICY tenant_isolation ON inventory.temperature_sensors
+		FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+		WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+	ALTER TABLE inventory.temperature_readings
+		ADD COLUMN IF NOT EXISTS sensor_id UUID REFERENCES inventory.temperature_sensors(id);
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_temperature_readings_sensor_dedup
+		ON inventory.temperature_readings(sensor_id, recorded_at)
+		WHERE sensor_id IS NOT NULL;
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_temperature_readings_unsigned_dedup
+		ON inventory.temperature_readings(cabinet_id, recorded_at)
+		WHERE sensor_id IS NULL AND source IN ('webhook', 'sensor');
+
+	CREATE TABLE IF NOT EXISTS inventory.temperature_excursions (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+		cabinet_id UUID NOT NULL REFERENCES inventory.storage_cabinets(id) ON DELETE CASCADE,
+		status VARCHAR(20) NOT NULL DEFAULT 'pending',
+		started_at TIMESTAMPTZ NOT NULL,
+		ended_at TIMESTAMPTZ,
+		last_reading_at TIMESTAMPTZ NOT NULL,
+		duration_seconds INTEGER NOT NULL DEFAULT 0,
+		reading_count INTEGER NOT NULL DEFAULT 0,
+		min_temperature_celsius DECIMAL(5,2) NOT NULL,
+		max_temperature_celsius DECIMAL(5,2) NOT NULL,
+		allowed_min_celsius DECIMAL(5,2),
+		allowed_max_celsius DECIMAL(5,2),
+		grace_minutes INTEGER NOT NULL,
+		confirmed_at TIMESTAMPTZ,
+		alert_id UUID,
+		decision_reason TEXT,
+		decided_by UUID,
+		decided_at TIMESTAMPTZ,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		CONSTRAINT temperature_excursions_status_valid CHECK (
+			status IN ('pending', 'transient', 'confirmed', 'released', 'discarded')
+		),
+		CONSTRAINT temperature_excursions_decision_documented CHECK (
+			status NOT IN ('released', 'discarded')
+			OR (decision_reason IS NOT NULL AND decided_by IS NOT NULL AND decided_at IS NOT NULL)
+		)
+	);
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_temperature_excursions_ongoing
+		ON inventory.temperature_excursions(cabinet_id) WHERE ended_at IS NULL;
+	ALTER TABLE inventory.temperature_excursions ENABLE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS tenant_isolation ON inventory.temperature_excursions;
+	CREATE POLICY tenant_isolation ON inventory.temperature_excursions
+		FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+		WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+	CREATE TABLE IF NOT EXISTS inventory.temperature_excursion_batches (
+		excursion_id UUID NOT NULL REFERENCES inventory.temperature_excursions(id) ON DELETE CASCADE,
+		batch_id UUID NOT NULL REFERENCES inventory.inventory_batches(id) ON DELETE CASCADE,
+		tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+		previous_status VARCHAR(20) NOT NULL,
+		quarantined_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		PRIMARY KEY (excursion_id, batch_id)
+	);
+	ALTER TABLE inventory.temperature_excursion_batches ENABLE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS tenant_isolation ON inventory.temperature_excursion_batches;
+	CREATE POLICY tenant_isolation ON inventory.temperature_excursion_batches
+		FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+		WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+	-- BtM register and authorized personnel (000018), entry dates and monthly closings (000045)
+	CREATE TABLE IF NOT EXISTS inventory.btm_register (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+		item_id UUID NOT NULL REFERENCES inventory.inventory_items(id),
+		entry_number INTEGER NOT NULL,
+		entry_type VARCHAR(30) NOT NULL,
+		entry_date DATE NOT NULL,
+		quantity DECIMAL(12,4) NOT NULL,
+		running_balance DECIMAL(12,4) NOT NULL,
+		unit VARCHAR(50) NOT NULL,
+		supplier_name VARCHAR(255),
+		delivery_note_number VARCHAR(100),
+		patient_identifier VARCHAR(255),
+		prescribing_doctor VARCHAR(255),
+		purpose TEXT,
+		disposal_method VARCHAR(100),
+		disposal_witness VARCHAR(255),
+		correction_reason TEXT,
+		corrects_entry_id UUID REFERENCES inventory.btm_register(id),
+		performed_by UUID NOT NULL,
+		performed_by_name VARCHAR(255) NOT NULL,
+		notes TEXT,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		CONSTRAINT btm_register_entry_type_valid CHECK (
+			entry_type IN ('receipt', 'dispense', 'disposal', 'correction', 'inventory_check')
+		),
+		CONSTRAINT btm_register_entry_number_unique UNIQUE (tenant_id, item_id, entry_number)
+	);
+	CREATE INDEX IF NOT EXISTS idx_btm_register_item_date
+		ON inventory.btm_register(item_id, entry_date, entry_number);
+	ALTER TABLE inventory.btm_register ENABLE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS btm_register_select ON inventory.btm_register;
+	CREATE POLICY btm_register_select ON inventory.btm_register
+		FOR SELECT USING (tenant_id = current_setting('app.current_tenant')::uuid);
+	DROP POLICY IF EXISTS btm_register_insert ON inventory.btm_register;
+	CREATE POLICY btm_register_insert ON inventory.btm_register
+		FOR INSERT WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+	CREATE TABLE IF NOT EXISTS inventory.btm_authorized_personnel (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+		user_id UUID NOT NULL,
+		user_name VARCHAR(255) NOT NULL,
+		authorization_type VARCHAR(30) NOT NULL,
+		authorized_by UUID,
+		authorized_by_name VARCHAR(255),
+		authorized_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		revoked_at TIMESTAMPTZ,
+		revoked_by UUID,
+		revoked_by_name VARCHAR(255),
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		deleted_at TIMESTAMPTZ,
+		created_by UUID,
+		updated_by UUID,
+		CONSTRAINT btm_auth_type_valid CHECK (
+			authorization_type IN ('full', 'dispense_only', 'view_only')
+		)
+	);
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_btm_authorized_active
+		ON inventory.btm_authorized_personnel(tenant_id, user_id)
+		WHERE revoked_at IS NULL;
+	ALTER TABLE inventory.btm_authorized_personnel ENABLE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS tenant_isolation ON inventory.btm_authorized_personnel;
+	CREATE POLICY tenant_isolation ON inventory.btm_authorized_personnel
+		FOR ALL USING (tenant_id = current_setting('app.current_tenant')::uuid)
+		WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+	CREATE TABLE IF NOT EXISTS inventory.btm_period_closings (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+		period_start DATE NOT NULL,
+		period_end DATE NOT NULL,
+		substance_count INTEGER NOT NULL DEFAULT 0,
+		difference_count INTEGER NOT NULL DEFAULT 0,
+		notes TEXT,
+		closed_by UUID NOT NULL,
+		closed_by_name VARCHAR(255) NOT NULL,
+		closed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		protocol_sha256 CHAR(64) NOT NULL,
+		CONSTRAINT btm_period_closings_period_valid CHECK (period_end >= period_start),
+		CONSTRAINT btm_period_closings_differences_explained CHECK (
+			difference_count = 0 OR (notes IS NOT NULL AND notes <> '')
+		),
+		CONSTRAINT btm_period_closings_period_unique UNIQUE (tenant_id, period_start)
+	);
+	ALTER TABLE inventory.btm_period_closings ENABLE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS btm_period_closings_select ON inventory.btm_period_closings;
+	CREATE POLICY btm_period_closings_select ON inventory.btm_period_closings
+		FOR SELECT USING (tenant_id = current_setting('app.current_tenant')::uuid);
+	DROP POLICY IF EXISTS btm_period_closings_insert ON inventory.btm_period_closings;
+	CREATE POLICY btm_period_closings_insert ON inventory.btm_period_closings
+		FOR INSERT WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
+
+	CREATE TABLE IF NOT EXISTS inventory.btm_period_balances (
+		closing_id UUID NOT NULL REFERENCES inventory.btm_period_closings(id),
+		tenant_id UUID NOT NULL REFERENCES public.tenants(id),
+		item_id UUID NOT NULL REFERENCES inventory.inventory_items(id),
+		item_name VARCHAR(255) NOT NULL,
+		unit VARCHAR(50) NOT NULL,
+		opening_balance DECIMAL(12,4) NOT NULL,
+		receipts DECIMAL(12,4) NOT NULL,
+		dispensed DECIMAL(12,4) NOT NULL,
+		disposed DECIMAL(12,4) NOT NULL,
+		corrections DECIMAL(12,4) NOT NULL,
+		check_difference DECIMAL(12,4) NOT NULL,
+		closing_balance DECIMAL(12,4) NOT NULL,
+		entry_count INTEGER NOT NULL,
+		check_count INTEGER NOT NULL,
+		difference_count INTEGER NOT NULL,
+		PRIMARY KEY (closing_id, item_id)
+	);
+	ALTER TABLE inventory.btm_period_balances ENABLE ROW LEVEL SECURITY;
+	DROP POLICY IF EXISTS btm_period_balances_select ON inventory.btm_period_balances;
+	CREATE POLICY btm_period_balances_select ON inventory.btm_period_balances
+		FOR SELECT USING (tenant_id = current_setting('app.current_tenant')::uuid);
+	DROP POLICY IF EXISTS btm_period_balances_insert ON inventory.btm_period_balances;
+	CREATE POLICY btm_period_balances_insert ON inventory.btm_period_balances
+		FOR INSERT WITH CHECK (tenant_id = current_setting('app.current_tenant')::uuid);
 `
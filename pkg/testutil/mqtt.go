@@ -0,0 +1,58 @@
+package testutil
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// MosquittoContainer wraps a testcontainers Eclipse Mosquitto MQTT broker
+type MosquittoContainer struct {
+	testcontainers.Container
+	BrokerURL string // tcp://host:port
+}
+
+// NewMosquittoContainer starts an MQTT broker that accepts anonymous
+// clients, for integration tests of MQTT subscribers.
+//
+// Usage:
+//
+//	broker, err := testutil.NewMosquittoContainer(ctx)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer broker.Terminate(ctx)
+func NewMosquittoContainer(ctx context.Context) (*MosquittoContainer, error) {
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        "eclipse-mosquitto:2",
+			ExposedPorts: []string{"1883/tcp"},
+			// Mosquitto 2 only listens on localhost without a config; the
+			// image ships one that allows anonymous clients on 1883
+			Cmd:        []string{"mosquitto", "-c", "/mosquitto-no-auth.conf"},
+			WaitingFor: wait.ForListeningPort("1883/tcp"),
+		},
+		Started: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start mosquitto container: %w", err)
+	}
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		container.Terminate(ctx)
+		return nil, fmt.Errorf("failed to get mosquitto host: %w", err)
+	}
+	port, err := container.MappedPort(ctx, "1883/tcp")
+	if err != nil {
+		container.Terminate(ctx)
+		return nil, fmt.Errorf("failed to get mosquitto port: %w", err)
+	}
+
+	return &MosquittoContainer{
+		Container: container,
+		BrokerURL: fmt.Sprintf("tcp://%s:%s", host, port.Port()),
+	}, nil
+}
@@ -0,0 +1,214 @@
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// Credentials of the replication role created on the primary
+const (
+	replicationUser     = "replicator"
+	replicationPassword = "replicator"
+)
+
+// replicaEntrypoint clones the primary with pg_basebackup and starts a hot
+// standby that streams WAL from it (-R writes primary_conninfo and
+// standby.signal). The base backup copies roles, pg_hba.conf and
+// postgresql.conf, so the standby accepts the same logins as the primary.
+const replicaEntrypoint = `set -e
+as_postgres() { if command -v gosu >/dev/null; then gosu postgres "$@"; else su-exec postgres "$@"; fi; }
+mkdir -p "$PGDATA"
+chown postgres:postgres "$PGDATA"
+chmod 700 "$PGDATA"
+as_postgres pg_basebackup -h "$PRIMARY_HOST" -p 5432 -U ` + replicationUser + ` -D "$PGDATA" -R -X stream
+as_postgres postgres -c hot_standby=on`
+
+// ReplicaContainer is a hot standby streaming from a PostgresContainer.
+// Unlike a read-only session on the primary it has real replication lag, so
+// tests can observe the read-your-writes gap of replica routing.
+type ReplicaContainer struct {
+	testcontainers.Container
+	primary    *PostgresContainer
+	DSN        string // superuser, for replay control
+	AppRoleDSN string // medflow_app, for database.DB.AddReplica
+}
+
+// NewReplicaContainer starts a streaming replica of primary.
+// Call this after CreateAppRole: the medflow_app role reaches the standby
+// through replication like any other catalog change.
+func NewReplicaContainer(ctx context.Context, primary *PostgresContainer) (*ReplicaContainer, error) {
+	if err := primary.enableReplication(ctx); err != nil {
+		return nil, err
+	}
+
+	primaryHost, err := primary.ContainerIP(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get primary container IP: %w", err)
+	}
+
+	image := DefaultPostgresConfig().Image
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        image,
+			ExposedPorts: []string{"5432/tcp"},
+			Env: map[string]string{
+				"PRIMARY_HOST": primaryHost,
+				"PGPASSWORD":   replicationPassword,
+			},
+			Entrypoint: []string{"sh", "-c", replicaEntrypoint},
+			WaitingFor: wait.ForLog("database system is ready to accept read-only connections").
+				WithStartupTimeout(60 * time.Second),
+		},
+		Started: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start replica container: %w", err)
+	}
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		container.Terminate(ctx)
+		return nil, fmt.Errorf("failed to get replica host: %w", err)
+	}
+	port, err := container.MappedPort(ctx, "5432/tcp")
+	if err != nil {
+		container.Terminate(ctx)
+		return nil, fmt.Errorf("failed to get replica port: %w", err)
+	}
+
+	hostPort := net.JoinHostPort(host, port.Port())
+	replica := &ReplicaContainer{
+		Container: container,
+		primary:   primary,
+		DSN:       replaceHostInDSN(primary.DSN, hostPort),
+	}
+	if primary.AppRoleDSN != "" {
+		replica.AppRoleDSN = replaceHostInDSN(primary.AppRoleDSN, hostPort)
+	}
+
+	// The standby must have caught up with the schema before tests use it
+	if err := replica.WaitForReplay(ctx); err != nil {
+		container.Terminate(ctx)
+		return nil, err
+	}
+	return replica, nil
+}
+
+// enableReplication creates the replication role and allows it to connect
+// from other containers
+func (c *PostgresContainer) enableReplication(ctx context.Context) error {
+	db, err := c.Connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	role := fmt.Sprintf(`
+		DO $$
+		BEGIN
+			IF NOT EXISTS (SELECT FROM pg_roles WHERE rolname = '%s') THEN
+				CREATE ROLE %s WITH REPLICATION LOGIN PASSWORD '%s';
+			END IF;
+		END
+		$$;
+	`, replicationUser, replicationUser, replicationPassword)
+	if _, err := db.ExecContext(ctx, role); err != nil {
+		return fmt.Errorf("failed to create replication role: %w", err)
+	}
+
+	hba := fmt.Sprintf(`echo "host replication %s all scram-sha-256" >> "$PGDATA/pg_hba.conf"`, replicationUser)
+	code, _, err := c.Exec(ctx, []string{"sh", "-c", hba})
+	if err != nil {
+		return fmt.Errorf("failed to allow replication connections: %w", err)
+	}
+	if code != 0 {
+		return fmt.Errorf("failed to allow replication connections: exit code %d", code)
+	}
+
+	if _, err := db.ExecContext(ctx, `SELECT pg_reload_conf()`); err != nil {
+		return fmt.Errorf("failed to reload primary configuration: %w", err)
+	}
+	return nil
+}
+
+// PauseReplay stops applying WAL on the standby. Writes on the primary stay
+// invisible to replica reads until ResumeReplay, which makes the replica lag
+// deterministic in tests.
+func (r *ReplicaContainer) PauseReplay(ctx context.Context) error {
+	return r.exec(ctx, `SELECT pg_wal_replay_pause()`)
+}
+
+// ResumeReplay resumes applying WAL on the standby
+func (r *ReplicaContainer) ResumeReplay(ctx context.Context) error {
+	return r.exec(ctx, `SELECT pg_wal_replay_resume()`)
+}
+
+// WaitForReplay waits until the standby has replayed everything the primary
+// has written so far
+func (r *ReplicaContainer) WaitForReplay(ctx context.Context) error {
+	primary, err := r.primary.Connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer primary.Close()
+
+	standby, err := sqlx.ConnectContext(ctx, "postgres", r.DSN)
+	if err != nil {
+		return fmt.Errorf("failed to connect to replica: %w", err)
+	}
+	defer standby.Close()
+
+	var target string
+	if err := primary.GetContext(ctx, &target, `SELECT pg_current_wal_lsn()::text`); err != nil {
+		return fmt.Errorf("failed to read primary WAL position: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	for {
+		var caughtUp bool
+		err := standby.GetContext(ctx, &caughtUp,
+			`SELECT COALESCE(pg_last_wal_replay_lsn() >= $1::pg_lsn, FALSE)`, target)
+		if err != nil {
+			return fmt.Errorf("failed to read replica replay position: %w", err)
+		}
+		if caughtUp {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("replica did not catch up to %s: %w", target, ctx.Err())
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
+
+// exec runs a statement on the standby as superuser
+func (r *ReplicaContainer) exec(ctx context.Context, query string) error {
+	db, err := sqlx.ConnectContext(ctx, "postgres", r.DSN)
+	if err != nil {
+		return fmt.Errorf("failed to connect to replica: %w", err)
+	}
+	defer db.Close()
+
+	_, err = db.ExecContext(ctx, query)
+	return err
+}
+
+// replaceHostInDSN points a URL-format postgres DSN at another host:port
+func replaceHostInDSN(dsn, hostPort string) string {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return dsn
+	}
+	u.Host = hostPort
+	return u.String()
+}
@@ -17,6 +17,11 @@ var (
 	globalDB        *sqlx.DB
 	containerOnce   sync.Once
 	containerErr    error
+
+	// Streaming replica of the global container, started on first use
+	globalReplica *ReplicaContainer
+	replicaOnce   sync.Once
+	replicaErr    error
 )
 
 // IntegrationSuite provides a base for integration tests with real PostgreSQL
@@ -177,6 +182,50 @@ func (s *IntegrationSuite) SetupFullTenant(t *testing.T, ctx context.Context, na
 	return s.SetupTenant(t, ctx, name, migrations)
 }
 
+// Replica returns the streaming replica of the suite's container, starting
+// it on first use. Tests that pause replay must resume it before returning.
+func (s *IntegrationSuite) Replica(t *testing.T) *ReplicaContainer {
+	t.Helper()
+
+	replicaOnce.Do(func() {
+		globalReplica, replicaErr = NewReplicaContainer(context.Background(), s.Container)
+	})
+	if replicaErr != nil {
+		t.Fatalf("failed to start replica: %v", replicaErr)
+	}
+	return globalReplica
+}
+
+// NewReplicatedDB returns a database.DB with the suite's database as primary
+// and its streaming replica (see Replica) as read replica. Reads marked with
+// database.ReadOnly are routed to the replica; writes stay on the primary.
+// The replica lags behind: call Replica(t).WaitForReplay before reading
+// writes back through it.
+func (s *IntegrationSuite) NewReplicatedDB(t *testing.T, searchPath string) *database.DB {
+	t.Helper()
+
+	replica := s.Replica(t)
+
+	appDSN := s.Container.AppRoleDSN
+	if appDSN == "" {
+		appDSN = s.Container.DSN
+	}
+	db, err := database.NewWithDSNAndSearchPath(appDSN, searchPath, s.Logger)
+	if err != nil {
+		t.Fatalf("failed to connect primary: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	replicaDSN := replica.AppRoleDSN
+	if replicaDSN == "" {
+		replicaDSN = replica.DSN
+	}
+	if err := db.AddReplica(replicaDSN); err != nil {
+		t.Fatalf("failed to connect replica: %v", err)
+	}
+	return db
+}
+
 // TenantContext returns a context with the tenant set
 func (s *IntegrationSuite) TenantContext(tenant *TestTenant) context.Context {
 	return WithTestTenant(context.Background(), tenant)
@@ -194,6 +243,9 @@ func (s *IntegrationSuite) Cleanup(ctx context.Context) error {
 // TerminateContainer terminates the shared container.
 // Only call this in TestMain after all tests have completed.
 func TerminateContainer(ctx context.Context) {
+	if globalReplica != nil {
+		globalReplica.Terminate(ctx)
+	}
 	if globalContainer != nil {
 		globalContainer.Terminate(ctx)
 	}
@@ -94,9 +94,9 @@ func (tm *TenantManager) DropTenant(ctx context.Context, t *TestTenant) error {
 		schema string
 		tables []string
 	}{
-		{"inventory", []string{"temperature_readings", "device_incidents", "device_trainings", "device_inspections", "item_documents", "hazardous_substance_details", "inventory_alerts", "stock_adjustments", "inventory_batches", "inventory_items", "storage_shelves", "storage_cabinets", "storage_rooms", "user_cache"}},
-		{"staff", []string{"document_processing_audit", "time_correction_requests", "compliance_alerts", "compliance_violations", "compliance_settings", "arbzg_compliance_log", "time_corrections", "time_breaks", "time_entries", "vacation_balances", "absences", "shift_assignments", "shift_templates", "employee_files", "employee_documents", "employee_social_insurance", "employee_financials", "employee_contacts", "employee_addresses", "employees", "user_cache"}},
-		{"users", []string{"audit_logs", "user_roles", "sessions", "roles", "users"}},
+		{"inventory", []string{"gdpdu_exports", "audit_trail", "temperature_readings", "device_incidents", "device_trainings", "device_inspections", "item_documents", "hazardous_substance_details", "inventory_alerts", "stock_adjustments", "inventory_batches", "inventory_items", "storage_shelves", "storage_cabinets", "storage_rooms", "user_cache"}},
+		{"staff", []string{"audit_trail", "document_processing_audit", "time_correction_requests", "compliance_alerts", "compliance_violations", "compliance_settings", "arbzg_compliance_log", "time_corrections", "time_breaks", "time_entries", "vacation_balances", "absences", "shift_assignments", "shift_templates", "employee_files", "employee_documents", "employee_social_insurance", "employee_financials", "employee_contacts", "employee_addresses", "employees", "user_cache"}},
+		{"users", []string{"notification_inbox", "notification_deliveries", "notifications", "notification_rules", "notification_webhooks", "notification_settings", "audit_logs", "user_roles", "sessions", "roles", "users"}},
 	}
 
 	for _, s := range schemas {
@@ -112,6 +112,7 @@ func (tm *TenantManager) DropTenant(ctx context.Context, t *TestTenant) error {
 	// Remove from public tables
 	_, _ = tm.db.ExecContext(ctx, "DELETE FROM public.user_tenant_lookup WHERE tenant_id = $1", t.ID)
 	_, _ = tm.db.ExecContext(ctx, "DELETE FROM public.tenant_audit_log WHERE tenant_id = $1", t.ID)
+	_, _ = tm.db.ExecContext(ctx, "DELETE FROM public.job_runs WHERE tenant_id = $1", t.ID)
 	_, err := tm.db.ExecContext(ctx, "DELETE FROM public.tenants WHERE id = $1", t.ID)
 	if err != nil {
 		return fmt.Errorf("failed to delete tenant record: %w", err)